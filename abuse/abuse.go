@@ -0,0 +1,149 @@
+package abuse
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/utils"
+)
+
+const (
+	// discordEpoch is the first second of 2015 in milliseconds, the epoch of Discord snowflake IDs.
+	discordEpoch = 1_420_070_400_000
+
+	// geoIPTimeout is how long the GeoIP of an HTTP caller is waited for, the faucet has no timeout of its own.
+	geoIPTimeout = 3 * time.Second
+)
+
+// Claim is a faucet request to check before the payout.
+type Claim struct {
+	AppID    command.AppID
+	CallerID string // The platform user ID, or the IP address for HTTP.
+	Address  string
+}
+
+// Verdict is the result of the checks, a claim is suspicious if there is any reason.
+type Verdict struct {
+	Reasons []string
+}
+
+func (v Verdict) Suspicious() bool {
+	return len(v.Reasons) > 0
+}
+
+// Store is the faucet history that address reuse is detected from.
+type Store interface {
+	CountFaucetUsersByAddress(address, exceptUserID string) (int64, error)
+	CountFaucetAddressesByUser(userID string) (int64, error)
+}
+
+type Config struct {
+	MinAccountAge    time.Duration // Younger platform accounts are suspicious, zero disables the check.
+	MaxAddressUsers  int64         // Claims on an address already used by this many other users, zero disables the check.
+	MaxUserAddresses int64         // Claims of a user who already used this many addresses, zero disables the check.
+	BlockedASNs      []string      // Autonomous systems of HTTP callers to review, like: "AS14061".
+	ReviewHosting    bool          // Review HTTP claims from proxies and data centers.
+}
+
+type Detector struct {
+	cfg        Config
+	store      Store
+	geoIP      func(ctx context.Context, ip string) (*utils.GeoIP, error)
+	geoTimeout time.Duration
+	now        func() time.Time
+}
+
+func NewDetector(cfg Config, store Store) *Detector {
+	return &Detector{
+		cfg:        cfg,
+		store:      store,
+		geoIP:      utils.GetGeoIPContext,
+		geoTimeout: geoIPTimeout,
+		now:        time.Now,
+	}
+}
+
+// Check runs the heuristics on the claim.
+// Failing to run a check is not a reason, so the faucet keeps working when the store or GeoIP is down or slow.
+func (d *Detector) Check(ctx context.Context, claim Claim) Verdict {
+	verdict := Verdict{}
+
+	if createdAt, ok := AccountCreatedAt(claim.AppID, claim.CallerID); ok && d.cfg.MinAccountAge > 0 {
+		age := d.now().Sub(createdAt)
+		if age < d.cfg.MinAccountAge {
+			verdict.Reasons = append(verdict.Reasons,
+				fmt.Sprintf("account is created %s ago", age.Truncate(time.Hour)))
+		}
+	}
+
+	if d.cfg.MaxAddressUsers > 0 {
+		users, err := d.store.CountFaucetUsersByAddress(claim.Address, claim.CallerID)
+		if err != nil {
+			log.Error("can't count the users of the address", "err", err, "address", claim.Address)
+		} else if users >= d.cfg.MaxAddressUsers {
+			verdict.Reasons = append(verdict.Reasons,
+				fmt.Sprintf("address is claimed by %d other users", users))
+		}
+	}
+
+	if d.cfg.MaxUserAddresses > 0 {
+		addresses, err := d.store.CountFaucetAddressesByUser(claim.CallerID)
+		if err != nil {
+			log.Error("can't count the addresses of the user", "err", err, "user", claim.CallerID)
+		} else if addresses >= d.cfg.MaxUserAddresses {
+			verdict.Reasons = append(verdict.Reasons,
+				fmt.Sprintf("user claimed for %d addresses", addresses))
+		}
+	}
+
+	if claim.AppID == command.AppIdHTTP && net.ParseIP(claim.CallerID) != nil {
+		verdict.Reasons = append(verdict.Reasons, d.checkIP(ctx, claim.CallerID)...)
+	}
+
+	return verdict
+}
+
+func (d *Detector) checkIP(ctx context.Context, ip string) []string {
+	reasons := make([]string, 0)
+
+	ctx, cancel := context.WithTimeout(ctx, d.geoTimeout)
+	defer cancel()
+
+	geo, err := d.geoIP(ctx, ip)
+	if err != nil {
+		log.Ctx(ctx).Warn("can't look up the GeoIP of the caller", "err", err, "ip", ip)
+
+		return reasons
+	}
+
+	if d.cfg.ReviewHosting && (geo.Proxy || geo.Hosting) {
+		reasons = append(reasons, fmt.Sprintf("IP %s is a proxy or data center address (%s)", ip, geo.ISP))
+	}
+
+	if asn := geo.ASN(); asn != "" && slices.Contains(d.cfg.BlockedASNs, asn) {
+		reasons = append(reasons, fmt.Sprintf("IP %s is in blocked %s", ip, geo.AS))
+	}
+
+	return reasons
+}
+
+// AccountCreatedAt returns the creation time of the platform account, if the platform ID contains it.
+// Discord IDs are snowflakes with the creation timestamp, other platforms don't expose it.
+func AccountCreatedAt(appID command.AppID, callerID string) (time.Time, bool) {
+	if appID != command.AppIdDiscord {
+		return time.Time{}, false
+	}
+
+	id, err := strconv.ParseUint(callerID, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.UnixMilli(int64(id>>22) + discordEpoch), true
+}
@@ -0,0 +1,114 @@
+package abuse
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	addressUsers  int64
+	userAddresses int64
+	err           error
+}
+
+func (s *fakeStore) CountFaucetUsersByAddress(_, _ string) (int64, error) {
+	return s.addressUsers, s.err
+}
+
+func (s *fakeStore) CountFaucetAddressesByUser(_ string) (int64, error) {
+	return s.userAddresses, s.err
+}
+
+// discordID returns a snowflake ID created at the given time.
+func discordID(createdAt time.Time) string {
+	return strconv.FormatUint(uint64(createdAt.UnixMilli()-discordEpoch)<<22, 10)
+}
+
+func setup(store Store, geo *utils.GeoIP) *Detector {
+	d := NewDetector(Config{
+		MinAccountAge:    30 * 24 * time.Hour,
+		MaxAddressUsers:  1,
+		MaxUserAddresses: 3,
+		BlockedASNs:      []string{"AS14061"},
+		ReviewHosting:    true,
+	}, store)
+	d.geoIP = func(_ context.Context, _ string) (*utils.GeoIP, error) { return geo, nil }
+
+	return d
+}
+
+func TestAccountCreatedAt(t *testing.T) {
+	// The example of Discord documentation.
+	createdAt, ok := AccountCreatedAt(command.AppIdDiscord, "175928847299117063")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1462015105796), createdAt.UnixMilli())
+
+	_, ok = AccountCreatedAt(command.AppIdTelegram, "175928847299117063")
+	assert.False(t, ok)
+
+	_, ok = AccountCreatedAt(command.AppIdDiscord, "not-a-snowflake")
+	assert.False(t, ok)
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	oldAccount := discordID(time.Now().Add(-365 * 24 * time.Hour))
+
+	t.Run("clean claim", func(t *testing.T) {
+		d := setup(&fakeStore{}, &utils.GeoIP{})
+		verdict := d.Check(ctx, Claim{AppID: command.AppIdDiscord, CallerID: oldAccount, Address: "tpc1z..."})
+		assert.False(t, verdict.Suspicious())
+	})
+
+	t.Run("young account", func(t *testing.T) {
+		d := setup(&fakeStore{}, &utils.GeoIP{})
+		verdict := d.Check(ctx, Claim{
+			AppID:    command.AppIdDiscord,
+			CallerID: discordID(time.Now().Add(-48 * time.Hour)),
+			Address:  "tpc1z...",
+		})
+		assert.True(t, verdict.Suspicious())
+		assert.Contains(t, verdict.Reasons[0], "account is created 48h0m0s ago")
+	})
+
+	t.Run("address reuse", func(t *testing.T) {
+		d := setup(&fakeStore{addressUsers: 2, userAddresses: 3}, &utils.GeoIP{})
+		verdict := d.Check(ctx, Claim{AppID: command.AppIdDiscord, CallerID: oldAccount, Address: "tpc1z..."})
+		assert.Len(t, verdict.Reasons, 2)
+	})
+
+	t.Run("store failure is not a reason", func(t *testing.T) {
+		d := setup(&fakeStore{err: errors.New("database is locked")}, &utils.GeoIP{})
+		verdict := d.Check(ctx, Claim{AppID: command.AppIdTelegram, CallerID: "12345", Address: "tpc1z..."})
+		assert.False(t, verdict.Suspicious())
+	})
+
+	t.Run("HTTP claim from data center", func(t *testing.T) {
+		d := setup(&fakeStore{}, &utils.GeoIP{ISP: "DigitalOcean", AS: "AS14061 DigitalOcean, LLC", Hosting: true})
+		verdict := d.Check(ctx, Claim{AppID: command.AppIdHTTP, CallerID: "203.0.113.10", Address: "tpc1z..."})
+		assert.Len(t, verdict.Reasons, 2)
+
+		// Only HTTP callers are IP addresses.
+		verdict = d.Check(ctx, Claim{AppID: command.AppIdTelegram, CallerID: "12345", Address: "tpc1z..."})
+		assert.False(t, verdict.Suspicious())
+	})
+	t.Run("HTTP claim with a slow GeoIP", func(t *testing.T) {
+		d := setup(&fakeStore{}, nil)
+		d.geoTimeout = 10 * time.Millisecond
+		d.geoIP = func(ctx context.Context, _ string) (*utils.GeoIP, error) {
+			<-ctx.Done()
+
+			return &utils.GeoIP{}, ctx.Err()
+		}
+
+		verdict := d.Check(ctx, Claim{AppID: command.AppIdHTTP, CallerID: "203.0.113.10", Address: "tpc1z..."})
+		assert.False(t, verdict.Suspicious(), "a timeout is no signal")
+	})
+}
@@ -0,0 +1,210 @@
+package accounts
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const (
+	// checkInterval is the time between the checks of the accounts, a few blocks.
+	checkInterval = time.Minute
+
+	// baselineDays is the days before the last one that the daily average of the new accounts is computed over.
+	baselineDays = 7
+)
+
+// Store keeps the daily network snapshots of the indexer and the posted announcements.
+type Store interface {
+	GetNetworkSnapshot(at time.Time) (*database.NetworkSnapshot, error)
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Spike is the new accounts of the last day compared with the daily average of the days before it.
+type Spike struct {
+	Accounts    int32
+	NewAccounts int32
+	Average     float64 // The new accounts in a day, in the baseline days.
+	Days        int     // The days of the average, less than the baseline days for a young index.
+	Ratio       float64
+}
+
+// Monitor announces the milestones of the accounts, like every 10,000 accounts, and the spikes of the new accounts
+// in a day, they may be a dusting attack. The milestones and the spikes have their own channels.
+type Monitor struct {
+	lock              sync.Mutex
+	clientMgr         *client.Mgr
+	store             Store
+	hub               *notify.Hub
+	milestoneChannels []notify.Channel
+	spikeChannels     []notify.Channel
+	step              int32   // The milestones are the multiples of it, zero disables them.
+	spikeFactor       float64 // The new accounts over this times the average are a spike, zero disables them.
+	spikeMin          int32   // The fewer new accounts are not a spike, like in a quiet network.
+	last              int32   // The accounts in the last run, zero until the first run.
+	now               func() time.Time
+}
+
+// NewMonitor creates the monitor of the accounts, the milestones and the spikes are disabled without a channel.
+func NewMonitor(clientMgr *client.Mgr, store Store, hub *notify.Hub,
+	milestoneChannels, spikeChannels []notify.Channel, step int32, spikeFactor float64, spikeMin int32,
+) *Monitor {
+	return &Monitor{
+		clientMgr:         clientMgr,
+		store:             store,
+		hub:               hub,
+		milestoneChannels: milestoneChannels,
+		spikeChannels:     spikeChannels,
+		step:              step,
+		spikeFactor:       spikeFactor,
+		spikeMin:          spikeMin,
+		now:               time.Now,
+	}
+}
+
+func (m *Monitor) milestonesEnabled() bool {
+	return m.step > 0 && len(m.milestoneChannels) > 0
+}
+
+func (m *Monitor) spikesEnabled() bool {
+	return m.spikeFactor > 0 && len(m.spikeChannels) > 0
+}
+
+// Job returns the scheduler job of the checks. It's not exclusive, each instance keeps its own last count.
+// The announcements are claimed by the milestone and the day, so an announcement of the instances is posted once.
+func (m *Monitor) Job() scheduler.Job {
+	interval := checkInterval
+	if !m.milestonesEnabled() && !m.spikesEnabled() {
+		interval = 0
+	}
+
+	return scheduler.Job{
+		Name:      "accounts",
+		Interval:  interval,
+		Exclusive: false,
+		Run:       m.Run,
+	}
+}
+
+// Run checks the accounts for a passed milestone and a spike in the last day.
+// The first run starts from the current accounts, the milestones before the start are not announced.
+func (m *Monitor) Run(ctx context.Context) error {
+	info, err := m.clientMgr.GetBlockchainInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	accounts := info.TotalAccounts
+	m.lock.Lock()
+	last := m.last
+	m.last = max(m.last, accounts)
+	m.lock.Unlock()
+
+	if m.milestonesEnabled() && last > 0 {
+		// only the last one of the milestones passed at once is announced.
+		if milestone := accounts / m.step * m.step; milestone > last {
+			log.Info("the accounts passed a milestone", "milestone", milestone, "accounts", accounts)
+			m.announce(m.milestoneChannels, "milestone:"+strconv.Itoa(int(milestone)), "accounts_milestone",
+				map[string]any{
+					"Milestone": milestone,
+					"Accounts":  accounts,
+				})
+		}
+	}
+
+	if m.spikesEnabled() {
+		spike, ok, err := m.Spike(accounts)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			log.Warn("a spike of the new accounts", "new", spike.NewAccounts, "average", spike.Average)
+			m.announce(m.spikeChannels, "spike:"+m.now().UTC().Format(time.DateOnly), "accounts_spike", spike)
+		}
+	}
+
+	return nil
+}
+
+// Spike compares the new accounts since the snapshot of a day ago with the daily average of the snapshots before it.
+// It returns false if the new accounts are not a spike, or the snapshots are not enough to compare.
+func (m *Monitor) Spike(accounts int32) (Spike, bool, error) {
+	now := m.now().UTC()
+	dayAgo, err := m.store.GetNetworkSnapshot(now.AddDate(0, 0, -1))
+	if err != nil || dayAgo == nil {
+		return Spike{}, false, err
+	}
+
+	oldest, err := m.store.GetNetworkSnapshot(dayAgo.Day.AddDate(0, 0, -baselineDays))
+	if err != nil {
+		return Spike{}, false, err
+	}
+
+	if oldest == nil {
+		// a young index has no snapshot of the baseline days ago, the average is of the days it has.
+		oldest, err = m.store.GetNetworkSnapshot(dayAgo.Day.AddDate(0, 0, -1))
+		if err != nil || oldest == nil {
+			return Spike{}, false, err
+		}
+	}
+
+	days := int(dayAgo.Day.Sub(oldest.Day).Hours() / 24)
+	if days <= 0 {
+		return Spike{}, false, nil
+	}
+
+	spike := Spike{
+		Accounts:    accounts,
+		NewAccounts: accounts - dayAgo.Accounts,
+		Average:     float64(dayAgo.Accounts-oldest.Accounts) / float64(days),
+		Days:        days,
+	}
+	if spike.Average > 0 {
+		spike.Ratio = float64(spike.NewAccounts) / spike.Average
+	}
+
+	exceeded := spike.Average <= 0 || spike.Ratio > m.spikeFactor
+
+	return spike, spike.NewAccounts >= m.spikeMin && exceeded, nil
+}
+
+func (m *Monitor) announce(channels []notify.Channel, kind, name string, data any) {
+	for _, channel := range channels {
+		if !m.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		key := "accounts:" + kind + ":" + channel.String()
+		claimed, err := m.store.ClaimAnnouncement(key)
+		if err != nil {
+			log.Error("can't claim the accounts announcement", "err", err, "channel", channel)
+
+			continue
+		}
+
+		if !claimed {
+			// another instance posted it.
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, name, data)
+		if err != nil {
+			log.Error("can't render the accounts announcement", "err", err, "template", name)
+
+			continue
+		}
+
+		if err := m.hub.Announce(channel, msg); err != nil {
+			log.Warn("can't post the accounts announcement", "err", err, "channel", channel)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type memoryStore struct {
+	snapshots []*database.NetworkSnapshot // In ascending order of the days.
+	claimed   map[string]bool
+}
+
+func (s *memoryStore) GetNetworkSnapshot(at time.Time) (*database.NetworkSnapshot, error) {
+	var last *database.NetworkSnapshot
+	for _, snapshot := range s.snapshots {
+		if snapshot.Day.After(at) {
+			break
+		}
+		last = snapshot
+	}
+
+	return last, nil
+}
+
+func (s *memoryStore) ClaimAnnouncement(key string) (bool, error) {
+	if s.claimed[key] {
+		return false, nil
+	}
+	s.claimed[key] = true
+
+	return true, nil
+}
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func TestMonitor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	accounts := int32(19_990)
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).DoAndReturn(
+		func(_ context.Context) (*pactus.GetBlockchainInfoResponse, error) {
+			return &pactus.GetBlockchainInfoResponse{TotalAccounts: accounts}, nil
+		}).AnyTimes()
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	now := time.Date(2024, 5, 20, 12, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{claimed: make(map[string]bool)}
+	for d := 8; d >= 1; d-- {
+		// 100 new accounts in a day.
+		store.snapshots = append(store.snapshots, &database.NetworkSnapshot{
+			Day:      today.AddDate(0, 0, -d),
+			Accounts: 19_000 - int32(d)*100,
+		})
+	}
+
+	monitor := NewMonitor(cm, store, hub,
+		[]notify.Channel{{AppID: command.AppIdDiscord, ID: "milestones"}},
+		[]notify.Channel{{AppID: command.AppIdDiscord, ID: "alerts"}, {AppID: command.AppIdTelegram, ID: "-1001"}},
+		10_000, 3, 500)
+	monitor.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, monitor.Run(ctx), "the first run starts from the current accounts")
+	assert.Empty(t, discord["milestones"])
+	require.Len(t, discord["alerts"], 1)
+	assert.Equal(t, "New accounts spike⚠️: 1,090 new accounts in the last day, "+
+		"10.9 times the daily average of 100 in the last 7 days.\n"+
+		"A spike of the new accounts may be a dusting attack, please check the recent transfers.", discord["alerts"][0])
+
+	accounts = 20_010
+	require.NoError(t, monitor.Run(ctx))
+	require.NoError(t, monitor.Run(ctx), "the milestone is announced once")
+	assert.Equal(t, []string{"Milestone📈: the network passed 20,000 accounts, there are 20,010 accounts now."},
+		discord["milestones"])
+
+	t.Run("spike", func(t *testing.T) {
+		spike, ok, err := monitor.Spike(20_010)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int32(1_110), spike.NewAccounts)
+		assert.Equal(t, 7, spike.Days)
+		assert.InDelta(t, 100, spike.Average, 0.01)
+		assert.InDelta(t, 11.1, spike.Ratio, 0.01)
+		assert.Len(t, discord["alerts"], 1, "the spike is announced once a day")
+
+		_, ok, err = monitor.Spike(19_200)
+		require.NoError(t, err)
+		assert.False(t, ok, "under the minimum")
+
+		young := NewMonitor(cm, &memoryStore{snapshots: store.snapshots[6:]}, hub, nil, nil, 0, 3, 500)
+		young.now = monitor.now
+		spike, ok, err = young.Spike(20_010)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, 1, spike.Days, "the index has two snapshots")
+
+		fresh := NewMonitor(cm, &memoryStore{snapshots: store.snapshots[7:]}, hub, nil, nil, 0, 3, 500)
+		fresh.now = monitor.now
+		_, ok, err = fresh.Spike(20_010)
+		require.NoError(t, err)
+		assert.False(t, ok, "no snapshot to compare")
+	})
+
+	assert.Zero(t, NewMonitor(cm, store, hub, nil, nil, 10_000, 3, 500).Job().Interval, "no channel")
+	assert.Zero(t, NewMonitor(cm, store, hub, monitor.milestoneChannels, monitor.spikeChannels, 0, 0, 500).
+		Job().Interval, "disabled")
+}
@@ -0,0 +1,192 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/lock"
+	"github.com/pagu-project/Pagu/log"
+)
+
+const (
+	filePrefix = "pagu-"
+	fileSuffix = ".db"
+	timeLayout = "20060102-150405"
+	lockName   = "backup"
+)
+
+// Snapshotter writes a consistent copy of the database to a new file.
+type Snapshotter interface {
+	Snapshot(path string) error
+}
+
+type Config struct {
+	Path     string        // Directory of the backup files, empty disables the backups.
+	Interval time.Duration // Time between the scheduled backups, zero disables the schedule.
+	Keep     int           // Number of the latest backup files kept, zero keeps all.
+}
+
+// Backup takes the database snapshots on schedule or on demand and removes the old ones.
+type Backup struct {
+	lock   sync.Mutex
+	cfg    Config
+	db     Snapshotter
+	locker *lock.Locker
+	now    func() time.Time
+	last   time.Time
+}
+
+func NewBackup(cfg Config, db Snapshotter) *Backup {
+	return &Backup{
+		cfg: cfg,
+		db:  db,
+		now: time.Now,
+	}
+}
+
+// SetLocker sets the locker of the instances that share the database, only one of them takes the scheduled backups.
+func (b *Backup) SetLocker(locker *lock.Locker) {
+	b.locker = locker
+}
+
+func (b *Backup) Enabled() bool {
+	return b.cfg.Path != ""
+}
+
+// Last returns the time of the last successful backup, zero if there is none since the start.
+func (b *Backup) Last() time.Time {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.last
+}
+
+// Now takes a backup and returns its file path.
+func (b *Backup) Now() (string, error) {
+	if !b.Enabled() {
+		return "", DisabledError{}
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := os.MkdirAll(b.cfg.Path, 0o750); err != nil {
+		return "", BackupError{
+			Reason: err.Error(),
+		}
+	}
+
+	now := b.now().UTC()
+	path := filepath.Join(b.cfg.Path, filePrefix+now.Format(timeLayout)+fileSuffix)
+	if err := b.db.Snapshot(path); err != nil {
+		return "", BackupError{
+			Reason: err.Error(),
+		}
+	}
+	b.last = now
+
+	if err := b.prune(); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Start takes the scheduled backups until the context is done.
+func (b *Backup) Start(ctx context.Context) {
+	if !b.Enabled() || b.cfg.Interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(b.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				if !b.isLeader() {
+					continue
+				}
+
+				path, err := b.Now()
+				if err != nil {
+					log.Error("scheduled backup failed", "error", err)
+
+					continue
+				}
+				log.Info("scheduled backup done", "path", path)
+			}
+		}
+	}()
+}
+
+// isLeader checks if this instance should take the scheduled backup.
+// The leader renews the lock on each backup, the lock lasts longer than the interval,
+// so another instance takes over only if the leader stops.
+func (b *Backup) isLeader() bool {
+	if b.locker == nil {
+		return true
+	}
+
+	ok, err := b.locker.TryLock(lockName, b.cfg.Interval+b.cfg.Interval/2)
+	if err != nil {
+		log.Error("unable to lock the scheduled backup", "error", err)
+
+		return false
+	}
+
+	return ok
+}
+
+// Files returns the backup file names, the oldest first.
+func (b *Backup) Files() ([]string, error) {
+	entries, err := os.ReadDir(b.cfg.Path)
+	if err != nil {
+		return nil, BackupError{
+			Reason: err.Error(),
+		}
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			files = append(files, name)
+		}
+	}
+	// the names have the time of the backup, so they are sorted by time.
+	slices.Sort(files)
+
+	return files, nil
+}
+
+func (b *Backup) prune() error {
+	if b.cfg.Keep <= 0 {
+		return nil
+	}
+
+	files, err := b.Files()
+	if err != nil {
+		return err
+	}
+
+	for len(files) > b.cfg.Keep {
+		if err := os.Remove(filepath.Join(b.cfg.Path, files[0])); err != nil {
+			return BackupError{
+				Reason: err.Error(),
+			}
+		}
+		files = files[1:]
+	}
+
+	return nil
+}
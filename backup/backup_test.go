@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/lock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fileSnapshotter struct{}
+
+func (fileSnapshotter) Snapshot(path string) error {
+	return os.WriteFile(path, []byte("snapshot"), 0o600)
+}
+
+func TestBackup(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	b := NewBackup(Config{Path: dir, Keep: 2}, fileSnapshotter{})
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		path, err := b.Now()
+		require.NoError(t, err)
+		assert.FileExists(t, path)
+		now = now.Add(time.Hour)
+	}
+
+	files, err := b.Files()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pagu-20240501-110000.db", "pagu-20240501-120000.db"}, files)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), b.Last())
+}
+
+func TestBackupDisabled(t *testing.T) {
+	b := NewBackup(Config{}, fileSnapshotter{})
+	assert.False(t, b.Enabled())
+
+	_, err := b.Now()
+	assert.ErrorIs(t, err, DisabledError{})
+
+	// no-op, there is nothing to schedule.
+	b.Start(context.Background())
+}
+
+type memoryLockStore struct {
+	owner string
+}
+
+func (s *memoryLockStore) AcquireLock(_, owner string, _ time.Duration) (bool, error) {
+	if s.owner != "" {
+		return false, nil
+	}
+	s.owner = owner
+
+	return true, nil
+}
+
+func (s *memoryLockStore) RenewLock(_, owner string, _ time.Duration) (bool, error) {
+	return s.owner == owner, nil
+}
+
+func (s *memoryLockStore) ReleaseLock(_, owner string) error {
+	if s.owner == owner {
+		s.owner = ""
+	}
+
+	return nil
+}
+
+func TestBackupLeader(t *testing.T) {
+	store := &memoryLockStore{}
+	cfg := Config{Path: t.TempDir(), Interval: time.Hour}
+
+	leader := NewBackup(cfg, fileSnapshotter{})
+	leader.SetLocker(lock.NewLocker(store, "pagu-1"))
+	follower := NewBackup(cfg, fileSnapshotter{})
+	follower.SetLocker(lock.NewLocker(store, "pagu-2"))
+
+	assert.True(t, leader.isLeader())
+	assert.False(t, follower.isLeader())
+	assert.True(t, leader.isLeader(), "the leader renews the lock")
+}
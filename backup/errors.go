@@ -0,0 +1,17 @@
+package backup
+
+import "fmt"
+
+type DisabledError struct{}
+
+func (e DisabledError) Error() string {
+	return "backup is disabled, set BACKUP_PATH to enable it"
+}
+
+type BackupError struct {
+	Reason string
+}
+
+func (e BackupError) Error() string {
+	return fmt.Sprintf("backup error: %s", e.Reason)
+}
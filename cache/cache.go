@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+// Store keeps the cached values and the rate limit counters.
+// The instances share them if the store is remote, like Redis.
+type Store interface {
+	// Get returns the value of the key, false if it's not set or it's expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Incr increments the counter of the key and returns the new value,
+	// the counter expires after the TTL of the first increment.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	Close() error
+}
+
+type Config struct {
+	Driver        string // memory or redis, memory by default.
+	RedisAddress  string
+	RedisPassword string
+	RedisDB       int
+}
+
+// NewStore returns the store of the configured driver.
+func NewStore(cfg Config) (Store, error) {
+	switch cfg.Driver {
+	case "", DriverMemory:
+		return NewMemoryStore(), nil
+
+	case DriverRedis:
+		return NewRedisStore(cfg.RedisAddress, cfg.RedisPassword, cfg.RedisDB)
+
+	default:
+		return nil, UnknownDriverError{
+			Driver: cfg.Driver,
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pagu-project/Pagu/lock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupStores returns the stores of all the drivers and a function to move their time forward.
+func setupStores(t *testing.T) (map[string]Store, func(time.Duration)) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	redisStore, err := NewStore(Config{Driver: DriverRedis, RedisAddress: server.Addr()})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = redisStore.Close() })
+
+	now := time.Now()
+	memoryStore := NewMemoryStore()
+	memoryStore.now = func() time.Time { return now }
+
+	forward := func(d time.Duration) {
+		now = now.Add(d)
+		server.FastForward(d)
+	}
+
+	return map[string]Store{
+		DriverMemory: memoryStore,
+		DriverRedis:  redisStore,
+	}, forward
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	stores, forward := setupStores(t)
+
+	for name, store := range stores {
+		_, ok, err := store.Get(ctx, "info")
+		require.NoError(t, err, name)
+		assert.False(t, ok, name)
+
+		require.NoError(t, store.Set(ctx, "info", []byte("value"), time.Second), name)
+		value, ok, err := store.Get(ctx, "info")
+		require.NoError(t, err, name)
+		assert.True(t, ok, name)
+		assert.Equal(t, []byte("value"), value, name)
+
+		for i := int64(1); i <= 3; i++ {
+			counter, err := store.Incr(ctx, "counter", time.Second)
+			require.NoError(t, err, name)
+			assert.Equal(t, i, counter, name)
+		}
+	}
+
+	forward(2 * time.Second)
+
+	for name, store := range stores {
+		_, ok, err := store.Get(ctx, "info")
+		require.NoError(t, err, name)
+		assert.False(t, ok, "%s: the value is expired", name)
+
+		counter, err := store.Incr(ctx, "counter", time.Second)
+		require.NoError(t, err, name)
+		assert.Equal(t, int64(1), counter, "%s: the counter is expired", name)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	store, err := NewStore(Config{})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+
+	_, err = NewStore(Config{Driver: "memcached"})
+	assert.ErrorIs(t, err, UnknownDriverError{Driver: "memcached"})
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	stores, _ := setupStores(t)
+
+	for name, store := range stores {
+		now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		limiter := NewLimiter(store, 2, time.Minute)
+		limiter.now = func() time.Time { return now }
+
+		for i := 0; i < 2; i++ {
+			ok, err := limiter.Allow(ctx, "discord:123")
+			require.NoError(t, err, name)
+			assert.True(t, ok, name)
+		}
+
+		ok, err := limiter.Allow(ctx, "discord:123")
+		require.NoError(t, err, name)
+		assert.False(t, ok, "%s: over the limit", name)
+		assert.Equal(t, time.Minute, limiter.RetryAfter(), name)
+
+		now = now.Add(15 * time.Second)
+		assert.Equal(t, 45*time.Second, limiter.RetryAfter(), name)
+
+		ok, err = limiter.Allow(ctx, "discord:456")
+		require.NoError(t, err, name)
+		assert.True(t, ok, "%s: another caller", name)
+
+		now = now.Add(45 * time.Second)
+		ok, err = limiter.Allow(ctx, "discord:123")
+		require.NoError(t, err, name)
+		assert.True(t, ok, "%s: next window", name)
+	}
+
+	limiter := NewLimiter(stores[DriverMemory], 0, time.Minute)
+	ok, err := limiter.Allow(ctx, "discord:123")
+	require.NoError(t, err)
+	assert.True(t, ok, "no limit")
+}
+
+func TestRedisLock(t *testing.T) {
+	server := miniredis.RunT(t)
+	store, err := NewRedisStore(server.Addr(), "", 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	first := lock.NewLocker(store, "pagu-1")
+	second := lock.NewLocker(store, "pagu-2")
+
+	ok, err := first.TryLock("job:indexer", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = second.TryLock("job:indexer", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "the lock is held by the first instance")
+
+	server.FastForward(30 * time.Second)
+	ok, err = first.TryLock("job:indexer", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "the holder renews the lock")
+
+	server.FastForward(45 * time.Second)
+	ok, err = second.TryLock("job:indexer", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "the renewed lock is not expired")
+
+	require.NoError(t, second.Unlock("job:indexer"))
+	ok, err = second.TryLock("job:indexer", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "only the holder releases the lock")
+
+	require.NoError(t, first.Unlock("job:indexer"))
+	ok, err = second.TryLock("job:indexer", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	server.FastForward(2 * time.Minute)
+	ok, err = first.TryLock("job:indexer", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "the expired lock is taken over")
+}
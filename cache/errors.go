@@ -0,0 +1,19 @@
+package cache
+
+import "fmt"
+
+type UnknownDriverError struct {
+	Driver string
+}
+
+func (e UnknownDriverError) Error() string {
+	return fmt.Sprintf("unknown cache driver: %s, it should be memory or redis", e.Driver)
+}
+
+type StoreError struct {
+	Reason string
+}
+
+func (e StoreError) Error() string {
+	return fmt.Sprintf("cache store error: %s", e.Reason)
+}
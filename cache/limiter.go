@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limiter allows a number of calls per key in fixed time windows, like 30 commands per minute.
+type Limiter struct {
+	store  Store
+	limit  int64
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewLimiter returns a limiter on the store, a limit of zero allows all the calls.
+func NewLimiter(store Store, limit int64, window time.Duration) *Limiter {
+	return &Limiter{
+		store:  store,
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// Allow counts the call of the key and checks if it is in the limit of the current window.
+func (l *Limiter) Allow(ctx context.Context, key string) (bool, error) {
+	return l.AllowLimit(ctx, key, l.limit)
+}
+
+// AllowLimit is Allow with the limit of the key, like a server with a higher quota. A limit of zero allows the call.
+func (l *Limiter) AllowLimit(ctx context.Context, key string, limit int64) (bool, error) {
+	if limit <= 0 || l.window <= 0 {
+		return true, nil
+	}
+
+	// the window is in the key, so a counter without expiry is not used in the next window.
+	windowKey := fmt.Sprintf("ratelimit:%s:%d", key, l.now().UnixNano()/int64(l.window))
+	counter, err := l.store.Incr(ctx, windowKey, l.window)
+	if err != nil {
+		return false, err
+	}
+
+	return counter <= limit, nil
+}
+
+// RetryAfter returns the time to the next window, when the calls over the limit are allowed again.
+func (l *Limiter) RetryAfter() time.Duration {
+	if l.window <= 0 {
+		return 0
+	}
+
+	return l.window - time.Duration(l.now().UnixNano()%int64(l.window))
+}
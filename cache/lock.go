@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// lockTimeout is how long a call of the locks waits for Redis, the locks have no context of the caller.
+const lockTimeout = 5 * time.Second
+
+// renewScript extends the lock if the owner holds it, in one atomic step.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript removes the lock if the owner holds it, in one atomic step.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLock takes the lock for the owner until the TTL is passed, so the instances on other hosts share it.
+// It returns false if the lock is held, even by the same owner, so the lock is not reentrant.
+func (s *RedisStore) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	ok, err := s.client.SetNX(ctx, lockKey(name), owner, ttl).Result()
+	if err != nil {
+		return false, StoreError{
+			Reason: err.Error(),
+		}
+	}
+
+	return ok, nil
+}
+
+// RenewLock extends the lock until the TTL is passed, it returns false if the owner doesn't hold the lock.
+func (s *RedisStore) RenewLock(name, owner string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	renewed, err := renewScript.Run(ctx, s.client, []string{lockKey(name)}, owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, StoreError{
+			Reason: err.Error(),
+		}
+	}
+
+	return renewed == 1, nil
+}
+
+// ReleaseLock removes the lock if the owner holds it.
+func (s *RedisStore) ReleaseLock(name, owner string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	if err := releaseScript.Run(ctx, s.client, []string{lockKey(name)}, owner).Err(); err != nil {
+		return StoreError{
+			Reason: err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func lockKey(name string) string {
+	return keyPrefix + "lock:" + name
+}
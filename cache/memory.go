@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the minimum time between removing the expired entries.
+const sweepInterval = time.Minute
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore is the store of a single instance, the values are lost on restart.
+type MemoryStore struct {
+	lock      sync.Mutex
+	now       func() time.Time
+	entries   map[string]entry
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !s.now().Before(e.expiresAt) {
+		return nil, false, nil
+	}
+
+	return e.value, true, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sweep()
+	s.entries[key] = entry{
+		value:     value,
+		expiresAt: s.now().Add(ttl),
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sweep()
+	now := s.now()
+	e, ok := s.entries[key]
+	if !ok || !now.Before(e.expiresAt) {
+		e = entry{
+			value:     []byte("0"),
+			expiresAt: now.Add(ttl),
+		}
+	}
+
+	counter, err := strconv.ParseInt(string(e.value), 10, 64)
+	if err != nil {
+		return 0, StoreError{
+			Reason: err.Error(),
+		}
+	}
+
+	counter++
+	e.value = []byte(strconv.FormatInt(counter, 10))
+	s.entries[key] = e
+
+	return counter, nil
+}
+
+func (*MemoryStore) Close() error {
+	return nil
+}
+
+// sweep removes the expired entries, the caller should hold the lock.
+func (s *MemoryStore) sweep() {
+	now := s.now()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix separates the keys of Pagu from the other applications on the same Redis.
+const keyPrefix = "pagu:"
+
+// incrScript increments the counter and sets its expiry on the first increment, in one atomic step.
+var incrScript = redis.NewScript(`
+local counter = redis.call("INCR", KEYS[1])
+if counter == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return counter
+`)
+
+// RedisStore is the store shared by the instances, so they share the quotas and the cached RPC results.
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(address, password string, db int) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     address,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+
+		return nil, StoreError{
+			Reason: err.Error(),
+		}
+	}
+
+	return &RedisStore{
+		client: client,
+	}, nil
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, StoreError{
+			Reason: err.Error(),
+		}
+	}
+
+	return value, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.client.Set(ctx, keyPrefix+key, value, ttl).Err(); err != nil {
+		return StoreError{
+			Reason: err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	counter, err := incrScript.Run(ctx, s.client, []string{keyPrefix + key}, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, StoreError{
+			Reason: err.Error(),
+		}
+	}
+
+	return counter, nil
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
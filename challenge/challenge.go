@@ -0,0 +1,134 @@
+package challenge
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+// Challenge is a verification step that the user should pass before running a high-value command.
+type Challenge struct {
+	Kind     string // The provider kind, it selects the template to render the challenge.
+	Question string // The question of chat challenges, like: "7 + 5".
+	SiteKey  string // The site key of web challenges like hCaptcha.
+	TTL      time.Duration
+
+	answer string
+}
+
+// Provider creates and verifies one kind of challenges.
+type Provider interface {
+	Kind() string
+	New() Challenge
+	// Verify checks the response of the user, the remote is the caller ID like the IP address of HTTP callers.
+	Verify(ch Challenge, response, remote string) (bool, error)
+}
+
+type key struct {
+	appID  command.AppID
+	userID string
+}
+
+type pending struct {
+	challenge Challenge
+	tokens    []string // The command to run after the verification.
+	expiresAt time.Time
+}
+
+// Manager keeps the pending challenges and the short-lived verifications of the users.
+type Manager struct {
+	lock      sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time
+	fallback  Provider
+	providers map[command.AppID]Provider
+	pending   map[key]pending
+	verified  map[key]time.Time
+}
+
+func NewManager(ttl time.Duration, fallback Provider) *Manager {
+	return &Manager{
+		ttl:       ttl,
+		now:       time.Now,
+		fallback:  fallback,
+		providers: make(map[command.AppID]Provider),
+		pending:   make(map[key]pending),
+		verified:  make(map[key]time.Time),
+	}
+}
+
+// SetProvider sets the provider of the platform, the fallback provider is used for the others.
+func (m *Manager) SetProvider(appID command.AppID, p Provider) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.providers[appID] = p
+}
+
+func (m *Manager) provider(appID command.AppID) Provider {
+	if p, ok := m.providers[appID]; ok {
+		return p
+	}
+
+	return m.fallback
+}
+
+// Issue creates a new challenge for the user and keeps the command tokens to run after the verification.
+// The previous challenge of the user is replaced.
+func (m *Manager) Issue(appID command.AppID, userID string, tokens []string) Challenge {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ch := m.provider(appID).New()
+	ch.TTL = m.ttl
+	m.pending[key{appID, userID}] = pending{
+		challenge: ch,
+		tokens:    tokens,
+		expiresAt: m.now().Add(m.ttl),
+	}
+
+	return ch
+}
+
+// Verify checks the response of the pending challenge and returns the command tokens to run.
+// The challenge is removed after one try, so it can't be brute-forced.
+func (m *Manager) Verify(appID command.AppID, userID, response string) ([]string, error) {
+	m.lock.Lock()
+	k := key{appID, userID}
+	p, ok := m.pending[k]
+	delete(m.pending, k)
+	m.lock.Unlock()
+
+	if !ok || m.now().After(p.expiresAt) {
+		return nil, NoChallengeError{}
+	}
+
+	passed, err := m.provider(appID).Verify(p.challenge, response, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !passed {
+		return nil, WrongResponseError{}
+	}
+
+	m.lock.Lock()
+	m.verified[k] = m.now().Add(m.ttl)
+	m.lock.Unlock()
+
+	return p.tokens, nil
+}
+
+// Consume uses the verification of the user, if it's not expired.
+// Each verification allows one high-value command.
+func (m *Manager) Consume(appID command.AppID, userID string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	k := key{appID, userID}
+	expiresAt, ok := m.verified[k]
+	delete(m.verified, k)
+
+	return ok && !m.now().After(expiresAt)
+}
@@ -0,0 +1,86 @@
+package challenge
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	now := time.Now()
+	mgr := NewManager(5*time.Minute, Math{})
+	mgr.now = func() time.Time { return now }
+
+	t.Run("verify and consume", func(t *testing.T) {
+		ch := mgr.Issue(command.AppIdDiscord, "user-1", []string{"phoenix", "faucet", "tpc1z..."})
+		assert.Equal(t, KindMath, ch.Kind)
+		assert.False(t, mgr.Consume(command.AppIdDiscord, "user-1"))
+
+		tokens, err := mgr.Verify(command.AppIdDiscord, "user-1", ch.answer)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"phoenix", "faucet", "tpc1z..."}, tokens)
+
+		assert.False(t, mgr.Consume(command.AppIdTelegram, "user-1"), "verification is per platform")
+		assert.True(t, mgr.Consume(command.AppIdDiscord, "user-1"))
+		assert.False(t, mgr.Consume(command.AppIdDiscord, "user-1"), "verification is used once")
+	})
+
+	t.Run("one try per challenge", func(t *testing.T) {
+		ch := mgr.Issue(command.AppIdDiscord, "user-2", nil)
+
+		_, err := mgr.Verify(command.AppIdDiscord, "user-2", "wrong")
+		assert.ErrorIs(t, err, WrongResponseError{})
+
+		_, err = mgr.Verify(command.AppIdDiscord, "user-2", ch.answer)
+		assert.ErrorIs(t, err, NoChallengeError{})
+	})
+
+	t.Run("expired challenge and verification", func(t *testing.T) {
+		ch := mgr.Issue(command.AppIdDiscord, "user-3", nil)
+		now = now.Add(10 * time.Minute)
+
+		_, err := mgr.Verify(command.AppIdDiscord, "user-3", ch.answer)
+		assert.ErrorIs(t, err, NoChallengeError{})
+
+		ch = mgr.Issue(command.AppIdDiscord, "user-3", nil)
+		_, err = mgr.Verify(command.AppIdDiscord, "user-3", ch.answer)
+		require.NoError(t, err)
+
+		now = now.Add(10 * time.Minute)
+		assert.False(t, mgr.Consume(command.AppIdDiscord, "user-3"))
+	})
+}
+
+func TestHCaptcha(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "203.0.113.10", r.PostForm.Get("remoteip"))
+
+		fmt.Fprintf(w, `{"success": %v}`, r.PostForm.Get("response") == "valid-token")
+	}))
+	defer server.Close()
+
+	h := NewHCaptcha("site-key", "secret")
+	h.verifyURL = server.URL
+
+	mgr := NewManager(time.Minute, Math{})
+	mgr.SetProvider(command.AppIdHTTP, h)
+
+	ch := mgr.Issue(command.AppIdHTTP, "203.0.113.10", nil)
+	assert.Equal(t, KindHCaptcha, ch.Kind)
+	assert.Equal(t, "site-key", ch.SiteKey)
+
+	_, err := mgr.Verify(command.AppIdHTTP, "203.0.113.10", "valid-token")
+	assert.NoError(t, err)
+
+	mgr.Issue(command.AppIdHTTP, "203.0.113.10", nil)
+	_, err = mgr.Verify(command.AppIdHTTP, "203.0.113.10", "invalid-token")
+	assert.ErrorIs(t, err, WrongResponseError{})
+}
@@ -0,0 +1,24 @@
+package challenge
+
+import "fmt"
+
+type NoChallengeError struct{}
+
+func (e NoChallengeError) Error() string {
+	return "there is no challenge to verify or it's expired, please run the command again"
+}
+
+type WrongResponseError struct{}
+
+func (e WrongResponseError) Error() string {
+	return "the response is not correct, please run the command again to get a new challenge"
+}
+
+type ProviderError struct {
+	Kind   string
+	Reason string
+}
+
+func (e ProviderError) Error() string {
+	return fmt.Sprintf("can't verify %s challenge: %s", e.Kind, e.Reason)
+}
@@ -0,0 +1,65 @@
+package challenge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	KindHCaptcha = "hcaptcha"
+
+	hCaptchaVerifyURL = "https://api.hcaptcha.com/siteverify"
+)
+
+// HCaptcha is a web challenge, the client solves the captcha widget with the site key
+// and sends the response token to verify.
+type HCaptcha struct {
+	siteKey   string
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+func NewHCaptcha(siteKey, secret string) *HCaptcha {
+	return &HCaptcha{
+		siteKey:   siteKey,
+		secret:    secret,
+		verifyURL: hCaptchaVerifyURL,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (*HCaptcha) Kind() string {
+	return KindHCaptcha
+}
+
+func (h *HCaptcha) New() Challenge {
+	return Challenge{
+		Kind:    KindHCaptcha,
+		SiteKey: h.siteKey,
+	}
+}
+
+func (h *HCaptcha) Verify(_ Challenge, response, remote string) (bool, error) {
+	res, err := h.client.PostForm(h.verifyURL, url.Values{
+		"secret":   {h.secret},
+		"response": {response},
+		"remoteip": {remote},
+		"sitekey":  {h.siteKey},
+	})
+	if err != nil {
+		return false, ProviderError{Kind: KindHCaptcha, Reason: err.Error()}
+	}
+	defer res.Body.Close()
+
+	result := struct {
+		Success bool `json:"success"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return false, ProviderError{Kind: KindHCaptcha, Reason: err.Error()}
+	}
+
+	return result.Success, nil
+}
@@ -0,0 +1,32 @@
+package challenge
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+const KindMath = "math"
+
+// Math is a chat challenge that asks the sum of two small numbers.
+type Math struct{}
+
+func (Math) Kind() string {
+	return KindMath
+}
+
+func (Math) New() Challenge {
+	a := rand.IntN(20) + 1
+	b := rand.IntN(20) + 1
+
+	return Challenge{
+		Kind:     KindMath,
+		Question: fmt.Sprintf("%d + %d", a, b),
+		answer:   strconv.Itoa(a + b),
+	}
+}
+
+func (Math) Verify(ch Challenge, response, _ string) (bool, error) {
+	return strings.TrimSpace(response) == ch.answer, nil
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+const (
+	Prompt      = ">> "
+	ExitCommand = "exit"
+)
+
+// Runner runs the commands, like the bot engine.
+type Runner interface {
+	Run(appID command.AppID, callerID string, tokens []string) command.CommandResult
+}
+
+// REPL runs the commands of a terminal on the engine, as the CLI platform.
+// It needs no platform credentials, so the commands can be developed and debugged locally.
+type REPL struct {
+	runner        Runner
+	callerID      string
+	attachmentDir string
+	out           io.Writer
+}
+
+// NewREPL creates the REPL, the attachments of the results are saved in the directory.
+func NewREPL(runner Runner, callerID, attachmentDir string, out io.Writer) *REPL {
+	return &REPL{
+		runner:        runner,
+		callerID:      callerID,
+		attachmentDir: attachmentDir,
+		out:           out,
+	}
+}
+
+// Start reads the commands line by line and prints the results, until "exit" or the end of the input.
+func (r *REPL) Start(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(r.out, "\n"+Prompt)
+
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+
+			return scanner.Err()
+		}
+
+		tokens := strings.Fields(scanner.Text())
+		if len(tokens) == 0 {
+			continue
+		}
+
+		if strings.EqualFold(tokens[0], ExitCommand) {
+			fmt.Fprintln(r.out, "exiting from repl")
+
+			return nil
+		}
+
+		r.Exec(tokens)
+	}
+}
+
+// Exec runs a command and prints its result, it returns false if the command is not successful.
+func (r *REPL) Exec(tokens []string) bool {
+	res := r.runner.Run(command.AppIdCLI, r.callerID, tokens)
+
+	if res.Title != "" {
+		fmt.Fprintln(r.out, res.Title)
+	}
+	fmt.Fprintln(r.out, res.Message)
+
+	for _, att := range res.Attachments {
+		path := filepath.Join(r.attachmentDir, filepath.Base(att.Name))
+		if err := os.WriteFile(path, att.Data, 0o600); err != nil {
+			fmt.Fprintf(r.out, "can't save the attachment %s: %v\n", att.Name, err)
+
+			continue
+		}
+
+		fmt.Fprintf(r.out, "attachment saved: %s\n", path)
+	}
+
+	return res.Successful
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRunner struct {
+	calls [][]string
+}
+
+func (r *fakeRunner) Run(appID command.AppID, callerID string, tokens []string) command.CommandResult {
+	r.calls = append(r.calls, tokens)
+	if appID != command.AppIdCLI || callerID != "42" {
+		return command.CommandResult{Message: "wrong caller"}
+	}
+
+	if tokens[0] == "qr" {
+		return command.CommandResult{
+			Message:     "the QR code",
+			Successful:  true,
+			Attachments: []command.Attachment{{Name: "../qr.png", Data: []byte("png")}},
+		}
+	}
+
+	return command.CommandResult{Title: "Network", Message: "healthy", Successful: tokens[0] == "network"}
+}
+
+func TestREPL(t *testing.T) {
+	dir := t.TempDir()
+	runner := &fakeRunner{}
+	out := new(bytes.Buffer)
+	repl := NewREPL(runner, "42", dir, out)
+
+	in := strings.NewReader("network   health\n\n  \nqr\nEXIT\nnetwork status\n")
+	require.NoError(t, repl.Start(in))
+
+	assert.Equal(t, [][]string{{"network", "health"}, {"qr"}}, runner.calls, "the commands after exit are not run")
+	assert.Contains(t, out.String(), Prompt+"Network\nhealthy\n")
+	assert.Contains(t, out.String(), "attachment saved: "+filepath.Join(dir, "qr.png"))
+	assert.Contains(t, out.String(), "exiting from repl")
+
+	data, err := os.ReadFile(filepath.Join(dir, "qr.png"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("png"), data, "the attachment is saved in the directory")
+
+	require.NoError(t, repl.Start(strings.NewReader("network health")), "the end of the input exits")
+	assert.False(t, repl.Exec([]string{"tx"}))
+}
+
+// fixtureRunner returns the golden fixture of the command name.
+type fixtureRunner map[string]command.CommandResult
+
+func (r fixtureRunner) Run(_ command.AppID, _ string, tokens []string) command.CommandResult {
+	return r[tokens[0]]
+}
+
+func TestGoldenResults(t *testing.T) {
+	runner := fixtureRunner{}
+	for _, f := range golden.Fixtures() {
+		runner[f.Name] = f.Result
+	}
+
+	for _, f := range golden.Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			out := new(bytes.Buffer)
+			repl := NewREPL(runner, "42", dir, out)
+
+			assert.Equal(t, f.Result.Successful, repl.Exec([]string{f.Name}))
+			golden.Assert(t, f.Name, strings.ReplaceAll(out.String(), dir, "<dir>"))
+		})
+	}
+}
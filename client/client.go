@@ -13,24 +13,30 @@ type Client struct {
 	blockchainClient  pactus.BlockchainClient
 	networkClient     pactus.NetworkClient
 	transactionClient pactus.TransactionClient
-	conn              *grpc.ClientConn
+	conn              *Conn
 }
 
-func NewClient(endpoint string) (*Client, error) {
-	conn, err := grpc.Dial(endpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewClient connects to the endpoint with its own connection, the clients of a Pool share the connections.
+func NewClient(endpoint string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dialConn(endpoint, 1, opts...)
 	if err != nil {
 		return nil, err
 	}
+	conn.refs.Add(1)
 
 	log.Info("establishing new connection", "addr", endpoint)
 
+	return newClientOf(conn), nil
+}
+
+func newClientOf(conn *Conn) *Client {
 	return &Client{
 		blockchainClient:  pactus.NewBlockchainClient(conn),
 		networkClient:     pactus.NewNetworkClient(conn),
 		transactionClient: pactus.NewTransactionClient(conn),
 		conn:              conn,
-	}, nil
+	}
 }
 
 func (c *Client) GetBlockchainInfo(ctx context.Context) (*pactus.GetBlockchainInfoResponse, error) {
@@ -49,6 +55,28 @@ func (c *Client) GetBlockchainHeight(ctx context.Context) (uint32, error) {
 	return blockchainInfo.LastBlockHeight, nil
 }
 
+func (c *Client) GetBlockHash(ctx context.Context, height uint32) ([]byte, error) {
+	res, err := c.blockchainClient.GetBlockHash(ctx, &pactus.GetBlockHashRequest{Height: height})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Hash, nil
+}
+
+// GetBlockTransactions returns the block at the height with its transactions.
+func (c *Client) GetBlockTransactions(ctx context.Context, height uint32) (*pactus.GetBlockResponse, error) {
+	return c.blockchainClient.GetBlock(ctx, &pactus.GetBlockRequest{
+		Height:    height,
+		Verbosity: pactus.BlockVerbosity_BLOCK_TRANSACTIONS,
+	})
+}
+
+// Target returns the endpoint of the node.
+func (c *Client) Target() string {
+	return c.conn.Target()
+}
+
 func (c *Client) GetNetworkInfo(ctx context.Context) (*pactus.GetNetworkInfoResponse, error) {
 	networkInfo, err := c.networkClient.GetNetworkInfo(ctx, &pactus.GetNetworkInfoRequest{})
 	if err != nil {
@@ -151,6 +179,18 @@ func (c *Client) GetBalance(ctx context.Context, address string) (int64, error)
 	return account.Account.Balance, nil
 }
 
+// GetPublicKey returns the public key of the address, the node knows it after the first transaction of the address.
+func (c *Client) GetPublicKey(ctx context.Context, address string) (string, error) {
+	res, err := c.blockchainClient.GetPublicKey(ctx, &pactus.GetPublicKeyRequest{
+		Address: address,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res.PublicKey, nil
+}
+
 func (c *Client) GetFee(ctx context.Context, amt int64) (int64, error) {
 	res, err := c.transactionClient.CalculateFee(ctx, &pactus.CalculateFeeRequest{
 		Amount:      amt,
@@ -163,6 +203,39 @@ func (c *Client) GetFee(ctx context.Context, amt int64) (int64, error) {
 	return res.Fee, nil
 }
 
+func (c *Client) GetRawTransferTransaction(ctx context.Context, sender, receiver, memo string,
+	amt int64,
+) ([]byte, error) {
+	res, err := c.transactionClient.GetRawTransferTransaction(ctx, &pactus.GetRawTransferTransactionRequest{
+		Sender:   sender,
+		Receiver: receiver,
+		Amount:   amt,
+		Memo:     memo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.RawTransaction, nil
+}
+
+func (c *Client) GetRawBondTransaction(ctx context.Context, sender, validator, pubKey, memo string,
+	stake int64,
+) ([]byte, error) {
+	res, err := c.transactionClient.GetRawBondTransaction(ctx, &pactus.GetRawBondTransactionRequest{
+		Sender:    sender,
+		Receiver:  validator,
+		Stake:     stake,
+		PublicKey: pubKey,
+		Memo:      memo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.RawTransaction, nil
+}
+
 func (c *Client) Close() error {
 	return c.conn.Close()
 }
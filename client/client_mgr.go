@@ -1,37 +1,77 @@
 package client
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pactus-project/pactus/util/logger"
 	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/cache"
 	"github.com/pagu-project/Pagu/log"
+	"google.golang.org/protobuf/proto"
 )
 
 type Mgr struct {
 	valMapLock sync.RWMutex
 	valMap     map[string]*pactus.PeerInfo
 
-	ctx     context.Context
-	clients []IClient
+	validatorsLock sync.RWMutex
+	validators     []*pactus.ValidatorInfo
+	validatorsAt   time.Time
+
+	ctx context.Context
+
+	// the clients are appended and removed under the select lock, like the discovered nodes.
+	// A removal copies the lists, so the lists that clientList returned don't change.
+	selectLock sync.Mutex
+	clients    []IClient
+	weights    []int
+	drained    []bool
+	stickiness time.Duration
+	selected   int
+	selectedAt time.Time
+
+	cache    cache.Store
+	cacheTTL time.Duration
 }
 
 func NewClientMgr(ctx context.Context) *Mgr {
 	return &Mgr{
 		clients:    make([]IClient, 0),
+		weights:    make([]int, 0),
+		drained:    make([]bool, 0),
 		valMap:     make(map[string]*pactus.PeerInfo),
 		valMapLock: sync.RWMutex{},
+		stickiness: DefaultStickiness,
 		ctx:        ctx,
 	}
 }
 
+// SetStickiness sets how long a selected client keeps receiving the calls before selecting again.
+// It should call before Start.
+func (cm *Mgr) SetStickiness(stickiness time.Duration) {
+	cm.stickiness = stickiness
+}
+
+// SetCache sets the store of the cached RPC results, like the blockchain and the network info.
+// A shared store reduces the calls of all the instances to the nodes. It should call before Start.
+func (cm *Mgr) SetCache(store cache.Store, ttl time.Duration) {
+	cm.cache = store
+	cm.cacheTTL = ttl
+}
+
 func (cm *Mgr) Start() {
 	ticker := time.NewTicker(30 * time.Minute)
 
 	go func() {
+		cm.updateValidators()
+
 		for {
 			select {
 			case <-cm.ctx.Done():
@@ -40,6 +80,7 @@ func (cm *Mgr) Start() {
 			case <-ticker.C:
 				logger.Info("updating validator map started")
 				cm.updateValMap()
+				cm.updateValidators()
 			}
 		}
 	}()
@@ -48,7 +89,7 @@ func (cm *Mgr) Start() {
 }
 
 func (cm *Mgr) Stop() {
-	for addr, c := range cm.clients {
+	for addr, c := range cm.clientList() {
 		if err := c.Close(); err != nil {
 			log.Error("could not close connection to RPC node", "err", err, "RPCAddr", addr)
 		}
@@ -58,12 +99,16 @@ func (cm *Mgr) Stop() {
 func (cm *Mgr) updateValMap() {
 	freshValMap := make(map[string]*pactus.PeerInfo)
 
-	for _, c := range cm.clients {
-		networkInfo, err := c.GetNetworkInfo(cm.ctx)
-		if err != nil {
-			continue
-		}
+	// the nodes are scanned at once, a slow node doesn't delay the others.
+	scan := Scan(cm.ctx, NewScanner(0, 0), cm.clientList(),
+		func(ctx context.Context, c IClient) (*pactus.GetNetworkInfoResponse, error) {
+			return c.GetNetworkInfo(ctx)
+		})
+	if scan.Failed > 0 {
+		logger.Warn("can't get the network info of some nodes", "failed", scan.Failed)
+	}
 
+	for _, networkInfo := range scan.Results {
 		if networkInfo == nil {
 			logger.Warn("network info is nil")
 			continue
@@ -92,44 +137,149 @@ func (cm *Mgr) updateValMap() {
 }
 
 // AddClient should call before Start.
+// The first client is the local client and receives all the calls,
+// the others are only used for the network and peer info.
 func (cm *Mgr) AddClient(c IClient) {
+	weight := 0
+	if len(cm.clientList()) == 0 {
+		weight = 1
+	}
+
+	cm.AddWeightedClient(c, weight)
+}
+
+// AddWeightedClient adds a client that receives a share of the calls proportional to its weight.
+// Clients with zero weight are only used for the network and peer info.
+// It can call after Start too, like for the discovered nodes.
+func (cm *Mgr) AddWeightedClient(c IClient, weight int) {
+	cm.selectLock.Lock()
+	defer cm.selectLock.Unlock()
+
 	cm.clients = append(cm.clients, c)
+	cm.weights = append(cm.weights, weight)
+	cm.drained = append(cm.drained, false)
+	// selecting again, so the new client receives its share of the calls.
+	cm.selectedAt = time.Time{}
+}
+
+// RemoveClient removes the client of the target, like a discovered node that is not healthy anymore,
+// and returns it to be closed. The last client that is not drained is not removed.
+func (cm *Mgr) RemoveClient(target string) (IClient, bool) {
+	cm.selectLock.Lock()
+	defer cm.selectLock.Unlock()
+
+	index := slices.IndexFunc(cm.clients, func(c IClient) bool {
+		return c.Target() == target
+	})
+	if index < 0 || (!cm.drained[index] && len(cm.drained)-countTrue(cm.drained) == 1) {
+		return nil, false
+	}
+
+	c := cm.clients[index]
+	cm.clients = slices.Delete(slices.Clone(cm.clients), index, index+1)
+	cm.weights = slices.Delete(slices.Clone(cm.weights), index, index+1)
+	cm.drained = slices.Delete(slices.Clone(cm.drained), index, index+1)
+	// selecting again, so the removed client doesn't receive the sticky calls.
+	cm.selectedAt = time.Time{}
+
+	return c, true
+}
+
+// HasNode returns true if a client of the manager has the target, like: "localhost:50051".
+func (cm *Mgr) HasNode(target string) bool {
+	return slices.ContainsFunc(cm.clientList(), func(c IClient) bool {
+		return c.Target() == target
+	})
+}
+
+// clientList returns the clients, the manager doesn't change a returned list, so it's safe to read without the lock.
+func (cm *Mgr) clientList() []IClient {
+	cm.selectLock.Lock()
+	defer cm.selectLock.Unlock()
+
+	return cm.clients[:len(cm.clients):len(cm.clients)]
+}
+
+// getClient returns the client to send the call to.
+// The client is selected randomly by weight and kept for the stickiness duration,
+// so consecutive calls see the same node state.
+// NOTE: the local client is used if no client has a weight, or the first client that is not drained.
+func (cm *Mgr) getClient() IClient {
+	cm.selectLock.Lock()
+	defer cm.selectLock.Unlock()
+
+	if time.Since(cm.selectedAt) >= cm.stickiness {
+		cm.selected = cm.selectWeighted()
+		cm.selectedAt = time.Now()
+	}
+
+	return cm.clients[cm.selected]
 }
 
-// NOTE: local client is always the first client.
-func (cm *Mgr) getLocalClient() IClient {
-	return cm.clients[0]
+func (cm *Mgr) selectWeighted() int {
+	total := 0
+	for i, w := range cm.weights {
+		if !cm.drained[i] {
+			total += w
+		}
+	}
+
+	if total == 0 {
+		if i := slices.Index(cm.drained, false); i >= 0 {
+			return i
+		}
+
+		return 0
+	}
+
+	n := rand.IntN(total)
+	for i, w := range cm.weights {
+		if cm.drained[i] {
+			continue
+		}
+
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+
+	return 0
 }
 
 func (cm *Mgr) GetRandomClient() IClient {
-	for _, c := range cm.clients {
+	for _, c := range cm.clientList() {
 		return c
 	}
 
 	return nil
 }
 
-func (cm *Mgr) GetBlockchainInfo() (*pactus.GetBlockchainInfoResponse, error) {
-	localClient := cm.getLocalClient()
-	info, err := localClient.GetBlockchainInfo(cm.ctx)
-	if err != nil {
-		return nil, err
-	}
-	return info, nil
+func (cm *Mgr) GetBlockchainInfo(ctx context.Context) (*pactus.GetBlockchainInfoResponse, error) {
+	return cachedCall(ctx, cm, "rpc:blockchain-info", &pactus.GetBlockchainInfoResponse{},
+		func() (*pactus.GetBlockchainInfoResponse, error) {
+			c := cm.getClient()
+
+			return c.GetBlockchainInfo(ctx)
+		})
 }
 
-func (cm *Mgr) GetBlockchainHeight() (uint32, error) {
-	localClient := cm.getLocalClient()
-	height, err := localClient.GetBlockchainHeight(cm.ctx)
+func (cm *Mgr) GetBlockchainHeight(ctx context.Context) (uint32, error) {
+	c := cm.getClient()
+	height, err := c.GetBlockchainHeight(ctx)
 	if err != nil {
 		return 0, err
 	}
 	return height, nil
 }
 
-func (cm *Mgr) GetLastBlockTime() (uint32, uint32) {
-	localClient := cm.getLocalClient()
-	lastBlockTime, lastBlockHeight, err := localClient.LastBlockTime(cm.ctx)
+func (cm *Mgr) GetBlockTransactions(ctx context.Context, height uint32) (*pactus.GetBlockResponse, error) {
+	return cm.getClient().GetBlockTransactions(ctx, height)
+}
+
+func (cm *Mgr) GetLastBlockTime(ctx context.Context) (uint32, uint32) {
+	c := cm.getClient()
+	lastBlockTime, lastBlockHeight, err := c.LastBlockTime(ctx)
 	if err != nil {
 		return 0, 0
 	}
@@ -137,18 +287,81 @@ func (cm *Mgr) GetLastBlockTime() (uint32, uint32) {
 	return lastBlockTime, lastBlockHeight
 }
 
-func (cm *Mgr) GetNetworkInfo() (*pactus.GetNetworkInfoResponse, error) {
-	for _, c := range cm.clients {
-		info, err := c.GetNetworkInfo(cm.ctx)
-		if err != nil {
-			continue
-		}
-		return info, nil
+func (cm *Mgr) GetNetworkInfo(ctx context.Context) (*pactus.GetNetworkInfoResponse, error) {
+	return cachedCall(ctx, cm, "rpc:network-info", &pactus.GetNetworkInfoResponse{},
+		func() (*pactus.GetNetworkInfoResponse, error) {
+			clients := cm.clientList()
+			for _, c := range clients {
+				info, err := c.GetNetworkInfo(ctx)
+				if err != nil {
+					continue
+				}
+
+				return info, nil
+			}
+
+			return nil, NetworkInfoError{
+				Reason: fmt.Sprintf("can't get network info from non of %v nodes", len(clients)),
+			}
+		})
+}
+
+// GetNetworkBytes returns the total bytes that the nodes sent and received, and how many nodes are counted.
+// The nodes are asked at once, and a node that doesn't answer in the timeout is left out of the total.
+// A node counts its bytes in uint32, the total is in uint64 so it doesn't overflow.
+func (cm *Mgr) GetNetworkBytes(ctx context.Context) (sent, received uint64, nodes int) {
+	scan := Scan(ctx, NewScanner(0, trafficTimeout), cm.clientList(),
+		func(nodeCtx context.Context, c IClient) (*pactus.GetNetworkInfoResponse, error) {
+			return c.GetNetworkInfo(nodeCtx)
+		})
+
+	for _, info := range scan.Results {
+		sent += uint64(info.TotalSentBytes)
+		received += uint64(info.TotalReceivedBytes)
 	}
 
-	return nil, NetworkInfoError{
-		Reason: fmt.Sprintf("can't get network info from non of %v nodes", len(cm.clients)),
+	return sent, received, len(scan.Results)
+}
+
+// PeerView is how a node of the manager sees a peer, the nodes are the vantage points of the network.
+type PeerView struct {
+	Node  int              // The number of the node, from one. The targets of the nodes are not shown to the users.
+	Error error            // The node didn't answer.
+	Peer  *pactus.PeerInfo // Nil if the node doesn't know the peer.
+}
+
+// PeerViews returns how each node sees the first of its peers that matches, like by the peer ID.
+// The peers of a node are the connected ones and the ones that it knows, the banned peers too.
+func (cm *Mgr) PeerViews(ctx context.Context, match func(p *pactus.PeerInfo) bool) []PeerView {
+	clients := cm.clientList()
+	views := make([]PeerView, len(clients))
+
+	wg := sync.WaitGroup{}
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c IClient) {
+			defer wg.Done()
+
+			views[i] = PeerView{Node: i + 1}
+			info, err := c.GetNetworkInfo(ctx)
+			if err != nil {
+				views[i].Error = err
+
+				return
+			}
+
+			for _, p := range info.ConnectedPeers {
+				if match(p) {
+					views[i].Peer = p
+
+					break
+				}
+			}
+		}(i, c)
 	}
+	wg.Wait()
+
+	return views
 }
 
 func (cm *Mgr) GetPeerInfo(address string) (*pactus.PeerInfo, error) {
@@ -166,89 +379,129 @@ func (cm *Mgr) GetPeerInfo(address string) (*pactus.PeerInfo, error) {
 	return peerInfo, nil
 }
 
-func (cm *Mgr) GetValidatorInfo(address string) (*pactus.GetValidatorResponse, error) {
-	localClient := cm.getLocalClient()
-	val, err := localClient.GetValidatorInfo(cm.ctx, address)
+// PeerMatch is a validator address of a connected peer.
+type PeerMatch struct {
+	Address string
+	Peer    *pactus.PeerInfo
+}
+
+// FindPeers returns the validator addresses of the connected peers whose moniker contains the text,
+// case-insensitive. They are sorted by the moniker and the address.
+func (cm *Mgr) FindPeers(text string) []PeerMatch {
+	text = strings.ToLower(text)
+
+	cm.valMapLock.RLock()
+	matches := make([]PeerMatch, 0)
+	for addr, p := range cm.valMap {
+		if strings.Contains(strings.ToLower(p.Moniker), text) {
+			matches = append(matches, PeerMatch{
+				Address: addr,
+				Peer:    p,
+			})
+		}
+	}
+	cm.valMapLock.RUnlock()
+
+	slices.SortFunc(matches, func(a, b PeerMatch) int {
+		return cmp.Or(cmp.Compare(a.Peer.Moniker, b.Peer.Moniker), cmp.Compare(a.Address, b.Address))
+	})
+
+	return matches
+}
+
+func (cm *Mgr) GetValidatorInfo(ctx context.Context, address string) (*pactus.GetValidatorResponse, error) {
+	c := cm.getClient()
+	val, err := c.GetValidatorInfo(ctx, address)
 	if err != nil {
 		return nil, err
 	}
 	return val, nil
 }
 
-func (cm *Mgr) GetValidatorInfoByNumber(num int32) (*pactus.GetValidatorResponse, error) {
-	localClient := cm.getLocalClient()
-	val, err := localClient.GetValidatorInfoByNumber(cm.ctx, num)
+func (cm *Mgr) GetValidatorInfoByNumber(ctx context.Context, num int32) (*pactus.GetValidatorResponse, error) {
+	c := cm.getClient()
+	val, err := c.GetValidatorInfoByNumber(ctx, num)
 	if err != nil {
 		return nil, err
 	}
 	return val, nil
 }
 
-func (cm *Mgr) GetTransactionData(txID string) (*pactus.GetTransactionResponse, error) {
-	localClient := cm.getLocalClient()
-	txData, err := localClient.GetTransactionData(cm.ctx, txID)
+func (cm *Mgr) GetTransactionData(ctx context.Context, txID string) (*pactus.GetTransactionResponse, error) {
+	c := cm.getClient()
+	txData, err := c.GetTransactionData(ctx, txID)
 	if err != nil {
 		return nil, err
 	}
 	return txData, nil
 }
 
-func (cm *Mgr) GetBalance(addr string) (int64, error) {
-	return cm.getLocalClient().GetBalance(cm.ctx, addr)
+func (cm *Mgr) GetBalance(ctx context.Context, addr string) (int64, error) {
+	return cm.getClient().GetBalance(ctx, addr)
+}
+
+func (cm *Mgr) GetPublicKey(ctx context.Context, addr string) (string, error) {
+	return cm.getClient().GetPublicKey(ctx, addr)
+}
+
+func (cm *Mgr) GetFee(ctx context.Context, amt int64) (int64, error) {
+	return cm.getClient().GetFee(ctx, amt)
 }
 
-func (cm *Mgr) GetFee(amt int64) (int64, error) {
-	return cm.getLocalClient().GetFee(cm.ctx, amt)
+// GetRawTransferTransaction asks the selected node to build an unsigned transfer transaction.
+// The lock time and fee are filled by the node based on its current state.
+func (cm *Mgr) GetRawTransferTransaction(ctx context.Context, sender, receiver, memo string,
+	amt int64,
+) ([]byte, error) {
+	return cm.getClient().GetRawTransferTransaction(ctx, sender, receiver, memo, amt)
 }
 
-func (cm *Mgr) GetCirculatingSupply() (int64, error) {
-	localClient := cm.getLocalClient()
+// GetRawBondTransaction asks the selected node to build an unsigned bond transaction.
+// The public key can be empty if the validator is already known by the network.
+func (cm *Mgr) GetRawBondTransaction(ctx context.Context, sender, validator, pubKey, memo string,
+	stake int64,
+) ([]byte, error) {
+	return cm.getClient().GetRawBondTransaction(ctx, sender, validator, pubKey, memo, stake)
+}
 
-	height, err := localClient.GetBlockchainInfo(cm.ctx)
+func (cm *Mgr) GetCirculatingSupply(ctx context.Context) (int64, error) {
+	supply, err := cm.GetSupply(ctx)
 	if err != nil {
 		return 0, err
 	}
-	minted := float64(height.LastBlockHeight) * 1e9
-	staked := height.TotalPower
-	warm := int64(630_000_000_000_000)
-
-	var addr1Out int64 = 0
-	var addr2Out int64 = 0
-	var addr3Out int64 = 0
-	var addr4Out int64 = 0
-	var addr5Out int64 = 0 // warm wallet
-	var addr6Out int64 = 0 // warm wallet
 
-	balance1, err := localClient.GetBalance(cm.ctx, "pc1z2r0fmu8sg2ffa0tgrr08gnefcxl2kq7wvquf8z")
-	if err == nil {
-		addr1Out = 8_400_000_000_000_000 - balance1
-	}
+	return supply.Circulating, nil
+}
 
-	balance2, err := localClient.GetBalance(cm.ctx, "pc1zprhnvcsy3pthekdcu28cw8muw4f432hkwgfasv")
-	if err == nil {
-		addr2Out = 6_300_000_000_000_000 - balance2
+// cachedCall returns the cached response if it's not expired, otherwise it calls the node and caches the response.
+// The cache is best effort, the node is called if the cache store fails.
+func cachedCall[T proto.Message](ctx context.Context, cm *Mgr, key string, cached T,
+	call func() (T, error),
+) (T, error) {
+	if cm.cache == nil || cm.cacheTTL <= 0 {
+		return call()
 	}
 
-	balance3, err := localClient.GetBalance(cm.ctx, "pc1znn2qxsugfrt7j4608zvtnxf8dnz8skrxguyf45")
-	if err == nil {
-		addr3Out = 4_200_000_000_000_000 - balance3
+	data, ok, err := cm.cache.Get(ctx, key)
+	if err != nil {
+		log.Ctx(ctx).Warn("can't read the cached RPC result", "key", key, "err", err)
+	} else if ok && proto.Unmarshal(data, cached) == nil {
+		return cached, nil
 	}
 
-	balance4, err := localClient.GetBalance(cm.ctx, "pc1zs64vdggjcshumjwzaskhfn0j9gfpkvche3kxd3")
-	if err == nil {
-		addr4Out = 2_100_000_000_000_000 - balance4
+	res, err := call()
+	if err != nil {
+		return res, err
 	}
 
-	balance5, err := localClient.GetBalance(cm.ctx, "pc1zuavu4sjcxcx9zsl8rlwwx0amnl94sp0el3u37g")
+	data, err = proto.Marshal(res)
 	if err == nil {
-		addr5Out = 420_000_000_000_000 - balance5
+		err = cm.cache.Set(ctx, key, data, cm.cacheTTL)
 	}
 
-	balance6, err := localClient.GetBalance(cm.ctx, "pc1zf0gyc4kxlfsvu64pheqzmk8r9eyzxqvxlk6s6t")
-	if err == nil {
-		addr6Out = 210_000_000_000_000 - balance6
+	if err != nil {
+		log.Ctx(ctx).Warn("can't cache the RPC result", "key", key, "err", err)
 	}
 
-	circulating := (addr1Out + addr2Out + addr3Out + addr4Out + addr5Out + addr6Out + int64(minted)) - staked - warm
-	return circulating, nil
+	return res, nil
 }
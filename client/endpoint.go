@@ -0,0 +1,30 @@
+package client
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStickiness is how long the selected client keeps receiving the calls by default.
+const DefaultStickiness = time.Minute
+
+// ParseEndpoint parses a node endpoint with an optional weight, like: "localhost:50051=10".
+// The weight is -1 when it's not set.
+func ParseEndpoint(value string) (string, int, error) {
+	// the weight is after the last "=", a password of the endpoint can have one too.
+	index := strings.LastIndex(value, "=")
+	if index < 0 || strings.ContainsAny(value[index+1:], "@:") {
+		return value, -1, nil
+	}
+	endpoint, weightStr := value[:index], value[index+1:]
+
+	weight, err := strconv.Atoi(weightStr)
+	if err != nil || weight < 0 {
+		return "", 0, InvalidEndpointError{
+			Endpoint: value,
+		}
+	}
+
+	return endpoint, weight, nil
+}
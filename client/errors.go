@@ -1,6 +1,12 @@
 package client
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 type NotFoundError struct {
 	Search  string
@@ -11,6 +17,11 @@ func (e NotFoundError) Error() string {
 	return fmt.Sprintf("%s not found with %s address", e.Search, e.Address)
 }
 
+// IsNotFound returns true if the node has no such entity, like a validator that is never bonded.
+func IsNotFound(err error) bool {
+	return errors.As(err, &NotFoundError{}) || status.Code(err) == codes.NotFound
+}
+
 type NetworkInfoError struct {
 	Reason string
 }
@@ -18,3 +29,36 @@ type NetworkInfoError struct {
 func (e NetworkInfoError) Error() string {
 	return e.Reason
 }
+
+type InvalidEndpointError struct {
+	Endpoint string
+}
+
+func (e InvalidEndpointError) Error() string {
+	return fmt.Sprintf("invalid node endpoint %s, the weight should be a non-negative number",
+		RedactEndpoint(e.Endpoint))
+}
+
+type DrainError struct {
+	Node string
+}
+
+func (e DrainError) Error() string {
+	return fmt.Sprintf("%s is the last node that receives the calls, it can't be drained", e.Node)
+}
+
+type InsecureAuthError struct {
+	Endpoint string
+}
+
+func (e InsecureAuthError) Error() string {
+	return fmt.Sprintf("the credentials of %s need a TLS endpoint, like: tls://user:password@%s", e.Endpoint, e.Endpoint)
+}
+
+type InvalidCertificateError struct {
+	File string
+}
+
+func (e InvalidCertificateError) Error() string {
+	return fmt.Sprintf("no PEM certificate in %s", e.File)
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+)
+
+// NodeHash is the hash of a block on a node, the error is set if the node can't return it.
+type NodeHash struct {
+	Node string
+	Hash string
+	Err  error
+}
+
+// GetBlockHashes returns the hash of the block at the height on all the nodes, to compare them.
+func (cm *Mgr) GetBlockHashes(ctx context.Context, height uint32) []NodeHash {
+	clients := cm.clientList()
+	hashes := make([]NodeHash, 0, len(clients))
+	for _, c := range clients {
+		nh := NodeHash{
+			Node: c.Target(),
+		}
+
+		hash, err := c.GetBlockHash(ctx, height)
+		if err != nil {
+			nh.Err = err
+		} else {
+			nh.Hash = hex.EncodeToString(hash)
+		}
+		hashes = append(hashes, nh)
+	}
+
+	return hashes
+}
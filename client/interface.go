@@ -9,12 +9,18 @@ import (
 type IClient interface {
 	GetBlockchainInfo(context.Context) (*pactus.GetBlockchainInfoResponse, error)
 	GetBlockchainHeight(context.Context) (uint32, error)
+	GetBlockHash(context.Context, uint32) ([]byte, error)
+	GetBlockTransactions(context.Context, uint32) (*pactus.GetBlockResponse, error)
 	LastBlockTime(context.Context) (uint32, uint32, error)
 	GetNetworkInfo(context.Context) (*pactus.GetNetworkInfoResponse, error)
 	GetValidatorInfo(context.Context, string) (*pactus.GetValidatorResponse, error)
 	GetValidatorInfoByNumber(context.Context, int32) (*pactus.GetValidatorResponse, error)
 	GetTransactionData(context.Context, string) (*pactus.GetTransactionResponse, error)
 	GetBalance(context.Context, string) (int64, error)
+	GetPublicKey(context.Context, string) (string, error)
 	GetFee(context.Context, int64) (int64, error)
+	GetRawTransferTransaction(context.Context, string, string, string, int64) ([]byte, error)
+	GetRawBondTransaction(context.Context, string, string, string, string, int64) ([]byte, error)
+	Target() string
 	Close() error
 }
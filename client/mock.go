@@ -69,6 +69,36 @@ func (mr *MockIClientMockRecorder) GetBalance(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockIClient)(nil).GetBalance), arg0, arg1)
 }
 
+// GetBlockHash mocks base method.
+func (m *MockIClient) GetBlockHash(arg0 context.Context, arg1 uint32) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHash", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockHash indicates an expected call of GetBlockHash.
+func (mr *MockIClientMockRecorder) GetBlockHash(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHash", reflect.TypeOf((*MockIClient)(nil).GetBlockHash), arg0, arg1)
+}
+
+// GetBlockTransactions mocks base method.
+func (m *MockIClient) GetBlockTransactions(arg0 context.Context, arg1 uint32) (*pactus.GetBlockResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockTransactions", arg0, arg1)
+	ret0, _ := ret[0].(*pactus.GetBlockResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockTransactions indicates an expected call of GetBlockTransactions.
+func (mr *MockIClientMockRecorder) GetBlockTransactions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockTransactions", reflect.TypeOf((*MockIClient)(nil).GetBlockTransactions), arg0, arg1)
+}
+
 // GetBlockchainHeight mocks base method.
 func (m *MockIClient) GetBlockchainHeight(arg0 context.Context) (uint32, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +159,51 @@ func (mr *MockIClientMockRecorder) GetNetworkInfo(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkInfo", reflect.TypeOf((*MockIClient)(nil).GetNetworkInfo), arg0)
 }
 
+// GetPublicKey mocks base method.
+func (m *MockIClient) GetPublicKey(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicKey", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockIClientMockRecorder) GetPublicKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockIClient)(nil).GetPublicKey), arg0, arg1)
+}
+
+// GetRawBondTransaction mocks base method.
+func (m *MockIClient) GetRawBondTransaction(arg0 context.Context, arg1, arg2, arg3, arg4 string, arg5 int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRawBondTransaction", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRawBondTransaction indicates an expected call of GetRawBondTransaction.
+func (mr *MockIClientMockRecorder) GetRawBondTransaction(arg0, arg1, arg2, arg3, arg4, arg5 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRawBondTransaction", reflect.TypeOf((*MockIClient)(nil).GetRawBondTransaction), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// GetRawTransferTransaction mocks base method.
+func (m *MockIClient) GetRawTransferTransaction(arg0 context.Context, arg1, arg2, arg3 string, arg4 int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRawTransferTransaction", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRawTransferTransaction indicates an expected call of GetRawTransferTransaction.
+func (mr *MockIClientMockRecorder) GetRawTransferTransaction(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRawTransferTransaction", reflect.TypeOf((*MockIClient)(nil).GetRawTransferTransaction), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetTransactionData mocks base method.
 func (m *MockIClient) GetTransactionData(arg0 context.Context, arg1 string) (*pactus.GetTransactionResponse, error) {
 	m.ctrl.T.Helper()
@@ -189,3 +264,17 @@ func (mr *MockIClientMockRecorder) LastBlockTime(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastBlockTime", reflect.TypeOf((*MockIClient)(nil).LastBlockTime), arg0)
 }
+
+// Target mocks base method.
+func (m *MockIClient) Target() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Target")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Target indicates an expected call of Target.
+func (mr *MockIClientMockRecorder) Target() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Target", reflect.TypeOf((*MockIClient)(nil).Target))
+}
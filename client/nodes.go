@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxBehind is how many blocks a node can be behind the other nodes and still be healthy.
+	maxBehind = 3
+
+	// heightTimeout is the time to check the height of a node, a node that hangs doesn't hold the others.
+	heightTimeout = 3 * time.Second
+
+	// trafficTimeout is the time to read the traffic of a node, a node that hangs doesn't hold the others.
+	trafficTimeout = 3 * time.Second
+)
+
+type NodeHealth string
+
+const (
+	NodeHealthy     NodeHealth = "healthy"
+	NodeBehind      NodeHealth = "behind"
+	NodeUnreachable NodeHealth = "unreachable"
+	NodeDrained     NodeHealth = "drained"
+)
+
+// NodeState is the state of a node of the manager, the drained nodes don't receive the calls.
+type NodeState struct {
+	Number int // The 1-based position of the node, the local node is the first one.
+	Node   string
+	Weight int
+	Height uint32
+	Err    error
+	Health NodeHealth
+}
+
+// Nodes returns the state of the nodes, the height of each node is checked to find the nodes that are behind.
+// The heights are checked at once, and a node that doesn't answer in the timeout is unreachable.
+func (cm *Mgr) Nodes(ctx context.Context) []NodeState {
+	cm.selectLock.Lock()
+	clients := slices.Clone(cm.clients)
+	weights := slices.Clone(cm.weights)
+	drained := slices.Clone(cm.drained)
+	cm.selectLock.Unlock()
+
+	states := make([]NodeState, 0, len(clients))
+	for i, c := range clients {
+		states = append(states, NodeState{
+			Number: i + 1,
+			Node:   c.Target(),
+			Weight: weights[i],
+		})
+	}
+
+	// the scan function doesn't fail, so a node is left out only if the context is done before it's checked.
+	scan := Scan(ctx, NewScanner(0, heightTimeout), states,
+		func(nodeCtx context.Context, state NodeState) (NodeState, error) {
+			state.Height, state.Err = clients[state.Number-1].GetBlockchainHeight(nodeCtx)
+
+			return state, nil
+		})
+
+	checked := make([]bool, len(states))
+	for _, state := range scan.Results {
+		states[state.Number-1] = state
+		checked[state.Number-1] = true
+	}
+
+	maxHeight := uint32(0)
+	for i := range states {
+		if !checked[i] {
+			states[i].Err = ctx.Err()
+		}
+
+		if states[i].Err == nil {
+			maxHeight = max(maxHeight, states[i].Height)
+		} else {
+			states[i].Height = 0
+		}
+	}
+
+	for i := range states {
+		switch {
+		case drained[i]:
+			states[i].Health = NodeDrained
+		case states[i].Err != nil:
+			states[i].Health = NodeUnreachable
+		case states[i].Height+maxBehind < maxHeight:
+			states[i].Health = NodeBehind
+		default:
+			states[i].Health = NodeHealthy
+		}
+	}
+
+	return states
+}
+
+// Drain stops sending the calls to the node, like during its maintenance.
+// The node is found by its target or its number, the last node that is not drained can't be drained.
+func (cm *Mgr) Drain(node string) (string, error) {
+	return cm.setDrained(node, true)
+}
+
+// Restore sends the calls to the drained node again.
+func (cm *Mgr) Restore(node string) (string, error) {
+	return cm.setDrained(node, false)
+}
+
+func (cm *Mgr) setDrained(node string, drained bool) (string, error) {
+	cm.selectLock.Lock()
+	defer cm.selectLock.Unlock()
+
+	// the node is found under the lock, a removed node, like a discovered one, moves the next nodes.
+	index := nodeIndex(cm.clients, node)
+	if index < 0 {
+		return "", NotFoundError{
+			Search:  "RPC node",
+			Address: node,
+		}
+	}
+
+	if drained && !cm.drained[index] && len(cm.drained)-countTrue(cm.drained) == 1 {
+		return "", DrainError{
+			Node: cm.clients[index].Target(),
+		}
+	}
+
+	cm.drained[index] = drained
+	// selecting again, so the drained node doesn't receive the sticky calls.
+	cm.selectedAt = time.Time{}
+
+	return cm.clients[index].Target(), nil
+}
+
+func nodeIndex(clients []IClient, node string) int {
+	if number, err := strconv.Atoi(node); err == nil {
+		if number >= 1 && number <= len(clients) {
+			return number - 1
+		}
+
+		return -1
+	}
+
+	return slices.IndexFunc(clients, func(c IClient) bool {
+		return c.Target() == node
+	})
+}
+
+func countTrue(values []bool) int {
+	count := 0
+	for _, v := range values {
+		if v {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/pagu-project/Pagu/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	pool := NewPool(config.RPCClient{Size: 3})
+
+	c1, err := pool.NewClient("localhost:50051")
+	require.NoError(t, err)
+	c2, err := pool.NewClient("localhost:50051")
+	require.NoError(t, err)
+	other, err := pool.NewClient("localhost:50052")
+	require.NoError(t, err)
+
+	assert.Same(t, c1.conn, c2.conn, "the clients of an endpoint share the connections")
+	assert.NotSame(t, c1.conn, other.conn)
+	assert.Len(t, c1.conn.conns, 3)
+	assert.Equal(t, "localhost:50051", c1.Target())
+
+	picked := map[any]bool{}
+	for i := 0; i < 3; i++ {
+		picked[c1.conn.pick()] = true
+	}
+	assert.Len(t, picked, 3, "the calls are spread over the connections")
+
+	require.NoError(t, c1.Close())
+	assert.False(t, c2.conn.closed(), "another client uses the connections")
+	require.NoError(t, c2.Close())
+	assert.True(t, c2.conn.closed())
+
+	c3, err := pool.NewClient("localhost:50051")
+	require.NoError(t, err)
+	assert.NotSame(t, c1.conn, c3.conn, "the closed connections are dialed again")
+
+	require.NoError(t, c3.Close())
+	require.NoError(t, other.Close())
+}
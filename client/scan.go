@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	DefaultScanParallel = 8
+	DefaultScanTimeout  = 5 * time.Second
+)
+
+// Scanner scans the peers at once instead of one by one, like the network info of the nodes
+// or the GeoIP of the peers.
+type Scanner struct {
+	Parallel int           // At most this many peers are scanned at once.
+	Timeout  time.Duration // Each peer is given this time, a slow peer is left out.
+}
+
+// NewScanner creates a scanner, a zero parallelism or timeout is the default one.
+func NewScanner(parallel int, timeout time.Duration) Scanner {
+	if parallel <= 0 {
+		parallel = DefaultScanParallel
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultScanTimeout
+	}
+
+	return Scanner{
+		Parallel: parallel,
+		Timeout:  timeout,
+	}
+}
+
+// ScanResult is the partial result of a scan, the failed peers are left out.
+type ScanResult[R any] struct {
+	Results []R // The results of the scanned peers, in the order of the peers.
+	Failed  int // The peers that failed or timed out, and the ones that are not scanned when the context is done.
+}
+
+// Scan calls the scan function for the peers, at most the parallelism of the scanner at once.
+// The scan function is given the timeout of the scanner in its context, it should stop when the context is done.
+// A failed peer doesn't stop the others.
+func Scan[T, R any](ctx context.Context, s Scanner, peers []T,
+	scan func(ctx context.Context, peer T) (R, error),
+) ScanResult[R] {
+	s = NewScanner(s.Parallel, s.Timeout)
+
+	results := make([]R, len(peers))
+	scanned := make([]bool, len(peers))
+	sem := semaphore.NewWeighted(int64(s.Parallel))
+	group := errgroup.Group{}
+
+	for i, peer := range peers {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			// the context is done, the rest of the peers are failed.
+			break
+		}
+
+		group.Go(func() error {
+			defer sem.Release(1)
+
+			peerCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+			defer cancel()
+
+			res, err := scan(peerCtx, peer)
+			if err != nil {
+				// the errors are not returned, so the group doesn't stop the other peers.
+				return nil
+			}
+
+			results[i] = res
+			scanned[i] = true
+
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	res := ScanResult[R]{
+		Results: make([]R, 0, len(peers)),
+	}
+	for i, ok := range scanned {
+		if !ok {
+			res.Failed++
+
+			continue
+		}
+		res.Results = append(res.Results, results[i])
+	}
+
+	return res
+}
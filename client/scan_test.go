@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	t.Run("bounded parallelism and partial results", func(t *testing.T) {
+		var running, most atomic.Int32
+		peers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+		res := Scan(context.Background(), NewScanner(3, time.Second), peers,
+			func(_ context.Context, peer int) (int, error) {
+				now := running.Add(1)
+				defer running.Add(-1)
+				for {
+					last := most.Load()
+					if now <= last || most.CompareAndSwap(last, now) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				if peer%4 == 0 {
+					return 0, errors.New("unreachable")
+				}
+
+				return peer * 10, nil
+			})
+
+		assert.LessOrEqual(t, most.Load(), int32(3))
+		assert.Equal(t, []int{10, 20, 30, 50, 60, 70, 90, 100}, res.Results, "in the order of the peers")
+		assert.Equal(t, 2, res.Failed)
+	})
+
+	t.Run("a slow peer times out", func(t *testing.T) {
+		res := Scan(context.Background(), NewScanner(2, 20*time.Millisecond), []string{"fast", "slow"},
+			func(ctx context.Context, peer string) (string, error) {
+				if peer == "slow" {
+					<-ctx.Done()
+
+					return "", ctx.Err()
+				}
+
+				return peer, nil
+			})
+
+		assert.Equal(t, []string{"fast"}, res.Results)
+		assert.Equal(t, 1, res.Failed)
+	})
+
+	t.Run("a done context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		res := Scan(ctx, NewScanner(0, 0), []int{1, 2},
+			func(_ context.Context, peer int) (int, error) {
+				return peer, nil
+			})
+
+		assert.Empty(t, res.Results)
+		assert.Equal(t, 2, res.Failed)
+	})
+}
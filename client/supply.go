@@ -0,0 +1,94 @@
+package client
+
+import "context"
+
+// reserveAccount is an account of the genesis allocation that releases its coins over time.
+type reserveAccount struct {
+	address    string
+	allocation int64
+	warm       bool // Warm wallets are funded by the reserves, so only their balance is out of circulation.
+}
+
+var reserveAccounts = []reserveAccount{
+	{address: "pc1z2r0fmu8sg2ffa0tgrr08gnefcxl2kq7wvquf8z", allocation: 8_400_000_000_000_000},
+	{address: "pc1zprhnvcsy3pthekdcu28cw8muw4f432hkwgfasv", allocation: 6_300_000_000_000_000},
+	{address: "pc1znn2qxsugfrt7j4608zvtnxf8dnz8skrxguyf45", allocation: 4_200_000_000_000_000},
+	{address: "pc1zs64vdggjcshumjwzaskhfn0j9gfpkvche3kxd3", allocation: 2_100_000_000_000_000},
+	{address: "pc1zuavu4sjcxcx9zsl8rlwwx0amnl94sp0el3u37g", allocation: 420_000_000_000_000, warm: true},
+	{address: "pc1zf0gyc4kxlfsvu64pheqzmk8r9eyzxqvxlk6s6t", allocation: 210_000_000_000_000, warm: true},
+}
+
+// TreasuryAddresses returns the reserve accounts and the warm wallets, their balances are the treasury of the supply.
+func TreasuryAddresses() []string {
+	addresses := make([]string, 0, len(reserveAccounts))
+	for _, acc := range reserveAccounts {
+		addresses = append(addresses, acc.address)
+	}
+
+	return addresses
+}
+
+// Supply is the breakdown of the coins in NanoPAC.
+type Supply struct {
+	Minted      int64 // Block rewards minted since the genesis.
+	Staked      int64 // Coins bonded to validators.
+	Treasury    int64 // Coins held by the reserve accounts and warm wallets.
+	Circulating int64 // Liquid coins that are not staked nor held by the treasury.
+	// Burned is always zero, since Pactus pays the transaction fees to the block proposers.
+	Burned int64
+}
+
+// Total returns the total supply that is released or reserved.
+func (s *Supply) Total() int64 {
+	return s.Circulating + s.Staked + s.Treasury + s.Burned
+}
+
+// Percent returns the share of the amount in the total supply.
+func (s *Supply) Percent(amt int64) float64 {
+	total := s.Total()
+	if total == 0 {
+		return 0
+	}
+
+	return float64(amt) * 100 / float64(total)
+}
+
+// GetSupply calculates the supply breakdown from the minted rewards, the total stake
+// and the balances of the reserve accounts.
+// If the balance of a reserve account is not available, its allocation is assumed not released.
+func (cm *Mgr) GetSupply(ctx context.Context) (*Supply, error) {
+	c := cm.getClient()
+
+	info, err := c.GetBlockchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	minted := int64(info.LastBlockHeight) * 1e9
+	staked := info.TotalPower
+
+	released := int64(0)
+	treasury := int64(0)
+	warm := int64(0)
+	for _, acc := range reserveAccounts {
+		balance, err := c.GetBalance(ctx, acc.address)
+		if err != nil {
+			balance = acc.allocation
+		}
+
+		if acc.warm {
+			warm += balance
+		} else {
+			released += acc.allocation - balance
+			treasury += balance
+		}
+	}
+
+	return &Supply{
+		Minted:      minted,
+		Staked:      staked,
+		Treasury:    treasury + warm,
+		Circulating: released + minted - staked - warm,
+		Burned:      0,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSupply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c := NewMockIClient(ctrl)
+	cm := NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{
+		LastBlockHeight: 1_000_000,
+		TotalPower:      500_000_000_000_000,
+	}, nil)
+
+	// The first reserve account released 1M PAC, the second one is not available
+	// and the others are untouched, except the first warm wallet that spent half of its coins.
+	balances := map[string]int64{
+		reserveAccounts[0].address: reserveAccounts[0].allocation - 1_000_000_000_000_000,
+		reserveAccounts[2].address: reserveAccounts[2].allocation,
+		reserveAccounts[3].address: reserveAccounts[3].allocation,
+		reserveAccounts[4].address: reserveAccounts[4].allocation / 2,
+		reserveAccounts[5].address: reserveAccounts[5].allocation,
+	}
+	c.EXPECT().GetBalance(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, addr string) (int64, error) {
+			balance, ok := balances[addr]
+			if !ok {
+				return 0, errors.New("not found")
+			}
+
+			return balance, nil
+		}).Times(len(reserveAccounts))
+
+	supply, err := cm.GetSupply(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1_000_000_000_000_000), supply.Minted)
+	assert.Equal(t, int64(500_000_000_000_000), supply.Staked)
+	assert.Equal(t, int64(1_000_000_000_000_000+1_000_000_000_000_000-500_000_000_000_000-420_000_000_000_000),
+		supply.Circulating)
+	assert.Equal(t, supply.Circulating+supply.Staked+supply.Treasury, supply.Total())
+	assert.InDelta(t, 100, supply.Percent(supply.Circulating)+supply.Percent(supply.Staked)+supply.Percent(supply.Treasury), 0.0001)
+}
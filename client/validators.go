@@ -0,0 +1,54 @@
+package client
+
+import (
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// updateValidators takes a snapshot of the active validators, there is no RPC to list them,
+// so they are fetched by number in the background.
+func (cm *Mgr) updateValidators() {
+	info, err := cm.GetBlockchainInfo(cm.ctx)
+	if err != nil {
+		log.Warn("can't update the validators", "err", err)
+
+		return
+	}
+
+	validators := make([]*pactus.ValidatorInfo, 0, info.TotalValidators)
+	for num := int32(0); num < info.TotalValidators; num++ {
+		select {
+		case <-cm.ctx.Done():
+			return
+		default:
+		}
+
+		val, err := cm.GetValidatorInfoByNumber(cm.ctx, num)
+		if err != nil {
+			continue
+		}
+
+		if val.Validator.UnbondingHeight != 0 {
+			continue
+		}
+
+		validators = append(validators, val.Validator)
+	}
+
+	cm.validatorsLock.Lock()
+	cm.validators = validators
+	cm.validatorsAt = time.Now()
+	cm.validatorsLock.Unlock()
+
+	log.Info("validators updated successfully", "count", len(validators))
+}
+
+// GetValidators returns the last snapshot of the active validators and the time of the snapshot.
+func (cm *Mgr) GetValidators() ([]*pactus.ValidatorInfo, time.Time) {
+	cm.validatorsLock.RLock()
+	defer cm.validatorsLock.RUnlock()
+
+	return cm.validators, cm.validatorsAt
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/preflight"
+	"github.com/spf13/cobra"
+)
+
+// CheckConfigCommand adds the check-config command, it checks the config before deploying, like in CI.
+// The tokens of the required platforms should be set.
+func CheckConfigCommand(parentCmd *cobra.Command, required ...command.AppID) {
+	check := &cobra.Command{
+		Use:   "check-config",
+		Short: "Checks the config, the RPC nodes, the database and the platform tokens",
+	}
+
+	parentCmd.AddCommand(check)
+
+	envOpt := check.Flags().StringP("env", "e", ".env", "the env file path")
+
+	check.Run = func(cmd *cobra.Command, _ []string) {
+		cfg, err := config.Load(*envOpt)
+		ExitOnError(cmd, err)
+
+		report := preflight.Run(context.Background(), preflight.Checks(cfg, required...))
+		cmd.Println(report.String())
+
+		if report.Failed() > 0 {
+			ExitOnError(cmd, errors.New("config check failed"))
+		}
+	}
+}
@@ -1,67 +1,69 @@
 package main
 
 import (
-	"bufio"
+	"errors"
 	"os"
-	"strings"
 
 	"github.com/pactus-project/pactus/crypto"
 	pagu "github.com/pagu-project/Pagu"
+	"github.com/pagu-project/Pagu/cli"
 	pCmd "github.com/pagu-project/Pagu/cmd"
 	"github.com/pagu-project/Pagu/config"
 	"github.com/pagu-project/Pagu/engine"
-	"github.com/pagu-project/Pagu/engine/command"
 	"github.com/pagu-project/Pagu/log"
 	cobra "github.com/spf13/cobra"
 )
 
-const PROMPT = "\n>> "
+func main() {
+	rootCmd := &cobra.Command{
+		Use:     "pagu-cli [command]",
+		Short:   "Runs the commands from the terminal, the REPL starts if no command is given",
+		Example: "  pagu-cli\n  pagu-cli network health",
+		Version: pagu.StringVersion(),
+		Args:    cobra.ArbitraryArgs,
+	}
 
-func run(cmd *cobra.Command, args []string) {
-	envOpt := cmd.Flags().StringP("env", "e", ".env", "the env file path")
-	config, err := config.Load(*envOpt)
-	pCmd.ExitOnError(cmd, err)
+	// "pagu-cli help" runs the help command of Pagu, the usage of the CLI is in --help.
+	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
 
-	log.InitGlobalLogger(config.Logger)
+	envOpt := rootCmd.Flags().StringP("env", "e", ".env", "the env file path")
+	callerOpt := rootCmd.Flags().String("caller", "0", "the caller ID of the commands")
+	attachmentsOpt := rootCmd.Flags().String("attachments", os.TempDir(), "the directory that the attachments are saved in")
 
-	if config.Network == "Localnet" {
-		crypto.AddressHRP = "tpc"
-	}
+	rootCmd.Run = func(cmd *cobra.Command, args []string) {
+		config, err := config.Load(*envOpt)
+		pCmd.ExitOnError(cmd, err)
 
-	botEngine, err := engine.NewBotEngine(config)
-	pCmd.ExitOnError(cmd, err)
+		log.InitGlobalLogger(config.Logger)
 
-	botEngine.RegisterAllCommands()
-	botEngine.Start()
+		if config.Network == "Localnet" {
+			crypto.AddressHRP = "tpc"
+		}
 
-	reader := bufio.NewReader(os.Stdin)
+		botEngine, err := engine.NewBotEngine(config)
+		pCmd.ExitOnError(cmd, err)
 
-	for {
-		cmd.Print(PROMPT)
+		botEngine.RegisterAllCommands()
+		defer botEngine.Stop()
 
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSuffix(input, "\n")
+		repl := cli.NewREPL(botEngine, *callerOpt, *attachmentsOpt, cmd.OutOrStdout())
 
-		if strings.ToLower(input) == "exit" {
-			cmd.Println("exiting from repl")
+		// a single command runs without the REPL and the scheduled jobs, like in the scripts.
+		if len(args) > 0 {
+			botEngine.StartClients()
+			if !repl.Exec(args) {
+				botEngine.Stop()
+				pCmd.ExitOnError(cmd, errors.New("the command is not successful"))
+			}
 
 			return
 		}
 
-		inputs := strings.Split(input, " ")
-
-		response := botEngine.Run(command.AppIdCLI, "0", inputs)
-
-		cmd.Printf("%v\n%v", response.Title, response.Message)
+		botEngine.Start()
+		pCmd.ExitOnError(cmd, repl.Start(os.Stdin))
 	}
-}
 
-func main() {
-	rootCmd := &cobra.Command{
-		Use:     "pagu-cli",
-		Version: pagu.StringVersion(),
-		Run:     run,
-	}
+	pCmd.CheckConfigCommand(rootCmd)
 
 	err := rootCmd.Execute()
 	pCmd.ExitOnError(rootCmd, err)
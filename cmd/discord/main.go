@@ -3,6 +3,7 @@ package main
 import (
 	pagu "github.com/pagu-project/Pagu"
 	"github.com/pagu-project/Pagu/cmd"
+	"github.com/pagu-project/Pagu/engine/command"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +14,7 @@ func main() {
 	}
 
 	runCommand(rootCmd)
+	cmd.CheckConfigCommand(rootCmd, command.AppIdDiscord)
 
 	err := rootCmd.Execute()
 	cmd.ExitOnError(rootCmd, err)
@@ -13,6 +13,7 @@ func main() {
 	}
 
 	runCommand(rootCmd)
+	cmd.CheckConfigCommand(rootCmd)
 
 	err := rootCmd.Execute()
 	cmd.ExitOnError(rootCmd, err)
@@ -0,0 +1,21 @@
+package main
+
+import (
+	pagu "github.com/pagu-project/Pagu"
+	"github.com/pagu-project/Pagu/cmd"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:     "pagu-queue",
+		Version: pagu.StringVersion(),
+	}
+
+	runCommand(rootCmd)
+	cmd.CheckConfigCommand(rootCmd, command.AppIdQueue)
+
+	err := rootCmd.Execute()
+	cmd.ExitOnError(rootCmd, err)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	pCmd "github.com/pagu-project/Pagu/cmd"
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/engine"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/queue"
+	"github.com/spf13/cobra"
+)
+
+func runCommand(parentCmd *cobra.Command) {
+	run := &cobra.Command{
+		Use:   "run",
+		Short: "Runs a mainnet instance of Pagu that consumes the command requests from the message queue",
+	}
+
+	parentCmd.AddCommand(run)
+
+	run.Run = func(cmd *cobra.Command, _ []string) {
+		// load configuration.
+		config, err := config.Load()
+		pCmd.ExitOnError(cmd, err)
+
+		// Initialize global logger.
+		log.InitGlobalLogger(config.Logger)
+
+		// starting botEngine.
+		botEngine, err := engine.NewBotEngine(config)
+		pCmd.ExitOnError(cmd, err)
+
+		botEngine.RegisterAllCommands()
+		botEngine.Start()
+
+		broker, err := queue.NewNATS(config.Queue.URL)
+		pCmd.ExitOnError(cmd, err)
+
+		queueServer := queue.NewServer(botEngine, broker, config.Queue)
+
+		err = queueServer.Start()
+		pCmd.ExitOnError(cmd, err)
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+		<-sigChan
+
+		if err := queueServer.Stop(); err != nil {
+			pCmd.ExitOnError(cmd, err)
+		}
+
+		botEngine.Stop()
+	}
+}
@@ -0,0 +1,269 @@
+package committee
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const (
+	// checkInterval is the block time of Pactus, the height is checked on each block.
+	checkInterval = 10 * time.Second
+
+	// confirmations is how many blocks the batch is behind the last block, so the nodes have its blocks.
+	confirmations = 2
+
+	// maxHeights is the heights shown for the sortitions and the proposals of a validator, the rest are counted.
+	maxHeights = 5
+
+	// maxValidators is the validators shown in an announcement, to fit in a message.
+	maxValidators = 20
+)
+
+// Store keeps the watched validators and the posted announcements.
+type Store interface {
+	GetAllWatchedValidators() ([]*database.WatchedValidator, error)
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Win is the sortitions and the proposed blocks of a watched validator in a batch of blocks.
+type Win struct {
+	Address          string
+	Sortitions       int
+	SortitionHeights []uint32 // The first heights of the sortitions, up to maxHeights.
+	Proposals        int
+	ProposalHeights  []uint32 // The first heights of the proposed blocks, up to maxHeights.
+}
+
+// Batch is the wins of the watched validators in the blocks from the first height to the last one.
+type Batch struct {
+	From uint32
+	To   uint32
+	Wins []Win
+	More int // The validators with a win that are not shown.
+}
+
+// Announcer posts the sortitions and the proposed blocks of the watched validators to the channels.
+// The blocks are batched, so the channels get one announcement in N blocks whatever the win rate is.
+// The batches are the multiples of N, so an announcement of the instances is posted once.
+type Announcer struct {
+	lock        sync.Mutex
+	clientMgr   *client.Mgr
+	store       Store
+	hub         *notify.Hub
+	channels    []notify.Channel
+	batchBlocks uint32
+	last        uint32 // The last height of the last batch, zero until the first run.
+}
+
+// NewAnnouncer creates the announcer of the batches of N blocks, zero blocks or no channel disables it.
+func NewAnnouncer(clientMgr *client.Mgr, store Store, hub *notify.Hub,
+	channels []notify.Channel, batchBlocks uint32,
+) *Announcer {
+	return &Announcer{
+		clientMgr:   clientMgr,
+		store:       store,
+		hub:         hub,
+		channels:    channels,
+		batchBlocks: batchBlocks,
+	}
+}
+
+// Job returns the scheduler job of the announcements. It's not exclusive, each instance reads the batches
+// and the first one that claims an announcement posts it.
+func (a *Announcer) Job() scheduler.Job {
+	interval := checkInterval
+	if a.batchBlocks == 0 || len(a.channels) == 0 {
+		interval = 0
+	}
+
+	return scheduler.Job{
+		Name:      "committee",
+		Interval:  interval,
+		Exclusive: false,
+		Run:       a.Run,
+	}
+}
+
+// Run announces the last batch of blocks, if it's not announced yet. The first run starts from the last batch,
+// the wins before the start are not announced, and a batch that is missed in a long outage is skipped.
+// A batch that fails to be collected is collected again in the next run.
+func (a *Announcer) Run(ctx context.Context) error {
+	if a.batchBlocks == 0 {
+		return nil
+	}
+
+	height, err := a.clientMgr.GetBlockchainHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	if height <= confirmations {
+		return nil
+	}
+
+	to := (height - confirmations) / a.batchBlocks * a.batchBlocks
+	a.lock.Lock()
+	last := a.last
+	if last == 0 {
+		a.last = to
+	}
+	a.lock.Unlock()
+
+	if last == 0 || to <= last {
+		return nil
+	}
+
+	watched, err := a.store.GetAllWatchedValidators()
+	if err != nil {
+		return err
+	}
+
+	if len(watched) == 0 {
+		a.advance(to)
+
+		return nil
+	}
+
+	addresses := make(map[string]bool, len(watched))
+	for _, w := range watched {
+		addresses[w.Address] = true
+	}
+
+	batch, err := a.Collect(ctx, to-a.batchBlocks+1, to, addresses)
+	if err != nil {
+		return err
+	}
+	a.advance(to)
+
+	if len(batch.Wins) > 0 {
+		a.announce(batch)
+	}
+
+	return nil
+}
+
+// advance sets the last batch, a slower run of an older batch doesn't move it back.
+func (a *Announcer) advance(to uint32) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.last = max(a.last, to)
+}
+
+// Collect returns the wins of the addresses in the blocks from the first height to the last one.
+// The validators are sorted by the wins, the most first.
+func (a *Announcer) Collect(ctx context.Context, from, to uint32, addresses map[string]bool) (Batch, error) {
+	wins := make(map[string]*Win)
+	win := func(address string) *Win {
+		w, ok := wins[address]
+		if !ok {
+			w = &Win{Address: address}
+			wins[address] = w
+		}
+
+		return w
+	}
+
+	for h := from; h <= to; h++ {
+		if ctx.Err() != nil {
+			return Batch{}, ctx.Err()
+		}
+
+		block, err := a.clientMgr.GetBlockTransactions(ctx, h)
+		if err != nil {
+			return Batch{}, err
+		}
+
+		if block.Header != nil && addresses[block.Header.ProposerAddress] {
+			w := win(block.Header.ProposerAddress)
+			w.Proposals++
+			if len(w.ProposalHeights) < maxHeights {
+				w.ProposalHeights = append(w.ProposalHeights, h)
+			}
+		}
+
+		for _, tx := range block.Txs {
+			sortition, ok := tx.Payload.(*pactus.TransactionInfo_Sortition)
+			if !ok || !addresses[sortition.Sortition.Address] {
+				continue
+			}
+
+			w := win(sortition.Sortition.Address)
+			w.Sortitions++
+			if len(w.SortitionHeights) < maxHeights {
+				w.SortitionHeights = append(w.SortitionHeights, h)
+			}
+		}
+	}
+
+	batch := Batch{
+		From: from,
+		To:   to,
+		Wins: make([]Win, 0, len(wins)),
+	}
+	for _, w := range wins {
+		batch.Wins = append(batch.Wins, *w)
+	}
+
+	slices.SortFunc(batch.Wins, func(a, b Win) int {
+		if diff := (b.Sortitions + b.Proposals) - (a.Sortitions + a.Proposals); diff != 0 {
+			return diff
+		}
+
+		if a.Address < b.Address {
+			return -1
+		}
+
+		return 1
+	})
+
+	if len(batch.Wins) > maxValidators {
+		batch.More = len(batch.Wins) - maxValidators
+		batch.Wins = batch.Wins[:maxValidators]
+	}
+
+	return batch, nil
+}
+
+func (a *Announcer) announce(batch Batch) {
+	for _, channel := range a.channels {
+		if !a.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		key := "committee:" + strconv.FormatUint(uint64(batch.To), 10) + ":" + channel.String()
+		claimed, err := a.store.ClaimAnnouncement(key)
+		if err != nil {
+			log.Error("can't claim the committee announcement", "err", err, "channel", channel)
+
+			continue
+		}
+
+		if !claimed {
+			// another instance posted it.
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, "committee_announcement", batch)
+		if err != nil {
+			log.Error("can't render the committee announcement", "err", err)
+
+			continue
+		}
+
+		if err := a.hub.Announce(channel, msg); err != nil {
+			log.Warn("can't post the committee announcement", "err", err, "channel", channel)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package committee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type memoryStore struct {
+	watched []*database.WatchedValidator
+	claimed map[string]bool
+}
+
+func (s *memoryStore) GetAllWatchedValidators() ([]*database.WatchedValidator, error) {
+	return s.watched, nil
+}
+
+func (s *memoryStore) ClaimAnnouncement(key string) (bool, error) {
+	if s.claimed[key] {
+		return false, nil
+	}
+	s.claimed[key] = true
+
+	return true, nil
+}
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func sortitionTx(address string) *pactus.TransactionInfo {
+	return &pactus.TransactionInfo{
+		Payload: &pactus.TransactionInfo_Sortition{Sortition: &pactus.PayloadSortition{Address: address}},
+	}
+}
+
+func TestAnnouncer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	height := uint32(12)
+	failed := uint32(0) // The height of the block that fails to be read.
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainHeight(gomock.Any()).DoAndReturn(func(_ context.Context) (uint32, error) {
+		return height, nil
+	}).AnyTimes()
+	c.EXPECT().GetBlockTransactions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, h uint32) (*pactus.GetBlockResponse, error) {
+			if h == failed {
+				return nil, errors.New("unavailable")
+			}
+
+			block := &pactus.GetBlockResponse{
+				Height: h,
+				Header: &pactus.BlockHeaderInfo{ProposerAddress: "pc1p-other"},
+				Txs:    []*pactus.TransactionInfo{sortitionTx("pc1p-other")},
+			}
+			if h%2 == 0 {
+				block.Header.ProposerAddress = "pc1p-alice"
+			}
+			if h == 13 || h == 17 {
+				block.Txs = append(block.Txs, sortitionTx("pc1p-bob"))
+			}
+
+			return block, nil
+		}).AnyTimes()
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	store := &memoryStore{
+		watched: []*database.WatchedValidator{
+			{UserID: "alice", Address: "pc1p-alice"},
+			{UserID: "bob", Address: "pc1p-bob"},
+			{UserID: "carol", Address: "pc1p-bob"},
+		},
+		claimed: make(map[string]bool),
+	}
+	announcer := func() *Announcer {
+		return NewAnnouncer(cm, store, hub, []notify.Channel{
+			{AppID: command.AppIdDiscord, ID: "wins"},
+			{AppID: command.AppIdTelegram, ID: "-1001"},
+		}, 10)
+	}
+	a := announcer()
+	ctx := context.Background()
+
+	require.NoError(t, a.Run(ctx), "the first run starts from the last batch")
+	assert.Empty(t, discord)
+
+	height = 21
+	require.NoError(t, a.Run(ctx), "the batch of 11 to 20 is not confirmed")
+	assert.Empty(t, discord)
+
+	height = 22
+	require.NoError(t, a.Run(ctx))
+	require.NoError(t, a.Run(ctx), "the batch is announced once")
+	require.Len(t, discord["wins"], 1)
+	assert.Equal(t, "Committee of the watched validators🔔 blocks 11 to 20\n\n"+
+		"pc1p-alice\nProposed 5 blocks, at 12, 14, 16, 18, 20\n\n"+
+		"pc1p-bob\nJoined the committee 2 times, at 13, 17", discord["wins"][0])
+
+	other := announcer()
+	require.NoError(t, other.Run(ctx))
+	height = 32
+	require.NoError(t, other.Run(ctx))
+	require.NoError(t, a.Run(ctx))
+	assert.Len(t, discord["wins"], 2, "the instances post a batch once")
+	assert.Contains(t, discord["wins"][1], "blocks 21 to 30\n\npc1p-alice\nProposed 5 blocks")
+
+	height, failed = 42, 35
+	require.Error(t, a.Run(ctx))
+	assert.Len(t, discord["wins"], 2)
+
+	failed = 0
+	require.NoError(t, a.Run(ctx), "the failed batch is collected again")
+	require.Len(t, discord["wins"], 3)
+	assert.Contains(t, discord["wins"][2], "blocks 31 to 40")
+
+	t.Run("batches", func(t *testing.T) {
+		addresses := make(map[string]bool)
+		for i := 0; i < 25; i++ {
+			addresses[fmt.Sprintf("pc1p-%02d", i)] = true
+		}
+		addresses["pc1p-alice"] = true
+
+		batch, err := a.Collect(ctx, 1, 14, addresses)
+		require.NoError(t, err)
+		require.Len(t, batch.Wins, 1)
+		assert.Equal(t, 7, batch.Wins[0].Proposals)
+		assert.Len(t, batch.Wins[0].ProposalHeights, maxHeights)
+
+		msg, err := command.RenderTemplate(command.AppIdCLI, "committee_announcement", batch)
+		require.NoError(t, err)
+		assert.Contains(t, msg, "Proposed 7 blocks, at 2, 4, 6, 8, 10 and more")
+
+		wins := make([]Win, 0, 25)
+		for i := 0; i < 25; i++ {
+			wins = append(wins, Win{Address: fmt.Sprintf("pc1p-%02d", i), Sortitions: 1, SortitionHeights: []uint32{1}})
+		}
+		msg, err = command.RenderTemplate(command.AppIdCLI, "committee_announcement", Batch{
+			From: 1, To: 10, Wins: wins[:maxValidators], More: 5,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, msg, "pc1p-00\nJoined the committee 1 time, at 1\n\n")
+		assert.True(t, strings.HasSuffix(msg, "\n\nAnd 5 more validators."), msg)
+	})
+
+	assert.Zero(t, NewAnnouncer(cm, store, hub, nil, 10).Job().Interval, "no channel")
+	assert.Zero(t, NewAnnouncer(cm, store, hub, a.channels, 0).Job().Interval, "no batch")
+}
@@ -0,0 +1,205 @@
+package concentration
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+// checkInterval is the block time of Pactus, the height is checked on each block.
+const checkInterval = 10 * time.Second
+
+// Holder is a committee validator and its share of the committee power in percent.
+type Holder struct {
+	Address string
+	Stake   int64
+	Share   float64
+}
+
+// Status is the result of the last check of the committee.
+type Status struct {
+	Checked  bool
+	Height   uint32 // The first block of the epoch.
+	Power    int64  // The total power of the committee.
+	Top      []Holder
+	Share    float64 // The share of the top validators in percent.
+	MaxShare float64
+	Exceeded bool
+}
+
+// Store keeps the posted alerts.
+type Store interface {
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Monitor computes the share of the committee power that the top N validators hold, once in each epoch.
+// The operators are alerted when the share exceeds the maximum and when it's back under it.
+type Monitor struct {
+	lock      sync.Mutex
+	clientMgr *client.Mgr
+	store     Store
+	hub       *notify.Hub
+	channels  []notify.Channel
+	top       int
+	maxShare  float64
+	epoch     uint32
+	status    Status
+}
+
+// NewMonitor creates the monitor of the top N validators, with epochs of the blocks. Zero epoch disables it.
+func NewMonitor(clientMgr *client.Mgr, store Store, hub *notify.Hub,
+	channels []notify.Channel, top int, maxShare float64, epoch uint32,
+) *Monitor {
+	return &Monitor{
+		clientMgr: clientMgr,
+		store:     store,
+		hub:       hub,
+		channels:  channels,
+		top:       top,
+		maxShare:  maxShare,
+		epoch:     epoch,
+	}
+}
+
+// Status returns the result of the last check.
+func (m *Monitor) Status() Status {
+	if m == nil {
+		return Status{}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.status
+}
+
+// Job returns the scheduler job of the checks. It's not exclusive, each instance keeps its own status.
+// The epochs start at the multiples of N, so an alert of the instances is posted once.
+func (m *Monitor) Job() scheduler.Job {
+	interval := checkInterval
+	if m.epoch == 0 {
+		interval = 0
+	}
+
+	return scheduler.Job{
+		Name:      "concentration",
+		Interval:  interval,
+		Exclusive: false,
+		Run:       m.Run,
+	}
+}
+
+// Run checks the committee, if it's not checked in the current epoch yet.
+func (m *Monitor) Run(ctx context.Context) error {
+	if m.epoch == 0 {
+		return nil
+	}
+
+	info, err := m.clientMgr.GetBlockchainInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	epoch := info.LastBlockHeight / m.epoch * m.epoch
+	last := m.Status()
+	if last.Checked && epoch <= last.Height {
+		return nil
+	}
+
+	holders := make([]Holder, 0, len(info.CommitteeValidators))
+	for _, val := range info.CommitteeValidators {
+		holders = append(holders, Holder{
+			Address: val.Address,
+			Stake:   val.Stake,
+		})
+	}
+
+	status := Status{
+		Checked:  true,
+		Height:   epoch,
+		Power:    info.CommitteePower,
+		Top:      topHolders(holders, m.top, info.CommitteePower),
+		MaxShare: m.maxShare,
+	}
+
+	for _, holder := range status.Top {
+		status.Share += holder.Share
+	}
+	status.Exceeded = status.Share > m.maxShare
+
+	m.lock.Lock()
+	m.status = status
+	m.lock.Unlock()
+
+	switch {
+	case status.Exceeded && !last.Exceeded:
+		log.Warn("the committee power is concentrated", "height", epoch, "share", status.Share)
+		m.alert("exceeded", status)
+
+	case !status.Exceeded && last.Exceeded:
+		log.Info("the committee power is decentralized again", "height", epoch, "share", status.Share)
+		m.alert("resolved", status)
+	}
+
+	return nil
+}
+
+// topHolders returns the N validators with the most stake, with their shares of the power.
+func topHolders(holders []Holder, top int, power int64) []Holder {
+	slices.SortFunc(holders, func(a, b Holder) int {
+		return cmp.Or(cmp.Compare(b.Stake, a.Stake), cmp.Compare(a.Address, b.Address))
+	})
+
+	holders = holders[:min(top, len(holders))]
+	if power > 0 {
+		for i := range holders {
+			holders[i].Share = float64(holders[i].Stake) * 100 / float64(power)
+		}
+	}
+
+	return holders
+}
+
+func (m *Monitor) alert(kind string, status Status) {
+	for _, channel := range m.channels {
+		if !m.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		key := "concentration:" + kind + ":" + strconv.FormatUint(uint64(status.Height), 10) + ":" + channel.String()
+		claimed, err := m.store.ClaimAnnouncement(key)
+		if err != nil {
+			log.Error("can't claim the concentration alert", "err", err, "channel", channel)
+
+			continue
+		}
+
+		if !claimed {
+			// another instance posted it.
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, "concentration_alert", map[string]any{
+			"Resolved": kind == "resolved",
+			"Status":   status,
+		})
+		if err != nil {
+			log.Error("can't render the concentration alert", "err", err)
+
+			continue
+		}
+
+		if err := m.hub.Announce(channel, msg); err != nil {
+			log.Warn("can't post the concentration alert", "err", err, "channel", channel)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package concentration
+
+import (
+	"context"
+	"testing"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type memoryStore map[string]bool
+
+func (s memoryStore) ClaimAnnouncement(key string) (bool, error) {
+	if s[key] {
+		return false, nil
+	}
+	s[key] = true
+
+	return true, nil
+}
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func TestMonitor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	info := &pactus.GetBlockchainInfoResponse{
+		LastBlockHeight: 105,
+		CommitteePower:  100,
+		CommitteeValidators: []*pactus.ValidatorInfo{
+			{Address: "val-a", Stake: 20},
+			{Address: "val-b", Stake: 10},
+			{Address: "val-c", Stake: 40},
+			{Address: "val-d", Stake: 30},
+		},
+	}
+
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).DoAndReturn(func(_ context.Context) (*pactus.GetBlockchainInfoResponse, error) {
+		return info, nil
+	}).AnyTimes()
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	monitor := NewMonitor(cm, memoryStore{}, hub, []notify.Channel{{AppID: command.AppIdDiscord, ID: "ops"}}, 2, 75, 10)
+
+	require.NoError(t, monitor.Run(context.Background()))
+	status := monitor.Status()
+	assert.True(t, status.Checked)
+	assert.Equal(t, uint32(100), status.Height)
+	assert.Equal(t, []Holder{
+		{Address: "val-c", Stake: 40, Share: 40},
+		{Address: "val-d", Stake: 30, Share: 30},
+	}, status.Top)
+	assert.InDelta(t, 70, status.Share, 0.001)
+	assert.False(t, status.Exceeded)
+	assert.Empty(t, discord["ops"])
+
+	info.LastBlockHeight = 112
+	info.CommitteeValidators[0].Stake = 90
+	info.CommitteePower = 170
+	require.NoError(t, monitor.Run(context.Background()))
+	assert.True(t, monitor.Status().Exceeded, "130 of 170")
+	require.Len(t, discord["ops"], 1)
+	assert.Contains(t, discord["ops"][0], "Committee power concentrated")
+	assert.Contains(t, discord["ops"][0], "hold 76.47% of the committee power at block 110, over 75.00%")
+	assert.Contains(t, discord["ops"][0], "val-a: 52.94%")
+
+	info.LastBlockHeight = 118
+	require.NoError(t, monitor.Run(context.Background()))
+	assert.Len(t, discord["ops"], 1, "checked once in the epoch")
+
+	info.LastBlockHeight = 120
+	info.CommitteeValidators[0].Stake = 20
+	info.CommitteePower = 100
+	require.NoError(t, monitor.Run(context.Background()))
+	assert.False(t, monitor.Status().Exceeded)
+	require.Len(t, discord["ops"], 2)
+	assert.Contains(t, discord["ops"][1], "Committee power decentralized")
+
+	var nilMonitor *Monitor
+	assert.Equal(t, Status{}, nilMonitor.Status())
+}
@@ -2,28 +2,131 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/pactus-project/pactus/util"
 )
 
+const (
+	DefaultSLOTarget        = 99.0
+	DefaultNodeStickiness   = time.Minute
+	DefaultRPCPoolSize      = 2
+	DefaultRPCKeepalive     = 5 * time.Minute
+	DefaultRPCKeepaliveWait = 20 * time.Second
+	DefaultRPCIdleTimeout   = 30 * time.Minute
+	DefaultRPCReconnect     = 30 * time.Second
+	DefaultMinAccountAge    = 30 * 24 * time.Hour
+	DefaultMaxAddressUsers  = 1
+	DefaultMaxUserAddresses = 3
+	DefaultChallengeTTL     = 5 * time.Minute
+	DefaultBackupInterval   = 24 * time.Hour
+	DefaultBackupKeep       = 7
+	DefaultRetentionEvery   = 24 * time.Hour
+	DefaultCacheTTL         = 10 * time.Second
+	DefaultRateLimitWindow  = time.Minute
+	DefaultAtRiskMinScore   = 0.8
+	DefaultAtRiskMaxScore   = 0.9
+	DefaultMarketInterval   = 5 * time.Minute
+	DefaultMaxPriceAlerts   = 5
+	DefaultContextTTL       = 10 * time.Minute
+	DefaultPromptTTL        = 2 * time.Minute
+	DefaultCommandTimeout   = 30 * time.Second
+	DefaultMaxWatched       = 10
+	DefaultReleaseInterval  = time.Hour
+	DefaultForkCheckBlocks  = 10
+	DefaultQueueWorkers     = 8
+	DefaultTopValidators    = 5
+	DefaultMaxPowerShare    = 33.0 // Over a third of the power can stop the consensus.
+	DefaultEpochBlocks      = 360  // One hour.
+	DefaultStatusInterval   = 5 * time.Minute
+	DefaultAmountPrecision  = 9 // The NanoPAC precision.
+	DefaultRemindBefore     = time.Hour
+	DefaultFaucetMemo       = "Pagu faucet {claim_id}"
+	DefaultRewardMemo       = "Pagu Zealy reward {claim_id}"
+	DefaultMessageLimit     = 4096 // The embed description on Discord and the message on Telegram.
+	DefaultFloodWindow      = 10 * time.Second
+	DefaultFloodMute        = 10 * time.Minute
+	DefaultCommitteeBlocks  = 60 // About ten minutes.
+	DefaultAccountsStep     = 10_000
+	DefaultSpikeFactor      = 3.0
+	DefaultSpikeMin         = 500
+	DefaultDiscoveryPort    = 50051
+	DefaultDiscoveryWeight  = 1
+	DefaultDiscoveryNodes   = 5
+	DefaultDiscoveryEvery   = time.Hour
+	DefaultReportDay        = time.Monday
+	DefaultReportHour       = 12
+	DefaultSampleRatio      = 1.0
+	DefaultTreasuryAlert    = 100_000
+	DefaultDiscordSendRate  = 50.0 // The global rate limit of the bots on Discord.
+	DefaultTelegramSendRate = 30.0 // The broadcast limit of the bots on Telegram.
+)
+
+// DefaultRetentionDays are the days that the rows of the tables are kept, the samples of the charts are kept longer.
+// The announcements are the claims of the posted alerts and announcements.
+var DefaultRetentionDays = map[string]int64{
+	"announcements":        90,
+	"availability_samples": 180,
+	"price_samples":        365,
+}
+
 type Config struct {
-	Network       string
-	NetworkNodes  []string
-	LocalNode     string
-	DataBasePath  string
-	AuthIDs       []string
-	DiscordBot    DiscordBot
-	GRPC          GRPC
-	Wallet        Wallet
-	TestNetWallet Wallet
-	Logger        Logger
-	HTTP          HTTP
-	Phoenix       PhoenixNetwork
-	Telegram      Telegram
+	Network        string
+	InstanceID     string // Name of the instance in the locks shared with the other instances.
+	NetworkNodes   []string
+	LocalNode      string
+	NodeStickiness time.Duration // How long the selected node keeps receiving the calls.
+	ReadOnly       bool          // The state-changing commands are disabled, like the faucet and the payouts.
+	RPCClient      RPCClient
+	Discovery      Discovery
+	DataBasePath   string
+	Backup         Backup
+	Retention      Retention
+	TemplatesPath  string
+	ExplorerURL    string   // Base URL of the block explorer that the outputs link to.
+	AllowedHosts   []string // Hosts that the outputs can link to besides the explorer, instead of the default ones.
+	TrafficPath    string   // The commands are recorded to the file for the benchmarks, empty disables it.
+	Theme          Theme
+	Amount         Amount
+	AuthIDs        []string
+	SLOTarget      float64 // Target success rate in percent of commands and RPC calls.
+	AtRiskScore    ScoreRange
+	ContextTTL     time.Duration // How long a follow-up message can refer to the last answer.
+	PromptTTL      time.Duration // How long a command waits for a missing argument on the chat platforms.
+	CommandTimeout time.Duration // How long the engine waits for a command, the commands can have their own timeout.
+	MaxWatched     int64         // Validators that a user can watch.
+	DiscordBot     DiscordBot
+	GRPC           GRPC
+	Wallet         Wallet
+	TestNetWallet  Wallet
+	Logger         Logger
+	HTTP           HTTP
+	Queue          Queue
+	Phoenix        PhoenixNetwork
+	Zealy          Zealy
+	FaucetAbuse    FaucetAbuse
+	Challenge      Challenge
+	Cache          Cache
+	Market         Market
+	NLP            NLP
+	Release        Release
+	Fork           Fork
+	Committee      Committee
+	Accounts       Accounts
+	Report         Report
+	Telemetry      Telemetry
+	Concentration  Concentration
+	Treasury       Treasury
+	StatusPage     StatusPage
+	Maintenance    Maintenance
+	Feedback       Feedback
+	Flood          Flood
+	Telegram       Telegram
 }
 
 type Wallet struct {
@@ -35,21 +138,231 @@ type Wallet struct {
 }
 
 type DiscordBot struct {
-	Token   string
-	GuildID string
+	Token          string
+	MessageContent bool    // Reads the messages for the prefixed commands, it's a privileged intent of the bot.
+	MessageLimit   int     // Longer results are summarized, and the full result is attached as a text file.
+	ShardCount     int     // The shards of the gateway, zero uses the count that Discord recommends.
+	ShardIDs       []int   // The shards that this instance runs, empty runs all of them.
+	SendRate       float64 // The outgoing messages per second, zero doesn't pace them.
 }
 
 type GRPC struct {
 	Listen string
 }
 
+// RPCClient is the connections to each node, their keepalive and reconnect policies,
+// and the TLS and the auth of the TLS endpoints, like: "tls://gateway.example.org:443".
+type RPCClient struct {
+	Size             int64         // Connections to each node, the calls are spread over them.
+	Keepalive        time.Duration // Time between the pings of an active connection, zero disables them.
+	KeepaliveTimeout time.Duration // A connection without the ping answer in this time is dialed again.
+	IdleTimeout      time.Duration // A connection without any call in this time is closed until the next call.
+	ReconnectDelay   time.Duration // Maximum delay between the reconnects of a failed connection.
+	TLSCAFile        string        // Custom CA certificates of the TLS endpoints, the system ones by default.
+	TLSCertFile      string        // Client certificate and key for the mutual TLS, optional.
+	TLSKeyFile       string
+	AuthToken        string // Bearer token of the TLS endpoints, the basic auth is used if it's empty.
+	Username         string
+	Password         string
+}
+
 type HTTP struct {
 	Listen string
+	// TrustedProxies are the proxies whose X-Forwarded-For is the IP of the caller, like a load balancer.
+	// The IP of the connection is the caller without them.
+	TrustedProxies []*net.IPNet
+}
+
+// Queue is the message queue that the other services send the command requests to, like NATS.
+type Queue struct {
+	URL           string // Like: "nats://localhost:4222".
+	Subject       string // The subject of the requests, "pagu.commands" by default.
+	Group         string // The instances in the group share the requests, "pagu" by default.
+	ResultSubject string // The subject of the results of the requests without a reply subject, "pagu.results" by default.
+	Workers       int64  // Requests that are run at the same time.
 }
 
 type PhoenixNetwork struct {
 	NetworkNodes []string
 	FaucetAmount uint
+	FaucetMemo   string // Memo template of the faucet transactions, like: "Pagu faucet {claim_id}".
+}
+
+type Zealy struct {
+	RewardMemo string // Memo template of the reward transactions, like: "Pagu Zealy reward {claim_id}".
+}
+
+// FaucetAbuse is the thresholds of the faucet abuse detection, suspicious claims are reviewed by admins.
+type FaucetAbuse struct {
+	MinAccountAge    time.Duration
+	MaxAddressUsers  int64
+	MaxUserAddresses int64
+	BlockedASNs      []string
+	ReviewHosting    bool
+}
+
+// Challenge is the verification step before high-value commands.
+type Challenge struct {
+	TTL             time.Duration
+	HCaptchaSiteKey string
+	HCaptchaSecret  string
+}
+
+// Market is the price source of PAC and the limits of the price alerts.
+type Market struct {
+	PriceURL     string // Xeggex market endpoint, the PAC/USDT market by default.
+	PollInterval time.Duration
+	MaxAlerts    int64              // Active price alerts allowed per user.
+	Rates        map[string]float64 // The USD rates of the fiat currencies of the users, like: "EUR": 0.92.
+}
+
+// Release is the source of the Pactus node releases and the channels that the new ones are announced to.
+type Release struct {
+	URL          string   // GitHub latest release endpoint, the Pactus repository by default.
+	Channels     []string // In "Platform:ID" format, like: "Discord:1234" or "Telegram:-1001234".
+	PollInterval time.Duration
+}
+
+// Fork is the comparison of the block hashes of the nodes, different hashes mean a fork.
+type Fork struct {
+	CheckBlocks   int64    // Hashes are compared every N blocks, zero disables the checks.
+	AlertChannels []string // In "Platform:ID" format, like: "Discord:1234" or "Telegram:-1001234".
+}
+
+// Discovery is the RPC nodes that are found by the DNS seeds and added to the nodes after a probe.
+type Discovery struct {
+	Seeds    []string // The host names of the seeds, with an optional port like: "seed.example.org:50052".
+	Port     int64    // The RPC port of the seeds without a port.
+	Weight   int64    // The weight of the discovered nodes, zero only uses them for the network and peer info.
+	MaxNodes int64    // The discovered nodes an instance adds at most, zero disables the discovery.
+	Interval time.Duration
+}
+
+// Committee is the announcements of the sortitions and the proposed blocks of the watched validators.
+type Committee struct {
+	BatchBlocks int64    // The wins in N blocks are posted at once, zero disables the announcements.
+	Channels    []string // In "Platform:ID" format, like: "Discord:1234" or "Telegram:-1001234".
+}
+
+// Accounts is the announcements of the milestones of the accounts and the spikes of the new accounts in a day.
+type Accounts struct {
+	MilestoneStep     int64    // The milestones are the multiples of it, zero disables them.
+	MilestoneChannels []string // In "Platform:ID" format, like: "Discord:1234" or "Telegram:-1001234".
+	SpikeFactor       float64  // The new accounts over this times the daily average are a spike, zero disables them.
+	SpikeMin          int64    // The fewer new accounts in a day are not a spike.
+	SpikeChannels     []string
+}
+
+// Report is the weekly report of the network, posted on the day and the hour of the week in UTC.
+type Report struct {
+	Weekday  time.Weekday
+	Hour     int64
+	Channels []string // In "Platform:ID" format, like: "Discord:1234" or "Telegram:-1001234".
+}
+
+// Telemetry is the OTLP collector of the traces and the metrics, like an OpenTelemetry Collector.
+type Telemetry struct {
+	Endpoint    string  // Like: "localhost:4317", empty disables the export.
+	Insecure    bool    // The collector has no TLS, like a local agent.
+	SampleRatio float64 // The share of the commands that are traced, between 0 and 1.
+}
+
+// Concentration is the share of the committee power that the top validators hold, checked once in each epoch.
+type Concentration struct {
+	TopValidators int64
+	MaxShare      float64  // Maximum share of the top validators in percent, more is alerted.
+	EpochBlocks   int64    // The committee is checked every N blocks, zero disables the checks.
+	AlertChannels []string // In "Platform:ID" format, like: "Discord:1234" or "Telegram:-1001234".
+}
+
+// Treasury is the alerts of the large outgoing transactions of the reserve accounts and the warm wallets.
+type Treasury struct {
+	AlertAmount   int64    // In PAC, the transactions of this amount or more are alerted, zero disables the alerts.
+	AlertChannels []string // In "Platform:ID" format, like: "Discord:1234" or "Telegram:-1001234".
+}
+
+// Maintenance is the reminder of the maintenance windows that the admins schedule.
+type Maintenance struct {
+	RemindBefore  time.Duration // The operators are reminded before the window starts, zero disables it.
+	AlertChannels []string      // In "Platform:ID" format, like: "Discord:1234" or "Telegram:-1001234".
+}
+
+// Feedback is the suggestions and the bugs that the users send with the feedback command.
+type Feedback struct {
+	Channels []string // The maintainer channels in "Platform:ID" format, like: "Discord:1234".
+}
+
+// Flood is the flood protection of the chat platforms, a user that sends too many messages is muted for a while.
+type Flood struct {
+	Messages      int64 // Messages allowed per user in the window, zero disables the protection.
+	Window        time.Duration
+	Mute          time.Duration
+	AlertChannels []string // The moderator channels in "Platform:ID" format, like: "Discord:1234".
+}
+
+// StatusPage is the static page of the command outputs, written to the directory and to the S3 bucket if they are set.
+type StatusPage struct {
+	Interval    time.Duration // Zero disables the page.
+	Commands    []string      // Empty is the network status, health and growth.
+	Path        string
+	S3Endpoint  string // Like: "s3.amazonaws.com", or the endpoint of an S3 compatible storage.
+	S3Bucket    string
+	S3Prefix    string // Directory of the files in the bucket.
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// NLP is the optional layer that maps the free-text questions to the commands,
+// with keyword rules and an LLM with an OpenAI compatible API if its URL is set.
+type NLP struct {
+	Enable    bool
+	LLMURL    string // Chat completions endpoint, like: "https://api.openai.com/v1/chat/completions".
+	LLMAPIKey string
+	LLMModel  string
+}
+
+// ScoreRange is the PIP-19 score range of the at-risk validators, the maximum is exclusive.
+type ScoreRange struct {
+	Min float64
+	Max float64
+}
+
+// Cache is the store of the cached RPC results and the rate limit counters,
+// Redis shares them between the instances.
+type Cache struct {
+	Driver          string
+	RedisAddress    string
+	RedisPassword   string
+	RedisDB         int64
+	TTL             time.Duration
+	RateLimit       int64 // Commands allowed per caller in the window, zero disables the limit.
+	RateLimitWindow time.Duration
+	GuildQuota      int64 // Expensive commands allowed per guild in a day, zero disables the quota.
+}
+
+// Backup is the schedule of the database backups, restoring is documented in the README.
+type Backup struct {
+	Path     string
+	Interval time.Duration
+	Keep     int64
+}
+
+// Retention is how long the rows of the tables are kept, the older rows are pruned on schedule.
+type Retention struct {
+	Interval time.Duration    // Time between the prunings, zero disables them.
+	Days     map[string]int64 // The days that the rows of each table are kept, zero keeps them forever.
+}
+
+// Amount is how the amounts are shown in the outputs, like: "1,234.56 PAC".
+type Amount struct {
+	Precision int64  // Decimal digits of the PAC amounts, the trailing zeros are removed.
+	Unit      string // PAC or NanoPAC.
+}
+
+type Theme struct {
+	Name      string
+	Overrides []string
+	PlainText bool // The outputs have no emoji and decorative characters, like for the screen readers.
 }
 
 type Logger struct {
@@ -62,9 +375,14 @@ type Logger struct {
 }
 
 type Telegram struct {
-	BotToken  string
-	ChatID    int64
-	GroupLink string
+	BotToken     string
+	ChatID       int64
+	GroupLink    string
+	MessageLimit int     // Longer results are summarized, and the full result is attached as a text file.
+	SendRate     float64 // The outgoing messages per second, zero doesn't pace them.
+
+	// The Telegram user IDs allowed to run the admin commands.
+	AuthIDs []string
 }
 
 func Load(filePaths ...string) (*Config, error) {
@@ -112,9 +430,407 @@ func Load(filePaths ...string) (*Config, error) {
 		return nil, err
 	}
 
+	sloTarget, err := getEnvFloat("SLO_TARGET", DefaultSLOTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	if sloTarget <= 0 || sloTarget > 100 {
+		return nil, fmt.Errorf("config: SLO_TARGET should be between 0 and 100")
+	}
+
+	nodeStickiness, err := getEnvDuration("NODE_STICKINESS", DefaultNodeStickiness)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcPoolSize, err := getEnvInt("RPC_POOL_SIZE", DefaultRPCPoolSize)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcKeepalive, err := getEnvDuration("RPC_KEEPALIVE", DefaultRPCKeepalive)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcKeepaliveTimeout, err := getEnvDuration("RPC_KEEPALIVE_TIMEOUT", DefaultRPCKeepaliveWait)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcIdleTimeout, err := getEnvDuration("RPC_IDLE_TIMEOUT", DefaultRPCIdleTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcReconnect, err := getEnvDuration("RPC_RECONNECT_DELAY", DefaultRPCReconnect)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryPort, err := getEnvInt("DISCOVERY_PORT", DefaultDiscoveryPort)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryWeight, err := getEnvInt("DISCOVERY_WEIGHT", DefaultDiscoveryWeight)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryNodes, err := getEnvInt("DISCOVERY_MAX_NODES", DefaultDiscoveryNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryInterval, err := getEnvDuration("DISCOVERY_INTERVAL", DefaultDiscoveryEvery)
+	if err != nil {
+		return nil, err
+	}
+
+	if discoveryWeight < 0 || discoveryNodes < 0 {
+		return nil, fmt.Errorf("config: DISCOVERY_WEIGHT and DISCOVERY_MAX_NODES should not be negative")
+	}
+
+	minAccountAge, err := getEnvDuration("FAUCET_MIN_ACCOUNT_AGE", DefaultMinAccountAge)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAddressUsers, err := getEnvInt("FAUCET_MAX_ADDRESS_USERS", DefaultMaxAddressUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	maxUserAddresses, err := getEnvInt("FAUCET_MAX_USER_ADDRESSES", DefaultMaxUserAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewHosting, err := getEnvBool("FAUCET_REVIEW_HOSTING", true)
+	if err != nil {
+		return nil, err
+	}
+
+	contextTTL, err := getEnvDuration("CONTEXT_TTL", DefaultContextTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	promptTTL, err := getEnvDuration("PROMPT_TTL", DefaultPromptTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	commandTimeout, err := getEnvDuration("COMMAND_TIMEOUT", DefaultCommandTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	maxWatched, err := getEnvInt("MAX_WATCHED_VALIDATORS", DefaultMaxWatched)
+	if err != nil {
+		return nil, err
+	}
+
+	nlpEnable, err := getEnvBool("ENABLE_NLP", false)
+	if err != nil {
+		return nil, err
+	}
+
+	plainText, err := getEnvBool("PLAIN_TEXT", false)
+	if err != nil {
+		return nil, err
+	}
+
+	readOnly, err := getEnvBool("READ_ONLY", false)
+	if err != nil {
+		return nil, err
+	}
+
+	discordMessageContent, err := getEnvBool("DISCORD_MESSAGE_CONTENT", false)
+	if err != nil {
+		return nil, err
+	}
+
+	discordMessageLimit, err := getEnvInt("DISCORD_MESSAGE_LIMIT", DefaultMessageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	shardCount, err := getEnvInt("DISCORD_SHARD_COUNT", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	shardIDs, err := getEnvInts("DISCORD_SHARD_IDS")
+	if err != nil {
+		return nil, err
+	}
+
+	if shardCount < 0 {
+		return nil, fmt.Errorf("config: DISCORD_SHARD_COUNT should not be negative")
+	}
+
+	for _, id := range shardIDs {
+		if id < 0 || (shardCount > 0 && id >= int(shardCount)) {
+			return nil, fmt.Errorf("config: DISCORD_SHARD_IDS should be between 0 and DISCORD_SHARD_COUNT-1, got: %d", id)
+		}
+	}
+
+	telegramMessageLimit, err := getEnvInt("TELEGRAM_MESSAGE_LIMIT", DefaultMessageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	discordSendRate, err := getEnvFloat("DISCORD_SEND_RATE", DefaultDiscordSendRate)
+	if err != nil {
+		return nil, err
+	}
+
+	telegramSendRate, err := getEnvFloat("TELEGRAM_SEND_RATE", DefaultTelegramSendRate)
+	if err != nil {
+		return nil, err
+	}
+
+	if discordSendRate < 0 || telegramSendRate < 0 {
+		return nil, fmt.Errorf("config: DISCORD_SEND_RATE and TELEGRAM_SEND_RATE should not be negative")
+	}
+
+	challengeTTL, err := getEnvDuration("CHALLENGE_TTL", DefaultChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	backupInterval, err := getEnvDuration("BACKUP_INTERVAL", DefaultBackupInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	backupKeep, err := getEnvInt("BACKUP_KEEP", DefaultBackupKeep)
+	if err != nil {
+		return nil, err
+	}
+
+	retentionInterval, err := getEnvDuration("RETENTION_INTERVAL", DefaultRetentionEvery)
+	if err != nil {
+		return nil, err
+	}
+
+	retentionDays, err := getEnvDays("RETENTION_DAYS", DefaultRetentionDays)
+	if err != nil {
+		return nil, err
+	}
+
+	redisDB, err := getEnvInt("REDIS_DB", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheTTL, err := getEnvDuration("CACHE_TTL", DefaultCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimit, err := getEnvInt("RATE_LIMIT", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	guildQuota, err := getEnvInt("GUILD_QUOTA", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimitWindow, err := getEnvDuration("RATE_LIMIT_WINDOW", DefaultRateLimitWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	atRiskMin, err := getEnvFloat("VALIDATOR_AT_RISK_MIN", DefaultAtRiskMinScore)
+	if err != nil {
+		return nil, err
+	}
+
+	atRiskMax, err := getEnvFloat("VALIDATOR_AT_RISK_MAX", DefaultAtRiskMaxScore)
+	if err != nil {
+		return nil, err
+	}
+
+	if atRiskMin >= atRiskMax {
+		return nil, fmt.Errorf("config: VALIDATOR_AT_RISK_MIN should be less than VALIDATOR_AT_RISK_MAX")
+	}
+
+	releaseInterval, err := getEnvDuration("RELEASE_POLL_INTERVAL", DefaultReleaseInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	forkCheckBlocks, err := getEnvInt("FORK_CHECK_BLOCKS", DefaultForkCheckBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if forkCheckBlocks < 0 {
+		return nil, fmt.Errorf("config: FORK_CHECK_BLOCKS should not be negative")
+	}
+
+	committeeBlocks, err := getEnvInt("COMMITTEE_BATCH_BLOCKS", DefaultCommitteeBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if committeeBlocks < 0 {
+		return nil, fmt.Errorf("config: COMMITTEE_BATCH_BLOCKS should not be negative")
+	}
+
+	accountsStep, err := getEnvInt("ACCOUNTS_MILESTONE_STEP", DefaultAccountsStep)
+	if err != nil {
+		return nil, err
+	}
+
+	spikeFactor, err := getEnvFloat("ACCOUNTS_SPIKE_FACTOR", DefaultSpikeFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	spikeMin, err := getEnvInt("ACCOUNTS_SPIKE_MIN", DefaultSpikeMin)
+	if err != nil {
+		return nil, err
+	}
+
+	if accountsStep < 0 || spikeFactor < 0 || spikeMin < 0 {
+		return nil, fmt.Errorf("config: ACCOUNTS_MILESTONE_STEP, ACCOUNTS_SPIKE_FACTOR and ACCOUNTS_SPIKE_MIN " +
+			"should not be negative")
+	}
+
+	reportDay, err := getEnvWeekday("WEEKLY_REPORT_DAY", DefaultReportDay)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedProxies, err := getEnvNets("HTTP_TRUSTED_PROXIES")
+	if err != nil {
+		return nil, err
+	}
+
+	reportHour, err := getEnvInt("WEEKLY_REPORT_HOUR", DefaultReportHour)
+	if err != nil {
+		return nil, err
+	}
+
+	if reportHour < 0 || reportHour > 23 {
+		return nil, fmt.Errorf("config: WEEKLY_REPORT_HOUR should be between 0 and 23")
+	}
+
+	telemetryInsecure, err := getEnvBool("TELEMETRY_INSECURE", false)
+	if err != nil {
+		return nil, err
+	}
+
+	sampleRatio, err := getEnvFloat("TELEMETRY_SAMPLE_RATIO", DefaultSampleRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	if sampleRatio < 0 || sampleRatio > 1 {
+		return nil, fmt.Errorf("config: TELEMETRY_SAMPLE_RATIO should be between 0 and 1")
+	}
+
+	topValidators, err := getEnvInt("CONCENTRATION_TOP_VALIDATORS", DefaultTopValidators)
+	if err != nil {
+		return nil, err
+	}
+
+	if topValidators <= 0 {
+		return nil, fmt.Errorf("config: CONCENTRATION_TOP_VALIDATORS should be positive")
+	}
+
+	maxPowerShare, err := getEnvFloat("CONCENTRATION_MAX_SHARE", DefaultMaxPowerShare)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxPowerShare <= 0 || maxPowerShare > 100 {
+		return nil, fmt.Errorf("config: CONCENTRATION_MAX_SHARE should be between 0 and 100")
+	}
+
+	epochBlocks, err := getEnvInt("CONCENTRATION_EPOCH_BLOCKS", DefaultEpochBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if epochBlocks < 0 {
+		return nil, fmt.Errorf("config: CONCENTRATION_EPOCH_BLOCKS should not be negative")
+	}
+
+	treasuryAlert, err := getEnvInt("TREASURY_ALERT_AMOUNT", DefaultTreasuryAlert)
+	if err != nil {
+		return nil, err
+	}
+
+	if treasuryAlert < 0 {
+		return nil, fmt.Errorf("config: TREASURY_ALERT_AMOUNT should not be negative")
+	}
+
+	amountPrecision, err := getEnvInt("AMOUNT_PRECISION", DefaultAmountPrecision)
+	if err != nil {
+		return nil, err
+	}
+
+	statusInterval, err := getEnvDuration("STATUS_PAGE_INTERVAL", DefaultStatusInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	remindBefore, err := getEnvDuration("MAINTENANCE_REMIND_BEFORE", DefaultRemindBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	floodMessages, err := getEnvInt("FLOOD_MESSAGES", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	floodWindow, err := getEnvDuration("FLOOD_WINDOW", DefaultFloodWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	floodMute, err := getEnvDuration("FLOOD_MUTE", DefaultFloodMute)
+	if err != nil {
+		return nil, err
+	}
+
+	queueWorkers, err := getEnvInt("QUEUE_WORKERS", DefaultQueueWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	if queueWorkers <= 0 {
+		return nil, fmt.Errorf("config: QUEUE_WORKERS should be positive")
+	}
+
+	marketInterval, err := getEnvDuration("MARKET_POLL_INTERVAL", DefaultMarketInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	currencyRates, err := getEnvRates("CURRENCY_RATES")
+	if err != nil {
+		return nil, err
+	}
+
+	maxPriceAlerts, err := getEnvInt("MARKET_MAX_ALERTS", DefaultMaxPriceAlerts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Fetch config values from environment variables.
 	cfg := &Config{
-		Network: os.Getenv("NETWORK"),
+		Network:    os.Getenv("NETWORK"),
+		InstanceID: os.Getenv("INSTANCE_ID"),
 		Wallet: Wallet{
 			Address:  os.Getenv("WALLET_ADDRESS"),
 			Path:     os.Getenv("WALLET_PATH"),
@@ -129,13 +845,70 @@ func Load(filePaths ...string) (*Config, error) {
 			RPCUrl:   os.Getenv("TESTNET_WALLET_PRC"),
 			Enable:   enableTestNetWallet,
 		},
-		LocalNode:    os.Getenv("LOCAL_NODE"),
-		NetworkNodes: strings.Split(os.Getenv("NETWORK_NODES"), ","),
-		DataBasePath: os.Getenv("DATABASE_PATH"),
-		AuthIDs:      strings.Split(os.Getenv("AUTHORIZED_DISCORD_IDS"), ","),
+		LocalNode:      os.Getenv("LOCAL_NODE"),
+		NodeStickiness: nodeStickiness,
+		ReadOnly:       readOnly,
+		NetworkNodes:   strings.Split(os.Getenv("NETWORK_NODES"), ","),
+		DataBasePath:   os.Getenv("DATABASE_PATH"),
+		TemplatesPath:  os.Getenv("TEMPLATES_PATH"),
+		TrafficPath:    os.Getenv("TRAFFIC_RECORD_PATH"),
+		ExplorerURL:    os.Getenv("EXPLORER_URL"),
+		AllowedHosts:   splitNonEmpty(os.Getenv("OUTPUT_ALLOWED_HOSTS")),
+		RPCClient: RPCClient{
+			Size:             rpcPoolSize,
+			Keepalive:        rpcKeepalive,
+			KeepaliveTimeout: rpcKeepaliveTimeout,
+			IdleTimeout:      rpcIdleTimeout,
+			ReconnectDelay:   rpcReconnect,
+			TLSCAFile:        os.Getenv("RPC_TLS_CA"),
+			TLSCertFile:      os.Getenv("RPC_TLS_CERT"),
+			TLSKeyFile:       os.Getenv("RPC_TLS_KEY"),
+			AuthToken:        os.Getenv("RPC_AUTH_TOKEN"),
+			Username:         os.Getenv("RPC_USERNAME"),
+			Password:         os.Getenv("RPC_PASSWORD"),
+		},
+		Discovery: Discovery{
+			Seeds:    splitNonEmpty(os.Getenv("DISCOVERY_SEEDS")),
+			Port:     discoveryPort,
+			Weight:   discoveryWeight,
+			MaxNodes: discoveryNodes,
+			Interval: discoveryInterval,
+		},
+		Backup: Backup{
+			Path:     os.Getenv("BACKUP_PATH"),
+			Interval: backupInterval,
+			Keep:     backupKeep,
+		},
+		Retention: Retention{
+			Interval: retentionInterval,
+			Days:     retentionDays,
+		},
+		Theme: Theme{
+			Name:      os.Getenv("THEME"),
+			Overrides: strings.Split(os.Getenv("THEME_OVERRIDES"), ","),
+			PlainText: plainText,
+		},
+		Amount: Amount{
+			Precision: amountPrecision,
+			Unit:      os.Getenv("AMOUNT_UNIT"),
+		},
+		AuthIDs:   strings.Split(os.Getenv("AUTHORIZED_DISCORD_IDS"), ","),
+		SLOTarget: sloTarget,
+		AtRiskScore: ScoreRange{
+			Min: atRiskMin,
+			Max: atRiskMax,
+		},
+		ContextTTL:     contextTTL,
+		PromptTTL:      promptTTL,
+		CommandTimeout: commandTimeout,
+		MaxWatched:     maxWatched,
 		DiscordBot: DiscordBot{
-			Token:   os.Getenv("DISCORD_TOKEN"),
-			GuildID: os.Getenv("DISCORD_GUILD_ID"),
+			Token:          os.Getenv("DISCORD_TOKEN"),
+			MessageContent: discordMessageContent,
+			MessageLimit:   int(discordMessageLimit),
+			ShardCount:     int(shardCount),
+			ShardIDs:       shardIDs,
+			SendRate:       discordSendRate,
 		},
 		GRPC: GRPC{
 			Listen: os.Getenv("GRPC_LISTEN"),
@@ -149,16 +922,128 @@ func Load(filePaths ...string) (*Config, error) {
 			Compress:   compress,
 		},
 		HTTP: HTTP{
-			Listen: os.Getenv("HTTP_LISTEN"),
+			Listen:         os.Getenv("HTTP_LISTEN"),
+			TrustedProxies: trustedProxies,
+		},
+		Queue: Queue{
+			URL:           os.Getenv("QUEUE_URL"),
+			Subject:       os.Getenv("QUEUE_SUBJECT"),
+			Group:         os.Getenv("QUEUE_GROUP"),
+			ResultSubject: os.Getenv("QUEUE_RESULT_SUBJECT"),
+			Workers:       queueWorkers,
 		},
 		Phoenix: PhoenixNetwork{
 			NetworkNodes: strings.Split(os.Getenv("PHOENIX_NETWORK_NODES"), ","),
 			FaucetAmount: uint(faucetAmount),
+			FaucetMemo:   getEnv("PHOENIX_FAUCET_MEMO", DefaultFaucetMemo),
+		},
+		Zealy: Zealy{
+			RewardMemo: getEnv("ZEALY_REWARD_MEMO", DefaultRewardMemo),
+		},
+		FaucetAbuse: FaucetAbuse{
+			MinAccountAge:    minAccountAge,
+			MaxAddressUsers:  maxAddressUsers,
+			MaxUserAddresses: maxUserAddresses,
+			BlockedASNs:      splitNonEmpty(os.Getenv("FAUCET_BLOCKED_ASNS")),
+			ReviewHosting:    reviewHosting,
+		},
+		Challenge: Challenge{
+			TTL:             challengeTTL,
+			HCaptchaSiteKey: os.Getenv("HCAPTCHA_SITE_KEY"),
+			HCaptchaSecret:  os.Getenv("HCAPTCHA_SECRET"),
+		},
+		Cache: Cache{
+			Driver:          os.Getenv("CACHE_DRIVER"),
+			RedisAddress:    os.Getenv("REDIS_ADDRESS"),
+			RedisPassword:   os.Getenv("REDIS_PASSWORD"),
+			RedisDB:         redisDB,
+			TTL:             cacheTTL,
+			RateLimit:       rateLimit,
+			GuildQuota:      guildQuota,
+			RateLimitWindow: rateLimitWindow,
+		},
+		Market: Market{
+			PriceURL:     os.Getenv("MARKET_PRICE_URL"),
+			PollInterval: marketInterval,
+			MaxAlerts:    maxPriceAlerts,
+			Rates:        currencyRates,
+		},
+		Release: Release{
+			URL:          os.Getenv("RELEASE_URL"),
+			Channels:     splitNonEmpty(os.Getenv("RELEASE_CHANNELS")),
+			PollInterval: releaseInterval,
+		},
+		Fork: Fork{
+			CheckBlocks:   forkCheckBlocks,
+			AlertChannels: splitNonEmpty(os.Getenv("FORK_ALERT_CHANNELS")),
+		},
+		Committee: Committee{
+			BatchBlocks: committeeBlocks,
+			Channels:    splitNonEmpty(os.Getenv("COMMITTEE_CHANNELS")),
+		},
+		Accounts: Accounts{
+			MilestoneStep:     accountsStep,
+			MilestoneChannels: splitNonEmpty(os.Getenv("ACCOUNTS_MILESTONE_CHANNELS")),
+			SpikeFactor:       spikeFactor,
+			SpikeMin:          spikeMin,
+			SpikeChannels:     splitNonEmpty(os.Getenv("ACCOUNTS_SPIKE_CHANNELS")),
+		},
+		Report: Report{
+			Weekday:  reportDay,
+			Hour:     reportHour,
+			Channels: splitNonEmpty(os.Getenv("WEEKLY_REPORT_CHANNELS")),
+		},
+		Telemetry: Telemetry{
+			Endpoint:    os.Getenv("TELEMETRY_ENDPOINT"),
+			Insecure:    telemetryInsecure,
+			SampleRatio: sampleRatio,
+		},
+		Concentration: Concentration{
+			TopValidators: topValidators,
+			MaxShare:      maxPowerShare,
+			EpochBlocks:   epochBlocks,
+			AlertChannels: splitNonEmpty(os.Getenv("CONCENTRATION_ALERT_CHANNELS")),
+		},
+		Treasury: Treasury{
+			AlertAmount:   treasuryAlert,
+			AlertChannels: splitNonEmpty(os.Getenv("TREASURY_ALERT_CHANNELS")),
+		},
+		Maintenance: Maintenance{
+			RemindBefore:  remindBefore,
+			AlertChannels: splitNonEmpty(os.Getenv("MAINTENANCE_ALERT_CHANNELS")),
+		},
+		Feedback: Feedback{
+			Channels: splitNonEmpty(os.Getenv("FEEDBACK_CHANNELS")),
+		},
+		Flood: Flood{
+			Messages:      floodMessages,
+			Window:        floodWindow,
+			Mute:          floodMute,
+			AlertChannels: splitNonEmpty(os.Getenv("FLOOD_ALERT_CHANNELS")),
+		},
+		StatusPage: StatusPage{
+			Interval:    statusInterval,
+			Commands:    splitNonEmpty(os.Getenv("STATUS_PAGE_COMMANDS")),
+			Path:        os.Getenv("STATUS_PAGE_PATH"),
+			S3Endpoint:  os.Getenv("STATUS_PAGE_S3_ENDPOINT"),
+			S3Bucket:    os.Getenv("STATUS_PAGE_S3_BUCKET"),
+			S3Prefix:    os.Getenv("STATUS_PAGE_S3_PREFIX"),
+			S3AccessKey: os.Getenv("STATUS_PAGE_S3_ACCESS_KEY"),
+			S3SecretKey: os.Getenv("STATUS_PAGE_S3_SECRET_KEY"),
+		},
+		NLP: NLP{
+			Enable:    nlpEnable,
+			LLMURL:    os.Getenv("NLP_LLM_URL"),
+			LLMAPIKey: os.Getenv("NLP_LLM_API_KEY"),
+			LLMModel:  os.Getenv("NLP_LLM_MODEL"),
 		},
 		Telegram: Telegram{
-			BotToken:  os.Getenv("TELEGRAM_BOT_TOKEN"),
-			ChatID:    chatID,
-			GroupLink: os.Getenv("TELEGRAM_GROUP_LINK"),
+			BotToken:     os.Getenv("TELEGRAM_BOT_TOKEN"),
+			ChatID:       chatID,
+			GroupLink:    os.Getenv("TELEGRAM_GROUP_LINK"),
+			MessageLimit: int(telegramMessageLimit),
+			SendRate:     telegramSendRate,
+			AuthIDs:      splitNonEmpty(os.Getenv("AUTHORIZED_TELEGRAM_IDS")),
 		},
 	}
 
@@ -170,6 +1055,152 @@ func Load(filePaths ...string) (*Config, error) {
 	return cfg, nil
 }
 
+// getEnv returns the value of the environment variable, or the default value if it's not set.
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+// getEnvFloat returns the float value of the environment variable, or the default value if it's not set.
+func getEnvFloat(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// getEnvInt returns the integer value of the environment variable, or the default value if it's not set.
+func getEnvInt(key string, defaultValue int64) (int64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// getEnvBool returns the boolean value of the environment variable, or the default value if it's not set.
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// splitNonEmpty splits the comma separated list and drops the empty items.
+func splitNonEmpty(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// getEnvInts returns the comma separated integers of the environment variable, like: "0,1,2".
+func getEnvInts(key string) ([]int, error) {
+	items := splitNonEmpty(os.Getenv(key))
+	values := make([]int, 0, len(items))
+	for _, item := range items {
+		value, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s has an invalid number: %s", key, item)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// getEnvNets returns the comma separated IPs and networks of the environment variable, like: "10.0.0.0/8,127.0.0.1".
+func getEnvNets(key string) ([]*net.IPNet, error) {
+	items := splitNonEmpty(os.Getenv(key))
+	nets := make([]*net.IPNet, 0, len(items))
+	for _, item := range items {
+		if ip := net.ParseIP(item); ip != nil {
+			bits := 8 * len(ip)
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s has an invalid IP or network: %s", key, item)
+		}
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
+// getEnvDays returns the days of the tables of the environment variable over the default days,
+// like: "price_samples:730,announcements:0".
+func getEnvDays(key string, defaults map[string]int64) (map[string]int64, error) {
+	days := make(map[string]int64, len(defaults))
+	for table, d := range defaults {
+		days[table] = d
+	}
+
+	for _, item := range splitNonEmpty(os.Getenv(key)) {
+		table, value, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("config: %s should be like: price_samples:365, got: %s", key, item)
+		}
+
+		d, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("config: %s has invalid days: %s", key, item)
+		}
+
+		days[strings.TrimSpace(table)] = d
+	}
+
+	return days, nil
+}
+
+// getEnvRates returns the rates of the environment variable, like: "EUR:0.92,IRR:600000".
+func getEnvRates(key string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, item := range splitNonEmpty(os.Getenv(key)) {
+		code, value, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("config: %s should be like: EUR:0.92, got: %s", key, item)
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s has an invalid rate: %s", key, item)
+		}
+
+		rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+
+	return rates, nil
+}
+
+// getEnvDuration returns the duration value of the environment variable, or the default value if it's not set.
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
 // Validate checks for the presence of required environment variables.
 func (cfg *Config) BasicCheck() error {
 	if cfg.Wallet.Enable {
@@ -200,3 +1231,19 @@ func (cfg *Config) BasicCheck() error {
 
 	return nil
 }
+
+// getEnvWeekday parses the day of the week by its English name, like: "Monday" or "friday".
+func getEnvWeekday(key string, defaultValue time.Weekday) (time.Weekday, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(value, day.String()) {
+			return day, nil
+		}
+	}
+
+	return defaultValue, fmt.Errorf("config: %s is not a day of the week: %s", key, value)
+}
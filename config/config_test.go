@@ -27,8 +27,7 @@ func TestBasicCheck(t *testing.T) {
 				},
 				NetworkNodes: []string{"http://127.0.0.1:8545"},
 				DiscordBot: DiscordBot{
-					Token:   "MTEabc123",
-					GuildID: "123456789",
+					Token: "MTEabc123",
 				},
 				Phoenix: PhoenixNetwork{
 					NetworkNodes: []string{""},
@@ -46,8 +45,7 @@ func TestBasicCheck(t *testing.T) {
 				},
 				NetworkNodes: []string{},
 				DiscordBot: DiscordBot{
-					Token:   "MTEabc123",
-					GuildID: "123456789",
+					Token: "MTEabc123",
 				},
 			},
 			wantErr: true,
@@ -69,3 +67,15 @@ func TestBasicCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEnvNets(t *testing.T) {
+	t.Setenv("HTTP_TRUSTED_PROXIES", "10.0.0.0/8, 127.0.0.1,::1")
+	nets, err := getEnvNets("HTTP_TRUSTED_PROXIES")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "127.0.0.1/32", "::1/128"},
+		[]string{nets[0].String(), nets[1].String(), nets[2].String()})
+
+	t.Setenv("HTTP_TRUSTED_PROXIES", "proxy.local")
+	_, err = getEnvNets("HTTP_TRUSTED_PROXIES")
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SetAddressAlias sets the alias of the address, it replaces the previous alias of the address.
+// It returns false if another address has the name, so an alias can't impersonate another validator.
+func (db *DB) SetAddressAlias(a *AddressAlias) (bool, error) {
+	a.Name = strings.TrimSpace(a.Name)
+	taken := false
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&AddressAlias{}).Where("LOWER(name) = ? AND address <> ?", strings.ToLower(a.Name), a.Address).
+			Count(&count).Error; err != nil {
+			return ReadError{
+				Reason: err.Error(),
+			}
+		}
+
+		if count > 0 {
+			taken = true
+
+			return nil
+		}
+
+		if err := tx.Unscoped().Where("address = ?", a.Address).Delete(&AddressAlias{}).Error; err != nil {
+			return WriteError{
+				Reason: err.Error(),
+			}
+		}
+
+		if err := tx.Create(a).Error; err != nil {
+			return WriteError{
+				Reason: err.Error(),
+			}
+		}
+
+		return nil
+	})
+
+	return !taken, err
+}
+
+// GetAddressAlias returns the alias of the address, nil if it has none.
+func (db *DB) GetAddressAlias(address string) (*AddressAlias, error) {
+	var a []*AddressAlias
+	tx := db.Where("address = ?", address).Limit(1).Find(&a)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	if len(a) == 0 {
+		return nil, nil
+	}
+
+	return a[0], nil
+}
+
+// GetAddressAliases returns the names of the addresses that have an alias.
+func (db *DB) GetAddressAliases(addresses []string) (map[string]string, error) {
+	names := make(map[string]string)
+	if len(addresses) == 0 {
+		return names, nil
+	}
+
+	var a []*AddressAlias
+	tx := db.Where("address IN ?", addresses).Find(&a)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	for _, alias := range a {
+		names[alias.Address] = alias.Name
+	}
+
+	return names, nil
+}
+
+// GetUserAddressAliases returns the aliases that the user set, the oldest first.
+func (db *DB) GetUserAddressAliases(appID int, userID string) ([]*AddressAlias, error) {
+	var a []*AddressAlias
+	tx := db.Where("app_id = ? AND user_id = ?", appID, userID).Order("id").Find(&a)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return a, nil
+}
+
+// GetRecentAddressAliases returns the last aliases that are set, the newest first, for the moderators to review.
+func (db *DB) GetRecentAddressAliases(limit int) ([]*AddressAlias, error) {
+	var a []*AddressAlias
+	tx := db.Order("updated_at DESC").Limit(limit).Find(&a)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return a, nil
+}
+
+// DeleteAddressAlias removes the alias of the address, it returns false if the address has none.
+func (db *DB) DeleteAddressAlias(address string) (bool, error) {
+	tx := db.Unscoped().Where("address = ?", address).Delete(&AddressAlias{})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
@@ -0,0 +1,16 @@
+package database
+
+import "gorm.io/gorm/clause"
+
+// ClaimAnnouncement records the announcement, it returns false if it's claimed already,
+// like by another instance.
+func (db *DB) ClaimAnnouncement(key string) (bool, error) {
+	tx := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&Announcement{Key: key})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
@@ -0,0 +1,14 @@
+package database
+
+// Snapshot writes a consistent copy of the database to the path, it's safe to call while the bot is running.
+// The path should not exist.
+func (db *DB) Snapshot(path string) error {
+	tx := db.Exec("VACUUM INTO ?", path)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
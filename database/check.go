@@ -0,0 +1,45 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetStatusCheck keeps the result of the check of the user, it replaces the last one.
+func (db *DB) SetStatusCheck(appID int, userID, name, result string) error {
+	tx := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "app_id"}, {Name: "user_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"result", "updated_at"}),
+	}).Create(&StatusCheck{
+		AppID:  appID,
+		UserID: userID,
+		Name:   name,
+		Result: result,
+	})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetStatusCheck returns the last check of the user, nil if the user has none.
+func (db *DB) GetStatusCheck(appID int, userID, name string) (*StatusCheck, error) {
+	var c StatusCheck
+	tx := db.Where("app_id = ? AND user_id = ? AND name = ?", appID, userID, name).First(&c)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return &c, nil
+}
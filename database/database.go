@@ -21,11 +21,51 @@ func NewDB(path string) (*DB, error) {
 
 	if !db.Migrator().HasTable(&User{}) ||
 		!db.Migrator().HasTable(&Faucet{}) ||
-		!db.Migrator().HasTable(&ZealyUser{}) {
+		!db.Migrator().HasTable(&FaucetReview{}) ||
+		!db.Migrator().HasTable(&ZealyUser{}) ||
+		!db.Migrator().HasTable(&Lock{}) ||
+		!db.Migrator().HasTable(&PriceSample{}) ||
+		!db.Migrator().HasTable(&PriceAlert{}) ||
+		!db.Migrator().HasTable(&NetworkSnapshot{}) ||
+		!db.Migrator().HasTable(&DigestSubscription{}) ||
+		!db.Migrator().HasTable(&WatchedValidator{}) ||
+		!db.Migrator().HasTable(&Announcement{}) ||
+		!db.Migrator().HasTable(&AccountTransaction{}) ||
+		!db.Migrator().HasTable(&FeatureFlag{}) ||
+		!db.Migrator().HasTable(&DeprecatedCall{}) ||
+		!db.Migrator().HasTable(&Setting{}) ||
+		!db.Migrator().HasTable(&Feedback{}) ||
+		!db.Migrator().HasTable(&AddressLink{}) ||
+		!db.Migrator().HasTable(&AvailabilitySample{}) ||
+		!db.Migrator().HasTable(&UserPreference{}) ||
+		!db.Migrator().HasTable(&AddressAlias{}) ||
+		!db.Migrator().HasTable(&StatusCheck{}) ||
+		!db.Migrator().HasTable(&HealthSample{}) ||
+		!db.Migrator().HasColumn(&Faucet{}, "Memo") ||
+		!db.Migrator().HasColumn(&ZealyUser{}, "Memo") {
 		if err := db.AutoMigrate(
 			&User{},
 			&Faucet{},
+			&FaucetReview{},
 			&ZealyUser{},
+			&Lock{},
+			&PriceSample{},
+			&PriceAlert{},
+			&NetworkSnapshot{},
+			&DigestSubscription{},
+			&WatchedValidator{},
+			&Announcement{},
+			&AccountTransaction{},
+			&FeatureFlag{},
+			&DeprecatedCall{},
+			&Setting{},
+			&Feedback{},
+			&AddressLink{},
+			&AvailabilitySample{},
+			&UserPreference{},
+			&AddressAlias{},
+			&StatusCheck{},
+			&HealthSample{},
 		); err != nil {
 			return nil, MigrationError{
 				Reason: err.Error(),
@@ -38,6 +78,33 @@ func NewDB(path string) (*DB, error) {
 	}, nil
 }
 
+// Ping opens the database read-only and checks its connection. Unlike NewDB, it doesn't create or migrate
+// the database, so a check of the config doesn't change it.
+func Ping(path string) error {
+	db, err := gorm.Open(sqlite.Open("file:"+path+"?mode=ro"), &gorm.Config{})
+	if err != nil {
+		return ReadError{
+			Reason: err.Error(),
+		}
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return ReadError{
+			Reason: err.Error(),
+		}
+	}
+	defer sqlDB.Close()
+
+	if err := sqlDB.Ping(); err != nil {
+		return ReadError{
+			Reason: err.Error(),
+		}
+	}
+
+	return nil
+}
+
 func (db *DB) AddUser(u *User) error {
 	tx := db.Create(u)
 	if tx.Error != nil {
@@ -72,6 +139,33 @@ func (db *DB) AddFaucet(f *Faucet) error {
 	return nil
 }
 
+// UpdateFaucetTransaction records the transaction of the faucet claim.
+func (db *DB) UpdateFaucetTransaction(id uint, txHash, memo string) error {
+	tx := db.Model(&Faucet{}).Where("id = ?", id).Updates(map[string]any{
+		"transaction_hash": txHash,
+		"memo":             memo,
+	})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// DeleteFaucet removes the faucet claim, like when its transaction fails, so the user can claim again.
+func (db *DB) DeleteFaucet(id uint) error {
+	tx := db.Unscoped().Delete(&Faucet{}, id)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
 func (db *DB) HasUser(id string) bool {
 	var exists bool
 
@@ -104,6 +198,125 @@ func (db *DB) CanGetFaucet(id string) bool {
 	return true
 }
 
+// CountFaucetUsersByAddress returns the number of users that got faucets on the address, except the user.
+func (db *DB) CountFaucetUsersByAddress(address, exceptUserID string) (int64, error) {
+	var count int64
+	tx := db.Model(&Faucet{}).
+		Where("address = ? AND user_id <> ?", address, exceptUserID).
+		Distinct("user_id").
+		Count(&count)
+	if tx.Error != nil {
+		return 0, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return count, nil
+}
+
+// CountFaucetAddressesByUser returns the number of addresses that the user got faucets on.
+func (db *DB) CountFaucetAddressesByUser(userID string) (int64, error) {
+	var count int64
+	tx := db.Model(&Faucet{}).
+		Where("user_id = ?", userID).
+		Distinct("address").
+		Count(&count)
+	if tx.Error != nil {
+		return 0, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return count, nil
+}
+
+func (db *DB) AddFaucetReview(r *FaucetReview) error {
+	tx := db.Create(r)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) GetFaucetReview(id uint) (*FaucetReview, error) {
+	var r *FaucetReview
+	tx := db.Model(&FaucetReview{}).First(&r, id)
+	if tx.Error != nil {
+		return &FaucetReview{}, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return r, nil
+}
+
+func (db *DB) GetPendingFaucetReviews() ([]*FaucetReview, error) {
+	var r []*FaucetReview
+	tx := db.Where("status = ?", FaucetReviewPending).Order("created_at").Find(&r)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return r, nil
+}
+
+// GetFaucetsByUser returns the faucet claims of the user, the newest first.
+func (db *DB) GetFaucetsByUser(userID string) ([]*Faucet, error) {
+	var f []*Faucet
+	tx := db.Where("user_id = ?", userID).Order("created_at DESC").Find(&f)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return f, nil
+}
+
+// GetFaucetReviewsByUser returns the reviews of the suspicious faucet claims of the user, the newest first.
+func (db *DB) GetFaucetReviewsByUser(userID string) ([]*FaucetReview, error) {
+	var r []*FaucetReview
+	tx := db.Where("user_id = ?", userID).Order("created_at DESC").Find(&r)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return r, nil
+}
+
+func (db *DB) HasPendingFaucetReview(userID string) bool {
+	var exists bool
+
+	_ = db.Model(&FaucetReview{}).
+		Select("count(*) > 0").
+		Where("user_id = ? AND status = ?", userID, FaucetReviewPending).
+		Find(&exists).
+		Error
+
+	return exists
+}
+
+func (db *DB) UpdateFaucetReviewStatus(id uint, status FaucetReviewStatus, reviewer string) error {
+	tx := db.Model(&FaucetReview{}).Where("id = ?", id).Updates(map[string]any{
+		"status":   status,
+		"reviewer": reviewer,
+	})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
 //! Zealy Database
 
 func (db *DB) GetZealyUser(id string) (*ZealyUser, error) {
@@ -118,6 +331,18 @@ func (db *DB) GetZealyUser(id string) (*ZealyUser, error) {
 	return u, nil
 }
 
+func (db *DB) HasZealyUser(id string) bool {
+	var exists bool
+
+	_ = db.Model(&ZealyUser{}).
+		Select("count(*) > 0").
+		Where("discord_id = ?", id).
+		Find(&exists).
+		Error
+
+	return exists
+}
+
 func (db *DB) AddZealyUser(u *ZealyUser) error {
 	tx := db.Create(u)
 	if tx.Error != nil {
@@ -129,10 +354,13 @@ func (db *DB) AddZealyUser(u *ZealyUser) error {
 	return nil
 }
 
-func (db *DB) UpdateZealyUser(id string, txHash string) error {
+func (db *DB) UpdateZealyUser(id, txHash, memo string) error {
 	tx := db.Model(&ZealyUser{
 		DiscordID: id,
-	}).Where("discord_id = ?", id).Update("tx_hash", txHash)
+	}).Where("discord_id = ?", id).Updates(map[string]any{
+		"tx_hash": txHash,
+		"memo":    memo,
+	})
 	if tx.Error != nil {
 		return WriteError{
 			Reason: tx.Error.Error(),
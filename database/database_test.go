@@ -3,7 +3,9 @@ package database
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,6 +21,16 @@ func setup(t *testing.T) *DB {
 	return db
 }
 
+func TestPing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pagu.db")
+	require.Error(t, Ping(path))
+	assert.NoFileExists(t, path, "the missing database is not created")
+
+	_, err := NewDB(path)
+	require.NoError(t, err)
+	assert.NoError(t, Ping(path))
+}
+
 func TestUserAndFaucet(t *testing.T) {
 	db := setup(t)
 
@@ -34,16 +46,34 @@ func TestUserAndFaucet(t *testing.T) {
 	r := db.CanGetFaucet("123456789")
 	assert.True(t, r)
 
-	err = db.AddFaucet(&Faucet{
+	f := &Faucet{
 		Address: "tpc1zlymfcuxlgvvuud2q4zw0scllqn74d2f90hld6w",
 		Amount:  5,
 		UserID:  "123456789",
-	})
+	}
+	err = db.AddFaucet(f)
 	assert.NoError(t, err)
 
 	r = db.CanGetFaucet("123456789")
 	assert.False(t, r)
 
+	err = db.DeleteFaucet(f.ID)
+	assert.NoError(t, err)
+	assert.True(t, db.CanGetFaucet("123456789"))
+
+	f.ID = 0
+	err = db.AddFaucet(f)
+	assert.NoError(t, err)
+
+	err = db.UpdateFaucetTransaction(f.ID, "0x123456789", "Pagu faucet 2")
+	assert.NoError(t, err)
+
+	u, err = db.GetUser("123456789")
+	assert.NoError(t, err)
+	require.Len(t, u.Faucets, 1)
+	assert.Equal(t, "0x123456789", u.Faucets[0].TransactionHash)
+	assert.Equal(t, "Pagu faucet 2", u.Faucets[0].Memo)
+
 	u, err = db.GetUser("not-exist")
 	fmt.Println(u.ID)
 	assert.Error(t, err)
@@ -64,12 +94,13 @@ func TestZealyDB(t *testing.T) {
 	assert.Equal(t, "", uz.TxHash)
 	assert.Equal(t, int64(100), uz.Amount)
 
-	err = db.UpdateZealyUser("12345678", "0x123456789")
+	err = db.UpdateZealyUser("12345678", "0x123456789", "Pagu Zealy reward 1")
 	assert.NoError(t, err)
 
 	uz, err = db.GetZealyUser("12345678")
 	assert.NoError(t, err)
 	assert.Equal(t, "0x123456789", uz.TxHash)
+	assert.Equal(t, "Pagu Zealy reward 1", uz.Memo)
 	assert.Equal(t, int64(100), uz.Amount)
 
 	_, err = db.GetZealyUser("87654321")
@@ -79,3 +110,607 @@ func TestZealyDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(azu))
 }
+
+func TestFaucetReview(t *testing.T) {
+	db := setup(t)
+
+	for _, f := range []*Faucet{
+		{Address: "tpc1zaddr1", Amount: 5, UserID: "user-1"},
+		{Address: "tpc1zaddr1", Amount: 5, UserID: "user-2"},
+		{Address: "tpc1zaddr2", Amount: 5, UserID: "user-1"},
+	} {
+		assert.NoError(t, db.AddFaucet(f))
+	}
+
+	users, err := db.CountFaucetUsersByAddress("tpc1zaddr1", "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), users)
+
+	addresses, err := db.CountFaucetAddressesByUser("user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), addresses)
+
+	err = db.AddFaucetReview(&FaucetReview{
+		UserID:  "user-3",
+		Address: "tpc1zaddr1",
+		Reasons: "address is claimed by 2 other users",
+		Status:  FaucetReviewPending,
+	})
+	assert.NoError(t, err)
+	assert.True(t, db.HasPendingFaucetReview("user-3"))
+
+	reviews, err := db.GetPendingFaucetReviews()
+	assert.NoError(t, err)
+	require.Len(t, reviews, 1)
+
+	err = db.UpdateFaucetReviewStatus(reviews[0].ID, FaucetReviewRejected, "admin")
+	assert.NoError(t, err)
+	assert.False(t, db.HasPendingFaucetReview("user-3"))
+
+	r, err := db.GetFaucetReview(reviews[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, FaucetReviewRejected, r.Status)
+	assert.Equal(t, "admin", r.Reviewer)
+
+	_, err = db.GetFaucetReview(1000)
+	assert.Error(t, err)
+}
+
+func TestSnapshot(t *testing.T) {
+	db := setup(t)
+
+	err := db.AddUser(&User{
+		ID: "123456789",
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	require.NoError(t, db.Snapshot(path))
+	assert.Error(t, db.Snapshot(path), "the backup file exists")
+
+	restored, err := NewDB(path)
+	require.NoError(t, err)
+	assert.True(t, restored.HasUser("123456789"))
+}
+
+func TestLock(t *testing.T) {
+	db := setup(t)
+
+	ok, err := db.AcquireLock("backup", "pagu-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.AcquireLock("backup", "pagu-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "held by another owner")
+
+	ok, err = db.AcquireLock("backup", "pagu-1", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "not reentrant")
+
+	ok, err = db.RenewLock("backup", "pagu-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "renewed by the owner")
+
+	ok, err = db.RenewLock("backup", "pagu-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "only the owner can renew")
+
+	require.NoError(t, db.ReleaseLock("backup", "pagu-2"))
+	ok, err = db.AcquireLock("backup", "pagu-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "only the owner can release")
+
+	require.NoError(t, db.ReleaseLock("backup", "pagu-1"))
+	ok, err = db.AcquireLock("backup", "pagu-2", -time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.AcquireLock("backup", "pagu-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "the lock of pagu-2 is expired")
+}
+
+func TestPriceSamples(t *testing.T) {
+	db := setup(t)
+
+	hour := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, db.AddPriceSample(&PriceSample{Hour: hour, Price: 0.1}))
+	require.NoError(t, db.AddPriceSample(&PriceSample{Hour: hour, Price: 0.2}), "the hour has a sample")
+	require.NoError(t, db.AddPriceSample(&PriceSample{Hour: hour.Add(time.Hour), Price: 0.3}))
+
+	samples, err := db.GetPriceSamples(hour)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, 0.1, samples[0].Price)
+	assert.Equal(t, 0.3, samples[1].Price)
+
+	samples, err = db.GetPriceSamples(hour.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, samples, 1)
+}
+
+func TestPruneTable(t *testing.T) {
+	db := setup(t)
+
+	hour := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, db.AddPriceSample(&PriceSample{Hour: hour, Price: 0.1}))
+	require.NoError(t, db.AddPriceSample(&PriceSample{Hour: hour.Add(time.Hour), Price: 0.2}))
+	require.NoError(t, db.AddAvailabilitySample(&AvailabilitySample{Address: "pc1p-val", Hour: hour}))
+	require.NoError(t, db.Create(&Announcement{Key: "release:v1.0.0", CreatedAt: hour}).Error)
+
+	pruned, err := db.PruneTable(TablePriceSamples, hour.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	samples, err := db.GetPriceSamples(hour)
+	require.NoError(t, err)
+	require.Len(t, samples, 1, "the samples since the time are kept")
+	assert.Equal(t, 0.2, samples[0].Price)
+
+	pruned, err = db.PruneTable(TableAvailabilitySamples, hour)
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+
+	pruned, err = db.PruneTable(TableAnnouncements, hour.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	_, err = db.PruneTable("users", hour)
+	assert.Error(t, err, "the users are not pruned")
+	assert.Equal(t, []string{TableAnnouncements, TableAvailabilitySamples, TablePriceSamples}, RetentionTables())
+}
+
+func TestPriceAlerts(t *testing.T) {
+	db := setup(t)
+
+	above := &PriceAlert{AppID: 1, UserID: "123", Direction: PriceAlertAbove, Price: 0.5}
+	below := &PriceAlert{AppID: 1, UserID: "123", Direction: PriceAlertBelow, Price: 0.1}
+	require.NoError(t, db.AddPriceAlert(above))
+	require.NoError(t, db.AddPriceAlert(below))
+	require.NoError(t, db.AddPriceAlert(&PriceAlert{AppID: 2, UserID: "123", Direction: PriceAlertAbove, Price: 1}))
+
+	assert.True(t, above.Crossed(0.5))
+	assert.False(t, above.Crossed(0.4))
+	assert.True(t, below.Crossed(0.05))
+	assert.False(t, below.Crossed(0.2))
+
+	alerts, err := db.GetUserPriceAlerts(1, "123")
+	require.NoError(t, err)
+	assert.Len(t, alerts, 2)
+
+	ok, err := db.TriggerPriceAlert(above.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.TriggerPriceAlert(above.ID)
+	require.NoError(t, err)
+	assert.False(t, ok, "triggered already")
+
+	ok, err = db.DeletePriceAlert(below.ID, 1, "456")
+	require.NoError(t, err)
+	assert.False(t, ok, "alert of another user")
+
+	ok, err = db.DeletePriceAlert(below.ID, 1, "123")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	alerts, err = db.GetActivePriceAlerts()
+	require.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, 2, alerts[0].AppID)
+}
+
+func TestNetworkSnapshots(t *testing.T) {
+	db := setup(t)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	s, err := db.GetNetworkSnapshot(day)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	require.NoError(t, db.AddNetworkSnapshot(&NetworkSnapshot{Day: day, Validators: 10}))
+	require.NoError(t, db.AddNetworkSnapshot(&NetworkSnapshot{Day: day, Validators: 11}), "the day has a snapshot")
+	require.NoError(t, db.AddNetworkSnapshot(&NetworkSnapshot{Day: day.AddDate(0, 0, 2), Validators: 12}))
+
+	s, err = db.GetNetworkSnapshot(day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), s.Validators)
+
+	s, err = db.GetNetworkSnapshot(day.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	assert.Equal(t, int32(12), s.Validators)
+}
+
+func TestDigestSubscriptions(t *testing.T) {
+	db := setup(t)
+
+	s, err := db.GetDigestSubscription(1, "123")
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	require.NoError(t, db.SetDigestSubscription(&DigestSubscription{AppID: 1, UserID: "123", Hour: 8, Timezone: "UTC"}))
+	require.NoError(t, db.SetDigestSubscription(&DigestSubscription{AppID: 1, UserID: "123", Hour: 9, Timezone: "Asia/Tokyo"}))
+
+	subs, err := db.GetDigestSubscriptions()
+	require.NoError(t, err)
+	require.Len(t, subs, 1, "the subscription is updated")
+	assert.Equal(t, 9, subs[0].Hour)
+	assert.Equal(t, "Asia/Tokyo", subs[0].Timezone)
+
+	ok, err := db.ClaimDigest(subs[0].ID, time.Now().Add(-time.Hour), 1000, 50)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.ClaimDigest(subs[0].ID, time.Now().Add(-time.Hour), 1001, 51)
+	require.NoError(t, err)
+	assert.False(t, ok, "sent already")
+
+	s, err = db.GetDigestSubscription(1, "123")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1000), s.LastHeight)
+	assert.NotNil(t, s.LastSentAt)
+
+	ok, err = db.DeleteDigestSubscription(1, "123")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, db.SetDigestSubscription(&DigestSubscription{AppID: 1, UserID: "123", Hour: 7, Timezone: "UTC"}),
+		"subscribe again")
+}
+
+func TestWatchedValidators(t *testing.T) {
+	db := setup(t)
+
+	require.NoError(t, db.AddWatchedValidator(&WatchedValidator{AppID: 1, UserID: "123", Address: "pc1p1"}))
+	require.NoError(t, db.AddWatchedValidator(&WatchedValidator{AppID: 1, UserID: "123", Address: "pc1p1"}))
+	require.NoError(t, db.AddWatchedValidator(&WatchedValidator{AppID: 1, UserID: "123", Address: "pc1p2"}))
+
+	w, err := db.GetWatchedValidators(1, "123")
+	require.NoError(t, err)
+	assert.Len(t, w, 2)
+
+	ok, err := db.DeleteWatchedValidator(1, "123", "pc1p1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.DeleteWatchedValidator(1, "123", "pc1p1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, db.AddWatchedValidator(&WatchedValidator{AppID: 1, UserID: "123", Address: "pc1p1"}), "watch again")
+}
+
+func TestClaimAnnouncement(t *testing.T) {
+	db := setup(t)
+
+	ok, err := db.ClaimAnnouncement("release:v1.2.0:Discord:1234")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.ClaimAnnouncement("release:v1.2.0:Discord:1234")
+	require.NoError(t, err)
+	assert.False(t, ok, "announced already")
+
+	ok, err = db.ClaimAnnouncement("release:v1.2.0:Telegram:-1001")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestAccountTransactions(t *testing.T) {
+	db := setup(t)
+
+	height, err := db.GetLastIndexedHeight()
+	require.NoError(t, err)
+	assert.Zero(t, height)
+
+	require.NoError(t, db.AddAccountTransactions([]*AccountTransaction{
+		{TxID: "tx1", Address: "pc1z1", Height: 10, Direction: TxOutgoing, Counterparty: "pc1z2", Amount: 5},
+		{TxID: "tx1", Address: "pc1z2", Height: 10, Direction: TxIncoming, Counterparty: "pc1z1", Amount: 5},
+	}))
+	require.NoError(t, db.AddAccountTransactions([]*AccountTransaction{
+		{TxID: "tx1", Address: "pc1z1", Height: 10, Direction: TxOutgoing, Counterparty: "pc1z2", Amount: 5},
+		{TxID: "tx2", Address: "pc1z1", Height: 12, Direction: TxIncoming, Counterparty: "pc1z3", Amount: 7},
+	}), "the indexed transactions are ignored")
+	require.NoError(t, db.AddAccountTransactions(nil))
+
+	height, err = db.GetLastIndexedHeight()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(12), height)
+
+	txs, err := db.GetAccountTransactions("pc1z1", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, "tx2", txs[0].TxID, "the newest first")
+
+	txs, err = db.GetAccountTransactions("pc1z1", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, "tx1", txs[0].TxID)
+
+	require.NoError(t, db.AddAccountTransactions([]*AccountTransaction{
+		{TxID: "tx3", Address: "pc1p1", Height: 13, Type: "bond", Direction: TxIncoming, Amount: 9},
+		{TxID: "tx4", Address: "pc1p1", Height: 14, Type: "transfer", Direction: TxIncoming, Amount: 1},
+		{TxID: "tx5", Address: "pc1p1", Height: 15, Type: "unbond", Direction: TxOutgoing},
+	}))
+
+	txs, err = db.GetBondTransactions("pc1p1", 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2, "the transfers are not bonds")
+	assert.Equal(t, "tx5", txs[0].TxID)
+
+	txs, err = db.GetOutgoingTransactions([]string{"pc1z1", "pc1p1"}, 10, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 1, "after the height")
+	assert.Equal(t, "tx5", txs[0].TxID)
+
+	txs, err = db.GetOutgoingTransactions([]string{"pc1z1", "pc1p1"}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, "tx1", txs[1].TxID)
+
+	txs, err = db.GetNextOutgoingTransactions([]string{"pc1z1", "pc1p1"}, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, txs, 1, "the oldest first")
+	assert.Equal(t, "tx1", txs[0].TxID)
+}
+
+func TestWeeklyStats(t *testing.T) {
+	db := setup(t)
+
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	stats, err := db.GetBlockStats(since)
+	require.NoError(t, err)
+	assert.Nil(t, stats)
+
+	sent := func(id string, height uint32, at time.Duration, typ string, amt int64) *AccountTransaction {
+		return &AccountTransaction{
+			TxID:      id,
+			Address:   "pc1z1",
+			Height:    height,
+			BlockTime: since.Add(at),
+			Type:      typ,
+			Direction: TxOutgoing,
+			Amount:    amt,
+		}
+	}
+	received := sent("tx2", 20, 100*time.Second, "transfer", 70)
+	received.Direction = TxIncoming
+
+	require.NoError(t, db.AddAccountTransactions([]*AccountTransaction{
+		sent("tx0", 9, -time.Hour, "transfer", 900),
+		sent("tx1", 10, 0, "transfer", 5),
+		received,
+		sent("tx3", 30, 200*time.Second, "bond", 80),
+	}))
+
+	stats, err = db.GetBlockStats(since)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), stats.FirstHeight)
+	assert.Equal(t, uint32(30), stats.LastHeight)
+	assert.Equal(t, 200*time.Second, stats.LastTime.Sub(stats.FirstTime))
+
+	biggest, err := db.GetBiggestTransfer(since)
+	require.NoError(t, err)
+	assert.Equal(t, "tx1", biggest.TxID, "the sent transfers since the time")
+
+	biggest, err = db.GetBiggestTransfer(since.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, biggest)
+}
+
+func TestFeatureFlags(t *testing.T) {
+	db := setup(t)
+
+	require.NoError(t, db.SetFeatureFlag(&FeatureFlag{Name: "network.map", Enabled: false, UpdatedBy: "123"}))
+	require.NoError(t, db.SetFeatureFlag(&FeatureFlag{Name: "blockchain", Enabled: false, UpdatedBy: "123"}))
+	require.NoError(t, db.SetFeatureFlag(&FeatureFlag{Name: "network.map", Enabled: true, UpdatedBy: "456"}))
+
+	flags, err := db.GetFeatureFlags()
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "blockchain", flags[0].Name)
+	assert.Equal(t, "network.map", flags[1].Name)
+	assert.True(t, flags[1].Enabled)
+	assert.Equal(t, "456", flags[1].UpdatedBy)
+
+	ok, err := db.DeleteFeatureFlag("network.map")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.DeleteFeatureFlag("network.map")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestDeprecatedCalls(t *testing.T) {
+	db := setup(t)
+
+	usage, err := db.GetDeprecatedCalls()
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	now := time.Now()
+	require.NoError(t, db.AddDeprecatedCall("network old-status", now))
+	require.NoError(t, db.AddDeprecatedCall("network old-status", now.Add(time.Minute)))
+	require.NoError(t, db.AddDeprecatedCall("blockchain old-info", now))
+
+	usage, err = db.GetDeprecatedCalls()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"network old-status": 2, "blockchain old-info": 1}, usage)
+}
+
+func TestSettings(t *testing.T) {
+	db := setup(t)
+
+	require.NoError(t, db.SetSetting(&Setting{Name: "prefix.discord", Value: "!pagu", UpdatedBy: "123"}))
+	require.NoError(t, db.SetSetting(&Setting{Name: "prefix.discord.1234", Value: ".p", UpdatedBy: "123"}))
+	require.NoError(t, db.SetSetting(&Setting{Name: "prefix.discord", Value: "!p", UpdatedBy: "456"}))
+
+	settings, err := db.GetSettings()
+	require.NoError(t, err)
+	require.Len(t, settings, 2)
+	assert.Equal(t, "prefix.discord", settings[0].Name)
+	assert.Equal(t, "!p", settings[0].Value)
+	assert.Equal(t, "456", settings[0].UpdatedBy)
+
+	ok, err := db.DeleteSetting("prefix.discord")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.DeleteSetting("prefix.discord")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFeedbacks(t *testing.T) {
+	db := setup(t)
+
+	first := &Feedback{AppID: 2, UserID: "123", Text: "Add the validator rank"}
+	require.NoError(t, db.AddFeedback(first))
+	require.NoError(t, db.AddFeedback(&Feedback{AppID: 5, UserID: "456", Text: "The price is late"}))
+
+	feedbacks, err := db.GetOpenFeedbacks(10)
+	require.NoError(t, err)
+	require.Len(t, feedbacks, 2)
+	assert.Equal(t, "Add the validator rank", feedbacks[0].Text)
+
+	ok, err := db.ResolveFeedback(first.ID, "admin-id")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.ResolveFeedback(first.ID, "admin-id")
+	require.NoError(t, err)
+	assert.False(t, ok, "resolved already")
+
+	feedbacks, err = db.GetOpenFeedbacks(10)
+	require.NoError(t, err)
+	require.Len(t, feedbacks, 1)
+	assert.Equal(t, "456", feedbacks[0].UserID)
+}
+
+func TestUserPreference(t *testing.T) {
+	db := setup(t)
+
+	p, err := db.GetUserPreference(2, "123")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	require.NoError(t, db.SetUserCurrency(2, "123", "EUR"))
+	require.NoError(t, db.SetUserCurrency(2, "123", "IRR"), "the currency is updated")
+	require.NoError(t, db.SetUserCurrency(5, "123", "USD"))
+
+	p, err = db.GetUserPreference(2, "123")
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	assert.Equal(t, "IRR", p.Currency)
+	assert.False(t, p.Plain)
+
+	require.NoError(t, db.SetUserPlainText(2, "123", true))
+	require.NoError(t, db.SetUserPlainText(2, "456", true))
+
+	p, err = db.GetUserPreference(2, "123")
+	require.NoError(t, err)
+	assert.True(t, p.Plain)
+	assert.Equal(t, "IRR", p.Currency, "the currency is kept")
+
+	removed, err := db.ForgetUser(2, "123")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+
+	p, err = db.GetUserPreference(5, "123")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", p.Currency, "the preferences are per platform")
+}
+
+func TestHealthSamples(t *testing.T) {
+	db := setup(t)
+
+	minute := time.Date(2024, 5, 20, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		at := minute.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, db.AddHealthSample(&HealthSample{Minute: at, Height: uint32(100 + i), Lag: 5}))
+	}
+	require.NoError(t, db.AddHealthSample(&HealthSample{Minute: minute, Height: 1}), "the minute has a sample")
+
+	samples, err := db.GetHealthSamples(minute.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, uint32(101), samples[0].Height)
+
+	require.NoError(t, db.DeleteHealthSamples(minute.Add(2*time.Minute)))
+	samples, err = db.GetHealthSamples(minute)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, uint32(102), samples[0].Height)
+}
+
+func TestStatusCheck(t *testing.T) {
+	db := setup(t)
+
+	c, err := db.GetStatusCheck(2, "123", "network status")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	require.NoError(t, db.SetStatusCheck(2, "123", "network status", `{"Height":1}`))
+	require.NoError(t, db.SetStatusCheck(2, "123", "network status", `{"Height":2}`), "the last check is replaced")
+	require.NoError(t, db.SetStatusCheck(2, "456", "network status", `{"Height":3}`))
+
+	c, err = db.GetStatusCheck(2, "123", "network status")
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	assert.Equal(t, `{"Height":2}`, c.Result)
+
+	removed, err := db.ForgetUser(2, "123")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+
+	c, err = db.GetStatusCheck(2, "456", "network status")
+	require.NoError(t, err)
+	assert.Equal(t, `{"Height":3}`, c.Result, "the checks are per user")
+}
+
+func TestAddressAliases(t *testing.T) {
+	db := setup(t)
+
+	set, err := db.SetAddressAlias(&AddressAlias{Address: "pc1pa", Name: " Pactus Lovers ", AppID: 2, UserID: "123"})
+	require.NoError(t, err)
+	assert.True(t, set)
+
+	set, err = db.SetAddressAlias(&AddressAlias{Address: "pc1pb", Name: "pactus lovers", AppID: 2, UserID: "456"})
+	require.NoError(t, err)
+	assert.False(t, set, "another address has the name")
+
+	set, err = db.SetAddressAlias(&AddressAlias{Address: "pc1pa", Name: "Pactus Fans", AppID: 2, UserID: "123"})
+	require.NoError(t, err)
+	assert.True(t, set, "the alias of the address is replaced")
+
+	set, err = db.SetAddressAlias(&AddressAlias{Address: "pc1pb", Name: "Pactus Lovers", AppID: 2, UserID: "456"})
+	require.NoError(t, err)
+	assert.True(t, set, "the old name is free")
+
+	names, err := db.GetAddressAliases([]string{"pc1pa", "pc1pb", "pc1pc"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pc1pa": "Pactus Fans", "pc1pb": "Pactus Lovers"}, names)
+
+	recent, err := db.GetRecentAddressAliases(1)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, "pc1pb", recent[0].Address)
+
+	removed, err := db.ForgetUser(2, "456")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+
+	a, err := db.GetAddressAlias("pc1pb")
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	deleted, err := db.DeleteAddressAlias("pc1pa")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = db.DeleteAddressAlias("pc1pa")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
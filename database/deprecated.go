@@ -0,0 +1,44 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddDeprecatedCall counts a call to the deprecated command.
+func (db *DB) AddDeprecatedCall(name string, at time.Time) error {
+	tx := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"calls":        gorm.Expr("calls + 1"),
+			"last_call_at": at,
+		}),
+	}).Create(&DeprecatedCall{Name: name, Calls: 1, LastCallAt: at})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetDeprecatedCalls returns the number of the calls to each deprecated command.
+func (db *DB) GetDeprecatedCalls() (map[string]int64, error) {
+	var calls []*DeprecatedCall
+	tx := db.Find(&calls)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	usage := make(map[string]int64, len(calls))
+	for _, c := range calls {
+		usage[c.Name] = c.Calls
+	}
+
+	return usage, nil
+}
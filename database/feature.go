@@ -0,0 +1,42 @@
+package database
+
+import "gorm.io/gorm/clause"
+
+// SetFeatureFlag enables or disables the feature, or updates the flag if it's set already.
+func (db *DB) SetFeatureFlag(f *FeatureFlag) error {
+	tx := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_by", "updated_at"}),
+	}).Create(f)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) GetFeatureFlags() ([]*FeatureFlag, error) {
+	var f []*FeatureFlag
+	tx := db.Order("name").Find(&f)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return f, nil
+}
+
+// DeleteFeatureFlag removes the flag so the feature has its default state, it returns false if it's not set.
+func (db *DB) DeleteFeatureFlag(name string) (bool, error) {
+	tx := db.Where("name = ?", name).Delete(&FeatureFlag{})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
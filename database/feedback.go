@@ -0,0 +1,42 @@
+package database
+
+import "time"
+
+func (db *DB) AddFeedback(f *Feedback) error {
+	tx := db.Create(f)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetOpenFeedbacks returns the feedbacks that are not resolved yet, the oldest first.
+func (db *DB) GetOpenFeedbacks(limit int) ([]*Feedback, error) {
+	var f []*Feedback
+	tx := db.Where("resolved_at IS NULL").Order("id").Limit(limit).Find(&f)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return f, nil
+}
+
+// ResolveFeedback resolves the open feedback, it returns false if there is no open feedback with the ID.
+func (db *DB) ResolveFeedback(id uint, resolvedBy string) (bool, error) {
+	tx := db.Model(&Feedback{}).Where("id = ? AND resolved_at IS NULL", id).Updates(map[string]any{
+		"resolved_by": resolvedBy,
+		"resolved_at": time.Now(),
+	})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// AddHealthSample adds the sample of the minute, it's ignored if the minute has a sample already.
+func (db *DB) AddHealthSample(s *HealthSample) error {
+	tx := db.Clauses(clause.OnConflict{DoNothing: true}).Create(s)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetHealthSamples returns the samples since the time, the oldest first.
+func (db *DB) GetHealthSamples(since time.Time) ([]*HealthSample, error) {
+	var s []*HealthSample
+	tx := db.Where("minute >= ?", since).Order("minute").Find(&s)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return s, nil
+}
+
+// DeleteHealthSamples removes the samples before the time, the timeline only needs the last day.
+func (db *DB) DeleteHealthSamples(before time.Time) error {
+	tx := db.Where("minute < ?", before).Delete(&HealthSample{})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
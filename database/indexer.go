@@ -0,0 +1,177 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddNetworkSnapshot adds the snapshot of the day, it's ignored if the day has a snapshot already.
+func (db *DB) AddNetworkSnapshot(s *NetworkSnapshot) error {
+	tx := db.Clauses(clause.OnConflict{DoNothing: true}).Create(s)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetNetworkSnapshot returns the last snapshot on or before the time, nil if there is none.
+func (db *DB) GetNetworkSnapshot(at time.Time) (*NetworkSnapshot, error) {
+	var s *NetworkSnapshot
+	tx := db.Where("day <= ?", at).Order("day DESC").First(&s)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return s, nil
+}
+
+// AddAccountTransactions adds the transactions of a block, the indexed ones are ignored.
+func (db *DB) AddAccountTransactions(txs []*AccountTransaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	tx := db.Clauses(clause.OnConflict{DoNothing: true}).Create(txs)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetLastIndexedHeight returns the height of the last indexed transaction, zero if there is none.
+func (db *DB) GetLastIndexedHeight() (uint32, error) {
+	var height uint32
+	tx := db.Model(&AccountTransaction{}).Select("COALESCE(MAX(height), 0)").Scan(&height)
+	if tx.Error != nil {
+		return 0, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return height, nil
+}
+
+// GetAccountTransactions returns the transactions of the address, the newest first.
+func (db *DB) GetAccountTransactions(address string, offset, limit int) ([]*AccountTransaction, error) {
+	var txs []*AccountTransaction
+	tx := db.Where("address = ?", address).Order("height DESC").Offset(offset).Limit(limit).Find(&txs)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return txs, nil
+}
+
+// GetBondTransactions returns the bonds, the unbonds and the withdraws of the validator, the newest first.
+func (db *DB) GetBondTransactions(address string, limit int) ([]*AccountTransaction, error) {
+	var txs []*AccountTransaction
+	tx := db.Where("address = ? AND type IN ?", address, []string{"bond", "unbond", "withdraw"}).
+		Order("height DESC").Limit(limit).Find(&txs)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return txs, nil
+}
+
+// GetOutgoingTransactions returns the outgoing transactions of the addresses after the height, the newest first.
+func (db *DB) GetOutgoingTransactions(addresses []string, afterHeight uint32, limit int) ([]*AccountTransaction, error) {
+	var txs []*AccountTransaction
+	tx := db.Where("address IN ? AND direction = ? AND height > ?", addresses, TxOutgoing, afterHeight).
+		Order("height DESC").Limit(limit).Find(&txs)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return txs, nil
+}
+
+// GetNextOutgoingTransactions returns the outgoing transactions of the addresses after the height, the oldest first,
+// so they are read forward in pages.
+func (db *DB) GetNextOutgoingTransactions(addresses []string, afterHeight uint32, limit int,
+) ([]*AccountTransaction, error) {
+	var txs []*AccountTransaction
+	tx := db.Where("address IN ? AND direction = ? AND height > ?", addresses, TxOutgoing, afterHeight).
+		Order("height, tx_id").Limit(limit).Find(&txs)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return txs, nil
+}
+
+// BlockStats is the first and the last indexed blocks in a period.
+type BlockStats struct {
+	FirstHeight uint32
+	LastHeight  uint32
+	FirstTime   time.Time
+	LastTime    time.Time
+}
+
+// GetBlockStats returns the first and the last indexed blocks since the time, nil if there is none.
+// Every indexed block has a row, the reward transaction of its proposer.
+func (db *DB) GetBlockStats(since time.Time) (*BlockStats, error) {
+	var first, last AccountTransaction
+	tx := db.Where("block_time >= ?", since).Order("height").First(&first)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if tx.Error == nil {
+		tx = db.Where("block_time >= ?", since).Order("height DESC").First(&last)
+	}
+
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return &BlockStats{
+		FirstHeight: first.Height,
+		LastHeight:  last.Height,
+		FirstTime:   first.BlockTime,
+		LastTime:    last.BlockTime,
+	}, nil
+}
+
+// GetBiggestTransfer returns the sent transfer with the biggest amount since the time, nil if there is none.
+func (db *DB) GetBiggestTransfer(since time.Time) (*AccountTransaction, error) {
+	var t *AccountTransaction
+	tx := db.Where("block_time >= ? AND type = ? AND direction = ?", since, "transfer", TxOutgoing).
+		Order("amount DESC").First(&t)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return t, nil
+}
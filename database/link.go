@@ -0,0 +1,116 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AddPendingAddressLink adds the pending link of the user, it replaces the previous pending link of the user.
+func (db *DB) AddPendingAddressLink(l *AddressLink) error {
+	tx := db.Unscoped().Where("app_id = ? AND user_id = ? AND verified_at IS NULL", l.AppID, l.UserID).
+		Delete(&AddressLink{})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	tx = db.Create(l)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetPendingAddressLink returns the pending link of the user that is not expired, nil if there is none.
+func (db *DB) GetPendingAddressLink(appID int, userID string) (*AddressLink, error) {
+	var l []*AddressLink
+	tx := db.Where("app_id = ? AND user_id = ? AND verified_at IS NULL AND expires_at > ?", appID, userID, time.Now()).
+		Order("id DESC").Limit(1).Find(&l)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	if len(l) == 0 {
+		return nil, nil
+	}
+
+	return l[0], nil
+}
+
+// VerifyAddressLink verifies the pending link. The address is only linked to one user,
+// so the links of the address to the other users are removed.
+func (db *DB) VerifyAddressLink(id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var l AddressLink
+		if err := tx.First(&l, id).Error; err != nil {
+			return ReadError{
+				Reason: err.Error(),
+			}
+		}
+
+		if err := tx.Unscoped().Where("address = ? AND id <> ? AND verified_at IS NOT NULL", l.Address, id).
+			Delete(&AddressLink{}).Error; err != nil {
+			return WriteError{
+				Reason: err.Error(),
+			}
+		}
+
+		if err := tx.Model(&l).Update("verified_at", time.Now()).Error; err != nil {
+			return WriteError{
+				Reason: err.Error(),
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetAddressLinks returns the verified addresses of the user, the oldest first.
+func (db *DB) GetAddressLinks(appID int, userID string) ([]*AddressLink, error) {
+	var l []*AddressLink
+	tx := db.Where("app_id = ? AND user_id = ? AND verified_at IS NOT NULL", appID, userID).Order("id").Find(&l)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return l, nil
+}
+
+// GetAddressOwner returns the verified link of the address, nil if no user owns it.
+func (db *DB) GetAddressOwner(address string) (*AddressLink, error) {
+	var l []*AddressLink
+	tx := db.Where("address = ? AND verified_at IS NOT NULL", address).Limit(1).Find(&l)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	if len(l) == 0 {
+		return nil, nil
+	}
+
+	return l[0], nil
+}
+
+// DeleteAddressLink removes the verified link of the user to the address, it returns false if it's not linked.
+func (db *DB) DeleteAddressLink(appID int, userID, address string) (bool, error) {
+	tx := db.Unscoped().Where("app_id = ? AND user_id = ? AND address = ? AND verified_at IS NOT NULL",
+		appID, userID, address).Delete(&AddressLink{})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// AcquireLock takes the lock for the owner until the TTL is passed.
+// It returns false if the lock is held and it's not expired, even by the same owner, so the lock is not reentrant.
+func (db *DB) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	// each statement is atomic, so only one owner can take over an expired lock.
+	tx := db.Model(&Lock{}).
+		Where("name = ? AND expires_at < ?", name, now).
+		Updates(map[string]any{"owner": owner, "expires_at": expiresAt})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	if tx.RowsAffected > 0 {
+		return true, nil
+	}
+
+	tx = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&Lock{
+		Name:      name,
+		Owner:     owner,
+		ExpiresAt: expiresAt,
+	})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// RenewLock extends the lock until the TTL is passed, it returns false if the owner doesn't hold the lock.
+func (db *DB) RenewLock(name, owner string, ttl time.Duration) (bool, error) {
+	tx := db.Model(&Lock{}).
+		Where("name = ? AND owner = ?", name, owner).
+		Update("expires_at", time.Now().Add(ttl))
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// ReleaseLock removes the lock if the owner holds it.
+func (db *DB) ReleaseLock(name, owner string) error {
+	tx := db.Where("name = ? AND owner = ?", name, owner).Delete(&Lock{})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
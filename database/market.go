@@ -0,0 +1,96 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// AddPriceSample adds the price of the hour, it's ignored if the hour has a sample already.
+func (db *DB) AddPriceSample(s *PriceSample) error {
+	tx := db.Clauses(clause.OnConflict{DoNothing: true}).Create(s)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetPriceSamples returns the samples since the time, the oldest first.
+func (db *DB) GetPriceSamples(since time.Time) ([]*PriceSample, error) {
+	var s []*PriceSample
+	tx := db.Where("hour >= ?", since).Order("hour").Find(&s)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return s, nil
+}
+
+func (db *DB) AddPriceAlert(a *PriceAlert) error {
+	tx := db.Create(a)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetActivePriceAlerts returns the alerts that are not triggered yet.
+func (db *DB) GetActivePriceAlerts() ([]*PriceAlert, error) {
+	var a []*PriceAlert
+	tx := db.Where("triggered_at IS NULL").Order("id").Find(&a)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return a, nil
+}
+
+// GetUserPriceAlerts returns the active alerts of the user on the platform.
+func (db *DB) GetUserPriceAlerts(appID int, userID string) ([]*PriceAlert, error) {
+	var a []*PriceAlert
+	tx := db.Where("app_id = ? AND user_id = ? AND triggered_at IS NULL", appID, userID).Order("id").Find(&a)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return a, nil
+}
+
+// TriggerPriceAlert marks the alert as triggered, it returns false if the alert is triggered already.
+func (db *DB) TriggerPriceAlert(id uint) (bool, error) {
+	tx := db.Model(&PriceAlert{}).
+		Where("id = ? AND triggered_at IS NULL", id).
+		Update("triggered_at", time.Now())
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// DeletePriceAlert removes the active alert of the user, it returns false if there is no such alert.
+func (db *DB) DeletePriceAlert(id uint, appID int, userID string) (bool, error) {
+	tx := db.Where("id = ? AND app_id = ? AND user_id = ? AND triggered_at IS NULL", id, appID, userID).
+		Delete(&PriceAlert{})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetUserCurrency sets the fiat currency of the user, an empty currency shows the amounts in PAC only.
+func (db *DB) SetUserCurrency(appID int, userID, currency string) error {
+	tx := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "app_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"currency", "updated_at"}),
+	}).Create(&UserPreference{
+		AppID:    appID,
+		UserID:   userID,
+		Currency: currency,
+	})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// SetUserPlainText sets the plain-text mode of the outputs of the user.
+func (db *DB) SetUserPlainText(appID int, userID string, plain bool) error {
+	tx := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "app_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"plain", "updated_at"}),
+	}).Create(&UserPreference{
+		AppID:  appID,
+		UserID: userID,
+		Plain:  plain,
+	})
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetUserPreference returns the preferences of the user, nil if the user has none.
+func (db *DB) GetUserPreference(appID int, userID string) (*UserPreference, error) {
+	var p UserPreference
+	tx := db.Where("app_id = ? AND user_id = ?", appID, userID).First(&p)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,51 @@
+package database
+
+import "gorm.io/gorm"
+
+// CountUserFeedbacks returns how many feedbacks the user sent, the resolved ones too.
+func (db *DB) CountUserFeedbacks(appID int, userID string) (int64, error) {
+	var count int64
+	tx := db.Model(&Feedback{}).Where("app_id = ? AND user_id = ?", appID, userID).Count(&count)
+	if tx.Error != nil {
+		return 0, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return count, nil
+}
+
+// ForgetUser removes the data that the user keeps on the platform: the price alerts, the digest subscription,
+// the watched validators, the address links and their aliases, the feedbacks, the preferences and the last checks.
+// It returns how many records are removed.
+// The payouts are kept, the faucet limits and the accounting of the rewards rely on them.
+func (db *DB) ForgetUser(appID int, userID string) (int64, error) {
+	var removed int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{
+			&PriceAlert{},
+			&DigestSubscription{},
+			&WatchedValidator{},
+			&AddressLink{},
+			&AddressAlias{},
+			&Feedback{},
+			&UserPreference{},
+			&StatusCheck{},
+		} {
+			res := tx.Unscoped().Where("app_id = ? AND user_id = ?", appID, userID).Delete(model)
+			if res.Error != nil {
+				return WriteError{
+					Reason: res.Error.Error(),
+				}
+			}
+			removed += res.RowsAffected
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// The tables that are pruned by the age of their rows.
+const (
+	TableAnnouncements       = "announcements"
+	TableAvailabilitySamples = "availability_samples"
+	TablePriceSamples        = "price_samples"
+)
+
+// retentionTable is a table that is pruned, the rows are aged by the time column.
+type retentionTable struct {
+	model  any
+	column string
+}
+
+var retentionTables = map[string]retentionTable{
+	TableAnnouncements:       {model: &Announcement{}, column: "created_at"},
+	TableAvailabilitySamples: {model: &AvailabilitySample{}, column: "hour"},
+	TablePriceSamples:        {model: &PriceSample{}, column: "hour"},
+}
+
+// RetentionTables returns the names of the tables that can be pruned, sorted by the name.
+func RetentionTables() []string {
+	names := make([]string, 0, len(retentionTables))
+	for name := range retentionTables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// PruneTable removes the rows of the table that are older than the time and returns how many are removed.
+func (db *DB) PruneTable(table string, before time.Time) (int64, error) {
+	t, ok := retentionTables[table]
+	if !ok {
+		return 0, WriteError{
+			Reason: fmt.Sprintf("%s is not a table with a retention", table),
+		}
+	}
+
+	tx := db.Where(t.column+" < ?", before).Delete(t.model)
+	if tx.Error != nil {
+		return 0, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected, nil
+}
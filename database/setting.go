@@ -0,0 +1,42 @@
+package database
+
+import "gorm.io/gorm/clause"
+
+// SetSetting sets the value of the setting, or updates it if it's set already.
+func (db *DB) SetSetting(s *Setting) error {
+	tx := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+	}).Create(s)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) GetSettings() ([]*Setting, error) {
+	var s []*Setting
+	tx := db.Order("name").Find(&s)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return s, nil
+}
+
+// DeleteSetting removes the setting so it has its default value, it returns false if it's not set.
+func (db *DB) DeleteSetting(name string) (bool, error) {
+	tx := db.Where("name = ?", name).Delete(&Setting{})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetDigestSubscription subscribes the user to the digest, or updates the hour and the timezone of the subscription.
+func (db *DB) SetDigestSubscription(s *DigestSubscription) error {
+	tx := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "app_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hour", "timezone", "updated_at"}),
+	}).Create(s)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetDigestSubscription returns the subscription of the user, nil if the user is not subscribed.
+func (db *DB) GetDigestSubscription(appID int, userID string) (*DigestSubscription, error) {
+	var s DigestSubscription
+	tx := db.Where("app_id = ? AND user_id = ?", appID, userID).First(&s)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return &s, nil
+}
+
+func (db *DB) GetDigestSubscriptions() ([]*DigestSubscription, error) {
+	var s []*DigestSubscription
+	tx := db.Order("id").Find(&s)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return s, nil
+}
+
+// DeleteDigestSubscription removes the subscription of the user, it returns false if the user is not subscribed.
+func (db *DB) DeleteDigestSubscription(appID int, userID string) (bool, error) {
+	tx := db.Unscoped().Where("app_id = ? AND user_id = ?", appID, userID).Delete(&DigestSubscription{})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// ClaimDigest marks the digest as sent with the current state, if it's not sent since the time.
+// It returns false if another instance sent the digest already.
+func (db *DB) ClaimDigest(id uint, sentBefore time.Time, height uint32, validators int32) (bool, error) {
+	tx := db.Model(&DigestSubscription{}).
+		Where("id = ? AND (last_sent_at IS NULL OR last_sent_at < ?)", id, sentBefore).
+		Updates(map[string]any{
+			"last_sent_at":    time.Now(),
+			"last_height":     height,
+			"last_validators": validators,
+		})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// AddWatchedValidator adds the validator to the watch list of the user, it's ignored if it's there already.
+func (db *DB) AddWatchedValidator(w *WatchedValidator) error {
+	tx := db.Clauses(clause.OnConflict{DoNothing: true}).Create(w)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) GetWatchedValidators(appID int, userID string) ([]*WatchedValidator, error) {
+	var w []*WatchedValidator
+	tx := db.Where("app_id = ? AND user_id = ?", appID, userID).Order("id").Find(&w)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return w, nil
+}
+
+// GetAllWatchedValidators returns the watched validators of all the users, the oldest first.
+func (db *DB) GetAllWatchedValidators() ([]*WatchedValidator, error) {
+	var w []*WatchedValidator
+	tx := db.Order("id").Find(&w)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return w, nil
+}
+
+// DeleteWatchedValidator removes the validator from the watch list, it returns false if it's not watched.
+func (db *DB) DeleteWatchedValidator(appID int, userID, address string) (bool, error) {
+	tx := db.Unscoped().Where("app_id = ? AND user_id = ? AND address = ?", appID, userID, address).
+		Delete(&WatchedValidator{})
+	if tx.Error != nil {
+		return false, WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return tx.RowsAffected > 0, nil
+}
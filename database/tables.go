@@ -18,15 +18,36 @@ type Faucet struct {
 	Address         string
 	Amount          int
 	TransactionHash string
+	Memo            string // Memo of the transaction, to reconcile it with the claim.
 	UserID          string
 
 	gorm.Model
 }
 
+type FaucetReviewStatus string
+
+const (
+	FaucetReviewPending  FaucetReviewStatus = "pending"
+	FaucetReviewApproved FaucetReviewStatus = "approved"
+	FaucetReviewRejected FaucetReviewStatus = "rejected"
+)
+
+// FaucetReview is a suspicious faucet claim that waits for an admin before the payout.
+type FaucetReview struct {
+	UserID   string
+	Address  string
+	Reasons  string // Reasons of the abuse detection, separated by new lines.
+	Status   FaucetReviewStatus
+	Reviewer string // The admin who approved or rejected the claim.
+
+	gorm.Model
+}
+
 type ZealyUser struct {
 	Amount    int64
 	DiscordID string `gorm:"column:discord_id"`
 	TxHash    string
+	Memo      string // Memo of the reward transaction.
 
 	gorm.Model
 }
@@ -34,3 +55,204 @@ type ZealyUser struct {
 func (z *ZealyUser) IsClaimed() bool {
 	return len(z.TxHash) > 0
 }
+
+// Lock is a lease on a job or a payout, so it runs once across the Pagu instances that share the database.
+type Lock struct {
+	Name      string `gorm:"primaryKey"`
+	Owner     string // The instance that holds the lock.
+	ExpiresAt time.Time
+}
+
+// PriceSample is the PAC price of an hour, the first price seen in the hour is kept.
+type PriceSample struct {
+	Hour  time.Time `gorm:"primaryKey"`
+	Price float64   // Price in USDT.
+}
+
+type PriceAlertDirection string
+
+const (
+	PriceAlertAbove PriceAlertDirection = "above"
+	PriceAlertBelow PriceAlertDirection = "below"
+)
+
+// PriceAlert notifies the user once when the price crosses the target.
+type PriceAlert struct {
+	AppID       int // The platform to notify the user on.
+	UserID      string
+	Direction   PriceAlertDirection
+	Price       float64 // Target price in USDT.
+	TriggeredAt *time.Time
+
+	gorm.Model
+}
+
+// Crossed checks if the price reached the target of the alert.
+func (a *PriceAlert) Crossed(price float64) bool {
+	if a.Direction == PriceAlertAbove {
+		return price >= a.Price
+	}
+
+	return price <= a.Price
+}
+
+// NetworkSnapshot is the size of the network on a day, the first snapshot of the day is kept.
+type NetworkSnapshot struct {
+	Day        time.Time `gorm:"primaryKey"`
+	Validators int32
+	Accounts   int32
+	TotalPower int64   // Total stake of the validators in NanoPAC.
+	Nodes      uint32  // Peers connected to the bot nodes.
+	Gini       float64 // Gini coefficient of the stakes of the active validators.
+	Nakamoto   int32   // Fewest validators holding over a third of the stake, zero if the validators are not loaded.
+}
+
+// DigestSubscription sends the daily digest to the user at the hour of the timezone.
+// The state of the last digest is kept to show the changes since then.
+type DigestSubscription struct {
+	AppID          int    `gorm:"uniqueIndex:idx_digest_user"` // The platform to notify the user on.
+	UserID         string `gorm:"uniqueIndex:idx_digest_user"`
+	Hour           int    // Hour of the day in the timezone, from 0 to 23.
+	Timezone       string // IANA name, like: "Europe/Berlin".
+	LastSentAt     *time.Time
+	LastHeight     uint32
+	LastValidators int32
+
+	gorm.Model
+}
+
+// WatchedValidator is a validator that the user follows, like in the daily digest.
+type WatchedValidator struct {
+	AppID   int    `gorm:"uniqueIndex:idx_watched_validator"`
+	UserID  string `gorm:"uniqueIndex:idx_watched_validator"`
+	Address string `gorm:"uniqueIndex:idx_watched_validator"`
+
+	gorm.Model
+}
+
+// Announcement is a message posted to a channel, the key prevents posting it twice.
+type Announcement struct {
+	Key       string `gorm:"primaryKey"` // Like: "release:v1.2.0:Discord:1234".
+	CreatedAt time.Time
+}
+
+// FeatureFlag turns a command or an experimental behavior on or off at runtime, like: "network.map".
+type FeatureFlag struct {
+	Name      string `gorm:"primaryKey"`
+	Enabled   bool
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// DeprecatedCall is the number of the calls to a deprecated command, like: "network old-status".
+// The calls are counted across the restarts, to know when it's safe to remove the command.
+type DeprecatedCall struct {
+	Name       string `gorm:"primaryKey"`
+	Calls      int64
+	LastCallAt time.Time
+}
+
+// Setting is a runtime setting of Pagu, like the command prefix of a Discord server: "prefix.discord.1234".
+type Setting struct {
+	Name      string `gorm:"primaryKey"`
+	Value     string
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// Feedback is a suggestion or a bug that a user sends, the maintainers triage and resolve it.
+type Feedback struct {
+	AppID      int // The platform of the user.
+	UserID     string
+	Text       string
+	ResolvedBy string
+	ResolvedAt *time.Time // Nil while the feedback is open.
+
+	gorm.Model
+}
+
+// AddressLink links a user of a platform to an address that the user proved to own by signing the nonce.
+// It's pending until the signature is verified, the pending links expire.
+type AddressLink struct {
+	AppID      int    `gorm:"index:idx_address_link_user"` // The platform of the user.
+	UserID     string `gorm:"index:idx_address_link_user"`
+	Address    string `gorm:"index"`
+	Nonce      string // The message to sign has it, so an old signature can't link the address again.
+	ExpiresAt  time.Time
+	VerifiedAt *time.Time // Nil while the link is pending.
+
+	gorm.Model
+}
+
+// AddressAlias is the public name of an address, like the name of a validator. The owner of the address sets it,
+// the owner is the user that linked the address.
+type AddressAlias struct {
+	Address string `gorm:"uniqueIndex"`
+	Name    string `gorm:"index"` // Unique in the lower case, the other commands show it next to the address.
+	AppID   int    // The platform of the user that set the alias.
+	UserID  string
+
+	gorm.Model
+}
+
+// UserPreference is how the user wants the outputs, like the fiat currency of the amounts.
+type UserPreference struct {
+	AppID    int    `gorm:"uniqueIndex:idx_user_preference"`
+	UserID   string `gorm:"uniqueIndex:idx_user_preference"`
+	Currency string // Like: "EUR", empty shows the amounts in PAC only.
+	Plain    bool   // The outputs are in plain text, without the emoji and the decorative characters.
+
+	gorm.Model
+}
+
+// StatusCheck is the last result of a check that the user repeats, like the network status,
+// the next check shows what changed since it.
+type StatusCheck struct {
+	AppID  int    `gorm:"uniqueIndex:idx_status_check"`
+	UserID string `gorm:"uniqueIndex:idx_status_check"`
+	Name   string `gorm:"uniqueIndex:idx_status_check"` // The checked command, like: "network status".
+	Result string // The result in JSON.
+
+	gorm.Model
+}
+
+// HealthSample is the last block that the nodes had in a minute, the first sample of the minute is kept.
+// The health timeline of the network is computed from the samples.
+type HealthSample struct {
+	Minute time.Time `gorm:"primaryKey"`
+	Height uint32    // The last block height.
+	Lag    int64     // The seconds since the last block, like the time diff of the network health.
+}
+
+// AvailabilitySample is the state of a watched validator in an hour, the first sample of the hour is kept.
+// The monthly uptime reports are computed from the samples.
+type AvailabilitySample struct {
+	Address             string    `gorm:"primaryKey"`
+	Hour                time.Time `gorm:"primaryKey"`
+	Height              uint32    // The last block of the network.
+	Online              bool      // The node is connected to the nodes of Pagu and synced.
+	Score               float64   // Availability score.
+	LastSortitionHeight uint32
+	Stake               int64 // Stake in NanoPAC.
+	TotalPower          int64 // Total stake of the validators in NanoPAC.
+}
+
+type TxDirection string
+
+const (
+	TxIncoming TxDirection = "in"
+	TxOutgoing TxDirection = "out"
+)
+
+// AccountTransaction is a transaction that touches the address,
+// a transaction between two addresses has a row for each of them.
+type AccountTransaction struct {
+	TxID         string `gorm:"primaryKey"`
+	Address      string `gorm:"primaryKey;index"`
+	Height       uint32 `gorm:"index"`
+	BlockTime    time.Time
+	Type         string // Payload type, like: "transfer" or "bond".
+	Direction    TxDirection
+	Counterparty string // The other address of the transaction, empty if there is none, like in unbond.
+	Amount       int64  // Amount in NanoPAC.
+}
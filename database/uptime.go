@@ -0,0 +1,32 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// AddAvailabilitySample adds the sample of the hour, it's ignored if the hour has a sample already.
+func (db *DB) AddAvailabilitySample(s *AvailabilitySample) error {
+	tx := db.Clauses(clause.OnConflict{DoNothing: true}).Create(s)
+	if tx.Error != nil {
+		return WriteError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetAvailabilitySamples returns the samples of the validator from the time until before the end, the oldest first.
+func (db *DB) GetAvailabilitySamples(address string, from, to time.Time) ([]*AvailabilitySample, error) {
+	var s []*AvailabilitySample
+	tx := db.Where("address = ? AND hour >= ? AND hour < ?", address, from, to).Order("hour").Find(&s)
+	if tx.Error != nil {
+		return nil, ReadError{
+			Reason: tx.Error.Error(),
+		}
+	}
+
+	return s, nil
+}
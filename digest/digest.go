@@ -0,0 +1,220 @@
+package digest
+
+import (
+	"context"
+	"time"
+	_ "time/tzdata" // the timezones of the users are known on the hosts without the tz database too.
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+// CheckInterval is how often the subscriptions are checked, a digest is sent in the first check of its hour.
+const CheckInterval = 5 * time.Minute
+
+// minResend prevents sending the digest twice a day, like when the timezone has a daylight saving change.
+const minResend = 20 * time.Hour
+
+// Store keeps the digest subscriptions and the data of the digests.
+type Store interface {
+	GetDigestSubscriptions() ([]*database.DigestSubscription, error)
+	ClaimDigest(id uint, sentBefore time.Time, height uint32, validators int32) (bool, error)
+	GetWatchedValidators(appID int, userID string) ([]*database.WatchedValidator, error)
+	GetPriceSamples(since time.Time) ([]*database.PriceSample, error)
+}
+
+// PriceSource returns the last price of PAC in USDT, like the market tracker.
+type PriceSource interface {
+	Price(ctx context.Context) (float64, error)
+}
+
+// Summary is the content of a daily digest.
+type Summary struct {
+	Height         uint32
+	HeightDelta    int64
+	Validators     int32
+	NewValidators  int64
+	HasPrevious    bool // The changes are known since the last digest.
+	Price          float64
+	HasPrice       bool
+	PriceChange    float64 // 24h change in percent.
+	HasPriceChange bool
+	Watched        []Validator
+}
+
+// Validator is a watched validator in the digest.
+type Validator struct {
+	Address           string
+	Found             bool
+	Number            int32
+	AvailabilityScore float64
+	Stake             amount.Amount
+}
+
+// Digest sends the daily digests to the subscribed users.
+// The instances can share the store, a digest is claimed once a day before it's sent.
+type Digest struct {
+	clientMgr *client.Mgr
+	store     Store
+	prices    PriceSource
+	hub       *notify.Hub
+	now       func() time.Time
+}
+
+func NewDigest(clientMgr *client.Mgr, store Store, prices PriceSource, hub *notify.Hub) *Digest {
+	return &Digest{
+		clientMgr: clientMgr,
+		store:     store,
+		prices:    prices,
+		hub:       hub,
+		now:       time.Now,
+	}
+}
+
+// Job returns the scheduler job of the digests. It's not exclusive,
+// each instance sends the digests of the platforms that it can notify on.
+func (d *Digest) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:      "digest",
+		Interval:  CheckInterval,
+		Exclusive: false,
+		Run:       d.Run,
+	}
+}
+
+// Due checks if the digest of the subscription should be sent at the time.
+func Due(sub *database.DigestSubscription, now time.Time) bool {
+	loc, err := time.LoadLocation(sub.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	if now.In(loc).Hour() != sub.Hour {
+		return false
+	}
+
+	return sub.LastSentAt == nil || now.Sub(*sub.LastSentAt) >= minResend
+}
+
+// Run sends the digests that are due.
+func (d *Digest) Run(ctx context.Context) error {
+	subs, err := d.store.GetDigestSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	now := d.now()
+	due := make([]*database.DigestSubscription, 0)
+	for _, sub := range subs {
+		if Due(sub, now) && d.hub.Supports(command.AppID(sub.AppID)) {
+			due = append(due, sub)
+		}
+	}
+
+	if len(due) == 0 {
+		return nil
+	}
+
+	info, err := d.clientMgr.GetBlockchainInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	price, hasPrice, priceChange, hasPriceChange := d.priceChange(ctx, now)
+	for _, sub := range due {
+		summary := Summary{
+			Height:         info.LastBlockHeight,
+			Validators:     info.TotalValidators,
+			HasPrevious:    sub.LastSentAt != nil,
+			Price:          price,
+			HasPrice:       hasPrice,
+			PriceChange:    priceChange,
+			HasPriceChange: hasPriceChange,
+		}
+		if summary.HasPrevious {
+			summary.HeightDelta = int64(info.LastBlockHeight) - int64(sub.LastHeight)
+			summary.NewValidators = int64(info.TotalValidators) - int64(sub.LastValidators)
+		}
+		summary.Watched = d.watched(ctx, sub)
+
+		d.send(sub, now, summary)
+	}
+
+	return nil
+}
+
+// send claims the digest of the subscription and sends it, a failed message is not retried until the next day.
+func (d *Digest) send(sub *database.DigestSubscription, now time.Time, summary Summary) {
+	claimed, err := d.store.ClaimDigest(sub.ID, now.Add(-minResend), summary.Height, summary.Validators)
+	if err != nil {
+		log.Error("can't claim the digest", "err", err, "id", sub.ID)
+
+		return
+	}
+
+	if !claimed {
+		// another instance sent it.
+		return
+	}
+
+	appID := command.AppID(sub.AppID)
+	msg, err := command.RenderTemplate(appID, "digest", summary)
+	if err != nil {
+		log.Error("can't render the digest", "err", err, "id", sub.ID)
+
+		return
+	}
+
+	if err := d.hub.Notify(appID, sub.UserID, msg); err != nil {
+		log.Warn("can't send the digest", "err", err, "id", sub.ID, "user", sub.UserID)
+	}
+}
+
+// priceChange returns the price and its change from the oldest sample of the last 24 hours.
+func (d *Digest) priceChange(ctx context.Context, now time.Time) (float64, bool, float64, bool) {
+	price, err := d.prices.Price(ctx)
+	if err != nil {
+		log.Warn("can't get the price for the digest", "err", err)
+
+		return 0, false, 0, false
+	}
+
+	samples, err := d.store.GetPriceSamples(now.Add(-24 * time.Hour))
+	if err != nil || len(samples) == 0 || samples[0].Price <= 0 {
+		return price, true, 0, false
+	}
+
+	return price, true, (price - samples[0].Price) * 100 / samples[0].Price, true
+}
+
+func (d *Digest) watched(ctx context.Context, sub *database.DigestSubscription) []Validator {
+	watched, err := d.store.GetWatchedValidators(sub.AppID, sub.UserID)
+	if err != nil {
+		log.Error("can't get the watched validators", "err", err, "user", sub.UserID)
+
+		return nil
+	}
+
+	validators := make([]Validator, 0, len(watched))
+	for _, w := range watched {
+		v := Validator{
+			Address: w.Address,
+		}
+
+		info, err := d.clientMgr.GetValidatorInfo(ctx, w.Address)
+		if err == nil && info.Validator != nil {
+			v.Found = true
+			v.Number = info.Validator.Number
+			v.AvailabilityScore = info.Validator.AvailabilityScore
+			v.Stake = amount.Amount(info.Validator.Stake)
+		}
+		validators = append(validators, v)
+	}
+
+	return validators
+}
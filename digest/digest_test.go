@@ -0,0 +1,153 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type memoryStore struct {
+	subs    []*database.DigestSubscription
+	watched []*database.WatchedValidator
+	samples []*database.PriceSample
+}
+
+func (s *memoryStore) GetDigestSubscriptions() ([]*database.DigestSubscription, error) {
+	return s.subs, nil
+}
+
+func (s *memoryStore) ClaimDigest(id uint, sentBefore time.Time, height uint32, validators int32) (bool, error) {
+	for _, sub := range s.subs {
+		if sub.ID != id || (sub.LastSentAt != nil && !sub.LastSentAt.Before(sentBefore)) {
+			continue
+		}
+
+		now := time.Now()
+		sub.LastSentAt = &now
+		sub.LastHeight = height
+		sub.LastValidators = validators
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (s *memoryStore) GetWatchedValidators(appID int, userID string) ([]*database.WatchedValidator, error) {
+	watched := make([]*database.WatchedValidator, 0)
+	for _, w := range s.watched {
+		if w.AppID == appID && w.UserID == userID {
+			watched = append(watched, w)
+		}
+	}
+
+	return watched, nil
+}
+
+func (s *memoryStore) GetPriceSamples(_ time.Time) ([]*database.PriceSample, error) {
+	return s.samples, nil
+}
+
+type fixedPrice float64
+
+func (p fixedPrice) Price(_ context.Context) (float64, error) {
+	return float64(p), nil
+}
+
+type messages map[string][]string
+
+func (m messages) Notify(userID, message string) error {
+	m[userID] = append(m[userID], message)
+
+	return nil
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2024, 5, 10, 7, 3, 0, 0, time.UTC)
+	sub := &database.DigestSubscription{Hour: 9, Timezone: "Europe/Berlin"}
+	assert.True(t, Due(sub, now), "09:03 in Berlin")
+
+	sub.Timezone = "UTC"
+	assert.False(t, Due(sub, now))
+
+	sub.Timezone = "Not/Exist"
+	sub.Hour = 7
+	assert.True(t, Due(sub, now), "UTC if the timezone is unknown")
+
+	sent := now.Add(-time.Hour)
+	sub.LastSentAt = &sent
+	assert.False(t, Due(sub, now), "sent today")
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{
+		LastBlockHeight: 12_000,
+		TotalValidators: 105,
+	}, nil).AnyTimes()
+	c.EXPECT().GetValidatorInfo(gomock.Any(), "pc1p1").Return(&pactus.GetValidatorResponse{
+		Validator: &pactus.ValidatorInfo{Number: 7, AvailabilityScore: 0.95, Stake: 1_000_000_000_000},
+	}, nil).AnyTimes()
+	c.EXPECT().GetValidatorInfo(gomock.Any(), "pc1p2").Return(nil, errors.New("not found")).AnyTimes()
+
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	now := time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	store := &memoryStore{
+		subs: []*database.DigestSubscription{
+			{AppID: int(command.AppIdDiscord), UserID: "alice", Hour: 8, Timezone: "UTC"},
+			{
+				AppID: int(command.AppIdDiscord), UserID: "bob", Hour: 8, Timezone: "UTC",
+				LastSentAt: &yesterday, LastHeight: 11_000, LastValidators: 100,
+			},
+			{AppID: int(command.AppIdDiscord), UserID: "carol", Hour: 20, Timezone: "UTC"},
+			{AppID: int(command.AppIdTelegram), UserID: "dave", Hour: 8, Timezone: "UTC"},
+		},
+		watched: []*database.WatchedValidator{
+			{AppID: int(command.AppIdDiscord), UserID: "bob", Address: "pc1p1"},
+			{AppID: int(command.AppIdDiscord), UserID: "bob", Address: "pc1p2"},
+		},
+		samples: []*database.PriceSample{{Hour: yesterday, Price: 0.1}},
+	}
+	for i, sub := range store.subs {
+		sub.ID = uint(i + 1)
+	}
+
+	discord := messages{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	d := NewDigest(cm, store, fixedPrice(0.12), hub)
+	d.now = func() time.Time { return now }
+	require.NoError(t, d.Run(context.Background()))
+
+	require.Len(t, discord["alice"], 1)
+	assert.Contains(t, discord["alice"][0], "Block height: 12,000\n")
+	assert.Contains(t, discord["alice"][0], "PAC price: 0.1200 USDT (+20.00% in 24h)")
+
+	require.Len(t, discord["bob"], 1)
+	assert.Contains(t, discord["bob"][0], "Block height: 12,000 (+1000)")
+	assert.Contains(t, discord["bob"][0], "Validators: 105 (+5)")
+	assert.Contains(t, discord["bob"][0], "#7 pc1p1: score 0.95, stake 1,000 PAC")
+	assert.Contains(t, discord["bob"][0], "pc1p2: not found")
+
+	assert.Empty(t, discord["carol"], "not the hour")
+	assert.Nil(t, store.subs[3].LastSentAt, "no notifier for telegram in this instance")
+
+	require.NoError(t, d.Run(context.Background()))
+	assert.Len(t, discord["alice"], 1, "a digest is sent once a day")
+}
@@ -1,19 +1,31 @@
 package discord
 
 import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/pactus-project/pactus/types/amount"
 	"github.com/pagu-project/Pagu/config"
 	"github.com/pagu-project/Pagu/engine"
 	"github.com/pagu-project/Pagu/engine/command"
 	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/outbox"
 	"github.com/pagu-project/Pagu/utils"
 )
 
+// uploadOptionSuffix is the suffix of the attachment option of an argument that can be uploaded,
+// like: "validators_file".
+const uploadOptionSuffix = "_file"
+
 type DiscordBot struct {
 	Session *discordgo.Session
+	shards  []*discordgo.Session // The gateway sessions of the shards of the instance, the first one is the Session.
+	ctx     context.Context      // Canceled when the bot stops, like the downloads of the uploaded files.
+	cancel  context.CancelFunc
+	outbox  *outbox.Outbox // Paces the messages, the replies to the commands are sent before the announcements.
 	engine  *engine.BotEngine
 	cfg     config.DiscordBot
 }
@@ -24,8 +36,17 @@ func NewDiscordBot(botEngine *engine.BotEngine, token string, cfg config.Discord
 		return nil, err
 	}
 
+	if cfg.MessageContent {
+		s.Identify.Intents |= discordgo.IntentMessageContent
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &DiscordBot{
 		Session: s,
+		ctx:     ctx,
+		cancel:  cancel,
+		outbox:  outbox.NewOutbox(cfg.SendRate, outboxQueue, retryAfter),
 		engine:  botEngine,
 		cfg:     cfg,
 	}, nil
@@ -34,19 +55,110 @@ func NewDiscordBot(botEngine *engine.BotEngine, token string, cfg config.Discord
 func (bot *DiscordBot) Start() error {
 	log.Info("starting Discord Bot...")
 
-	err := bot.Session.Open()
+	bot.outbox.Start()
+	err := bot.openShards()
 	if err != nil {
 		return err
 	}
 
+	bot.engine.SetNotifier(command.AppIdDiscord, bot)
+	bot.engine.SetServerAdmins(command.AppIdDiscord, bot)
+
 	bot.deleteAllCommands()
 	return bot.registerCommands()
 }
 
+// Notify sends a direct message to the user.
+func (bot *DiscordBot) Notify(userID, message string) error {
+	channel, err := bot.Session.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+
+	return bot.sendMessage(bot.Session, outbox.PriorityAnnouncement, channel.ID, &discordgo.MessageSend{
+		Content: message,
+	})
+}
+
+// IsServerAdmin returns true if the user owns the server, or manages it by a role.
+func (bot *DiscordBot) IsServerAdmin(guildID, userID string) bool {
+	guild, err := bot.Session.Guild(guildID)
+	if err != nil {
+		log.Warn("can't get the server", "err", err, "guild", guildID)
+
+		return false
+	}
+
+	if guild.OwnerID == userID {
+		return true
+	}
+
+	member, err := bot.Session.GuildMember(guildID, userID)
+	if err != nil {
+		log.Warn("can't get the member of the server", "err", err, "guild", guildID)
+
+		return false
+	}
+
+	return managesGuild(guild, member)
+}
+
+// HasChannel returns true if the channel is in the server, from the state of the session or from Discord.
+func (bot *DiscordBot) HasChannel(guildID, channelID string) bool {
+	channel, err := bot.Session.State.Channel(channelID)
+	if err != nil {
+		channel, err = bot.Session.Channel(channelID)
+	}
+
+	if err != nil {
+		log.Warn("can't get the channel", "err", err, "channel", channelID)
+
+		return false
+	}
+
+	return channel.GuildID == guildID
+}
+
+// managesGuild returns true if a role of the member has the Administrator or the Manage Server permission.
+// All the members have the @everyone role, its ID is the ID of the server.
+func managesGuild(guild *discordgo.Guild, member *discordgo.Member) bool {
+	const manage = discordgo.PermissionAdministrator | discordgo.PermissionManageServer
+	for _, role := range guild.Roles {
+		if (role.ID == guild.ID || slices.Contains(member.Roles, role.ID)) && role.Permissions&manage != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Announce posts the message to the channel, the mentions in it don't ping anyone.
+func (bot *DiscordBot) Announce(channelID, message string) error {
+	return bot.sendMessage(bot.Session, outbox.PriorityAnnouncement, channelID, &discordgo.MessageSend{
+		Content:         message,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+}
+
+// AnnounceAttachments posts the message with the attachments to the channel, the first image is shown in an embed.
+func (bot *DiscordBot) AnnounceAttachments(channelID, message string, attachments []command.Attachment) error {
+	embed, files := resultEmbed(command.CommandResult{
+		Message:     message,
+		Successful:  true,
+		Attachments: attachments,
+	}, bot.cfg.MessageLimit)
+	embed.Title = ""
+	embed.Color = PACTUS
+
+	return bot.sendMessage(bot.Session, outbox.PriorityAnnouncement, channelID, &discordgo.MessageSend{
+		Embeds:          []*discordgo.MessageEmbed{embed},
+		Files:           files,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+}
+
 func (bot *DiscordBot) deleteAllCommands() {
-	cmdsServer, _ := bot.Session.ApplicationCommands(bot.Session.State.User.ID, bot.cfg.GuildID)
-	cmdsGlobal, _ := bot.Session.ApplicationCommands(bot.Session.State.User.ID, "")
-	cmds := append(cmdsServer, cmdsGlobal...) //nolint
+	cmds, _ := bot.Session.ApplicationCommands(bot.Session.State.User.ID, "")
 
 	for _, cmd := range cmds {
 		err := bot.Session.ApplicationCommandDelete(cmd.ApplicationID, cmd.GuildID, cmd.ID)
@@ -59,10 +171,6 @@ func (bot *DiscordBot) deleteAllCommands() {
 }
 
 func (bot *DiscordBot) registerCommands() error {
-	bot.Session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		bot.commandHandler(bot, s, i)
-	})
-
 	beCmds := bot.engine.Commands()
 	for i, beCmd := range beCmds {
 		if !beCmd.HasAppId(command.AppIdDiscord) {
@@ -77,6 +185,10 @@ func (bot *DiscordBot) registerCommands() error {
 			Description: beCmd.Desc,
 		}
 
+		if localizations := nameLocalizations(beCmd); len(localizations) > 0 {
+			discordCmd.NameLocalizations = &localizations
+		}
+
 		if beCmd.HasSubCommand() {
 			for _, sCmd := range beCmd.SubCommands {
 				if sCmd.Name == "" || sCmd.Desc == "" {
@@ -86,29 +198,7 @@ func (bot *DiscordBot) registerCommands() error {
 				log.Info("adding command sub-command", "command", beCmd.Name,
 					"sub-command", sCmd.Name, "desc", sCmd.Desc)
 
-				subCmd := &discordgo.ApplicationCommandOption{
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-					Name:        sCmd.Name,
-					Description: sCmd.Desc,
-				}
-
-				for _, arg := range sCmd.Args {
-					if arg.Desc == "" || arg.Name == "" {
-						continue
-					}
-
-					log.Info("adding sub command argument", "command", beCmd.Name,
-						"sub-command", sCmd.Name, "argument", arg.Name, "desc", arg.Desc)
-
-					subCmd.Options = append(subCmd.Options, &discordgo.ApplicationCommandOption{
-						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        arg.Name,
-						Description: arg.Desc,
-						Required:    !arg.Optional,
-					})
-				}
-
-				discordCmd.Options = append(discordCmd.Options, subCmd)
+				discordCmd.Options = append(discordCmd.Options, newSubCommandOption(beCmd, sCmd))
 			}
 		} else {
 			for _, arg := range beCmd.Args {
@@ -119,16 +209,12 @@ func (bot *DiscordBot) registerCommands() error {
 				log.Info("adding command argument", "command", beCmd.Name,
 					"argument", arg.Name, "desc", arg.Desc)
 
-				discordCmd.Options = append(discordCmd.Options, &discordgo.ApplicationCommandOption{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        arg.Name,
-					Description: arg.Desc,
-					Required:    !arg.Optional,
-				})
+				discordCmd.Options = append(discordCmd.Options, argOptions(arg)...)
 			}
 		}
 
-		cmd, err := bot.Session.ApplicationCommandCreate(bot.Session.State.User.ID, bot.cfg.GuildID, &discordCmd)
+		// the commands are global, so the bot serves all the guilds that it's a member of.
+		cmd, err := bot.Session.ApplicationCommandCreate(bot.Session.State.User.ID, "", &discordCmd)
 		if err != nil {
 			log.Error("can not register discord command", "name", discordCmd.Name, "error", err)
 			return err
@@ -139,77 +225,376 @@ func (bot *DiscordBot) registerCommands() error {
 	return nil
 }
 
+// newSubCommandOption converts a sub-command to a Discord option.
+// Sub-commands that have their own sub-commands are registered as sub-command groups,
+// since Discord supports only one nested level of groups.
+func newSubCommandOption(beCmd, sCmd command.Command) *discordgo.ApplicationCommandOption {
+	if sCmd.HasSubCommand() {
+		group := &discordgo.ApplicationCommandOption{
+			Type:              discordgo.ApplicationCommandOptionSubCommandGroup,
+			Name:              sCmd.Name,
+			NameLocalizations: nameLocalizations(sCmd),
+			Description:       sCmd.Desc,
+		}
+
+		for _, gCmd := range sCmd.SubCommands {
+			if gCmd.Name == "" || gCmd.Desc == "" || gCmd.HasSubCommand() {
+				continue
+			}
+
+			log.Info("adding sub-command group command", "command", beCmd.Name,
+				"group", sCmd.Name, "sub-command", gCmd.Name, "desc", gCmd.Desc)
+
+			group.Options = append(group.Options, newSubCommandOption(sCmd, gCmd))
+		}
+
+		return group
+	}
+
+	subCmd := &discordgo.ApplicationCommandOption{
+		Type:              discordgo.ApplicationCommandOptionSubCommand,
+		Name:              sCmd.Name,
+		NameLocalizations: nameLocalizations(sCmd),
+		Description:       sCmd.Desc,
+	}
+
+	for _, arg := range sCmd.Args {
+		if arg.Desc == "" || arg.Name == "" {
+			continue
+		}
+
+		log.Info("adding sub command argument", "command", beCmd.Name,
+			"sub-command", sCmd.Name, "argument", arg.Name, "desc", arg.Desc)
+
+		subCmd.Options = append(subCmd.Options, argOptions(arg)...)
+	}
+
+	return subCmd
+}
+
+// argOptions converts an argument to the Discord options, an argument that can be uploaded
+// has an attachment option too, like: "validators_file".
+func argOptions(arg command.Args) []*discordgo.ApplicationCommandOption {
+	options := []*discordgo.ApplicationCommandOption{{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        arg.Name,
+		Description: arg.Desc,
+		Required:    !arg.Optional,
+	}}
+
+	if arg.Upload {
+		options = append(options, &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionAttachment,
+			Name:        arg.Name + uploadOptionSuffix,
+			Description: "A text file of the " + arg.Name + ", one or more in a line",
+			Required:    false,
+		})
+	}
+
+	return options
+}
+
+// nameLocalizations returns the localized names of the command for the locales that Discord supports.
+// Discord sends the interaction with the original name, so the engine doesn't need to match the aliases.
+func nameLocalizations(cmd command.Command) map[discordgo.Locale]string {
+	localizations := make(map[discordgo.Locale]string)
+	for _, alias := range cmd.Aliases {
+		locale := discordgo.Locale(alias.Locale)
+		if _, supported := discordgo.Locales[locale]; !supported {
+			continue
+		}
+
+		if _, ok := localizations[locale]; !ok {
+			localizations[locale] = alias.Name
+		}
+	}
+
+	return localizations
+}
+
+// uploadArgs returns the arguments of the first uploaded text file of the message, the other files are ignored.
+func uploadArgs(ctx context.Context, attachments []*discordgo.MessageAttachment) []string {
+	for _, att := range attachments {
+		if !command.IsTextUpload(att.ContentType, int64(att.Size)) {
+			continue
+		}
+
+		args, err := command.DownloadUploadArgs(ctx, att.URL)
+		if err != nil {
+			log.Warn("can't download the uploaded file", "err", err, "name", att.Filename)
+		}
+
+		return args
+	}
+
+	return nil
+}
+
+// messageHandler runs the commands of the messages that start with the command prefix of the guild,
+// like: "!pagu network status". The result is the reply of the message.
+func (bot *DiscordBot) messageHandler(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m.Author == nil || m.Author.Bot || m.GuildID == "" {
+		return
+	}
+
+	tokens, ok := bot.engine.PrefixedTokens(command.AppIdDiscord, m.GuildID, m.Content)
+	if !ok {
+		// the answer of a missing argument doesn't need the prefix, like the validator address.
+		if !bot.engine.Prompted(command.AppIdDiscord, m.Author.ID) {
+			return
+		}
+		tokens = strings.Fields(m.Content)
+	}
+
+	// the messages to Pagu are counted in the flood protection, the muted users are ignored.
+	if !bot.engine.AllowMessage(command.AppIdDiscord, m.Author.ID) {
+		return
+	}
+
+	// the arguments can be uploaded in a text file, like the validators of "validator bulk".
+	if len(m.Attachments) > 0 && bot.engine.AcceptsUpload(tokens) {
+		tokens = append(tokens, uploadArgs(bot.ctx, m.Attachments)...)
+	}
+
+	res := bot.engine.RunInGuild(command.AppIdDiscord, m.GuildID, m.Author.ID, tokens)
+	resEmbed, files := resultEmbed(res, bot.cfg.MessageLimit)
+
+	// the messages can't be ephemeral, so the result is sent to the author in private.
+	if res.Ephemeral {
+		bot.replyInPrivate(s, m, resEmbed, files)
+
+		return
+	}
+
+	err := bot.sendMessage(s, outbox.PriorityReply, m.ChannelID, &discordgo.MessageSend{
+		Embeds:          []*discordgo.MessageEmbed{resEmbed},
+		Files:           files,
+		Reference:       m.Reference(),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Error("can't reply to the message", "error", err)
+	}
+}
+
+// replyInPrivate sends the result to the author of the message in a direct message,
+// the channel is only told about it, so the data of the author is not shown there.
+func (bot *DiscordBot) replyInPrivate(s *discordgo.Session, m *discordgo.MessageCreate,
+	resEmbed *discordgo.MessageEmbed, files []*discordgo.File,
+) {
+	notice := "The result is sent to you in a direct message."
+
+	channel, err := s.UserChannelCreate(m.Author.ID)
+	if err == nil {
+		err = bot.sendMessage(s, outbox.PriorityReply, channel.ID, &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{resEmbed},
+			Files:  files,
+		})
+	}
+
+	if err != nil {
+		log.Error("can't send the result in a direct message", "error", err)
+		notice = "Can't send you the result in a direct message, please use the slash command."
+	}
+
+	err = bot.sendMessage(s, outbox.PriorityReply, m.ChannelID, &discordgo.MessageSend{
+		Content:         notice,
+		Reference:       m.Reference(),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Error("can't reply to the message", "error", err)
+	}
+}
+
 func (bot *DiscordBot) commandHandler(db *DiscordBot, s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.GuildID != bot.cfg.GuildID {
+	if i.GuildID == "" {
 		bot.respondErrMsg("Please send messages on server chat", s, i)
 		return
 	}
 
+	if !bot.engine.AllowMessage(command.AppIdDiscord, i.Member.User.ID) {
+		return
+	}
+
 	beInput := []string{}
 
 	// Get the application command data
 	discordCmd := i.ApplicationCommandData()
 	beInput = append(beInput, discordCmd.Name)
 	for _, opt := range discordCmd.Options {
-		if opt.Type == discordgo.ApplicationCommandOptionSubCommand {
-			beInput = append(beInput, opt.Name)
-			for _, args := range opt.Options {
-				beInput = append(beInput, args.StringValue())
-			}
+		beInput = appendSubCommandInput(beInput, opt)
+	}
+
+	// the download of an uploaded file can take longer than Discord waits for the response,
+	// so the response is deferred and the result is sent when it's ready.
+	uploads := uploadedAttachments(discordCmd)
+	if len(uploads) > 0 {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		})
+		if err != nil {
+			log.Error("InteractionRespond error:", "error", err)
+
+			return
 		}
+
+		beInput = append(beInput, uploadArgs(bot.ctx, uploads)...)
+		res := db.engine.RunInGuild(command.AppIdDiscord, i.GuildID, i.Member.User.ID, beInput)
+		bot.editResultMsg(res, s, i)
+
+		return
 	}
 
-	res := db.engine.Run(command.AppIdDiscord, i.Member.User.ID, beInput)
+	res := db.engine.RunInGuild(command.AppIdDiscord, i.GuildID, i.Member.User.ID, beInput)
 
 	bot.respondResultMsg(res, s, i)
 }
 
+// uploadedAttachments returns the files of the attachment options of the interaction, like: "validators_file".
+func uploadedAttachments(data discordgo.ApplicationCommandInteractionData) []*discordgo.MessageAttachment {
+	if data.Resolved == nil {
+		return nil
+	}
+
+	var uploads []*discordgo.MessageAttachment
+	var walk func(opts []*discordgo.ApplicationCommandInteractionDataOption)
+	walk = func(opts []*discordgo.ApplicationCommandInteractionDataOption) {
+		for _, opt := range opts {
+			if opt.Type != discordgo.ApplicationCommandOptionAttachment {
+				walk(opt.Options)
+
+				continue
+			}
+
+			id, _ := opt.Value.(string)
+			if att, ok := data.Resolved.Attachments[id]; ok {
+				uploads = append(uploads, att)
+			}
+		}
+	}
+	walk(data.Options)
+
+	return uploads
+}
+
+func appendSubCommandInput(beInput []string, opt *discordgo.ApplicationCommandInteractionDataOption) []string {
+	switch opt.Type {
+	case discordgo.ApplicationCommandOptionSubCommandGroup:
+		beInput = append(beInput, opt.Name)
+		for _, subOpt := range opt.Options {
+			beInput = appendSubCommandInput(beInput, subOpt)
+		}
+
+	case discordgo.ApplicationCommandOptionSubCommand:
+		beInput = append(beInput, opt.Name)
+		for _, args := range opt.Options {
+			// the uploaded files are downloaded by the command handler.
+			if args.Type == discordgo.ApplicationCommandOptionAttachment {
+				continue
+			}
+			beInput = append(beInput, args.StringValue())
+		}
+
+	default:
+	}
+
+	return beInput
+}
+
 func (bot *DiscordBot) respondErrMsg(errStr string, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	errorEmbed := &discordgo.MessageEmbed{
 		Title:       "Error",
 		Description: errStr,
 		Color:       RED,
 	}
-	bot.respondEmbed(errorEmbed, s, i)
+	bot.respondEmbed(errorEmbed, nil, false, s, i)
 }
 
 func (bot *DiscordBot) respondResultMsg(res command.CommandResult, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	resEmbed, files := resultEmbed(res, bot.cfg.MessageLimit)
+	bot.respondEmbed(resEmbed, files, res.Ephemeral, s, i)
+}
+
+// editResultMsg sends the result of a deferred interaction, like a command with an uploaded file.
+func (bot *DiscordBot) editResultMsg(res command.CommandResult, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	resEmbed, files := resultEmbed(res, bot.cfg.MessageLimit)
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{resEmbed},
+		Files:  files,
+	})
+	if err != nil {
+		log.Error("InteractionResponseEdit error:", "error", err)
+	}
+}
+
+// resultEmbed returns the embed of the result and its attachments as files.
+// A result over the limit is summarized, and the full result is sent as a file.
+func resultEmbed(res command.CommandResult, limit int) (*discordgo.MessageEmbed, []*discordgo.File) {
+	res = res.Fit(command.AppIdDiscord, limit)
+
 	var resEmbed *discordgo.MessageEmbed
+	msg := command.Linkify(command.AppIdDiscord, res.Message)
 	if res.Successful {
 		resEmbed = &discordgo.MessageEmbed{
 			Title:       "Successful",
-			Description: res.Message,
+			Description: msg,
 			Color:       GREEN,
 		}
 	} else {
 		resEmbed = &discordgo.MessageEmbed{
 			Title:       "Failed",
-			Description: res.Message,
+			Description: msg,
 			Color:       YELLOW,
 		}
 	}
 
-	bot.respondEmbed(resEmbed, s, i)
+	files := make([]*discordgo.File, 0, len(res.Attachments))
+	for _, att := range res.Attachments {
+		files = append(files, &discordgo.File{
+			Name:        att.Name,
+			ContentType: att.ContentType,
+			Reader:      bytes.NewReader(att.Data),
+		})
+
+		// Only one image can be shown inside an embed, the rest are sent as files.
+		if resEmbed.Image == nil && strings.HasPrefix(att.ContentType, "image/") {
+			resEmbed.Image = &discordgo.MessageEmbedImage{
+				URL: "attachment://" + att.Name,
+			}
+		}
+	}
+
+	return resEmbed, files
 }
 
-func (db *DiscordBot) respondEmbed(embed *discordgo.MessageEmbed, s *discordgo.Session, i *discordgo.InteractionCreate) {
+// respondEmbed responds to the interaction, an ephemeral response is only shown to the caller.
+// The responses are not in the outbox, the interactions are not bound to the global rate limit of the bot.
+func (db *DiscordBot) respondEmbed(embed *discordgo.MessageEmbed, files []*discordgo.File, ephemeral bool,
+	s *discordgo.Session, i *discordgo.InteractionCreate,
+) {
 	response := &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{embed},
+			Files:  files,
 		},
 	}
 
+	if ephemeral {
+		response.Data.Flags = discordgo.MessageFlagsEphemeral
+	}
+
 	err := s.InteractionRespond(i.Interaction, response)
 	if err != nil {
 		log.Error("InteractionRespond error:", "error", err)
 	}
 }
 
-func (db *DiscordBot) UpdateStatusInfo() {
+func (db *DiscordBot) UpdateStatusInfo(ctx context.Context) {
 	log.Info("info status started")
 	for {
-		ns, err := db.engine.NetworkStatus()
+		ns, err := db.engine.NetworkStatus(ctx)
 		if err != nil {
 			continue
 		}
@@ -238,10 +623,7 @@ func (db *DiscordBot) UpdateStatusInfo() {
 
 		time.Sleep(time.Second * 5)
 
-		circulatingSupplyAmount := amount.Amount(ns.CirculatingSupply)
-		formattedCirculatingSupply := circulatingSupplyAmount.Format(amount.UnitPAC) + " PAC"
-
-		err = db.Session.UpdateStatusComplex(newStatus("circ supply", formattedCirculatingSupply))
+		err = db.Session.UpdateStatusComplex(newStatus("circ supply", command.FormatAmount(ns.CirculatingSupply)))
 		if err != nil {
 			log.Error("can't set status", "err", err)
 			continue
@@ -249,10 +631,7 @@ func (db *DiscordBot) UpdateStatusInfo() {
 
 		time.Sleep(time.Second * 5)
 
-		totalNetworkPowerAmount := amount.Amount(ns.TotalNetworkPower)
-		formattedTotalNetworkPower := totalNetworkPowerAmount.Format(amount.UnitPAC) + " PAC"
-
-		err = db.Session.UpdateStatusComplex(newStatus("total power", formattedTotalNetworkPower))
+		err = db.Session.UpdateStatusComplex(newStatus("total power", command.FormatAmount(ns.TotalNetworkPower)))
 		if err != nil {
 			log.Error("can't set status", "err", err)
 			continue
@@ -265,5 +644,8 @@ func (db *DiscordBot) UpdateStatusInfo() {
 func (db *DiscordBot) Stop() error {
 	log.Info("Stopping Discord Bot")
 
-	return db.Session.Close()
+	db.cancel()
+	db.outbox.Stop()
+
+	return db.closeShards()
 }
@@ -0,0 +1,112 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoldenResults(t *testing.T) {
+	for _, f := range golden.Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			embed, files := resultEmbed(f.Result, golden.Limit)
+
+			sb := strings.Builder{}
+			fmt.Fprintf(&sb, "title: %s\n", embed.Title)
+			fmt.Fprintf(&sb, "color: %d\n", embed.Color)
+			if embed.Image != nil {
+				fmt.Fprintf(&sb, "image: %s\n", embed.Image.URL)
+			}
+			for _, file := range files {
+				fmt.Fprintf(&sb, "file: %s (%s)\n", file.Name, file.ContentType)
+			}
+			fmt.Fprintf(&sb, "description:\n%s\n", embed.Description)
+
+			golden.Assert(t, f.Name, sb.String())
+		})
+	}
+}
+
+func TestUploadOptions(t *testing.T) {
+	assert.Len(t, argOptions(command.Args{Name: "validator", Desc: "Validator address"}), 1)
+
+	options := argOptions(command.Args{Name: "validators", Desc: "Validator addresses", Optional: true, Upload: true})
+	require.Len(t, options, 2)
+	assert.Equal(t, discordgo.ApplicationCommandOptionAttachment, options[1].Type)
+	assert.Equal(t, "validators_file", options[1].Name)
+
+	upload := &discordgo.MessageAttachment{ID: "1", Filename: "validators.txt"}
+	data := discordgo.ApplicationCommandInteractionData{
+		Name: "validator",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+			Name: "bulk",
+			Type: discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "validators_file", Type: discordgo.ApplicationCommandOptionAttachment, Value: "1"},
+			},
+		}},
+		Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+			Attachments: map[string]*discordgo.MessageAttachment{"1": upload},
+		},
+	}
+
+	assert.Equal(t, []*discordgo.MessageAttachment{upload}, uploadedAttachments(data))
+	assert.Equal(t, []string{"validator", "bulk"}, appendSubCommandInput([]string{"validator"}, data.Options[0]),
+		"the file is not an argument")
+}
+
+func TestManagesGuild(t *testing.T) {
+	guild := &discordgo.Guild{ID: "1", Roles: []*discordgo.Role{
+		{ID: "1", Permissions: discordgo.PermissionSendMessages},
+		{ID: "2", Permissions: discordgo.PermissionManageServer},
+		{ID: "3", Permissions: discordgo.PermissionAdministrator},
+	}}
+
+	assert.False(t, managesGuild(guild, &discordgo.Member{}))
+	assert.True(t, managesGuild(guild, &discordgo.Member{Roles: []string{"2"}}))
+	assert.True(t, managesGuild(guild, &discordgo.Member{Roles: []string{"3"}}))
+
+	guild.Roles[0].Permissions |= discordgo.PermissionAdministrator
+	assert.True(t, managesGuild(guild, &discordgo.Member{}), "the @everyone role manages the server")
+}
+
+func TestHasChannel(t *testing.T) {
+	state := discordgo.NewState()
+	require.NoError(t, state.GuildAdd(&discordgo.Guild{ID: "1"}))
+	require.NoError(t, state.GuildAdd(&discordgo.Guild{ID: "2"}))
+	require.NoError(t, state.ChannelAdd(&discordgo.Channel{ID: "10", GuildID: "1"}))
+	require.NoError(t, state.ChannelAdd(&discordgo.Channel{ID: "20", GuildID: "2"}))
+	bot := &DiscordBot{Session: &discordgo.Session{State: state}}
+
+	assert.True(t, bot.HasChannel("1", "10"))
+	assert.False(t, bot.HasChannel("1", "20"), "a channel of another server")
+}
+
+func TestIdentifyLimiter(t *testing.T) {
+	ctx := context.Background()
+	interval := 100 * time.Millisecond
+	limiter := newIdentifyLimiter(2, interval)
+
+	start := time.Now()
+	for _, id := range []int{0, 1} {
+		require.NoError(t, limiter.wait(ctx, id))
+		limiter.identified(id)
+	}
+	assert.Less(t, time.Since(start), interval, "the shards of other buckets identify at once")
+
+	require.NoError(t, limiter.wait(ctx, 2))
+	assert.GreaterOrEqual(t, time.Since(start), interval, "the shard 2 is in the bucket of the shard 0")
+	limiter.identified(2)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, limiter.wait(canceled, 4), context.Canceled)
+}
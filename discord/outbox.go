@@ -0,0 +1,43 @@
+package discord
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/pagu-project/Pagu/outbox"
+)
+
+// outboxQueue is the number of the messages of each priority that wait to be sent.
+const outboxQueue = 100
+
+// noRetry returns the rate-limited messages to the outbox, so it sends them again and the other messages wait.
+var noRetry = discordgo.WithRetryOnRatelimit(false)
+
+// retryAfter returns the time that Discord asks to send a rate-limited message again in, the retry_after of a 429.
+func retryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.RateLimit != nil && rateLimitErr.TooManyRequests != nil {
+		return rateLimitErr.RetryAfter, true
+	}
+
+	return 0, false
+}
+
+// sendMessage sends the message to the channel by the outbox, the files are read again if it's sent again.
+func (bot *DiscordBot) sendMessage(s *discordgo.Session, priority outbox.Priority, channelID string,
+	msg *discordgo.MessageSend,
+) error {
+	return bot.outbox.Send(priority, func() error {
+		for _, file := range msg.Files {
+			if seeker, ok := file.Reader.(io.Seeker); ok {
+				_, _ = seeker.Seek(0, io.SeekStart)
+			}
+		}
+
+		_, err := s.ChannelMessageSendComplex(channelID, msg, noRetry)
+
+		return err
+	})
+}
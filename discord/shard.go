@@ -0,0 +1,210 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/pagu-project/Pagu/log"
+)
+
+const (
+	// shardHealthInterval is the time between the health reports of the shards.
+	shardHealthInterval = 30 * time.Second
+
+	// identifyInterval is the time between the identifies of a bucket, a bucket is the shard ID modulo
+	// the max concurrency of the bot.
+	identifyInterval = 5 * time.Second
+)
+
+// openShards opens a gateway session for each shard of the instance, the session of the bot is the first one.
+// The commands and the announcements are sent by the REST API, so any session sends them.
+func (bot *DiscordBot) openShards() error {
+	count := bot.cfg.ShardCount
+	maxConcurrency := 0
+	if count == 0 {
+		gateway, err := bot.Session.GatewayBot()
+		if err != nil {
+			return err
+		}
+		count = max(gateway.Shards, 1)
+		maxConcurrency = gateway.SessionStartLimit.MaxConcurrency
+		log.Info("discord shard count detected", "shards", count, "maxConcurrency", maxConcurrency)
+	}
+
+	ids, err := shardIDs(count, bot.cfg.ShardIDs)
+	if err != nil {
+		return err
+	}
+
+	if maxConcurrency == 0 && len(ids) > 1 {
+		gateway, err := bot.Session.GatewayBot()
+		if err != nil {
+			log.Warn("can't get the max concurrency of the shards, they are identified one by one", "error", err)
+		} else {
+			maxConcurrency = gateway.SessionStartLimit.MaxConcurrency
+		}
+	}
+
+	limiter := newIdentifyLimiter(maxConcurrency, identifyInterval)
+	for i, id := range ids {
+		s := bot.Session
+		if i > 0 {
+			s, err = discordgo.New(bot.Session.Token)
+			if err != nil {
+				_ = bot.closeShards()
+
+				return err
+			}
+			s.Identify.Intents = bot.Session.Identify.Intents
+		}
+		s.ShardID = id
+		s.ShardCount = count
+
+		bot.addHandlers(s)
+		if err := limiter.wait(bot.ctx, id); err != nil {
+			_ = bot.closeShards()
+
+			return err
+		}
+		err := s.Open()
+		limiter.identified(id)
+		if err != nil {
+			// the opened shards are closed, so a failed start doesn't leave them connected.
+			_ = bot.closeShards()
+
+			return fmt.Errorf("can't open the shard %d: %w", id, err)
+		}
+		log.Info("discord shard opened", "shard", id, "shards", count)
+
+		bot.shards = append(bot.shards, s)
+	}
+
+	go bot.reportShards()
+
+	return nil
+}
+
+// closeShards closes the gateway sessions of the opened shards.
+func (bot *DiscordBot) closeShards() error {
+	var err error
+	for _, s := range bot.shards {
+		err = errors.Join(err, s.Close())
+	}
+	bot.shards = nil
+
+	return err
+}
+
+// shardIDs returns the shards of the instance, all the shards if none is configured.
+func shardIDs(count int, configured []int) ([]int, error) {
+	if len(configured) == 0 {
+		ids := make([]int, count)
+		for i := range ids {
+			ids[i] = i
+		}
+
+		return ids, nil
+	}
+
+	for _, id := range configured {
+		if id < 0 || id >= count {
+			return nil, fmt.Errorf("the shard %d is out of the %d shards of the bot", id, count)
+		}
+	}
+
+	return configured, nil
+}
+
+// identifyLimiter waits between the identifies of the shards, Discord allows one identify in each interval
+// for each bucket, and a shard that identifies sooner is rate limited or gets an invalid session.
+type identifyLimiter struct {
+	maxConcurrency int
+	interval       time.Duration
+	last           map[int]time.Time
+}
+
+func newIdentifyLimiter(maxConcurrency int, interval time.Duration) *identifyLimiter {
+	return &identifyLimiter{
+		maxConcurrency: max(maxConcurrency, 1),
+		interval:       interval,
+		last:           make(map[int]time.Time),
+	}
+}
+
+// wait waits until the bucket of the shard can identify, or until the context is done.
+func (l *identifyLimiter) wait(ctx context.Context, shardID int) error {
+	last, ok := l.last[shardID%l.maxConcurrency]
+	if !ok {
+		return nil
+	}
+
+	delay := l.interval - time.Since(last)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// identified marks the bucket of the shard as identified now, the shard identifies when it opens.
+func (l *identifyLimiter) identified(shardID int) {
+	l.last[shardID%l.maxConcurrency] = time.Now()
+}
+
+// addHandlers adds the handlers of the interactions and the messages to the session of a shard,
+// Discord sends the events of a guild to its shard. The connection events update the health of the shard.
+func (bot *DiscordBot) addHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		bot.commandHandler(bot, s, i)
+	})
+	s.AddHandler(bot.messageHandler)
+	s.AddHandler(func(s *discordgo.Session, _ *discordgo.Connect) {
+		bot.observeShard(s, true)
+	})
+	s.AddHandler(func(s *discordgo.Session, _ *discordgo.Disconnect) {
+		bot.observeShard(s, false)
+	})
+}
+
+// reportShards reports the health of the shards until the bot stops.
+func (bot *DiscordBot) reportShards() {
+	ticker := time.NewTicker(shardHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bot.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, s := range bot.shards {
+				s.RLock()
+				connected := s.DataReady
+				s.RUnlock()
+
+				bot.observeShard(s, connected)
+			}
+		}
+	}
+}
+
+func (bot *DiscordBot) observeShard(s *discordgo.Session, connected bool) {
+	guilds := 0
+	if s.State != nil {
+		s.State.RLock()
+		guilds = len(s.State.Guilds)
+		s.State.RUnlock()
+	}
+
+	bot.engine.ObserveShard(s.ShardID, connected, guilds, s.HeartbeatLatency())
+}
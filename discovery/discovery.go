@@ -0,0 +1,180 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const (
+	// probeTimeout is the time that a candidate node has to answer the probe.
+	probeTimeout = 5 * time.Second
+
+	// maxBehind is how many blocks a candidate node can be behind the nodes of the manager and still be added.
+	maxBehind = 3
+)
+
+// Dialer creates the client of a candidate endpoint, like the pool of the connections.
+type Dialer func(endpoint string) (client.IClient, error)
+
+// Discovery resolves the DNS seeds to the candidate RPC nodes, probes them and adds the healthy ones
+// to the client manager, so the list of the nodes doesn't need to be kept by hand.
+type Discovery struct {
+	lock      sync.Mutex
+	clientMgr *client.Mgr
+	dial      Dialer
+	seeds     []string // Like: "seed.example.org" or "seed.example.org:50052".
+	port      int      // The RPC port of the seeds without a port.
+	weight    int      // The weight of the added nodes, zero only uses them for the network and peer info.
+	maxNodes  int
+	interval  time.Duration
+	added     []string // The endpoints of the added nodes, the unhealthy ones are removed.
+	lookup    func(ctx context.Context, host string) ([]string, error)
+}
+
+// NewDiscovery creates the discovery of the RPC nodes, no seed or zero nodes disables it.
+func NewDiscovery(clientMgr *client.Mgr, dial Dialer, seeds []string, port, weight, maxNodes int,
+	interval time.Duration,
+) *Discovery {
+	return &Discovery{
+		clientMgr: clientMgr,
+		dial:      dial,
+		seeds:     seeds,
+		port:      port,
+		weight:    weight,
+		maxNodes:  maxNodes,
+		interval:  interval,
+		lookup:    net.DefaultResolver.LookupHost,
+	}
+}
+
+// Job returns the scheduler job of the discovery. It's not exclusive, each instance has its own nodes.
+func (d *Discovery) Job() scheduler.Job {
+	interval := d.interval
+	if len(d.seeds) == 0 || d.maxNodes <= 0 {
+		interval = 0
+	}
+
+	return scheduler.Job{
+		Name:      "discovery",
+		Interval:  interval,
+		Exclusive: false,
+		Run:       d.Run,
+	}
+}
+
+// Run removes the added nodes that are not healthy anymore, then resolves the seeds and adds the healthy
+// candidates that the manager doesn't have, up to the maximum nodes.
+// A seed that can't be resolved is skipped, the next run tries it again.
+func (d *Discovery) Run(ctx context.Context) error {
+	d.evict(ctx)
+
+	height, err := d.clientMgr.GetBlockchainHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, seed := range d.seeds {
+		host, port := seed, strconv.Itoa(d.port)
+		if h, p, err := net.SplitHostPort(seed); err == nil {
+			host, port = h, p
+		}
+
+		addrs, err := d.lookup(ctx, host)
+		if err != nil {
+			log.Warn("can't resolve the DNS seed", "err", err, "seed", seed)
+
+			continue
+		}
+
+		for _, addr := range addrs {
+			if d.full() {
+				return nil
+			}
+
+			endpoint := net.JoinHostPort(addr, port)
+			if d.clientMgr.HasNode(endpoint) {
+				continue
+			}
+
+			if d.probe(ctx, endpoint, height) {
+				d.lock.Lock()
+				d.added = append(d.added, endpoint)
+				d.lock.Unlock()
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *Discovery) full() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return len(d.added) >= d.maxNodes
+}
+
+// evict removes the added nodes that are unreachable or behind the other nodes, and closes them.
+// A removed node is a candidate again, it's added back when it's healthy.
+func (d *Discovery) evict(ctx context.Context) {
+	d.lock.Lock()
+	added := slices.Clone(d.added)
+	d.lock.Unlock()
+
+	if len(added) == 0 {
+		return
+	}
+
+	for _, state := range d.clientMgr.Nodes(ctx) {
+		if !slices.Contains(added, state.Node) ||
+			(state.Health != client.NodeUnreachable && state.Health != client.NodeBehind) {
+			continue
+		}
+
+		c, ok := d.clientMgr.RemoveClient(state.Node)
+		if !ok {
+			continue
+		}
+		_ = c.Close()
+
+		d.lock.Lock()
+		d.added = slices.DeleteFunc(d.added, func(endpoint string) bool { return endpoint == state.Node })
+		d.lock.Unlock()
+
+		log.Info("discovered node client removed", "addr", state.Node, "health", state.Health, "err", state.Err)
+	}
+}
+
+// probe adds the candidate if it answers in the probe timeout and it's not behind the height of the manager.
+func (d *Discovery) probe(ctx context.Context, endpoint string, height uint32) bool {
+	c, err := d.dial(endpoint)
+	if err != nil {
+		log.Debug("can't dial the discovered node", "err", err, "addr", endpoint)
+
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	candidateHeight, err := c.GetBlockchainHeight(ctx)
+	if err != nil || candidateHeight+maxBehind < height {
+		log.Debug("the discovered node is not healthy", "err", err, "addr", endpoint, "height", candidateHeight)
+		_ = c.Close()
+
+		return false
+	}
+
+	d.clientMgr.AddWeightedClient(c, d.weight)
+	log.Info("discovered node client added", "addr", endpoint, "weight", d.weight, "height", candidateHeight)
+
+	return true
+}
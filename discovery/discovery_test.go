@@ -0,0 +1,97 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDiscovery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	local := client.NewMockIClient(ctrl)
+	local.EXPECT().Target().Return("10.0.0.1:50051").AnyTimes()
+	local.EXPECT().GetBlockchainHeight(gomock.Any()).Return(uint32(100), nil).AnyTimes()
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(local)
+
+	// the heights of the candidates, zero is unreachable.
+	heights := map[string]uint32{
+		"10.0.0.2:50051": 99,
+		"10.0.0.3:50051": 90,
+		"10.0.0.4:50051": 0,
+		"10.0.0.5:50052": 101,
+		"10.0.0.6:50052": 100,
+	}
+	closed := make(map[string]bool)
+	dial := func(endpoint string) (client.IClient, error) {
+		if _, ok := heights[endpoint]; !ok {
+			return nil, errors.New("unknown endpoint")
+		}
+
+		c := client.NewMockIClient(ctrl)
+		c.EXPECT().Target().Return(endpoint).AnyTimes()
+		c.EXPECT().GetBlockchainHeight(gomock.Any()).DoAndReturn(func(_ context.Context) (uint32, error) {
+			height := heights[endpoint]
+			if height == 0 {
+				return 0, errors.New("connection refused")
+			}
+
+			return height, nil
+		}).AnyTimes()
+		c.EXPECT().Close().DoAndReturn(func() error {
+			closed[endpoint] = true
+
+			return nil
+		}).AnyTimes()
+
+		return c, nil
+	}
+
+	d := NewDiscovery(cm, dial, []string{"seed1.example.org", "unknown.example.org", "seed2.example.org:50052"},
+		50051, 1, 3, time.Hour)
+	d.lookup = func(_ context.Context, host string) ([]string, error) {
+		switch host {
+		case "seed1.example.org":
+			return []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}, nil
+		case "seed2.example.org":
+			return []string{"10.0.0.5", "10.0.0.6"}, nil
+		default:
+			return nil, errors.New("no such host")
+		}
+	}
+
+	require.NoError(t, d.Run(context.Background()))
+	assert.True(t, cm.HasNode("10.0.0.2:50051"))
+	assert.False(t, cm.HasNode("10.0.0.3:50051"), "behind")
+	assert.True(t, closed["10.0.0.3:50051"])
+	assert.False(t, cm.HasNode("10.0.0.4:50051"), "unreachable")
+	assert.True(t, closed["10.0.0.4:50051"])
+	assert.True(t, cm.HasNode("10.0.0.5:50052"), "the port of the seed")
+	assert.True(t, cm.HasNode("10.0.0.6:50052"))
+
+	nodes := cm.Nodes(context.Background())
+	require.Len(t, nodes, 4, "the local node is not added again")
+	assert.Equal(t, 1, nodes[1].Weight)
+
+	heights["10.0.0.3:50051"] = 100
+	require.NoError(t, d.Run(context.Background()))
+	assert.False(t, cm.HasNode("10.0.0.3:50051"), "up to the maximum nodes")
+
+	heights["10.0.0.2:50051"] = 0
+	require.NoError(t, d.Run(context.Background()))
+	assert.False(t, cm.HasNode("10.0.0.2:50051"), "the unreachable node is removed")
+	assert.True(t, closed["10.0.0.2:50051"])
+	assert.True(t, cm.HasNode("10.0.0.3:50051"), "a healthy candidate takes its place")
+	assert.Len(t, cm.Nodes(context.Background()), 4)
+
+	assert.Zero(t, NewDiscovery(cm, dial, nil, 50051, 1, 3, time.Hour).Job().Interval, "no seed")
+	assert.Zero(t, NewDiscovery(cm, dial, d.seeds, 50051, 1, 0, time.Hour).Job().Interval, "no node")
+	assert.Equal(t, time.Hour, d.Job().Interval)
+}
@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/abuse"
+	"github.com/pagu-project/Pagu/backup"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command/network"
+	"github.com/pagu-project/Pagu/feature"
+	"github.com/pagu-project/Pagu/lock"
+	"github.com/pagu-project/Pagu/metrics"
+	"github.com/pagu-project/Pagu/settings"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// setupBenchEngine creates the engine with all the commands on a mocked node,
+// so the benchmarks measure the engine and not the network.
+func setupBenchEngine(b *testing.B) *BotEngine {
+	b.Helper()
+
+	// the production level, the debug logs of each command would be measured too.
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+
+	ctrl := gomock.NewController(b)
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().Target().Return("mock:50051").AnyTimes()
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{
+		LastBlockHeight: 1_500_000,
+		TotalAccounts:   60_000,
+		TotalValidators: 2_000,
+		TotalPower:      500_000_000_000_000,
+		CommitteePower:  20_000_000_000_000,
+	}, nil).AnyTimes()
+	c.EXPECT().GetNetworkInfo(gomock.Any()).Return(&pactus.GetNetworkInfoResponse{
+		NetworkName: "pactus",
+	}, nil).AnyTimes()
+	c.EXPECT().LastBlockTime(gomock.Any()).DoAndReturn(func(_ context.Context) (uint32, uint32, error) {
+		return uint32(time.Now().Unix()), 1_500_000, nil
+	}).AnyTimes()
+	c.EXPECT().GetBalance(gomock.Any(), gomock.Any()).Return(int64(1_000_000_000_000), nil).AnyTimes()
+	c.EXPECT().GetFee(gomock.Any(), gomock.Any()).Return(int64(10_000_000), nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.Cleanup(cancel)
+
+	cm := client.NewClientMgr(ctx)
+	cm.AddClient(c)
+
+	db, err := database.NewDB(filepath.Join(b.TempDir(), "pagu.db"))
+	require.NoError(b, err)
+
+	be := newBotEngine(cm, client.NewClientMgr(ctx), nil, nil, db, metrics.NewMetrics(),
+		network.ScoreRange{Min: 0.8, Max: 0.9}, nil, nil, backup.NewBackup(backup.Config{}, db),
+		lock.NewLocker(db, "bench"), feature.NewFlags(db), settings.NewSettings(db), nil,
+		abuse.Config{}, config.DefaultFaucetMemo, config.DefaultRewardMemo, 99, nil, ctx, cancel)
+	be.contexts = newContextStore(time.Minute)
+	be.RegisterAllCommands()
+
+	return be
+}
+
+// loadTraffic loads the traffic that is replayed, the PAGU_TRAFFIC file if it's set,
+// like the one that is recorded by TRAFFIC_RECORD_PATH.
+func loadTraffic(b *testing.B) []TrafficRecord {
+	b.Helper()
+
+	path := os.Getenv("PAGU_TRAFFIC")
+	if path == "" {
+		path = filepath.Join("testdata", "traffic.jsonl")
+	}
+
+	file, err := os.Open(path)
+	require.NoError(b, err)
+	defer file.Close()
+
+	records, err := ReadTraffic(file)
+	require.NoError(b, err)
+	require.NotEmpty(b, records, "the traffic is empty")
+
+	return records
+}
+
+// BenchmarkReplay replays the traffic one command at a time, run it with -benchmem and -memprofile
+// for the allocation profiles, like: make bench.
+func BenchmarkReplay(b *testing.B) {
+	be := setupBenchEngine(b)
+	records := loadTraffic(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r := records[i%len(records)]
+		be.Run(r.AppID, r.CallerID, r.Tokens)
+	}
+
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "cmds/s")
+}
+
+// BenchmarkReplayParallel replays the traffic from GOMAXPROCS goroutines, like the platforms do.
+func BenchmarkReplayParallel(b *testing.B) {
+	be := setupBenchEngine(b)
+	records := loadTraffic(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			r := records[i%len(records)]
+			be.Run(r.AppID, r.CallerID, r.Tokens)
+			i++
+		}
+	})
+
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "cmds/s")
+}
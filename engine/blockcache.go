@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pagu-project/Pagu/cache"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const (
+	// heightInterval is how often the last block height is observed, shorter than the block time of 10 seconds.
+	heightInterval = 3 * time.Second
+
+	// resultTTL removes the results of the old blocks, a new block has another key anyway.
+	resultTTL = 5 * time.Minute
+)
+
+// blockCache keeps the results of the commands that only change per block, like the network status.
+// The results are keyed by the last observed block height, so a block has one result and a new block has a new one.
+type blockCache struct {
+	store  cache.Store
+	height func(ctx context.Context) (uint32, error)
+	last   atomic.Uint32
+}
+
+func newBlockCache(store cache.Store, height func(ctx context.Context) (uint32, error)) *blockCache {
+	return &blockCache{
+		store:  store,
+		height: height,
+	}
+}
+
+// Job returns the scheduler job that observes the block height. It's not exclusive, each instance observes it,
+// and it runs in the maintenance too, where the admins can run the commands.
+func (c *blockCache) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:       "block-height",
+		Interval:   heightInterval,
+		Exclusive:  false,
+		RunsPaused: true,
+		Run:        c.Observe,
+	}
+}
+
+// Observe observes the last block height, a lower height of a lagging node doesn't replace it.
+func (c *blockCache) Observe(ctx context.Context) error {
+	height, err := c.height(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		last := c.last.Load()
+		if height <= last || c.last.CompareAndSwap(last, height) {
+			return nil
+		}
+	}
+}
+
+// Result returns the cached result of the command in the last observed block, otherwise it runs the handler
+// and caches the successful result. The commands that are not per block are not cached,
+// neither before a height is observed. The results are keyed by the arguments, and by the caller too
+// with the arguments of the caller, like the diff of the network status since the last check of the caller.
+func (c *blockCache) Result(ctx context.Context, cmd command.Command, appID command.AppID, callerID string,
+	path, args []string, handle func() command.CommandResult,
+) command.CommandResult {
+	if c == nil || !cmd.PerBlock {
+		return handle()
+	}
+
+	height := c.last.Load()
+	if height == 0 {
+		return handle()
+	}
+
+	// the templates are rendered for the platform, each platform has its own result.
+	key := "result:" + strconv.Itoa(int(appID)) + ":" + strconv.FormatUint(uint64(height), 10) + ":" +
+		strings.Join(slices.Concat(path, args), " ")
+	if slices.ContainsFunc(args, func(arg string) bool { return slices.Contains(cmd.CallerArgs, arg) }) {
+		key += ":" + callerID
+	}
+
+	data, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		log.Ctx(ctx).Warn("can't read the cached result", "key", key, "err", err)
+	} else if ok {
+		var res command.CommandResult
+		if json.Unmarshal(data, &res) == nil {
+			return res
+		}
+	}
+
+	res := handle()
+	if !res.Successful {
+		return res
+	}
+
+	data, err = json.Marshal(res)
+	if err == nil {
+		err = c.store.Set(ctx, key, data, resultTTL)
+	}
+
+	if err != nil {
+		log.Ctx(ctx).Warn("can't cache the result", "key", key, "err", err)
+	}
+
+	return res
+}
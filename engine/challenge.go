@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/pagu-project/Pagu/challenge"
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+const VerifyCommandName = "verify"
+
+// verifyCommand returns the command that answers the pending challenge and runs the challenged command.
+func (be *BotEngine) verifyCommand() command.Command {
+	return command.Command{
+		Emoji: "🔒",
+		Name:  VerifyCommandName,
+		Desc:  "Answer the verification challenge",
+		Help:  "Send the answer of the challenge that is asked before some commands, like the faucet",
+		Args: []command.Args{
+			{
+				Name:     "answer",
+				Desc:     "Answer of the challenge or the captcha response token",
+				Optional: false,
+			},
+		},
+		AppIDs: []command.AppID{
+			command.AppIdDiscord,
+			command.AppIdgRPC,
+			command.AppIdHTTP,
+			command.AppIdTelegram,
+			command.AppIdQueue,
+		},
+		Handler: be.verifyHandler,
+	}
+}
+
+func (be *BotEngine) verifyHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	tokens, err := be.challenges.Verify(appID, callerID, args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	// the command is counted in the quota of the guild before the challenge.
+	return be.run(be.context(), appID, "", callerID, tokens)
+}
+
+// challengeResult asks the caller to pass a challenge before running the command tokens.
+// It returns false if the caller is already verified, so the command can run.
+func (be *BotEngine) challengeResult(cmd command.Command, appID command.AppID, callerID string,
+	tokens []string,
+) (command.CommandResult, bool) {
+	// CLI is run by the operator.
+	if appID == command.AppIdCLI || be.challenges.Consume(appID, callerID) {
+		return command.CommandResult{}, false
+	}
+
+	ch := be.challenges.Issue(appID, callerID, tokens)
+	res := cmd.RenderResult(appID, "challenge_"+ch.Kind, ch)
+	res.Successful = false
+
+	return res, true
+}
+
+// newChallengeManager creates the challenge manager, HTTP callers get hCaptcha if it's configured
+// and the others get a math question in the chat.
+func newChallengeManager(cfg config.Challenge) *challenge.Manager {
+	mgr := challenge.NewManager(cfg.TTL, challenge.Math{})
+	if cfg.HCaptchaSiteKey != "" && cfg.HCaptchaSecret != "" {
+		mgr.SetProvider(command.AppIdHTTP, challenge.NewHCaptcha(cfg.HCaptchaSiteKey, cfg.HCaptchaSecret))
+	}
+
+	return mgr
+}
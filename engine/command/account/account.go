@@ -0,0 +1,187 @@
+package account
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/indexer"
+)
+
+const (
+	CommandName         = "account"
+	ActivityCommandName = "activity"
+	VerifyCommandName   = "verify"
+	HelpCommandName     = "help"
+)
+
+const (
+	defaultActivityCount = 10
+	maxActivityCount     = 25
+)
+
+type Account struct {
+	clientMgr *client.Mgr
+	indexer   *indexer.Indexer
+}
+
+func NewAccount(clientMgr *client.Mgr, idx *indexer.Indexer) Account {
+	return Account{
+		clientMgr: clientMgr,
+		indexer:   idx,
+	}
+}
+
+// Activity is a transaction in the activity of an account.
+type Activity struct {
+	TxID         string
+	Height       uint32
+	Type         string
+	Incoming     bool
+	Counterparty string
+	Amount       amount.Amount
+}
+
+func (a *Account) GetCommand() command.Command {
+	subCmdActivity := command.Command{
+		Name: ActivityCommandName,
+		Desc: "Last transactions of an address",
+		Help: "Shows the transfers, bonds, unbonds and withdraws of the address since the bot started indexing them. " +
+			"Use the page to see the older ones",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "Account or validator address [example: pc1z...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+			{
+				Name:     "count",
+				Desc:     "Transactions in a page, 10 by default and 25 at most",
+				Optional: true,
+			},
+			{
+				Name:     "page",
+				Desc:     "Page number, 1 by default",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1z...", "pc1z... 5 2"},
+		Handler:     a.activityHandler,
+	}
+
+	subCmdVerify := command.Command{
+		Name: VerifyCommandName,
+		Desc: "Verify a signed message of an address",
+		Help: "Confirms that the owner of the address signed the message, like in the reward campaigns. " +
+			"The public key of the address is on the chain after its first transaction. " +
+			"The signature comes before the message, so the message is the rest of the words without quotes, " +
+			"separated by one space",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "Account or validator address [example: pc1z...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+			{
+				Name:     "signature",
+				Desc:     "Signature of the message in hex",
+				Optional: false,
+			},
+			{
+				Name:     "message",
+				Desc:     "The signed message [example: I own this address]",
+				Optional: false,
+				Variadic: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1z... 8a3f... I own this address"},
+		Handler:     a.verifyHandler,
+	}
+
+	cmdAccount := command.Command{
+		Emoji:       "👤",
+		Name:        CommandName,
+		Desc:        "Account information",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdAccount.AddSubCommand(subCmdActivity)
+	cmdAccount.AddSubCommand(subCmdVerify)
+
+	return cmdAccount
+}
+
+func (a *Account) activityHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	addr, err := crypto.AddressFromString(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	count := defaultActivityCount
+	if len(args) > 1 {
+		count, err = strconv.Atoi(args[1])
+		if err != nil || count < 1 || count > maxActivityCount {
+			return cmd.FailedResult("count should be a number from 1 to %d", maxActivityCount)
+		}
+	}
+
+	page := 1
+	if len(args) > 2 {
+		page, err = strconv.Atoi(args[2])
+		if err != nil || page < 1 {
+			return cmd.FailedResult("page should be a positive number")
+		}
+	}
+
+	txs, err := a.indexer.Activity(addr.String(), (page-1)*count, count)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	activities := make([]Activity, 0, len(txs))
+	for _, tx := range txs {
+		activities = append(activities, Activity{
+			TxID:         tx.TxID,
+			Height:       tx.Height,
+			Type:         tx.Type,
+			Incoming:     tx.Direction == database.TxIncoming,
+			Counterparty: tx.Counterparty,
+			Amount:       amount.Amount(tx.Amount),
+		})
+	}
+
+	return cmd.RenderResult(appID, "account_activity", map[string]any{
+		"Address":    addr.String(),
+		"Page":       page,
+		"Activities": activities,
+	})
+}
+
+// verifyHandler verifies the signature of the message with the public key of the address on the chain.
+func (a *Account) verifyHandler(ctx context.Context, cmd command.Command, _ command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	msg := strings.Join(args[2:], " ")
+	if err := VerifyMessage(ctx, a.clientMgr, args[0], args[1], msg); err != nil {
+		return cmd.FailedResult("%v", err)
+	}
+
+	return cmd.SuccessfulResult("The signature is valid, %s signed the message: %s", args[0], msg)
+}
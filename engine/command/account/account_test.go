@@ -0,0 +1,100 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pactus-project/pactus/util/testsuite"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/indexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestActivity(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	alice := ts.RandAccAddress().String()
+	bob := ts.RandAccAddress().String()
+	require.NoError(t, db.AddAccountTransactions([]*database.AccountTransaction{
+		{TxID: "aa", Address: alice, Height: 10, Type: "transfer", Direction: database.TxOutgoing, Counterparty: bob, Amount: 5e9},
+		{TxID: "bb", Address: alice, Height: 12, Type: "transfer", Direction: database.TxIncoming, Amount: 1e9},
+	}))
+
+	a := NewAccount(nil, indexer.NewIndexer(nil, db))
+	cmd := a.GetCommand()
+
+	res := a.activityHandler(context.Background(), cmd, command.AppIdCLI, "", "invalid")
+	assert.False(t, res.Successful)
+
+	res = a.activityHandler(context.Background(), cmd, command.AppIdCLI, "", alice, "100")
+	assert.False(t, res.Successful)
+
+	res = a.activityHandler(context.Background(), cmd, command.AppIdCLI, "", alice)
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "Out: transfer of 5 PAC to "+bob)
+	assert.Contains(t, res.Message, "In: transfer of 1 PAC (block reward)")
+	assert.Contains(t, res.Message, "https://pacviewer.com/block/12")
+	assert.Less(t, strings.Index(res.Message, "block/12"), strings.Index(res.Message, "block/10"), "the newest first")
+
+	res = a.activityHandler(context.Background(), cmd, command.AppIdCLI, "", alice, "1", "2")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "(page 2)")
+	assert.Contains(t, res.Message, "https://pacviewer.com/transaction/aa")
+	assert.NotContains(t, res.Message, "transaction/bb")
+
+	res = a.activityHandler(context.Background(), cmd, command.AppIdCLI, "", alice, "1", "3")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "the page 3 is empty")
+
+	res = a.activityHandler(context.Background(), cmd, command.AppIdCLI, "", bob)
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "No transactions found")
+}
+
+func TestVerify(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	a := NewAccount(cm, nil)
+	cmd := a.GetCommand()
+
+	pub, prv := ts.RandBLSKeyPair()
+	addr := pub.AccountAddress().String()
+	sig := prv.Sign([]byte("I own this address")).String()
+	c.EXPECT().GetPublicKey(gomock.Any(), addr).Return(pub.String(), nil).AnyTimes()
+
+	res := a.verifyHandler(context.Background(), cmd, command.AppIdCLI, "", addr, sig, "I", "own", "this", "address")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "The signature is valid, "+addr+" signed the message: I own this address", res.Message)
+
+	res = a.verifyHandler(context.Background(), cmd, command.AppIdCLI, "", addr, sig, "I", "own", "that", "address")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "not valid")
+
+	res = a.verifyHandler(context.Background(), cmd, command.AppIdCLI, "", addr, "hi", "I", "own", "this", "address")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "is not a signature")
+
+	otherPub, _ := ts.RandBLSKeyPair()
+	other := otherPub.AccountAddress().String()
+	c.EXPECT().GetPublicKey(gomock.Any(), other).Return("", errors.New("public key not found"))
+
+	res = a.verifyHandler(context.Background(), cmd, command.AppIdCLI, "", other, sig, "I", "own", "this", "address")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "is not on the chain")
+}
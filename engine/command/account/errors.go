@@ -0,0 +1,28 @@
+package account
+
+import "fmt"
+
+type SignatureFormatError struct {
+	Signature string
+}
+
+func (e SignatureFormatError) Error() string {
+	return fmt.Sprintf("%s is not a signature, it's the hex of a BLS signature", e.Signature)
+}
+
+type PublicKeyNotFoundError struct {
+	Address string
+}
+
+func (e PublicKeyNotFoundError) Error() string {
+	return fmt.Sprintf("the public key of %s is not on the chain, it's known after the first transaction of the address",
+		e.Address)
+}
+
+type InvalidSignatureError struct {
+	Address string
+}
+
+func (e InvalidSignatureError) Error() string {
+	return fmt.Sprintf("the signature is not valid, %s didn't sign the message", e.Address)
+}
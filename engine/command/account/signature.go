@@ -0,0 +1,43 @@
+package account
+
+import (
+	"context"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/crypto/bls"
+	"github.com/pagu-project/Pagu/client"
+)
+
+// VerifyMessage verifies the signature of the message with the public key of the address on the chain,
+// like the messages that the wallets sign to prove the ownership of the address.
+func VerifyMessage(ctx context.Context, cm *client.Mgr, address, signature, msg string) error {
+	addr, err := crypto.AddressFromString(address)
+	if err != nil {
+		return err
+	}
+
+	sig, err := bls.SignatureFromString(signature)
+	if err != nil {
+		return SignatureFormatError{Signature: signature}
+	}
+
+	pubStr, err := cm.GetPublicKey(ctx, addr.String())
+	if err != nil {
+		return PublicKeyNotFoundError{Address: addr.String()}
+	}
+
+	pub, err := bls.PublicKeyFromString(pubStr)
+	if err != nil {
+		return err
+	}
+
+	if err := pub.VerifyAddress(addr); err != nil {
+		return err
+	}
+
+	if err := pub.Verify([]byte(msg), sig); err != nil {
+		return InvalidSignatureError{Address: addr.String()}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package command
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/util/bech32m"
+)
+
+const (
+	MainnetHRP = "pc"
+	TestnetHRP = "tpc"
+)
+
+// addressPattern finds the addresses of the outputs, the code is matched first so the addresses in it are kept,
+// like an address in a message to sign.
+var addressPattern = regexp.MustCompile("(```[\\s\\S]*?```|`[^`\n]*`)|\\b(t?pc1[02-9ac-hj-np-z]{38,})\\b")
+
+// FindAddresses returns the addresses of the message out of the code, without the repeated ones.
+func FindAddresses(msg string) []string {
+	addresses := make([]string, 0)
+	for _, groups := range addressPattern.FindAllStringSubmatch(msg, -1) {
+		if groups[2] != "" && !slices.Contains(addresses, groups[2]) {
+			addresses = append(addresses, groups[2])
+		}
+	}
+
+	return addresses
+}
+
+// AppendAliases appends the alias of each address of the message after it, like: "pc1p... (Pactus Foundation)".
+func AppendAliases(msg string, names map[string]string) string {
+	if len(names) == 0 {
+		return msg
+	}
+
+	return addressPattern.ReplaceAllStringFunc(msg, func(match string) string {
+		if name, ok := names[match]; ok {
+			return match + " (" + name + ")"
+		}
+
+		return match
+	})
+}
+
+// NetworkName returns the name of the network of the address HRP, like: "Mainnet" for "pc".
+func NetworkName(hrp string) string {
+	switch hrp {
+	case MainnetHRP:
+		return "Mainnet"
+	case TestnetHRP:
+		return "Testnet"
+	}
+
+	return hrp
+}
+
+// addressHRP returns the HRP of the addresses of the command, the Testnet commands like Phoenix use the
+// Testnet addresses and the others use the addresses of the network that Pagu runs on.
+func (cmd *Command) addressHRP() string {
+	if cmd.Testnet {
+		return TestnetHRP
+	}
+
+	return crypto.AddressHRP
+}
+
+// CheckAddresses checks the address arguments, like: "pc1p...", before the handler calls the nodes.
+// The addresses of the list arguments are checked one by one, the other arguments are not checked,
+// like a feedback that names an address.
+// A Testnet address for a Mainnet command, or the other way, is a NetworkError that names the correct network.
+func (cmd *Command) CheckAddresses(args []string) error {
+	addresses := make([]string, 0, len(args))
+	for i, arg := range args {
+		switch cmd.argKind(i) {
+		case ArgAddress:
+			addresses = append(addresses, arg)
+		case ArgList:
+			addresses = append(addresses, SplitList([]string{arg})...)
+		case ArgText, ArgHex:
+		}
+	}
+
+	want := cmd.addressHRP()
+	for _, arg := range addresses {
+		hrp, ok := addressPrefix(arg)
+		if !ok {
+			continue
+		}
+
+		if hrp != want {
+			return NetworkError{Address: arg, Network: NetworkName(hrp), Want: NetworkName(want)}
+		}
+
+		if _, _, _, err := bech32m.DecodeToBase256WithTypeNoLimit(arg); err != nil {
+			return AddressError{Address: arg}
+		}
+	}
+
+	return nil
+}
+
+// IsValidatorAddress returns true if the argument is a validator address of any network, like: "tpc1p...".
+func IsValidatorAddress(arg string) bool {
+	_, typ, _, err := bech32m.DecodeToBase256WithTypeNoLimit(arg)
+
+	return err == nil && crypto.AddressType(typ) == crypto.AddressTypeValidator
+}
+
+// addressPrefix returns the HRP of an argument that starts like an address of the networks, like: "tpc1".
+func addressPrefix(arg string) (string, bool) {
+	lower := strings.ToLower(arg)
+	for _, hrp := range []string{TestnetHRP, MainnetHRP} {
+		if strings.HasPrefix(lower, hrp+"1") {
+			return hrp, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/util/bech32m"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAddresses(t *testing.T) {
+	addr := crypto.NewAddress(crypto.AddressTypeValidator, make([]byte, 20))
+	mainnet := addr.String()
+	testnet, err := bech32m.EncodeFromBase256WithType(TestnetHRP, byte(crypto.AddressTypeValidator), make([]byte, 20))
+	require.NoError(t, err)
+
+	cmd := Command{Name: "node-info", Args: []Args{{Name: "validator", Kind: ArgAddress}, {Name: "period"}}}
+	assert.NoError(t, cmd.CheckAddresses([]string{mainnet}))
+	assert.NoError(t, cmd.CheckAddresses([]string{"12", "day"}), "not an address")
+	assert.NoError(t, cmd.CheckAddresses([]string{mainnet, testnet}), "not an address argument")
+	assert.Equal(t, NetworkError{Address: testnet, Network: "Testnet", Want: "Mainnet"},
+		cmd.CheckAddresses([]string{testnet}))
+	assert.Equal(t, AddressError{Address: mainnet[:len(mainnet)-1] + "x"},
+		cmd.CheckAddresses([]string{mainnet[:len(mainnet)-1] + "x"}), "the checksum fails")
+
+	phoenix := Command{Name: "phoenix", Testnet: true, SubCommands: make([]Command, 0)}
+	phoenix.AddSubCommand(Command{Name: "faucet", Args: []Args{{Name: "address", Kind: ArgAddress}}})
+	faucet := phoenix.SubCommands[0]
+	assert.True(t, faucet.Testnet)
+	assert.NoError(t, faucet.CheckAddresses([]string{testnet}))
+	err = faucet.CheckAddresses([]string{mainnet})
+	assert.Equal(t, NetworkError{Address: mainnet, Network: "Mainnet", Want: "Testnet"}, err)
+	assert.Contains(t, err.Error(), "needs a Testnet address")
+
+	bulk := Command{Name: "bulk", Args: []Args{{Name: "validators", Kind: ArgList, Variadic: true}}}
+	assert.NoError(t, bulk.CheckAddresses([]string{mainnet + "," + mainnet, "42"}), "the list is split first")
+	assert.Equal(t, NetworkError{Address: testnet, Network: "Testnet", Want: "Mainnet"},
+		bulk.CheckAddresses([]string{mainnet + "," + testnet}))
+
+	feedback := Command{Name: "feedback", Args: []Args{{Name: "message", Variadic: true}}}
+	assert.NoError(t, feedback.CheckAddresses([]string{"pc1", "is", "broken"}), "a free text")
+}
+
+func TestIsValidatorAddress(t *testing.T) {
+	testnet, err := bech32m.EncodeFromBase256WithType(TestnetHRP, byte(crypto.AddressTypeValidator), make([]byte, 20))
+	require.NoError(t, err)
+
+	assert.True(t, IsValidatorAddress(crypto.NewAddress(crypto.AddressTypeValidator, make([]byte, 20)).String()))
+	assert.True(t, IsValidatorAddress(testnet), "any network")
+	assert.False(t, IsValidatorAddress(crypto.NewAddress(crypto.AddressTypeBLSAccount, make([]byte, 20)).String()))
+	assert.False(t, IsValidatorAddress("tpc1zinvalid"))
+}
+
+func TestAppendAliases(t *testing.T) {
+	addr := crypto.NewAddress(crypto.AddressTypeValidator, make([]byte, 20)).String()
+	names := map[string]string{addr: "Pactus Lovers"}
+
+	msg := "Validator " + addr + " is online, sign `Link " + addr + " to Pagu`"
+	assert.Equal(t, []string{addr}, FindAddresses(msg+" "+addr))
+	assert.Equal(t, "Validator "+addr+" (Pactus Lovers) is online, sign `Link "+addr+" to Pagu`",
+		AppendAliases(msg, names), "the code is kept")
+	assert.Empty(t, FindAddresses("`"+addr+"`"))
+	assert.Equal(t, msg, AppendAliases(msg, nil))
+}
+
+func FuzzCheckAddresses(f *testing.F) {
+	f.Add(crypto.NewAddress(crypto.AddressTypeValidator, make([]byte, 20)).String())
+	f.Add("tpc1zqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq")
+	f.Add("PC1P")
+	f.Add("pc1")
+	f.Add("tpc1\xff")
+
+	f.Fuzz(func(t *testing.T, arg string) {
+		cmd := Command{Name: "fuzz"}
+		err := cmd.CheckAddresses([]string{arg})
+		if IsValidatorAddress(arg) && strings.HasPrefix(arg, crypto.AddressHRP+"1") && err != nil {
+			t.Fatalf("the validator address %q is rejected: %v", arg, err)
+		}
+
+		FindAddresses(arg)
+	})
+}
@@ -0,0 +1,769 @@
+package admin
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pagu-project/Pagu/backup"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/feature"
+	"github.com/pagu-project/Pagu/maintenance"
+	"github.com/pagu-project/Pagu/metrics"
+	"github.com/pagu-project/Pagu/settings"
+)
+
+const (
+	CommandName             = "admin"
+	SLOCommandName          = "slo"
+	DeprecationsCommandName = "deprecations"
+	BackupCommandName       = "backup"
+	BackupNowCommandName    = "now"
+	FeatureCommandName      = "feature"
+	EnableCommandName       = "enable"
+	DisableCommandName      = "disable"
+	ResetCommandName        = "reset"
+	ListCommandName         = "list"
+	NodesCommandName        = "nodes"
+	StatusCommandName       = "status"
+	DrainCommandName        = "drain"
+	RestoreCommandName      = "restore"
+	SettingsCommandName     = "settings"
+	PrefixCommandName       = "prefix"
+	ReadOnlyCommandName     = "read-only"
+	TopicCommandName        = "topic"
+	MaintenanceCommandName  = "maintenance"
+	SetupCommandName        = "setup"
+	HelpCommandName         = "help"
+)
+
+// nodeStatsWindow is the window of the rolling latency and error rate of the nodes.
+const nodeStatsWindow = time.Hour
+
+type Admin struct {
+	clientMgr  *client.Mgr
+	metrics    *metrics.Metrics
+	sloTarget  float64
+	backup     *backup.Backup
+	features   *feature.Flags
+	settings   *settings.Settings
+	maint      *maintenance.Maintenance
+	groups     func() []string                  // The command groups of the bot, for the setup of the servers.
+	deprecated func() (map[string]int64, error) // The calls to the deprecated commands, nil reads the metrics.
+	now        func() time.Time
+
+	// channels checks that the channel is in the server, for the setup of the servers.
+	channels func(appID command.AppID, server, channel string) bool
+}
+
+func NewAdmin(cm *client.Mgr, mtr *metrics.Metrics, sloTarget float64, bkp *backup.Backup,
+	features *feature.Flags, setts *settings.Settings, maint *maintenance.Maintenance,
+) Admin {
+	return Admin{
+		clientMgr: cm,
+		metrics:   mtr,
+		sloTarget: sloTarget,
+		backup:    bkp,
+		features:  features,
+		settings:  setts,
+		maint:     maint,
+		now:       time.Now,
+	}
+}
+
+// SLOReport is the service level of the bot in a time window.
+type SLOReport struct {
+	Window                string
+	Commands              metrics.Stats
+	RPC                   metrics.Stats
+	CommandSuccessRate    float64
+	RPCSuccessRate        float64
+	CommandBudgetConsumed float64
+	RPCBudgetConsumed     float64
+	Nodes                 []NodeReport
+}
+
+// NodeReport is the service level of an RPC node, to compare the nodes while shifting the traffic.
+type NodeReport struct {
+	Node        string
+	Stats       metrics.Stats
+	SuccessRate float64
+}
+
+func (a *Admin) GetCommand() command.Command {
+	subCmdSLO := command.Command{
+		Name:        SLOCommandName,
+		Desc:        "Service level report of the last 24 hours and 7 days",
+		Help:        "Shows the command success rate, handler latency and the consumed error budget of commands and RPC calls, per RPC node as well",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     a.sloHandler,
+	}
+
+	subCmdDeprecations := command.Command{
+		Name:        DeprecationsCommandName,
+		Desc:        "Usage of the deprecated commands",
+		Help:        "Shows how many times each deprecated command is called, to know when it's safe to remove it",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     a.deprecationsHandler,
+	}
+
+	subCmdBackupNow := command.Command{
+		Name:        BackupNowCommandName,
+		Desc:        "Take a database backup now",
+		Help:        "Takes a backup besides the scheduled ones, like before an upgrade. See the README to restore it",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     a.backupNowHandler,
+	}
+
+	subCmdBackup := command.Command{
+		Name:        BackupCommandName,
+		Desc:        "Database backups",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	subCmdBackup.AddSubCommand(subCmdBackupNow)
+
+	featureArgs := []command.Args{
+		{
+			Name:     "name",
+			Desc:     "Command path joined by dots or an experimental feature [example: network.map]",
+			Optional: false,
+		},
+	}
+
+	subCmdFeatureEnable := command.Command{
+		Name:        EnableCommandName,
+		Desc:        "Enable a feature",
+		Help:        "Enables a command or an experimental feature on all the instances",
+		Args:        featureArgs,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"network.map"},
+		Handler:     a.featureEnableHandler,
+	}
+
+	subCmdFeatureDisable := command.Command{
+		Name:        DisableCommandName,
+		Desc:        "Disable a feature",
+		Help:        "Disables a command or an experimental feature on all the instances, a disabled group disables its sub commands",
+		Args:        featureArgs,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"network.map"},
+		Handler:     a.featureDisableHandler,
+	}
+
+	subCmdFeatureReset := command.Command{
+		Name:        ResetCommandName,
+		Desc:        "Reset a feature to its default",
+		Help:        "Removes the flag of the feature, the commands are enabled by default",
+		Args:        featureArgs,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"network.map"},
+		Handler:     a.featureResetHandler,
+	}
+
+	subCmdFeatureList := command.Command{
+		Name:        ListCommandName,
+		Desc:        "The feature flags",
+		Help:        "",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     a.featureListHandler,
+	}
+
+	subCmdFeature := command.Command{
+		Name:        FeatureCommandName,
+		Desc:        "Feature flags of the commands and the experimental features",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	subCmdFeature.AddSubCommand(subCmdFeatureEnable)
+	subCmdFeature.AddSubCommand(subCmdFeatureDisable)
+	subCmdFeature.AddSubCommand(subCmdFeatureReset)
+	subCmdFeature.AddSubCommand(subCmdFeatureList)
+
+	nodeArgs := []command.Args{
+		{
+			Name:     "node",
+			Desc:     "Number or address of the node in the nodes status [example: 2]",
+			Optional: false,
+		},
+	}
+
+	subCmdNodesStatus := command.Command{
+		Name:        StatusCommandName,
+		Desc:        "Status of the RPC nodes",
+		Help:        "Shows the height, the health and the latency and error rate of the last hour of each RPC node",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     a.nodesStatusHandler,
+	}
+
+	subCmdNodesDrain := command.Command{
+		Name:        DrainCommandName,
+		Desc:        "Stop sending the calls to a node",
+		Help:        "The calls are sent to the other nodes, like during the maintenance of the node. It's only on this instance",
+		Args:        nodeArgs,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"2", "node.example.com:50051"},
+		Handler:     a.nodesDrainHandler,
+	}
+
+	subCmdNodesRestore := command.Command{
+		Name:        RestoreCommandName,
+		Desc:        "Send the calls to a drained node again",
+		Help:        "",
+		Args:        nodeArgs,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"2", "node.example.com:50051"},
+		Handler:     a.nodesRestoreHandler,
+	}
+
+	subCmdNodes := command.Command{
+		Name:        NodesCommandName,
+		Desc:        "RPC nodes management",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	subCmdNodes.AddSubCommand(subCmdNodesStatus)
+	subCmdNodes.AddSubCommand(subCmdNodesDrain)
+	subCmdNodes.AddSubCommand(subCmdNodesRestore)
+
+	subCmdSettingsPrefix := command.Command{
+		Name: PrefixCommandName,
+		Desc: "Set the command prefix of a platform",
+		Help: "Sets the prefix of the commands in the messages, like: \"!pagu network status\", on all the instances. " +
+			"The server prefix is preferred to the platform one, \"reset\" removes the prefix",
+		Args: []command.Args{
+			{
+				Name:     "platform",
+				Desc:     "Platform of the prefix [example: discord]",
+				Optional: false,
+			},
+			{
+				Name:     "prefix",
+				Desc:     "Prefix of the commands or reset [example: !pagu]",
+				Optional: false,
+			},
+			{
+				Name:     "server",
+				Desc:     "ID of the server, like a Discord guild. Empty is the whole platform",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"discord !pagu", "discord .p 1234", "discord reset"},
+		Handler:     a.settingsPrefixHandler,
+	}
+
+	subCmdSettingsReadOnly := command.Command{
+		Name: ReadOnlyCommandName,
+		Desc: "Turn the read-only mode on or off",
+		Help: "Disables the state-changing commands, like the faucet and the payouts, on all the instances. " +
+			"The read commands keep working, like in an incident or a chain halt",
+		Args: []command.Args{
+			{
+				Name:     "state",
+				Desc:     "on, off or status [example: on]",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"on", "off", "status"},
+		Handler:     a.settingsReadOnlyHandler,
+	}
+
+	subCmdSettingsTopic := command.Command{
+		Name: TopicCommandName,
+		Desc: "Bind the command groups to a topic of a Telegram forum group",
+		Help: "The topic runs only its command groups, and the groups run only in their topics, " +
+			"like the faucet in the \"Testnet\" topic. The General topic is \"general\", \"reset\" removes the binding",
+		Args: []command.Args{
+			{
+				Name:     "group",
+				Desc:     "ID of the Telegram group [example: -1001234567890]",
+				Optional: false,
+			},
+			{
+				Name:     "topic",
+				Desc:     "ID of the topic, the ID in its link [example: 42]",
+				Optional: false,
+			},
+			{
+				Name:     "commands",
+				Desc:     "Comma separated command groups or reset [example: phoenix,network]",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples: []string{
+			"-1001234567890 42 phoenix",
+			"-1001234567890 general network,blockchain",
+			"-1001234567890 42 reset",
+		},
+		Handler: a.settingsTopicHandler,
+	}
+
+	subCmdSettings := command.Command{
+		Name:        SettingsCommandName,
+		Desc:        "Runtime settings of Pagu",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	subCmdSettings.AddSubCommand(subCmdSettingsPrefix)
+	subCmdSettings.AddSubCommand(subCmdSettingsReadOnly)
+	subCmdSettings.AddSubCommand(subCmdSettingsTopic)
+
+	subCmdMaintenance := command.Command{
+		Name: MaintenanceCommandName,
+		Desc: "Schedule a maintenance window",
+		Help: "The commands respond with a notice and the jobs are paused in the window on all the instances, " +
+			"they are resumed at the end. The operators are reminded before it. " +
+			"\"status\" shows the scheduled window and \"cancel\" removes it",
+		Args: []command.Args{
+			{
+				Name:     "start",
+				Desc:     "Start in UTC, now, status or cancel [example: 2024-07-01T10:00]",
+				Optional: false,
+			},
+			{
+				Name:     "duration",
+				Desc:     "Duration of the window, up to 24h [example: 30m]",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"2024-07-01T10:00 30m", "now 1h", "status", "cancel"},
+		Handler:     a.maintenanceHandler,
+	}
+
+	cmdAdmin := command.Command{
+		Emoji:       "🛠️",
+		Name:        CommandName,
+		Desc:        "Maintainer commands",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		AdminOnly:   true,
+		Handler:     nil,
+	}
+
+	cmdAdmin.AddSubCommand(subCmdSLO)
+	cmdAdmin.AddSubCommand(subCmdDeprecations)
+	cmdAdmin.AddSubCommand(subCmdBackup)
+	cmdAdmin.AddSubCommand(subCmdFeature)
+	cmdAdmin.AddSubCommand(subCmdNodes)
+	cmdAdmin.AddSubCommand(subCmdSettings)
+	cmdAdmin.AddSubCommand(subCmdMaintenance)
+	cmdAdmin.AddSubCommand(a.setupCommand())
+
+	return cmdAdmin
+}
+
+func (a *Admin) sloHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	errorCounts := a.metrics.ErrorCounts()
+	codes := make([]string, 0, len(errorCounts))
+	for code := range errorCounts {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	return cmd.RenderResult(appID, "admin_slo", map[string]any{
+		"Target":      a.sloTarget,
+		"Codes":       codes,
+		"ErrorCounts": errorCounts,
+		"Shards":      a.metrics.ShardHealth(),
+		"Reports": []SLOReport{
+			a.sloReport("24h", 24*time.Hour),
+			a.sloReport("7d", 7*24*time.Hour),
+		},
+	})
+}
+
+// SetDeprecatedCalls sets the function that returns the calls to each deprecated command, like from the database.
+func (a *Admin) SetDeprecatedCalls(calls func() (map[string]int64, error)) {
+	a.deprecated = calls
+}
+
+func (a *Admin) deprecationsHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	usage := a.metrics.DeprecatedUsage()
+	if a.deprecated != nil {
+		var err error
+		usage, err = a.deprecated()
+		if err != nil {
+			return cmd.ErrorResult(err)
+		}
+	}
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return cmd.RenderResult(appID, "admin_deprecations", map[string]any{
+		"Names": names,
+		"Usage": usage,
+	})
+}
+
+func (a *Admin) backupNowHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	path, err := a.backup.Now()
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "admin_backup", map[string]any{
+		"Path": path,
+	})
+}
+
+func (a *Admin) sloReport(name string, window time.Duration) SLOReport {
+	cmdStats := a.metrics.CommandStats(window)
+	rpcStats := a.metrics.RPCStats(window)
+
+	nodes := make([]NodeReport, 0)
+	for node, stats := range a.metrics.RPCNodeStats(window) {
+		if stats.Total == 0 {
+			continue
+		}
+
+		nodes = append(nodes, NodeReport{
+			Node:        node,
+			Stats:       stats,
+			SuccessRate: stats.SuccessRate(),
+		})
+	}
+
+	slices.SortFunc(nodes, func(a, b NodeReport) int {
+		return strings.Compare(a.Node, b.Node)
+	})
+
+	return SLOReport{
+		Window:                name,
+		Commands:              cmdStats,
+		RPC:                   rpcStats,
+		CommandSuccessRate:    cmdStats.SuccessRate(),
+		RPCSuccessRate:        rpcStats.SuccessRate(),
+		CommandBudgetConsumed: budgetConsumed(cmdStats, a.sloTarget),
+		RPCBudgetConsumed:     budgetConsumed(rpcStats, a.sloTarget),
+		Nodes:                 nodes,
+	}
+}
+
+// budgetConsumed returns the percentage of the error budget used by the failures,
+// the budget is the failure rate allowed by the target success rate.
+func budgetConsumed(stats metrics.Stats, target float64) float64 {
+	budget := 100 - target
+	if budget <= 0 {
+		if stats.Failed > 0 {
+			return 100
+		}
+
+		return 0
+	}
+
+	return (100 - stats.SuccessRate()) * 100 / budget
+}
+
+// NodeStatus is the state of an RPC node with its latency and error rate of the last hour.
+type NodeStatus struct {
+	client.NodeState
+	Stats     metrics.Stats
+	ErrorRate float64
+}
+
+func (a *Admin) nodesStatusHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	stats := a.metrics.RPCNodeStats(nodeStatsWindow)
+	states := a.clientMgr.Nodes(ctx)
+	nodes := make([]NodeStatus, 0, len(states))
+	for _, state := range states {
+		nodeStats := stats[state.Node]
+		nodes = append(nodes, NodeStatus{
+			NodeState: state,
+			Stats:     nodeStats,
+			ErrorRate: 100 - nodeStats.SuccessRate(),
+		})
+	}
+
+	return cmd.RenderResult(appID, "admin_nodes", map[string]any{
+		"Nodes": nodes,
+	})
+}
+
+func (a *Admin) nodesDrainHandler(_ context.Context, cmd command.Command, _ command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	node, err := a.clientMgr.Drain(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("%s is drained, the calls are sent to the other nodes", node)
+}
+
+func (a *Admin) nodesRestoreHandler(_ context.Context, cmd command.Command, _ command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	node, err := a.clientMgr.Restore(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("%s is restored, it receives the calls again", node)
+}
+
+func (a *Admin) featureEnableHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	name := strings.ToLower(args[0])
+	if !feature.ValidName(name) {
+		return cmd.FailedResult("%s is not a feature name, like: network.map", args[0])
+	}
+
+	if err := a.features.Enable(name, callerID); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("%s is enabled", name)
+}
+
+func (a *Admin) featureDisableHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	name := strings.ToLower(args[0])
+	if !feature.ValidName(name) {
+		return cmd.FailedResult("%s is not a feature name, like: network.map", args[0])
+	}
+
+	// the admin commands can't be disabled, they are needed to enable the features again.
+	if name == CommandName || strings.HasPrefix(name, CommandName+".") {
+		return cmd.FailedResult("The admin commands can't be disabled")
+	}
+
+	if err := a.features.Disable(name, callerID); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("%s is disabled", name)
+}
+
+func (a *Admin) featureResetHandler(_ context.Context, cmd command.Command, _ command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	name := strings.ToLower(args[0])
+	removed, err := a.features.Reset(name)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !removed {
+		return cmd.FailedResult("%s has no flag", name)
+	}
+
+	return cmd.SuccessfulResult("%s has its default state", name)
+}
+
+func (a *Admin) featureListHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	flags, err := a.features.List()
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "admin_features", map[string]any{
+		"Flags": flags,
+	})
+}
+
+func (a *Admin) settingsPrefixHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	platform, ok := command.ParseAppID(args[0])
+	if !ok || !slices.Contains(settings.PrefixPlatforms, platform) {
+		return cmd.FailedResult("%s is not a platform with a prefix, like: discord", args[0])
+	}
+
+	scope := ""
+	if len(args) > 2 {
+		scope = args[2]
+	}
+	name := settings.PrefixName(platform, scope)
+
+	if strings.EqualFold(args[1], ResetCommandName) {
+		removed, err := a.settings.Reset(name)
+		if err != nil {
+			return cmd.ErrorResult(err)
+		}
+
+		if !removed {
+			return cmd.FailedResult("No prefix is set for %s", name)
+		}
+
+		return cmd.SuccessfulResult("The prefix of %s is removed", name)
+	}
+
+	if !settings.ValidPrefix(args[1]) {
+		return cmd.FailedResult("%s is not a prefix, it's up to %d characters without spaces, like: !pagu",
+			args[1], settings.MaxPrefixLength)
+	}
+
+	if err := a.settings.Set(name, args[1], callerID); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("The prefix of %s is %s", name, args[1])
+}
+
+func (a *Admin) settingsReadOnlyHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	switch strings.ToLower(args[0]) {
+	case StatusCommandName:
+		switch {
+		case a.settings.ReadOnlyForced():
+			return cmd.SuccessfulResult("Pagu is read-only by the config of this instance")
+		case a.settings.ReadOnly():
+			return cmd.SuccessfulResult("Pagu is read-only, the state-changing commands are disabled")
+		default:
+			return cmd.SuccessfulResult("Pagu is not read-only")
+		}
+
+	case "on":
+		if err := a.settings.SetReadOnly(true, callerID); err != nil {
+			return cmd.ErrorResult(err)
+		}
+
+		return cmd.SuccessfulResult("Pagu is read-only, the state-changing commands are disabled on all the instances")
+
+	case "off":
+		if err := a.settings.SetReadOnly(false, callerID); err != nil {
+			return cmd.ErrorResult(err)
+		}
+
+		if a.settings.ReadOnlyForced() {
+			return cmd.SuccessfulResult("The read-only mode is off, but this instance is read-only by its config")
+		}
+
+		return cmd.SuccessfulResult("The read-only mode is off, the state-changing commands are enabled")
+	}
+
+	return cmd.FailedResult("%s is not a state, like: on, off or status", args[0])
+}
+
+func (a *Admin) settingsTopicHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	chatID, topicID := args[0], strings.ToLower(args[1])
+	name := settings.TopicName(chatID, topicID)
+
+	if strings.EqualFold(args[2], ResetCommandName) {
+		removed, err := a.settings.Reset(name)
+		if err != nil {
+			return cmd.ErrorResult(err)
+		}
+
+		if !removed {
+			return cmd.FailedResult("No command group is bound to the topic %s of %s", topicID, chatID)
+		}
+
+		return cmd.SuccessfulResult("The topic %s of %s runs the commands that are not bound", topicID, chatID)
+	}
+
+	groups := settings.ParseTopicGroups(args[2])
+	if len(groups) == 0 {
+		return cmd.FailedResult("%s has no command group, like: phoenix,network", args[2])
+	}
+
+	if err := a.settings.Set(name, strings.Join(groups, ","), callerID); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("The topic %s of %s runs the %s commands", topicID, chatID, strings.Join(groups, ", "))
+}
+
+func (a *Admin) maintenanceHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	switch strings.ToLower(args[0]) {
+	case StatusCommandName:
+		window, ok := a.maint.Scheduled()
+		if !ok {
+			return cmd.SuccessfulResult("No maintenance is scheduled")
+		}
+
+		return cmd.SuccessfulResult("The maintenance is from %s to %s UTC",
+			window.Start.Format("2006-01-02 15:04"), window.End.Format("2006-01-02 15:04"))
+
+	case "cancel":
+		canceled, err := a.maint.Cancel()
+		if err != nil {
+			return cmd.ErrorResult(err)
+		}
+
+		if !canceled {
+			return cmd.FailedResult("No maintenance is scheduled")
+		}
+
+		return cmd.SuccessfulResult("The maintenance is canceled, the commands and the jobs are resumed")
+	}
+
+	if len(args) < 2 {
+		return cmd.FailedResult("The duration of the maintenance is needed, like: 30m")
+	}
+
+	window, err := maintenance.ParseWindow(args[0], args[1], a.now())
+	if err != nil {
+		return cmd.FailedResult("Invalid maintenance window: %v", err)
+	}
+
+	if err := a.maint.Schedule(window, callerID); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("The maintenance is scheduled from %s to %s UTC",
+		window.Start.Format("2006-01-02 15:04"), window.End.Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,257 @@
+package admin
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/maintenance"
+	"github.com/pagu-project/Pagu/metrics"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	local := client.NewMockIClient(ctrl)
+	local.EXPECT().Target().Return("localhost:50051").AnyTimes()
+	local.EXPECT().GetBlockchainHeight(gomock.Any()).Return(uint32(1_500_000), nil).AnyTimes()
+	remote := client.NewMockIClient(ctrl)
+	remote.EXPECT().Target().Return("node.example.com:50051").AnyTimes()
+	remote.EXPECT().GetBlockchainHeight(gomock.Any()).Return(uint32(1_499_990), nil).AnyTimes()
+
+	cm := client.NewClientMgr(context.Background())
+	cm.AddWeightedClient(local, 1)
+	cm.AddWeightedClient(remote, 1)
+
+	mtr := metrics.NewMetrics()
+	mtr.ObserveRPC("localhost:50051", nil, 20*time.Millisecond)
+	mtr.ObserveRPC("localhost:50051", context.DeadlineExceeded, 5*time.Second)
+
+	a := NewAdmin(cm, mtr, 99, nil, nil, nil, nil)
+	cmd := a.GetCommand()
+
+	res := a.nodesStatusHandler(context.Background(), cmd, command.AppIdCLI, "admin-id")
+	assert.True(t, res.Successful)
+	assert.Contains(t, res.Message, "1. localhost:50051: healthy")
+	assert.Contains(t, res.Message, "Weight: 1, height: 1,500,000")
+	assert.Contains(t, res.Message, "Last hour: 2 calls, error rate: 50.00%")
+	assert.Contains(t, res.Message, "2. node.example.com:50051: behind")
+
+	res = a.nodesDrainHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "2")
+	assert.True(t, res.Successful)
+	assert.Contains(t, res.Message, "node.example.com:50051 is drained")
+
+	res = a.nodesStatusHandler(context.Background(), cmd, command.AppIdCLI, "admin-id")
+	assert.Contains(t, res.Message, "2. node.example.com:50051: drained")
+
+	res = a.nodesDrainHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "1")
+	assert.False(t, res.Successful, "the last node")
+
+	res = a.nodesRestoreHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "node.example.com:50051")
+	assert.True(t, res.Successful)
+}
+
+func TestSLOShards(t *testing.T) {
+	mtr := metrics.NewMetrics()
+	mtr.ObserveShard(1, true, 1200, 80*time.Millisecond)
+	mtr.ObserveShard(0, true, 1500, 40*time.Millisecond)
+	mtr.ObserveShard(1, false, 1200, 0)
+
+	a := NewAdmin(nil, mtr, 99, nil, nil, nil, nil)
+	res := a.sloHandler(context.Background(), a.GetCommand(), command.AppIdCLI, "admin-id")
+	assert.True(t, res.Successful)
+	assert.Contains(t, res.Message, "Discord shard 0: connected, 1,500 guilds, heartbeat 40ms, disconnects: 0\n"+
+		"Discord shard 1: disconnected")
+	assert.Contains(t, res.Message, "1,200 guilds, heartbeat 0s, disconnects: 1")
+}
+
+func TestSettingsPrefix(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	setts := settings.NewSettings(db)
+	a := NewAdmin(nil, metrics.NewMetrics(), 99, nil, nil, setts, nil)
+	cmd := a.GetCommand()
+
+	res := a.settingsPrefixHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "discord", "!pagu")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "The prefix of prefix.discord is !pagu", res.Message)
+
+	res = a.settingsPrefixHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "Discord", ".p", "1234")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, ".p", setts.Prefix(command.AppIdDiscord, "1234"))
+	assert.Equal(t, "!pagu", setts.Prefix(command.AppIdDiscord, "5678"))
+
+	res = a.settingsPrefixHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "discord", "reset", "1234")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "!pagu", setts.Prefix(command.AppIdDiscord, "1234"))
+
+	res = a.settingsPrefixHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "discord", "reset", "1234")
+	assert.False(t, res.Successful, "no prefix is set")
+
+	res = a.settingsPrefixHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "cli", "!pagu")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "is not a platform with a prefix")
+
+	res = a.settingsPrefixHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "discord",
+		"!averylongprefix")
+	assert.False(t, res.Successful)
+}
+
+func TestSettingsTopic(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	setts := settings.NewSettings(db)
+	a := NewAdmin(nil, metrics.NewMetrics(), 99, nil, nil, setts, nil)
+	cmd := a.GetCommand()
+
+	res := a.settingsTopicHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "-100123", "42",
+		"Phoenix,zealy")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "The topic 42 of -100123 runs the phoenix, zealy commands", res.Message)
+	assert.False(t, setts.TopicAllows("-100123", "7", "phoenix"))
+
+	res = a.settingsTopicHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "-100123", "42", ",")
+	assert.False(t, res.Successful)
+
+	res = a.settingsTopicHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "-100123", "42", "reset")
+	require.True(t, res.Successful, res.Message)
+	assert.True(t, setts.TopicAllows("-100123", "7", "phoenix"))
+
+	res = a.settingsTopicHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "-100123", "42", "reset")
+	assert.False(t, res.Successful, "no group is bound")
+}
+
+func TestSettingsReadOnly(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	setts := settings.NewSettings(db)
+	a := NewAdmin(nil, metrics.NewMetrics(), 99, nil, nil, setts, nil)
+	cmd := a.GetCommand()
+
+	res := a.settingsReadOnlyHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "status")
+	assert.Equal(t, "Pagu is not read-only", res.Message)
+
+	res = a.settingsReadOnlyHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "on")
+	require.True(t, res.Successful, res.Message)
+	assert.True(t, settings.NewSettings(db).ReadOnly(), "all the instances are read-only")
+
+	res = a.settingsReadOnlyHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "off")
+	require.True(t, res.Successful, res.Message)
+	assert.False(t, setts.ReadOnly())
+
+	setts.ForceReadOnly()
+	res = a.settingsReadOnlyHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "off")
+	assert.Contains(t, res.Message, "read-only by its config")
+	assert.True(t, setts.ReadOnly())
+
+	res = a.settingsReadOnlyHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "maybe")
+	assert.False(t, res.Successful)
+}
+
+func TestMaintenance(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	maint := maintenance.NewMaintenance(settings.NewSettings(db), db, notify.NewHub(), nil, time.Hour)
+	a := NewAdmin(nil, metrics.NewMetrics(), 99, nil, nil, nil, maint)
+	a.now = func() time.Time { return time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC) }
+	cmd := a.GetCommand()
+
+	res := a.maintenanceHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "2024-07-01T10:00")
+	assert.False(t, res.Successful, "no duration")
+
+	res = a.maintenanceHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "2024-06-01T10:00", "30m")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "the window is in the past")
+
+	res = a.maintenanceHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "2099-07-01T10:00", "30m")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "The maintenance is scheduled from 2099-07-01 10:00 to 2099-07-01 10:30 UTC", res.Message)
+
+	res = a.maintenanceHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "status")
+	assert.Equal(t, "The maintenance is from 2099-07-01 10:00 to 2099-07-01 10:30 UTC", res.Message)
+
+	res = a.maintenanceHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "cancel")
+	assert.True(t, res.Successful)
+
+	res = a.maintenanceHandler(context.Background(), cmd, command.AppIdCLI, "admin-id", "cancel")
+	assert.False(t, res.Successful, "no maintenance is scheduled")
+}
+
+func TestDeprecations(t *testing.T) {
+	a := NewAdmin(nil, metrics.NewMetrics(), 99, nil, nil, nil, nil)
+	cmd := a.GetCommand()
+
+	calls := map[string]int64{}
+	a.SetDeprecatedCalls(func() (map[string]int64, error) { return calls, nil })
+	res := a.deprecationsHandler(context.Background(), cmd, command.AppIdCLI, "admin-id")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "No deprecated command is called yet.", res.Message)
+
+	calls = map[string]int64{"old-status": 1200, "old-info": 3}
+	res = a.deprecationsHandler(context.Background(), cmd, command.AppIdCLI, "admin-id")
+	assert.Equal(t, "old-info: 3 calls\nold-status: 1,200 calls", res.Message)
+}
+
+func TestSetup(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	setts := settings.NewSettings(db)
+	a := NewAdmin(nil, metrics.NewMetrics(), 99, nil, nil, setts, nil)
+	a.SetCommandGroups(func() []string { return []string{"network", "blockchain", "phoenix"} })
+	a.SetServerChannels(func(_ command.AppID, server, channel string) bool {
+		return server == "1234" && channel == "5678"
+	})
+	cmd := a.GetCommand()
+
+	res := a.setupHandler(context.Background(), cmd, command.AppIdDiscord, "admin-id", "1234", "Network,blockchain",
+		"5678", "tr", "!pagu", "strict")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "The setup of the Discord server 1234 is saved✅\n\n"+
+		"Commands: network, blockchain\n"+
+		"Announcements: 5678 in tr\n"+
+		"Prefix: !pagu\n"+
+		"Rate limits: strict", res.Message)
+	assert.True(t, setts.ServerAllows(command.AppIdDiscord, "1234", "network"))
+	assert.False(t, setts.ServerAllows(command.AppIdDiscord, "1234", "phoenix"))
+	assert.Equal(t, "!pagu", setts.Prefix(command.AppIdDiscord, "1234"))
+	assert.Equal(t, []notify.Channel{{AppID: command.AppIdDiscord, ID: "5678", Locale: "tr"}}, setts.ServerChannels())
+
+	res = a.setupHandler(context.Background(), cmd, command.AppIdDiscord, "admin-id", "1234", "all", "keep", "en",
+		"none", "keep")
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "Commands: all\nAnnouncements: 5678\nPrefix: none\nRate limits: strict")
+	assert.True(t, setts.ServerAllows(command.AppIdDiscord, "1234", "phoenix"))
+
+	for _, args := range [][]string{
+		{"1234", "network,zealy", "keep", "keep", "keep", "keep"},
+		{"1234", "keep", "keep", "xx", "keep", "keep"},
+		{"1234", "keep", "keep", "keep", "!averylongprefix", "keep"},
+		{"1234", "keep", "none", "keep", "keep", "loose"},
+		{"1234", "keep", "9999", "keep", "keep", "keep"},
+	} {
+		res = a.setupHandler(context.Background(), cmd, command.AppIdDiscord, "admin-id", args...)
+		assert.False(t, res.Successful, args)
+		assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+	}
+	assert.Equal(t, []notify.Channel{{AppID: command.AppIdDiscord, ID: "5678"}}, setts.ServerChannels(),
+		"a wrong answer saves nothing")
+
+	res = a.setupHandler(context.Background(), cmd, command.AppIdDiscord, "admin-id", "1234", "keep", "9999",
+		"keep", "keep", "keep")
+	assert.Equal(t, "9999 is not a channel of the server 1234 that Pagu can see", res.Message,
+		"the announcements are not pointed to another server")
+}
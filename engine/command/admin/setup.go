@@ -0,0 +1,224 @@
+package admin
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/i18n"
+	"github.com/pagu-project/Pagu/settings"
+)
+
+// The answers of the setup that are not a value.
+const (
+	keepWord = "keep" // Keeps the current value of the setting.
+	allWord  = "all"  // Enables all the command groups.
+	noneWord = "none" // Removes the setting, like no announcement channel.
+)
+
+// defaultLocale is the language of the announcements without a translation.
+const defaultLocale = "en"
+
+// ServerSetup is the settings of a server after the setup.
+type ServerSetup struct {
+	Platform string
+	Server   string
+	Groups   string // The comma separated command groups, empty if all of them run.
+	Channel  string
+	Locale   string
+	Prefix   string
+	Limits   string
+}
+
+// SetCommandGroups sets the function that lists the command groups of the bot, the setup enables some of them.
+func (a *Admin) SetCommandGroups(groups func() []string) {
+	a.groups = groups
+}
+
+// SetServerChannels sets the function that checks the channel is in the server,
+// the setup points the announcements only to a channel of the server.
+func (a *Admin) SetServerChannels(channels func(appID command.AppID, server, channel string) bool) {
+	a.channels = channels
+}
+
+func (a *Admin) setupCommand() command.Command {
+	return command.Command{
+		Name: SetupCommandName,
+		Desc: "Set up a Discord server or a Telegram group",
+		Help: "Walks through the settings of a server step by step: the command groups that run in it, " +
+			"the channel and the language of the announcements, the command prefix and the rate-limit preset. " +
+			"\"keep\" keeps the current value of a step",
+		Args: []command.Args{
+			{
+				Name:     "server",
+				Desc:     "ID of the Discord server or the Telegram group [example: 1234]",
+				Optional: false,
+			},
+			{
+				Name:     "commands",
+				Desc:     "Comma separated command groups that run in the server, all or keep [example: network,blockchain]",
+				Optional: false,
+			},
+			{
+				Name:     "channel",
+				Desc:     "ID of the channel of the announcements, like the releases, none or keep",
+				Optional: false,
+			},
+			{
+				Name:     "language",
+				Desc:     "Language of the announcements, like: en, tr or fa, or keep",
+				Optional: false,
+			},
+			{
+				Name:     "prefix",
+				Desc:     "Prefix of the commands in the messages, none or keep [example: !pagu]",
+				Optional: false,
+			},
+			{
+				Name:     "limits",
+				Desc:     "Rate-limit preset of the heavy commands: strict, standard, relaxed or keep",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      settings.PrefixPlatforms,
+		Examples:    []string{"1234 network,blockchain 5678 en !pagu standard", "1234 all keep tr keep relaxed"},
+		Mutating:    true,
+		ServerAdmin: true,
+		Handler:     a.setupHandler,
+	}
+}
+
+// setupHandler checks all the answers of the setup first, so a wrong answer doesn't save a part of them.
+func (a *Admin) setupHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	scope := args[0]
+	changes := make(map[string]string) // The empty values are reset.
+
+	if answer := strings.ToLower(args[1]); answer != keepWord {
+		value := ""
+		if answer != allWord {
+			groups := settings.ParseTopicGroups(answer)
+			if unknown := a.unknownGroups(groups); len(unknown) > 0 {
+				return cmd.FailedResult("%s is not a command group, the groups are: %s",
+					strings.Join(unknown, ", "), strings.Join(a.commandGroups(), ", ")).WithCode(command.ErrCodeInvalidArgs)
+			}
+
+			if len(groups) == 0 {
+				return cmd.FailedResult("%s has no command group, like: network,blockchain", args[1]).
+					WithCode(command.ErrCodeInvalidArgs)
+			}
+			value = strings.Join(groups, ",")
+		}
+		changes[settings.ServerName(appID, scope, settings.ServerGroups)] = value
+	}
+
+	if answer := args[2]; !strings.EqualFold(answer, keepWord) {
+		if strings.EqualFold(answer, noneWord) {
+			answer = ""
+		} else if a.channels != nil && !a.channels(appID, scope, answer) {
+			return cmd.FailedResult("%s is not a channel of the server %s that Pagu can see", answer, scope).
+				WithCode(command.ErrCodeInvalidArgs)
+		}
+		changes[settings.ServerName(appID, scope, settings.ServerChannel)] = answer
+	}
+
+	if answer := strings.ToLower(args[3]); answer != keepWord {
+		if answer != defaultLocale && !i18n.HasLocale(answer) {
+			return cmd.FailedResult("%s is not a language of the announcements, like: en, tr or fa", args[3]).
+				WithCode(command.ErrCodeInvalidArgs)
+		}
+
+		if answer == defaultLocale {
+			answer = ""
+		}
+		changes[settings.ServerName(appID, scope, settings.ServerLocale)] = answer
+	}
+
+	if answer := args[4]; !strings.EqualFold(answer, keepWord) {
+		if strings.EqualFold(answer, noneWord) {
+			answer = ""
+		} else if !settings.ValidPrefix(answer) {
+			return cmd.FailedResult("%s is not a prefix, it's up to %d characters without spaces, like: !pagu",
+				answer, settings.MaxPrefixLength).WithCode(command.ErrCodeInvalidArgs)
+		}
+		changes[settings.PrefixName(appID, scope)] = answer
+	}
+
+	if answer := strings.ToLower(args[5]); answer != keepWord {
+		if _, ok := settings.LimitPresets[answer]; !ok {
+			return cmd.FailedResult("%s is not a rate-limit preset, like: strict, standard or relaxed", args[5]).
+				WithCode(command.ErrCodeInvalidArgs)
+		}
+		changes[settings.ServerName(appID, scope, settings.ServerLimits)] = answer
+	}
+
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		var err error
+		if value := changes[name]; value == "" {
+			_, err = a.settings.Reset(name)
+		} else {
+			err = a.settings.Set(name, value, callerID)
+		}
+
+		if err != nil {
+			return cmd.ErrorResult(err)
+		}
+	}
+
+	return cmd.RenderResult(appID, "admin_setup", a.serverSetup(appID, scope))
+}
+
+// serverSetup returns the current settings of the server.
+func (a *Admin) serverSetup(appID command.AppID, scope string) ServerSetup {
+	setup := ServerSetup{
+		Platform: appID.String(),
+		Server:   scope,
+		Limits:   settings.LimitsStandard,
+	}
+
+	if groups, ok := a.settings.ServerGroupsOf(appID, scope); ok {
+		setup.Groups = strings.Join(groups, ", ")
+	}
+	setup.Channel, _ = a.settings.Get(settings.ServerName(appID, scope, settings.ServerChannel))
+	setup.Locale, _ = a.settings.Get(settings.ServerName(appID, scope, settings.ServerLocale))
+	setup.Prefix, _ = a.settings.Get(settings.PrefixName(appID, scope))
+	if limits, ok := a.settings.Get(settings.ServerName(appID, scope, settings.ServerLimits)); ok {
+		setup.Limits = limits
+	}
+
+	return setup
+}
+
+func (a *Admin) commandGroups() []string {
+	if a.groups == nil {
+		return nil
+	}
+
+	return a.groups()
+}
+
+// unknownGroups returns the groups that the bot doesn't have, nothing is unknown if the groups are not set.
+func (a *Admin) unknownGroups(groups []string) []string {
+	known := a.commandGroups()
+	if known == nil {
+		return nil
+	}
+
+	unknown := make([]string, 0)
+	for _, group := range groups {
+		if !slices.Contains(known, group) {
+			unknown = append(unknown, group)
+		}
+	}
+
+	return unknown
+}
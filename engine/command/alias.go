@@ -0,0 +1,84 @@
+package command
+
+import (
+	"strings"
+)
+
+// Alias is a localized name of a command, like: "şebeke" for network in "tr" locale.
+type Alias struct {
+	Locale string
+	Name   string
+}
+
+// MatchName checks if the token is the name of the command or one of its aliases.
+// Aliases are matched case-insensitive.
+func (cmd *Command) MatchName(token string) bool {
+	if cmd.Name == token {
+		return true
+	}
+
+	for _, alias := range cmd.Aliases {
+		if strings.EqualFold(alias.Name, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindSubCommand returns the nested sub-command with the given path of names, like: "network", "status".
+func (cmd *Command) FindSubCommand(path ...string) *Command {
+	target := cmd
+	for _, name := range path {
+		var found *Command
+		for i := range target.SubCommands {
+			if target.SubCommands[i].Name == name {
+				found = &target.SubCommands[i]
+
+				break
+			}
+		}
+
+		if found == nil {
+			return nil
+		}
+		target = found
+	}
+
+	return target
+}
+
+// AddAlias adds a localized name to the command.
+// The alias is rejected if it's already a name or an alias of a sibling command.
+func (cmd *Command) AddAlias(path []string, alias Alias) error {
+	if len(path) == 0 {
+		return AliasError{Alias: alias.Name, Reason: "empty command path"}
+	}
+
+	parent := cmd.FindSubCommand(path[:len(path)-1]...)
+	target := cmd.FindSubCommand(path...)
+	if parent == nil || target == nil {
+		return AliasError{Alias: alias.Name, Reason: "command not found: " + strings.Join(path, " ")}
+	}
+
+	for _, sibling := range parent.SubCommands {
+		if sibling.MatchName(alias.Name) {
+			return AliasError{Alias: alias.Name, Reason: "conflicts with command: " + sibling.Name}
+		}
+	}
+
+	target.Aliases = append(target.Aliases, alias)
+
+	return nil
+}
+
+// LocalizedName returns the first alias of the command in the locale.
+func (cmd *Command) LocalizedName(locale string) (string, bool) {
+	for _, alias := range cmd.Aliases {
+		if alias.Locale == locale {
+			return alias.Name, true
+		}
+	}
+
+	return "", false
+}
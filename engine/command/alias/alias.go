@@ -0,0 +1,342 @@
+package alias
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+)
+
+const (
+	CommandName       = "alias"
+	SetCommandName    = "set"
+	ShowCommandName   = "show"
+	ClearCommandName  = "clear"
+	ListCommandName   = "list"
+	RemoveCommandName = "remove"
+)
+
+const (
+	MinNameLength = 3
+	MaxNameLength = 32
+
+	// recentLimit is the number of the last aliases that the moderators review.
+	recentLimit = 20
+)
+
+// namePattern keeps the names plain, so they can't format the messages or mention the users.
+var namePattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} .-]*$`)
+
+// Alias keeps the public names of the addresses, like the validators of a community.
+// Only the verified owner of an address names it, the moderators remove the abusive names.
+type Alias struct {
+	db *database.DB
+}
+
+func NewAlias(db *database.DB) Alias {
+	return Alias{
+		db: db,
+	}
+}
+
+func (a *Alias) GetCommand() command.Command {
+	subCmdSet := command.Command{
+		Name: SetCommandName,
+		Desc: "Name an address that you own",
+		Help: "The name is shown next to the address in the outputs, link the address first to prove you own it",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "Your linked address [example: pc1p...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+			{
+				Name:     "name",
+				Desc:     "The public name of the address [example: Pactus Lovers]",
+				Optional: false,
+				Variadic: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p... Pactus Lovers"},
+		Mutating:    true,
+		Handler:     a.setHandler,
+	}
+
+	subCmdShow := command.Command{
+		Name: ShowCommandName,
+		Desc: "The name of an address",
+		Help: "",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "The address [example: pc1p...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     a.showHandler,
+	}
+
+	subCmdClear := command.Command{
+		Name: ClearCommandName,
+		Desc: "Remove the name of an address that you own",
+		Help: "",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "Your linked address [example: pc1p...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Mutating:    true,
+		Handler:     a.clearHandler,
+	}
+
+	cmdAlias := command.Command{
+		Emoji:       "🏷️",
+		Name:        CommandName,
+		Desc:        "Public names of the addresses",
+		Help:        "The owners name their addresses, and the names are shown next to the addresses in the outputs",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdAlias.AddSubCommand(subCmdSet)
+	cmdAlias.AddSubCommand(subCmdShow)
+	cmdAlias.AddSubCommand(subCmdClear)
+
+	return cmdAlias
+}
+
+// GetAdminCommand returns the moderation commands of the aliases, they are added to the admin commands.
+func (a *Alias) GetAdminCommand() command.Command {
+	subCmdList := command.Command{
+		Name:        ListCommandName,
+		Desc:        "The last set aliases",
+		Help:        "Lists the last aliases and who set them, to review the names",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     a.listHandler,
+	}
+
+	subCmdRemove := command.Command{
+		Name: RemoveCommandName,
+		Desc: "Remove an abusive alias",
+		Help: "Removes the alias of any address, like an offensive name or one that impersonates another validator",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "The named address [example: pc1p...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p..."},
+		Mutating:    true,
+		Handler:     a.removeHandler,
+	}
+
+	cmdAlias := command.Command{
+		Name:        CommandName,
+		Desc:        "Moderation of the address aliases",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdAlias.AddSubCommand(subCmdList)
+	cmdAlias.AddSubCommand(subCmdRemove)
+
+	return cmdAlias
+}
+
+// WithAliases appends the aliases of the addresses of the message after them, an alias that is already
+// in the message is not repeated, like in the outputs of the alias commands.
+// The message is kept as it is if the aliases are not available.
+func (a *Alias) WithAliases(ctx context.Context, msg string) string {
+	if a.db == nil {
+		return msg
+	}
+
+	addresses := command.FindAddresses(msg)
+	if len(addresses) == 0 {
+		return msg
+	}
+
+	names, err := a.db.GetAddressAliases(addresses)
+	if err != nil {
+		log.Ctx(ctx).Debug("can't get the aliases", "err", err)
+
+		return msg
+	}
+
+	for address, name := range names {
+		if strings.Contains(msg, name) {
+			delete(names, address)
+		}
+	}
+
+	return command.AppendAliases(msg, names)
+}
+
+func (a *Alias) setHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	addr, err := crypto.AddressFromString(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if res, owned := a.checkOwner(cmd, appID, callerID, addr.String()); !owned {
+		return res
+	}
+
+	name := strings.Join(strings.Fields(strings.Join(args[1:], " ")), " ")
+	if err := validateName(name); err != nil {
+		return cmd.FailedResult("%v", err).WithCode(command.ErrCodeInvalidArgs)
+	}
+
+	set, err := a.db.SetAddressAlias(&database.AddressAlias{
+		Address: addr.String(),
+		Name:    name,
+		AppID:   int(appID),
+		UserID:  callerID,
+	})
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !set {
+		return cmd.FailedResult("%s is the name of another address, please choose another one", name)
+	}
+
+	return cmd.SuccessfulResult("The name of %s is %s", addr.String(), name)
+}
+
+func (a *Alias) showHandler(_ context.Context, cmd command.Command, _ command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	alias, err := a.db.GetAddressAlias(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if alias == nil {
+		return cmd.FailedResult("%s has no alias", args[0]).WithCode(command.ErrCodeNotFound)
+	}
+
+	return cmd.SuccessfulResult("The name of %s is %s", alias.Address, alias.Name)
+}
+
+func (a *Alias) clearHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	if res, owned := a.checkOwner(cmd, appID, callerID, args[0]); !owned {
+		return res
+	}
+
+	cleared, err := a.db.DeleteAddressAlias(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !cleared {
+		return cmd.FailedResult("%s has no alias", args[0])
+	}
+
+	return cmd.SuccessfulResult("The alias of %s is removed", args[0])
+}
+
+func (a *Alias) listHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	aliases, err := a.db.GetRecentAddressAliases(recentLimit)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	items := make([]map[string]any, 0, len(aliases))
+	for _, alias := range aliases {
+		items = append(items, map[string]any{
+			"Address": alias.Address,
+			"Name":    alias.Name,
+			"App":     command.AppID(alias.AppID).String(),
+			"UserID":  alias.UserID,
+		})
+	}
+
+	return cmd.RenderResult(appID, "alias_list", map[string]any{
+		"Aliases": items,
+	})
+}
+
+func (a *Alias) removeHandler(ctx context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	removed, err := a.db.DeleteAddressAlias(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !removed {
+		return cmd.FailedResult("%s has no alias", args[0]).WithCode(command.ErrCodeNotFound)
+	}
+
+	log.Ctx(ctx).Info("alias removed by a moderator", "address", args[0], "moderator", callerID)
+
+	return cmd.SuccessfulResult("The alias of %s is removed", args[0])
+}
+
+// checkOwner checks that the caller linked the address, the failed result tells them how to link it.
+func (a *Alias) checkOwner(cmd command.Command, appID command.AppID, callerID, address string,
+) (command.CommandResult, bool) {
+	owner, err := a.db.GetAddressOwner(address)
+	if err != nil {
+		return cmd.ErrorResult(err), false
+	}
+
+	if owner == nil || owner.AppID != int(appID) || owner.UserID != callerID {
+		return cmd.FailedResult("Only the owner of %s names it, link it first with: link address %s",
+			address, address).WithCode(command.ErrCodeUnauthorized), false
+	}
+
+	return command.CommandResult{}, true
+}
+
+// validateName checks that the name is plain and is not an address, so it can't mislead the readers.
+func validateName(name string) error {
+	length := utf8.RuneCountInString(name)
+	if length < MinNameLength || length > MaxNameLength {
+		return NameError{Reason: "the name must have 3 to 32 characters"}
+	}
+
+	if !namePattern.MatchString(name) {
+		return NameError{Reason: "the name can only have letters, digits, spaces, dots and dashes"}
+	}
+
+	if len(command.FindAddresses(name)) > 0 {
+		return NameError{Reason: "the name can't be an address"}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package alias
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pactus-project/pactus/util/testsuite"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlias(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	a := NewAlias(db)
+	cmd := a.GetCommand()
+	appID := command.AppIdTelegram
+	addr := ts.RandValAddress().String()
+
+	link := &database.AddressLink{
+		AppID:     int(appID),
+		UserID:    "alice",
+		Address:   addr,
+		Nonce:     "00",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, db.AddPendingAddressLink(link))
+
+	res := a.setHandler(context.Background(), cmd, appID, "alice", addr, "Pactus", "Lovers")
+	assert.False(t, res.Successful, "the link is not verified")
+	assert.Equal(t, command.ErrCodeUnauthorized, res.Code)
+
+	require.NoError(t, db.VerifyAddressLink(link.ID))
+
+	for _, name := range []string{"ab", "@everyone", "**Pagu**", addr} {
+		res = a.setHandler(context.Background(), cmd, appID, "alice", addr, name)
+		assert.False(t, res.Successful, name)
+		assert.Equal(t, command.ErrCodeInvalidArgs, res.Code, name)
+	}
+
+	res = a.setHandler(context.Background(), cmd, appID, "alice", addr, "Pactus", " Lovers")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "The name of "+addr+" is Pactus Lovers", res.Message)
+
+	res = a.setHandler(context.Background(), cmd, appID, "bob", addr, "Pactus Fans")
+	assert.False(t, res.Successful, "bob doesn't own the address")
+
+	res = a.showHandler(context.Background(), cmd, appID, "bob", addr)
+	assert.Contains(t, res.Message, "Pactus Lovers")
+
+	msg := "Validator " + addr + " is online"
+	assert.Equal(t, "Validator "+addr+" (Pactus Lovers) is online", a.WithAliases(context.Background(), msg))
+	assert.Equal(t, res.Message, a.WithAliases(context.Background(), res.Message), "the alias is not repeated")
+
+	res = a.listHandler(context.Background(), cmd, appID, "admin")
+	assert.Contains(t, res.Message, addr+": Pactus Lovers, by alice on Telegram")
+
+	t.Run("the moderators remove an alias", func(t *testing.T) {
+		res := a.removeHandler(context.Background(), cmd, appID, "admin", addr)
+		require.True(t, res.Successful, res.Message)
+		assert.Equal(t, msg, a.WithAliases(context.Background(), msg))
+
+		res = a.removeHandler(context.Background(), cmd, appID, "admin", addr)
+		assert.Equal(t, command.ErrCodeNotFound, res.Code)
+	})
+
+	res = a.setHandler(context.Background(), cmd, appID, "alice", addr, "Pactus Lovers")
+	require.True(t, res.Successful, res.Message)
+
+	res = a.clearHandler(context.Background(), cmd, appID, "bob", addr)
+	assert.False(t, res.Successful, "only the owner clears the alias")
+
+	res = a.clearHandler(context.Background(), cmd, appID, "alice", addr)
+	assert.True(t, res.Successful)
+
+	res = a.showHandler(context.Background(), cmd, appID, "bob", addr)
+	assert.False(t, res.Successful)
+}
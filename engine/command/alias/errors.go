@@ -0,0 +1,9 @@
+package alias
+
+type NameError struct {
+	Reason string
+}
+
+func (e NameError) Error() string {
+	return e.Reason
+}
@@ -1,19 +1,23 @@
 package blockchain
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/pactus-project/pactus/crypto"
 	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pactus-project/pactus/types/param"
 	"github.com/pagu-project/Pagu/client"
 	"github.com/pagu-project/Pagu/engine/command"
-	"github.com/pagu-project/Pagu/utils"
 )
 
 const (
 	CommandName           = "blockchain"
 	CalcRewardCommandName = "reward-calc"
 	CalcFeeCommandName    = "fee-calc"
+	CalcUnbondCommandName = "unbond-calc"
 	HelpCommandName       = "help"
 )
 
@@ -48,6 +52,7 @@ func (bc *Blockchain) GetCommand() command.Command {
 		},
 		SubCommands: nil,
 		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"100 day", "1000 year"},
 		Handler:     bc.calcRewardHandler,
 	}
 
@@ -64,9 +69,30 @@ func (bc *Blockchain) GetCommand() command.Command {
 		},
 		SubCommands: nil,
 		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"250"},
 		Handler:     bc.calcFeeHandler,
 	}
 
+	subCmdCalcUnbond := command.Command{
+		Name: CalcUnbondCommandName,
+		Desc: "Calculate when the stake of a validator becomes withdrawable",
+		Help: "Shows the bonding and unbonding cooldowns of the validator, " +
+			"the dates are estimated with the average block time of the last blocks",
+		Args: []command.Args{
+			{
+				Name:     "validator",
+				Desc:     "Validator address",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p..."},
+		Expensive:   true,
+		Handler:     bc.calcUnbondHandler,
+	}
+
 	cmdBlockchain := command.Command{
 		Name:        CommandName,
 		Desc:        "Blockchain information and tools",
@@ -79,11 +105,14 @@ func (bc *Blockchain) GetCommand() command.Command {
 
 	cmdBlockchain.AddSubCommand(subCmdCalcReward)
 	cmdBlockchain.AddSubCommand(subCmdCalcFee)
+	cmdBlockchain.AddSubCommand(subCmdCalcUnbond)
 
 	return cmdBlockchain
 }
 
-func (bc *Blockchain) calcRewardHandler(cmd command.Command, _ command.AppID, _ string, args ...string) command.CommandResult {
+func (bc *Blockchain) calcRewardHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
 	stake, err := strconv.Atoi(args[0])
 	if err != nil {
 		return cmd.ErrorResult(err)
@@ -108,31 +137,135 @@ func (bc *Blockchain) calcRewardHandler(cmd command.Command, _ command.AppID, _
 		time = "day"
 	}
 
-	bi, err := bc.clientMgr.GetBlockchainInfo()
+	bi, err := bc.clientMgr.GetBlockchainInfo(ctx)
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	reward := int64(stake*blocks) / int64(amount.Amount(bi.TotalPower).ToPAC())
+	// each block rewards 1 PAC, the share of the stake in the total power is the share of the rewards.
+	totalPower := amount.Amount(bi.TotalPower)
+	reward, err := amount.NewAmount(float64(stake*blocks) / totalPower.ToPAC())
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
 
-	return cmd.SuccessfulResult("Approximately you earn %v PAC reward, with %v PAC stake 🔒 on your validator in one %s ⏰ with %s total power ⚡ of committee."+
-		"\n\n> Note📝: This number is just an estimation. It will vary depending on your stake amount and total network power.",
-		utils.FormatNumber(reward), utils.FormatNumber(int64(stake)), time, utils.FormatNumber(int64(amount.Amount(bi.TotalPower).ToPAC())))
+	return cmd.RenderResult(appID, "reward_calc", map[string]any{
+		"Reward":     reward,
+		"Stake":      amount.Amount(int64(stake) * 1e9),
+		"Time":       time,
+		"TotalPower": totalPower,
+	})
 }
 
-func (bc *Blockchain) calcFeeHandler(cmd command.Command, _ command.AppID, _ string, args ...string) command.CommandResult {
+func (bc *Blockchain) calcFeeHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
 	amt, err := amount.FromString(args[0])
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	fee, err := bc.clientMgr.GetFee(int64(amt))
+	fee, err := bc.clientMgr.GetFee(ctx, int64(amt))
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "fee_calc", map[string]any{
+		"Amount": amt,
+		"Fee":    amount.Amount(fee),
+	})
+}
+
+// blockTimeSamples is how many of the last blocks the average block time is calculated with.
+const blockTimeSamples = 100
+
+// Cooldown is the bonding and unbonding heights of a validator, with the estimated time of the heights.
+type Cooldown struct {
+	Address           string
+	Stake             amount.Amount
+	Height            uint32
+	BlockTime         time.Duration
+	LastBondingHeight uint32
+	ActiveHeight      uint32 // The height that the last bonded stake joins the sortition.
+	ActiveTime        time.Time
+	UnbondingHeight   uint32 // Zero if the validator is not unbonded.
+	WithdrawHeight    uint32 // If the validator is not unbonded, the height if it unbonds now.
+	WithdrawTime      time.Time
+}
+
+func (c Cooldown) Unbonded() bool {
+	return c.UnbondingHeight > 0
+}
+
+func (c Cooldown) Active() bool {
+	return c.Height >= c.ActiveHeight
+}
+
+func (c Cooldown) Withdrawable() bool {
+	return c.Unbonded() && c.Height >= c.WithdrawHeight
+}
+
+func (bc *Blockchain) calcUnbondHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	addr, err := crypto.AddressFromString(args[0])
+	if err != nil || !addr.IsValidatorAddress() {
+		return cmd.FailedResult("%s is not a validator address", args[0])
+	}
+
+	res, err := bc.clientMgr.GetValidatorInfo(ctx, addr.String())
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
+	val := res.Validator
+
+	lastBlockTime, height := bc.clientMgr.GetLastBlockTime(ctx)
+	if height == 0 {
+		return cmd.FailedResult("can't get the last block of the network, please try again later")
+	}
+
+	params := param.DefaultParams()
+	cooldown := Cooldown{
+		Address:           val.Address,
+		Stake:             amount.Amount(val.Stake),
+		Height:            height,
+		BlockTime:         bc.averageBlockTime(ctx, lastBlockTime, height),
+		LastBondingHeight: val.LastBondingHeight,
+		ActiveHeight:      val.LastBondingHeight + params.BondInterval,
+		UnbondingHeight:   val.UnbondingHeight,
+		WithdrawHeight:    height + params.UnbondInterval,
+	}
 
-	calcedFee := amount.Amount(fee)
+	if cooldown.Unbonded() {
+		cooldown.WithdrawHeight = val.UnbondingHeight + params.UnbondInterval
+	}
+
+	cooldown.ActiveTime = cooldown.heightTime(lastBlockTime, cooldown.ActiveHeight)
+	cooldown.WithdrawTime = cooldown.heightTime(lastBlockTime, cooldown.WithdrawHeight)
+
+	return cmd.RenderResult(appID, "unbond_calc", cooldown).
+		WithReference(command.ReferenceValidator, val.Address)
+}
+
+// heightTime estimates the time of the height, from the time of the last block.
+func (c Cooldown) heightTime(lastBlockTime, height uint32) time.Time {
+	blocks := time.Duration(int64(height) - int64(c.Height))
+
+	return time.Unix(int64(lastBlockTime), 0).Add(blocks * c.BlockTime)
+}
+
+// averageBlockTime returns the average block time of the last blocks,
+// or the block interval of the protocol if the blocks can't be read.
+func (bc *Blockchain) averageBlockTime(ctx context.Context, lastBlockTime, height uint32) time.Duration {
+	fallback := param.DefaultParams().BlockInterval()
+	if height <= blockTimeSamples {
+		return fallback
+	}
+
+	block, err := bc.clientMgr.GetBlockTransactions(ctx, height-blockTimeSamples)
+	if err != nil || block.BlockTime >= lastBlockTime {
+		return fallback
+	}
 
-	return cmd.SuccessfulResult("Sending %s will cost %s with current fee percentage."+
-		"\n> Note: Consider unbond and sortition transaction fee is 0 PAC always.", amt, calcedFee.String())
+	return time.Duration(lastBlockTime-block.BlockTime) * time.Second / blockTimeSamples
 }
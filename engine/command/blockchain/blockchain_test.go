@@ -0,0 +1,62 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pactus-project/pactus/util/testsuite"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCalcUnbond(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	bc := NewBlockchain(cm)
+	cmd := bc.GetCommand()
+
+	valAddr := ts.RandValAddress().String()
+	// 2024-01-01 00:00:00 UTC, the last 100 blocks are 12 seconds apart.
+	lastBlockTime := uint32(1_704_067_200)
+	c.EXPECT().LastBlockTime(gomock.Any()).Return(lastBlockTime, uint32(10_000), nil).AnyTimes()
+	c.EXPECT().GetBlockTransactions(gomock.Any(), uint32(9_900)).
+		Return(&pactus.GetBlockResponse{BlockTime: lastBlockTime - 1_200}, nil).AnyTimes()
+
+	t.Run("bonded", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfo(gomock.Any(), valAddr).Return(&pactus.GetValidatorResponse{
+			Validator: &pactus.ValidatorInfo{Address: valAddr, Stake: 1e12, LastBondingHeight: 9_800},
+		}, nil)
+
+		res := bc.calcUnbondHandler(context.Background(), cmd, command.AppIdCLI, "user-id", valAddr)
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "last bonded at block 9,800")
+		assert.Contains(t, res.Message, "joins the sortition at block 10,160, about 01/01/2024, 00:32 UTC")
+		assert.Contains(t, res.Message, "If it unbonds now, the stake becomes withdrawable at block 191,440")
+		assert.Contains(t, res.Message, "average block time of the last 100 blocks: 12s")
+	})
+
+	t.Run("unbonded", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfo(gomock.Any(), valAddr).Return(&pactus.GetValidatorResponse{
+			Validator: &pactus.ValidatorInfo{Address: valAddr, LastBondingHeight: 100, UnbondingHeight: 5_000},
+		}, nil)
+
+		res := bc.calcUnbondHandler(context.Background(), cmd, command.AppIdCLI, "user-id", valAddr)
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "in the sortition since block 460")
+		assert.Contains(t, res.Message, "Unbonded at block 5,000, the stake becomes withdrawable at block 186,440")
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		res := bc.calcUnbondHandler(context.Background(), cmd, command.AppIdCLI, "user-id",
+			ts.RandAccAddress().String())
+		assert.False(t, res.Successful)
+	})
+}
@@ -0,0 +1,26 @@
+package command
+
+// Capability is a rendering feature that a platform may or may not support.
+type Capability int
+
+const (
+	CapabilityImage Capability = 1 << iota
+	// CapabilityPrompt is a chat that the next message of the user can answer a question, like a missing argument.
+	CapabilityPrompt
+)
+
+func (appID AppID) capabilities() Capability {
+	switch appID {
+	case AppIdDiscord, AppIdTelegram:
+		return CapabilityImage | CapabilityPrompt
+	case AppIdCLI, AppIdgRPC, AppIdHTTP, AppIdQueue:
+		return 0
+	}
+
+	return 0
+}
+
+// Supports reports whether the platform is able to render the given capability.
+func (appID AppID) Supports(c Capability) bool {
+	return appID.capabilities()&c == c
+}
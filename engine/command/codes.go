@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pagu-project/Pagu/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCode is the machine-readable type of a failed result, the API consumers and the dashboards branch on it.
+// The codes are part of the HTTP API, they are not renamed.
+type ErrorCode string
+
+const (
+	ErrCodeFailed         ErrorCode = "ERR_FAILED" // The default of the failed results, like a rejected request.
+	ErrCodeInternal       ErrorCode = "ERR_INTERNAL"
+	ErrCodeRPCUnavailable ErrorCode = "ERR_RPC_UNAVAILABLE"
+	ErrCodeInvalidAddress ErrorCode = "ERR_INVALID_ADDRESS"
+	ErrCodeInvalidArgs    ErrorCode = "ERR_INVALID_ARGS"
+	ErrCodeNotFound       ErrorCode = "ERR_NOT_FOUND"
+	ErrCodeUnknownCommand ErrorCode = "ERR_UNKNOWN_COMMAND"
+	ErrCodeUnauthorized   ErrorCode = "ERR_UNAUTHORIZED"
+	ErrCodeRateLimited    ErrorCode = "ERR_RATE_LIMITED"
+	ErrCodeQuotaExceeded  ErrorCode = "ERR_QUOTA_EXCEEDED"
+	ErrCodeDisabled       ErrorCode = "ERR_DISABLED" // The command is disabled by a feature flag or the read-only mode.
+	ErrCodeMaintenance    ErrorCode = "ERR_MAINTENANCE"
+	ErrCodeTimeout        ErrorCode = "ERR_TIMEOUT" // The handler took longer than the timeout, like on a slow node.
+)
+
+// ErrorCodeOf returns the code of the error, the unknown errors are internal.
+// The errors of the RPC nodes are classified by their gRPC status.
+func ErrorCodeOf(err error) ErrorCode {
+	var argsErr ArgsError
+	var addrErr AddressError
+	var netErr NetworkError
+	var notFoundErr client.NotFoundError
+
+	switch {
+	case err == nil:
+		return ""
+
+	case errors.As(err, &argsErr):
+		return ErrCodeInvalidArgs
+
+	case errors.As(err, &addrErr), errors.As(err, &netErr):
+		return ErrCodeInvalidAddress
+
+	case errors.As(err, &notFoundErr):
+		return ErrCodeNotFound
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrCodeRPCUnavailable
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+			return ErrCodeRPCUnavailable
+
+		case codes.NotFound:
+			return ErrCodeNotFound
+
+		case codes.InvalidArgument:
+			return ErrCodeInvalidArgs
+
+		default:
+		}
+	}
+
+	return ErrCodeInternal
+}
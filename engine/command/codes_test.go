@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		code ErrorCode
+	}{
+		{nil, ""},
+		{errors.New("boom"), ErrCodeInternal},
+		{ArgsError{Min: 1, Got: 0}, ErrCodeInvalidArgs},
+		{AddressError{Address: "pc1zabc"}, ErrCodeInvalidAddress},
+		{
+			fmt.Errorf("can't check: %w", NetworkError{Address: "tpc1z", Network: "Testnet", Want: "Mainnet"}),
+			ErrCodeInvalidAddress,
+		},
+		{client.NotFoundError{Search: "peer", Address: "pc1p"}, ErrCodeNotFound},
+		{context.DeadlineExceeded, ErrCodeRPCUnavailable},
+		{status.Error(codes.Unavailable, "connection refused"), ErrCodeRPCUnavailable},
+		{status.Error(codes.NotFound, "validator not found"), ErrCodeNotFound},
+		{status.Error(codes.InvalidArgument, "invalid address"), ErrCodeInvalidArgs},
+		{status.Error(codes.Internal, "panic"), ErrCodeInternal},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, ErrorCodeOf(tt.err), "%v", tt.err)
+	}
+}
+
+func TestResultCode(t *testing.T) {
+	cmd := &Command{Name: "test"}
+
+	assert.Empty(t, cmd.SuccessfulResult("ok").Code)
+	assert.Equal(t, ErrCodeFailed, cmd.FailedResult("no").Code)
+	assert.Equal(t, ErrCodeRPCUnavailable, cmd.ErrorResult(status.Error(codes.Unavailable, "down")).Code)
+	assert.Equal(t, ErrCodeRateLimited, cmd.FailedResult("slow down").WithCode(ErrCodeRateLimited).Code)
+}
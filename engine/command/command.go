@@ -1,8 +1,11 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"slices"
+	"strings"
+	"time"
 )
 
 type AppID int
@@ -13,6 +16,7 @@ const (
 	AppIdgRPC     AppID = 3
 	AppIdHTTP     AppID = 4
 	AppIdTelegram AppID = 5
+	AppIdQueue    AppID = 6
 )
 
 func (appID AppID) String() string {
@@ -27,11 +31,24 @@ func (appID AppID) String() string {
 		return "HTTP"
 	case AppIdTelegram:
 		return "Telegram"
+	case AppIdQueue:
+		return "Queue"
 	}
 
 	return ""
 }
 
+// ParseAppID returns the AppID with the given name, case-insensitive.
+func ParseAppID(name string) (AppID, bool) {
+	for _, appID := range AllAppIDs() {
+		if strings.EqualFold(appID.String(), name) {
+			return appID, true
+		}
+	}
+
+	return 0, false
+}
+
 func AllAppIDs() []AppID {
 	return []AppID{
 		AppIdCLI,
@@ -39,6 +56,7 @@ func AllAppIDs() []AppID {
 		AppIdgRPC,
 		AppIdHTTP,
 		AppIdTelegram,
+		AppIdQueue,
 	}
 }
 
@@ -46,6 +64,9 @@ type Args struct {
 	Name     string
 	Desc     string
 	Optional bool
+	Variadic bool    // The last argument takes the rest of the words, like a free text.
+	Kind     ArgKind // The kind of the value, the text is the default.
+	Upload   bool    // The value can be uploaded in a text file too, like a list of validators.
 }
 
 type Command struct {
@@ -57,23 +78,110 @@ type Command struct {
 	Args        []Args //! should be nil for commands.
 	AppIDs      []AppID
 	SubCommands []Command
-	Handler     func(cmd Command, source AppID, callerID string, args ...string) CommandResult
+	AdminOnly   bool // Only the authorized IDs can run the command and its sub-commands.
+	ServerAdmin bool // The admins of a server run it for their own server, its first argument is the server ID.
+	Challenge   bool // The caller should pass a challenge, like a captcha, before running the command.
+	Expensive   bool // Counted in the daily quota of the guild, like the commands that scan the blocks.
+	Mutating    bool // Changes the state, like the faucet and the payouts; it's disabled in the read-only mode.
+	PerBlock    bool // The result only changes per block, it's cached for the last block height, like the network status.
+	Testnet     bool // The addresses of the command and its sub-commands are Testnet addresses, like Phoenix.
+	Ephemeral   bool // The result has the data of the caller, like the claim status; only the caller sees it on Discord.
+	Deprecated  bool
+	ReplacedBy  string        // The command to use instead of the deprecated one, like: "network status".
+	SunsetAt    time.Time     // The deprecated command is hidden from help after this time.
+	Examples    []string      // Example arguments shown in the detailed help, like: "100 day".
+	CallerArgs  []string      // The per block results with these arguments are of the caller, like: "--diff".
+	Aliases     []Alias       // Localized names of the command.
+	Timeout     time.Duration // The handler is given up after this time, zero uses the timeout of the engine.
+	Handler     func(ctx context.Context, cmd Command, source AppID, callerID string, args ...string) CommandResult
+
+	echoFormat Format   // The markup of the platform that the results are sent to, see WithEchoes.
+	echoes     []string // The arguments that are escaped when the results echo them.
 }
 
 type CommandResult struct {
-	Color      string
-	Title      string
-	Error      string
-	Message    string
-	Successful bool
+	Color       string
+	Title       string
+	Error       string
+	Message     string
+	Successful  bool
+	Attachments []Attachment
+	Reference   Reference
+	Ephemeral   bool          // Only the caller sees the result, where the platform supports it.
+	Code        ErrorCode     // The type of the failure, empty for the successful results.
+	RetryAfter  time.Duration // The time to retry a blocked command in, like the rest of a rate limit window.
+	RequestID   string        // The ID of the command in the logs and the traces, set by the engine.
+}
+
+// ReferenceValidator is the kind of the reference to a validator, the value is the validator address.
+const ReferenceValidator = "validator"
+
+// Reference is the entity that a result is about, like a validator.
+// The engine keeps it for a while to resolve the follow-up questions, like: "and its stake?".
+type Reference struct {
+	Kind  string
+	Value string
+}
+
+// Attachment is a file sent along with the result message, like a QR code image.
+// Platforms that don't support the attachment type ignore it.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	AltText     string // The description of an image for the screen readers, like the key figures of a chart.
+}
+
+// WithAttachment returns a copy of the result with the given attachment appended.
+// The alt text of an image is added to the message too, for the platforms that can't attach it to the image.
+func (res CommandResult) WithAttachment(att Attachment) CommandResult {
+	res.Attachments = append(res.Attachments, att)
+	res.Message = WithAltText(res.Message, []Attachment{att})
+
+	return res
+}
+
+// WithAltText appends the fallback line of the alt text of each image to the message, like: "Image: Bar chart of ...".
+func WithAltText(msg string, attachments []Attachment) string {
+	for _, att := range attachments {
+		if att.AltText == "" {
+			continue
+		}
+
+		msg += "\n\nImage: " + att.AltText
+	}
+
+	return msg
+}
+
+// WithCode returns the failed result with the code, like: ErrCodeRateLimited.
+func (res CommandResult) WithCode(code ErrorCode) CommandResult {
+	res.Code = code
+
+	return res
+}
+
+// WithRetryAfter returns the result of a blocked command that can be retried after the duration.
+func (res CommandResult) WithRetryAfter(d time.Duration) CommandResult {
+	res.RetryAfter = d
+
+	return res
+}
+
+// WithReference returns the result that refers to the entity of the kind.
+func (res CommandResult) WithReference(kind, value string) CommandResult {
+	res.Reference = Reference{Kind: kind, Value: value}
+
+	return res
 }
 
 func (cmd *Command) SuccessfulResult(message string, a ...interface{}) CommandResult {
 	return CommandResult{
 		Color:      cmd.Color,
 		Title:      fmt.Sprintf("%v %v", cmd.Desc, cmd.Emoji),
-		Message:    fmt.Sprintf(message, a...),
+		Message:    fmt.Sprintf(message, cmd.echoArgs(a)...),
 		Successful: true,
+		Ephemeral:  cmd.Ephemeral,
 	}
 }
 
@@ -81,13 +189,16 @@ func (cmd *Command) FailedResult(message string, a ...interface{}) CommandResult
 	return CommandResult{
 		Color:      cmd.Color,
 		Title:      fmt.Sprintf("%v %v", cmd.Desc, cmd.Emoji),
-		Message:    fmt.Sprintf(message, a...),
+		Message:    fmt.Sprintf(message, cmd.echoArgs(a)...),
 		Successful: false,
+		Ephemeral:  cmd.Ephemeral,
+		Code:       ErrCodeFailed,
 	}
 }
 
+// ErrorResult returns the failed result of the error, the code is classified from the error.
 func (cmd *Command) ErrorResult(err error) CommandResult {
-	return cmd.FailedResult("An error occurred: %v", err.Error())
+	return cmd.FailedResult("An error occurred: %v", err.Error()).WithCode(ErrorCodeOf(err))
 }
 
 func (cmd *Command) HelpResult() CommandResult {
@@ -109,8 +220,12 @@ func (cmd *Command) CheckArgs(input []string) error {
 		}
 	}
 
+	if len(cmd.Args) > 0 && cmd.Args[len(cmd.Args)-1].Variadic && len(input) >= minArg {
+		return nil
+	}
+
 	if len(input) < minArg || len(input) > maxArg {
-		return fmt.Errorf("incorrect number of arguments, expected %d but got %d", minArg, len(input))
+		return ArgsError{Min: minArg, Got: len(input)}
 	}
 
 	return nil
@@ -125,16 +240,39 @@ func (cmd *Command) HasSubCommand() bool {
 }
 
 func (cmd *Command) HelpMessage() string {
+	return cmd.helpMessage(func(sc Command) bool {
+		return !sc.IsSunset(time.Now())
+	})
+}
+
+func (cmd *Command) helpMessage(visible func(Command) bool) string {
 	help := cmd.Help
 	help += "\n\nAvailable commands:\n"
 	for _, sc := range cmd.SubCommands {
+		if !visible(sc) {
+			continue
+		}
+
 		help += fmt.Sprintf("  %-12s %s\n", sc.Name, sc.Desc)
 	}
 
 	return help
 }
 
+// IsSunset checks if the command is deprecated and its sunset time is passed.
+func (cmd *Command) IsSunset(now time.Time) bool {
+	return cmd.Deprecated && !cmd.SunsetAt.IsZero() && now.After(cmd.SunsetAt)
+}
+
 func (cmd *Command) AddSubCommand(subCmd Command) {
+	if cmd.AdminOnly {
+		subCmd.setAdminOnly()
+	}
+
+	if cmd.Testnet {
+		subCmd.setTestnet()
+	}
+
 	if subCmd.HasSubCommand() {
 		subCmd.AddHelpSubCommand()
 	}
@@ -142,12 +280,33 @@ func (cmd *Command) AddSubCommand(subCmd Command) {
 	cmd.SubCommands = append(cmd.SubCommands, subCmd)
 }
 
+func (cmd *Command) setAdminOnly() {
+	cmd.AdminOnly = true
+	for i := range cmd.SubCommands {
+		cmd.SubCommands[i].setAdminOnly()
+	}
+}
+
+func (cmd *Command) setTestnet() {
+	cmd.Testnet = true
+	for i := range cmd.SubCommands {
+		cmd.SubCommands[i].setTestnet()
+	}
+}
+
 func (cmd *Command) AddHelpSubCommand() {
 	helpCmd := Command{
-		Name:   "help",
-		Desc:   fmt.Sprintf("Help for %v command", cmd.Name),
+		Name: HelpCommandName,
+		Desc: fmt.Sprintf("Help for %v command", cmd.Name),
+		Args: []Args{
+			{
+				Name:     "command",
+				Desc:     "Command to get the details and examples of, like: status",
+				Optional: true,
+			},
+		},
 		AppIDs: AllAppIDs(),
-		Handler: func(_ Command, _ AppID, _ string, _ ...string) CommandResult {
+		Handler: func(_ context.Context, _ Command, _ AppID, _ string, _ ...string) CommandResult {
 			return cmd.SuccessfulResult(cmd.HelpMessage())
 		},
 	}
@@ -0,0 +1,42 @@
+package command
+
+import "fmt"
+
+type AliasError struct {
+	Alias  string
+	Reason string
+}
+
+func (e AliasError) Error() string {
+	return fmt.Sprintf("invalid alias %s: %s", e.Alias, e.Reason)
+}
+
+// NetworkError is an address of another network, like a Testnet address for a Mainnet command.
+type NetworkError struct {
+	Address string
+	Network string
+	Want    string
+}
+
+func (e NetworkError) Error() string {
+	return fmt.Sprintf("%s is a %s address, this command needs a %s address", e.Address, e.Network, e.Want)
+}
+
+// AddressError is an argument that starts like an address but is not a valid one, like a mistyped address.
+type AddressError struct {
+	Address string
+}
+
+func (e AddressError) Error() string {
+	return fmt.Sprintf("%s is not a valid address, please check it", e.Address)
+}
+
+// ArgsError is a wrong number of arguments of a command.
+type ArgsError struct {
+	Min int
+	Got int
+}
+
+func (e ArgsError) Error() string {
+	return fmt.Sprintf("incorrect number of arguments, expected %d but got %d", e.Min, e.Got)
+}
@@ -0,0 +1,206 @@
+package feedback
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+)
+
+const (
+	CommandName        = "feedback"
+	ListCommandName    = "list"
+	ResolveCommandName = "resolve"
+	HelpCommandName    = "help"
+)
+
+const (
+	// MaxTextLength is the maximum length of a feedback in characters, the rest is cut.
+	MaxTextLength = 1000
+
+	// listLimit is how many of the open feedbacks are listed, the oldest first.
+	listLimit = 20
+)
+
+type Feedback struct {
+	db       *database.DB
+	hub      *notify.Hub
+	channels []notify.Channel
+}
+
+func NewFeedback(db *database.DB, hub *notify.Hub, channels []notify.Channel) Feedback {
+	return Feedback{
+		db:       db,
+		hub:      hub,
+		channels: channels,
+	}
+}
+
+// Item is a feedback with the name of its platform.
+type Item struct {
+	*database.Feedback
+	Platform string
+}
+
+func newItem(f *database.Feedback) Item {
+	return Item{
+		Feedback: f,
+		Platform: command.AppID(f.AppID).String(),
+	}
+}
+
+func (f *Feedback) GetCommand() command.Command {
+	return command.Command{
+		Emoji: "💬",
+		Name:  CommandName,
+		Desc:  "Send a suggestion or a bug to the maintainers",
+		Help:  "Your feedback is kept for the maintainers and forwarded to their channel, thanks for helping Pagu!",
+		Args: []command.Args{
+			{
+				Name:     "text",
+				Desc:     "Your suggestion or the bug [example: Add the rank of the validators]",
+				Optional: false,
+				Variadic: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"Add the rank of the validators"},
+		Mutating:    true,
+		Handler:     f.feedbackHandler,
+	}
+}
+
+// GetAdminCommand returns the triage commands of the feedbacks, they are added to the admin commands.
+func (f *Feedback) GetAdminCommand() command.Command {
+	subCmdList := command.Command{
+		Name:        ListCommandName,
+		Desc:        "The open feedbacks",
+		Help:        "Lists the oldest open feedbacks of the users",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     f.listHandler,
+	}
+
+	subCmdResolve := command.Command{
+		Name: ResolveCommandName,
+		Desc: "Resolve a feedback",
+		Help: "Closes the feedback, like when the suggestion is done or the bug is fixed",
+		Args: []command.Args{
+			{
+				Name:     "id",
+				Desc:     "ID of the feedback [example: 12]",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"12"},
+		Mutating:    true,
+		Handler:     f.resolveHandler,
+	}
+
+	cmdFeedback := command.Command{
+		Name:        CommandName,
+		Desc:        "Triage of the user feedbacks",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdFeedback.AddSubCommand(subCmdList)
+	cmdFeedback.AddSubCommand(subCmdResolve)
+
+	return cmdFeedback
+}
+
+func (f *Feedback) feedbackHandler(ctx context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		return cmd.FailedResult("Please write your suggestion or the bug")
+	}
+
+	if runes := []rune(text); len(runes) > MaxTextLength {
+		text = string(runes[:MaxTextLength])
+	}
+
+	fb := &database.Feedback{
+		AppID:  int(appID),
+		UserID: callerID,
+		Text:   text,
+	}
+	if err := f.db.AddFeedback(fb); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	f.forward(ctx, newItem(fb))
+
+	return cmd.SuccessfulResult("Thanks for your feedback, it's #%d for the maintainers", fb.ID)
+}
+
+// forward posts the feedback to the maintainer channels, a failed post is kept in the database anyway.
+func (f *Feedback) forward(ctx context.Context, item Item) {
+	for _, channel := range f.channels {
+		if !f.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		msg, err := command.RenderTemplate(channel.AppID, "feedback_forward", item)
+		if err != nil {
+			log.Ctx(ctx).Error("can't render the feedback", "err", err)
+
+			continue
+		}
+
+		if err := f.hub.Announce(channel, msg); err != nil {
+			log.Ctx(ctx).Warn("can't forward the feedback", "err", err, "channel", channel, "id", item.ID)
+		}
+	}
+}
+
+func (f *Feedback) listHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	feedbacks, err := f.db.GetOpenFeedbacks(listLimit)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	items := make([]Item, 0, len(feedbacks))
+	for _, fb := range feedbacks {
+		items = append(items, newItem(fb))
+	}
+
+	return cmd.RenderResult(appID, "admin_feedback", map[string]any{
+		"Feedbacks": items,
+	})
+}
+
+func (f *Feedback) resolveHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	id, err := strconv.ParseUint(strings.TrimPrefix(args[0], "#"), 10, 32)
+	if err != nil {
+		return cmd.FailedResult("%s is not a feedback ID, like: 12", args[0])
+	}
+
+	resolved, err := f.db.ResolveFeedback(uint(id), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !resolved {
+		return cmd.FailedResult("No open feedback with ID #%d", id)
+	}
+
+	return cmd.SuccessfulResult("The feedback #%d is resolved", id)
+}
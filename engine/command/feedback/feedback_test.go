@@ -0,0 +1,74 @@
+package feedback
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func TestFeedback(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	f := NewFeedback(db, hub, []notify.Channel{{AppID: command.AppIdDiscord, ID: "maintainers"}})
+	cmd := f.GetCommand()
+	adminCmd := f.GetAdminCommand()
+
+	args := strings.Fields("Add the rank of the validators")
+	require.NoError(t, cmd.CheckArgs(args), "the text has many words")
+	require.Error(t, cmd.CheckArgs(nil))
+
+	res := f.feedbackHandler(context.Background(), cmd, command.AppIdTelegram, "123", args...)
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "Thanks for your feedback, it's #1 for the maintainers", res.Message)
+	require.Len(t, discord["maintainers"], 1)
+	assert.Contains(t, discord["maintainers"][0], "New feedback #1📝 from Telegram user 123:\nAdd the rank of the validators")
+
+	res = f.feedbackHandler(context.Background(), cmd, command.AppIdDiscord, "456",
+		strings.Repeat("a", MaxTextLength+1))
+	require.True(t, res.Successful, res.Message)
+
+	res = f.listHandler(context.Background(), adminCmd, command.AppIdCLI, "admin-id")
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "#1 from Telegram user 123 at ")
+	assert.Contains(t, res.Message, "#2 from Discord user 456 at ")
+
+	res = f.resolveHandler(context.Background(), adminCmd, command.AppIdCLI, "admin-id", "#1")
+	require.True(t, res.Successful, res.Message)
+
+	res = f.resolveHandler(context.Background(), adminCmd, command.AppIdCLI, "admin-id", "1")
+	assert.False(t, res.Successful, "resolved already")
+
+	res = f.resolveHandler(context.Background(), adminCmd, command.AppIdCLI, "admin-id", "first")
+	assert.False(t, res.Successful)
+
+	res = f.listHandler(context.Background(), adminCmd, command.AppIdCLI, "admin-id")
+	assert.NotContains(t, res.Message, "#1 from")
+
+	items, err := db.GetOpenFeedbacks(10)
+	require.NoError(t, err)
+	assert.Len(t, items[0].Text, MaxTextLength, "the long text is cut")
+}
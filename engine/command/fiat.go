@@ -0,0 +1,35 @@
+package command
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pagu-project/Pagu/utils"
+)
+
+// amountPattern matches the amounts of FormatAmount, like: "1,234.56 PAC" or "1,234 NanoPAC".
+var amountPattern = regexp.MustCompile(`(-?\d{1,3}(?:,\d{3})*(?:\.\d+)?) (PAC|NanoPAC)\b`)
+
+// HasAmounts returns true if the message has an amount of FormatAmount.
+func HasAmounts(msg string) bool {
+	return amountPattern.MatchString(msg)
+}
+
+// AppendFiat appends the fiat value of each amount of the message after it, like: "12 PAC (≈1.08 EUR)".
+// The fiat function formats the value of the amount in PAC.
+func AppendFiat(msg string, fiat func(pac float64) string) string {
+	return amountPattern.ReplaceAllStringFunc(msg, func(match string) string {
+		groups := amountPattern.FindStringSubmatch(match)
+		value, err := strconv.ParseFloat(strings.ReplaceAll(groups[1], ",", ""), 64)
+		if err != nil {
+			return match
+		}
+
+		if groups[2] == utils.UnitNanoPAC {
+			value /= 1e9
+		}
+
+		return match + " (" + fiat(value) + ")"
+	})
+}
@@ -0,0 +1,24 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendFiat(t *testing.T) {
+	usd := func(pac float64) string {
+		return fmt.Sprintf("%.2f USD", pac*0.5)
+	}
+
+	assert.True(t, HasAmounts("Stake: 1,000 PAC"))
+	assert.False(t, HasAmounts("Validators: 1,000"))
+	assert.False(t, HasAmounts("The PACT protocol"))
+
+	assert.Equal(t, "Stake: 1,000.5 PAC (500.25 USD), fee: 0.02 PAC (0.01 USD)",
+		AppendFiat("Stake: 1,000.5 PAC, fee: 0.02 PAC", usd))
+	assert.Equal(t, "Reward: 2,000,000,000 NanoPAC (1.00 USD)", AppendFiat("Reward: 2,000,000,000 NanoPAC", usd))
+	assert.Equal(t, "Change: -12 PAC (-6.00 USD)", AppendFiat("Change: -12 PAC", usd))
+	assert.Equal(t, "No amount", AppendFiat("No amount", usd))
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// OutputFileName is the name of the text file that has the full message of a long result.
+const OutputFileName = "output.txt"
+
+// fitNote ends the summary of a long result.
+const fitNote = "\n\n… The result is too long, the full result is in " + OutputFileName
+
+// Fit fits the message of the result in the limit of the platform, in characters after the message is linkified.
+// A longer message is summarized by its first lines, and the full message is attached as a text file,
+// so the platform doesn't reject or cut it. A limit of zero doesn't limit the message.
+func (res CommandResult) Fit(appID AppID, limit int) CommandResult {
+	if limit <= 0 || messageLength(appID, res.Message) <= limit {
+		return res
+	}
+
+	full := res.Message
+	summary := ""
+	for _, line := range strings.Split(full, "\n") {
+		next := line
+		if summary != "" {
+			next = summary + "\n" + line
+		}
+
+		if messageLength(appID, next+fitNote) > limit {
+			break
+		}
+		summary = next
+	}
+
+	// the first line is too long, it's cut until it fits.
+	if summary == "" {
+		runes := []rune(strings.SplitN(full, "\n", 2)[0])
+		size := len(runes)
+		for size > 0 && messageLength(appID, string(runes[:size])+fitNote) > limit {
+			size = size * 9 / 10
+		}
+		summary = string(runes[:size])
+	}
+
+	res.Message = summary + fitNote
+
+	return res.WithAttachment(Attachment{
+		Name:        OutputFileName,
+		ContentType: "text/plain; charset=utf-8",
+		Data:        []byte(full),
+	})
+}
+
+func messageLength(appID AppID, msg string) int {
+	return utf8.RuneCountInString(Linkify(appID, msg))
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFit(t *testing.T) {
+	res := CommandResult{Message: "short", Successful: true}
+	assert.Equal(t, res, res.Fit(AppIdDiscord, 100))
+	assert.Equal(t, res, res.Fit(AppIdDiscord, 0), "zero doesn't limit")
+
+	lines := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		lines = append(lines, strings.Repeat("x", 9))
+	}
+	long := strings.Join(lines, "\n")
+
+	fitted := CommandResult{Message: long, Successful: true}.Fit(AppIdDiscord, 100)
+	assert.True(t, fitted.Successful)
+	assert.LessOrEqual(t, len([]rune(fitted.Message)), 100)
+	assert.True(t, strings.HasPrefix(fitted.Message, lines[0]+"\n"+lines[1]+"\n"))
+	assert.True(t, strings.HasSuffix(fitted.Message, fitNote))
+	require.Len(t, fitted.Attachments, 1)
+	assert.Equal(t, OutputFileName, fitted.Attachments[0].Name)
+	assert.Equal(t, long, string(fitted.Attachments[0].Data), "the full message is attached")
+
+	t.Run("the links count", func(t *testing.T) {
+		addr := "pc1zgp0x33hehvczq6dtfjyh9ca8nd0cyw8m8yppaa"
+		msg := strings.Repeat(addr+"\n", 3)
+		assert.Equal(t, msg, CommandResult{Message: msg}.Fit(AppIdCLI, 200).Message)
+
+		fitted := CommandResult{Message: msg}.Fit(AppIdDiscord, 200)
+		assert.LessOrEqual(t, len([]rune(Linkify(AppIdDiscord, fitted.Message))), 200)
+		assert.Len(t, fitted.Attachments, 1)
+	})
+
+	t.Run("a long line is cut", func(t *testing.T) {
+		fitted := CommandResult{Message: strings.Repeat("界", 500)}.Fit(AppIdTelegram, 100)
+		assert.LessOrEqual(t, len([]rune(fitted.Message)), 100)
+		assert.True(t, strings.HasPrefix(fitted.Message, "界界"))
+	})
+}
@@ -0,0 +1,162 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const HelpCommandName = "help"
+
+// IsVisible checks if the command can be shown to the caller on the platform.
+// Admin-only commands are hidden from the other callers and sunset commands from everyone.
+func (cmd *Command) IsVisible(appID AppID, isAdmin bool) bool {
+	if !cmd.HasAppId(appID) || cmd.Name == "" {
+		return false
+	}
+
+	if cmd.AdminOnly && !isAdmin {
+		return false
+	}
+
+	return !cmd.IsSunset(time.Now())
+}
+
+// FindVisibleSubCommand finds the sub-command with the given name that is visible to the caller.
+func (cmd *Command) FindVisibleSubCommand(name string, appID AppID, isAdmin bool) (Command, bool) {
+	for _, sc := range cmd.SubCommands {
+		if sc.MatchName(name) && sc.IsVisible(appID, isAdmin) {
+			return sc, true
+		}
+	}
+
+	return Command{}, false
+}
+
+// HelpResultFor returns the help of the command, listing only the sub-commands visible to the caller.
+func (cmd *Command) HelpResultFor(appID AppID, isAdmin bool) CommandResult {
+	res := cmd.HelpResult()
+	res.Message = cmd.helpMessage(func(sc Command) bool {
+		return sc.IsVisible(appID, isAdmin)
+	})
+
+	return res
+}
+
+// Syntax is how the callers type the commands on a platform, the examples are shown in it to be copied.
+type Syntax struct {
+	Prefix string // Typed before the command, like "/" on Telegram or the command prefix of the server: "!pagu".
+	Named  bool   // The arguments are typed by their names, like the options of the Discord slash commands.
+}
+
+// PlatformSyntax returns the syntax of the commands on the platform, without a command prefix.
+func PlatformSyntax(appID AppID) Syntax {
+	switch appID {
+	case AppIdDiscord:
+		return Syntax{Prefix: "/", Named: true}
+	case AppIdTelegram:
+		return Syntax{Prefix: "/"}
+	default:
+		return Syntax{}
+	}
+}
+
+// Command returns the invocation of the command with the arguments in the syntax, like:
+// "/network node-info validator:pc1p..." on Discord or "!pagu network node-info pc1p..." with a prefix.
+func (s Syntax) Command(cmd *Command, fullName, args string) string {
+	prefix := s.Prefix
+	// a prefix that ends with a word is separated by a space, like the messages: "!pagu network status".
+	if last, _ := utf8.DecodeLastRuneInString(prefix); unicode.IsLetter(last) || unicode.IsDigit(last) {
+		prefix += " "
+	}
+
+	words := strings.Fields(args)
+	if !s.Named || len(words) == 0 {
+		return strings.TrimSpace(prefix + fullName + " " + args)
+	}
+
+	options := make([]string, 0, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		if i >= len(words) {
+			break
+		}
+
+		value := words[i]
+		if arg.Variadic || i == len(cmd.Args)-1 {
+			value = strings.Join(words[i:], " ")
+		}
+		options = append(options, arg.Name+":"+value)
+	}
+
+	return prefix + fullName + " " + strings.Join(options, " ")
+}
+
+// ExampleCommands returns the examples of the command in the syntax, they are copied and pasted by the callers.
+func (cmd *Command) ExampleCommands(syntax Syntax, fullName string) []string {
+	examples := make([]string, 0, len(cmd.Examples))
+	for _, example := range cmd.Examples {
+		examples = append(examples, syntax.Command(cmd, fullName, example))
+	}
+
+	return examples
+}
+
+// ArgsErrorResult returns the failed result of the wrong arguments with the usage and the examples of the command,
+// so the caller can fix the command, like a missing validator address.
+func (cmd *Command) ArgsErrorResult(appID AppID, syntax Syntax, fullName string, err error) CommandResult {
+	res := cmd.RenderResult(appID, "command_usage", map[string]any{
+		"Error":    err.Error(),
+		"Usage":    cmd.Usage(fullName),
+		"Examples": cmd.ExampleCommands(syntax, fullName),
+	})
+	if !res.Successful {
+		return res
+	}
+	res.Successful = false
+
+	return res.WithCode(ErrorCodeOf(err))
+}
+
+// DetailedHelpResult returns the usage, arguments and examples of the command in the syntax of the caller.
+// The full name is the path of the command, like: "network node-info".
+func (cmd *Command) DetailedHelpResult(appID AppID, syntax Syntax, isAdmin bool, fullName string) CommandResult {
+	subCmds := make([]Command, 0, len(cmd.SubCommands))
+	for _, sc := range cmd.SubCommands {
+		if sc.Name != HelpCommandName && sc.IsVisible(appID, isAdmin) {
+			subCmds = append(subCmds, sc)
+		}
+	}
+
+	return cmd.RenderResult(appID, "command_help", map[string]any{
+		"Name":        fullName,
+		"Usage":       cmd.Usage(fullName),
+		"Examples":    cmd.ExampleCommands(syntax, fullName),
+		"Command":     cmd,
+		"SubCommands": subCmds,
+	})
+}
+
+// Usage returns the usage line of the command, optional arguments are in brackets.
+func (cmd *Command) Usage(fullName string) string {
+	usage := fullName
+	if cmd.HasSubCommand() {
+		return fmt.Sprintf("%s <command>", usage)
+	}
+
+	args := make([]string, 0, len(cmd.Args))
+	for _, arg := range cmd.Args {
+		if arg.Optional {
+			args = append(args, fmt.Sprintf("[%s]", arg.Name))
+		} else {
+			args = append(args, fmt.Sprintf("<%s>", arg.Name))
+		}
+	}
+
+	if len(args) == 0 {
+		return usage
+	}
+
+	return fmt.Sprintf("%s %s", usage, strings.Join(args, " "))
+}
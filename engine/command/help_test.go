@@ -0,0 +1,28 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntax(t *testing.T) {
+	cmd := Command{
+		Name: "set",
+		Args: []Args{
+			{Name: "address"},
+			{Name: "name", Variadic: true},
+		},
+		Examples: []string{"pc1p... Pactus Lovers", "pc1p..."},
+	}
+
+	assert.Equal(t, []string{"alias set pc1p... Pactus Lovers", "alias set pc1p..."},
+		cmd.ExampleCommands(PlatformSyntax(AppIdCLI), "alias set"))
+	assert.Equal(t, []string{"/alias set pc1p... Pactus Lovers", "/alias set pc1p..."},
+		cmd.ExampleCommands(PlatformSyntax(AppIdTelegram), "alias set"))
+	assert.Equal(t, []string{"/alias set address:pc1p... name:Pactus Lovers", "/alias set address:pc1p..."},
+		cmd.ExampleCommands(PlatformSyntax(AppIdDiscord), "alias set"), "the options of the slash command")
+	assert.Equal(t, "!pagu alias set pc1p...", Syntax{Prefix: "!pagu"}.Command(&cmd, "alias set", "pc1p..."))
+	assert.Equal(t, "!alias set pc1p...", Syntax{Prefix: "!"}.Command(&cmd, "alias set", "pc1p..."))
+	assert.Equal(t, "/network status", PlatformSyntax(AppIdDiscord).Command(&Command{}, "network status", ""))
+}
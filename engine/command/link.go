@@ -0,0 +1,132 @@
+package command
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const DefaultExplorerURL = "https://pacviewer.com"
+
+var explorerURL = DefaultExplorerURL
+
+// linkPattern finds the values that are linked to the explorer. The code and the URLs are matched first,
+// so the values inside them are kept as they are, like an address to copy.
+var linkPattern = regexp.MustCompile("(```[\\s\\S]*?```|`[^`\n]*`)" +
+	`|(https?://\S+)` +
+	`|\b(t?pc1[02-9ac-hj-np-z]{38,})\b` +
+	`|\b([0-9a-f]{64})\b` +
+	`|((?i:\bblock(?: height)?:? #?))(\d[\d,]*\d|\d)`)
+
+// Format is the markup of an output, like the Markdown of Discord.
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// linkStyle is the link markup of a format, the rest of the message is escaped for the markup.
+type linkStyle struct {
+	escape func(text string) string
+	link   func(text, url string) string
+}
+
+var linkStyles = map[Format]linkStyle{
+	FormatMarkdown: {
+		escape: func(text string) string { return text },
+		link:   func(text, url string) string { return "[" + text + "](" + url + ")" },
+	},
+	FormatHTML: {
+		escape: html.EscapeString,
+		link: func(text, url string) string {
+			return `<a href="` + html.EscapeString(url) + `">` + html.EscapeString(text) + "</a>"
+		},
+	},
+}
+
+// platformFormats is the markup of the platforms, the others have no markup.
+var platformFormats = map[AppID]Format{
+	AppIdDiscord:  FormatMarkdown,
+	AppIdTelegram: FormatHTML,
+}
+
+// SetExplorer sets the base URL of the block explorer that the outputs link to, empty sets the default.
+func SetExplorer(url string) {
+	if url == "" {
+		url = DefaultExplorerURL
+	}
+
+	explorerURL = strings.TrimSuffix(url, "/")
+}
+
+// ExplorerURL returns the base URL of the block explorer.
+func ExplorerURL() string {
+	return explorerURL
+}
+
+func AddressURL(address string) string {
+	return explorerURL + "/address/" + address
+}
+
+func TransactionURL(txID string) string {
+	return explorerURL + "/transaction/" + txID
+}
+
+func BlockURL(height uint32) string {
+	return explorerURL + "/block/" + strconv.FormatUint(uint64(height), 10)
+}
+
+// Linkify links the addresses, transaction IDs and block heights of the message to the explorer,
+// in the link markup of the platform. The messages of the platforms without a markup are not changed.
+func Linkify(appID AppID, msg string) string {
+	return LinkifyFormat(platformFormats[appID], msg)
+}
+
+// LinkifyFormat links the message to the explorer in the markup of the format, the text format is not changed.
+func LinkifyFormat(format Format, msg string) string {
+	style, ok := linkStyles[format]
+	if !ok {
+		return msg
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range linkPattern.FindAllStringSubmatchIndex(msg, -1) {
+		start, end := m[0], m[1]
+		var text, url string
+		switch {
+		case m[2] >= 0:
+			// the code is copied as it is.
+			continue
+
+		case m[4] >= 0:
+			// a URL is linked by the platform itself.
+			continue
+
+		case m[6] >= 0:
+			text, url = msg[m[6]:m[7]], AddressURL(msg[m[6]:m[7]])
+
+		case m[8] >= 0:
+			text, url = msg[m[8]:m[9]], TransactionURL(msg[m[8]:m[9]])
+
+		default:
+			// only the number after "block" is linked.
+			start = m[12]
+			height, err := strconv.ParseUint(strings.ReplaceAll(msg[m[12]:m[13]], ",", ""), 10, 32)
+			if err != nil {
+				continue
+			}
+			text, url = msg[m[12]:m[13]], BlockURL(uint32(height))
+		}
+
+		sb.WriteString(style.escape(msg[last:start]))
+		sb.WriteString(style.link(text, url))
+		last = end
+	}
+	sb.WriteString(style.escape(msg[last:]))
+
+	return sb.String()
+}
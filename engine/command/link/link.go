@@ -0,0 +1,218 @@
+package link
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/engine/command/account"
+)
+
+const (
+	CommandName        = "link"
+	AddressCommandName = "address"
+	ConfirmCommandName = "confirm"
+	ListCommandName    = "list"
+	RemoveCommandName  = "remove"
+	HelpCommandName    = "help"
+)
+
+// linkTTL is how long the user has to sign the nonce and confirm the link.
+const linkTTL = 30 * time.Minute
+
+// Link links the users of the platforms to the addresses they own, they prove it by signing a nonce.
+// The address-bound features, like the faucet limits and the reward routing, can rely on the linked addresses.
+type Link struct {
+	db        *database.DB
+	clientMgr *client.Mgr
+	now       func() time.Time
+}
+
+func NewLink(db *database.DB, clientMgr *client.Mgr) Link {
+	return Link{
+		db:        db,
+		clientMgr: clientMgr,
+		now:       time.Now,
+	}
+}
+
+// Message returns the message that the user signs to link the address, the nonce makes it unique.
+func Message(address, nonce string) string {
+	return fmt.Sprintf("Link %s to Pagu, nonce: %s", address, nonce)
+}
+
+func (l *Link) GetCommand() command.Command {
+	subCmdAddress := command.Command{
+		Name: AddressCommandName,
+		Desc: "Start linking an address that you own",
+		Help: "Gives you a message to sign with your wallet, then send the signature with the confirm command",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "Your account or validator address [example: pc1z...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1z..."},
+		Mutating:    true,
+		Ephemeral:   true,
+		Handler:     l.addressHandler,
+	}
+
+	subCmdConfirm := command.Command{
+		Name: ConfirmCommandName,
+		Desc: "Confirm the link with the signature of the message",
+		Help: "Verifies the signature of the message that the address command gave you, and links the address",
+		Args: []command.Args{
+			{
+				Name:     "signature",
+				Desc:     "Signature of the message in hex",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Mutating:    true,
+		Ephemeral:   true,
+		Handler:     l.confirmHandler,
+	}
+
+	subCmdList := command.Command{
+		Name:        ListCommandName,
+		Desc:        "Your linked addresses",
+		Help:        "",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Ephemeral:   true,
+		Handler:     l.listHandler,
+	}
+
+	subCmdRemove := command.Command{
+		Name: RemoveCommandName,
+		Desc: "Unlink an address",
+		Help: "",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "The linked address [example: pc1z...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Mutating:    true,
+		Ephemeral:   true,
+		Handler:     l.removeHandler,
+	}
+
+	cmdLink := command.Command{
+		Emoji:       "🔗",
+		Name:        CommandName,
+		Desc:        "Link the addresses that you own",
+		Help:        "Prove that you own an address by signing a message with your wallet",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdLink.AddSubCommand(subCmdAddress)
+	cmdLink.AddSubCommand(subCmdConfirm)
+	cmdLink.AddSubCommand(subCmdList)
+	cmdLink.AddSubCommand(subCmdRemove)
+
+	return cmdLink
+}
+
+func (l *Link) addressHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	addr, err := crypto.AddressFromString(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	link := &database.AddressLink{
+		AppID:     int(appID),
+		UserID:    callerID,
+		Address:   addr.String(),
+		Nonce:     hex.EncodeToString(nonce),
+		ExpiresAt: l.now().Add(linkTTL),
+	}
+	if err := l.db.AddPendingAddressLink(link); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "link_address", map[string]any{
+		"Message": Message(link.Address, link.Nonce),
+		"Minutes": int(linkTTL.Minutes()),
+	})
+}
+
+func (l *Link) confirmHandler(ctx context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	link, err := l.db.GetPendingAddressLink(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if link == nil {
+		return cmd.FailedResult("You have no link to confirm, start it with: link address <address>")
+	}
+
+	msg := Message(link.Address, link.Nonce)
+	if err := account.VerifyMessage(ctx, l.clientMgr, link.Address, args[0], msg); err != nil {
+		return cmd.FailedResult("%v", err)
+	}
+
+	if err := l.db.VerifyAddressLink(link.ID); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("%s is linked to you", link.Address)
+}
+
+func (l *Link) listHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	_ ...string,
+) command.CommandResult {
+	links, err := l.db.GetAddressLinks(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "link_list", map[string]any{
+		"Links": links,
+	})
+}
+
+func (l *Link) removeHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	removed, err := l.db.DeleteAddressLink(int(appID), callerID, args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !removed {
+		return cmd.FailedResult("%s is not linked to you", args[0])
+	}
+
+	return cmd.SuccessfulResult("%s is unlinked", args[0])
+}
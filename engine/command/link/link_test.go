@@ -0,0 +1,111 @@
+package link
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pactus-project/pactus/util/testsuite"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLink(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	l := NewLink(db, cm)
+	cmd := l.GetCommand()
+	appID := command.AppIdTelegram
+
+	pub, prv := ts.RandBLSKeyPair()
+	addr := pub.AccountAddress().String()
+	c.EXPECT().GetPublicKey(gomock.Any(), addr).Return(pub.String(), nil).AnyTimes()
+
+	res := l.confirmHandler(context.Background(), cmd, appID, "alice", "aa")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "no link to confirm")
+
+	res = l.addressHandler(context.Background(), cmd, appID, "alice", addr)
+	require.True(t, res.Successful, res.Message)
+
+	pending, err := db.GetPendingAddressLink(int(appID), "alice")
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	msg := Message(addr, pending.Nonce)
+	assert.Contains(t, res.Message, msg)
+
+	res = l.confirmHandler(context.Background(), cmd, appID, "alice", prv.Sign([]byte("another message")).String())
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "not valid")
+
+	res = l.confirmHandler(context.Background(), cmd, appID, "alice", prv.Sign([]byte(msg)).String())
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, addr+" is linked to you", res.Message)
+
+	res = l.listHandler(context.Background(), cmd, appID, "alice")
+	assert.True(t, strings.Contains(res.Message, addr))
+
+	owner, err := db.GetAddressOwner(addr)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", owner.UserID)
+
+	t.Run("another user proves the ownership", func(t *testing.T) {
+		res := l.addressHandler(context.Background(), cmd, appID, "bob", addr)
+		require.True(t, res.Successful, res.Message)
+
+		pending, err := db.GetPendingAddressLink(int(appID), "bob")
+		require.NoError(t, err)
+
+		res = l.confirmHandler(context.Background(), cmd, appID, "bob",
+			prv.Sign([]byte(Message(addr, pending.Nonce))).String())
+		require.True(t, res.Successful, res.Message)
+
+		owner, err := db.GetAddressOwner(addr)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", owner.UserID)
+
+		res = l.listHandler(context.Background(), cmd, appID, "alice")
+		assert.Contains(t, res.Message, "no linked address")
+	})
+
+	t.Run("the old signature can't link again", func(t *testing.T) {
+		res := l.addressHandler(context.Background(), cmd, appID, "alice", addr)
+		require.True(t, res.Successful, res.Message)
+
+		res = l.confirmHandler(context.Background(), cmd, appID, "alice", prv.Sign([]byte(msg)).String())
+		assert.False(t, res.Successful)
+	})
+
+	t.Run("the pending link expires", func(t *testing.T) {
+		l.now = func() time.Time { return time.Now().Add(-time.Hour) }
+		defer func() { l.now = time.Now }()
+
+		res := l.addressHandler(context.Background(), cmd, appID, "carol", addr)
+		require.True(t, res.Successful, res.Message)
+
+		pending, err := db.GetPendingAddressLink(int(appID), "carol")
+		require.NoError(t, err)
+		assert.Nil(t, pending)
+	})
+
+	res = l.removeHandler(context.Background(), cmd, appID, "bob", addr)
+	assert.True(t, res.Successful)
+
+	res = l.removeHandler(context.Background(), cmd, appID, "bob", addr)
+	assert.False(t, res.Successful)
+}
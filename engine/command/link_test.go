@@ -0,0 +1,36 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkify(t *testing.T) {
+	t.Cleanup(func() {
+		SetExplorer("")
+	})
+
+	addr := "pc1zgp0x33hehvczq6dtfjyh9ca8nd0cyw8m8yppaa"
+	txID := strings.Repeat("ab", 32)
+	msg := "Sent <1 PAC> to " + addr + "\nTx: " + txID + "\nLast Block Height: 1,234\n" +
+		"Link: https://pacviewer.com/transaction/" + txID
+
+	assert.Equal(t, msg, Linkify(AppIdCLI, msg), "no markup on the CLI")
+
+	assert.Equal(t, "Sent <1 PAC> to ["+addr+"](https://pacviewer.com/address/"+addr+")\n"+
+		"Tx: ["+txID+"](https://pacviewer.com/transaction/"+txID+")\n"+
+		"Last Block Height: [1,234](https://pacviewer.com/block/1234)\n"+
+		"Link: https://pacviewer.com/transaction/"+txID, Linkify(AppIdDiscord, msg))
+
+	SetExplorer("https://explorer.example/")
+	assert.Equal(t, "https://explorer.example", ExplorerURL())
+
+	linked := Linkify(AppIdTelegram, "Sent <1 PAC> to "+addr+", at block 12.")
+	assert.Equal(t, "Sent &lt;1 PAC&gt; to <a href=\"https://explorer.example/address/"+addr+"\">"+addr+"</a>, "+
+		"at block <a href=\"https://explorer.example/block/12\">12</a>.", linked)
+
+	code := "Copy `" + addr + "`:\n```\nreward_addresses = [\"" + addr + "\"]\n```"
+	assert.Equal(t, code, Linkify(AppIdDiscord, code), "the code is kept as it is")
+}
@@ -0,0 +1,297 @@
+package market
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/market"
+)
+
+const (
+	CommandName            = "market"
+	PriceCommandName       = "price"
+	HistoryCommandName     = "history"
+	AlertCommandName       = "alert"
+	AlertsCommandName      = "alerts"
+	RemoveAlertCommandName = "remove-alert"
+	HelpCommandName        = "help"
+)
+
+const (
+	defaultHistoryHours = 24
+	maxHistoryHours     = 30 * 24
+)
+
+type Market struct {
+	tracker   *market.Tracker
+	db        *database.DB
+	maxAlerts int64
+}
+
+func NewMarket(tracker *market.Tracker, db *database.DB, maxAlerts int64) Market {
+	return Market{
+		tracker:   tracker,
+		db:        db,
+		maxAlerts: maxAlerts,
+	}
+}
+
+// History is the summary of the price samples in a time window.
+type History struct {
+	Hours   int
+	Samples int
+	Open    float64
+	Close   float64
+	High    float64
+	Low     float64
+	Change  float64 // Change from the open to the close in percent.
+}
+
+func (m *Market) GetCommand() command.Command {
+	// users are notified by direct messages, so the alerts are only on the chat platforms.
+	alertAppIDs := []command.AppID{command.AppIdDiscord, command.AppIdTelegram}
+
+	subCmdPrice := command.Command{
+		Name:        PriceCommandName,
+		Desc:        "Price of PAC in USDT",
+		Help:        "Shows the last price and its change in the last 24 hours",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     m.priceHandler,
+	}
+
+	subCmdHistory := command.Command{
+		Name: HistoryCommandName,
+		Desc: "Price history of PAC",
+		Help: "Shows the open, close, high and low price of the hourly samples in the last hours, 24 by default",
+		Args: []command.Args{
+			{
+				Name:     "hours",
+				Desc:     "Number of the last hours, up to 720",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"168"},
+		Handler:     m.historyHandler,
+	}
+
+	subCmdAlert := command.Command{
+		Name: AlertCommandName,
+		Desc: "Get a message when the price crosses a target",
+		Help: "The alert is sent once as a direct message, when the price goes above or below the target in USDT",
+		Args: []command.Args{
+			{
+				Name:     "direction",
+				Desc:     "above or below",
+				Optional: false,
+			},
+			{
+				Name:     "price",
+				Desc:     "Target price in USDT",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      alertAppIDs,
+		Examples:    []string{"above 0.5", "below 0.1"},
+		Mutating:    true,
+		Handler:     m.alertHandler,
+	}
+
+	subCmdAlerts := command.Command{
+		Name:        AlertsCommandName,
+		Desc:        "Your active price alerts",
+		Help:        "",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      alertAppIDs,
+		Ephemeral:   true,
+		Handler:     m.alertsHandler,
+	}
+
+	subCmdRemoveAlert := command.Command{
+		Name: RemoveAlertCommandName,
+		Desc: "Remove a price alert",
+		Help: "Provide the alert ID from the alerts command",
+		Args: []command.Args{
+			{
+				Name:     "id",
+				Desc:     "Alert ID",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      alertAppIDs,
+		Examples:    []string{"12"},
+		Mutating:    true,
+		Handler:     m.removeAlertHandler,
+	}
+
+	cmdMarket := command.Command{
+		Emoji:       "📈",
+		Name:        CommandName,
+		Desc:        "Market price of PAC",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdMarket.AddSubCommand(subCmdPrice)
+	cmdMarket.AddSubCommand(subCmdHistory)
+	cmdMarket.AddSubCommand(subCmdAlert)
+	cmdMarket.AddSubCommand(subCmdAlerts)
+	cmdMarket.AddSubCommand(subCmdRemoveAlert)
+
+	return cmdMarket
+}
+
+func (m *Market) priceHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	price, err := m.tracker.Price(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	samples, err := m.db.GetPriceSamples(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	// the change is from the oldest sample of the last 24 hours.
+	hasChange := len(samples) > 0 && samples[0].Price > 0
+	change := 0.0
+	if hasChange {
+		change = (price - samples[0].Price) * 100 / samples[0].Price
+	}
+
+	return cmd.RenderResult(appID, "market_price", map[string]any{
+		"Price":     price,
+		"HasChange": hasChange,
+		"Change":    change,
+	})
+}
+
+func (m *Market) historyHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	hours := defaultHistoryHours
+	if len(args) > 0 {
+		var err error
+		hours, err = strconv.Atoi(args[0])
+		if err != nil || hours <= 0 || hours > maxHistoryHours {
+			return cmd.FailedResult("hours should be a number from 1 to %d", maxHistoryHours)
+		}
+	}
+
+	samples, err := m.db.GetPriceSamples(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if len(samples) == 0 {
+		return cmd.FailedResult("There is no price sample in the last %d hours yet", hours)
+	}
+
+	return cmd.RenderResult(appID, "market_history", summarize(hours, samples))
+}
+
+func (m *Market) alertHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	direction := database.PriceAlertDirection(args[0])
+	if direction != database.PriceAlertAbove && direction != database.PriceAlertBelow {
+		return cmd.FailedResult("direction should be %s or %s", database.PriceAlertAbove, database.PriceAlertBelow)
+	}
+
+	price, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || price <= 0 {
+		return cmd.FailedResult("%s is not a price", args[1])
+	}
+
+	alerts, err := m.db.GetUserPriceAlerts(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if int64(len(alerts)) >= m.maxAlerts {
+		return cmd.FailedResult("You have %d alerts, please remove one with `market remove-alert` first", len(alerts))
+	}
+
+	alert := &database.PriceAlert{
+		AppID:     int(appID),
+		UserID:    callerID,
+		Direction: direction,
+		Price:     price,
+	}
+	if err := m.db.AddPriceAlert(alert); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("Alert %d is set, you will get a message when the price is %s %.4f USDT",
+		alert.ID, direction, price)
+}
+
+func (m *Market) alertsHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	_ ...string,
+) command.CommandResult {
+	alerts, err := m.db.GetUserPriceAlerts(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "market_alerts", map[string]any{
+		"Alerts": alerts,
+	})
+}
+
+func (m *Market) removeAlertHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	id, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return cmd.FailedResult("%s is not an alert ID", args[0])
+	}
+
+	removed, err := m.db.DeletePriceAlert(uint(id), int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !removed {
+		return cmd.FailedResult("You have no active alert with ID %d", id)
+	}
+
+	return cmd.SuccessfulResult("Alert %d is removed", id)
+}
+
+// summarize returns the history of the samples, they are sorted by time.
+func summarize(hours int, samples []*database.PriceSample) History {
+	prices := make([]float64, 0, len(samples))
+	for _, s := range samples {
+		prices = append(prices, s.Price)
+	}
+
+	h := History{
+		Hours:   hours,
+		Samples: len(prices),
+		Open:    prices[0],
+		Close:   prices[len(prices)-1],
+		High:    slices.Max(prices),
+		Low:     slices.Min(prices),
+	}
+	if h.Open > 0 {
+		h.Change = (h.Close - h.Open) * 100 / h.Open
+	}
+
+	return h
+}
@@ -0,0 +1,96 @@
+package market
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/market"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedProvider float64
+
+func (p fixedProvider) Price(_ context.Context) (float64, error) {
+	return float64(p), nil
+}
+
+func setup(t *testing.T) (*Market, command.Command) {
+	t.Helper()
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	tracker := market.NewTracker(fixedProvider(0.12), db, notify.NewHub(), time.Minute)
+	m := NewMarket(tracker, db, 2)
+
+	return &m, m.GetCommand()
+}
+
+func TestAlerts(t *testing.T) {
+	m, cmd := setup(t)
+	appID := command.AppIdDiscord
+
+	res := m.alertHandler(context.Background(), cmd, appID, "alice", "sideways", "0.5")
+	assert.False(t, res.Successful)
+
+	res = m.alertHandler(context.Background(), cmd, appID, "alice", "above", "-1")
+	assert.False(t, res.Successful)
+
+	res = m.alertHandler(context.Background(), cmd, appID, "alice", "above", "0.5")
+	assert.True(t, res.Successful)
+	res = m.alertHandler(context.Background(), cmd, appID, "alice", "below", "0.1")
+	assert.True(t, res.Successful)
+
+	res = m.alertHandler(context.Background(), cmd, appID, "alice", "below", "0.05")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "You have 2 alerts")
+
+	res = m.alertsHandler(context.Background(), cmd, appID, "alice")
+	assert.Equal(t, "#1 above 0.5000 USDT\n#2 below 0.1000 USDT", res.Message)
+
+	res = m.removeAlertHandler(context.Background(), cmd, appID, "bob", "1")
+	assert.False(t, res.Successful, "alert of another user")
+
+	res = m.removeAlertHandler(context.Background(), cmd, appID, "alice", "1")
+	assert.True(t, res.Successful)
+
+	res = m.alertsHandler(context.Background(), cmd, appID, "bob")
+	assert.Equal(t, "You have no active price alert.", res.Message)
+}
+
+func TestSummarize(t *testing.T) {
+	h := summarize(24, []*database.PriceSample{
+		{Price: 0.10},
+		{Price: 0.15},
+		{Price: 0.08},
+		{Price: 0.12},
+	})
+
+	assert.Equal(t, 4, h.Samples)
+	assert.Equal(t, 0.10, h.Open)
+	assert.Equal(t, 0.12, h.Close)
+	assert.Equal(t, 0.15, h.High)
+	assert.Equal(t, 0.08, h.Low)
+	assert.InDelta(t, 20.0, h.Change, 0.0001)
+}
+
+func TestPrice(t *testing.T) {
+	m, cmd := setup(t)
+
+	res := m.priceHandler(context.Background(), cmd, command.AppIdCLI, "")
+	assert.Equal(t, "PAC price: 0.1200 USDT📈", res.Message)
+
+	hour := time.Now().UTC().Add(-time.Hour).Truncate(time.Hour)
+	require.NoError(t, m.db.AddPriceSample(&database.PriceSample{Hour: hour, Price: 0.1}))
+
+	res = m.priceHandler(context.Background(), cmd, command.AppIdCLI, "")
+	assert.Equal(t, "PAC price: 0.1200 USDT📈\n24h change: +20.00%", res.Message)
+}
@@ -1,42 +1,136 @@
 package network
 
 import (
+	"cmp"
 	"context"
-	"fmt"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pactus-project/pactus/types/amount"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/cache"
 	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/concentration"
+	"github.com/pagu-project/Pagu/database"
 	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/fork"
+	"github.com/pagu-project/Pagu/indexer"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/treasury"
 	"github.com/pagu-project/Pagu/utils"
 )
 
 const (
-	CommandName         = "network"
-	NodeInfoCommandName = "node-info"
-	StatusCommandName   = "status"
-	HealthCommandName   = "health"
-	HelpCommandName     = "help"
+	CommandName           = "network"
+	NodeInfoCommandName   = "node-info"
+	ValidatorCommandName  = "validator"
+	ValidatorsCommandName = "validators"
+	AtRiskCommandName     = "at-risk"
+	StatusCommandName     = "status"
+	HealthCommandName     = "health"
+	SupplyCommandName     = "supply"
+	GrowthCommandName     = "growth"
+	DecentralCommandName  = "decentralization"
+	FindCommandName       = "find"
+	PeersCommandName      = "peers"
+	StakeDistCommandName  = "stake-distribution"
+	TreasuryCommandName   = "treasury"
+	HelpCommandName       = "help"
 )
 
+// growthDays are the days ago that the network growth is compared with.
+var growthDays = []int{7, 30}
+
+// trendDays is the days ago that the decentralization is compared with.
+const trendDays = 30
+
+// maxFindResults is the number of the found validators shown, the country of each one is looked up.
+const maxFindResults = 10
+
+// minFindLength prevents listing all the peers with a short text.
+const minFindLength = 2
+
+// treasuryMovements is the number of the last outgoing transactions of the treasury that are shown.
+const treasuryMovements = 10
+
+// maxAtRiskValidators is the number of validators shown in the at-risk list, to fit in a message.
+const maxAtRiskValidators = 20
+
+// timelineHours is the number of the last hours in the health timeline.
+const timelineHours = 24
+
+// DiffOption shows the changes of the network status since the last check of the user.
+const DiffOption = "--diff"
+
+// statusCheckName is the name of the last status check of a user in the database.
+const statusCheckName = "network status"
+
 type Network struct {
-	ctx       context.Context
 	clientMgr *client.Mgr
+	indexer   *indexer.Indexer
+	forks     *fork.Checker
+	power     *concentration.Monitor
+	atRisk    ScoreRange
+	db        *database.DB // The last status checks of the users, nil disables the diff.
+
+	dialPeer  func(ctx context.Context, address string) (time.Duration, error)
+	lookupGeo func(ctx context.Context, ip string) (*utils.GeoIP, error)
+	countries cache.Store // The countries of the peers by their IPs.
+}
+
+// ScoreRange is the bounds of the PIP-19 availability score, the minimum is inclusive and the maximum is exclusive.
+type ScoreRange struct {
+	Min float64
+	Max float64
 }
 
-func NewNetwork(ctx context.Context,
-	clientMgr *client.Mgr,
+func (r ScoreRange) Contains(score float64) bool {
+	return score >= r.Min && score < r.Max
+}
+
+func NewNetwork(
+	clientMgr *client.Mgr, idx *indexer.Indexer, forks *fork.Checker, power *concentration.Monitor, atRisk ScoreRange,
+	db *database.DB,
 ) Network {
 	return Network{
-		ctx:       ctx,
 		clientMgr: clientMgr,
+		indexer:   idx,
+		forks:     forks,
+		power:     power,
+		atRisk:    atRisk,
+		db:        db,
+		dialPeer:  dialLatency,
+		lookupGeo: utils.GetGeoIPContext,
+		countries: cache.NewMemoryStore(),
 	}
 }
 
+// Growth is a network metric now and its changes since the days ago.
+type Growth struct {
+	Name    string
+	Amount  bool // The values are amounts in NanoPAC.
+	Current int64
+	Changes []Change
+}
+
+type Change struct {
+	Days    int
+	Known   bool // The change is unknown if there is no snapshot of the days ago yet.
+	Delta   int64
+	Percent float64
+}
+
 type NodeInfo struct {
 	PeerID              string
+	ValidatorAddress    string
 	IPAddress           string
+	Host                string // The IP or the host name of the multiaddr, empty if it's malformed.
+	Relayed             bool   // The peer is reached through the relay of the host.
 	Agent               string
 	Moniker             string
 	Country             string
@@ -46,7 +140,7 @@ type NodeInfo struct {
 	ISP                 string
 	ValidatorNum        int32
 	AvailabilityScore   float64
-	StakeAmount         int64
+	StakeAmount         amount.Amount
 	LastBondingHeight   uint32
 	LastSortitionHeight uint32
 }
@@ -55,13 +149,14 @@ type NetStatus struct {
 	NetworkName         string
 	ConnectedPeersCount uint32
 	ValidatorsCount     int32
-	TotalBytesSent      uint32
-	TotalBytesReceived  uint32
+	TotalBytesSent      uint64
+	TotalBytesReceived  uint64
+	TrafficNodes        int // The number of the nodes of Pagu in the traffic, the traffic is their total.
 	CurrentBlockHeight  uint32
-	TotalNetworkPower   int64
-	TotalCommitteePower int64
+	TotalNetworkPower   amount.Amount
+	TotalCommitteePower amount.Amount
 	TotalAccounts       int32
-	CirculatingSupply   int64
+	CirculatingSupply   amount.Amount
 }
 
 func (n *Network) GetCommand() command.Command {
@@ -72,15 +167,34 @@ func (n *Network) GetCommand() command.Command {
 		Args: []command.Args{
 			{
 				Name:     "validator_address",
-				Desc:     "Your validator address",
+				Desc:     "Your validator address or number",
 				Optional: false,
+				Kind:     command.ArgAddress,
 			},
 		},
 		SubCommands: nil,
 		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p...", "42"},
 		Handler:     n.nodeInfoHandler,
 	}
 
+	subCmdValidator := command.Command{
+		Name: ValidatorCommandName,
+		Desc: "View the information of a validator by its number",
+		Help: "Provide the validator number to get the validator and node info, the same as node-info",
+		Args: []command.Args{
+			{
+				Name:     "number",
+				Desc:     "Validator number",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"42"},
+		Handler:     n.validatorHandler,
+	}
+
 	subCmdHealth := command.Command{
 		Name:        HealthCommandName,
 		Desc:        "Checking network health status",
@@ -92,15 +206,158 @@ func (n *Network) GetCommand() command.Command {
 	}
 
 	subCmdStatus := command.Command{
-		Name:        StatusCommandName,
-		Desc:        "Network statistics",
-		Help:        "",
-		Args:        []command.Args{},
+		Name: StatusCommandName,
+		Desc: "Network statistics",
+		Help: "With --diff, shows what changed since your last check with --diff, like: Block height +1,234",
+		Args: []command.Args{
+			{
+				Name:     "options",
+				Desc:     "--diff to compare with your last check",
+				Optional: true,
+			},
+		},
 		SubCommands: nil,
 		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{DiffOption},
+		PerBlock:    true,
+		CallerArgs:  []string{DiffOption},
 		Handler:     n.networkStatusHandler,
 	}
 
+	subCmdSupply := command.Command{
+		Name:        SupplyCommandName,
+		Desc:        "Supply breakdown of the network",
+		Help:        "Shows the circulating, staked and treasury coins and their share of the total supply",
+		Args:        []command.Args{},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		PerBlock:    true,
+		Handler:     n.networkSupplyHandler,
+	}
+
+	subCmdTreasury := command.Command{
+		Name: TreasuryCommandName,
+		Desc: "Spending of the treasury",
+		Help: "Shows the balance of the reserve accounts and the warm wallets, and their last outgoing transactions " +
+			"since the bot started indexing them",
+		Args:        []command.Args{},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Expensive:   true,
+		Handler:     n.treasuryHandler,
+	}
+
+	subCmdGrowth := command.Command{
+		Name:        GrowthCommandName,
+		Desc:        "Network growth in the last 7 and 30 days",
+		Help:        "Compares the validators, accounts, network power and connected nodes with the daily snapshots of 7 and 30 days ago",
+		Args:        []command.Args{},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Expensive:   true,
+		Handler:     n.growthHandler,
+	}
+
+	subCmdDecentral := command.Command{
+		Name: DecentralCommandName,
+		Desc: "Decentralization of the stake and its trend in the last 30 days",
+		Help: "Shows the Gini coefficient of the stakes of the active validators, lower is more equal, " +
+			"and the Nakamoto coefficient, the fewest validators holding over a third of the stake, higher is better",
+		Args:        []command.Args{},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Expensive:   true,
+		Handler:     n.decentralizationHandler,
+	}
+
+	subCmdStakeDist := command.Command{
+		Name: StakeDistCommandName,
+		Desc: "Validators by the size of their stake",
+		Help: "Buckets the active validators by their stake, like under 10 PAC or 100 to 500 PAC, " +
+			"with the number of validators and the total stake of each bucket and a chart of them",
+		Args:        []command.Args{},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     n.stakeDistributionHandler,
+	}
+
+	subCmdFind := command.Command{
+		Name: FindCommandName,
+		Desc: "Find validators by the moniker of their node",
+		Help: "Searches the monikers of the connected peers, case-insensitive. " +
+			"Use node-info with the found address to see the details",
+		Args: []command.Args{
+			{
+				Name:     "moniker",
+				Desc:     "Whole or part of the moniker",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pagu"},
+		Expensive:   true,
+		Handler:     n.findHandler,
+	}
+
+	subCmdPeers := command.Command{
+		Name: PeersCommandName,
+		Desc: "Connected peers of the node, sorted and filtered",
+		Help: "Lists the connected peers with their agent, height, country and latency, 10 in a page. " +
+			"Sort by --sort=moniker, height, latency or country, and filter by --filter=agent:v1.2, " +
+			"moniker:pagu or country:germany. The sort by the latency or the country scans all the peers",
+		Args: []command.Args{
+			{
+				Name:     "options",
+				Desc:     "Like: --sort=height --filter=agent:v1.2 --page=2",
+				Optional: true,
+				Variadic: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"--sort=height --filter=agent:v1.2", "--sort=latency --page=2"},
+		Expensive:   true,
+		Timeout:     time.Minute, // The sort by the latency dials all the peers.
+		Handler:     n.peersHandler,
+	}
+
+	subCmdAtRisk := command.Command{
+		Name: AtRiskCommandName,
+		Desc: "Validators with a low PIP-19 score, the lowest first",
+		Help: "Lists the validators whose availability score is in the at-risk range, to ping the operators " +
+			"before they get penalized. The range can be set by the arguments",
+		Args: []command.Args{
+			{
+				Name:     "min",
+				Desc:     "Minimum score, inclusive",
+				Optional: true,
+			},
+			{
+				Name:     "max",
+				Desc:     "Maximum score, exclusive",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"0.7 0.85"},
+		Expensive:   true,
+		Handler:     n.atRiskHandler,
+	}
+
+	subCmdValidators := command.Command{
+		Name:        ValidatorsCommandName,
+		Desc:        "Validators of the network",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	subCmdValidators.AddSubCommand(subCmdAtRisk)
+
 	cmdNetwork := command.Command{
 		Name:        CommandName,
 		Desc:        "Network related commands",
@@ -114,80 +371,476 @@ func (n *Network) GetCommand() command.Command {
 	cmdNetwork.AddSubCommand(subCmdHealth)
 	cmdNetwork.AddSubCommand(subCmdNodeInfo)
 	cmdNetwork.AddSubCommand(subCmdStatus)
+	cmdNetwork.AddSubCommand(subCmdSupply)
+	cmdNetwork.AddSubCommand(subCmdTreasury)
+	cmdNetwork.AddSubCommand(subCmdGrowth)
+	cmdNetwork.AddSubCommand(subCmdDecentral)
+	cmdNetwork.AddSubCommand(subCmdStakeDist)
+	cmdNetwork.AddSubCommand(subCmdValidator)
+	cmdNetwork.AddSubCommand(subCmdFind)
+	cmdNetwork.AddSubCommand(subCmdPeers)
+	cmdNetwork.AddSubCommand(subCmdValidators)
 
 	return cmdNetwork
 }
 
-func (n *Network) networkHealthHandler(cmd command.Command, _ command.AppID, _ string, _ ...string) command.CommandResult {
-	lastBlockTime, lastBlockHeight := n.clientMgr.GetLastBlockTime()
+func (n *Network) networkHealthHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	lastBlockTime, lastBlockHeight := n.clientMgr.GetLastBlockTime(ctx)
 	lastBlockTimeFormatted := time.Unix(int64(lastBlockTime), 0).Format("02/01/2006, 15:04:05")
 	currentTime := time.Now()
 
 	timeDiff := (currentTime.Unix() - int64(lastBlockTime))
+	forkStatus := n.forks.Status()
+	// the concentration is a warning, the network still works.
+	powerStatus := n.power.Status()
+
+	data := map[string]any{
+		"Healthy":         timeDiff <= indexer.HealthyLag && !forkStatus.Diverged,
+		"CurrentTime":     currentTime.Format("02/01/2006, 15:04:05"),
+		"LastBlockTime":   lastBlockTimeFormatted,
+		"TimeDiff":        timeDiff,
+		"LastBlockHeight": lastBlockHeight,
+		"Fork":            forkStatus,
+		"Concentration":   powerStatus,
+	}
 
-	healthStatus := true
-	if timeDiff > 15 {
-		healthStatus = false
+	// the timeline is optional, the current health is shown without it.
+	if n.indexer != nil {
+		timeline, err := n.indexer.HealthTimeline(timelineHours)
+		if err != nil {
+			log.Ctx(ctx).Warn("can't get the health timeline", "err", err)
+		} else {
+			maps.Copy(data, timelineData(timeline))
+		}
 	}
 
-	var status string
-	if healthStatus {
-		status = "Healthy✅"
-	} else {
-		status = "UnHealthy❌"
+	return cmd.RenderResult(appID, "network_health", data)
+}
+
+// timelineData returns the hours of the timeline and a summary of them: the degraded and the down hours,
+// and the last hour that had an issue, to tell a hiccup from an ongoing issue.
+func timelineData(timeline []indexer.HealthHour) map[string]any {
+	degraded, down := 0, 0
+	lastIssue := ""
+	for _, hour := range timeline {
+		switch hour.State {
+		case indexer.HealthDegraded:
+			degraded++
+		case indexer.HealthDown:
+			down++
+		default:
+			continue
+		}
+		lastIssue = hour.Hour.Format("15:04")
 	}
 
-	return cmd.SuccessfulResult("Network is %s\nCurrentTime: %v\nLastBlockTime: %v\nTime Diff: %v\nLast Block Height: %v",
-		status, currentTime.Format("02/01/2006, 15:04:05"), lastBlockTimeFormatted, timeDiff, utils.FormatNumber(int64(lastBlockHeight)))
+	return map[string]any{
+		"Timeline":  timeline,
+		"From":      timeline[0].Hour.Format("15:04"),
+		"Degraded":  degraded,
+		"Down":      down,
+		"LastIssue": lastIssue,
+	}
 }
 
-func (be *Network) networkStatusHandler(cmd command.Command, _ command.AppID, _ string, _ ...string) command.CommandResult {
-	netInfo, err := be.clientMgr.GetNetworkInfo()
+func (be *Network) networkStatusHandler(ctx context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	diff := len(args) > 0
+	if diff && args[0] != DiffOption {
+		return cmd.FailedResult("%s is not an option, try: %s", args[0], DiffOption).
+			WithCode(command.ErrCodeInvalidArgs)
+	}
+
+	if diff && be.db == nil {
+		return cmd.FailedResult("The diff of the status is not available now, please try again later!")
+	}
+
+	netInfo, err := be.clientMgr.GetNetworkInfo(ctx)
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	chainInfo, err := be.clientMgr.GetBlockchainInfo()
+	chainInfo, err := be.clientMgr.GetBlockchainInfo(ctx)
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	cs, err := be.clientMgr.GetCirculatingSupply()
+	cs, err := be.clientMgr.GetCirculatingSupply(ctx)
 	if err != nil {
 		cs = 0
 	}
 
-	// Convert NanoPAC to PAC using the Amount type.
-	totalNetworkPower := amount.Amount(chainInfo.TotalPower).ToPAC()
-	totalCommitteePower := amount.Amount(chainInfo.CommitteePower).ToPAC()
-	circulatingSupply := amount.Amount(cs).ToPAC()
+	sent, received, trafficNodes := be.clientMgr.GetNetworkBytes(ctx)
 
 	net := NetStatus{
+		ConnectedPeersCount: netInfo.ConnectedPeersCount,
 		ValidatorsCount:     chainInfo.TotalValidators,
+		TotalBytesSent:      sent,
+		TotalBytesReceived:  received,
+		TrafficNodes:        trafficNodes,
 		CurrentBlockHeight:  chainInfo.LastBlockHeight,
-		TotalNetworkPower:   int64(totalNetworkPower),
-		TotalCommitteePower: int64(totalCommitteePower),
+		TotalNetworkPower:   amount.Amount(chainInfo.TotalPower),
+		TotalCommitteePower: amount.Amount(chainInfo.CommitteePower),
 		NetworkName:         netInfo.NetworkName,
 		TotalAccounts:       chainInfo.TotalAccounts,
-		CirculatingSupply:   int64(circulatingSupply),
+		CirculatingSupply:   amount.Amount(cs),
+	}
+
+	res := cmd.RenderResult(appID, "network_status", net)
+	if !diff || !res.Successful {
+		return res
+	}
+
+	return be.withStatusDiff(ctx, cmd, res, appID, callerID, net)
+}
+
+// withStatusDiff appends the changes of the status since the last check of the caller, then keeps the status
+// as the last check. Failing to keep it is not fatal, the next check compares with the older one.
+func (be *Network) withStatusDiff(ctx context.Context, cmd command.Command, res command.CommandResult,
+	appID command.AppID, callerID string, net NetStatus,
+) command.CommandResult {
+	last, err := be.db.GetStatusCheck(int(appID), callerID, statusCheckName)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	data := map[string]any{
+		"First": last == nil,
+	}
+
+	if last != nil {
+		var old NetStatus
+		if err := json.Unmarshal([]byte(last.Result), &old); err != nil {
+			data["First"] = true
+		} else {
+			data["Since"] = time.Since(last.UpdatedAt).Round(time.Second)
+			data["Changes"] = StatusChanges(old, net)
+		}
+	}
+
+	if result, err := json.Marshal(net); err == nil {
+		err = be.db.SetStatusCheck(int(appID), callerID, statusCheckName, string(result))
+		if err != nil {
+			log.Ctx(ctx).Warn("can't keep the status check", "err", err)
+		}
+	}
+
+	changes, err := command.RenderTemplate(appID, "network_status_diff", data)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+	res.Message += "\n\n" + changes
+
+	return res
+}
+
+// StatusChange is the change of a metric of the network status since the last check.
+type StatusChange struct {
+	Name   string
+	Amount bool // The delta is an amount in NanoPAC.
+	Delta  int64
+}
+
+// StatusChanges returns the metrics that changed between the statuses, in the order of the status.
+// The traffic is not compared, the counters of a node are reset when it restarts.
+func StatusChanges(old, current NetStatus) []StatusChange {
+	metrics := []StatusChange{
+		{Name: "Block height", Delta: int64(current.CurrentBlockHeight) - int64(old.CurrentBlockHeight)},
+		{Name: "Peers", Delta: int64(current.ConnectedPeersCount) - int64(old.ConnectedPeersCount)},
+		{Name: "Validators", Delta: int64(current.ValidatorsCount) - int64(old.ValidatorsCount)},
+		{Name: "Accounts", Delta: int64(current.TotalAccounts) - int64(old.TotalAccounts)},
+		{Name: "Total power", Amount: true, Delta: int64(current.TotalNetworkPower - old.TotalNetworkPower)},
+		{Name: "Committee power", Amount: true, Delta: int64(current.TotalCommitteePower - old.TotalCommitteePower)},
+		{Name: "Circulating supply", Amount: true, Delta: int64(current.CirculatingSupply - old.CirculatingSupply)},
+	}
+
+	changes := make([]StatusChange, 0, len(metrics))
+	for _, m := range metrics {
+		if m.Delta != 0 {
+			changes = append(changes, m)
+		}
+	}
+
+	return changes
+}
+
+func (n *Network) networkSupplyHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	supply, err := n.clientMgr.GetSupply(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "network_supply", map[string]any{
+		"Total":              amount.Amount(supply.Total()),
+		"Minted":             amount.Amount(supply.Minted),
+		"Circulating":        amount.Amount(supply.Circulating),
+		"CirculatingPercent": supply.Percent(supply.Circulating),
+		"Staked":             amount.Amount(supply.Staked),
+		"StakedPercent":      supply.Percent(supply.Staked),
+		"Treasury":           amount.Amount(supply.Treasury),
+		"TreasuryPercent":    supply.Percent(supply.Treasury),
+		"Burned":             amount.Amount(supply.Burned),
+		"BurnedPercent":      supply.Percent(supply.Burned),
+	})
+}
+
+func (n *Network) treasuryHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	supply, err := n.clientMgr.GetSupply(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	addresses := client.TreasuryAddresses()
+	txs, err := n.indexer.Outgoing(addresses, treasuryMovements)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	movements := make([]treasury.Movement, 0, len(txs))
+	for _, tx := range txs {
+		movements = append(movements, treasury.NewMovement(tx, addresses))
+	}
+
+	return cmd.RenderResult(appID, "network_treasury", map[string]any{
+		"Balance":   amount.Amount(supply.Treasury),
+		"Accounts":  len(addresses),
+		"Movements": movements,
+	})
+}
+
+func (n *Network) growthHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	current, err := n.indexer.Current(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	past := make([]*database.NetworkSnapshot, 0, len(growthDays))
+	for _, days := range growthDays {
+		s, err := n.indexer.DaysAgo(days)
+		if err != nil {
+			return cmd.ErrorResult(err)
+		}
+		past = append(past, s)
+	}
+
+	metrics := []struct {
+		name   string
+		amount bool
+		value  func(s *database.NetworkSnapshot) int64
+	}{
+		{"Validators", false, func(s *database.NetworkSnapshot) int64 { return int64(s.Validators) }},
+		{"Accounts", false, func(s *database.NetworkSnapshot) int64 { return int64(s.Accounts) }},
+		{"Network Power", true, func(s *database.NetworkSnapshot) int64 { return s.TotalPower }},
+		{"Connected Nodes", false, func(s *database.NetworkSnapshot) int64 { return int64(s.Nodes) }},
+	}
+
+	growths := make([]Growth, 0, len(metrics))
+	for _, m := range metrics {
+		g := growth(m.name, current, past, m.value)
+		g.Amount = m.amount
+		growths = append(growths, g)
+	}
+
+	return cmd.RenderResult(appID, "network_growth", map[string]any{
+		"Growths": growths,
+	})
+}
+
+func (n *Network) decentralizationHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	current, err := n.indexer.Current(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if current.Nakamoto == 0 {
+		return cmd.FailedResult("The validators are not loaded yet, please try again later!")
+	}
+
+	past, err := n.indexer.DaysAgo(trendDays)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	// the snapshots before the coefficients were added have zero Nakamoto coefficient.
+	known := past != nil && past.Nakamoto != 0
+
+	data := map[string]any{
+		"Validators": current.Validators,
+		"Gini":       current.Gini,
+		"Nakamoto":   current.Nakamoto,
+		"Known":      known,
+		"Days":       trendDays,
+	}
+
+	if known {
+		data["GiniDelta"] = current.Gini - past.Gini
+		data["NakamotoDelta"] = current.Nakamoto - past.Nakamoto
 	}
 
-	return cmd.SuccessfulResult("Network Name: %s\nConnected Peers: %v\n"+
-		"Validators Count: %v\nAccounts Count: %v\nCurrent Block Height: %v\nTotal Power: %v PAC\nTotal Committee Power: %v PAC\nCirculating Supply: %v PAC\n"+
-		"\n> Note📝: This info is from one random network node. Non-blockchain data may not be consistent.",
-		net.NetworkName,
-		utils.FormatNumber(int64(net.ConnectedPeersCount)),
-		utils.FormatNumber(int64(net.ValidatorsCount)),
-		utils.FormatNumber(int64(net.TotalAccounts)),
-		utils.FormatNumber(int64(net.CurrentBlockHeight)),
-		utils.FormatNumber(net.TotalNetworkPower),
-		utils.FormatNumber(net.TotalCommitteePower),
-		utils.FormatNumber(net.CirculatingSupply),
-	)
+	return cmd.RenderResult(appID, "network_decentralization", data)
 }
 
-func (n *Network) nodeInfoHandler(cmd command.Command, _ command.AppID, _ string, args ...string) command.CommandResult {
-	valAddress := args[0]
+func (n *Network) stakeDistributionHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	buckets, validators, ok := n.indexer.StakeDistribution()
+	if !ok {
+		return cmd.FailedResult("The validators are not loaded yet, please try again later!")
+	}
+
+	res := cmd.RenderResult(appID, "network_stake_distribution", map[string]any{
+		"Validators": validators,
+		"Buckets":    buckets,
+	})
+
+	return withStakeChart(res, appID, buckets)
+}
+
+// withStakeChart attaches the chart of the buckets to the result if the platform can render images.
+// Failing to draw the chart is not fatal, the buckets are in the message itself.
+func withStakeChart(res command.CommandResult, appID command.AppID,
+	buckets []indexer.StakeBucket,
+) command.CommandResult {
+	if !res.Successful || !appID.Supports(command.CapabilityImage) {
+		return res
+	}
+
+	labels := make([]string, 0, len(buckets))
+	counts := make([]int64, 0, len(buckets))
+	for _, b := range buckets {
+		labels = append(labels, b.Label)
+		counts = append(counts, int64(b.Validators))
+	}
+
+	png, err := utils.BarChartPNG(labels, counts)
+	if err != nil {
+		return res
+	}
+
+	return res.WithAttachment(command.Attachment{
+		Name:        "stake-distribution.png",
+		ContentType: "image/png",
+		Data:        png,
+		AltText:     utils.BarChartAltText("the validators by their stake in PAC", labels, counts),
+	})
+}
+
+// growth returns the changes of the metric since the past snapshots of the growth days.
+func growth(name string, current *database.NetworkSnapshot, past []*database.NetworkSnapshot,
+	value func(s *database.NetworkSnapshot) int64,
+) Growth {
+	g := Growth{
+		Name:    name,
+		Current: value(current),
+		Changes: make([]Change, 0, len(past)),
+	}
+
+	for i, s := range past {
+		change := Change{
+			Days: growthDays[i],
+		}
+
+		if s != nil {
+			old := value(s)
+			change.Known = true
+			change.Delta = g.Current - old
+			if old != 0 {
+				change.Percent = float64(change.Delta) * 100 / float64(old)
+			}
+		}
+
+		g.Changes = append(g.Changes, change)
+	}
+
+	return g
+}
+
+func (n *Network) atRiskHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	bounds := n.atRisk
+	for i, arg := range args {
+		score, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return cmd.FailedResult("%s is not a score", arg)
+		}
+
+		if i == 0 {
+			bounds.Min = score
+		} else {
+			bounds.Max = score
+		}
+	}
+
+	validators, updatedAt := n.clientMgr.GetValidators()
+	if updatedAt.IsZero() {
+		return cmd.FailedResult("The validators are not loaded yet, please try again later!")
+	}
+
+	atRisk := atRiskValidators(validators, bounds)
+	more := 0
+	if len(atRisk) > maxAtRiskValidators {
+		more = len(atRisk) - maxAtRiskValidators
+		atRisk = atRisk[:maxAtRiskValidators]
+	}
+
+	return cmd.RenderResult(appID, "network_validators_at_risk", map[string]any{
+		"Min":        bounds.Min,
+		"Max":        bounds.Max,
+		"Validators": atRisk,
+		"More":       more,
+		"UpdatedAt":  updatedAt.Format("02/01/2006, 15:04:05"),
+	})
+}
+
+// atRiskValidators returns the validators in the score range, the lowest score first.
+func atRiskValidators(validators []*pactus.ValidatorInfo, bounds ScoreRange) []*pactus.ValidatorInfo {
+	atRisk := make([]*pactus.ValidatorInfo, 0)
+	for _, val := range validators {
+		if bounds.Contains(val.AvailabilityScore) {
+			atRisk = append(atRisk, val)
+		}
+	}
+
+	slices.SortFunc(atRisk, func(a, b *pactus.ValidatorInfo) int {
+		if c := cmp.Compare(a.AvailabilityScore, b.AvailabilityScore); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Number, b.Number)
+	})
+
+	return atRisk
+}
+
+func (n *Network) validatorHandler(ctx context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	if _, err := strconv.ParseInt(args[0], 10, 32); err != nil {
+		return cmd.FailedResult("%s is not a validator number", args[0])
+	}
+
+	return n.nodeInfoHandler(ctx, cmd, appID, callerID, args...)
+}
+
+func (n *Network) nodeInfoHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	valAddress, err := n.validatorAddress(ctx, args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
 
 	peerInfo, err := n.clientMgr.GetPeerInfo(valAddress)
 	if err != nil {
@@ -199,31 +852,32 @@ func (n *Network) nodeInfoHandler(cmd command.Command, _ command.AppID, _ string
 		return cmd.ErrorResult(err)
 	}
 
-	ip := utils.ExtractIPFromMultiAddr(peerInfo.Address)
-	geoData := utils.GetGeoIP(ip)
+	geoData := utils.GetMultiAddrGeoIP(ctx, peerInfo.Address)
+	addr, _ := utils.ParseMultiAddr(peerInfo.Address)
 
 	nodeInfo := &NodeInfo{
-		PeerID:     peerID.String(),
-		IPAddress:  peerInfo.Address,
-		Agent:      peerInfo.Agent,
-		Moniker:    peerInfo.Moniker,
-		Country:    geoData.CountryName,
-		City:       geoData.City,
-		RegionName: geoData.RegionName,
-		TimeZone:   geoData.TimeZone,
-		ISP:        geoData.ISP,
+		PeerID:           peerID.String(),
+		ValidatorAddress: valAddress,
+		IPAddress:        peerInfo.Address,
+		Host:             addr.Host,
+		Relayed:          addr.Relayed,
+		Agent:            peerInfo.Agent,
+		Moniker:          peerInfo.Moniker,
+		Country:          geoData.CountryName,
+		City:             geoData.City,
+		RegionName:       geoData.RegionName,
+		TimeZone:         geoData.TimeZone,
+		ISP:              geoData.ISP,
 	}
 
 	// here we check if the node is also a validator.
 	// if its a validator , then we populate the validator data.
 	// if not validator then we set everything to 0/empty .
-	val, err := n.clientMgr.GetValidatorInfo(valAddress)
+	val, err := n.clientMgr.GetValidatorInfo(ctx, valAddress)
 	if err == nil && val != nil {
 		nodeInfo.ValidatorNum = val.Validator.Number
 		nodeInfo.AvailabilityScore = val.Validator.AvailabilityScore
-		// Convert NanoPAC to PAC using the Amount type and then to int64.
-		stakeAmount := amount.Amount(val.Validator.Stake).ToPAC()
-		nodeInfo.StakeAmount = int64(stakeAmount) // Convert float64 to int64.
+		nodeInfo.StakeAmount = amount.Amount(val.Validator.Stake)
 		nodeInfo.LastBondingHeight = val.Validator.LastBondingHeight
 		nodeInfo.LastSortitionHeight = val.Validator.LastSortitionHeight
 	} else {
@@ -234,17 +888,74 @@ func (n *Network) nodeInfoHandler(cmd command.Command, _ command.AppID, _ string
 		nodeInfo.LastSortitionHeight = 0
 	}
 
-	var pip19Score string
-	if nodeInfo.AvailabilityScore >= 0.9 {
-		pip19Score = fmt.Sprintf("%v✅", nodeInfo.AvailabilityScore)
-	} else {
-		pip19Score = fmt.Sprintf("%v⚠️", nodeInfo.AvailabilityScore)
+	return cmd.RenderResult(appID, "node_info", map[string]any{
+		"Node": nodeInfo,
+	}).WithReference(command.ReferenceValidator, valAddress)
+}
+
+// FoundValidator is a validator whose moniker matches the search.
+type FoundValidator struct {
+	Moniker           string
+	Address           string
+	Country           string
+	Active            bool // The validator is in the last snapshot of the active validators.
+	AvailabilityScore float64
+}
+
+func (n *Network) findHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	text := strings.TrimSpace(args[0])
+	if len([]rune(text)) < minFindLength {
+		return cmd.FailedResult("Please provide at least %d characters of the moniker", minFindLength)
+	}
+
+	matches := n.clientMgr.FindPeers(text)
+	total := len(matches)
+	if total > maxFindResults {
+		matches = matches[:maxFindResults]
+	}
+
+	validators, _ := n.clientMgr.GetValidators()
+	scores := make(map[string]float64, len(validators))
+	for _, val := range validators {
+		scores[val.Address] = val.AvailabilityScore
+	}
+
+	// the countries are looked up at once, a failed lookup has no country.
+	scan := client.Scan(ctx, client.NewScanner(0, 0), matches,
+		func(peerCtx context.Context, m client.PeerMatch) (FoundValidator, error) {
+			score, active := scores[m.Address]
+			v := FoundValidator{
+				Moniker:           m.Peer.Moniker,
+				Address:           m.Address,
+				Active:            active,
+				AvailabilityScore: score,
+			}
+			v.Country = utils.GetMultiAddrGeoIP(peerCtx, m.Peer.Address).CountryName
+
+			return v, nil
+		})
+	found := scan.Results
+
+	return cmd.RenderResult(appID, "network_find", map[string]any{
+		"Text":       text,
+		"Validators": found,
+		"Total":      total,
+	})
+}
+
+// validatorAddress returns the address of the validator, the argument is the validator address or number.
+func (n *Network) validatorAddress(ctx context.Context, arg string) (string, error) {
+	num, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil {
+		return arg, nil
+	}
+
+	val, err := n.clientMgr.GetValidatorInfoByNumber(ctx, int32(num))
+	if err != nil {
+		return "", err
 	}
 
-	return cmd.SuccessfulResult("PeerID: %s\nIP Address: %s\nAgent: %s\n"+
-		"Moniker: %s\nCountry: %s\nCity: %s\nRegion Name: %s\nTimeZone: %s\n"+
-		"ISP: %s\n\nValidator Info🔍\nNumber: %v\nPIP-19 Score: %s\nStake: %v PAC's\n",
-		nodeInfo.PeerID, nodeInfo.IPAddress, nodeInfo.Agent, nodeInfo.Moniker, nodeInfo.Country,
-		nodeInfo.City, nodeInfo.RegionName, nodeInfo.TimeZone, nodeInfo.ISP, utils.FormatNumber(int64(nodeInfo.ValidatorNum)),
-		pip19Score, utils.FormatNumber(nodeInfo.StakeAmount))
+	return val.Validator.Address, nil
 }
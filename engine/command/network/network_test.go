@@ -0,0 +1,407 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/indexer"
+	"github.com/pagu-project/Pagu/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidatorAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetValidatorInfoByNumber(gomock.Any(), int32(42)).Return(&pactus.GetValidatorResponse{
+		Validator: &pactus.ValidatorInfo{Number: 42, Address: "pc1p42"},
+	}, nil)
+
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	n := NewNetwork(cm, nil, nil, nil, ScoreRange{Min: 0.8, Max: 0.9}, nil)
+
+	addr, err := n.validatorAddress(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, "pc1p42", addr)
+
+	addr, err = n.validatorAddress(context.Background(), "pc1p43")
+	require.NoError(t, err)
+	assert.Equal(t, "pc1p43", addr, "the address is not resolved")
+
+	res := n.validatorHandler(context.Background(), n.GetCommand(), command.AppIdCLI, "user-id", "pc1p43")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "not a validator number")
+}
+
+func TestAtRiskValidators(t *testing.T) {
+	validators := []*pactus.ValidatorInfo{
+		{Number: 1, AvailabilityScore: 0.95},
+		{Number: 2, AvailabilityScore: 0.85},
+		{Number: 3, AvailabilityScore: 0.8},
+		{Number: 4, AvailabilityScore: 0.9},
+		{Number: 5, AvailabilityScore: 0.5},
+		{Number: 6, AvailabilityScore: 0.85},
+	}
+
+	atRisk := atRiskValidators(validators, ScoreRange{Min: 0.8, Max: 0.9})
+	numbers := make([]int32, 0, len(atRisk))
+	for _, val := range atRisk {
+		numbers = append(numbers, val.Number)
+	}
+	assert.Equal(t, []int32{3, 2, 6}, numbers)
+}
+
+func TestGrowth(t *testing.T) {
+	current := &database.NetworkSnapshot{Validators: 110}
+	past := []*database.NetworkSnapshot{{Validators: 100}, nil}
+	validators := func(s *database.NetworkSnapshot) int64 { return int64(s.Validators) }
+
+	g := growth("Validators", current, past, validators)
+	assert.Equal(t, int64(110), g.Current)
+	assert.Equal(t, Change{Days: 7, Known: true, Delta: 10, Percent: 10}, g.Changes[0])
+	assert.Equal(t, Change{Days: 30}, g.Changes[1])
+
+	g2 := growth("Validators", &database.NetworkSnapshot{Validators: 90}, past[:1], validators)
+	msg, err := command.RenderTemplate(command.AppIdCLI, "network_growth", map[string]any{
+		"Growths": []Growth{g, g2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Network growth📈\n\nValidators: 110\n7 days: +10 (+10.00%)⬆️\n30 days: no snapshot yet"+
+		"\n\nValidators: 90\n7 days: -10 (-10.00%)⬇️", msg)
+}
+
+func TestFind(t *testing.T) {
+	n := NewNetwork(client.NewClientMgr(context.Background()), nil, nil, nil, ScoreRange{}, nil)
+	cmd := n.GetCommand()
+
+	res := n.findHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "a")
+	assert.False(t, res.Successful)
+
+	res = n.findHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "pagu")
+	assert.True(t, res.Successful)
+	assert.Equal(t, `No connected validator has "pagu" in the moniker.`, res.Message)
+
+	msg, err := command.RenderTemplate(command.AppIdCLI, "network_find", map[string]any{
+		"Text": "pagu",
+		"Validators": []FoundValidator{
+			{Moniker: "Pagu-1", Address: "pc1p1", Country: "Germany", Active: true, AvailabilityScore: 0.95},
+			{Moniker: "pagu-2", Address: "pc1p2"},
+		},
+		"Total": 12,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, msg, "Pagu-1\nAddress: pc1p1\nCountry: Germany\nPIP-19 Score: 0.95")
+	assert.Contains(t, msg, "Country: unknown\nPIP-19 Score: not an active validator")
+	assert.Contains(t, msg, "2 of 12 validators are shown")
+}
+
+func TestParsePeerOptions(t *testing.T) {
+	opts, err := ParsePeerOptions(nil)
+	require.NoError(t, err)
+	assert.Equal(t, PeerOptions{Sort: SortByMoniker, Page: 1}, opts)
+
+	opts, err = ParsePeerOptions([]string{"--sort=height", "--filter=agent:V1.2", "--page=3"})
+	require.NoError(t, err)
+	assert.Equal(t, PeerOptions{Sort: SortByHeight, FilterKey: FilterAgent, FilterValue: "v1.2", Page: 3}, opts)
+	assert.Equal(t, "agent:v1.2", opts.Filter())
+
+	opts, err = ParsePeerOptions([]string{"2"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, opts.Page, "a bare number is the page")
+
+	for _, args := range [][]string{
+		{"--sort=stake"}, {"--filter=agent"}, {"--filter=ip:1.2"}, {"--page=0"}, {"--verbose"},
+	} {
+		_, err := ParsePeerOptions(args)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestPeers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	peers := make([]*pactus.PeerInfo, 0, 12)
+	for i := 0; i < 12; i++ {
+		peers = append(peers, &pactus.PeerInfo{
+			Moniker: fmt.Sprintf("node-%02d", i),
+			Agent:   "node=gui/version=v1.1.0",
+			Address: fmt.Sprintf("/ip4/198.51.100.%d/tcp/21888", i),
+			Height:  uint32(1_000 + i),
+			Status:  peerConnected,
+		})
+	}
+	peers = append(peers, &pactus.PeerInfo{Moniker: "node-99", Address: "/ip4/198.51.100.99/tcp/21888", Status: 1})
+	peers[3].Agent = "node=daemon/version=v1.2.0"
+	peers[7].Agent = "node=daemon/version=v1.2.1"
+
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetNetworkInfo(gomock.Any()).Return(&pactus.GetNetworkInfoResponse{
+		ConnectedPeers: peers,
+	}, nil).AnyTimes()
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	n := NewNetwork(cm, nil, nil, nil, ScoreRange{}, nil)
+	lastOctet := func(ip string) int {
+		last, _ := strconv.Atoi(ip[strings.LastIndex(ip, ".")+1:])
+
+		return last
+	}
+	n.lookupGeo = func(_ context.Context, ip string) (*utils.GeoIP, error) {
+		switch {
+		case lastOctet(ip) == 5:
+			return &utils.GeoIP{}, errors.New("rate limited")
+		case lastOctet(ip) < 5:
+			return &utils.GeoIP{CountryName: "Germany"}, nil
+		default:
+			return &utils.GeoIP{CountryName: "Canada"}, nil
+		}
+	}
+	n.dialPeer = func(_ context.Context, address string) (time.Duration, error) {
+		if address == "198.51.100.2:21888" {
+			return 0, errors.New("refused")
+		}
+
+		host, _, _ := net.SplitHostPort(address)
+
+		return time.Duration(100-lastOctet(host)) * time.Millisecond, nil
+	}
+	cmd := n.GetCommand()
+
+	res := n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id")
+	require.True(t, res.Successful)
+	assert.True(t, strings.HasPrefix(res.Message, "Connected peers🔍 12 by moniker\n\n1. node-00\n"), res.Message)
+	assert.Contains(t, res.Message, "10. node-09\n")
+	assert.NotContains(t, res.Message, "node-10")
+	assert.Contains(t, res.Message, "Height: 1,002, country: Germany, latency: not reachable")
+	assert.True(t, strings.HasSuffix(res.Message, "Page 1 of 2, try: --page=2"), res.Message)
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--page=2", "--sort=height")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "11. node-01\n")
+	assert.True(t, strings.HasSuffix(res.Message, "Page 2 of 2"), res.Message)
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--sort=latency")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "1. node-11\n", "the fastest first")
+	assert.Contains(t, res.Message, "latency: 89ms")
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--sort=latency", "--page=2")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "12. node-02\n", "the unreachable peer last")
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--filter=agent:v1.2")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "2 by moniker, with agent:v1.2\n\n1. node-03\n")
+	assert.Contains(t, res.Message, "2. node-07\n")
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--filter=country:canada",
+		"--sort=country")
+	require.True(t, res.Successful)
+	assert.True(t, strings.HasPrefix(res.Message,
+		"Connected peers🔍 6 by country, with country:canada\n\n1. node-06\n"), res.Message)
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--sort=country", "--page=2")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "12. node-05\nAgent: node=gui/version=v1.1.0\nHeight: 1,005, country: unknown")
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--page=3")
+	assert.Equal(t, "Page 3 is out of the 2 pages of the peers.", res.Message)
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--filter=moniker:pagu")
+	assert.Equal(t, "No connected peer with moniker:pagu.", res.Message)
+
+	res = n.peersHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "--sort=stake")
+	assert.False(t, res.Successful)
+	assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+}
+
+func TestPeerHost(t *testing.T) {
+	const peerID = "12D3KooWRR3tZo7tHnUZvYB3AT7ShR1tMYTYdURaEkEDx6PNLksV"
+
+	ip, port, ok := peerHost(context.Background(), "/ip4/198.51.100.7/tcp/21888/p2p/"+peerID)
+	assert.True(t, ok)
+	assert.Equal(t, "198.51.100.7", ip)
+	assert.Equal(t, 21888, port)
+
+	_, _, ok = peerHost(context.Background(), "/ip6/2001:db8::1/udp/21888/quic-v1")
+	assert.True(t, ok)
+
+	for _, address := range []string{
+		"/ip4/10.0.0.7/tcp/21888",
+		"/ip4/127.0.0.1/tcp/21888",
+		"/ip6/fe80::1/tcp/21888",
+		"/dns4/node.local/tcp/21888",
+		"/ip4/198.51.100.7/tcp/21888/p2p/" + peerID + "/p2p-circuit",
+		"not a multiaddr",
+	} {
+		_, _, ok = peerHost(context.Background(), address)
+		assert.False(t, ok, address)
+	}
+}
+
+func TestPeerCountry(t *testing.T) {
+	n := NewNetwork(client.NewClientMgr(context.Background()), nil, nil, nil, ScoreRange{}, nil)
+	lookups := 0
+	n.lookupGeo = func(_ context.Context, ip string) (*utils.GeoIP, error) {
+		lookups++
+		if ip == "198.51.100.5" {
+			return &utils.GeoIP{}, errors.New("rate limited")
+		}
+
+		return &utils.GeoIP{CountryName: "Germany"}, nil
+	}
+
+	assert.Equal(t, "Germany", n.peerCountry(context.Background(), "198.51.100.1"))
+	assert.Equal(t, "Germany", n.peerCountry(context.Background(), "198.51.100.1"))
+	assert.Equal(t, 1, lookups, "the country is cached")
+
+	assert.Empty(t, n.peerCountry(context.Background(), "198.51.100.5"))
+	assert.Empty(t, n.peerCountry(context.Background(), "198.51.100.5"))
+	assert.Equal(t, 3, lookups, "a failed lookup is not cached")
+}
+
+func TestStakeDistribution(t *testing.T) {
+	cm := client.NewClientMgr(context.Background())
+	n := NewNetwork(cm, indexer.NewIndexer(cm, nil), nil, nil, ScoreRange{}, nil)
+
+	res := n.stakeDistributionHandler(context.Background(), n.GetCommand(), command.AppIdCLI, "user-id")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "not loaded yet")
+
+	buckets := indexer.StakeDistribution([]int64{5e9, 250e9, 1_000e9}, indexer.StakeBounds)
+	msg, err := command.RenderTemplate(command.AppIdCLI, "network_stake_distribution", map[string]any{
+		"Validators": 3,
+		"Buckets":    buckets,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, msg, "Stake distribution📈 of 3 active validators\n<10 PAC: 1 validators (33.3%), 5 PAC staked\n")
+	assert.Contains(t, msg, "\n10-100 PAC: 0 validators (0.0%), 0 PAC staked\n")
+
+	cmd := n.GetCommand()
+	res = withStakeChart(cmd.SuccessfulResult("buckets"), command.AppIdDiscord, buckets)
+	require.Len(t, res.Attachments, 1)
+	assert.Equal(t, "image/png", res.Attachments[0].ContentType)
+	assert.Equal(t, "Bar chart of the validators by their stake in PAC: <10: 1, 10-100: 0, 100-500: 1, 500-1k: 0, "+
+		"1k+: 1. The total is 3, the highest is <10 with 1.", res.Attachments[0].AltText)
+	assert.Contains(t, res.Message, "buckets\n\nImage: Bar chart of the validators", "the fallback of the alt text")
+
+	res = withStakeChart(cmd.SuccessfulResult("buckets"), command.AppIdCLI, buckets)
+	assert.Empty(t, res.Attachments, "the CLI can't render images")
+}
+
+func TestTreasury(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{}, nil)
+	c.EXPECT().GetBalance(gomock.Any(), gomock.Any()).Return(int64(1_000_000_000_000), nil).
+		Times(len(client.TreasuryAddresses()))
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	n := NewNetwork(cm, indexer.NewIndexer(cm, db), nil, nil, ScoreRange{}, nil)
+
+	reserve, warm := client.TreasuryAddresses()[0], client.TreasuryAddresses()[4]
+	require.NoError(t, db.AddAccountTransactions([]*database.AccountTransaction{
+		{TxID: "aa", Address: reserve, Height: 10, Direction: database.TxOutgoing, Counterparty: warm, Amount: 5e12},
+		{TxID: "aa", Address: warm, Height: 10, Direction: database.TxIncoming, Counterparty: reserve, Amount: 5e12},
+		{TxID: "bb", Address: warm, Height: 20, Direction: database.TxOutgoing, Counterparty: "pc1z-grant", Amount: 2e12},
+	}))
+
+	res := n.treasuryHandler(context.Background(), n.GetCommand(), command.AppIdCLI, "user-id")
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "Balance: 6,000 PAC in 6 accounts")
+	assert.Contains(t, res.Message, "⬆️ 2,000 PAC to pc1z-grant\nFrom: "+warm)
+	assert.Contains(t, res.Message, "⬆️ 5,000 PAC to "+warm+" (a treasury account)\nFrom: "+reserve)
+	assert.Less(t, strings.Index(res.Message, "/bb"), strings.Index(res.Message, "/aa"), "the newest first")
+}
+
+func TestStatusDiff(t *testing.T) {
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	n := NewNetwork(client.NewClientMgr(context.Background()), nil, nil, nil, ScoreRange{}, db)
+	cmd := n.GetCommand()
+
+	res := n.networkStatusHandler(context.Background(), cmd, command.AppIdCLI, "alice", "--dif")
+	assert.False(t, res.Successful)
+	assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+
+	old := NetStatus{
+		ConnectedPeersCount: 50,
+		ValidatorsCount:     100,
+		CurrentBlockHeight:  1_000,
+		TotalNetworkPower:   5_000e9,
+		TotalAccounts:       300,
+	}
+	res = n.withStatusDiff(context.Background(), cmd, cmd.SuccessfulResult("status"), command.AppIdCLI, "alice", old)
+	assert.Contains(t, res.Message, "status\n\nChanges📈: this is your first check")
+
+	current := old
+	current.ConnectedPeersCount = 48
+	current.CurrentBlockHeight = 2_234
+	current.TotalNetworkPower = 4_500e9
+	res = n.withStatusDiff(context.Background(), cmd, cmd.SuccessfulResult("status"), command.AppIdCLI, "alice",
+		current)
+	assert.Contains(t, res.Message, "since your last check")
+	assert.Contains(t, res.Message, "ago:\nBlock height: +1,234⬆️\nPeers: -2⬇️\nTotal power: -500 PAC⬇️")
+	assert.NotContains(t, res.Message, "Validators")
+
+	res = n.withStatusDiff(context.Background(), cmd, cmd.SuccessfulResult("status"), command.AppIdCLI, "alice",
+		current)
+	assert.Contains(t, res.Message, "Nothing changed.")
+
+	res = n.withStatusDiff(context.Background(), cmd, cmd.SuccessfulResult("status"), command.AppIdCLI, "bob", current)
+	assert.Contains(t, res.Message, "this is your first check", "the checks are per user")
+}
+
+func TestHealthTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().LastBlockTime(gomock.Any()).Return(uint32(now.Unix()-3), uint32(1_000), nil)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	n := NewNetwork(cm, indexer.NewIndexer(cm, db), nil, nil, ScoreRange{}, nil)
+
+	require.NoError(t, db.AddHealthSample(&database.HealthSample{
+		Minute: now.Add(-time.Hour).Truncate(time.Minute), Height: 900, Lag: 90,
+	}))
+	require.NoError(t, db.AddHealthSample(&database.HealthSample{
+		Minute: now.Truncate(time.Minute), Height: 1_000, Lag: 20,
+	}))
+
+	res := n.networkHealthHandler(context.Background(), n.GetCommand(), command.AppIdCLI, "alice")
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "Network is Healthy")
+	assert.Contains(t, res.Message, "Last 24 hours from "+now.Add(-23*time.Hour).Format("15")+":00 UTC")
+	assert.Contains(t, res.Message, strings.Repeat("⬜", 22)+"🟥🟨\n")
+	assert.Contains(t, res.Message, "Degraded hours: 1, down hours: 1, the last issue at "+now.Format("15")+":00 UTC")
+}
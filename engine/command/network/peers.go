@@ -0,0 +1,319 @@
+package network
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/utils"
+)
+
+const (
+	peersPerPage = 10
+
+	// peerConnected is the status of a connected peer in the peer set of a Pactus node.
+	peerConnected int32 = 2
+
+	// countryTTL keeps the country of a peer for a day, the free tier of ip-api allows 45 lookups a minute.
+	countryTTL = 24 * time.Hour
+
+	// dialTimeout is the time to dial a peer, a peer that doesn't answer doesn't hold the scan.
+	dialTimeout = 2 * time.Second
+
+	SortByMoniker = "moniker"
+	SortByHeight  = "height"
+	SortByLatency = "latency"
+	SortByCountry = "country"
+
+	FilterAgent   = "agent"
+	FilterMoniker = "moniker"
+	FilterCountry = "country"
+)
+
+var (
+	peerSorts   = []string{SortByMoniker, SortByHeight, SortByLatency, SortByCountry}
+	peerFilters = []string{FilterAgent, FilterMoniker, FilterCountry}
+)
+
+// Peer is a row of the connected peers, the country and the latency are scanned.
+type Peer struct {
+	Rank    int
+	Moniker string
+	Agent   string
+	PeerID  string
+	Address string // The multiaddr of the peer.
+	Height  uint32
+	Country string
+	Latency time.Duration // Zero if the peer is not reachable.
+}
+
+// PeerOptions are the options of the peer list, like: --sort=height --filter=agent:v1.2 --page=2.
+type PeerOptions struct {
+	Sort        string
+	FilterKey   string
+	FilterValue string
+	Page        int
+}
+
+// ParsePeerOptions parses the options of the peer list, a bare number is the page.
+func ParsePeerOptions(args []string) (PeerOptions, error) {
+	opts := PeerOptions{Sort: SortByMoniker, Page: 1}
+	for _, arg := range args {
+		name, value, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		switch {
+		case name == "sort":
+			if !slices.Contains(peerSorts, value) {
+				return opts, fmt.Errorf("the sort should be one of: %s", strings.Join(peerSorts, ", "))
+			}
+			opts.Sort = value
+
+		case name == "filter":
+			key, text, ok := strings.Cut(value, ":")
+			if !ok || text == "" || !slices.Contains(peerFilters, key) {
+				return opts, fmt.Errorf("the filter should be like agent:v1.2, the keys are: %s",
+					strings.Join(peerFilters, ", "))
+			}
+			opts.FilterKey, opts.FilterValue = key, strings.ToLower(text)
+
+		case name == "page" || value == "":
+			if name == "page" {
+				name = value
+			}
+
+			page, err := strconv.Atoi(name)
+			if err != nil || page < 1 {
+				return opts, fmt.Errorf("%s is not an option, try: --sort=height --filter=agent:v1.2 --page=2", arg)
+			}
+			opts.Page = page
+
+		default:
+			return opts, fmt.Errorf("%s is not an option, try: --sort=height --filter=agent:v1.2 --page=2", arg)
+		}
+	}
+
+	return opts, nil
+}
+
+// scansAll returns true if all the peers should be scanned before the page is taken,
+// the other options scan only the peers of the page.
+func (o PeerOptions) scansAll() bool {
+	return o.Sort == SortByLatency || o.Sort == SortByCountry || o.FilterKey == FilterCountry
+}
+
+func (o PeerOptions) Filter() string {
+	if o.FilterKey == "" {
+		return ""
+	}
+
+	return o.FilterKey + ":" + o.FilterValue
+}
+
+func (n *Network) peersHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	opts, err := ParsePeerOptions(args)
+	if err != nil {
+		return cmd.FailedResult("%v", err).WithCode(command.ErrCodeInvalidArgs)
+	}
+
+	info, err := n.clientMgr.GetNetworkInfo(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	peers := connectedPeers(info.ConnectedPeers)
+	if opts.FilterKey != FilterCountry {
+		peers = filterPeers(peers, opts)
+	}
+
+	if opts.scansAll() {
+		peers = n.scanPeers(ctx, peers)
+		peers = filterPeers(peers, opts)
+	}
+
+	sortPeers(peers, opts.Sort)
+
+	total := len(peers)
+	from := min((opts.Page-1)*peersPerPage, total)
+	page := peers[from:min(from+peersPerPage, total)]
+	if !opts.scansAll() {
+		page = n.scanPeers(ctx, page)
+	}
+
+	for i := range page {
+		page[i].Rank = from + i + 1
+	}
+
+	pages := (total + peersPerPage - 1) / peersPerPage
+	nextPage := 0
+	if opts.Page < pages {
+		nextPage = opts.Page + 1
+	}
+
+	return cmd.RenderResult(appID, "network_peers", map[string]any{
+		"Peers":    page,
+		"Total":    total,
+		"Options":  opts,
+		"Pages":    pages,
+		"NextPage": nextPage,
+	})
+}
+
+func connectedPeers(infos []*pactus.PeerInfo) []Peer {
+	peers := make([]Peer, 0, len(infos))
+	for _, info := range infos {
+		if info.Status != peerConnected {
+			continue
+		}
+
+		p := Peer{
+			Moniker: info.Moniker,
+			Agent:   info.Agent,
+			Address: info.Address,
+			Height:  info.Height,
+		}
+		if id, err := peer.IDFromBytes(info.PeerId); err == nil {
+			p.PeerID = id.String()
+		}
+
+		peers = append(peers, p)
+	}
+
+	return peers
+}
+
+func filterPeers(peers []Peer, opts PeerOptions) []Peer {
+	if opts.FilterKey == "" {
+		return peers
+	}
+
+	return slices.DeleteFunc(peers, func(p Peer) bool {
+		value := ""
+		switch opts.FilterKey {
+		case FilterAgent:
+			value = p.Agent
+		case FilterMoniker:
+			value = p.Moniker
+		case FilterCountry:
+			value = p.Country
+		}
+
+		return !strings.Contains(strings.ToLower(value), opts.FilterValue)
+	})
+}
+
+// sortPeers sorts the peers, the highest first by the height and the unknown latencies and countries last.
+func sortPeers(peers []Peer, sortBy string) {
+	slices.SortStableFunc(peers, func(a, b Peer) int {
+		byMoniker := cmp.Or(cmp.Compare(a.Moniker, b.Moniker), cmp.Compare(a.PeerID, b.PeerID))
+		switch sortBy {
+		case SortByHeight:
+			return cmp.Or(cmp.Compare(b.Height, a.Height), byMoniker)
+
+		case SortByLatency:
+			return cmp.Or(compareKnown(a.Latency, b.Latency, 0), byMoniker)
+
+		case SortByCountry:
+			return cmp.Or(compareKnown(a.Country, b.Country, ""), byMoniker)
+
+		default:
+			return byMoniker
+		}
+	})
+}
+
+// compareKnown compares the values, the unknown value is the greatest.
+func compareKnown[T cmp.Ordered](a, b, unknown T) int {
+	switch {
+	case a == b:
+		return 0
+	case a == unknown:
+		return 1
+	case b == unknown:
+		return -1
+	default:
+		return cmp.Compare(a, b)
+	}
+}
+
+// scanPeers looks up the countries of the peers and dials them at once, a failed lookup has no country
+// and a failed dial has no latency. The relayed peers and the private IPs are not scanned.
+func (n *Network) scanPeers(ctx context.Context, peers []Peer) []Peer {
+	scan := client.Scan(ctx, client.NewScanner(0, 0), peers,
+		func(peerCtx context.Context, p Peer) (Peer, error) {
+			ip, port, ok := peerHost(peerCtx, p.Address)
+			if !ok {
+				return p, nil
+			}
+
+			p.Country = n.peerCountry(peerCtx, ip)
+			if port == 0 {
+				return p, nil
+			}
+
+			dialCtx, cancel := context.WithTimeout(peerCtx, dialTimeout)
+			defer cancel()
+
+			if latency, err := n.dialPeer(dialCtx, net.JoinHostPort(ip, strconv.Itoa(port))); err == nil {
+				p.Latency = max(latency.Round(time.Millisecond), time.Millisecond)
+			}
+
+			return p, nil
+		})
+
+	return scan.Results
+}
+
+// peerHost returns the public IP and the port of the multiaddr, like: /dns4/node.example.com/tcp/21888/p2p/12D3....
+// A host name is resolved to its public IP, the host of a relayed peer is the relay's.
+func peerHost(ctx context.Context, address string) (string, int, bool) {
+	addr, err := utils.ParseMultiAddr(address)
+	if err != nil || addr.Relayed {
+		return "", 0, false
+	}
+
+	ip, err := addr.ResolveIP(ctx)
+	if err != nil || !utils.IsPublicIP(net.ParseIP(ip)) {
+		return "", 0, false
+	}
+
+	return ip, addr.Port, true
+}
+
+// peerCountry returns the country of the IP, the countries are cached so a page of the peers doesn't look them up
+// again. A failed lookup is not cached.
+func (n *Network) peerCountry(ctx context.Context, ip string) string {
+	if country, ok, err := n.countries.Get(ctx, ip); err == nil && ok {
+		return string(country)
+	}
+
+	geo, err := n.lookupGeo(ctx, ip)
+	if err != nil {
+		return ""
+	}
+	_ = n.countries.Set(ctx, ip, []byte(geo.CountryName), countryTTL)
+
+	return geo.CountryName
+}
+
+// dialLatency returns the time to open a TCP connection to the address.
+func dialLatency(ctx context.Context, address string) (time.Duration, error) {
+	start := time.Now()
+	conn, err := new(net.Dialer).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	latency := time.Since(start)
+	_ = conn.Close()
+
+	return latency, nil
+}
@@ -0,0 +1,166 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+const (
+	dialTimeout      = 3 * time.Second
+	handshakeTimeout = 10 * time.Second
+)
+
+// Port is a port of the Pactus node.
+type Port struct {
+	Number int
+	Name   string
+}
+
+// defaultPorts are the ports that a Pactus node listens on by default.
+var defaultPorts = []Port{
+	{Number: 21888, Name: "Mainnet P2P"},
+	{Number: 21777, Name: "Testnet P2P"},
+	{Number: 50051, Name: "gRPC"},
+}
+
+// Target is the parsed address of the checked peer.
+type Target struct {
+	Host string
+	Port int // The port of the multiaddr, zero if there is none.
+	Peer *peer.AddrInfo
+}
+
+// Prober dials the peers, the default one dials them on the network.
+type Prober interface {
+	Resolve(ctx context.Context, host string) ([]net.IP, error)
+	DialTCP(ctx context.Context, address string) error
+	Handshake(ctx context.Context, info peer.AddrInfo) (string, error)
+}
+
+// ParseTarget parses an IP, a host name or a multiaddr, like: "/ip4/1.2.3.4/tcp/21888/p2p/12D3Koo...".
+func ParseTarget(arg string) (*Target, error) {
+	if !strings.HasPrefix(arg, "/") {
+		if net.ParseIP(arg) == nil && !isHostName(arg) {
+			return nil, fmt.Errorf("%s is not an IP or a multiaddr", arg)
+		}
+
+		return &Target{Host: arg}, nil
+	}
+
+	addr, err := ma.NewMultiaddr(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &Target{}
+	for _, code := range []int{ma.P_IP4, ma.P_IP6, ma.P_DNS, ma.P_DNS4, ma.P_DNS6} {
+		if value, err := addr.ValueForProtocol(code); err == nil {
+			target.Host = value
+
+			break
+		}
+	}
+
+	if target.Host == "" {
+		return nil, fmt.Errorf("%s has no IP or host name", arg)
+	}
+
+	if value, err := addr.ValueForProtocol(ma.P_TCP); err == nil {
+		target.Port, _ = strconv.Atoi(value)
+	}
+
+	if _, err := addr.ValueForProtocol(ma.P_P2P); err == nil {
+		info, err := peer.AddrInfoFromP2pAddr(addr)
+		if err != nil {
+			return nil, err
+		}
+		target.Peer = info
+	}
+
+	return target, nil
+}
+
+func isHostName(host string) bool {
+	if host == "" || len(host) > 253 {
+		return false
+	}
+
+	for _, r := range host {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '.' {
+			return false
+		}
+	}
+
+	return strings.Contains(host, ".")
+}
+
+// isPublic checks if the IP is on the internet, the bot doesn't dial its own network.
+func isPublic(ip net.IP) bool {
+	return !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsMulticast()
+}
+
+type netProber struct{}
+
+func (netProber) Resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+
+	return ips, nil
+}
+
+func (netProber) DialTCP(ctx context.Context, address string) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	conn, err := new(net.Dialer).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+// Handshake connects to the peer with a temporary libp2p host and returns the agent of the peer.
+func (netProber) Handshake(ctx context.Context, info peer.AddrInfo) (string, error) {
+	h, err := libp2p.New(libp2p.NoListenAddrs, libp2p.DisableRelay())
+	if err != nil {
+		return "", err
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	if err := h.Connect(ctx, info); err != nil {
+		return "", err
+	}
+
+	agent, err := h.Peerstore().Get(info.ID, "AgentVersion")
+	if err != nil {
+		return "", nil
+	}
+
+	s, _ := agent.(string)
+
+	return s, nil
+}
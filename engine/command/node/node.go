@@ -0,0 +1,242 @@
+package node
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+const (
+	CommandName      = "node"
+	CheckCommandName = "check"
+	HelpCommandName  = "help"
+)
+
+// minBans is the number of the RPC nodes that ban a peer for a network-wide ban, one node can have its own issue.
+const minBans = 2
+
+// peerStatuses are the names of the statuses of the peers of a Pactus node, like in its peer set.
+var peerStatuses = map[int32]string{
+	-1: "banned",
+	0:  "unknown",
+	1:  "disconnected",
+	2:  "connected",
+	3:  "known",
+}
+
+type Node struct {
+	prober    Prober
+	clientMgr *client.Mgr // The RPC nodes of the bot are the vantage points of the check, nil skips them.
+}
+
+func NewNode(clientMgr *client.Mgr) Node {
+	return Node{
+		prober:    netProber{},
+		clientMgr: clientMgr,
+	}
+}
+
+// PortResult is the reachability of a port of the checked peer.
+type PortResult struct {
+	Port
+	Open  bool
+	Error string
+}
+
+// Vantage is how an RPC node of the bot sees the checked peer.
+type Vantage struct {
+	Node     int
+	Answered bool
+	Status   string // Like: "connected", "disconnected" or "banned", empty if the node doesn't know the peer.
+}
+
+func (n *Node) GetCommand() command.Command {
+	subCmdCheck := command.Command{
+		Name: CheckCommandName,
+		Desc: "Check if a node is reachable from the internet",
+		Help: "Dials the default Pactus ports of the IP, and the port of the multiaddr if it has one. " +
+			"If the multiaddr has the peer ID, the libp2p handshake is checked too. " +
+			"The RPC nodes of Pagu tell if they are connected to the node or banned it, " +
+			"so a ban of the network is told apart from a local firewall. " +
+			"It helps to find out why a node has no peers",
+		Args: []command.Args{
+			{
+				Name:     "address",
+				Desc:     "IP or multiaddr of the node [example: /ip4/1.2.3.4/tcp/21888/p2p/12D3Koo...]",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"1.2.3.4", "/ip4/1.2.3.4/tcp/21888/p2p/12D3Koo..."},
+		Handler:     n.checkHandler,
+	}
+
+	cmdNode := command.Command{
+		Emoji:       "🩺",
+		Name:        CommandName,
+		Desc:        "Diagnostics of Pactus nodes",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdNode.AddSubCommand(subCmdCheck)
+
+	return cmdNode
+}
+
+func (n *Node) checkHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	target, err := ParseTarget(args[0])
+	if err != nil {
+		return cmd.FailedResult("%s", err.Error())
+	}
+
+	ips, err := n.prober.Resolve(ctx, target.Host)
+	if err != nil || len(ips) == 0 {
+		return cmd.FailedResult("Can't resolve %s", target.Host)
+	}
+
+	for _, ip := range ips {
+		if !isPublic(ip) {
+			return cmd.FailedResult("%s is not a public address, only the nodes on the internet can be checked", target.Host)
+		}
+	}
+	ip := ips[0]
+
+	ports := make([]Port, 0, len(defaultPorts)+1)
+	if target.Port != 0 && !isDefaultPort(target.Port) {
+		ports = append(ports, Port{Number: target.Port, Name: "Multiaddr"})
+	}
+	ports = append(ports, defaultPorts...)
+
+	results := make([]PortResult, len(ports))
+	wg := sync.WaitGroup{}
+	for i, port := range ports {
+		wg.Add(1)
+		go func(i int, port Port) {
+			defer wg.Done()
+
+			results[i] = PortResult{Port: port, Open: true}
+			if err := n.prober.DialTCP(ctx, net.JoinHostPort(ip.String(), strconv.Itoa(port.Number))); err != nil {
+				results[i].Open = false
+				results[i].Error = err.Error()
+			}
+		}(i, port)
+	}
+	wg.Wait()
+
+	data := map[string]any{
+		"Host":    target.Host,
+		"IP":      ip.String(),
+		"Ports":   results,
+		"AnyOpen": anyOpen(results),
+		"P2POpen": anyOpen(results[:len(results)-1]), // all but the gRPC port.
+	}
+
+	if target.Peer != nil {
+		data["Handshake"] = true
+		data["PeerID"] = target.Peer.ID.String()
+		data["HandshakeError"] = "the multiaddr has no TCP port"
+
+		if target.Port != 0 {
+			agent, err := n.handshake(ctx, target, ip)
+			if err != nil {
+				data["HandshakeError"] = err.Error()
+			} else {
+				data["HandshakeError"] = ""
+				data["Agent"] = agent
+			}
+		}
+	}
+
+	if n.clientMgr != nil {
+		vantages := n.vantages(ctx, target, ip)
+		banned := 0
+		for _, v := range vantages {
+			if v.Status == peerStatuses[-1] {
+				banned++
+			}
+		}
+
+		data["Vantages"] = vantages
+		data["Banned"] = banned
+		data["BannedWidely"] = banned >= minBans
+	}
+
+	return cmd.RenderResult(appID, "node_check", data)
+}
+
+// vantages returns how the RPC nodes see the peer, it's matched by the peer ID if the multiaddr has it,
+// otherwise by the IP.
+func (n *Node) vantages(ctx context.Context, target *Target, ip net.IP) []Vantage {
+	match := func(p *pactus.PeerInfo) bool {
+		if target.Peer != nil {
+			return peer.ID(p.PeerId) == target.Peer.ID
+		}
+
+		return strings.Contains(p.Address+"/", "/"+ip.String()+"/")
+	}
+
+	views := n.clientMgr.PeerViews(ctx, match)
+	vantages := make([]Vantage, 0, len(views))
+	for _, view := range views {
+		v := Vantage{Node: view.Node, Answered: view.Error == nil}
+		if view.Peer != nil {
+			v.Status = peerStatuses[view.Peer.Status]
+		}
+		vantages = append(vantages, v)
+	}
+
+	return vantages
+}
+
+// handshake dials the resolved IP, so the host name is not resolved again to another address.
+func (n *Node) handshake(ctx context.Context, target *Target, ip net.IP) (string, error) {
+	proto := "/ip4/"
+	if ip.To4() == nil {
+		proto = "/ip6/"
+	}
+
+	addr, err := ma.NewMultiaddr(proto + ip.String() + "/tcp/" + strconv.Itoa(target.Port))
+	if err != nil {
+		return "", err
+	}
+
+	return n.prober.Handshake(ctx, peer.AddrInfo{
+		ID:    target.Peer.ID,
+		Addrs: []ma.Multiaddr{addr},
+	})
+}
+
+func isDefaultPort(number int) bool {
+	for _, port := range defaultPorts {
+		if port.Number == number {
+			return true
+		}
+	}
+
+	return false
+}
+
+func anyOpen(results []PortResult) bool {
+	for _, res := range results {
+		if res.Open {
+			return true
+		}
+	}
+
+	return false
+}
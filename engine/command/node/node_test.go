@@ -0,0 +1,197 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeProber struct {
+	ips       map[string][]net.IP
+	open      map[string]bool
+	handshake error
+	dialed    []string
+}
+
+func (p *fakeProber) Resolve(_ context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	return p.ips[host], nil
+}
+
+func (p *fakeProber) DialTCP(_ context.Context, address string) error {
+	if p.open[address] {
+		return nil
+	}
+
+	return errors.New("connection refused")
+}
+
+func (p *fakeProber) Handshake(_ context.Context, info peer.AddrInfo) (string, error) {
+	p.dialed = append(p.dialed, info.Addrs[0].String())
+	if p.handshake != nil {
+		return "", p.handshake
+	}
+
+	return "node=full/node-version=v1.1.4", nil
+}
+
+func peerID(t testing.TB) peer.ID {
+	t.Helper()
+
+	_, pub, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(t, err)
+	id, err := peer.IDFromPublicKey(pub)
+	require.NoError(t, err)
+
+	return id
+}
+
+func TestParseTarget(t *testing.T) {
+	id := peerID(t)
+
+	target, err := ParseTarget("1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, &Target{Host: "1.2.3.4"}, target)
+
+	target, err = ParseTarget("/dns4/node.example.com/tcp/21888/p2p/" + id.String())
+	require.NoError(t, err)
+	assert.Equal(t, "node.example.com", target.Host)
+	assert.Equal(t, 21888, target.Port)
+	assert.Equal(t, id, target.Peer.ID)
+
+	_, err = ParseTarget("not an address")
+	assert.Error(t, err)
+
+	_, err = ParseTarget("/tcp/21888")
+	assert.Error(t, err)
+}
+
+func FuzzParseTarget(f *testing.F) {
+	id := peerID(f)
+	for _, seed := range []string{
+		"1.2.3.4", "node.example.com", "/ip4/1.2.3.4/tcp/21888/p2p/" + id.String(), "/ip6/::1/tcp/0",
+		"/dns4/node.example.com/p2p/12D3KooW", "/p2p/" + id.String(), "/tcp/21888", "/", "",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, arg string) {
+		target, err := ParseTarget(arg)
+		if err != nil {
+			return
+		}
+
+		if target.Host == "" {
+			t.Fatalf("%q is parsed without a host", arg)
+		}
+
+		if target.Port < 0 || target.Port > 65535 {
+			t.Fatalf("%q is parsed with the port %d", arg, target.Port)
+		}
+
+		if target.Peer != nil {
+			if err := target.Peer.ID.Validate(); err != nil {
+				t.Fatalf("%q is parsed with an invalid peer ID: %v", arg, err)
+			}
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	prober := &fakeProber{
+		ips: map[string][]net.IP{
+			"node.example.com": {net.ParseIP("8.8.4.4")},
+			"internal.example": {net.ParseIP("10.0.0.1")},
+		},
+		open: map[string]bool{"8.8.4.4:21888": true, "8.8.4.4:50051": true},
+	}
+	n := &Node{prober: prober}
+	cmd := n.GetCommand()
+
+	res := n.checkHandler(context.Background(), cmd, command.AppIdCLI, "", "127.0.0.1")
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "not a public address")
+
+	res = n.checkHandler(context.Background(), cmd, command.AppIdCLI, "", "internal.example")
+	assert.False(t, res.Successful, "the resolved address is private")
+
+	id := peerID(t)
+	res = n.checkHandler(context.Background(), cmd, command.AppIdCLI, "",
+		"/dns4/node.example.com/tcp/21888/p2p/"+id.String())
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "Connection check of node.example.com (8.8.4.4)")
+	assert.Contains(t, res.Message, "Port 21888 (Mainnet P2P): open")
+	assert.Contains(t, res.Message, "Port 21777 (Testnet P2P): closed")
+	assert.Contains(t, res.Message, "agent: node=full/node-version=v1.1.4")
+	assert.Contains(t, res.Message, "The node is reachable from the internet.")
+	assert.Equal(t, []string{"/ip4/8.8.4.4/tcp/21888"}, prober.dialed, "the resolved IP is dialed")
+
+	prober.handshake = errors.New("peer id mismatch")
+	res = n.checkHandler(context.Background(), cmd, command.AppIdCLI, "", "/ip4/8.8.4.4/tcp/21888/p2p/"+id.String())
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "failed❌ (peer id mismatch)")
+	assert.Contains(t, res.Message, "the handshake failed")
+
+	prober.open = map[string]bool{"8.8.4.4:50051": true}
+	res = n.checkHandler(context.Background(), cmd, command.AppIdCLI, "", "8.8.4.4")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "The P2P port is not reachable")
+}
+
+func TestVantages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	id := peerID(t)
+	cm := client.NewClientMgr(context.Background())
+	nodes := make([]*client.MockIClient, 0, 3)
+	for i := 0; i < 3; i++ {
+		c := client.NewMockIClient(ctrl)
+		cm.AddClient(c)
+		nodes = append(nodes, c)
+	}
+
+	peers := func(status int32) *pactus.GetNetworkInfoResponse {
+		return &pactus.GetNetworkInfoResponse{
+			ConnectedPeers: []*pactus.PeerInfo{
+				{PeerId: []byte(peerID(t)), Address: "/ip4/8.8.8.8/tcp/21888", Status: 2},
+				{PeerId: []byte(id), Address: "/ip4/8.8.4.4/tcp/21888", Status: status},
+			},
+		}
+	}
+
+	prober := &fakeProber{open: map[string]bool{"8.8.4.4:21888": true}}
+	n := &Node{prober: prober, clientMgr: cm}
+	cmd := n.GetCommand()
+
+	nodes[0].EXPECT().GetNetworkInfo(gomock.Any()).Return(peers(-1), nil)
+	nodes[1].EXPECT().GetNetworkInfo(gomock.Any()).Return(peers(-1), nil)
+	nodes[2].EXPECT().GetNetworkInfo(gomock.Any()).Return(nil, errors.New("unavailable"))
+	res := n.checkHandler(context.Background(), cmd, command.AppIdCLI, "", "/ip4/8.8.4.4/tcp/21888/p2p/"+id.String())
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message,
+		"Seen by the RPC nodes of Pagu:\nNode 1: banned❌\nNode 2: banned❌\nNode 3: no answer\n")
+	assert.Contains(t, res.Message, "2 of the RPC nodes banned the node: it's banned by the network")
+	assert.NotContains(t, res.Message, "reachable from the internet")
+
+	nodes[0].EXPECT().GetNetworkInfo(gomock.Any()).Return(peers(-1), nil)
+	nodes[1].EXPECT().GetNetworkInfo(gomock.Any()).Return(peers(2), nil)
+	nodes[2].EXPECT().GetNetworkInfo(gomock.Any()).Return(&pactus.GetNetworkInfoResponse{}, nil)
+	res = n.checkHandler(context.Background(), cmd, command.AppIdCLI, "", "8.8.4.4")
+	require.True(t, res.Successful)
+	assert.Contains(t, res.Message, "Node 1: banned❌\nNode 2: connected✅\nNode 3: not known\n", "matched by the IP")
+	assert.Contains(t, res.Message, "The node is reachable from the internet.")
+	assert.Contains(t, res.Message, "One RPC node banned the node and the others didn't")
+}
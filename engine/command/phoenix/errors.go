@@ -0,0 +1,24 @@
+package phoenix
+
+import "fmt"
+
+type EmptyWalletError struct{}
+
+func (e EmptyWalletError) Error() string {
+	return "RoboPac Phoenix wallet is empty, please contact the team!"
+}
+
+type FaucetUsedError struct{}
+
+func (e FaucetUsedError) Error() string {
+	return "Uh, you used your share of faucets today!"
+}
+
+type ReviewStatusError struct {
+	ID     uint
+	Status string
+}
+
+func (e ReviewStatusError) Error() string {
+	return fmt.Sprintf("claim %d is already %s", e.ID, e.Status)
+}
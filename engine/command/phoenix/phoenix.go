@@ -1,15 +1,20 @@
 package phoenix
 
 import (
-	"fmt"
+	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/abuse"
 	"github.com/pagu-project/Pagu/client"
 	"github.com/pagu-project/Pagu/database"
 	"github.com/pagu-project/Pagu/engine/command"
 	"github.com/pagu-project/Pagu/engine/command/network"
+	"github.com/pagu-project/Pagu/lock"
+	"github.com/pagu-project/Pagu/log"
 	"github.com/pagu-project/Pagu/utils"
 	"github.com/pagu-project/Pagu/wallet"
 )
@@ -21,25 +26,47 @@ const (
 	StatusCommandName   = "status"
 	HealthCommandName   = "health"
 	NodeInfoCommandName = "node-info"
+	ReviewCommandName   = "review"
+	ListCommandName     = "list"
+	ApproveCommandName  = "approve"
+	RejectCommandName   = "reject"
 	HelpCommandName     = "help"
 )
 
+const (
+	faucetAmount = 5 //! define me on config?
+
+	// payoutLockTTL is longer than a transfer, so the user is not paid twice by two instances.
+	payoutLockTTL = 2 * time.Minute
+)
+
 type Phoenix struct {
-	wallet    *wallet.Wallet
-	db        database.DB
-	clientMgr *client.Mgr
+	wallet     *wallet.Wallet
+	db         database.DB
+	clientMgr  *client.Mgr
+	detector   *abuse.Detector
+	locker     *lock.Locker
+	faucetMemo string // Memo template of the faucet transactions.
 }
 
 func NewPhoenix(wallet *wallet.Wallet,
-	clientMgr *client.Mgr, db database.DB,
+	clientMgr *client.Mgr, db database.DB, detector *abuse.Detector, locker *lock.Locker, faucetMemo string,
 ) Phoenix {
 	return Phoenix{
-		wallet:    wallet,
-		clientMgr: clientMgr,
-		db:        db,
+		wallet:     wallet,
+		clientMgr:  clientMgr,
+		db:         db,
+		detector:   detector,
+		locker:     locker,
+		faucetMemo: faucetMemo,
 	}
 }
 
+// Enabled returns true if the testnet wallet is open, the faucet sends the coins from it.
+func (pt *Phoenix) Enabled() bool {
+	return pt.wallet != nil
+}
+
 func (pt *Phoenix) GetCommand() command.Command {
 	subCmdFaucet := command.Command{
 		Name: FaucetCommandName,
@@ -50,10 +77,13 @@ func (pt *Phoenix) GetCommand() command.Command {
 				Name:     "address",
 				Desc:     "your testnet address [example: tpc1z...]",
 				Optional: false,
+				Kind:     command.ArgAddress,
 			},
 		},
 		SubCommands: nil,
 		AppIDs:      command.AllAppIDs(),
+		Challenge:   true,
+		Mutating:    true,
 		Handler:     pt.faucetHandler,
 	}
 
@@ -96,6 +126,7 @@ func (pt *Phoenix) GetCommand() command.Command {
 				Name:     "validator_address",
 				Desc:     "Your validator address start with tpc1p...",
 				Optional: false,
+				Kind:     command.ArgAddress,
 			},
 		},
 		SubCommands: nil,
@@ -103,6 +134,65 @@ func (pt *Phoenix) GetCommand() command.Command {
 		Handler:     pt.nodeInfoHandler,
 	}
 
+	subCmdReviewList := command.Command{
+		Name:        ListCommandName,
+		Desc:        "List the suspicious faucet claims waiting for review",
+		Help:        "",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     pt.reviewListHandler,
+	}
+
+	subCmdReviewApprove := command.Command{
+		Name: ApproveCommandName,
+		Desc: "Approve a suspicious faucet claim and send the coins",
+		Help: "",
+		Args: []command.Args{
+			{
+				Name:     "id",
+				Desc:     "ID of the review in the list",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Mutating:    true,
+		Handler:     pt.reviewApproveHandler,
+	}
+
+	subCmdReviewReject := command.Command{
+		Name: RejectCommandName,
+		Desc: "Reject a suspicious faucet claim",
+		Help: "",
+		Args: []command.Args{
+			{
+				Name:     "id",
+				Desc:     "ID of the review in the list",
+				Optional: false,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Mutating:    true,
+		Handler:     pt.reviewRejectHandler,
+	}
+
+	subCmdReview := command.Command{
+		Name:        ReviewCommandName,
+		Desc:        "Review the suspicious faucet claims",
+		Help:        "Claims that look like abuse are queued here and paid only after an admin approves them",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		AdminOnly:   true,
+		Handler:     nil,
+	}
+
+	subCmdReview.AddSubCommand(subCmdReviewList)
+	subCmdReview.AddSubCommand(subCmdReviewApprove)
+	subCmdReview.AddSubCommand(subCmdReviewReject)
+
 	cmdPhoenix := command.Command{
 		Name:        CommandName,
 		Desc:        "Phoenix Testnet tools and utils for developers",
@@ -110,6 +200,7 @@ func (pt *Phoenix) GetCommand() command.Command {
 		Args:        nil,
 		AppIDs:      command.AllAppIDs(),
 		SubCommands: make([]command.Command, 0),
+		Testnet:     true,
 		Handler:     nil,
 	}
 
@@ -118,11 +209,19 @@ func (pt *Phoenix) GetCommand() command.Command {
 	cmdPhoenix.AddSubCommand(subCmdHealth)
 	cmdPhoenix.AddSubCommand(subCmdStatus)
 	cmdPhoenix.AddSubCommand(subCmdNodeInfo)
+	cmdPhoenix.AddSubCommand(subCmdReview)
 
 	return cmdPhoenix
 }
 
-func (pt *Phoenix) faucetHandler(cmd command.Command, _ command.AppID, callerID string, args ...string) command.CommandResult {
+func (pt *Phoenix) faucetHandler(ctx context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	// the faucet transfers to the accounts, the stake of a validator is bonded from an account.
+	if command.IsValidatorAddress(args[0]) {
+		return pt.validatorFaucetResult(ctx, cmd, args[0])
+	}
+
 	if !pt.db.HasUser(callerID) {
 		if err := pt.db.AddUser(
 			&database.User{
@@ -137,112 +236,254 @@ func (pt *Phoenix) faucetHandler(cmd command.Command, _ command.AppID, callerID
 		return cmd.FailedResult("Uh, you used your share of faucets today!")
 	}
 
-	if pt.wallet.Balance() < 5 {
+	if pt.db.HasPendingFaucetReview(callerID) {
+		return cmd.FailedResult("Your previous claim is still under review, please wait for the team!")
+	}
+
+	if pt.wallet.Balance() < faucetAmount {
 		return cmd.FailedResult("RoboPac Phoenix wallet is empty, please contact the team!")
 	}
 
 	toAddr := args[0]
-	txID, err := pt.wallet.TransferTransaction(toAddr, "Phoenix Testnet Pagu Faucet", 5) //! define me on config?
+	verdict := pt.detector.Check(ctx, abuse.Claim{
+		AppID:    appID,
+		CallerID: callerID,
+		Address:  toAddr,
+	})
+	if verdict.Suspicious() {
+		review := &database.FaucetReview{
+			UserID:  callerID,
+			Address: toAddr,
+			Reasons: strings.Join(verdict.Reasons, "\n"),
+			Status:  database.FaucetReviewPending,
+		}
+		if err := pt.db.AddFaucetReview(review); err != nil {
+			return cmd.ErrorResult(err)
+		}
+
+		log.Ctx(ctx).Info("faucet claim queued for review", "id", review.ID, "user", callerID,
+			"reasons", verdict.Reasons)
+
+		return cmd.RenderResult(appID, "phoenix_faucet_review", map[string]any{
+			"ID":      review.ID,
+			"Address": toAddr,
+		})
+	}
+
+	faucet, err := pt.payout(ctx, callerID, toAddr)
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	if err = pt.db.AddFaucet(&database.Faucet{
-		Address:         toAddr,
-		Amount:          5,
-		TransactionHash: txID,
-		UserID:          callerID,
-	}); err != nil {
+	return cmd.SuccessfulResult("You got %d tPAC in %s address on Phoenix Testnet! Memo: %s",
+		faucetAmount, toAddr, faucet.Memo)
+}
+
+// validatorFaucetResult explains how to stake the faucet coins on a validator,
+// the first bond of a validator needs its public key.
+func (pt *Phoenix) validatorFaucetResult(ctx context.Context, cmd command.Command,
+	valAddress string,
+) command.CommandResult {
+	_, err := pt.clientMgr.GetValidatorInfo(ctx, valAddress)
+	if err != nil && !client.IsNotFound(err) {
 		return cmd.ErrorResult(err)
 	}
 
-	return cmd.SuccessfulResult("You got %d tPAC in %s address on Phoenix Testnet!", 5, toAddr)
-}
+	hint := "It's bonded already, so the bond needs no public key."
+	if err != nil {
+		hint = "It's not bonded yet, so its first bond needs the public key of the validator, " +
+			"find it in the wallet of your node."
+	}
 
-func (pt *Phoenix) walletHandler(cmd command.Command, _ command.AppID, _ string, args ...string) command.CommandResult {
-	return cmd.SuccessfulResult("Pagu Phoenix Address: %s\nBalance: %d", pt.wallet.Address(), pt.wallet.Balance())
+	return cmd.FailedResult("%s is a validator address, the faucet sends tPAC to the account addresses "+
+		"like tpc1z.... Claim to your account, then bond the tPAC to the validator. %s", valAddress, hint).
+		WithCode(command.ErrCodeInvalidAddress)
 }
 
-func (pt *Phoenix) networkHealthHandler(cmd command.Command, _ command.AppID, _ string, _ ...string) command.CommandResult {
-	lastBlockTime, lastBlockHeight := pt.clientMgr.GetLastBlockTime()
-	lastBlockTimeFormatted := time.Unix(int64(lastBlockTime), 0).Format("02/01/2006, 15:04:05")
-	currentTime := time.Now()
+// payout sends the faucet coins to the address and records it for the user.
+// The user is locked across the instances and the daily share is checked again while holding the lock.
+// The claim is recorded before the transfer, so its ID is in the memo, and it's removed if the transfer fails.
+func (pt *Phoenix) payout(ctx context.Context, userID, toAddr string) (*database.Faucet, error) {
+	faucet := &database.Faucet{
+		Address: toAddr,
+		Amount:  faucetAmount,
+		UserID:  userID,
+	}
 
-	timeDiff := (currentTime.Unix() - int64(lastBlockTime))
+	err := pt.locker.WithLock("faucet-payout:"+userID, payoutLockTTL, func() error {
+		if !pt.db.CanGetFaucet(userID) {
+			return FaucetUsedError{}
+		}
 
-	healthStatus := true
-	if timeDiff > 15 {
-		healthStatus = false
-	}
+		if pt.wallet.Balance() < faucetAmount {
+			return EmptyWalletError{}
+		}
 
-	var status string
-	if healthStatus {
-		status = "Healthy✅"
-	} else {
-		status = "UnHealthy❌"
+		if err := pt.db.AddFaucet(faucet); err != nil {
+			return err
+		}
+
+		faucet.Memo = wallet.RenderMemo(pt.faucetMemo, wallet.MemoValues{
+			ClaimID: faucet.ID,
+			Address: toAddr,
+			Amount:  faucetAmount,
+		})
+
+		txID, err := pt.wallet.TransferTransaction(toAddr, faucet.Memo, faucetAmount)
+		if err != nil {
+			if delErr := pt.db.DeleteFaucet(faucet.ID); delErr != nil {
+				log.Ctx(ctx).Error("can't remove the failed faucet claim", "id", faucet.ID, "err", delErr)
+			}
+
+			return err
+		}
+		faucet.TransactionHash = txID
+
+		return pt.db.UpdateFaucetTransaction(faucet.ID, txID, faucet.Memo)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return cmd.SuccessfulResult("Network is %s\nCurrentTime: %v\nLastBlockTime: %v\nTime Diff: %v\nLast Block Height: %v",
-		status, currentTime.Format("02/01/2006, 15:04:05"), lastBlockTimeFormatted, timeDiff, utils.FormatNumber(int64(lastBlockHeight)))
+	return faucet, nil
 }
 
-func (pt *Phoenix) networkStatusHandler(cmd command.Command, _ command.AppID, _ string, _ ...string) command.CommandResult {
-	netInfo, err := pt.clientMgr.GetNetworkInfo()
+func (pt *Phoenix) reviewListHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	reviews, err := pt.db.GetPendingFaucetReviews()
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	chainInfo, err := pt.clientMgr.GetBlockchainInfo()
+	return cmd.RenderResult(appID, "phoenix_review_list", map[string]any{
+		"Reviews": reviews,
+	})
+}
+
+func (pt *Phoenix) reviewApproveHandler(ctx context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	review, err := pt.pendingReview(args[0])
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	cs, err := pt.clientMgr.GetCirculatingSupply()
+	faucet, err := pt.payout(ctx, review.UserID, review.Address)
 	if err != nil {
-		cs = 0
+		return cmd.ErrorResult(err)
 	}
 
-	// Convert int64 to float64.
-	totalNetworkPower, err := amount.NewAmount(float64(chainInfo.TotalPower))
+	if err := pt.db.UpdateFaucetReviewStatus(review.ID, database.FaucetReviewApproved, callerID); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("Claim %d is approved, %d tPAC sent to %s with the memo: %s",
+		review.ID, faucetAmount, review.Address, faucet.Memo)
+}
+
+func (pt *Phoenix) reviewRejectHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	review, err := pt.pendingReview(args[0])
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	totalCommitteePower, err := amount.NewAmount(float64(chainInfo.CommitteePower))
+	if err := pt.db.UpdateFaucetReviewStatus(review.ID, database.FaucetReviewRejected, callerID); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("Claim %d is rejected", review.ID)
+}
+
+func (pt *Phoenix) pendingReview(idStr string) (*database.FaucetReview, error) {
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := pt.db.GetFaucetReview(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Status != database.FaucetReviewPending {
+		return nil, ReviewStatusError{
+			ID:     review.ID,
+			Status: string(review.Status),
+		}
+	}
+
+	return review, nil
+}
+
+func (pt *Phoenix) walletHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	return cmd.RenderResult(appID, "phoenix_wallet", map[string]any{
+		"Address": pt.wallet.Address(),
+		"Balance": amount.Amount(pt.wallet.Balance()),
+	})
+}
+
+func (pt *Phoenix) networkHealthHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	lastBlockTime, lastBlockHeight := pt.clientMgr.GetLastBlockTime(ctx)
+	lastBlockTimeFormatted := time.Unix(int64(lastBlockTime), 0).Format("02/01/2006, 15:04:05")
+	currentTime := time.Now()
+
+	timeDiff := (currentTime.Unix() - int64(lastBlockTime))
+
+	return cmd.RenderResult(appID, "network_health", map[string]any{
+		"Healthy":         timeDiff <= 15,
+		"CurrentTime":     currentTime.Format("02/01/2006, 15:04:05"),
+		"LastBlockTime":   lastBlockTimeFormatted,
+		"TimeDiff":        timeDiff,
+		"LastBlockHeight": lastBlockHeight,
+	})
+}
+
+func (pt *Phoenix) networkStatusHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	netInfo, err := pt.clientMgr.GetNetworkInfo(ctx)
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	circulatingSupply, err := amount.NewAmount(float64(cs))
+	chainInfo, err := pt.clientMgr.GetBlockchainInfo(ctx)
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	// Convert Amount back to int64 for struct literal.
+	cs, err := pt.clientMgr.GetCirculatingSupply(ctx)
+	if err != nil {
+		cs = 0
+	}
+
+	sent, received, trafficNodes := pt.clientMgr.GetNetworkBytes(ctx)
+
 	net := network.NetStatus{
 		ValidatorsCount:     chainInfo.TotalValidators,
+		TotalBytesSent:      sent,
+		TotalBytesReceived:  received,
+		TrafficNodes:        trafficNodes,
 		CurrentBlockHeight:  chainInfo.LastBlockHeight,
-		TotalNetworkPower:   int64(totalNetworkPower.ToPAC()),
-		TotalCommitteePower: int64(totalCommitteePower.ToPAC()),
+		TotalNetworkPower:   amount.Amount(chainInfo.TotalPower),
+		TotalCommitteePower: amount.Amount(chainInfo.CommitteePower),
 		NetworkName:         netInfo.NetworkName,
 		TotalAccounts:       chainInfo.TotalAccounts,
-		CirculatingSupply:   int64(circulatingSupply.ToPAC()),
-	}
-
-	return cmd.SuccessfulResult("Network Name: %s\nConnected Peers: %v\n"+
-		"Validators Count: %v\nAccounts Count: %v\nCurrent Block Height: %v\nTotal Power: %v\nTotal Committee Power: %v\nCirculating Supply: %v\n"+
-		"\n> Note📝: This info is from one random network node. Non-blockchain data may not be consistent.",
-		net.NetworkName,
-		utils.FormatNumber(int64(net.ConnectedPeersCount)),
-		utils.FormatNumber(int64(net.ValidatorsCount)),
-		utils.FormatNumber(int64(net.TotalAccounts)),
-		utils.FormatNumber(int64(net.CurrentBlockHeight)),
-		net.TotalNetworkPower,
-		net.TotalCommitteePower,
-		net.CirculatingSupply)
+		CirculatingSupply:   amount.Amount(cs),
+	}
+
+	return cmd.RenderResult(appID, "phoenix_status", net)
 }
 
-func (pt *Phoenix) nodeInfoHandler(cmd command.Command, _ command.AppID, _ string, args ...string) command.CommandResult {
+func (pt *Phoenix) nodeInfoHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
 	valAddress := args[0]
 
 	peerInfo, err := pt.clientMgr.GetPeerInfo(valAddress)
@@ -255,29 +496,32 @@ func (pt *Phoenix) nodeInfoHandler(cmd command.Command, _ command.AppID, _ strin
 		return cmd.ErrorResult(err)
 	}
 
-	ip := utils.ExtractIPFromMultiAddr(peerInfo.Address)
-	geoData := utils.GetGeoIP(ip)
+	geoData := utils.GetMultiAddrGeoIP(ctx, peerInfo.Address)
+	addr, _ := utils.ParseMultiAddr(peerInfo.Address)
 
 	nodeInfo := &network.NodeInfo{
-		PeerID:     peerID.String(),
-		IPAddress:  peerInfo.Address,
-		Agent:      peerInfo.Agent,
-		Moniker:    peerInfo.Moniker,
-		Country:    geoData.CountryName,
-		City:       geoData.City,
-		RegionName: geoData.RegionName,
-		TimeZone:   geoData.TimeZone,
-		ISP:        geoData.ISP,
+		PeerID:           peerID.String(),
+		ValidatorAddress: valAddress,
+		IPAddress:        peerInfo.Address,
+		Host:             addr.Host,
+		Relayed:          addr.Relayed,
+		Agent:            peerInfo.Agent,
+		Moniker:          peerInfo.Moniker,
+		Country:          geoData.CountryName,
+		City:             geoData.City,
+		RegionName:       geoData.RegionName,
+		TimeZone:         geoData.TimeZone,
+		ISP:              geoData.ISP,
 	}
 
 	// here we check if the node is also a validator.
 	// if its a validator , then we populate the validator data.
 	// if not validator then we set everything to 0/empty .
-	val, err := pt.clientMgr.GetValidatorInfo(valAddress)
+	val, err := pt.clientMgr.GetValidatorInfo(ctx, valAddress)
 	if err == nil && val != nil {
 		nodeInfo.ValidatorNum = val.Validator.Number
 		nodeInfo.AvailabilityScore = val.Validator.AvailabilityScore
-		nodeInfo.StakeAmount = val.Validator.Stake
+		nodeInfo.StakeAmount = amount.Amount(val.Validator.Stake)
 		nodeInfo.LastBondingHeight = val.Validator.LastBondingHeight
 		nodeInfo.LastSortitionHeight = val.Validator.LastSortitionHeight
 	} else {
@@ -288,23 +532,7 @@ func (pt *Phoenix) nodeInfoHandler(cmd command.Command, _ command.AppID, _ strin
 		nodeInfo.LastSortitionHeight = 0
 	}
 
-	var pip19Score string
-	if nodeInfo.AvailabilityScore >= 0.9 {
-		pip19Score = fmt.Sprintf("%v✅", nodeInfo.AvailabilityScore)
-	} else {
-		pip19Score = fmt.Sprintf("%v⚠️", nodeInfo.AvailabilityScore)
-	}
-
-	stakeAmountInNanoPAC := int64(nodeInfo.StakeAmount)
-	stakeAmount := amount.Amount(stakeAmountInNanoPAC)
-
-	// Format the stake amount for display.
-	formattedStakeAmount := stakeAmount.Format(amount.UnitPAC)
-
-	return cmd.SuccessfulResult("PeerID: %s\nIP Address: %s\nAgent: %s\n"+
-		"Moniker: %s\nCountry: %s\nCity: %s\nRegion Name: %s\nTimeZone: %s\n"+
-		"ISP: %s\n\nValidator Info🔍\nNumber: %v\nPIP-19 Score: %s\nStake: %v PAC's\n",
-		nodeInfo.PeerID, nodeInfo.IPAddress, nodeInfo.Agent, nodeInfo.Moniker, nodeInfo.Country,
-		nodeInfo.City, nodeInfo.RegionName, nodeInfo.TimeZone, nodeInfo.ISP, utils.FormatNumber(int64(nodeInfo.ValidatorNum)),
-		pip19Score, formattedStakeAmount)
+	return cmd.RenderResult(appID, "node_info", map[string]any{
+		"Node": nodeInfo,
+	})
 }
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// plainText strips the emoji and the decorative characters of all the outputs of the deployment.
+var plainText bool
+
+// SetPlainText sets the plain-text mode of the deployment, like for the screen readers or a strict corporate platform.
+func SetPlainText(plain bool) {
+	plainText = plain
+}
+
+// PlainText returns true if the outputs of the deployment are in plain text.
+func PlainText() bool {
+	return plainText
+}
+
+// decorations are the known symbols of the commands out of the pictographs, like the emoji of the settings.
+var decorations = []string{"⚙"}
+
+// plainReplacer replaces the icons of the emoji theme with their words in the ASCII theme and removes the decorations.
+var plainReplacer = newPlainReplacer()
+
+// newPlainReplacer returns the replacer of the icons, the longest first, so an icon with a variation selector
+// is replaced before its bare symbol, like "⬆️" before "⬆". The replacer tries the icons in this order.
+func newPlainReplacer() *strings.Replacer {
+	words := make(map[string]string)
+	for name, icon := range themes[ThemeEmoji].Icons {
+		words[icon] = themes[ThemeASCII].Icons[name]
+		words[strings.TrimSuffix(icon, "\ufe0f")] = themes[ThemeASCII].Icons[name]
+	}
+	for _, symbol := range decorations {
+		words[symbol] = ""
+	}
+
+	icons := make([]string, 0, len(words))
+	for icon := range words {
+		icons = append(icons, icon)
+	}
+	slices.SortFunc(icons, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a, b))
+	})
+
+	pairs := make([]string, 0, 2*len(icons))
+	for _, icon := range icons {
+		pairs = append(pairs, icon, words[icon])
+	}
+
+	return strings.NewReplacer(pairs...)
+}
+
+// ToPlainText replaces the icons of the emoji theme with their words in the ASCII theme, like "✅" with "[OK]",
+// and removes the other emoji and the known decorations, like the command emoji.
+func ToPlainText(msg string) string {
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		plain := strings.Map(func(r rune) rune {
+			if isDecoration(r) {
+				return -1
+			}
+
+			return r
+		}, plainReplacer.Replace(line))
+
+		if plain != line {
+			lines[i] = tidyLine(line, plain)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// isDecoration returns true for the emoji pictographs and their modifiers.
+// The other symbols are kept, like "≈", "✓" and the box drawings, only the known icons are replaced.
+func isDecoration(r rune) bool {
+	switch {
+	case r == '\u200d', r == '\u20e3', r >= '\ufe00' && r <= '\ufe0f':
+		// the joiners, the keycaps and the variation selectors of the emoji.
+		return true
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		// the pictographs, the flags and the skin tones.
+		return true
+	}
+
+	return false
+}
+
+// tidyLine removes the spaces that the removed characters leave in a line, the indentation of the line is kept.
+// The other lines are kept as they are, like the aligned columns of a table.
+func tidyLine(line, plain string) string {
+	indent := line[:len(line)-len(strings.TrimLeftFunc(line, unicode.IsSpace))]
+	content := strings.Fields(plain)
+	if len(content) == 0 {
+		return ""
+	}
+
+	return indent + strings.Join(content, " ")
+}
@@ -0,0 +1,33 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToPlainText(t *testing.T) {
+	assert.Equal(t, "Your linked addresses [OK]\n  pc1z...", ToPlainText("Your linked addresses✅\n  pc1z..."))
+	assert.Equal(t, "Network growth\n7 days: +10 (+10.00%) (up)", ToPlainText("Network growth📈\n7 days: +10 (+10.00%)⬆️"))
+	assert.Equal(t, "Settings", ToPlainText("⚙️ Settings"), "the emoji with a variation selector")
+	assert.Equal(t, "Team ready", ToPlainText("Team 👩🏽‍💻 ready 🇮🇷"), "the joiners, the skin tones and the flags")
+	assert.Equal(t, "Supply: 100 PAC (≈45.00 EUR)…", ToPlainText("Supply: 100 PAC (≈45.00 EUR)…"))
+	assert.Equal(t, "Name     Stake\nval-a    10 PAC", ToPlainText("Name     Stake\nval-a    10 PAC"),
+		"the lines without a decoration are kept")
+	assert.Equal(t, "سلام 世界, Grüße", ToPlainText("سلام 世界, Grüße"))
+	assert.Equal(t, "Uptime [WARN] (down)", ToPlainText("Uptime⚠⬇"), "the bare symbols of the icons")
+	assert.Equal(t, "Health: ++~x.", ToPlainText("Health: 🟩🟩🟨🟥⬜"))
+	assert.Equal(t, "Synced ✓ → ─── ●", ToPlainText("Synced ✓ → ─── ●"), "not the icons")
+}
+
+func TestPlainTextPolicy(t *testing.T) {
+	SetPlainText(true)
+	defer SetPlainText(false)
+
+	assert.True(t, PlainText())
+	assert.Equal(t, "Done [OK]", ApplyOutputPolicy("Done✅"))
+
+	msg, err := RenderTemplate(AppIdDiscord, "link_list", map[string]any{"Links": []map[string]any{{"Address": "pc1z"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Your linked addresses [OK]\n  pc1z", msg)
+}
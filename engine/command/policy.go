@@ -0,0 +1,106 @@
+package command
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// RemovedLink replaces the links to the hosts that are not allowed.
+const RemovedLink = "[link removed]"
+
+// massMentionPattern finds the mentions that ping a whole server or group.
+var massMentionPattern = regexp.MustCompile(`(?i)@(everyone|here)\b`)
+
+// urlPattern finds the links, the brackets are not part of them, like a Markdown link.
+var urlPattern = regexp.MustCompile("(?i)\\bhttps?://[^\\s<>()\\[\\]\"'`]+")
+
+// DefaultAllowedHosts are the hosts of Pactus, and the repositories of Pactus on GitHub, like the release notes.
+var DefaultAllowedHosts = []string{"pactus.org", "github.com/pactus-project/"}
+
+// allowedHost is a host that the outputs can link to, the path limits the links to it, like a GitHub organization.
+type allowedHost struct {
+	host string
+	path string
+}
+
+// allowedHosts are the hosts that the outputs can link to, besides the explorer.
+var allowedHosts = parseAllowedHosts(DefaultAllowedHosts)
+
+// SetAllowedHosts sets the hosts that the outputs can link to instead of the default ones, none keeps the defaults.
+// The subdomains of a host are allowed too, unless it has a path, like: "github.com/pactus-project/".
+func SetAllowedHosts(hosts []string) {
+	allowed := parseAllowedHosts(hosts)
+	if len(allowed) == 0 {
+		allowed = parseAllowedHosts(DefaultAllowedHosts)
+	}
+
+	allowedHosts = allowed
+}
+
+func parseAllowedHosts(hosts []string) []allowedHost {
+	allowed := make([]allowedHost, 0, len(hosts))
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" {
+			continue
+		}
+
+		host, path, scoped := strings.Cut(host, "/")
+		if scoped {
+			path = "/" + path
+		}
+		allowed = append(allowed, allowedHost{host: host, path: path})
+	}
+
+	return allowed
+}
+
+// ApplyOutputPolicy breaks the mass mentions and removes the links to the hosts that are not allowed,
+// so the values of the users in an output, like a moniker or a memo, can't ping a server or link to a scam.
+// The outputs are in plain text too, if the deployment is in the plain-text mode.
+func ApplyOutputPolicy(msg string) string {
+	msg = massMentionPattern.ReplaceAllString(msg, "@\u200b$1")
+
+	msg = urlPattern.ReplaceAllStringFunc(msg, func(link string) string {
+		if isAllowedLink(link) {
+			return link
+		}
+
+		return RemovedLink
+	})
+
+	if plainText {
+		msg = ToPlainText(msg)
+	}
+
+	return msg
+}
+
+func isAllowedLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if explorer, err := url.Parse(explorerURL); err == nil && host == strings.ToLower(explorer.Hostname()) {
+		return true
+	}
+
+	// the dot segments are resolved like the browsers do, so they don't leave the path of a host.
+	linkPath := strings.ToLower(path.Clean("/" + u.Path))
+	for _, allowed := range allowedHosts {
+		if allowed.path == "" && (host == allowed.host || strings.HasSuffix(host, "."+allowed.host)) {
+			return true
+		}
+
+		if allowed.path != "" && host == allowed.host &&
+			strings.HasPrefix(linkPath+"/", strings.TrimSuffix(allowed.path, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyOutputPolicy(t *testing.T) {
+	t.Cleanup(func() {
+		SetAllowedHosts(nil)
+	})
+
+	assert.Equal(t, "Moniker: @\u200beveryone and @\u200bHere, mail: me@example.com",
+		ApplyOutputPolicy("Moniker: @everyone and @Here, mail: me@example.com"))
+
+	msg := "Memo: [claim](https://evil.example/airdrop), see https://pacviewer.com/address/pc1z and " +
+		"https://docs.example.org/."
+	assert.Equal(t, "Memo: [claim]("+RemovedLink+"), see https://pacviewer.com/address/pc1z and "+
+		RemovedLink, ApplyOutputPolicy(msg))
+	assert.Equal(t, "https://github.com/pactus-project/pactus/releases",
+		ApplyOutputPolicy("https://github.com/pactus-project/pactus/releases"))
+	assert.Equal(t, "https://docs.pactus.org/", ApplyOutputPolicy("https://docs.pactus.org/"))
+	for _, link := range []string{
+		"https://github.com/attacker/pactus",
+		"https://github.com/pactus-project-airdrop/claim",
+		"https://github.com/pactus-project/../attacker",
+		"https://gist.github.com/pactus-project/1234",
+		"https://raw.github.com/pactus-project/pactus/main/README.md",
+	} {
+		assert.Equal(t, RemovedLink, ApplyOutputPolicy(link), link)
+	}
+
+	SetAllowedHosts([]string{" Example.org "})
+	assert.Equal(t, "Memo: [claim]("+RemovedLink+"), see https://pacviewer.com/address/pc1z and "+
+		"https://docs.example.org/.", ApplyOutputPolicy(msg), "the subdomains are allowed")
+	assert.Equal(t, RemovedLink, ApplyOutputPolicy("https://github.com/pactus-project/pactus"),
+		"the configured hosts replace the defaults")
+
+	SetAllowedHosts([]string{"pactus.org", "github.com/Pactus-Project"})
+	assert.Equal(t, "https://github.com/pactus-project", ApplyOutputPolicy("https://github.com/pactus-project"))
+	assert.Equal(t, RemovedLink, ApplyOutputPolicy("https://docs.example.org/"))
+}
@@ -0,0 +1,199 @@
+package preference
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/market"
+)
+
+const (
+	CommandName         = "settings"
+	CurrencyCommandName = "currency"
+	PlainCommandName    = "plain"
+	HelpCommandName     = "help"
+)
+
+const (
+	onWord  = "on"
+	offWord = "off" // Shows the amounts in PAC only again, or turns the plain text off.
+)
+
+// Preference keeps how the users want the outputs, like the fiat currency of the amounts.
+type Preference struct {
+	db   *database.DB
+	fiat *market.Fiat
+}
+
+func NewPreference(db *database.DB, fiat *market.Fiat) Preference {
+	return Preference{
+		db:   db,
+		fiat: fiat,
+	}
+}
+
+func (p *Preference) GetCommand() command.Command {
+	subCmdCurrency := command.Command{
+		Name: CurrencyCommandName,
+		Desc: "Show the amounts in a fiat currency too",
+		Help: "The PAC amounts of the outputs, like the supply, the stakes and the payouts, get their value " +
+			"in the currency with the last price. Turn it off with: settings currency off",
+		Args: []command.Args{
+			{
+				Name:     "currency",
+				Desc:     "Like: USD, EUR, IRR or off, empty shows your currency",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"EUR", offWord},
+		Mutating:    true,
+		Ephemeral:   true,
+		Handler:     p.currencyHandler,
+	}
+
+	subCmdPlain := command.Command{
+		Name: PlainCommandName,
+		Desc: "Show the outputs in plain text",
+		Help: "Removes the emoji and the decorative characters of your outputs, like for a screen reader. " +
+			"The statuses are in words, like: [OK]",
+		Args: []command.Args{
+			{
+				Name:     "mode",
+				Desc:     "on or off, empty shows your mode",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{onWord, offWord},
+		Mutating:    true,
+		Ephemeral:   true,
+		Handler:     p.plainHandler,
+	}
+
+	cmdSettings := command.Command{
+		Emoji:       "⚙️",
+		Name:        CommandName,
+		Desc:        "Your settings of the outputs",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdSettings.AddSubCommand(subCmdCurrency)
+	cmdSettings.AddSubCommand(subCmdPlain)
+
+	return cmdSettings
+}
+
+// WithFiat appends the fiat values of the amounts of the message in the currency of the user.
+// The message is kept as it is if the user has no currency or the price is not available.
+func (p *Preference) WithFiat(ctx context.Context, appID command.AppID, userID, msg string) string {
+	if p.db == nil || p.fiat == nil || !command.HasAmounts(msg) {
+		return msg
+	}
+
+	pref, err := p.db.GetUserPreference(int(appID), userID)
+	if err != nil || pref == nil || pref.Currency == "" {
+		return msg
+	}
+
+	fiat, err := p.fiat.Converter(ctx, pref.Currency)
+	if err != nil {
+		log.Ctx(ctx).Debug("can't convert the amounts", "err", err, "currency", pref.Currency)
+
+		return msg
+	}
+
+	return command.AppendFiat(msg, fiat)
+}
+
+// PlainText returns true if the user wants the outputs in plain text.
+func (p *Preference) PlainText(appID command.AppID, userID string) bool {
+	if p.db == nil {
+		return false
+	}
+
+	pref, err := p.db.GetUserPreference(int(appID), userID)
+
+	return err == nil && pref != nil && pref.Plain
+}
+
+func (p *Preference) currencyHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	available := strings.Join(p.fiat.Currencies(), ", ")
+	if len(args) == 0 {
+		pref, err := p.db.GetUserPreference(int(appID), callerID)
+		if err != nil {
+			return cmd.ErrorResult(err)
+		}
+
+		if pref == nil || pref.Currency == "" {
+			return cmd.SuccessfulResult("The amounts are shown in PAC only. Set a currency of %s, like: "+
+				"settings currency USD", available)
+		}
+
+		return cmd.SuccessfulResult("The amounts are shown in %s too. The currencies are: %s",
+			pref.Currency, available)
+	}
+
+	if strings.EqualFold(args[0], offWord) {
+		if err := p.db.SetUserCurrency(int(appID), callerID, ""); err != nil {
+			return cmd.ErrorResult(err)
+		}
+
+		return cmd.SuccessfulResult("Done, the amounts are shown in PAC only.")
+	}
+
+	currency, ok := p.fiat.Currency(args[0])
+	if !ok {
+		return cmd.FailedResult("The currency %s is not supported, the currencies are: %s",
+			args[0], available).WithCode(command.ErrCodeInvalidArgs)
+	}
+
+	if err := p.db.SetUserCurrency(int(appID), callerID, currency); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("Done, the amounts are shown in %s too.", currency)
+}
+
+func (p *Preference) plainHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	if len(args) == 0 {
+		if p.PlainText(appID, callerID) {
+			return cmd.SuccessfulResult("Your outputs are in plain text. Turn it off with: settings plain off")
+		}
+
+		return cmd.SuccessfulResult("Your outputs have the emoji. Turn the plain text on with: settings plain on")
+	}
+
+	var plain bool
+	switch strings.ToLower(args[0]) {
+	case onWord:
+		plain = true
+	case offWord:
+		plain = false
+	default:
+		return cmd.FailedResult("The mode should be on or off").WithCode(command.ErrCodeInvalidArgs)
+	}
+
+	if err := p.db.SetUserPlainText(int(appID), callerID, plain); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if plain {
+		return cmd.SuccessfulResult("Done, your outputs are in plain text.")
+	}
+
+	return cmd.SuccessfulResult("Done, your outputs have the emoji again.")
+}
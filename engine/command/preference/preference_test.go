@@ -0,0 +1,95 @@
+package preference
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/market"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedProvider float64
+
+func (p fixedProvider) Price(_ context.Context) (float64, error) {
+	return float64(p), nil
+}
+
+func TestCurrency(t *testing.T) {
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	tracker := market.NewTracker(fixedProvider(0.5), db, notify.NewHub(), time.Minute)
+	fiat, err := market.NewFiat(tracker, map[string]float64{market.CurrencyEUR: 0.9})
+	require.NoError(t, err)
+
+	p := NewPreference(db, fiat)
+	currency := p.GetCommand().SubCommands[0]
+	appID := command.AppIdDiscord
+	ctx := context.Background()
+
+	res := p.currencyHandler(context.Background(), currency, appID, "alice")
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "The amounts are shown in PAC only. Set a currency of EUR, USD")
+	assert.Equal(t, "Supply: 100 PAC", p.WithFiat(ctx, appID, "alice", "Supply: 100 PAC"))
+
+	res = p.currencyHandler(context.Background(), currency, appID, "alice", "IRR")
+	assert.False(t, res.Successful, "IRR has no rate")
+	assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+
+	res = p.currencyHandler(context.Background(), currency, appID, "alice", "eur")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "Done, the amounts are shown in EUR too.", res.Message)
+	assert.Equal(t, "Supply: 100 PAC (≈45.00 EUR)", p.WithFiat(ctx, appID, "alice", "Supply: 100 PAC"))
+	assert.Equal(t, "Supply: 100 PAC", p.WithFiat(ctx, command.AppIdTelegram, "alice", "Supply: 100 PAC"),
+		"the currency is per platform")
+
+	res = p.currencyHandler(context.Background(), currency, appID, "alice")
+	assert.Contains(t, res.Message, "The amounts are shown in EUR too")
+
+	res = p.currencyHandler(context.Background(), currency, appID, "alice", "off")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "Supply: 100 PAC", p.WithFiat(ctx, appID, "alice", "Supply: 100 PAC"))
+
+	assert.Equal(t, "Supply: 100 PAC", (&Preference{}).WithFiat(ctx, appID, "alice", "Supply: 100 PAC"))
+}
+
+func TestPlain(t *testing.T) {
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	p := NewPreference(db, nil)
+	plain := p.GetCommand().SubCommands[1]
+	appID := command.AppIdTelegram
+
+	res := p.plainHandler(context.Background(), plain, appID, "alice")
+	assert.Contains(t, res.Message, "Your outputs have the emoji")
+	assert.False(t, p.PlainText(appID, "alice"))
+
+	res = p.plainHandler(context.Background(), plain, appID, "alice", "maybe")
+	assert.False(t, res.Successful)
+	assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+
+	res = p.plainHandler(context.Background(), plain, appID, "alice", "ON")
+	require.True(t, res.Successful, res.Message)
+	assert.True(t, p.PlainText(appID, "alice"))
+	assert.False(t, p.PlainText(command.AppIdDiscord, "alice"), "the mode is per platform")
+
+	res = p.plainHandler(context.Background(), plain, appID, "alice")
+	assert.Contains(t, res.Message, "Your outputs are in plain text")
+
+	res = p.plainHandler(context.Background(), plain, appID, "alice", "off")
+	require.True(t, res.Successful, res.Message)
+	assert.False(t, p.PlainText(appID, "alice"))
+
+	assert.False(t, (&Preference{}).PlainText(appID, "alice"))
+}
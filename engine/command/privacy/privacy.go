@@ -0,0 +1,200 @@
+package privacy
+
+import (
+	"context"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+const (
+	WhoamiCommandName = "whoami"
+	ForgetCommandName = "forget"
+	MeCommandName     = "me"
+	HelpCommandName   = "help"
+)
+
+// confirmWord is the argument that confirms the removal of the data of the user.
+const confirmWord = "confirm"
+
+// Privacy shows the users what Pagu keeps about them, and removes it on their demand.
+type Privacy struct {
+	db *database.DB
+}
+
+func NewPrivacy(db *database.DB) Privacy {
+	return Privacy{
+		db: db,
+	}
+}
+
+// Data is what Pagu keeps about the user on the platform.
+type Data struct {
+	Links         []*database.AddressLink
+	Aliases       []*database.AddressAlias     // The public names that the user set for the linked addresses.
+	Digest        *database.DigestSubscription // Nil if the user is not subscribed.
+	Watched       []*database.WatchedValidator
+	Alerts        []*database.PriceAlert
+	Feedbacks     int64
+	Currency      string // The fiat currency of the amounts, empty if it's not set.
+	PlainText     bool
+	FaucetClaims  int
+	FaucetReviews int
+	ZealyReward   bool
+}
+
+func (p *Privacy) GetCommand() command.Command {
+	return command.Command{
+		Emoji: "🪪",
+		Name:  WhoamiCommandName,
+		Desc:  "Everything that Pagu keeps about you",
+		Help: "Shows your linked addresses, subscriptions, alerts, feedbacks, settings and payouts. " +
+			"Remove them with: forget me",
+		Args:      nil,
+		AppIDs:    command.AllAppIDs(),
+		Ephemeral: true,
+		Handler:   p.whoamiHandler,
+	}
+}
+
+// GetForgetCommand returns the command that removes the data of the user.
+func (p *Privacy) GetForgetCommand() command.Command {
+	subCmdMe := command.Command{
+		Name: MeCommandName,
+		Desc: "Remove the data that Pagu keeps about you",
+		Help: "Removes your linked addresses, subscriptions, alerts, feedbacks and settings. " +
+			"The payouts are kept for the faucet limits and the accounting of the rewards",
+		Args: []command.Args{
+			{
+				Name:     confirmWord,
+				Desc:     "Type confirm to remove your data",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{confirmWord},
+		Mutating:    true,
+		Ephemeral:   true,
+		Handler:     p.forgetMeHandler,
+	}
+
+	cmdForget := command.Command{
+		Emoji:       "🧹",
+		Name:        ForgetCommandName,
+		Desc:        "Remove your data from Pagu",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdForget.AddSubCommand(subCmdMe)
+
+	return cmdForget
+}
+
+// Collect returns what Pagu keeps about the user on the platform.
+// The payouts are not bound to a platform, the user ID is their key.
+func (p *Privacy) Collect(appID command.AppID, userID string) (*Data, error) {
+	links, err := p.db.GetAddressLinks(int(appID), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := p.db.GetUserAddressAliases(int(appID), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := p.db.GetDigestSubscription(int(appID), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	watched, err := p.db.GetWatchedValidators(int(appID), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := p.db.GetUserPriceAlerts(int(appID), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbacks, err := p.db.CountUserFeedbacks(int(appID), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pref, err := p.db.GetUserPreference(int(appID), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, plain := "", false
+	if pref != nil {
+		currency, plain = pref.Currency, pref.Plain
+	}
+
+	faucets, err := p.db.GetFaucetsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := p.db.GetFaucetReviewsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Data{
+		Links:         links,
+		Aliases:       aliases,
+		Digest:        digest,
+		Watched:       watched,
+		Alerts:        alerts,
+		Feedbacks:     feedbacks,
+		Currency:      currency,
+		PlainText:     plain,
+		FaucetClaims:  len(faucets),
+		FaucetReviews: len(reviews),
+		ZealyReward:   p.db.HasZealyUser(userID),
+	}, nil
+}
+
+func (p *Privacy) whoamiHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	_ ...string,
+) command.CommandResult {
+	data, err := p.Collect(appID, callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "privacy_whoami", map[string]any{
+		"UserID": callerID,
+		"Data":   data,
+	})
+}
+
+func (p *Privacy) forgetMeHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	if len(args) == 0 || args[0] != confirmWord {
+		return cmd.SuccessfulResult("This removes your linked addresses and their aliases, subscriptions, alerts, " +
+			"feedbacks and settings, it can't be undone. " +
+			"The payouts are kept for the faucet limits and the accounting of the rewards.\n" +
+			"To go on, send: forget me confirm")
+	}
+
+	removed, err := p.db.ForgetUser(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if removed == 0 {
+		return cmd.SuccessfulResult("Pagu keeps nothing about you to remove, your payouts are kept.")
+	}
+
+	return cmd.SuccessfulResult("Done, %d records about you are removed. Your payouts are kept.", removed)
+}
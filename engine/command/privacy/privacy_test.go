@@ -0,0 +1,91 @@
+package privacy
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivacy(t *testing.T) {
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	p := NewPrivacy(db)
+	whoami := p.GetCommand()
+	forgetMe := p.GetForgetCommand().SubCommands[0]
+	appID := command.AppIdCLI
+
+	res := p.whoamiHandler(context.Background(), whoami, appID, "alice")
+	require.True(t, res.Successful, res.Message)
+	assert.True(t, res.Ephemeral, "only the caller sees the data")
+	assert.Contains(t, res.Message, "Linked addresses🔒: none")
+
+	now := time.Now()
+	require.NoError(t, db.AddPendingAddressLink(&database.AddressLink{
+		AppID: int(appID), UserID: "alice", Address: "pc1zaddr", Nonce: "aa", ExpiresAt: now.Add(time.Hour),
+	}))
+	link, err := db.GetPendingAddressLink(int(appID), "alice")
+	require.NoError(t, err)
+	require.NoError(t, db.VerifyAddressLink(link.ID))
+	require.NoError(t, db.SetDigestSubscription(&database.DigestSubscription{
+		AppID: int(appID), UserID: "alice", Hour: 9, Timezone: "UTC",
+	}))
+	require.NoError(t, db.AddWatchedValidator(&database.WatchedValidator{
+		AppID: int(appID), UserID: "alice", Address: "pc1pval",
+	}))
+	require.NoError(t, db.AddPriceAlert(&database.PriceAlert{
+		AppID: int(appID), UserID: "alice", Direction: database.PriceAlertAbove, Price: 0.5,
+	}))
+	require.NoError(t, db.AddFeedback(&database.Feedback{AppID: int(appID), UserID: "alice", Text: "thanks"}))
+	require.NoError(t, db.SetUserCurrency(int(appID), "alice", "EUR"))
+	require.NoError(t, db.AddFeedback(&database.Feedback{AppID: int(appID), UserID: "bob", Text: "hi"}))
+	require.NoError(t, db.AddUser(&database.User{ID: "alice"}))
+	require.NoError(t, db.AddFaucet(&database.Faucet{Address: "tpc1zaddr", Amount: 5, UserID: "alice"}))
+
+	res = p.whoamiHandler(context.Background(), whoami, appID, "alice")
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "pc1zaddr")
+	assert.Contains(t, res.Message, "at 09:00 UTC")
+	assert.Contains(t, res.Message, "pc1pval")
+	assert.Contains(t, res.Message, "above 0.5000 USDT")
+	assert.Contains(t, res.Message, "Feedbacks: 1")
+	assert.Contains(t, res.Message, "Currency: EUR")
+	assert.Contains(t, res.Message, "Faucet claims: 1")
+
+	res = p.forgetMeHandler(context.Background(), forgetMe, appID, "alice")
+	assert.True(t, res.Successful)
+	assert.Contains(t, res.Message, "forget me confirm")
+
+	data, err := p.Collect(appID, "alice")
+	require.NoError(t, err)
+	assert.Len(t, data.Links, 1, "nothing is removed without the confirmation")
+
+	res = p.forgetMeHandler(context.Background(), forgetMe, appID, "alice", "confirm")
+	require.True(t, res.Successful, res.Message)
+	assert.Equal(t, "Done, 6 records about you are removed. Your payouts are kept.", res.Message)
+
+	data, err = p.Collect(appID, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, data.Links)
+	assert.Nil(t, data.Digest)
+	assert.Empty(t, data.Watched)
+	assert.Empty(t, data.Alerts)
+	assert.Zero(t, data.Feedbacks)
+	assert.Empty(t, data.Currency)
+	assert.Equal(t, 1, data.FaucetClaims, "the payouts are kept")
+
+	count, err := db.CountUserFeedbacks(int(appID), "bob")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "the data of the others is kept")
+
+	res = p.forgetMeHandler(context.Background(), forgetMe, appID, "alice", "confirm")
+	assert.Equal(t, "Pagu keeps nothing about you to remove, your payouts are kept.", res.Message)
+}
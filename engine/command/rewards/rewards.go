@@ -0,0 +1,175 @@
+package rewards
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+const (
+	CommandName        = "rewards"
+	HistoryCommandName = "history"
+	HelpCommandName    = "help"
+)
+
+// historyLimit is how many of the payouts are listed, the newest first.
+const historyLimit = 10
+
+const (
+	KindFaucet = "Phoenix faucet"
+	KindZealy  = "Zealy reward"
+)
+
+type Status string
+
+const (
+	StatusPaid      Status = "paid"
+	StatusPending   Status = "pending"      // The transaction is being sent.
+	StatusReview    Status = "under review" // A suspicious faucet claim that waits for an admin.
+	StatusRejected  Status = "rejected"
+	StatusUnclaimed Status = "unclaimed" // A Zealy reward that is not claimed yet.
+)
+
+// Payout is a faucet claim or a reward of the user.
+type Payout struct {
+	Kind    string
+	Amount  amount.Amount // Zero if it's not known yet, like the claims under review.
+	TxHash  string
+	Memo    string
+	Date    time.Time
+	Status  Status
+	Testnet bool // The transaction is on the Phoenix Testnet, so it's not linked to the explorer.
+}
+
+type Rewards struct {
+	db *database.DB
+}
+
+func NewRewards(db *database.DB) Rewards {
+	return Rewards{
+		db: db,
+	}
+}
+
+func (r *Rewards) GetCommand() command.Command {
+	subCmdHistory := command.Command{
+		Name:        HistoryCommandName,
+		Desc:        "Your past faucet claims and rewards",
+		Help:        "Shows the amount, the transaction, the date and the status of your last payouts",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Ephemeral:   true,
+		Handler:     r.historyHandler,
+	}
+
+	cmdRewards := command.Command{
+		Emoji:       "🎁",
+		Name:        CommandName,
+		Desc:        "Your payouts of the faucet and the rewards",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdRewards.AddSubCommand(subCmdHistory)
+
+	return cmdRewards
+}
+
+func (r *Rewards) historyHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	_ ...string,
+) command.CommandResult {
+	payouts, err := r.History(callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "rewards_history", map[string]any{
+		"Payouts": payouts,
+	})
+}
+
+// History returns the last payouts of the user from the faucet claims, the faucet reviews and the Zealy rewards,
+// the newest first.
+func (r *Rewards) History(userID string) ([]Payout, error) {
+	faucets, err := r.db.GetFaucetsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := r.db.GetFaucetReviewsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	payouts := make([]Payout, 0, len(faucets)+len(reviews)+1)
+	for _, f := range faucets {
+		status := StatusPaid
+		if f.TransactionHash == "" {
+			status = StatusPending
+		}
+
+		payouts = append(payouts, Payout{
+			Kind:    KindFaucet,
+			Amount:  amount.Amount(int64(f.Amount) * 1e9),
+			TxHash:  f.TransactionHash,
+			Memo:    f.Memo,
+			Date:    f.CreatedAt,
+			Status:  status,
+			Testnet: true,
+		})
+	}
+
+	// the approved reviews are paid, they are in the faucet claims.
+	for _, rv := range reviews {
+		status := StatusReview
+		switch rv.Status {
+		case database.FaucetReviewApproved:
+			continue
+		case database.FaucetReviewRejected:
+			status = StatusRejected
+		case database.FaucetReviewPending:
+		}
+
+		payouts = append(payouts, Payout{
+			Kind:    KindFaucet,
+			Date:    rv.CreatedAt,
+			Status:  status,
+			Testnet: true,
+		})
+	}
+
+	if r.db.HasZealyUser(userID) {
+		user, err := r.db.GetZealyUser(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		payout := Payout{
+			Kind:   KindZealy,
+			Amount: amount.Amount(user.Amount * 1e9),
+			Date:   user.CreatedAt,
+			Status: StatusUnclaimed,
+		}
+		if user.IsClaimed() {
+			payout.TxHash = user.TxHash
+			payout.Memo = user.Memo
+			payout.Date = user.UpdatedAt
+			payout.Status = StatusPaid
+		}
+		payouts = append(payouts, payout)
+	}
+
+	slices.SortStableFunc(payouts, func(a, b Payout) int {
+		return b.Date.Compare(a.Date)
+	})
+
+	return payouts[:min(len(payouts), historyLimit)], nil
+}
@@ -0,0 +1,80 @@
+package rewards
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setup(t *testing.T) (*Rewards, *database.DB, command.Command) {
+	t.Helper()
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	r := NewRewards(db)
+
+	return &r, db, r.GetCommand()
+}
+
+func TestHistory(t *testing.T) {
+	r, db, cmd := setup(t)
+	appID := command.AppIdCLI
+
+	res := r.historyHandler(context.Background(), cmd.SubCommands[0], appID, "alice")
+	assert.True(t, res.Successful)
+	assert.True(t, res.Ephemeral, "only the caller sees the history")
+	assert.Equal(t, "You have no payouts yet, claim the faucet or your Zealy reward first.", res.Message)
+
+	require.NoError(t, db.AddUser(&database.User{ID: "alice"}))
+	require.NoError(t, db.AddFaucet(&database.Faucet{
+		Address:         "tpc1zaddr1",
+		Amount:          5,
+		TransactionHash: "aa",
+		Memo:            "Pagu faucet 1",
+		UserID:          "alice",
+	}))
+	require.NoError(t, db.AddFaucetReview(&database.FaucetReview{
+		UserID:  "alice",
+		Address: "tpc1zaddr2",
+		Status:  database.FaucetReviewRejected,
+	}))
+	require.NoError(t, db.AddFaucetReview(&database.FaucetReview{
+		UserID:  "alice",
+		Address: "tpc1zaddr1",
+		Status:  database.FaucetReviewApproved,
+	}))
+	require.NoError(t, db.AddZealyUser(&database.ZealyUser{
+		Amount:    100,
+		DiscordID: "alice",
+	}))
+
+	payouts, err := r.History("alice")
+	require.NoError(t, err)
+	require.Len(t, payouts, 3, "the approved review is in the faucet claims")
+	assert.Equal(t, KindZealy, payouts[0].Kind)
+	assert.Equal(t, StatusUnclaimed, payouts[0].Status)
+	assert.Equal(t, StatusRejected, payouts[1].Status)
+	assert.Equal(t, StatusPaid, payouts[2].Status)
+	assert.Equal(t, "aa", payouts[2].TxHash)
+	assert.Equal(t, "Pagu faucet 1", payouts[2].Memo)
+
+	require.NoError(t, db.UpdateZealyUser("alice", "bb", "Pagu Zealy reward 1"))
+
+	res = r.historyHandler(context.Background(), cmd, appID, "alice")
+	assert.True(t, res.Successful)
+	assert.Contains(t, res.Message, "Zealy reward: 100 PAC (paid)✅")
+	assert.Contains(t, res.Message, "/transaction/bb\nMemo: Pagu Zealy reward 1")
+	assert.Contains(t, res.Message, "Transaction: aa\nMemo: Pagu faucet 1")
+
+	payouts, err = r.History("bob")
+	require.NoError(t, err)
+	assert.Empty(t, payouts)
+}
@@ -0,0 +1,206 @@
+package command
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ArgKind is the kind of the value of an argument, it sets how long the value can be and how it's checked.
+type ArgKind int
+
+const (
+	ArgText    ArgKind = iota // A word or a free text, like a moniker or a memo.
+	ArgHex                    // A hex encoded value, like a raw transaction.
+	ArgAddress                // An address, it's checked against the network of the command before the handler runs.
+	ArgList                   // The addresses or the numbers separated by the commas, like: "pc1p...,42".
+)
+
+const (
+	// MaxArgLength is the maximum length of a text argument in characters, the rest is cut.
+	// The longest words, like the memos and the transaction IDs, fit in it.
+	MaxArgLength = 256
+
+	// MaxHexArgLength is the maximum length of a hex argument, the raw transactions with a long memo fit in it.
+	MaxHexArgLength = 8 * 1024
+
+	// MaxListArgLength is the maximum length of a list argument, like the 50 validators of "validator bulk".
+	MaxListArgLength = 4 * 1024
+)
+
+// MaxLength returns the maximum length of the arguments of the kind in characters.
+func (kind ArgKind) MaxLength() int {
+	switch kind {
+	case ArgHex:
+		return MaxHexArgLength
+	case ArgList:
+		return MaxListArgLength
+	case ArgText, ArgAddress:
+	}
+
+	return MaxArgLength
+}
+
+// maxTokenLength is the length of the longest argument kind, the tokens are cut to it before the command is found.
+var maxTokenLength = max(ArgText.MaxLength(), ArgHex.MaxLength(), ArgList.MaxLength())
+
+// SplitList returns the items of the list arguments, they are separated by the spaces or the commas,
+// like: "pc1p...,pc1p... 42". Discord sends a list in one argument.
+func SplitList(args []string) []string {
+	items := make([]string, 0, len(args))
+	for _, arg := range args {
+		items = append(items, strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })...)
+	}
+
+	return items
+}
+
+// markdownEscaper escapes the Markdown of Discord, so an echoed argument is shown as it's typed.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`,
+	">", `\>`, "#", `\#`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+)
+
+// mentionEscaper breaks the mentions with a zero-width space, so an echoed argument doesn't ping anyone.
+var mentionEscaper = strings.NewReplacer("@", "@\u200b")
+
+// SanitizeArgs removes the control and the bidirectional characters of the arguments and cuts the ones that
+// are longer than any kind. The engine sanitizes the inputs of all the platforms before the commands run,
+// then it cuts each argument by its kind with CutArgs.
+func SanitizeArgs(args []string) []string {
+	sanitized := make([]string, 0, len(args))
+	for _, arg := range args {
+		sanitized = append(sanitized, sanitizeArg(arg, maxTokenLength))
+	}
+
+	return sanitized
+}
+
+// SanitizeArg sanitizes a text argument, like SanitizeArgs, and cuts it to MaxArgLength.
+func SanitizeArg(arg string) string {
+	return sanitizeArg(arg, MaxArgLength)
+}
+
+// CutArgs cuts the arguments to the maximum length of their kinds, the variadic argument takes the rest of them.
+func (cmd *Command) CutArgs(args []string) []string {
+	cut := make([]string, 0, len(args))
+	for i, arg := range args {
+		cut = append(cut, cutArg(arg, cmd.argKind(i).MaxLength()))
+	}
+
+	return cut
+}
+
+// argKind returns the kind of the argument in the position, the text is the default.
+func (cmd *Command) argKind(index int) ArgKind {
+	if len(cmd.Args) == 0 {
+		return ArgText
+	}
+
+	if index >= len(cmd.Args) {
+		last := cmd.Args[len(cmd.Args)-1]
+		if !last.Variadic {
+			return ArgText
+		}
+
+		return last.Kind
+	}
+
+	return cmd.Args[index].Kind
+}
+
+func cutArg(arg string, maxLength int) string {
+	if utf8.RuneCountInString(arg) <= maxLength {
+		return arg
+	}
+
+	return string([]rune(arg)[:maxLength])
+}
+
+func sanitizeArg(arg string, maxLength int) string {
+	var sb strings.Builder
+	length := 0
+	for _, r := range arg {
+		if unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) || r == unicode.ReplacementChar {
+			continue
+		}
+
+		if length == maxLength {
+			break
+		}
+
+		sb.WriteRune(r)
+		length++
+	}
+
+	return sb.String()
+}
+
+// WithEchoes returns a copy of the command that escapes the arguments in its results, in the markup of the platform.
+// The engine sets it before the handler runs, so the markup of the results is kept and only the echoed
+// arguments are escaped, like the address of "%s is not a validator address".
+func (cmd Command) WithEchoes(appID AppID, args []string) Command {
+	cmd.echoFormat = platformFormats[appID]
+	cmd.echoes = args
+
+	return cmd
+}
+
+// Echo escapes the argument in the markup of the platform that the command echoes to, like EscapeArg.
+func (cmd *Command) Echo(arg string) string {
+	return escapeArg(cmd.echoFormat, arg)
+}
+
+// EscapeArg escapes an argument to echo in a message of the platform, so it's shown as it's typed.
+// The mentions are broken on the platforms with a markup, the Markdown is escaped on Discord.
+func EscapeArg(appID AppID, arg string) string {
+	return escapeArg(platformFormats[appID], arg)
+}
+
+func escapeArg(format Format, arg string) string {
+	if format == "" {
+		return arg
+	}
+
+	if format == FormatMarkdown {
+		arg = markdownEscaper.Replace(arg)
+	}
+
+	return mentionEscaper.Replace(arg)
+}
+
+// echoArgs escapes the values of the message that are the arguments of the command, the others are kept.
+func (cmd *Command) echoArgs(values []any) []any {
+	if cmd.echoFormat == "" {
+		return values
+	}
+
+	echoed := make([]any, 0, len(values))
+	for _, value := range values {
+		if arg, ok := value.(string); ok && slices.Contains(cmd.echoes, arg) {
+			value = cmd.Echo(arg)
+		}
+		echoed = append(echoed, value)
+	}
+
+	return echoed
+}
+
+// echoData escapes the values of the template data that are the arguments of the command.
+func (cmd *Command) echoData(data any) any {
+	values, ok := data.(map[string]any)
+	if !ok || cmd.echoFormat == "" {
+		return data
+	}
+
+	echoed := make(map[string]any, len(values))
+	for key, value := range values {
+		if arg, ok := value.(string); ok && slices.Contains(cmd.echoes, arg) {
+			value = cmd.Echo(arg)
+		}
+		echoed[key] = value
+	}
+
+	return echoed
+}
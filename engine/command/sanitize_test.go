@@ -0,0 +1,117 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeArgs(t *testing.T) {
+	args := SanitizeArgs([]string{"pc1p\x00abc\n", "moni\u202eker\x1b[31m", strings.Repeat("a", MaxHexArgLength+10)})
+
+	assert.Equal(t, "pc1pabc", args[0])
+	assert.Equal(t, "moniker[31m", args[1])
+	assert.Len(t, args[2], MaxHexArgLength)
+	assert.Len(t, SanitizeArg(args[2]), MaxArgLength)
+}
+
+func TestCutArgs(t *testing.T) {
+	cmd := Command{Args: []Args{{Name: "raw_tx", Kind: ArgHex}, {Name: "memo", Variadic: true}}}
+	rawTx := strings.Repeat("ab", MaxArgLength)
+	memo := strings.Repeat("a", MaxArgLength+10)
+
+	args := cmd.CutArgs([]string{rawTx, memo, memo})
+	assert.Equal(t, rawTx, args[0], "the hex arguments are longer")
+	assert.Len(t, args[1], MaxArgLength)
+	assert.Len(t, args[2], MaxArgLength, "the variadic argument takes the rest")
+
+	assert.Len(t, (&Command{}).CutArgs([]string{rawTx})[0], MaxArgLength, "the extra arguments are text")
+
+	list := strings.Repeat("pc1p...,", 50)
+	bulk := Command{Args: []Args{{Name: "validators", Kind: ArgList}}}
+	assert.Equal(t, list, bulk.CutArgs([]string{list})[0])
+}
+
+func TestSplitList(t *testing.T) {
+	assert.Equal(t, []string{"pc1p1", "pc1p2", "42", "pc1p3"}, SplitList([]string{"pc1p1,pc1p2, 42", ",pc1p3,"}))
+	assert.Empty(t, SplitList([]string{" , "}))
+}
+
+func TestEchoes(t *testing.T) {
+	args := []string{"@everyone", "[claim](https://evil.example)", "**"}
+
+	cmd := Command{}.WithEchoes(AppIdCLI, args)
+	assert.Equal(t, "**Error**: @everyone is not a validator address",
+		cmd.FailedResult("**Error**: %s is not a validator address", args[0]).Message, "no markup on the CLI")
+
+	cmd = Command{}.WithEchoes(AppIdDiscord, args)
+	assert.Equal(t, "**Error**: @\u200beveryone \\[claim\\]\\(https://evil.example\\) is not a validator address",
+		cmd.FailedResult("**Error**: %s %s is not a validator address", args[0], args[1]).Message)
+	assert.Equal(t, "**Error**: \\*\\* is not a validator address",
+		cmd.FailedResult("**Error**: %s is not a validator address", args[2]).Message,
+		"the markup of the message is kept")
+
+	cmd = Command{}.WithEchoes(AppIdTelegram, args)
+	assert.Equal(t, "@\u200beveryone [claim](https://evil.example)",
+		cmd.SuccessfulResult("%s %s", args[0], args[1]).Message, "the HTML is escaped by the links")
+	assert.Equal(t, map[string]any{"Moniker": "@\u200beveryone", "Count": 1},
+		cmd.echoData(map[string]any{"Moniker": args[0], "Count": 1}), "the arguments of the templates")
+}
+
+func FuzzSanitizeArg(f *testing.F) {
+	seeds := []string{"pc1p\x00abc\n", "moni\u202eker\x1b[31m", "\xff\xfe", "@everyone", strings.Repeat("ä", 300)}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, arg string) {
+		sanitized := SanitizeArg(arg)
+		if !utf8.ValidString(sanitized) {
+			t.Fatalf("%q is not valid UTF-8", sanitized)
+		}
+
+		if utf8.RuneCountInString(sanitized) > MaxArgLength {
+			t.Fatalf("%q is longer than %d characters", sanitized, MaxArgLength)
+		}
+
+		for _, r := range sanitized {
+			if unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) {
+				t.Fatalf("%q has the control character %U", sanitized, r)
+			}
+		}
+
+		if again := SanitizeArg(sanitized); again != sanitized {
+			t.Fatalf("sanitizing %q again changes it to %q", sanitized, again)
+		}
+
+		for _, appID := range AllAppIDs() {
+			cmd := Command{}.WithEchoes(appID, []string{sanitized})
+			cmd.FailedResult("The argument is %s", sanitized)
+		}
+	})
+}
+
+func FuzzCheckArgs(f *testing.F) {
+	f.Add("pc1p... Pactus Lovers", 2, true)
+	f.Add("12", 1, false)
+	f.Add("", 0, false)
+	f.Add("  a\tb\nc  ", 3, true)
+
+	f.Fuzz(func(t *testing.T, input string, count int, variadic bool) {
+		count = min(max(count, 0), 8)
+		cmd := Command{Name: "fuzz", Args: make([]Args, count)}
+		for i := range cmd.Args {
+			cmd.Args[i] = Args{Name: "arg", Optional: i%2 == 1, Variadic: variadic && i == count-1}
+		}
+
+		args := SanitizeArgs(strings.Fields(input))
+		if err := cmd.CheckArgs(args); err == nil && !variadic && len(args) > count {
+			t.Fatalf("%d arguments are accepted for %d", len(args), count)
+		}
+
+		_ = cmd.CheckAddresses(args)
+	})
+}
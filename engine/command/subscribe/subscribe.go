@@ -0,0 +1,254 @@
+package subscribe
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+const (
+	CommandName             = "subscribe"
+	DigestCommandName       = "digest"
+	CancelDigestCommandName = "cancel-digest"
+	WatchCommandName        = "watch"
+	UnwatchCommandName      = "unwatch"
+	ListCommandName         = "list"
+	HelpCommandName         = "help"
+)
+
+const defaultTimezone = "UTC"
+
+type Subscribe struct {
+	db         *database.DB
+	maxWatched int64
+}
+
+func NewSubscribe(db *database.DB, maxWatched int64) Subscribe {
+	return Subscribe{
+		db:         db,
+		maxWatched: maxWatched,
+	}
+}
+
+func (s *Subscribe) GetCommand() command.Command {
+	// the subscriptions are delivered by direct messages, so they are only on the chat platforms.
+	appIDs := []command.AppID{command.AppIdDiscord, command.AppIdTelegram}
+
+	subCmdDigest := command.Command{
+		Name: DigestCommandName,
+		Desc: "Get a daily digest of the network",
+		Help: "The digest has the block height and validators changes, the PAC price and your watched validators. " +
+			"It's sent as a direct message at the hour of your timezone, subscribe again to change the hour",
+		Args: []command.Args{
+			{
+				Name:     "hour",
+				Desc:     "Hour of the day, from 0 to 23",
+				Optional: false,
+			},
+			{
+				Name:     "timezone",
+				Desc:     "Your timezone, UTC by default [example: Europe/Berlin]",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      appIDs,
+		Examples:    []string{"8", "20 Asia/Tokyo"},
+		Mutating:    true,
+		Handler:     s.digestHandler,
+	}
+
+	subCmdCancelDigest := command.Command{
+		Name:        CancelDigestCommandName,
+		Desc:        "Stop the daily digest",
+		Help:        "",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      appIDs,
+		Mutating:    true,
+		Handler:     s.cancelDigestHandler,
+	}
+
+	subCmdWatch := command.Command{
+		Name: WatchCommandName,
+		Desc: "Watch a validator",
+		Help: "The scores of the watched validators are in your daily digest",
+		Args: []command.Args{
+			{
+				Name:     "validator",
+				Desc:     "Validator address [example: pc1p...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      appIDs,
+		Examples:    []string{"pc1p..."},
+		Mutating:    true,
+		Handler:     s.watchHandler,
+	}
+
+	subCmdUnwatch := command.Command{
+		Name: UnwatchCommandName,
+		Desc: "Stop watching a validator",
+		Help: "",
+		Args: []command.Args{
+			{
+				Name:     "validator",
+				Desc:     "Validator address [example: pc1p...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      appIDs,
+		Examples:    []string{"pc1p..."},
+		Mutating:    true,
+		Handler:     s.unwatchHandler,
+	}
+
+	subCmdList := command.Command{
+		Name:        ListCommandName,
+		Desc:        "Your subscriptions",
+		Help:        "Shows your daily digest and your watched validators",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      appIDs,
+		Ephemeral:   true,
+		Handler:     s.listHandler,
+	}
+
+	cmdSubscribe := command.Command{
+		Emoji:       "🔔",
+		Name:        CommandName,
+		Desc:        "Subscriptions to direct messages",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      appIDs,
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdSubscribe.AddSubCommand(subCmdDigest)
+	cmdSubscribe.AddSubCommand(subCmdCancelDigest)
+	cmdSubscribe.AddSubCommand(subCmdWatch)
+	cmdSubscribe.AddSubCommand(subCmdUnwatch)
+	cmdSubscribe.AddSubCommand(subCmdList)
+
+	return cmdSubscribe
+}
+
+func (s *Subscribe) digestHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	hour, err := strconv.Atoi(args[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return cmd.FailedResult("hour should be a number from 0 to 23")
+	}
+
+	timezone := defaultTimezone
+	if len(args) > 1 {
+		timezone = args[1]
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return cmd.FailedResult("%s is not a timezone, like: Europe/Berlin", timezone)
+	}
+
+	if err := s.db.SetDigestSubscription(&database.DigestSubscription{
+		AppID:    int(appID),
+		UserID:   callerID,
+		Hour:     hour,
+		Timezone: loc.String(),
+	}); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("You will get the daily digest at %02d:00 %s", hour, loc)
+}
+
+func (s *Subscribe) cancelDigestHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	_ ...string,
+) command.CommandResult {
+	removed, err := s.db.DeleteDigestSubscription(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !removed {
+		return cmd.FailedResult("You are not subscribed to the daily digest")
+	}
+
+	return cmd.SuccessfulResult("The daily digest is stopped")
+}
+
+func (s *Subscribe) watchHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	addr, err := crypto.AddressFromString(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !addr.IsValidatorAddress() {
+		return cmd.FailedResult("%s is not a validator address", args[0])
+	}
+
+	watched, err := s.db.GetWatchedValidators(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if int64(len(watched)) >= s.maxWatched {
+		return cmd.FailedResult("You watch %d validators, please unwatch one with `subscribe unwatch` first", len(watched))
+	}
+
+	if err := s.db.AddWatchedValidator(&database.WatchedValidator{
+		AppID:   int(appID),
+		UserID:  callerID,
+		Address: addr.String(),
+	}); err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.SuccessfulResult("You are watching %s", addr.String())
+}
+
+func (s *Subscribe) unwatchHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	removed, err := s.db.DeleteWatchedValidator(int(appID), callerID, args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !removed {
+		return cmd.FailedResult("You are not watching %s", args[0])
+	}
+
+	return cmd.SuccessfulResult("You stopped watching %s", args[0])
+}
+
+func (s *Subscribe) listHandler(_ context.Context, cmd command.Command, appID command.AppID, callerID string,
+	_ ...string,
+) command.CommandResult {
+	digest, err := s.db.GetDigestSubscription(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	watched, err := s.db.GetWatchedValidators(int(appID), callerID)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "subscribe_list", map[string]any{
+		"Digest":  digest,
+		"Watched": watched,
+	})
+}
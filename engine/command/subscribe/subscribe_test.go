@@ -0,0 +1,79 @@
+package subscribe
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pactus-project/pactus/util/testsuite"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setup(t *testing.T) (*Subscribe, command.Command) {
+	t.Helper()
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	s := NewSubscribe(db, 2)
+
+	return &s, s.GetCommand()
+}
+
+func TestDigest(t *testing.T) {
+	s, cmd := setup(t)
+	appID := command.AppIdTelegram
+
+	res := s.digestHandler(context.Background(), cmd, appID, "alice", "24")
+	assert.False(t, res.Successful)
+
+	res = s.digestHandler(context.Background(), cmd, appID, "alice", "8", "Mars/Olympus")
+	assert.False(t, res.Successful)
+
+	res = s.digestHandler(context.Background(), cmd, appID, "alice", "8", "Europe/Berlin")
+	assert.True(t, res.Successful)
+	assert.Equal(t, "You will get the daily digest at 08:00 Europe/Berlin", res.Message)
+
+	res = s.listHandler(context.Background(), cmd, appID, "alice")
+	assert.Equal(t, "Daily digest🔔: at 08:00 Europe/Berlin\n\nYou are not watching any validator.", res.Message)
+
+	res = s.cancelDigestHandler(context.Background(), cmd, appID, "alice")
+	assert.True(t, res.Successful)
+
+	res = s.cancelDigestHandler(context.Background(), cmd, appID, "alice")
+	assert.False(t, res.Successful)
+}
+
+func TestWatch(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	s, cmd := setup(t)
+	appID := command.AppIdDiscord
+	val1 := ts.RandValAddress().String()
+	val2 := ts.RandValAddress().String()
+
+	res := s.watchHandler(context.Background(), cmd, appID, "alice", ts.RandAccAddress().String())
+	assert.False(t, res.Successful, "not a validator address")
+
+	res = s.watchHandler(context.Background(), cmd, appID, "alice", val1)
+	assert.True(t, res.Successful)
+	res = s.watchHandler(context.Background(), cmd, appID, "alice", val2)
+	assert.True(t, res.Successful)
+
+	res = s.watchHandler(context.Background(), cmd, appID, "alice", ts.RandValAddress().String())
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "You watch 2 validators")
+
+	res = s.listHandler(context.Background(), cmd, appID, "alice")
+	assert.Equal(t, "You are not subscribed to the daily digest.\n\nWatched validators:\n  "+val1+"\n  "+val2, res.Message)
+
+	res = s.unwatchHandler(context.Background(), cmd, appID, "alice", val1)
+	assert.True(t, res.Successful)
+
+	res = s.unwatchHandler(context.Background(), cmd, appID, "bob", val2)
+	assert.False(t, res.Successful, "validator of another user")
+}
@@ -0,0 +1,230 @@
+package command
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/i18n"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/utils"
+)
+
+const templateExt = ".tmpl"
+
+//go:embed templates/*.tmpl
+var defaultTemplatesFS embed.FS
+
+var amountFormat = utils.DefaultAmountFormat
+
+var (
+	defaultTemplates = template.Must(template.New("").Funcs(templateFuncs()).ParseFS(defaultTemplatesFS, "templates/*.tmpl"))
+	customTemplates  = map[string]*template.Template{}
+)
+
+func templateFuncs() template.FuncMap {
+	return themeFuncs(defaultTheme)
+}
+
+func themeFuncs(theme Theme) template.FuncMap {
+	return template.FuncMap{
+		"number":    formatNumber,
+		"amount":    formatAmount,
+		"bytes":     utils.FormatBytes,
+		"icon":      theme.Icon,
+		"separator": func() string { return theme.Separator },
+		"explorer":  ExplorerURL,
+	}
+}
+
+// LoadTemplates loads the custom templates from the given directory.
+// Each template file overrides the default template with the same name, like: network_status.tmpl.
+// Invalid templates are skipped, so the embedded default is used for them.
+func LoadTemplates(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string]*template.Template, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			log.Error("can't read template file", "err", err, "path", path)
+
+			continue
+		}
+
+		name := filepath.Base(path)
+		tmpl, err := template.New(name).Funcs(templateFuncs()).Parse(string(content))
+		if err != nil {
+			log.Error("can't parse template file, using the default one", "err", err, "path", path)
+
+			continue
+		}
+
+		loaded[name] = tmpl
+		log.Info("custom template loaded", "name", name)
+	}
+
+	customTemplates = loaded
+
+	return nil
+}
+
+// RenderTemplate executes the template with given name (without extension) on data,
+// using the theme of the platform, and applies the output policy to it.
+// The custom template is preferred and the embedded default is used as fallback.
+func RenderTemplate(appID AppID, name string, data any) (string, error) {
+	out, err := renderTemplate(appID, name, data)
+	if err != nil {
+		return "", err
+	}
+
+	return ApplyOutputPolicy(out), nil
+}
+
+// RenderLocalizedTemplate executes the template of the language, like "tr", or the default template
+// if the language has none. The custom template of the language is like: release_announcement.tr.tmpl.
+func RenderLocalizedTemplate(appID AppID, locale, name string, data any) (string, error) {
+	if locale == "" {
+		return RenderTemplate(appID, name, data)
+	}
+
+	out, err := renderLocalizedTemplate(appID, locale, name, data)
+	if err != nil {
+		log.Error("can't execute the localized template, using the default one",
+			"err", err, "name", name, "locale", locale)
+
+		return RenderTemplate(appID, name, data)
+	}
+
+	if out == "" {
+		return RenderTemplate(appID, name, data)
+	}
+
+	return ApplyOutputPolicy(out), nil
+}
+
+// renderLocalizedTemplate returns an empty output if the language has no template.
+func renderLocalizedTemplate(appID AppID, locale, name string, data any) (string, error) {
+	theme := ThemeOf(appID)
+	if tmpl, ok := customTemplates[name+"."+locale+templateExt]; ok {
+		return execute(tmpl, theme, data)
+	}
+
+	content, ok := i18n.Template(locale, name)
+	if !ok {
+		return "", nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs()).Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	return execute(tmpl, theme, data)
+}
+
+func renderTemplate(appID AppID, name string, data any) (string, error) {
+	fileName := name + templateExt
+	theme := ThemeOf(appID)
+
+	if tmpl, ok := customTemplates[fileName]; ok {
+		out, err := execute(tmpl, theme, data)
+		if err == nil {
+			return out, nil
+		}
+
+		log.Error("can't execute custom template, using the default one", "err", err, "name", fileName)
+	}
+
+	tmpl := defaultTemplates.Lookup(fileName)
+	if tmpl == nil {
+		return "", fmt.Errorf("template %s not found", name)
+	}
+
+	return execute(tmpl, theme, data)
+}
+
+// RenderResult renders the named template for the platform and returns it as a successful result.
+// The arguments of the command in the data are escaped, see WithEchoes.
+func (cmd *Command) RenderResult(appID AppID, name string, data any) CommandResult {
+	msg, err := RenderTemplate(appID, name, cmd.echoData(data))
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	res := cmd.SuccessfulResult("%s", msg)
+	if !ThemeOf(appID).CommandEmoji {
+		res.Title = cmd.Desc
+	}
+
+	return res
+}
+
+func execute(tmpl *template.Template, theme Theme, data any) (string, error) {
+	themed, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := themed.Funcs(themeFuncs(theme)).Execute(buf, data); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// SetAmountFormat sets the precision and the unit of the amounts in the outputs.
+func SetAmountFormat(f utils.AmountFormat) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	amountFormat = f
+
+	return nil
+}
+
+// FormatAmount returns the amount in the format of the outputs, like: "1,234.56 PAC".
+func FormatAmount(amt amount.Amount) string {
+	return amountFormat.Format(amt)
+}
+
+// formatAmount formats the amounts of the templates, the integers are in NanoPAC.
+func formatAmount(v any) string {
+	switch n := v.(type) {
+	case amount.Amount:
+		return FormatAmount(n)
+	case int64:
+		return FormatAmount(amount.Amount(n))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatNumber(v any) string {
+	switch n := v.(type) {
+	case int:
+		return utils.FormatNumber(int64(n))
+	case int32:
+		return utils.FormatNumber(int64(n))
+	case int64:
+		return utils.FormatNumber(n)
+	case uint32:
+		return utils.FormatNumber(int64(n))
+	case uint64:
+		return utils.FormatNumber(int64(n))
+	case float64:
+		return utils.FormatNumber(int64(n))
+	default:
+		return fmt.Sprint(v)
+	}
+}
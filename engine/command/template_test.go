@@ -0,0 +1,144 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	t.Cleanup(func() {
+		customTemplates = map[string]*template.Template{}
+		require.NoError(t, SetThemes(ThemeEmoji, nil))
+	})
+
+	t.Run("default template", func(t *testing.T) {
+		msg, err := RenderTemplate(AppIdCLI, "zealy_import_winners", map[string]any{"TotalInserted": 1234})
+		assert.NoError(t, err)
+		assert.Equal(t, "Imported successfully\nTotal inserted: 1234", msg)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		_, err := RenderTemplate(AppIdCLI, "not_exist", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("custom template overrides default", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "zealy_import_winners.tmpl", "Done: {{number .TotalInserted}}")
+		require.NoError(t, LoadTemplates(dir))
+
+		msg, err := RenderTemplate(AppIdCLI, "zealy_import_winners", map[string]any{"TotalInserted": 1234})
+		assert.NoError(t, err)
+		assert.Equal(t, "Done: 1,234", msg)
+	})
+
+	t.Run("invalid custom template falls back to default", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "zealy_import_winners.tmpl", "Done: {{.TotalInserted")
+		require.NoError(t, LoadTemplates(dir))
+
+		msg, err := RenderTemplate(AppIdCLI, "zealy_import_winners", map[string]any{"TotalInserted": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "Imported successfully\nTotal inserted: 1", msg)
+	})
+
+	t.Run("failing custom template falls back to default", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "zealy_import_winners.tmpl", "Done: {{.TotalInserted.Missing}}")
+		require.NoError(t, LoadTemplates(dir))
+
+		msg, err := RenderTemplate(AppIdCLI, "zealy_import_winners", map[string]any{"TotalInserted": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "Imported successfully\nTotal inserted: 1", msg)
+	})
+}
+
+func TestRenderLocalizedTemplate(t *testing.T) {
+	t.Cleanup(func() {
+		customTemplates = map[string]*template.Template{}
+	})
+
+	data := map[string]any{"Kind": "ended"}
+	msg, err := RenderLocalizedTemplate(AppIdCLI, "tr", "maintenance_alert", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Bakım bitti✅: Pagu geri döndü, komutlar ve görevler devam ediyor.", msg)
+
+	msg, err = RenderLocalizedTemplate(AppIdCLI, "fa", "release_announcement", map[string]any{"Version": "v1.2.0"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(msg, "نسخه جدید نود پکتوس v1.2.0"))
+
+	msg, err = RenderLocalizedTemplate(AppIdCLI, "tr", "zealy_import_winners", map[string]any{"TotalInserted": 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Imported successfully\nTotal inserted: 1", msg, "the default template if there is no translation")
+
+	dir := t.TempDir()
+	writeTemplate(t, dir, "maintenance_alert.tr.tmpl", "Bitti")
+	require.NoError(t, LoadTemplates(dir))
+
+	msg, err = RenderLocalizedTemplate(AppIdCLI, "tr", "maintenance_alert", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Bitti", msg, "the custom translation overrides the embedded one")
+}
+
+func TestThemes(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, SetThemes(ThemeEmoji, nil))
+	})
+
+	require.NoError(t, SetThemes(ThemeEmoji, []string{"http:ascii"}))
+
+	data := map[string]any{
+		"Healthy":         true,
+		"CurrentTime":     "now",
+		"LastBlockTime":   "then",
+		"TimeDiff":        1,
+		"LastBlockHeight": 1000,
+	}
+
+	msg, err := RenderTemplate(AppIdDiscord, "network_health", data)
+	assert.NoError(t, err)
+	assert.Contains(t, msg, "Network is Healthy✅")
+
+	msg, err = RenderTemplate(AppIdHTTP, "network_health", data)
+	assert.NoError(t, err)
+	assert.Contains(t, msg, "Network is Healthy [OK]")
+
+	assert.Error(t, SetThemes("neon", nil))
+	assert.Error(t, SetThemes(ThemeEmoji, []string{"IRC:ascii"}))
+	assert.Error(t, SetThemes(ThemeEmoji, []string{"HTTP"}))
+}
+
+func TestDeprecation(t *testing.T) {
+	now := time.Now()
+	cmd := Command{Name: "old", Deprecated: true, ReplacedBy: "network status"}
+	assert.False(t, cmd.IsSunset(now), "deprecated without sunset time")
+
+	cmd.SunsetAt = now.Add(time.Hour)
+	assert.False(t, cmd.IsSunset(now))
+	assert.True(t, cmd.IsSunset(now.Add(2*time.Hour)))
+
+	parent := Command{Name: "network", Help: "Network commands"}
+	parent.AddSubCommand(Command{Name: "status", Desc: "Network status"})
+	parent.AddSubCommand(Command{Name: "old", Desc: "Old status", Deprecated: true, SunsetAt: now.Add(-time.Hour)})
+	help := parent.HelpMessage()
+	assert.Contains(t, help, "status")
+	assert.NotContains(t, help, "Old status")
+
+	msg, err := RenderTemplate(AppIdCLI, "command_deprecated", map[string]any{"Name": "old", "ReplacedBy": "network status"})
+	assert.NoError(t, err)
+	assert.Equal(t, "> Note⚠️: `old` is deprecated and will be removed, use `network status` instead.", msg)
+}
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+	require.NoError(t, err)
+}
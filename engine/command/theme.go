@@ -0,0 +1,129 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ThemeEmoji = "emoji"
+	ThemeASCII = "ascii"
+)
+
+// Theme is the set of symbols that templates use to decorate the command outputs.
+type Theme struct {
+	Name         string
+	Separator    string
+	CommandEmoji bool // Show the emoji of the command in the result title.
+	Icons        map[string]string
+}
+
+var (
+	themes = map[string]Theme{
+		ThemeEmoji: {
+			Name:         ThemeEmoji,
+			Separator:    "",
+			CommandEmoji: true,
+			Icons: map[string]string{
+				"check":  "✅",
+				"cross":  "❌",
+				"warn":   "⚠️",
+				"note":   "📝",
+				"search": "🔍",
+				"lock":   "🔒",
+				"clock":  "⏰",
+				"power":  "⚡",
+				"camera": "📷",
+				"bell":   "🔔",
+				"chart":  "📈",
+				"up":     "⬆️",
+				"down":   "⬇️",
+
+				// the marks of the hours of the health timeline.
+				"healthy":  "🟩",
+				"degraded": "🟨",
+				"outage":   "🟥",
+				"unknown":  "⬜",
+			},
+		},
+		ThemeASCII: {
+			Name:         ThemeASCII,
+			Separator:    "----------",
+			CommandEmoji: false,
+			Icons: map[string]string{
+				"check":  " [OK]",
+				"cross":  " [FAIL]",
+				"warn":   " [WARN]",
+				"note":   "",
+				"search": "",
+				"lock":   "",
+				"clock":  "",
+				"power":  "",
+				"camera": "",
+				"bell":   "",
+				"chart":  "",
+				"up":     " (up)",
+				"down":   " (down)",
+
+				"healthy":  "+",
+				"degraded": "~",
+				"outage":   "x",
+				"unknown":  ".",
+			},
+		},
+	}
+
+	defaultTheme   = themes[ThemeEmoji]
+	platformThemes = map[AppID]Theme{}
+)
+
+// SetThemes sets the deployment theme and the per platform overrides.
+// Overrides are in "Platform:theme" format, like: "HTTP:ascii".
+func SetThemes(name string, overrides []string) error {
+	theme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown theme: %s", name)
+	}
+
+	selected := make(map[AppID]Theme)
+	for _, override := range overrides {
+		if override == "" {
+			continue
+		}
+
+		platform, themeName, found := strings.Cut(override, ":")
+		if !found {
+			return fmt.Errorf("invalid theme override: %s", override)
+		}
+
+		appID, ok := ParseAppID(platform)
+		if !ok {
+			return fmt.Errorf("unknown platform in theme override: %s", platform)
+		}
+
+		platformTheme, ok := themes[strings.ToLower(themeName)]
+		if !ok {
+			return fmt.Errorf("unknown theme: %s", themeName)
+		}
+
+		selected[appID] = platformTheme
+	}
+
+	defaultTheme = theme
+	platformThemes = selected
+
+	return nil
+}
+
+// ThemeOf returns the theme used to render the outputs on the given platform.
+func ThemeOf(appID AppID) Theme {
+	if theme, ok := platformThemes[appID]; ok {
+		return theme
+	}
+
+	return defaultTheme
+}
+
+func (t Theme) Icon(name string) string {
+	return t.Icons[name]
+}
@@ -0,0 +1,436 @@
+package transaction
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/crypto/bls"
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pactus-project/pactus/types/param"
+	"github.com/pactus-project/pactus/types/tx"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/utils"
+)
+
+const (
+	CommandName         = "tx"
+	BuildCommandName    = "build"
+	BondCommandName     = "bond"
+	TransferCommandName = "transfer"
+	QRCommandName       = "qr"
+	DecodeCommandName   = "decode"
+	HelpCommandName     = "help"
+)
+
+type Transaction struct {
+	clientMgr *client.Mgr
+}
+
+func NewTransaction(
+	clientMgr *client.Mgr,
+) Transaction {
+	return Transaction{
+		clientMgr: clientMgr,
+	}
+}
+
+func (t *Transaction) GetCommand() command.Command {
+	subCmdBuildBond := command.Command{
+		Name: BondCommandName,
+		Desc: "Build an unsigned bond transaction to sign offline",
+		Help: "Provide the validator address, the stake amount in PAC and your account address. " +
+			"The public key is only required when the validator is bonded for the first time, " +
+			"Pagu checks the chain and asks for it",
+		Args: []command.Args{
+			{
+				Name:     "validator",
+				Desc:     "Validator address to bond to [example: pc1p...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+			{
+				Name:     "amount",
+				Desc:     "Stake amount in PAC",
+				Optional: false,
+			},
+			{
+				Name:     "sender",
+				Desc:     "Your account address that pays the stake [example: pc1z...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+			{
+				Name:     "public_key",
+				Desc:     "Validator public key, required for the first bond",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p... 1000 pc1z..."},
+		Handler:     t.buildBondHandler,
+	}
+
+	subCmdBuildTransfer := command.Command{
+		Name: TransferCommandName,
+		Desc: "Build an unsigned transfer transaction to sign offline",
+		Help: "Provide the receiver address, the amount in PAC and your account address",
+		Args: []command.Args{
+			{
+				Name:     "receiver",
+				Desc:     "Receiver address [example: pc1z...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+			{
+				Name:     "amount",
+				Desc:     "Amount in PAC",
+				Optional: false,
+			},
+			{
+				Name:     "sender",
+				Desc:     "Your account address that pays the amount [example: pc1z...]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+			{
+				Name:     "memo",
+				Desc:     "Optional transaction memo",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1z... 10 pc1z... gift"},
+		Handler:     t.buildTransferHandler,
+	}
+
+	subCmdBuild := command.Command{
+		Name:        BuildCommandName,
+		Desc:        "Build unsigned transactions",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	subCmdBuild.AddSubCommand(subCmdBuildBond)
+	subCmdBuild.AddSubCommand(subCmdBuildTransfer)
+
+	subCmdQR := command.Command{
+		Name: QRCommandName,
+		Desc: "Render an address or a raw transaction as a QR code",
+		Help: "Provide an address or a hex encoded transaction (signed or unsigned) to scan it with your wallet",
+		Args: []command.Args{
+			{
+				Name:     "data",
+				Desc:     "Address or raw transaction in hex",
+				Optional: false,
+				Kind:     command.ArgHex,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      []command.AppID{command.AppIdDiscord, command.AppIdTelegram},
+		Examples:    []string{"pc1z..."},
+		Handler:     t.qrHandler,
+	}
+
+	subCmdDecode := command.Command{
+		Name: DecodeCommandName,
+		Desc: "Decode a raw transaction to verify it before signing or broadcasting",
+		Help: "Provide a hex encoded transaction (signed or unsigned), it's only decoded and never broadcasted",
+		Args: []command.Args{
+			{
+				Name:     "raw_tx",
+				Desc:     "Raw transaction in hex",
+				Optional: false,
+				Kind:     command.ArgHex,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"0101..."},
+		Handler:     t.decodeHandler,
+	}
+
+	cmdTransaction := command.Command{
+		Name:        CommandName,
+		Desc:        "Transaction tools",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdTransaction.AddSubCommand(subCmdBuild)
+	cmdTransaction.AddSubCommand(subCmdQR)
+	cmdTransaction.AddSubCommand(subCmdDecode)
+
+	return cmdTransaction
+}
+
+func (t *Transaction) buildBondHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	validator, err := crypto.AddressFromString(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !validator.IsValidatorAddress() {
+		return cmd.FailedResult("%s is not a validator address", args[0])
+	}
+
+	stake, err := amount.FromString(args[1])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	sender, err := crypto.AddressFromString(args[2])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !sender.IsAccountAddress() {
+		return cmd.FailedResult("%s is not an account address", args[2])
+	}
+
+	pubKey := ""
+	if len(args) > 3 {
+		pubKey = args[3]
+	}
+
+	// the first bond of a validator needs its public key, the protocol rejects it on the later bonds.
+	_, err = t.clientMgr.GetValidatorInfo(ctx, validator.String())
+	if err != nil && !client.IsNotFound(err) {
+		return cmd.ErrorResult(err)
+	}
+	firstBond := err != nil
+	keyDropped := false
+
+	switch {
+	case firstBond && pubKey == "":
+		return cmd.FailedResult("%s is not bonded yet, so its first bond needs the public key of the validator. "+
+			"Find it in the wallet of your node and send: tx build bond %s %s %s <public_key>",
+			validator.String(), validator.String(), args[1], sender.String()).WithCode(command.ErrCodeInvalidArgs)
+
+	case firstBond:
+		pub, err := bls.PublicKeyFromString(pubKey)
+		if err != nil {
+			return cmd.FailedResult("The public key is not valid: %v", err).WithCode(command.ErrCodeInvalidArgs)
+		}
+
+		if pub.ValidatorAddress() != validator {
+			return cmd.FailedResult("The public key is of %s, not of the validator %s",
+				pub.ValidatorAddress().String(), validator.String()).WithCode(command.ErrCodeInvalidArgs)
+		}
+
+	case pubKey != "":
+		pubKey = ""
+		keyDropped = true
+	}
+
+	rawTx, err := t.clientMgr.GetRawBondTransaction(ctx, sender.String(), validator.String(), pubKey, "", int64(stake))
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	fee, err := t.clientMgr.GetFee(ctx, int64(stake))
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	rawTxHex := hex.EncodeToString(rawTx)
+	res := cmd.RenderResult(appID, "tx_build_bond", map[string]any{
+		"Sender":     sender.String(),
+		"Validator":  validator.String(),
+		"Stake":      stake,
+		"Fee":        amount.Amount(fee),
+		"RawTx":      rawTxHex,
+		"FirstBond":  firstBond,
+		"KeyDropped": keyDropped,
+	})
+
+	return withQRCode(res, appID, "bond-tx.png", rawTxHex)
+}
+
+func (t *Transaction) buildTransferHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	receiver, err := crypto.AddressFromString(args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	amt, err := amount.FromString(args[1])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	sender, err := crypto.AddressFromString(args[2])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if !sender.IsAccountAddress() {
+		return cmd.FailedResult("%s is not an account address", args[2])
+	}
+
+	memo := ""
+	if len(args) > 3 {
+		memo = args[3]
+	}
+
+	rawTx, err := t.clientMgr.GetRawTransferTransaction(ctx, sender.String(), receiver.String(), memo, int64(amt))
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	fee, err := t.clientMgr.GetFee(ctx, int64(amt))
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	rawTxHex := hex.EncodeToString(rawTx)
+	res := cmd.RenderResult(appID, "tx_build_transfer", map[string]any{
+		"Sender":   sender.String(),
+		"Receiver": receiver.String(),
+		"Amount":   amt,
+		"Fee":      amount.Amount(fee),
+		"RawTx":    rawTxHex,
+	})
+
+	return withQRCode(res, appID, "transfer-tx.png", rawTxHex)
+}
+
+func (t *Transaction) qrHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	if !appID.Supports(command.CapabilityImage) {
+		return cmd.FailedResult("QR codes are not supported on %s", appID)
+	}
+
+	data := args[0]
+	kind := "address"
+	if _, err := crypto.AddressFromString(data); err != nil {
+		rawTx, err := hex.DecodeString(data)
+		if err != nil {
+			return cmd.FailedResult("%s is neither an address nor a raw transaction", data)
+		}
+
+		if _, err := tx.FromBytes(rawTx); err != nil {
+			return cmd.ErrorResult(err)
+		}
+		kind = "transaction"
+	}
+
+	res := cmd.RenderResult(appID, "tx_qr", map[string]any{
+		"Kind": kind,
+	})
+
+	return withQRCode(res, appID, kind+".png", data)
+}
+
+// DecodedTx is the fields of a raw transaction.
+type DecodedTx struct {
+	ID         string
+	Type       string
+	Sender     string
+	Receiver   string
+	Amount     amount.Amount
+	Fee        amount.Amount
+	Memo       string
+	Signed     bool
+	LockTime   uint32
+	ValidUntil uint32 // The last height that the transaction can be committed at.
+	Height     uint32 // The current height, zero if it's unknown.
+}
+
+func (t *Transaction) decodeHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	rawTx, err := hex.DecodeString(strings.TrimPrefix(args[0], "0x"))
+	if err != nil {
+		return cmd.FailedResult("%s is not a hex encoded transaction", args[0])
+	}
+
+	trx, err := tx.FromBytes(rawTx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	decoded := decodeTx(trx)
+
+	// the height is only to show if the transaction is expired, the decoding doesn't need the node.
+	if height, err := t.clientMgr.GetBlockchainHeight(ctx); err == nil {
+		decoded.Height = height
+	}
+
+	return cmd.RenderResult(appID, "tx_decode", decoded)
+}
+
+func decodeTx(trx *tx.Tx) DecodedTx {
+	pld := trx.Payload()
+
+	// the sortition transactions live as long as the sortition interval, the others for a day.
+	params := param.DefaultParams()
+	toLive := params.TransactionToLiveInterval
+	if trx.IsSortitionTx() {
+		toLive = params.SortitionInterval
+	}
+
+	decoded := DecodedTx{
+		ID:         trx.ID().String(),
+		Type:       pld.Type().String(),
+		Sender:     pld.Signer().String(),
+		Amount:     pld.Value(),
+		Fee:        trx.Fee(),
+		Memo:       trx.Memo(),
+		Signed:     trx.IsSigned(),
+		LockTime:   trx.LockTime(),
+		ValidUntil: trx.LockTime() + toLive,
+	}
+
+	if receiver := pld.Receiver(); receiver != nil {
+		decoded.Receiver = receiver.String()
+	}
+
+	return decoded
+}
+
+// withQRCode attaches a QR code of the content to the result if the platform can render images.
+// Failing to render the QR code is not fatal, the content is always in the message itself.
+// The alt text tells what the code is, the content itself is too long to be read out.
+func withQRCode(res command.CommandResult, appID command.AppID, name, content string) command.CommandResult {
+	if !appID.Supports(command.CapabilityImage) {
+		return res
+	}
+
+	png, err := utils.QRCodePNG(content)
+	if err != nil {
+		return res
+	}
+
+	return res.WithAttachment(command.Attachment{
+		Name:        name,
+		ContentType: "image/png",
+		Data:        png,
+		AltText:     qrAltText(content),
+	})
+}
+
+// qrAltText describes the QR code of an address or a raw transaction, like: "QR code of the address pc1p...".
+func qrAltText(content string) string {
+	if _, err := crypto.AddressFromString(content); err == nil {
+		return "QR code of the address " + content
+	}
+
+	return fmt.Sprintf("QR code of the raw transaction, %d characters that start with %s",
+		len(content), content[:min(len(content), 8)])
+}
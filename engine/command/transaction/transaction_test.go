@@ -0,0 +1,246 @@
+package transaction
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pactus-project/pactus/types/tx"
+	"github.com/pactus-project/pactus/util/testsuite"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDecode(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	trx := NewTransaction(cm)
+	cmd := trx.GetCommand()
+
+	sender, receiver := ts.RandAccAddress(), ts.RandAccAddress()
+	transfer := tx.NewTransferTx(1_000_000, sender, receiver, amount.Amount(5e9), amount.Amount(1e6), "gift")
+	rawTx, err := transfer.Bytes()
+	require.NoError(t, err)
+
+	t.Run("unsigned transfer", func(t *testing.T) {
+		c.EXPECT().GetBlockchainHeight(gomock.Any()).Return(uint32(1_000_010), nil)
+
+		res := trx.decodeHandler(context.Background(), cmd, command.AppIdCLI, "user-id", hex.EncodeToString(rawTx))
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "Unsigned transfer transaction")
+		assert.Contains(t, res.Message, "ID: "+transfer.ID().String())
+		assert.Contains(t, res.Message, "Sender: "+sender.String())
+		assert.Contains(t, res.Message, "Receiver: "+receiver.String())
+		assert.Contains(t, res.Message, "Amount: 5 PAC")
+		assert.Contains(t, res.Message, "Memo: gift")
+		assert.Contains(t, res.Message, "Valid from block 1,000,000 to block 1,008,640")
+		assert.NotContains(t, res.Message, "expired")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c.EXPECT().GetBlockchainHeight(gomock.Any()).Return(uint32(1_010_000), nil)
+
+		res := trx.decodeHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "0x"+hex.EncodeToString(rawTx))
+		assert.Contains(t, res.Message, "The transaction is expired at the current block 1,010,000")
+	})
+
+	t.Run("node is not needed", func(t *testing.T) {
+		c.EXPECT().GetBlockchainHeight(gomock.Any()).Return(uint32(0), errors.New("unavailable"))
+
+		res := trx.decodeHandler(context.Background(), cmd, command.AppIdCLI, "user-id", hex.EncodeToString(rawTx))
+		assert.True(t, res.Successful)
+	})
+
+	t.Run("invalid transactions", func(t *testing.T) {
+		res := trx.decodeHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "not-hex")
+		assert.False(t, res.Successful)
+		assert.Contains(t, res.Message, "is not a hex encoded transaction")
+
+		res = trx.decodeHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "0102")
+		assert.False(t, res.Successful)
+	})
+}
+
+func TestBuildBond(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	trx := NewTransaction(cm)
+	cmd := trx.GetCommand().SubCommands[0].SubCommands[0]
+
+	pub, _ := ts.RandBLSKeyPair()
+	validator, sender := pub.ValidatorAddress(), ts.RandAccAddress()
+	notFound := status.Error(codes.NotFound, "validator not found")
+
+	t.Run("first bond without the public key", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfo(gomock.Any(), validator.String()).Return(nil, notFound)
+
+		res := trx.buildBondHandler(context.Background(), cmd, command.AppIdCLI, "user-id", validator.String(), "100",
+			sender.String())
+		assert.False(t, res.Successful)
+		assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+		assert.Contains(t, res.Message, "is not bonded yet, so its first bond needs the public key")
+		assert.Contains(t, res.Message, "tx build bond "+validator.String()+" 100 "+sender.String()+" <public_key>")
+	})
+
+	t.Run("public key of another validator", func(t *testing.T) {
+		other, _ := ts.RandBLSKeyPair()
+		c.EXPECT().GetValidatorInfo(gomock.Any(), validator.String()).Return(nil, notFound)
+
+		res := trx.buildBondHandler(context.Background(), cmd, command.AppIdCLI, "user-id",
+			validator.String(), "100", sender.String(), other.String())
+		assert.False(t, res.Successful)
+		assert.Contains(t, res.Message, "The public key is of "+other.ValidatorAddress().String())
+	})
+
+	t.Run("first bond", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfo(gomock.Any(), validator.String()).Return(nil, notFound)
+		c.EXPECT().GetRawBondTransaction(gomock.Any(), sender.String(), validator.String(), pub.String(), "",
+			int64(100e9)).Return([]byte{1, 2}, nil)
+		c.EXPECT().GetFee(gomock.Any(), int64(100e9)).Return(int64(1e7), nil)
+
+		res := trx.buildBondHandler(context.Background(), cmd, command.AppIdCLI, "user-id",
+			validator.String(), "100", sender.String(), pub.String())
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "First bond📝: the validator is not bonded yet")
+	})
+
+	t.Run("bonded validator", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfo(gomock.Any(), validator.String()).Return(&pactus.GetValidatorResponse{}, nil).Times(2)
+		c.EXPECT().GetRawBondTransaction(gomock.Any(), sender.String(), validator.String(), "", "",
+			int64(100e9)).Return([]byte{1, 2}, nil).Times(2)
+		c.EXPECT().GetFee(gomock.Any(), int64(100e9)).Return(int64(1e7), nil).Times(2)
+
+		res := trx.buildBondHandler(context.Background(), cmd, command.AppIdCLI, "user-id", validator.String(), "100",
+			sender.String())
+		require.True(t, res.Successful, res.Message)
+		assert.NotContains(t, res.Message, "public key")
+
+		res = trx.buildBondHandler(context.Background(), cmd, command.AppIdCLI, "user-id",
+			validator.String(), "100", sender.String(), pub.String())
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "The validator is bonded already⚠️: the public key is only for the first bond")
+	})
+
+	t.Run("node failure", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfo(gomock.Any(), validator.String()).Return(nil, errors.New("unavailable"))
+
+		res := trx.buildBondHandler(context.Background(), cmd, command.AppIdCLI, "user-id", validator.String(), "100",
+			sender.String())
+		assert.False(t, res.Successful)
+		assert.NotContains(t, res.Message, "public key")
+	})
+}
+
+func TestBuildTransfer(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	trx := NewTransaction(cm)
+	cmd := trx.GetCommand().SubCommands[0].SubCommands[1]
+
+	receiver, sender := ts.RandAccAddress(), ts.RandAccAddress()
+
+	t.Run("sender is not an account", func(t *testing.T) {
+		validator := ts.RandValAddress()
+
+		res := trx.buildTransferHandler(context.Background(), cmd, command.AppIdCLI, "user-id", receiver.String(), "5",
+			validator.String())
+		assert.False(t, res.Successful)
+		assert.Equal(t, validator.String()+" is not an account address", res.Message)
+	})
+
+	t.Run("transfer with a memo", func(t *testing.T) {
+		c.EXPECT().GetRawTransferTransaction(gomock.Any(), sender.String(), receiver.String(), "rent",
+			int64(5e9)).Return([]byte{1, 2, 0xab}, nil)
+		c.EXPECT().GetFee(gomock.Any(), int64(5e9)).Return(int64(1e7), nil)
+
+		res := trx.buildTransferHandler(context.Background(), cmd, command.AppIdDiscord, "user-id",
+			receiver.String(), "5", sender.String(), "rent")
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "Sender: "+sender.String())
+		assert.Contains(t, res.Message, "Receiver: "+receiver.String())
+		assert.Contains(t, res.Message, "Amount: 5 PAC")
+		assert.Contains(t, res.Message, "Fee: 0.01 PAC")
+		assert.Contains(t, res.Message, "\n0102ab\n")
+		require.Len(t, res.Attachments, 1)
+		assert.Equal(t, "transfer-tx.png", res.Attachments[0].Name)
+	})
+
+	t.Run("node failure", func(t *testing.T) {
+		c.EXPECT().GetRawTransferTransaction(gomock.Any(), sender.String(), receiver.String(), "",
+			int64(5e9)).Return(nil, errors.New("unavailable"))
+
+		res := trx.buildTransferHandler(context.Background(), cmd, command.AppIdCLI, "user-id", receiver.String(), "5",
+			sender.String())
+		assert.False(t, res.Successful)
+		assert.Contains(t, res.Message, "unavailable")
+	})
+}
+
+func TestQR(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	trx := NewTransaction(nil)
+	cmd := trx.GetCommand().SubCommands[1]
+
+	addr := ts.RandAccAddress().String()
+	transfer := tx.NewTransferTx(1_000_000, ts.RandAccAddress(), ts.RandAccAddress(), amount.Amount(5e9),
+		amount.Amount(1e6), "")
+	rawTx, err := transfer.Bytes()
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		data, kind string
+	}{
+		{addr, "address"},
+		{hex.EncodeToString(rawTx), "transaction"},
+	} {
+		res := trx.qrHandler(context.Background(), cmd, command.AppIdTelegram, "user-id", tt.data)
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "QR code of the "+tt.kind)
+		require.Len(t, res.Attachments, 1, tt.kind)
+		assert.Equal(t, tt.kind+".png", res.Attachments[0].Name)
+		assert.Equal(t, "image/png", res.Attachments[0].ContentType)
+		assert.Equal(t, "\x89PNG", string(res.Attachments[0].Data[:4]))
+	}
+
+	res := trx.qrHandler(context.Background(), cmd, command.AppIdDiscord, "user-id", "not-hex")
+	assert.False(t, res.Successful)
+	assert.Equal(t, "not-hex is neither an address nor a raw transaction", res.Message)
+
+	res = trx.qrHandler(context.Background(), cmd, command.AppIdDiscord, "user-id", "0102")
+	assert.False(t, res.Successful, "hex that is not a transaction")
+	assert.Empty(t, res.Attachments)
+
+	res = trx.qrHandler(context.Background(), cmd, command.AppIdCLI, "user-id", addr)
+	assert.False(t, res.Successful)
+	assert.Equal(t, "QR codes are not supported on CLI", res.Message)
+	assert.Empty(t, res.Attachments)
+}
+
+func TestQRAltText(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+
+	addr := ts.RandAccAddress().String()
+	assert.Equal(t, "QR code of the address "+addr, qrAltText(addr))
+	assert.Equal(t, "QR code of the raw transaction, 12 characters that start with 0102abcd",
+		qrAltText("0102abcdef00"))
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pagu-project/Pagu/utils"
+)
+
+const (
+	// MaxUploadSize is the size of an uploaded file of the arguments at most, like a list of validators.
+	MaxUploadSize = 16 << 10
+
+	// uploadTimeout is the time to download an uploaded file, a slow download doesn't hold the adapter.
+	uploadTimeout = 10 * time.Second
+)
+
+// IsTextUpload checks if an uploaded file is a text file of the arguments, like a .txt or a .csv file.
+func IsTextUpload(contentType string, size int64) bool {
+	return strings.HasPrefix(contentType, "text/") && size > 0 && size <= MaxUploadSize
+}
+
+// UploadArgs returns the arguments of an uploaded text file, they are separated by the spaces, the lines,
+// the commas or the semicolons.
+func UploadArgs(data []byte) []string {
+	return strings.FieldsFunc(string(data), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == ';'
+	})
+}
+
+// DownloadUploadArgs downloads an uploaded text file of the URL and returns its arguments,
+// the adapters append them to the arguments of the message, like: "validator bulk" with a list of validators.
+func DownloadUploadArgs(ctx context.Context, url string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	data, err := utils.DownloadFile(ctx, url, MaxUploadSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return UploadArgs(data), nil
+}
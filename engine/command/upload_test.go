@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadArgs(t *testing.T) {
+	assert.Equal(t, []string{"pc1p1", "pc1p2", "42", "7"}, UploadArgs([]byte("pc1p1,pc1p2\r\n42; 7\n\n")))
+	assert.Empty(t, UploadArgs(nil))
+
+	assert.True(t, IsTextUpload("text/plain; charset=utf-8", 100))
+	assert.True(t, IsTextUpload("text/csv", MaxUploadSize))
+	assert.False(t, IsTextUpload("image/png", 100))
+	assert.False(t, IsTextUpload("text/plain", MaxUploadSize+1))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			_, _ = w.Write([]byte(strings.Repeat("a", MaxUploadSize+1)))
+
+			return
+		}
+
+		_, _ = w.Write([]byte("pc1p1\npc1p2"))
+	}))
+	defer server.Close()
+
+	args, err := DownloadUploadArgs(context.Background(), server.URL+"/validators.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pc1p1", "pc1p2"}, args)
+
+	_, err = DownloadUploadArgs(context.Background(), server.URL+"/large")
+	assert.Error(t, err, "the file is larger than the limit")
+}
@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+// MaxBulkValidators is the number of the validators that a bulk command looks up at most,
+// like all the nodes of an operator.
+const MaxBulkValidators = 50
+
+// BulkValidator is a row of the bulk info, a validator that is not found has only its argument.
+type BulkValidator struct {
+	Arg                 string
+	Found               bool
+	Number              int32
+	Address             string
+	AvailabilityScore   float64
+	Healthy             bool
+	Stake               amount.Amount
+	LastSortitionHeight uint32
+}
+
+// bulkArgs returns the validators of the arguments without the repeated ones, like: "pc1p...,pc1p...".
+func bulkArgs(args []string) []string {
+	validators := make([]string, 0, len(args))
+	for _, val := range command.SplitList(args) {
+		if !slices.Contains(validators, val) {
+			validators = append(validators, val)
+		}
+	}
+
+	return validators
+}
+
+func (v *Validator) bulkHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	validators := bulkArgs(args)
+	if len(validators) == 0 {
+		return cmd.FailedResult("Please send the validator addresses or numbers, like: pc1p...,pc1p...").
+			WithCode(command.ErrCodeInvalidArgs)
+	}
+
+	if len(validators) > MaxBulkValidators {
+		return cmd.FailedResult("%d validators are sent, at most %d validators are looked up at once",
+			len(validators), MaxBulkValidators).WithCode(command.ErrCodeInvalidArgs)
+	}
+
+	// the validators are looked up at once, a failed lookup is shown as not found.
+	scan := client.Scan(ctx, client.NewScanner(0, 0), validators,
+		func(valCtx context.Context, arg string) (BulkValidator, error) {
+			row := BulkValidator{Arg: cmd.Echo(arg)}
+			val, err := v.validatorInfo(valCtx, arg)
+			if err != nil || val == nil {
+				return row, nil
+			}
+
+			row.Found = true
+			row.Number = val.Number
+			row.Address = val.Address
+			row.AvailabilityScore = val.AvailabilityScore
+			row.Healthy = val.AvailabilityScore >= v.healthyScore
+			row.Stake = amount.Amount(val.Stake)
+			row.LastSortitionHeight = val.LastSortitionHeight
+
+			return row, nil
+		})
+
+	found, unhealthy := 0, 0
+	total := amount.Amount(0)
+	for _, row := range scan.Results {
+		if !row.Found {
+			continue
+		}
+
+		found++
+		total += row.Stake
+		if !row.Healthy {
+			unhealthy++
+		}
+	}
+
+	return cmd.RenderResult(appID, "validator_bulk", map[string]any{
+		"Validators": scan.Results,
+		"Found":      found,
+		"Unhealthy":  unhealthy,
+		"Stake":      total,
+	})
+}
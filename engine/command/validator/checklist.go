@@ -0,0 +1,223 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pactus-project/pactus/types/param"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+const (
+	// syncTolerance is how many blocks the node can be behind the network and still be synced.
+	syncTolerance = 10
+
+	// rotationBlocks is the window that an active validator is expected to join the committee in, about a day.
+	rotationBlocks = 8640
+)
+
+type CheckStatus string
+
+const (
+	CheckPassed  CheckStatus = "passed"
+	CheckFailed  CheckStatus = "failed"
+	CheckUnknown CheckStatus = "unknown" // The check can't be run, like the sync of a node that is not reachable.
+)
+
+// Check is a step of the onboarding checklist of a validator, the hint tells how to fix a failed check.
+type Check struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+	Hint   string
+}
+
+// Checklist is the result of the onboarding checks of a validator.
+type Checklist struct {
+	Number  int32
+	Address string
+	Checks  []Check
+}
+
+// Passed returns how many of the checks are passed.
+func (c Checklist) Passed() int {
+	passed := 0
+	for _, check := range c.Checks {
+		if check.Status == CheckPassed {
+			passed++
+		}
+	}
+
+	return passed
+}
+
+func (v *Validator) checklistHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	val, err := v.validatorInfo(ctx, args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if val == nil {
+		return cmd.FailedResult("%s is not a validator address or number", args[0])
+	}
+
+	info, err := v.clientMgr.GetBlockchainInfo(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "validator_checklist", v.checklist(ctx, val, info)).
+		WithReference(command.ReferenceValidator, val.Address)
+}
+
+// checklist runs the checks of the validator, from the node to the rewards.
+// The checks that can't be run are unknown, like the reward address of a validator out of the committee.
+func (v *Validator) checklist(ctx context.Context, val *pactus.ValidatorInfo,
+	info *pactus.GetBlockchainInfoResponse,
+) Checklist {
+	height := info.LastBlockHeight
+	checks := make([]Check, 0, 6)
+
+	peer, err := v.clientMgr.GetPeerInfo(val.Address)
+	reachable := err == nil
+	if reachable {
+		checks = append(checks, Check{
+			Name:   "Node reachable",
+			Status: CheckPassed,
+			Detail: fmt.Sprintf("connected to %s (%s)", peer.Moniker, peer.Agent),
+		})
+	} else {
+		checks = append(checks, Check{
+			Name:   "Node reachable",
+			Status: CheckFailed,
+			Detail: "the nodes of Pagu are not connected to it",
+			Hint:   "Check that the node is running and its P2P port is open, try: node check <host>",
+		})
+	}
+
+	switch {
+	case !reachable:
+		checks = append(checks, Check{
+			Name:   "Synced",
+			Status: CheckUnknown,
+			Detail: "the node is not reachable",
+		})
+
+	case peer.Height+syncTolerance >= height:
+		checks = append(checks, Check{
+			Name:   "Synced",
+			Status: CheckPassed,
+			Detail: fmt.Sprintf("at block %d", peer.Height),
+		})
+
+	default:
+		checks = append(checks, Check{
+			Name:   "Synced",
+			Status: CheckFailed,
+			Detail: fmt.Sprintf("at block %d, %d blocks behind", peer.Height, height-peer.Height),
+			Hint:   "Wait for the node to sync, if it's stuck restart it or check its peers and the disk space",
+		})
+	}
+
+	activeHeight := val.LastBondingHeight + param.DefaultParams().BondInterval
+	bonded := val.Stake > 0 && val.UnbondingHeight == 0
+	switch {
+	case !bonded:
+		checks = append(checks, Check{
+			Name:   "Bonded",
+			Status: CheckFailed,
+			Detail: "the validator has no stake",
+			Hint:   "Bond the stake to the validator, try: tx build bond",
+		})
+
+	case height < activeHeight:
+		checks = append(checks, Check{
+			Name:   "Bonded",
+			Status: CheckFailed,
+			Detail: fmt.Sprintf("%s, active at block %d", amount.Amount(val.Stake), activeHeight),
+			Hint:   "The bonded stake joins the sortition after the bond interval, please wait",
+		})
+
+	default:
+		checks = append(checks, Check{
+			Name:   "Bonded",
+			Status: CheckPassed,
+			Detail: amount.Amount(val.Stake).String(),
+		})
+	}
+
+	inCommittee := slices.ContainsFunc(info.CommitteeValidators, func(c *pactus.ValidatorInfo) bool {
+		return c.Address == val.Address
+	})
+	switch {
+	case inCommittee:
+		checks = append(checks, Check{
+			Name:   "Committee rotation",
+			Status: CheckPassed,
+			Detail: fmt.Sprintf("a member since block %d", val.LastSortitionHeight),
+		})
+
+	case val.LastSortitionHeight > 0 && val.LastSortitionHeight+rotationBlocks >= height:
+		checks = append(checks, Check{
+			Name:   "Committee rotation",
+			Status: CheckPassed,
+			Detail: fmt.Sprintf("last joined at block %d", val.LastSortitionHeight),
+		})
+
+	default:
+		checks = append(checks, Check{
+			Name:   "Committee rotation",
+			Status: CheckFailed,
+			Detail: "not in the committee in the last day",
+			Hint: "Keep the node synced and the stake active, a validator with a small stake " +
+				"joins the committee less often",
+		})
+	}
+
+	if val.AvailabilityScore >= v.healthyScore {
+		checks = append(checks, Check{
+			Name:   "Availability score",
+			Status: CheckPassed,
+			Detail: fmt.Sprintf("%.2f", val.AvailabilityScore),
+		})
+	} else {
+		checks = append(checks, Check{
+			Name:   "Availability score",
+			Status: CheckFailed,
+			Detail: fmt.Sprintf("%.2f, below %.2f", val.AvailabilityScore, v.healthyScore),
+			Hint: "The validator missed the votes in the committee, keep the node online and synced, " +
+				"the score recovers over time",
+		})
+	}
+
+	// the reward address is found in the blocks that the validator proposed, only in the committee.
+	rewardAddr := ""
+	if inCommittee {
+		rewardAddr, _ = v.rewardAddress(ctx, val.Address, height, val.LastSortitionHeight)
+	}
+	if rewardAddr != "" {
+		checks = append(checks, Check{
+			Name:   "Reward address",
+			Status: CheckPassed,
+			Detail: rewardAddr,
+		})
+	} else {
+		checks = append(checks, Check{
+			Name:   "Reward address",
+			Status: CheckUnknown,
+			Detail: "the validator has not proposed a block recently",
+			Hint:   "Set the reward_addresses in the [node] config of the node, see: validator keys",
+		})
+	}
+
+	return Checklist{
+		Number:  val.Number,
+		Address: val.Address,
+		Checks:  checks,
+	}
+}
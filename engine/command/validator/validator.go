@@ -0,0 +1,361 @@
+package validator
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/types/amount"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/indexer"
+	"github.com/pagu-project/Pagu/uptime"
+)
+
+const (
+	CommandName          = "validator"
+	KeysCommandName      = "keys"
+	ChecklistCommandName = "checklist"
+	ReportCommandName    = "report"
+	BondsCommandName     = "bonds"
+	BulkCommandName      = "bulk"
+	HelpCommandName      = "help"
+)
+
+// rewardSearchBlocks is how many of the last blocks are searched for a block of the validator,
+// the reward address is not on the validator, it's the receiver of the block reward.
+const rewardSearchBlocks = 100
+
+// bondsCount is the number of the last bonds, unbonds and withdraws in the bond history.
+const bondsCount = 20
+
+type Validator struct {
+	clientMgr    *client.Mgr
+	healthyScore float64 // The availability score that a healthy validator has at least.
+	uptime       *uptime.Uptime
+	indexer      *indexer.Indexer
+}
+
+func NewValidator(clientMgr *client.Mgr, healthyScore float64, up *uptime.Uptime, idx *indexer.Indexer) Validator {
+	return Validator{
+		clientMgr:    clientMgr,
+		healthyScore: healthyScore,
+		uptime:       up,
+		indexer:      idx,
+	}
+}
+
+// Keys is the keys of a validator, the reward address is empty if no block of the validator is found.
+type Keys struct {
+	Number              int32
+	Address             string
+	PublicKey           string
+	InCommittee         bool
+	LastSortitionHeight uint32
+	RewardAddress       string
+	RewardHeight        uint32 // The block that the reward address is found in.
+}
+
+// Bond is a bond, an unbond or a withdraw of a validator, with the stake of the validator after it.
+type Bond struct {
+	TxID         string
+	Date         string
+	Type         string
+	Counterparty string // The bonder of a bond and the receiver of a withdraw.
+	Amount       amount.Amount
+	Stake        amount.Amount
+}
+
+func (v *Validator) GetCommand() command.Command {
+	subCmdKeys := command.Command{
+		Name: KeysCommandName,
+		Desc: "Public key and reward address of a validator",
+		Help: "Shows the public key, the committee membership and the reward address of the last block that " +
+			"the validator proposed, in a format to copy in the node config",
+		Args: []command.Args{
+			{
+				Name:     "validator",
+				Desc:     "Validator address or number [example: pc1p... or 42]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p...", "42"},
+		Expensive:   true,
+		Handler:     v.keysHandler,
+	}
+
+	subCmdChecklist := command.Command{
+		Name: ChecklistCommandName,
+		Desc: "Onboarding checklist of a validator",
+		Help: "Checks that the node is reachable and synced, the stake is bonded, the validator joins the committee, " +
+			"the availability score is healthy and the reward address is set, with the hints to fix the failed checks",
+		Args: []command.Args{
+			{
+				Name:     "validator",
+				Desc:     "Validator address or number [example: pc1p... or 42]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p...", "42"},
+		Expensive:   true,
+		Handler:     v.checklistHandler,
+	}
+
+	subCmdReport := command.Command{
+		Name: ReportCommandName,
+		Desc: "Monthly uptime report of a validator",
+		Help: "Shows the online blocks, the missed sortitions and the availability score of a month. " +
+			"The validators are sampled while they are watched, watch them with: subscribe watch",
+		Args: []command.Args{
+			{
+				Name:     "validator",
+				Desc:     "Validator address or number [example: pc1p... or 42]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+			{
+				Name:     "month",
+				Desc:     "The month of the report, the current month by default [example: 2024-07]",
+				Optional: true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p... 2024-07", "42"},
+		Handler:     v.reportHandler,
+	}
+
+	subCmdBonds := command.Command{
+		Name: BondsCommandName,
+		Desc: "Bond history of a validator",
+		Help: "Shows the last bonds, unbonds and withdraws of the validator and its stake after each of them, " +
+			"since the bot started indexing them",
+		Args: []command.Args{
+			{
+				Name:     "validator",
+				Desc:     "Validator address or number [example: pc1p... or 42]",
+				Optional: false,
+				Kind:     command.ArgAddress,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p...", "42"},
+		Handler:     v.bondsHandler,
+	}
+
+	subCmdBulk := command.Command{
+		Name: BulkCommandName,
+		Desc: "Compact info of many validators",
+		Help: "Shows the PIP-19 score, the stake and the last sortition of up to 50 validators, " +
+			"like all the nodes of an operator. The validators can be uploaded in a text file too",
+		Args: []command.Args{
+			{
+				Name:     "validators",
+				Desc:     "Validator addresses or numbers, separated by commas [example: pc1p...,pc1p...,42]",
+				Optional: true,
+				Variadic: true,
+				Kind:     command.ArgList,
+				Upload:   true,
+			},
+		},
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Examples:    []string{"pc1p...,pc1p...,42"},
+		Expensive:   true,
+		Handler:     v.bulkHandler,
+	}
+
+	cmdValidator := command.Command{
+		Emoji:       "🔑",
+		Name:        CommandName,
+		Desc:        "Validator tools",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdValidator.AddSubCommand(subCmdKeys)
+	cmdValidator.AddSubCommand(subCmdChecklist)
+	cmdValidator.AddSubCommand(subCmdReport)
+	cmdValidator.AddSubCommand(subCmdBonds)
+	cmdValidator.AddSubCommand(subCmdBulk)
+
+	return cmdValidator
+}
+
+func (v *Validator) keysHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	val, err := v.validatorInfo(ctx, args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if val == nil {
+		return cmd.FailedResult("%s is not a validator address or number", args[0])
+	}
+
+	info, err := v.clientMgr.GetBlockchainInfo(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	keys := Keys{
+		Number:              val.Number,
+		Address:             val.Address,
+		PublicKey:           val.PublicKey,
+		LastSortitionHeight: val.LastSortitionHeight,
+		InCommittee: slices.ContainsFunc(info.CommitteeValidators, func(c *pactus.ValidatorInfo) bool {
+			return c.Address == val.Address
+		}),
+	}
+
+	// only the committee members propose the blocks, the search is from the last block to the sortition height.
+	if keys.InCommittee {
+		keys.RewardAddress, keys.RewardHeight = v.rewardAddress(ctx, val.Address, info.LastBlockHeight,
+			val.LastSortitionHeight)
+	}
+
+	return cmd.RenderResult(appID, "validator_keys", keys).
+		WithReference(command.ReferenceValidator, val.Address)
+}
+
+func (v *Validator) reportHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	month := time.Now().UTC()
+	if len(args) > 1 {
+		parsed, err := uptime.ParseMonth(args[1])
+		if err != nil {
+			return cmd.FailedResult("%s is not a month, like: 2024-07", args[1])
+		}
+		month = parsed
+	}
+
+	val, err := v.validatorInfo(ctx, args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if val == nil {
+		return cmd.FailedResult("%s is not a validator address or number", args[0])
+	}
+
+	report, err := v.uptime.Report(val.Address, month)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if report == nil {
+		return cmd.FailedResult("There is no sample of %s in %s, the validators are sampled while they are watched, "+
+			"watch it with: subscribe watch %s", val.Address, month.Format(uptime.MonthLayout), val.Address)
+	}
+
+	return cmd.RenderResult(appID, "uptime_report", report).
+		WithReference(command.ReferenceValidator, val.Address)
+}
+
+// bondsHandler shows the bond history of the validator, the stake after each transaction is found
+// from the current stake backwards: a bond adds its amount, a withdraw takes it and an unbond only locks the stake.
+func (v *Validator) bondsHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
+	val, err := v.validatorInfo(ctx, args[0])
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	if val == nil {
+		return cmd.FailedResult("%s is not a validator address or number", args[0])
+	}
+
+	txs, err := v.indexer.Bonds(val.Address, bondsCount)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	stake := val.Stake
+	bonds := make([]Bond, 0, len(txs))
+	for _, tx := range txs {
+		bonds = append(bonds, Bond{
+			TxID:         tx.TxID,
+			Date:         tx.BlockTime.UTC().Format(time.DateOnly),
+			Type:         tx.Type,
+			Counterparty: tx.Counterparty,
+			Amount:       amount.Amount(tx.Amount),
+			Stake:        amount.Amount(stake),
+		})
+
+		switch {
+		case tx.Type == "bond" && tx.Direction == database.TxIncoming:
+			stake -= tx.Amount
+		case tx.Type == "withdraw" && tx.Direction == database.TxOutgoing:
+			stake += tx.Amount
+		}
+	}
+
+	return cmd.RenderResult(appID, "validator_bonds", map[string]any{
+		"Number":  val.Number,
+		"Address": val.Address,
+		"Stake":   amount.Amount(val.Stake),
+		"Bonds":   bonds,
+	}).WithReference(command.ReferenceValidator, val.Address)
+}
+
+// validatorInfo returns the validator with the number or the address, nil if the argument is neither of them.
+func (v *Validator) validatorInfo(ctx context.Context, arg string) (*pactus.ValidatorInfo, error) {
+	if num, err := strconv.ParseInt(arg, 10, 32); err == nil {
+		res, err := v.clientMgr.GetValidatorInfoByNumber(ctx, int32(num))
+		if err != nil {
+			return nil, err
+		}
+
+		return res.Validator, nil
+	}
+
+	addr, err := crypto.AddressFromString(arg)
+	if err != nil || !addr.IsValidatorAddress() {
+		return nil, nil
+	}
+
+	res, err := v.clientMgr.GetValidatorInfo(ctx, addr.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Validator, nil
+}
+
+// rewardAddress finds the last block of the proposer, from the height down to the lowest height,
+// and returns the receiver of its block reward.
+func (v *Validator) rewardAddress(ctx context.Context, proposer string, height, lowest uint32) (string, uint32) {
+	for i := 0; i < rewardSearchBlocks && height > lowest; i++ {
+		block, err := v.clientMgr.GetBlockTransactions(ctx, height)
+		if err != nil {
+			return "", 0
+		}
+
+		if block.Header != nil && block.Header.ProposerAddress == proposer && len(block.Txs) > 0 {
+			// the first transaction of a block is the block reward.
+			if transfer, ok := block.Txs[0].Payload.(*pactus.TransactionInfo_Transfer); ok {
+				return transfer.Transfer.Receiver, height
+			}
+		}
+		height--
+	}
+
+	return "", 0
+}
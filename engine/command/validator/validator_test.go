@@ -0,0 +1,263 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pactus-project/pactus/util/testsuite"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/indexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestKeys(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	v := NewValidator(cm, 0.9, nil, nil)
+	cmd := v.GetCommand()
+
+	valAddr := ts.RandValAddress().String()
+	rewardAddr := ts.RandAccAddress().String()
+	val := &pactus.ValidatorInfo{
+		Number:              42,
+		Address:             valAddr,
+		PublicKey:           "public1p...",
+		LastSortitionHeight: 1_000,
+	}
+
+	t.Run("committee member", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfoByNumber(gomock.Any(), int32(42)).
+			Return(&pactus.GetValidatorResponse{Validator: val}, nil)
+		c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{
+			LastBlockHeight:     1_010,
+			CommitteeValidators: []*pactus.ValidatorInfo{val},
+		}, nil)
+		c.EXPECT().GetBlockTransactions(gomock.Any(), uint32(1_010)).Return(&pactus.GetBlockResponse{
+			Header: &pactus.BlockHeaderInfo{ProposerAddress: ts.RandValAddress().String()},
+		}, nil)
+		c.EXPECT().GetBlockTransactions(gomock.Any(), uint32(1_009)).Return(&pactus.GetBlockResponse{
+			Header: &pactus.BlockHeaderInfo{ProposerAddress: valAddr},
+			Txs: []*pactus.TransactionInfo{{
+				Payload: &pactus.TransactionInfo_Transfer{
+					Transfer: &pactus.PayloadTransfer{Receiver: rewardAddr},
+				},
+			}},
+		}, nil)
+
+		res := v.keysHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "42")
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "Public key: public1p...")
+		assert.Contains(t, res.Message, "member since block 1,000")
+		assert.Contains(t, res.Message, "Reward address: "+rewardAddr+" (block 1,009)")
+		assert.Contains(t, res.Message, `reward_addresses = ["`+rewardAddr+`"]`)
+		assert.Equal(t, command.Reference{Kind: command.ReferenceValidator, Value: valAddr}, res.Reference)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfo(gomock.Any(), valAddr).
+			Return(&pactus.GetValidatorResponse{Validator: val}, nil)
+		c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{
+			LastBlockHeight: 1_010,
+		}, nil)
+
+		res := v.keysHandler(context.Background(), cmd, command.AppIdCLI, "user-id", valAddr)
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "Committee: not a member")
+		assert.Contains(t, res.Message, "Reward address: unknown")
+	})
+
+	t.Run("invalid argument", func(t *testing.T) {
+		res := v.keysHandler(context.Background(), cmd, command.AppIdCLI, "user-id", ts.RandAccAddress().String())
+		assert.False(t, res.Successful)
+		assert.Contains(t, res.Message, "is not a validator address or number")
+	})
+}
+
+func TestChecklist(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	v := NewValidator(cm, 0.9, nil, nil)
+	cmd := v.GetCommand()
+
+	valAddr := ts.RandValAddress().String()
+	rewardAddr := ts.RandAccAddress().String()
+	val := &pactus.ValidatorInfo{
+		Number:              42,
+		Address:             valAddr,
+		Stake:               1_000_000_000_000,
+		LastBondingHeight:   100,
+		LastSortitionHeight: 20_000,
+		AvailabilityScore:   0.5,
+	}
+
+	t.Run("committee member", func(t *testing.T) {
+		c.EXPECT().GetValidatorInfoByNumber(gomock.Any(), int32(42)).
+			Return(&pactus.GetValidatorResponse{Validator: val}, nil)
+		c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{
+			LastBlockHeight:     20_001,
+			CommitteeValidators: []*pactus.ValidatorInfo{val},
+		}, nil)
+		c.EXPECT().GetBlockTransactions(gomock.Any(), uint32(20_001)).Return(&pactus.GetBlockResponse{
+			Header: &pactus.BlockHeaderInfo{ProposerAddress: valAddr},
+			Txs: []*pactus.TransactionInfo{{
+				Payload: &pactus.TransactionInfo_Transfer{
+					Transfer: &pactus.PayloadTransfer{Receiver: rewardAddr},
+				},
+			}},
+		}, nil)
+
+		res := v.checklistHandler(context.Background(), cmd, command.AppIdCLI, "user-id", "42")
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "Node reachable: the nodes of Pagu are not connected to it")
+		assert.Contains(t, res.Message, "node check <host>")
+		assert.Contains(t, res.Message, "Synced: the node is not reachable")
+		assert.Contains(t, res.Message, "Bonded: 1000 PAC")
+		assert.Contains(t, res.Message, "Committee rotation: a member since block 20000")
+		assert.Contains(t, res.Message, "Availability score: 0.50, below 0.90")
+		assert.Contains(t, res.Message, "Reward address: "+rewardAddr)
+		assert.Contains(t, res.Message, "3 of 6 checks passed.")
+		assert.Equal(t, command.Reference{Kind: command.ReferenceValidator, Value: valAddr}, res.Reference)
+	})
+
+	t.Run("unbonded validator", func(t *testing.T) {
+		unbonded := &pactus.ValidatorInfo{
+			Number:          43,
+			Address:         valAddr,
+			Stake:           0,
+			UnbondingHeight: 10,
+		}
+		c.EXPECT().GetValidatorInfo(gomock.Any(), valAddr).
+			Return(&pactus.GetValidatorResponse{Validator: unbonded}, nil)
+		c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{
+			LastBlockHeight: 20_001,
+		}, nil)
+
+		checklist := v.checklist(context.Background(), unbonded,
+			&pactus.GetBlockchainInfoResponse{LastBlockHeight: 20_001})
+		assert.Zero(t, checklist.Passed())
+
+		res := v.checklistHandler(context.Background(), cmd, command.AppIdCLI, "user-id", valAddr)
+		require.True(t, res.Successful, res.Message)
+		assert.Contains(t, res.Message, "Bonded: the validator has no stake")
+		assert.Contains(t, res.Message, "tx build bond")
+		assert.Contains(t, res.Message, "Committee rotation: not in the committee in the last day")
+		assert.Contains(t, res.Message, "reward_addresses")
+	})
+}
+
+func TestBonds(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	v := NewValidator(cm, 0.9, nil, indexer.NewIndexer(cm, db))
+	cmd := v.GetCommand()
+
+	valAddr := ts.RandValAddress().String()
+	bonder := ts.RandAccAddress().String()
+	c.EXPECT().GetValidatorInfo(gomock.Any(), valAddr).Return(&pactus.GetValidatorResponse{
+		Validator: &pactus.ValidatorInfo{Number: 42, Address: valAddr, Stake: 1_500_000_000_000},
+	}, nil).Times(2)
+
+	res := v.bondsHandler(context.Background(), cmd, command.AppIdCLI, "user-id", valAddr)
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "No bonds found")
+
+	day := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, db.AddAccountTransactions([]*database.AccountTransaction{
+		{
+			TxID: "aa", Address: valAddr, Height: 10, BlockTime: day, Type: "bond",
+			Direction: database.TxIncoming, Counterparty: bonder, Amount: 1_000_000_000_000,
+		},
+		{
+			TxID: "bb", Address: valAddr, Height: 20, BlockTime: day.AddDate(0, 0, 1), Type: "transfer",
+			Direction: database.TxIncoming, Amount: 1_000_000_000,
+		},
+		{
+			TxID: "cc", Address: valAddr, Height: 30, BlockTime: day.AddDate(0, 0, 2), Type: "bond",
+			Direction: database.TxIncoming, Counterparty: bonder, Amount: 500_000_000_000,
+		},
+	}))
+
+	res = v.bondsHandler(context.Background(), cmd, command.AppIdCLI, "user-id", valAddr)
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "Stake: 1,500 PAC")
+	assert.Contains(t, res.Message, "2024-05-12: ⬇️ Bond of 500 PAC from "+bonder+"\nStake after: 1,500 PAC")
+	assert.Contains(t, res.Message, "2024-05-10: ⬇️ Bond of 1,000 PAC from "+bonder+"\nStake after: 1,000 PAC")
+	assert.NotContains(t, res.Message, "2024-05-11", "the transfers are not bonds")
+	assert.Equal(t, command.Reference{Kind: command.ReferenceValidator, Value: valAddr}, res.Reference)
+}
+
+func TestBulk(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+	v := NewValidator(cm, 0.9, nil, nil)
+	cmd := v.GetCommand()
+
+	healthy := &pactus.ValidatorInfo{
+		Number: 42, Address: ts.RandValAddress().String(), Stake: 1_000_000_000_000,
+		AvailabilityScore: 0.95, LastSortitionHeight: 20_000,
+	}
+	unhealthy := &pactus.ValidatorInfo{
+		Number: 7, Address: ts.RandValAddress().String(), Stake: 500_000_000_000,
+		AvailabilityScore: 0.5, LastSortitionHeight: 19_000,
+	}
+	unknown := ts.RandValAddress().String()
+
+	c.EXPECT().GetValidatorInfo(gomock.Any(), healthy.Address).
+		Return(&pactus.GetValidatorResponse{Validator: healthy}, nil)
+	c.EXPECT().GetValidatorInfoByNumber(gomock.Any(), int32(7)).
+		Return(&pactus.GetValidatorResponse{Validator: unhealthy}, nil)
+	c.EXPECT().GetValidatorInfo(gomock.Any(), unknown).Return(nil, errors.New("validator not found"))
+
+	res := v.bulkHandler(context.Background(), cmd, command.AppIdCLI, "user-id", healthy.Address+", 7", unknown,
+		healthy.Address)
+	require.True(t, res.Successful, res.Message)
+	assert.Contains(t, res.Message, "2 of 3 validators")
+	assert.Contains(t, res.Message, "stake: 1,500 PAC")
+	assert.Contains(t, res.Message,
+		"#42 "+healthy.Address+": score 0.950✅, stake 1,000 PAC, last sortition 20,000\n"+
+			"#7 "+unhealthy.Address+": score 0.500⚠️, stake 500 PAC, last sortition 19,000\n"+
+			unknown+": not a validator❌", "the rows are in the order of the arguments, without the repeated ones")
+	assert.Contains(t, res.Message, "1 of the validators are below the healthy PIP-19 score")
+
+	many := make([]string, 0, MaxBulkValidators+1)
+	for i := 0; i <= MaxBulkValidators; i++ {
+		many = append(many, strconv.Itoa(i))
+	}
+	res = v.bulkHandler(context.Background(), cmd, command.AppIdCLI, "user-id", strings.Join(many, ","))
+	assert.False(t, res.Successful)
+	assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+
+	res = v.bulkHandler(context.Background(), cmd, command.AppIdCLI, "user-id", ",,")
+	assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+}
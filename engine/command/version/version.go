@@ -0,0 +1,62 @@
+package version
+
+import (
+	"context"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/release"
+)
+
+const (
+	CommandName       = "version"
+	LatestCommandName = "latest"
+	HelpCommandName   = "help"
+)
+
+type Version struct {
+	watcher *release.Watcher
+}
+
+func NewVersion(watcher *release.Watcher) Version {
+	return Version{
+		watcher: watcher,
+	}
+}
+
+func (v *Version) GetCommand() command.Command {
+	subCmdLatest := command.Command{
+		Name:        LatestCommandName,
+		Desc:        "Latest release of the Pactus node",
+		Help:        "Shows the version, the changelog highlights and if the upgrade is urgent",
+		Args:        nil,
+		SubCommands: nil,
+		AppIDs:      command.AllAppIDs(),
+		Handler:     v.latestHandler,
+	}
+
+	cmdVersion := command.Command{
+		Emoji:       "🆕",
+		Name:        CommandName,
+		Desc:        "Pactus node versions",
+		Help:        "",
+		Args:        nil,
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		Handler:     nil,
+	}
+
+	cmdVersion.AddSubCommand(subCmdLatest)
+
+	return cmdVersion
+}
+
+func (v *Version) latestHandler(ctx context.Context, cmd command.Command, appID command.AppID, _ string,
+	_ ...string,
+) command.CommandResult {
+	latest, err := v.watcher.Latest(ctx)
+	if err != nil {
+		return cmd.ErrorResult(err)
+	}
+
+	return cmd.RenderResult(appID, "version_latest", latest)
+}
@@ -1,6 +1,7 @@
 package zealy
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"os"
@@ -16,7 +17,9 @@ Position| Discord User 	| 	Prize	.
 1st		|	user_id_1	|	amount	.
 2nd		|	user_id_2	|	amount	.
 */
-func (z *Zealy) importWinnersHandler(cmd command.Command, _ command.AppID, _ string, args ...string) command.CommandResult {
+func (z *Zealy) importWinnersHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
 	if len(args) == 0 {
 		return cmd.FailedResult("please specify a file path to import")
 	}
@@ -48,7 +51,9 @@ func (z *Zealy) importWinnersHandler(cmd command.Command, _ command.AppID, _ str
 		totalInserted++
 	}
 
-	return cmd.SuccessfulResult("Imported successfully\nTotal inserted: %d", totalInserted)
+	return cmd.RenderResult(appID, "zealy_import_winners", map[string]any{
+		"TotalInserted": totalInserted,
+	})
 }
 
 func readCSV(path string) ([][]string, error) {
@@ -1,12 +1,14 @@
 package zealy
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
 
 	"github.com/pagu-project/Pagu/database"
 	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/lock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,7 +21,7 @@ func TestImportWinnersHandler(t *testing.T) {
 		tempFile := createTempFile(t, tempPath, csvData)
 
 		cmd := zealy.GetCommand()
-		expectedRes := zealy.importWinnersHandler(cmd, command.AppIdCLI, "", tempFile.Name())
+		expectedRes := zealy.importWinnersHandler(context.Background(), cmd, command.AppIdCLI, "", tempFile.Name())
 
 		assert.Equal(t, true, expectedRes.Successful)
 		assert.Equal(t, "Imported successfully\nTotal inserted: 3", expectedRes.Message)
@@ -40,7 +42,7 @@ func TestImportWinnersHandler(t *testing.T) {
 		tempFile := createTempFile(t, tempPath, csvData)
 
 		cmd := zealy.GetCommand()
-		expectedRes := zealy.importWinnersHandler(cmd, command.AppIdCLI, "", tempFile.Name())
+		expectedRes := zealy.importWinnersHandler(context.Background(), cmd, command.AppIdCLI, "", tempFile.Name())
 
 		assert.Equal(t, false, expectedRes.Successful)
 		assert.Equal(t, "An error occurred: duplicate zealy user with discord ID: id3", expectedRes.Message)
@@ -58,7 +60,7 @@ func TestImportWinnersHandler(t *testing.T) {
 		zealy := setup(t)
 
 		cmd := zealy.GetCommand()
-		expectedRes := zealy.importWinnersHandler(cmd, command.AppIdCLI, "")
+		expectedRes := zealy.importWinnersHandler(context.Background(), cmd, command.AppIdCLI, "")
 
 		assert.Equal(t, false, expectedRes.Successful)
 		assert.Equal(t, "please specify a file path to import", expectedRes.Message)
@@ -68,7 +70,8 @@ func TestImportWinnersHandler(t *testing.T) {
 		zealy := setup(t)
 
 		cmd := zealy.GetCommand()
-		expectedRes := zealy.importWinnersHandler(cmd, command.AppIdCLI, "", "fake_csv_file_address")
+		expectedRes := zealy.importWinnersHandler(context.Background(), cmd, command.AppIdCLI, "",
+			"fake_csv_file_address")
 
 		assert.Equal(t, false, expectedRes.Successful)
 		assert.Equal(t, "An error occurred: csv file is not valid", expectedRes.Message)
@@ -83,7 +86,7 @@ func setup(t *testing.T) *Zealy {
 	db, err := database.NewDB(dbFile.Name())
 	require.NoError(t, err)
 
-	zealy := NewZealy(db, nil)
+	zealy := NewZealy(db, nil, lock.NewLocker(db, "test"), "")
 	return &zealy
 }
 
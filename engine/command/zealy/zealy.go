@@ -1,8 +1,13 @@
 package zealy
 
 import (
+	"context"
+	"time"
+
+	"github.com/pactus-project/pactus/types/amount"
 	"github.com/pagu-project/Pagu/database"
 	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/lock"
 	"github.com/pagu-project/Pagu/wallet"
 )
 
@@ -14,17 +19,24 @@ const (
 	HelpCommandName          = "help"
 )
 
+// claimLockTTL is longer than a transfer, so the claim is not paid twice by two instances.
+const claimLockTTL = 2 * time.Minute
+
 type Zealy struct {
-	db     *database.DB
-	wallet *wallet.Wallet
+	db         *database.DB
+	wallet     *wallet.Wallet
+	locker     *lock.Locker
+	rewardMemo string // Memo template of the reward transactions.
 }
 
 func NewZealy(
-	db *database.DB, wallet *wallet.Wallet,
+	db *database.DB, wallet *wallet.Wallet, locker *lock.Locker, rewardMemo string,
 ) Zealy {
 	return Zealy{
-		db:     db,
-		wallet: wallet,
+		db:         db,
+		wallet:     wallet,
+		locker:     locker,
+		rewardMemo: rewardMemo,
 	}
 }
 
@@ -38,10 +50,14 @@ func (z *Zealy) GetCommand() command.Command {
 				Name:     "address",
 				Desc:     "Your Pactus address",
 				Optional: false,
+				Kind:     command.ArgAddress,
 			},
 		},
 		SubCommands: nil,
 		AppIDs:      command.AllAppIDs(),
+		Challenge:   true,
+		Mutating:    true,
+		Ephemeral:   true,
 		Handler:     z.claimHandler,
 	}
 
@@ -82,6 +98,7 @@ func (z *Zealy) GetCommand() command.Command {
 		},
 		SubCommands: nil,
 		AppIDs:      []command.AppID{command.AppIdCLI},
+		Mutating:    true,
 		Handler:     z.importWinnersHandler,
 	}
 
@@ -89,32 +106,57 @@ func (z *Zealy) GetCommand() command.Command {
 	return cmdZealy
 }
 
-func (z *Zealy) claimHandler(cmd command.Command, _ command.AppID, callerID string, args ...string) command.CommandResult {
-	user, err := z.db.GetZealyUser(callerID)
-	if err != nil {
-		return cmd.ErrorResult(err)
-	}
+func (z *Zealy) claimHandler(_ context.Context, cmd command.Command, _ command.AppID, callerID string,
+	args ...string,
+) command.CommandResult {
+	var user *database.ZealyUser
+	txHash, memo := "", ""
+	err := z.locker.WithLock("zealy-claim:"+callerID, claimLockTTL, func() error {
+		var err error
+		user, err = z.db.GetZealyUser(callerID)
+		if err != nil {
+			return err
+		}
 
-	if user.IsClaimed() {
-		return cmd.FailedResult("You already claimed your reward: https://pacviewer.com/transaction/%s",
-			user.TxHash)
-	}
+		if user.IsClaimed() {
+			return nil
+		}
+
+		address := args[0]
+		memo = wallet.RenderMemo(z.rewardMemo, wallet.MemoValues{
+			ClaimID: user.ID,
+			Address: address,
+			Amount:  user.Amount,
+		})
+		txHash, err = z.wallet.TransferTransaction(address, memo, user.Amount)
+		if err != nil {
+			return err
+		}
 
-	address := args[0]
-	txHash, err := z.wallet.TransferTransaction(address, "PaGu Zealy reward distribution", int64(user.Amount))
+		return z.db.UpdateZealyUser(callerID, txHash, memo)
+	})
 	if err != nil {
 		return cmd.ErrorResult(err)
 	}
 
-	if err = z.db.UpdateZealyUser(callerID, txHash); err != nil {
-		return cmd.ErrorResult(err)
+	if txHash == "" {
+		// the claims before the memo templates have no memo.
+		if user.Memo == "" {
+			return cmd.FailedResult("You already claimed your reward: %s",
+				command.TransactionURL(user.TxHash))
+		}
+
+		return cmd.FailedResult("You already claimed your reward: %s\nMemo: %s",
+			command.TransactionURL(user.TxHash), user.Memo)
 	}
 
-	return cmd.SuccessfulResult("Zealy reward claimed successfully: https://pacviewer.com/transaction/%s",
-		txHash)
+	return cmd.SuccessfulResult("Zealy reward claimed successfully: %s\nMemo: %s",
+		command.TransactionURL(txHash), memo)
 }
 
-func (z *Zealy) statusHandler(cmd command.Command, _ command.AppID, _ string, args ...string) command.CommandResult {
+func (z *Zealy) statusHandler(_ context.Context, cmd command.Command, appID command.AppID, _ string,
+	args ...string,
+) command.CommandResult {
 	allUsers, err := z.db.GetAllZealyUser()
 	if err != nil {
 		return cmd.ErrorResult(err)
@@ -124,25 +166,29 @@ func (z *Zealy) statusHandler(cmd command.Command, _ command.AppID, _ string, ar
 	totalClaimed := 0
 	totalNotClaimed := 0
 
-	totalAmount := 0
-	totalClaimedAmount := 0
-	totalNotClaimedAmount := 0
+	// the amounts of the users are in PAC.
+	var totalAmount, totalClaimedAmount, totalNotClaimedAmount amount.Amount
 
 	for _, u := range allUsers {
 		total++
-		totalAmount += int(u.Amount)
+		userAmount := amount.Amount(u.Amount * 1e9)
+		totalAmount += userAmount
 
 		if u.IsClaimed() {
 			totalClaimed++
-			totalClaimedAmount += int(u.Amount)
+			totalClaimedAmount += userAmount
 		} else {
 			totalNotClaimed++
-			totalNotClaimedAmount += int(u.Amount)
+			totalNotClaimedAmount += userAmount
 		}
 	}
 
-	return cmd.SuccessfulResult("Total Users: %v\nTotal Claims: %v\nTotal not remained claims: %v\nTotal Coins: %v PAC\n"+
-		"Total claimed coins: %v PAC\nTotal not claimed coins: %v PAC\n", total, totalClaimed, totalNotClaimed,
-		totalAmount, totalClaimedAmount, totalNotClaimedAmount,
-	)
+	return cmd.RenderResult(appID, "zealy_status", map[string]any{
+		"Total":                 total,
+		"TotalClaimed":          totalClaimed,
+		"TotalNotClaimed":       totalNotClaimed,
+		"TotalAmount":           totalAmount,
+		"TotalClaimedAmount":    totalClaimedAmount,
+		"TotalNotClaimedAmount": totalNotClaimedAmount,
+	})
 }
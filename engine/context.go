@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/engine/command/network"
+)
+
+// followUp is the command that answers the follow-up questions about a reference,
+// the reference value is passed as the command argument.
+type followUp struct {
+	path  []string
+	words []string
+}
+
+var followUps = map[string]followUp{
+	command.ReferenceValidator: {
+		path: []string{network.CommandName, network.NodeInfoCommandName},
+		words: []string{
+			"it", "its", "stake", "score", "availability", "bond", "bonding",
+			"sortition", "moniker", "validator", "node", "info",
+		},
+	},
+}
+
+type contextEntry struct {
+	reference command.Reference
+	expiresAt time.Time
+}
+
+// contextStore keeps the last reference of each caller for a short time,
+// so a follow-up message can be resolved without repeating the reference.
+type contextStore struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]contextEntry
+}
+
+// newContextStore creates the context store, a zero TTL disables the follow-ups.
+func newContextStore(ttl time.Duration) *contextStore {
+	return &contextStore{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]contextEntry),
+	}
+}
+
+func contextKey(appID command.AppID, callerID string) string {
+	return appID.String() + ":" + callerID
+}
+
+// Remember keeps the reference of the caller, it replaces the previous one.
+func (s *contextStore) Remember(appID command.AppID, callerID string, ref command.Reference) {
+	if s == nil || s.ttl <= 0 || callerID == "" {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+
+	s.entries[contextKey(appID, callerID)] = contextEntry{
+		reference: ref,
+		expiresAt: now.Add(s.ttl),
+	}
+}
+
+// Recall returns the last reference of the caller if it's not expired.
+func (s *contextStore) Recall(appID command.AppID, callerID string) (command.Reference, bool) {
+	if s == nil {
+		return command.Reference{}, false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entry, ok := s.entries[contextKey(appID, callerID)]
+	if !ok || s.now().After(entry.expiresAt) {
+		return command.Reference{}, false
+	}
+
+	return entry.reference, true
+}
+
+// followUpTokens returns the command tokens of a follow-up message, like: "and its stake?".
+// It returns false if the caller has no recent reference or the message is not about it.
+func (be *BotEngine) followUpTokens(appID command.AppID, callerID string, tokens []string) ([]string, bool) {
+	ref, ok := be.contexts.Recall(appID, callerID)
+	if !ok {
+		return nil, false
+	}
+
+	fu, ok := followUps[ref.Kind]
+	if !ok {
+		return nil, false
+	}
+
+	for _, token := range tokens {
+		word := strings.ToLower(strings.Trim(token, "?!.,;:'\""))
+		if slices.Contains(fu.words, word) {
+			return append(slices.Clone(fu.path), ref.Value), true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// DeprecationStore keeps the calls to the deprecated commands across the restarts.
+type DeprecationStore interface {
+	AddDeprecatedCall(name string, at time.Time) error
+	GetDeprecatedCalls() (map[string]int64, error)
+}
+
+// observeDeprecated counts a call to the deprecated command in the metrics and in the store.
+func (be *BotEngine) observeDeprecated(ctx context.Context, name string) {
+	be.metrics.ObserveDeprecated(name)
+	if be.deprecations == nil {
+		return
+	}
+
+	if err := be.deprecations.AddDeprecatedCall(name, time.Now()); err != nil {
+		log.Ctx(ctx).Warn("can't count the call to the deprecated command", "err", err, "command", name)
+	}
+}
+
+// deprecatedCalls returns the calls to each deprecated command, the metrics count them since the start
+// if there is no store.
+func (be *BotEngine) deprecatedCalls() (map[string]int64, error) {
+	if be.deprecations == nil {
+		return be.metrics.DeprecatedUsage(), nil
+	}
+
+	return be.deprecations.GetDeprecatedCalls()
+}
+
+// sunsetResult is the result of a deprecated command after its sunset, it doesn't run anymore.
+func sunsetResult(cmd command.Command, name string) command.CommandResult {
+	if cmd.ReplacedBy == "" {
+		return cmd.FailedResult("The `%s` command is removed.", name).WithCode(command.ErrCodeDisabled)
+	}
+
+	return cmd.FailedResult("The `%s` command is removed, please use `%s` instead.", name, cmd.ReplacedBy).
+		WithCode(command.ErrCodeDisabled)
+}
@@ -2,61 +2,206 @@ package engine
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/pactus-project/pactus/types/amount"
+	pagu "github.com/pagu-project/Pagu"
+	"github.com/pagu-project/Pagu/abuse"
+	"github.com/pagu-project/Pagu/accounts"
+	"github.com/pagu-project/Pagu/backup"
+	"github.com/pagu-project/Pagu/cache"
+	"github.com/pagu-project/Pagu/challenge"
 	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/committee"
+	"github.com/pagu-project/Pagu/concentration"
 	"github.com/pagu-project/Pagu/config"
 	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/digest"
+	"github.com/pagu-project/Pagu/discovery"
 	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/engine/command/account"
+	"github.com/pagu-project/Pagu/engine/command/admin"
+	"github.com/pagu-project/Pagu/engine/command/alias"
 	"github.com/pagu-project/Pagu/engine/command/blockchain"
+	"github.com/pagu-project/Pagu/engine/command/feedback"
+	"github.com/pagu-project/Pagu/engine/command/link"
+	marketcmd "github.com/pagu-project/Pagu/engine/command/market"
 	"github.com/pagu-project/Pagu/engine/command/network"
+	"github.com/pagu-project/Pagu/engine/command/node"
 	phoenixtestnet "github.com/pagu-project/Pagu/engine/command/phoenix"
+	"github.com/pagu-project/Pagu/engine/command/preference"
+	"github.com/pagu-project/Pagu/engine/command/privacy"
+	"github.com/pagu-project/Pagu/engine/command/rewards"
+	"github.com/pagu-project/Pagu/engine/command/subscribe"
+	"github.com/pagu-project/Pagu/engine/command/transaction"
+	"github.com/pagu-project/Pagu/engine/command/validator"
+	"github.com/pagu-project/Pagu/engine/command/version"
 	"github.com/pagu-project/Pagu/engine/command/zealy"
+	"github.com/pagu-project/Pagu/engine/plugin"
+	"github.com/pagu-project/Pagu/feature"
+	"github.com/pagu-project/Pagu/flood"
+	"github.com/pagu-project/Pagu/fork"
+	"github.com/pagu-project/Pagu/i18n"
+	"github.com/pagu-project/Pagu/indexer"
+	"github.com/pagu-project/Pagu/intent"
+	"github.com/pagu-project/Pagu/lock"
 	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/maintenance"
+	"github.com/pagu-project/Pagu/market"
+	"github.com/pagu-project/Pagu/metrics"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/release"
+	"github.com/pagu-project/Pagu/report"
+	"github.com/pagu-project/Pagu/retention"
+	"github.com/pagu-project/Pagu/scheduler"
+	"github.com/pagu-project/Pagu/settings"
+	"github.com/pagu-project/Pagu/statuspage"
+	"github.com/pagu-project/Pagu/telemetry"
+	"github.com/pagu-project/Pagu/treasury"
+	"github.com/pagu-project/Pagu/uptime"
+	"github.com/pagu-project/Pagu/utils"
 	"github.com/pagu-project/Pagu/wallet"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 )
 
+// quotaWindow is the window of the guild quotas, the quotas are reset at the midnight of UTC.
+const quotaWindow = 24 * time.Hour
+
 type BotEngine struct {
 	ctx    context.Context //nolint
 	cancel context.CancelFunc
 
 	clientMgr        *client.Mgr
 	phoenixClientMgr *client.Mgr
+	metrics          *metrics.Metrics
+	challenges       *challenge.Manager
+	contexts         *contextStore
+	prompts          *promptStore
+	blockCache       *blockCache
+	intents          intent.Matcher
+	backup           *backup.Backup
+	cache            cache.Store
+	limiter          *cache.Limiter
+	quota            *cache.Limiter // The daily quota of the expensive commands in a guild.
+	quotaLimit       int64
+	commandTimeout   time.Duration // The timeout of the commands without their own timeout, zero disables it.
+	flood            *flood.Guard  // The flood protection of the messages that the chat adapters receive.
+	notifier         *notify.Hub
+	tracker          *market.Tracker
+	indexer          *indexer.Indexer
+	scheduler        *scheduler.Scheduler
+	features         *feature.Flags
+	settings         *settings.Settings
+	maintenance      *maintenance.Maintenance
+	traffic          *trafficRecorder
+	shutdown         func(context.Context) error // Flushes the traces and the metrics, nil without the telemetry.
 	rootCmd          command.Command
+	authIDs          map[command.AppID][]string // The admin IDs of each platform.
+	serverAdmins     map[command.AppID]ServerAdmins
+	serverAdminsLock sync.RWMutex
+	deprecations     DeprecationStore // The calls to the deprecated commands, nil counts them only in the metrics.
 
 	blockchainCmd blockchain.Blockchain
 	networkCmd    network.Network
 	phoenixCmd    phoenixtestnet.Phoenix
 	zealyCmd      zealy.Zealy
+	rewardsCmd    rewards.Rewards
+	linkCmd       link.Link
+	privacyCmd    privacy.Privacy
+	txCmd         transaction.Transaction
+	marketCmd     marketcmd.Market
+	subscribeCmd  subscribe.Subscribe
+	versionCmd    version.Version
+	accountCmd    account.Account
+	nodeCmd       node.Node
+	validatorCmd  validator.Validator
+	feedbackCmd   feedback.Feedback
+	preferenceCmd preference.Preference
+	aliasCmd      alias.Alias
+	adminCmd      admin.Admin
+	plugins       []plugin.CommandProvider
 }
 
 func NewBotEngine(cfg *config.Config) (*BotEngine, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// ? adding main network client manager.
-	cm := client.NewClientMgr(ctx)
+	// ? exporting the traces and the metrics by OTLP, if a collector is set.
+	shutdownTelemetry, err := telemetry.Setup(ctx, telemetry.Config{
+		Endpoint:    cfg.Telemetry.Endpoint,
+		Insecure:    cfg.Telemetry.Insecure,
+		SampleRatio: cfg.Telemetry.SampleRatio,
+		Service:     "pagu",
+		Instance:    cfg.InstanceID,
+		Version:     pagu.StringVersion(),
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// ? collecting command and RPC metrics for the SLO reports.
+	mtr := metrics.NewMetrics()
+	rpcMetrics := grpc.WithChainUnaryInterceptor(mtr.UnaryClientInterceptor(), telemetry.UnaryClientInterceptor())
 
-	localClient, err := client.NewClient(cfg.LocalNode)
+	// ? the cache store is shared by the instances if it's Redis.
+	store, err := cache.NewStore(cache.Config{
+		Driver:        cfg.Cache.Driver,
+		RedisAddress:  cfg.Cache.RedisAddress,
+		RedisPassword: cfg.Cache.RedisPassword,
+		RedisDB:       int(cfg.Cache.RedisDB),
+	})
 	if err != nil {
 		cancel()
 		return nil, err
 	}
+	log.Info("cache store created", "driver", cfg.Cache.Driver)
+
+	// ? adding main network client manager.
+	// the local node receives all the calls, unless the nodes have weights like: "localhost:50051=90".
+	// the clients of a node share its pooled connections, like the local node that is a network node too.
+	pool := client.NewPool(cfg.RPCClient, rpcMetrics)
 
-	cm.AddClient(localClient)
+	cm := client.NewClientMgr(ctx)
+	cm.SetStickiness(cfg.NodeStickiness)
+	cm.SetCache(store, cfg.Cache.TTL)
+
+	if err := addWeightedClient(cm, pool, cfg.LocalNode, 1); err != nil {
+		cancel()
+		return nil, err
+	}
 
 	for _, nn := range cfg.NetworkNodes {
-		c, err := client.NewClient(nn)
-		if err != nil {
-			log.Error("can't add new network node client", "err", err, "addr", nn)
+		if err := addWeightedClient(cm, pool, nn, 0); err != nil {
+			log.Error("can't add new network node client", "err", err, "addr", client.RedactEndpoint(nn))
 		}
-		cm.AddClient(c)
 	}
 
+	// the discovered nodes are added to the client manager while it runs.
+	disc := discovery.NewDiscovery(cm, func(endpoint string) (client.IClient, error) {
+		c, err := pool.NewClient(endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}, cfg.Discovery.Seeds, int(cfg.Discovery.Port), int(cfg.Discovery.Weight), int(cfg.Discovery.MaxNodes),
+		cfg.Discovery.Interval)
+
 	// ? adding phoenix test network client manager.
 	phoenixCm := client.NewClientMgr(ctx)
 	for _, tnn := range cfg.Phoenix.NetworkNodes {
-		c, err := client.NewClient(tnn)
+		c, err := pool.NewClient(tnn)
 		if err != nil {
-			log.Error("can't add new network node client", "err", err, "addr", tnn)
+			log.Error("can't add new network node client", "err", err, "addr", client.RedactEndpoint(tnn))
 		}
 
 		phoenixCm.AddClient(c)
@@ -81,15 +226,52 @@ func NewBotEngine(cfg *config.Config) (*BotEngine, error) {
 	var phoenixWal *wallet.Wallet
 	if cfg.TestNetWallet.Enable {
 		// load or create wallet.
-		wal = wallet.Open(&cfg.TestNetWallet)
-		if wal == nil {
+		phoenixWal = wallet.Open(&cfg.TestNetWallet)
+		if phoenixWal == nil {
 			cancel()
 			return nil, WalletError{
 				Reason: "can't open testnet wallet",
 			}
 		}
 
-		log.Info("testnet wallet opened successfully", "address", wal.Address())
+		log.Info("testnet wallet opened successfully", "address", phoenixWal.Address())
+	}
+
+	// ? loading custom message templates, the embedded defaults are used for the missing ones.
+	if cfg.TemplatesPath != "" {
+		if err := command.LoadTemplates(cfg.TemplatesPath); err != nil {
+			log.Error("can't load custom templates", "err", err, "path", cfg.TemplatesPath)
+		}
+	}
+
+	// ? selecting the output theme of each platform.
+	themeName := cfg.Theme.Name
+	if themeName == "" {
+		themeName = command.ThemeEmoji
+	}
+
+	if err := command.SetThemes(themeName, cfg.Theme.Overrides); err != nil {
+		cancel()
+		return nil, err
+	}
+	command.SetPlainText(cfg.Theme.PlainText)
+
+	// ? the block explorer that the outputs link to.
+	command.SetExplorer(cfg.ExplorerURL)
+	command.SetAllowedHosts(cfg.AllowedHosts)
+
+	// ? the precision and the unit of the amounts in the outputs.
+	amountUnit := cfg.Amount.Unit
+	if amountUnit == "" {
+		amountUnit = utils.UnitPAC
+	}
+
+	if err := command.SetAmountFormat(utils.AmountFormat{
+		Precision: int(cfg.Amount.Precision),
+		Unit:      amountUnit,
+	}); err != nil {
+		cancel()
+		return nil, err
 	}
 
 	// ? loading database.
@@ -100,10 +282,264 @@ func NewBotEngine(cfg *config.Config) (*BotEngine, error) {
 	}
 	log.Info("database loaded successfully")
 
-	return newBotEngine(cm, phoenixCm, wal, phoenixWal, db, cfg.AuthIDs, ctx, cancel), nil
+	abuseCfg := abuse.Config{
+		MinAccountAge:    cfg.FaucetAbuse.MinAccountAge,
+		MaxAddressUsers:  cfg.FaucetAbuse.MaxAddressUsers,
+		MaxUserAddresses: cfg.FaucetAbuse.MaxUserAddresses,
+		BlockedASNs:      cfg.FaucetAbuse.BlockedASNs,
+		ReviewHosting:    cfg.FaucetAbuse.ReviewHosting,
+	}
+
+	// ? coordinating the instances, so the scheduled jobs and payouts run once.
+	// the locks are in Redis if the cache is Redis, so the instances on other hosts share them too.
+	owner := cfg.InstanceID
+	if owner == "" {
+		owner = lock.DefaultOwner()
+	}
+	var lockStore lock.Store = db
+	if redisStore, ok := store.(*cache.RedisStore); ok {
+		lockStore = redisStore
+	}
+	locker := lock.NewLocker(lockStore, owner)
+	log.Info("instance locker created", "owner", owner, "driver", cfg.Cache.Driver)
+
+	bkp := backup.NewBackup(backup.Config{
+		Path:     cfg.Backup.Path,
+		Interval: cfg.Backup.Interval,
+		Keep:     int(cfg.Backup.Keep),
+	}, db)
+	bkp.SetLocker(locker)
+
+	// ? the platform adapters register their notifiers to send direct messages, like the price alerts.
+	hub := notify.NewHub()
+	priceURL := cfg.Market.PriceURL
+	if priceURL == "" {
+		priceURL = market.DefaultPriceURL
+	}
+	tracker := market.NewTracker(market.NewXeggex(priceURL), db, hub, cfg.Market.PollInterval)
+	fiat, err := market.NewFiat(tracker, cfg.Market.Rates)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	releaseChannels, err := notify.ParseChannels(cfg.Release.Channels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	releaseURL := cfg.Release.URL
+	if releaseURL == "" {
+		releaseURL = release.DefaultReleaseURL
+	}
+	watcher := release.NewWatcher(release.NewGitHub(releaseURL), db, hub, releaseChannels, cfg.Release.PollInterval)
+
+	forkChannels, err := notify.ParseChannels(cfg.Fork.AlertChannels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	forks := fork.NewChecker(cm, db, hub, forkChannels, uint32(cfg.Fork.CheckBlocks))
+
+	committeeChannels, err := notify.ParseChannels(cfg.Committee.Channels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	wins := committee.NewAnnouncer(cm, db, hub, committeeChannels, uint32(cfg.Committee.BatchBlocks))
+
+	milestoneChannels, err := notify.ParseChannels(cfg.Accounts.MilestoneChannels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	spikeChannels, err := notify.ParseChannels(cfg.Accounts.SpikeChannels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	accountsMonitor := accounts.NewMonitor(cm, db, hub, milestoneChannels, spikeChannels,
+		int32(cfg.Accounts.MilestoneStep), cfg.Accounts.SpikeFactor, int32(cfg.Accounts.SpikeMin))
+
+	reportChannels, err := notify.ParseChannels(cfg.Report.Channels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	reporter := report.NewReporter(cm, db, hub, reportChannels, cfg.Report.Weekday, int(cfg.Report.Hour))
+
+	powerChannels, err := notify.ParseChannels(cfg.Concentration.AlertChannels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	power := concentration.NewMonitor(cm, db, hub, powerChannels,
+		int(cfg.Concentration.TopValidators), cfg.Concentration.MaxShare, uint32(cfg.Concentration.EpochBlocks))
+
+	treasuryChannels, err := notify.ParseChannels(cfg.Treasury.AlertChannels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	spending := treasury.NewMonitor(db, hub, treasuryChannels, client.TreasuryAddresses(),
+		cfg.Treasury.AlertAmount*1e9)
+
+	pruner, err := retention.NewPruner(db, cfg.Retention.Interval, cfg.Retention.Days)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	atRisk := network.ScoreRange{
+		Min: cfg.AtRiskScore.Min,
+		Max: cfg.AtRiskScore.Max,
+	}
+
+	// ? the feature flags and the settings are in the database, so they are changed on all the instances.
+	features := feature.NewFlags(db)
+	setts := settings.NewSettings(db)
+	hub.SetChannelSource(setts.ServerChannels)
+	if cfg.ReadOnly {
+		setts.ForceReadOnly()
+	}
+
+	maintenanceChannels, err := notify.ParseChannels(cfg.Maintenance.AlertChannels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	maint := maintenance.NewMaintenance(setts, db, hub, maintenanceChannels, cfg.Maintenance.RemindBefore)
+
+	// ? the admin IDs are scoped by the platform, the callers of gRPC, HTTP and the queue choose their IDs.
+	authIDs := map[command.AppID][]string{
+		command.AppIdDiscord:  cfg.AuthIDs,
+		command.AppIdTelegram: cfg.Telegram.AuthIDs,
+	}
+
+	be := newBotEngine(cm, phoenixCm, wal, phoenixWal, db, mtr, atRisk, forks, power, bkp, locker, features,
+		setts, maint, abuseCfg, cfg.Phoenix.FaucetMemo, cfg.Zealy.RewardMemo, cfg.SLOTarget, authIDs, ctx, cancel)
+	be.challenges = newChallengeManager(cfg.Challenge)
+	be.contexts = newContextStore(cfg.ContextTTL)
+	be.prompts = newPromptStore(cfg.PromptTTL)
+	be.commandTimeout = cfg.CommandTimeout
+	be.intents = newIntentMatcher(cfg.NLP)
+	be.cache = store
+	be.shutdown = shutdownTelemetry
+	be.blockCache = newBlockCache(store, cm.GetBlockchainHeight)
+	be.limiter = cache.NewLimiter(store, cfg.Cache.RateLimit, cfg.Cache.RateLimitWindow)
+	be.quota = cache.NewLimiter(store, cfg.Cache.GuildQuota, quotaWindow)
+	be.quotaLimit = cfg.Cache.GuildQuota
+	be.notifier = hub
+	be.tracker = tracker
+	be.marketCmd = marketcmd.NewMarket(tracker, db, cfg.Market.MaxAlerts)
+	be.preferenceCmd = preference.NewPreference(db, fiat)
+	be.aliasCmd = alias.NewAlias(db)
+	be.subscribeCmd = subscribe.NewSubscribe(db, cfg.MaxWatched)
+	be.versionCmd = version.NewVersion(watcher)
+	be.accountCmd = account.NewAccount(cm, be.indexer)
+	be.nodeCmd = node.NewNode(cm)
+	up := uptime.NewUptime(cm, db, hub)
+	be.validatorCmd = validator.NewValidator(cm, atRisk.Max, up, be.indexer)
+
+	feedbackChannels, err := notify.ParseChannels(cfg.Feedback.Channels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	be.feedbackCmd = feedback.NewFeedback(db, hub, feedbackChannels)
+
+	floodChannels, err := notify.ParseChannels(cfg.Flood.AlertChannels)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	be.flood = flood.NewGuard(store, cfg.Flood.Messages, cfg.Flood.Window, cfg.Flood.Mute, hub, floodChannels)
+
+	// ? the scheduled jobs, the exclusive ones run on one of the instances. They are paused in the maintenance.
+	be.scheduler = scheduler.NewScheduler(locker)
+	be.scheduler.SetPause(maint.Paused)
+	be.scheduler.Add(maint.Job())
+	be.scheduler.Add(be.blockCache.Job())
+	be.scheduler.Add(digest.NewDigest(cm, db, tracker, hub).Job())
+	be.scheduler.Add(watcher.Job())
+	be.scheduler.Add(forks.Job())
+	be.scheduler.Add(wins.Job())
+	be.scheduler.Add(accountsMonitor.Job())
+	be.scheduler.Add(reporter.Job())
+	be.scheduler.Add(disc.Job())
+	be.scheduler.Add(power.Job())
+	be.scheduler.Add(spending.Job())
+	be.scheduler.Add(pruner.Job())
+	be.scheduler.Add(be.indexer.Job())
+	be.scheduler.Add(be.indexer.HealthJob())
+	for _, job := range up.Jobs() {
+		be.scheduler.Add(job)
+	}
+
+	// ? the public status page, rendered from the outputs of the commands.
+	statusWriters, err := statuspage.NewWriters(cfg.StatusPage)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	be.scheduler.Add(statuspage.NewGenerator(be, statusWriters, cfg.StatusPage.Commands, cfg.StatusPage.Interval).Job())
+
+	// ? loading the command groups of the compiled in plugins.
+	be.plugins, err = plugin.Load(plugin.Dependencies{
+		Ctx:       ctx,
+		ClientMgr: cm,
+		DB:        db,
+		Notifier:  hub,
+		Scheduler: be.scheduler,
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	log.Info("plugins loaded", "plugins", plugin.Names())
+
+	// ? recording the commands, so the benchmarks can replay the real traffic.
+	if cfg.TrafficPath != "" {
+		be.traffic, err = newTrafficRecorder(cfg.TrafficPath)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		log.Info("recording the commands", "path", cfg.TrafficPath)
+	}
+
+	return be, nil
 }
 
-func newBotEngine(cm, ptcm *client.Mgr, wallet *wallet.Wallet, phoenixWal *wallet.Wallet, db *database.DB, _ []string,
+// addWeightedClient connects to the node endpoint on the connections of the pool and adds it to the client manager.
+// The default weight is used if the endpoint has no weight.
+func addWeightedClient(cm *client.Mgr, pool *client.Pool, node string, defaultWeight int) error {
+	endpoint, weight, err := client.ParseEndpoint(node)
+	if err != nil {
+		return err
+	}
+
+	if weight < 0 {
+		weight = defaultWeight
+	}
+
+	c, err := pool.NewClient(endpoint)
+	if err != nil {
+		return err
+	}
+
+	cm.AddWeightedClient(c, weight)
+	log.Info("node client added", "addr", client.RedactEndpoint(endpoint), "weight", weight)
+
+	return nil
+}
+
+func newBotEngine(cm, ptcm *client.Mgr, wallet *wallet.Wallet, phoenixWal *wallet.Wallet, db *database.DB,
+	mtr *metrics.Metrics, atRisk network.ScoreRange, forks *fork.Checker, power *concentration.Monitor,
+	bkp *backup.Backup, locker *lock.Locker, features *feature.Flags, setts *settings.Settings,
+	maint *maintenance.Maintenance, abuseCfg abuse.Config, faucetMemo, rewardMemo string,
+	sloTarget float64, authIDs map[command.AppID][]string,
 	ctx context.Context, cnl context.CancelFunc,
 ) *BotEngine {
 	rootCmd := command.Command{
@@ -112,24 +548,42 @@ func newBotEngine(cm, ptcm *client.Mgr, wallet *wallet.Wallet, phoenixWal *walle
 		Desc:        "Root Command",
 		Help:        "Pagu Help Command",
 		AppIDs:      command.AllAppIDs(),
-		SubCommands: make([]command.Command, 3),
+		SubCommands: make([]command.Command, 0),
 	}
 
-	netCmd := network.NewNetwork(ctx, cm)
+	idx := indexer.NewIndexer(cm, db)
+	netCmd := network.NewNetwork(cm, idx, forks, power, atRisk, db)
 	bcCmd := blockchain.NewBlockchain(cm)
-	ptCmd := phoenixtestnet.NewPhoenix(phoenixWal, ptcm, *db)
-	zCmd := zealy.NewZealy(db, wallet)
+	ptCmd := phoenixtestnet.NewPhoenix(phoenixWal, ptcm, *db, abuse.NewDetector(abuseCfg, db), locker,
+		faucetMemo)
+	zCmd := zealy.NewZealy(db, wallet, locker, rewardMemo)
+	txCmd := transaction.NewTransaction(cm)
+	adminCmd := admin.NewAdmin(cm, mtr, sloTarget, bkp, features, setts, maint)
 
 	return &BotEngine{
 		ctx:              ctx,
 		cancel:           cnl,
 		clientMgr:        cm,
+		metrics:          mtr,
+		backup:           bkp,
+		indexer:          idx,
+		features:         features,
+		settings:         setts,
+		maintenance:      maint,
 		rootCmd:          rootCmd,
+		authIDs:          authIDs,
+		serverAdmins:     make(map[command.AppID]ServerAdmins),
 		networkCmd:       netCmd,
 		blockchainCmd:    bcCmd,
 		phoenixCmd:       ptCmd,
 		phoenixClientMgr: ptcm,
 		zealyCmd:         zCmd,
+		rewardsCmd:       rewards.NewRewards(db),
+		linkCmd:          link.NewLink(db, cm),
+		privacyCmd:       privacy.NewPrivacy(db),
+		txCmd:            txCmd,
+		adminCmd:         adminCmd,
+		deprecations:     db,
 	}
 }
 
@@ -140,37 +594,490 @@ func (be *BotEngine) Commands() []command.Command {
 func (be *BotEngine) RegisterAllCommands() {
 	be.rootCmd.AddSubCommand(be.blockchainCmd.GetCommand())
 	be.rootCmd.AddSubCommand(be.networkCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.accountCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.nodeCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.validatorCmd.GetCommand())
 	be.rootCmd.AddSubCommand(be.zealyCmd.GetCommand())
-	// be.rootCmd.AddSubCommand(be.phoenixCmd.GetCommand()) // TODO: FIX WALLET ISSUE
+	be.rootCmd.AddSubCommand(be.rewardsCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.linkCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.privacyCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.privacyCmd.GetForgetCommand())
+	be.rootCmd.AddSubCommand(be.txCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.marketCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.subscribeCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.versionCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.feedbackCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.preferenceCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.aliasCmd.GetCommand())
+
+	// the triage of the feedbacks is in the admin commands.
+	be.adminCmd.SetCommandGroups(be.commandGroups)
+	be.adminCmd.SetServerChannels(be.isServerChannel)
+	be.adminCmd.SetDeprecatedCalls(be.deprecatedCalls)
+	adminCmd := be.adminCmd.GetCommand()
+	adminCmd.AddSubCommand(be.feedbackCmd.GetAdminCommand())
+	adminCmd.AddSubCommand(be.aliasCmd.GetAdminCommand())
+	be.rootCmd.AddSubCommand(adminCmd)
+	be.rootCmd.AddSubCommand(be.verifyCommand())
+	// the faucet of Phoenix sends from the testnet wallet, the commands are not added without it.
+	if be.phoenixCmd.Enabled() {
+		be.rootCmd.AddSubCommand(be.phoenixCmd.GetCommand())
+	}
+	be.registerPlugins()
 
 	be.rootCmd.AddHelpSubCommand()
+
+	be.registerAliases()
+}
+
+// commandGroups returns the names of the command groups, the top commands without the help.
+func (be *BotEngine) commandGroups() []string {
+	groups := make([]string, 0, len(be.rootCmd.SubCommands))
+	for _, cmd := range be.rootCmd.SubCommands {
+		if cmd.Name != command.HelpCommandName {
+			groups = append(groups, cmd.Name)
+		}
+	}
+
+	return groups
+}
+
+// registerPlugins adds the command groups of the plugins,
+// the groups with the name of a registered command are skipped.
+func (be *BotEngine) registerPlugins() {
+	for _, provider := range be.plugins {
+		cmd := provider.GetCommand()
+		if slices.ContainsFunc(be.rootCmd.SubCommands, func(sc command.Command) bool {
+			return sc.Name == cmd.Name
+		}) {
+			log.Error("the plugin command is registered already", "name", cmd.Name)
+
+			continue
+		}
+
+		be.rootCmd.AddSubCommand(cmd)
+	}
+}
+
+// registerAliases adds the localized command names of the i18n catalogs.
+func (be *BotEngine) registerAliases() {
+	catalogs, err := i18n.Catalogs()
+	if err != nil {
+		log.Error("can't load i18n catalogs", "err", err)
+
+		return
+	}
+
+	for _, catalog := range catalogs {
+		for path, names := range catalog.Aliases {
+			for _, name := range names {
+				alias := command.Alias{Locale: catalog.Locale, Name: name}
+				if err := be.rootCmd.AddAlias(strings.Fields(path), alias); err != nil {
+					log.Warn("can't register command alias", "err", err, "locale", catalog.Locale, "command", path)
+				}
+			}
+		}
+	}
+}
+
+// ObserveShard records the health of a shard of the Discord gateway, the admins see it in the SLO report.
+func (be *BotEngine) ObserveShard(shard int, connected bool, guilds int, latency time.Duration) {
+	be.metrics.ObserveShard(shard, connected, guilds, latency)
+}
+
+// PrefixedTokens returns the command tokens of a message that starts with the command prefix of the platform,
+// the adapters parse the messages with it. The scope is a server of the platform, like a Discord guild.
+func (be *BotEngine) PrefixedTokens(appID command.AppID, scope, msg string) ([]string, bool) {
+	return be.settings.PrefixedTokens(appID, scope, msg)
+}
+
+// AcceptsUpload checks if the command of the tokens takes an uploaded text file, like "validator bulk".
+// The adapters download the uploaded files only for these commands.
+func (be *BotEngine) AcceptsUpload(tokens []string) bool {
+	cmd, _, _ := be.getCommand(tokens)
+
+	return slices.ContainsFunc(cmd.Args, func(arg command.Args) bool { return arg.Upload })
 }
 
 func (be *BotEngine) Run(appID command.AppID, callerID string, tokens []string) command.CommandResult {
-	log.Debug("run command", "callerID", callerID, "inputs", tokens)
+	return be.RunInGuild(appID, "", callerID, tokens)
+}
+
+// RunInGuild runs the command of a server of the platform, like a Discord guild.
+// The expensive commands are counted in the daily quota of the guild.
+func (be *BotEngine) RunInGuild(appID command.AppID, guildID, callerID string, tokens []string) command.CommandResult {
+	tokens = command.SanitizeArgs(tokens)
+	be.traffic.Record(appID, callerID, tokens)
+
+	// ? the request ID is in the log lines and the trace of the command, and in its internal errors.
+	requestID := newRequestID()
+	ctx := log.WithRequestID(be.context(), requestID)
+
+	// ? the dispatch span is the root of the command, the checks and the handler are its children.
+	// the caller is not recorded, so the exported traces have no user IDs.
+	ctx, span := telemetry.Tracer().Start(ctx, "engine.dispatch", trace.WithAttributes(
+		attribute.String("pagu.app", appID.String()),
+		attribute.String("pagu.request_id", requestID),
+	))
+	defer span.End()
+
+	res := be.run(ctx, appID, guildID, callerID, tokens)
+	res.RequestID = requestID
+	span.SetAttributes(attribute.Bool("pagu.successful", res.Successful))
+	if res.Code != "" {
+		span.SetAttributes(attribute.String("pagu.code", string(res.Code)))
+	}
+	if res.Successful {
+		res.Message = be.preferenceCmd.WithFiat(ctx, appID, callerID, res.Message)
+		res.Message = be.aliasCmd.WithAliases(ctx, res.Message)
+	}
+	res.Message = command.ApplyOutputPolicy(res.Message)
+	if command.PlainText() || be.preferenceCmd.PlainText(appID, callerID) {
+		res.Title = command.ToPlainText(res.Title)
+		res.Message = command.ToPlainText(res.Message)
+	}
+
+	if !res.Successful && res.Code != "" {
+		log.Ctx(ctx).Debug("command failed", "code", res.Code, "callerID", callerID, "inputs", tokens)
+		be.metrics.ObserveError(string(res.Code))
+	}
+
+	// the users send the error id of an internal error in their reports, it's the request ID in the logs.
+	if res.Code == command.ErrCodeInternal {
+		log.Ctx(ctx).Warn("internal error", "result", res.Message, "callerID", callerID, "inputs", tokens)
+		res.Message += fmt.Sprintf("\n\nerror id: %s", requestID)
+	}
+
+	return res
+}
+
+// newRequestID returns a short random ID of a command, like: "3f9a1c07".
+func newRequestID() string {
+	id := make([]byte, 4)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// context returns the context of the engine, the spans of the commands start from it.
+func (be *BotEngine) context() context.Context {
+	if be.ctx == nil {
+		return context.Background()
+	}
+
+	return be.ctx
+}
+
+// run runs the command, the engine runs the resolved commands with it, so they are not recorded again.
+func (be *BotEngine) run(ctx context.Context, appID command.AppID, guildID, callerID string,
+	tokens []string,
+) command.CommandResult {
+	log.Ctx(ctx).Debug("run command", "callerID", callerID, "inputs", tokens)
+
+	if res, ok := be.answerPrompt(ctx, appID, guildID, callerID, tokens); ok {
+		return res
+	}
+
+	cmd, argsIndex, path := be.getCommand(tokens)
+	if len(path) == 0 && len(tokens) != 0 {
+		if followUp, ok := be.followUpTokens(appID, callerID, tokens); ok {
+			log.Ctx(ctx).Debug("resolved follow-up", "callerID", callerID, "tokens", followUp)
+
+			return be.run(ctx, appID, guildID, callerID, followUp)
+		}
+	}
+
+	commandPath := attribute.String("pagu.command", strings.Join(path, " "))
+	trace.SpanFromContext(ctx).SetAttributes(commandPath)
+
+	// the middleware span ends before the handler, or on the check that fails the command.
+	_, checks := telemetry.Tracer().Start(ctx, "engine.middleware", trace.WithAttributes(commandPath))
+	defer checks.End()
 
-	cmd, argsIndex := be.getCommand(tokens)
 	if !cmd.HasAppId(appID) {
-		return cmd.FailedResult("unauthorized appID: %v", appID)
+		return cmd.FailedResult("unauthorized appID: %v", appID).WithCode(command.ErrCodeUnauthorized)
+	}
+
+	isAdmin := be.isAdmin(appID, callerID)
+	// the admins of a server run the server admin commands for it, like the setup of a Telegram group.
+	serverAdmin := cmd.ServerAdmin && !isAdmin && be.isServerAdmin(appID, guildID, callerID)
+	if cmd.AdminOnly && !isAdmin && !serverAdmin {
+		return cmd.FailedResult("unauthorized caller: %v", callerID).WithCode(command.ErrCodeUnauthorized)
+	}
+
+	// the admins can run the commands in the maintenance, like to cancel it.
+	if window, ok := be.maintenance.Active(); ok && !isAdmin {
+		return cmd.FailedResult("Pagu is under maintenance until %s UTC, please try again later!",
+			window.End.Format("2006-01-02 15:04")).WithCode(command.ErrCodeMaintenance).
+			WithRetryAfter(time.Until(window.End))
+	}
+
+	// the read commands keep working in the read-only mode, like in an incident or a chain halt.
+	if cmd.Mutating && be.settings.ReadOnly() {
+		return cmd.FailedResult("Pagu is in the read-only mode, this command is disabled for now. " +
+			"The other commands are working!").WithCode(command.ErrCodeDisabled)
+	}
+
+	if !isAdmin && !be.allow(ctx, appID, callerID) {
+		retryAfter := be.limiter.RetryAfter()
+
+		return cmd.FailedResult("Too many commands, please try again in %s!", retryIn(retryAfter)).
+			WithCode(command.ErrCodeRateLimited).WithRetryAfter(retryAfter)
+	}
+
+	if cmd.Expensive && !isAdmin && !be.allowQuota(ctx, appID, guildID) {
+		retryAfter := be.quota.RetryAfter()
+
+		return cmd.FailedResult("This server used its daily quota of %d heavy commands, like this one. "+
+			"Please try again in %s, after the midnight of UTC!", be.guildQuota(appID, guildID), retryIn(retryAfter)).
+			WithCode(command.ErrCodeQuotaExceeded).WithRetryAfter(retryAfter)
+	}
+
+	if !be.features.CommandEnabled(path) {
+		return cmd.FailedResult("The `%s` command is disabled for now, please try again later!",
+			strings.Join(path, " ")).WithCode(command.ErrCodeDisabled)
+	}
+
+	// the calls after the sunset are counted too, the users that still call it are told of its replacement.
+	if cmd.Deprecated {
+		be.observeDeprecated(ctx, strings.Join(path, " "))
+		if cmd.IsSunset(time.Now()) {
+			return sunsetResult(cmd, strings.Join(path, " "))
+		}
+	}
+
+	// the admins of the bot run all the commands, like the setup of a server that disabled the admin commands.
+	if guildID != "" && len(path) != 0 && path[0] != command.HelpCommandName && !isAdmin && !serverAdmin &&
+		!be.settings.ServerAllows(appID, guildID, path[0]) {
+		return cmd.FailedResult("The `%s` commands are not enabled in this server.", path[0]).
+			WithCode(command.ErrCodeDisabled)
+	}
+
+	// Free-text questions are counted in the rate limit too, the matcher may call an LLM.
+	if len(path) == 0 && be.intents != nil && strings.TrimSpace(strings.Join(tokens, "")) != "" {
+		return be.intentResult(ctx, appID, guildID, callerID, isAdmin, tokens)
+	}
+
+	if cmd.Name == command.HelpCommandName {
+		return be.helpResult(appID, be.syntax(appID, guildID), isAdmin, path[:len(path)-1],
+			strings.Fields(strings.Join(tokens[len(path):], " ")))
 	}
 
 	if cmd.Handler == nil {
-		return cmd.HelpResult()
+		return cmd.HelpResultFor(appID, isAdmin)
 	}
 
-	args := tokens[argsIndex:]
+	// the results escape the arguments that they echo, in the markup of the platform.
+	args := cmd.CutArgs(tokens[argsIndex:])
+	cmd = cmd.WithEchoes(appID, args)
 	err := cmd.CheckArgs(args)
 	if err != nil {
-		return cmd.ErrorResult(err)
+		// a missing argument is asked on the chat platforms, like: "Please send the validator".
+		if res, prompted := be.promptResult(cmd, appID, callerID, tokens, args); prompted {
+			return res
+		}
+
+		return cmd.ArgsErrorResult(appID, be.syntax(appID, guildID), strings.Join(path, " "), err)
+	}
+
+	if serverAdmin && args[0] != guildID {
+		return cmd.FailedResult("You can only run it for this server, its ID is: %s", guildID).
+			WithCode(command.ErrCodeUnauthorized)
 	}
 
-	return cmd.Handler(cmd, appID, callerID, args...)
+	// the addresses of another network are rejected before the handler calls the nodes.
+	if err := cmd.CheckAddresses(args); err != nil {
+		return cmd.FailedResult("%v", err).WithCode(command.ErrorCodeOf(err))
+	}
+
+	if cmd.Challenge {
+		if res, challenged := be.challengeResult(cmd, appID, callerID, tokens); challenged {
+			return res
+		}
+	}
+
+	checks.End()
+
+	handlerCtx, handler := telemetry.Tracer().Start(ctx, "engine.handler", trace.WithAttributes(commandPath))
+	start := time.Now()
+	res := be.blockCache.Result(handlerCtx, cmd, appID, callerID, path, args, func() command.CommandResult {
+		return be.handle(handlerCtx, cmd, appID, callerID, args)
+	})
+	be.metrics.ObserveCommand(res.Successful, time.Since(start))
+	handler.SetAttributes(attribute.Bool("pagu.successful", res.Successful))
+	handler.End()
+
+	if res.Successful && res.Reference.Kind != "" {
+		be.contexts.Remember(appID, callerID, res.Reference)
+	}
+
+	if cmd.Deprecated {
+		res = withDeprecationHint(res, appID, strings.Join(path, " "), cmd.ReplacedBy)
+	}
+
+	return res
+}
+
+// handle runs the handler of the command in its timeout, so a handler that hangs on a slow node doesn't hang
+// the platform. The handler gets the context of the timeout, its late result is dropped.
+// The state-changing commands are not given up, like a payout that is sent, so the caller doesn't retry
+// a command that is still running. Their RPCs are bound by the timeouts of the nodes.
+func (be *BotEngine) handle(ctx context.Context, cmd command.Command, appID command.AppID,
+	callerID string, args []string,
+) command.CommandResult {
+	timeout := cmd.Timeout
+	if timeout == 0 {
+		timeout = be.commandTimeout
+	}
+
+	if timeout <= 0 || cmd.Mutating {
+		return cmd.Handler(ctx, cmd, appID, callerID, args...)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan command.CommandResult, 1)
+	go func() {
+		done <- cmd.Handler(ctx, cmd, appID, callerID, args...)
+	}()
+
+	select {
+	case res := <-done:
+		return res
+	case <-ctx.Done():
+		log.Ctx(ctx).Warn("command timed out", "command", cmd.Name, "timeout", timeout, "callerID", callerID)
+
+		return cmd.FailedResult("The `%s` command took too long, the nodes may be slow. Please try again later!",
+			cmd.Name).WithCode(command.ErrCodeTimeout)
+	}
+}
+
+// allow checks the rate limit of the caller, the limit is shared by the instances if the cache is shared.
+// The command is allowed if the cache store fails, so a cache outage doesn't stop the bot.
+func (be *BotEngine) allow(ctx context.Context, appID command.AppID, callerID string) bool {
+	if be.limiter == nil {
+		return true
+	}
+
+	ok, err := be.limiter.Allow(ctx, appID.String()+":"+callerID)
+	if err != nil {
+		log.Ctx(ctx).Warn("can't check the rate limit", "err", err, "callerID", callerID)
+
+		return true
+	}
+
+	return ok
+}
+
+// retryIn formats the time to retry a command in, the seconds are dropped from the hours, like: "5h12m".
+func retryIn(d time.Duration) string {
+	if d >= time.Hour {
+		return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	}
+
+	return max(d.Round(time.Second), time.Second).String()
+}
+
+// guildQuota returns the daily quota of the guild, scaled by the rate-limit preset that the admins set up for it.
+func (be *BotEngine) guildQuota(appID command.AppID, guildID string) int64 {
+	if be.quotaLimit <= 0 {
+		return 0
+	}
+
+	return max(int64(math.Round(float64(be.quotaLimit)*be.settings.ServerQuotaFactor(appID, guildID))), 1)
+}
+
+// allowQuota counts the expensive command in the daily quota of the guild, the commands out of a guild are not counted.
+// The command is allowed if the cache store fails, like the rate limit.
+func (be *BotEngine) allowQuota(ctx context.Context, appID command.AppID, guildID string) bool {
+	if be.quota == nil || guildID == "" {
+		return true
+	}
+
+	ok, err := be.quota.AllowLimit(ctx, "quota:"+appID.String()+":"+guildID, be.guildQuota(appID, guildID))
+	if err != nil {
+		log.Ctx(ctx).Warn("can't check the guild quota", "err", err, "guildID", guildID)
+
+		return true
+	}
+
+	return ok
+}
+
+// withDeprecationHint appends the migration hint of a deprecated command to the result message.
+func withDeprecationHint(res command.CommandResult, appID command.AppID, name, replacedBy string) command.CommandResult {
+	hint, err := command.RenderTemplate(appID, "command_deprecated", map[string]any{
+		"Name":       name,
+		"ReplacedBy": replacedBy,
+	})
+	if err != nil {
+		log.Error("can't render deprecation hint", "err", err, "command", name)
+
+		return res
+	}
+
+	res.Message += "\n\n" + hint
+
+	return res
+}
+
+// syntax returns the syntax that the callers of the server type the commands in, the command prefix of the server
+// is used if it's set, like: "!pagu network status".
+func (be *BotEngine) syntax(appID command.AppID, guildID string) command.Syntax {
+	if prefix := be.settings.Prefix(appID, guildID); prefix != "" {
+		return command.Syntax{Prefix: prefix}
+	}
+
+	return command.PlatformSyntax(appID)
 }
 
-func (be *BotEngine) getCommand(tokens []string) (command.Command, int) {
+// helpResult returns the help of the parent command filtered for the caller,
+// or the detailed help of the topic command, like: "help network status".
+func (be *BotEngine) helpResult(appID command.AppID, syntax command.Syntax, isAdmin bool,
+	parentPath, topic []string,
+) command.CommandResult {
+	target := be.rootCmd
+	names := make([]string, 0, len(parentPath)+len(topic))
+	for _, name := range slices.Concat(parentPath, topic) {
+		names = append(names, name)
+
+		subCmd, found := target.FindVisibleSubCommand(name, appID, isAdmin)
+		if !found {
+			return target.FailedResult("unknown command: %s", command.EscapeArg(appID, strings.Join(names, " "))).
+				WithCode(command.ErrCodeUnknownCommand)
+		}
+		target = subCmd
+	}
+
+	if len(topic) == 0 {
+		res := target.HelpResultFor(appID, isAdmin)
+		res.Successful = true
+
+		return res
+	}
+
+	return target.DetailedHelpResult(appID, syntax, isAdmin, strings.Join(names, " "))
+}
+
+// isAdmin checks if the caller is authorized to run the admin commands.
+// The CLI is run by the operator, so it is always authorized. The IDs are checked on the platform of the caller,
+// the platforms that don't authenticate their callers, like gRPC and the queue, have no admins.
+func (be *BotEngine) isAdmin(appID command.AppID, callerID string) bool {
+	if appID == command.AppIdCLI {
+		return true
+	}
+
+	return callerID != "" && slices.Contains(be.authIDs[appID], callerID)
+}
+
+// getCommand finds the command of the tokens and returns it with the index of its first argument
+// and the names of the matched commands.
+func (be *BotEngine) getCommand(tokens []string) (command.Command, int, []string) {
 	index := 0
 	targetCmd := be.rootCmd
 	cmds := be.rootCmd.SubCommands
+	path := make([]string, 0)
 	for {
 		if len(tokens) <= index {
 			break
@@ -180,9 +1087,10 @@ func (be *BotEngine) getCommand(tokens []string) (command.Command, int) {
 
 		found := false
 		for _, cmd := range cmds {
-			if cmd.Name == token {
+			if cmd.MatchName(token) {
 				targetCmd = cmd
 				cmds = cmd.SubCommands
+				path = append(path, cmd.Name)
 				found = true
 
 				break
@@ -194,40 +1102,44 @@ func (be *BotEngine) getCommand(tokens []string) (command.Command, int) {
 		}
 	}
 
-	if len(targetCmd.Args) != 0 && index != 0 {
-		return targetCmd, index - 1 //! TODO: FIX ME IN THE MAIN LOGIC
+	// the arguments start after the command path, none of them is given if all the tokens are the path.
+	if len(targetCmd.Args) != 0 {
+		return targetCmd, len(path), path
 	}
 
-	return targetCmd, index
+	return targetCmd, index, path
 }
 
-func (be *BotEngine) NetworkStatus() (*network.NetStatus, error) {
-	netInfo, err := be.clientMgr.GetNetworkInfo()
+func (be *BotEngine) NetworkStatus(ctx context.Context) (*network.NetStatus, error) {
+	netInfo, err := be.clientMgr.GetNetworkInfo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	chainInfo, err := be.clientMgr.GetBlockchainInfo()
+	chainInfo, err := be.clientMgr.GetBlockchainInfo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	cs, err := be.clientMgr.GetCirculatingSupply()
+	cs, err := be.clientMgr.GetCirculatingSupply(ctx)
 	if err != nil {
 		cs = 0
 	}
 
+	sent, received, trafficNodes := be.clientMgr.GetNetworkBytes(ctx)
+
 	return &network.NetStatus{
 		ConnectedPeersCount: netInfo.ConnectedPeersCount,
 		ValidatorsCount:     chainInfo.TotalValidators,
-		TotalBytesSent:      netInfo.TotalSentBytes,
-		TotalBytesReceived:  netInfo.TotalReceivedBytes,
+		TotalBytesSent:      sent,
+		TotalBytesReceived:  received,
+		TrafficNodes:        trafficNodes,
 		CurrentBlockHeight:  chainInfo.LastBlockHeight,
-		TotalNetworkPower:   chainInfo.TotalPower,
-		TotalCommitteePower: chainInfo.CommitteePower,
+		TotalNetworkPower:   amount.Amount(chainInfo.TotalPower),
+		TotalCommitteePower: amount.Amount(chainInfo.CommitteePower),
 		NetworkName:         netInfo.NetworkName,
 		TotalAccounts:       chainInfo.TotalAccounts,
-		CirculatingSupply:   cs,
+		CirculatingSupply:   amount.Amount(cs),
 	}, nil
 }
 
@@ -235,13 +1147,44 @@ func (be *BotEngine) Stop() {
 	log.Info("Stopping the Bot Engine")
 
 	be.cancel()
+	if be.cache != nil {
+		_ = be.cache.Close()
+	}
 	be.clientMgr.Stop()
 	be.phoenixClientMgr.Stop()
+
+	if err := be.traffic.Close(); err != nil {
+		log.Warn("can't close the traffic record", "err", err)
+	}
+
+	if be.shutdown != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := be.shutdown(ctx); err != nil {
+			log.Warn("can't flush the telemetry", "err", err)
+		}
+	}
 }
 
 func (be *BotEngine) Start() {
 	log.Info("Starting the Bot Engine")
 
+	be.StartClients()
+	be.backup.Start(be.ctx)
+	be.tracker.Start(be.ctx)
+	be.indexer.Start(be.ctx)
+	be.scheduler.Start(be.ctx)
+}
+
+// StartClients starts only the clients of the nodes, without the scheduler and the background jobs,
+// like for a single command of the CLI.
+func (be *BotEngine) StartClients() {
 	be.clientMgr.Start()
 	be.phoenixClientMgr.Start()
 }
+
+// SetNotifier sets the notifier of the platform, to send direct messages to its users.
+func (be *BotEngine) SetNotifier(appID command.AppID, notifier notify.Notifier) {
+	be.notifier.Register(appID, notifier)
+}
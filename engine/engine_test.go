@@ -0,0 +1,1099 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pagu-project/Pagu/cache"
+	"github.com/pagu-project/Pagu/challenge"
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/engine/command/blockchain"
+	"github.com/pagu-project/Pagu/engine/command/network"
+	"github.com/pagu-project/Pagu/engine/command/transaction"
+	"github.com/pagu-project/Pagu/engine/plugin"
+	"github.com/pagu-project/Pagu/feature"
+	"github.com/pagu-project/Pagu/i18n"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/maintenance"
+	"github.com/pagu-project/Pagu/metrics"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// testAddress and otherAddress are valid Mainnet validator addresses, the commands reject the invalid ones.
+var (
+	testAddress  = crypto.NewAddress(crypto.AddressTypeValidator, make([]byte, 20)).String()
+	otherAddress = crypto.NewAddress(crypto.AddressTypeValidator, append(make([]byte, 19), 1)).String()
+)
+
+func setupHelpEngine() *BotEngine {
+	handler := func(
+		_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+	) command.CommandResult {
+		return cmd.SuccessfulResult("ok")
+	}
+
+	cmdNetwork := command.Command{
+		Name:        "network",
+		Desc:        "Network related commands",
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+	}
+	cmdNetwork.AddSubCommand(command.Command{
+		Name:     "node-info",
+		Desc:     "View the information of a node",
+		Args:     []command.Args{{Name: "validator_address", Desc: "Your validator address"}},
+		AppIDs:   command.AllAppIDs(),
+		Examples: []string{"pc1p..."},
+		Handler:  handler,
+	})
+	cmdNetwork.AddSubCommand(command.Command{
+		Name:    "qr",
+		Desc:    "Discord only command",
+		AppIDs:  []command.AppID{command.AppIdDiscord},
+		Handler: handler,
+	})
+
+	cmdAdmin := command.Command{
+		Name:        "admin",
+		Desc:        "Maintainer commands",
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+		AdminOnly:   true,
+	}
+	cmdAdmin.AddSubCommand(command.Command{
+		Name:    "slo",
+		Desc:    "Service level report",
+		AppIDs:  command.AllAppIDs(),
+		Handler: handler,
+	})
+
+	be := &BotEngine{
+		metrics: metrics.NewMetrics(),
+		authIDs: map[command.AppID][]string{
+			command.AppIdDiscord:  {"admin-id"},
+			command.AppIdTelegram: {"admin-id"},
+		},
+		rootCmd: command.Command{
+			Name:        "pagu",
+			AppIDs:      command.AllAppIDs(),
+			SubCommands: make([]command.Command, 0),
+		},
+	}
+	be.rootCmd.AddSubCommand(cmdNetwork)
+	be.rootCmd.AddSubCommand(cmdAdmin)
+	be.rootCmd.AddHelpSubCommand()
+
+	return be
+}
+
+func TestHelp(t *testing.T) {
+	be := setupHelpEngine()
+
+	t.Run("admin commands are hidden from users", func(t *testing.T) {
+		res := be.Run(command.AppIdTelegram, "user-id", []string{"help"})
+		assert.True(t, res.Successful)
+		assert.Contains(t, res.Message, "network")
+		assert.NotContains(t, res.Message, "admin")
+
+		res = be.Run(command.AppIdTelegram, "admin-id", []string{"help"})
+		assert.Contains(t, res.Message, "admin")
+	})
+
+	t.Run("the admin IDs are not trusted from the other platforms", func(t *testing.T) {
+		for _, appID := range []command.AppID{command.AppIdgRPC, command.AppIdQueue, command.AppIdHTTP} {
+			res := be.Run(appID, "admin-id", []string{"help"})
+			assert.NotContains(t, res.Message, "admin", appID)
+
+			res = be.Run(appID, "admin-id", []string{"admin", "slo"})
+			assert.False(t, res.Successful, appID)
+			assert.Equal(t, command.ErrCodeUnauthorized, res.Code, appID)
+		}
+
+		res := be.Run(command.AppIdDiscord, "admin-id", []string{"admin", "slo"})
+		assert.NotEqual(t, command.ErrCodeUnauthorized, res.Code)
+	})
+
+	t.Run("platform unsupported commands are hidden", func(t *testing.T) {
+		res := be.Run(command.AppIdHTTP, "user-id", []string{"network", "help"})
+		assert.Contains(t, res.Message, "node-info")
+		assert.NotContains(t, res.Message, "qr")
+
+		res = be.Run(command.AppIdDiscord, "user-id", []string{"network", "help"})
+		assert.Contains(t, res.Message, "qr")
+
+		res = be.Run(command.AppIdHTTP, "user-id", []string{"network"})
+		assert.NotContains(t, res.Message, "qr")
+	})
+
+	t.Run("detailed help", func(t *testing.T) {
+		res := be.Run(command.AppIdCLI, "0", []string{"help", "network", "node-info"})
+		assert.True(t, res.Successful)
+		assert.Contains(t, res.Message, "Usage: `network node-info <validator_address>`")
+		assert.Contains(t, res.Message, "`network node-info pc1p...`")
+
+		res = be.Run(command.AppIdCLI, "0", []string{"network", "help", "node-info"})
+		assert.Contains(t, res.Message, "Usage: `network node-info <validator_address>`")
+
+		// Discord sends the optional argument as one token.
+		res = be.Run(command.AppIdDiscord, "user-id", []string{"help", "network node-info"})
+		assert.Contains(t, res.Message, "Usage: `network node-info <validator_address>`")
+	})
+
+	t.Run("examples in the syntax of the platform", func(t *testing.T) {
+		res := be.Run(command.AppIdDiscord, "user-id", []string{"help", "network node-info"})
+		assert.Contains(t, res.Message, "`/network node-info validator_address:pc1p...`")
+
+		res = be.Run(command.AppIdTelegram, "user-id", []string{"help", "network", "node-info"})
+		assert.Contains(t, res.Message, "`/network node-info pc1p...`")
+
+		db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+		require.NoError(t, err)
+		be := setupHelpEngine()
+		be.settings = settings.NewSettings(db)
+		require.NoError(t, be.settings.Set(settings.PrefixName(command.AppIdDiscord, "guild-1"), "!pagu", "admin-id"))
+
+		res = be.RunInGuild(command.AppIdDiscord, "guild-1", "user-id", []string{"help", "network node-info"})
+		assert.Contains(t, res.Message, "`!pagu network node-info pc1p...`", "the prefix of the server")
+	})
+
+	t.Run("usage errors show the examples", func(t *testing.T) {
+		res := be.Run(command.AppIdCLI, "0", []string{"network", "node-info", "pc1p...", "extra"})
+		assert.False(t, res.Successful)
+		assert.Equal(t, command.ErrCodeInvalidArgs, res.Code)
+		assert.Contains(t, res.Message, "incorrect number of arguments")
+		assert.Contains(t, res.Message, "Usage: `network node-info <validator_address>`")
+		assert.Contains(t, res.Message, "`network node-info pc1p...`")
+	})
+
+	t.Run("detailed help of hidden commands", func(t *testing.T) {
+		res := be.Run(command.AppIdTelegram, "user-id", []string{"help", "admin", "slo"})
+		assert.False(t, res.Successful)
+		assert.Contains(t, res.Message, "unknown command: admin")
+
+		res = be.Run(command.AppIdHTTP, "user-id", []string{"help", "network", "qr"})
+		assert.False(t, res.Successful)
+	})
+}
+
+func TestAliases(t *testing.T) {
+	be := &BotEngine{
+		metrics:       metrics.NewMetrics(),
+		rootCmd:       command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+		blockchainCmd: blockchain.NewBlockchain(nil),
+		networkCmd:    network.NewNetwork(nil, nil, nil, nil, network.ScoreRange{}, nil),
+		txCmd:         transaction.NewTransaction(nil),
+	}
+	be.rootCmd.AddSubCommand(be.blockchainCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.networkCmd.GetCommand())
+	be.rootCmd.AddSubCommand(be.txCmd.GetCommand())
+	be.rootCmd.AddHelpSubCommand()
+
+	t.Run("all catalog aliases are valid", func(t *testing.T) {
+		catalogs, err := i18n.Catalogs()
+		require.NoError(t, err)
+		require.NotEmpty(t, catalogs)
+
+		for _, catalog := range catalogs {
+			for path, names := range catalog.Aliases {
+				for _, name := range names {
+					alias := command.Alias{Locale: catalog.Locale, Name: name}
+					assert.NoError(t, be.rootCmd.AddAlias(strings.Fields(path), alias), "%s: %s", catalog.Locale, path)
+				}
+			}
+		}
+	})
+
+	t.Run("localized names match the command", func(t *testing.T) {
+		cmd, _, path := be.getCommand([]string{"şebeke", "durum"})
+		assert.Equal(t, "status", cmd.Name)
+		assert.Equal(t, []string{"network", "status"}, path)
+
+		cmd, _, _ = be.getCommand([]string{"شبکه", "وضعیت"})
+		assert.Equal(t, "status", cmd.Name)
+
+		cmd, _, _ = be.getCommand([]string{"Şebeke"})
+		assert.Equal(t, "network", cmd.Name)
+	})
+
+	t.Run("conflicting alias", func(t *testing.T) {
+		err := be.rootCmd.AddAlias([]string{"network", "status"}, command.Alias{Locale: "xx", Name: "health"})
+		assert.Error(t, err)
+
+		err = be.rootCmd.AddAlias([]string{"network", "not-exist"}, command.Alias{Locale: "xx", Name: "foo"})
+		assert.Error(t, err)
+	})
+}
+
+func TestChallenge(t *testing.T) {
+	claimed := 0
+	be := &BotEngine{
+		metrics:    metrics.NewMetrics(),
+		challenges: challenge.NewManager(time.Minute, challenge.Math{}),
+		rootCmd:    command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	be.rootCmd.AddSubCommand(command.Command{
+		Name:      "claim",
+		Desc:      "Claim reward",
+		Args:      []command.Args{{Name: "address", Desc: "Your address"}},
+		AppIDs:    command.AllAppIDs(),
+		Challenge: true,
+		Handler: func(
+			_ context.Context, cmd command.Command, _ command.AppID, _ string, args ...string,
+		) command.CommandResult {
+			claimed++
+
+			return cmd.SuccessfulResult("claimed for %s", args[0])
+		},
+	})
+	be.rootCmd.AddSubCommand(be.verifyCommand())
+
+	res := be.Run(command.AppIdTelegram, "user-1", []string{"claim", testAddress})
+	assert.False(t, res.Successful)
+	assert.Zero(t, claimed)
+
+	var a, b int
+	_, err := fmt.Sscanf(res.Message[strings.Index(res.Message, "What is"):], "What is %d + %d?", &a, &b)
+	require.NoError(t, err)
+
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"verify", strconv.Itoa(a + b)})
+	assert.True(t, res.Successful)
+	assert.Equal(t, "claimed for "+testAddress, res.Message)
+	assert.Equal(t, 1, claimed)
+
+	// The verification is used, so the next claim is challenged again.
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"claim", testAddress})
+	assert.False(t, res.Successful)
+	assert.Equal(t, 1, claimed)
+
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"verify", "wrong"})
+	assert.False(t, res.Successful)
+	assert.Equal(t, 1, claimed)
+
+	// CLI is not challenged.
+	res = be.Run(command.AppIdCLI, "0", []string{"claim", testAddress})
+	assert.True(t, res.Successful)
+	assert.Equal(t, 2, claimed)
+}
+
+func TestRateLimit(t *testing.T) {
+	be := setupHelpEngine()
+	be.ctx = context.Background()
+	be.limiter = cache.NewLimiter(cache.NewMemoryStore(), 2, time.Minute)
+
+	tokens := []string{"network", "node-info", testAddress}
+	for i := 0; i < 2; i++ {
+		res := be.Run(command.AppIdDiscord, "user-id", tokens)
+		assert.True(t, res.Successful)
+	}
+
+	res := be.Run(command.AppIdDiscord, "user-id", tokens)
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "Too many commands")
+	assert.Equal(t, command.ErrCodeRateLimited, res.Code)
+	assert.Positive(t, res.RetryAfter)
+	assert.LessOrEqual(t, res.RetryAfter, time.Minute)
+	assert.Contains(t, res.Message, "please try again in")
+	assert.Equal(t, int64(1), be.metrics.ErrorCounts()[string(command.ErrCodeRateLimited)])
+
+	res = be.Run(command.AppIdTelegram, "user-id", tokens)
+	assert.True(t, res.Successful, "the limit is per platform")
+
+	for i := 0; i < 3; i++ {
+		res = be.Run(command.AppIdDiscord, "admin-id", tokens)
+		assert.True(t, res.Successful, "admins are not limited")
+	}
+}
+
+func TestRetryIn(t *testing.T) {
+	assert.Equal(t, "1s", retryIn(100*time.Millisecond))
+	assert.Equal(t, "42s", retryIn(42*time.Second+300*time.Millisecond))
+	assert.Equal(t, "12m5s", retryIn(12*time.Minute+5*time.Second))
+	assert.Equal(t, "5h12m", retryIn(5*time.Hour+12*time.Minute+20*time.Second))
+}
+
+func TestGuildQuota(t *testing.T) {
+	be := setupHelpEngine()
+	be.ctx = context.Background()
+	be.quota = cache.NewLimiter(cache.NewMemoryStore(), 2, quotaWindow)
+	be.quotaLimit = 2
+
+	// node-info is an expensive command in the test.
+	be.rootCmd.SubCommands[0].SubCommands[0].Expensive = true
+
+	tokens := []string{"network", "node-info", testAddress}
+	for i := 0; i < 2; i++ {
+		res := be.RunInGuild(command.AppIdDiscord, "guild-1", "user-"+strconv.Itoa(i), tokens)
+		assert.True(t, res.Successful)
+	}
+
+	res := be.RunInGuild(command.AppIdDiscord, "guild-1", "user-3", tokens)
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "This server used its daily quota of 2 heavy commands")
+	assert.Equal(t, command.ErrCodeQuotaExceeded, res.Code)
+	assert.Positive(t, res.RetryAfter)
+	assert.LessOrEqual(t, res.RetryAfter, quotaWindow)
+
+	res = be.RunInGuild(command.AppIdDiscord, "guild-1", "user-3", []string{"network", "qr"})
+	assert.True(t, res.Successful, "the other commands are not counted")
+
+	res = be.RunInGuild(command.AppIdDiscord, "guild-2", "user-3", tokens)
+	assert.True(t, res.Successful, "the quota is per guild")
+
+	res = be.Run(command.AppIdTelegram, "user-3", tokens)
+	assert.True(t, res.Successful, "out of a guild")
+
+	res = be.RunInGuild(command.AppIdDiscord, "guild-1", "admin-id", tokens)
+	assert.True(t, res.Successful, "admins are not counted")
+}
+
+func TestCommandTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	be := &BotEngine{
+		ctx:            context.Background(),
+		metrics:        metrics.NewMetrics(),
+		rootCmd:        command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+		commandTimeout: 200 * time.Millisecond,
+	}
+	hang := func(_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string) command.CommandResult {
+		<-release
+
+		return cmd.SuccessfulResult("done")
+	}
+	be.rootCmd.AddSubCommand(command.Command{Name: "hang", AppIDs: command.AllAppIDs(), Handler: hang})
+	be.rootCmd.AddSubCommand(command.Command{
+		Name:    "quick",
+		AppIDs:  command.AllAppIDs(),
+		Timeout: 10 * time.Millisecond,
+		Handler: hang,
+	})
+	be.rootCmd.AddSubCommand(command.Command{
+		Name:   "ok",
+		AppIDs: command.AllAppIDs(),
+		Handler: func(
+			ctx context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+		) command.CommandResult {
+			if _, ok := ctx.Deadline(); !ok {
+				return cmd.FailedResult("no deadline")
+			}
+
+			return cmd.SuccessfulResult("done")
+		},
+	})
+	be.rootCmd.AddSubCommand(command.Command{
+		Name:     "pay",
+		AppIDs:   command.AllAppIDs(),
+		Mutating: true,
+		Handler: func(
+			_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+		) command.CommandResult {
+			time.Sleep(300 * time.Millisecond)
+
+			return cmd.SuccessfulResult("paid")
+		},
+	})
+
+	start := time.Now()
+	res := be.Run(command.AppIdDiscord, "user-id", []string{"hang"})
+	assert.False(t, res.Successful)
+	assert.Equal(t, command.ErrCodeTimeout, res.Code)
+	assert.Contains(t, res.Message, "The `hang` command took too long")
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.Equal(t, int64(1), be.metrics.ErrorCounts()[string(command.ErrCodeTimeout)])
+
+	start = time.Now()
+	res = be.Run(command.AppIdDiscord, "user-id", []string{"quick"})
+	assert.Equal(t, command.ErrCodeTimeout, res.Code)
+	assert.Less(t, time.Since(start), 200*time.Millisecond, "the command has its own timeout")
+
+	res = be.Run(command.AppIdDiscord, "user-id", []string{"ok"})
+	assert.True(t, res.Successful)
+	assert.Equal(t, "done", res.Message, "the handler has the context of the timeout")
+
+	res = be.Run(command.AppIdDiscord, "user-id", []string{"pay"})
+	assert.True(t, res.Successful, "a state-changing command is not given up")
+	assert.Equal(t, "paid", res.Message)
+}
+
+func TestTracing(t *testing.T) {
+	global := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(global)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	be := &BotEngine{
+		ctx:     context.Background(),
+		metrics: metrics.NewMetrics(),
+		rootCmd: command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	be.rootCmd.AddSubCommand(command.Command{
+		Name:   "ok",
+		AppIDs: command.AllAppIDs(),
+		Handler: func(
+			ctx context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+		) command.CommandResult {
+			// like the span of an RPC of the client interceptor.
+			_, rpc := otel.Tracer("test").Start(ctx, "rpc")
+			rpc.End()
+
+			return cmd.SuccessfulResult("done")
+		},
+	})
+	be.rootCmd.AddSubCommand(command.Command{Name: "admin-only", AppIDs: command.AllAppIDs(), AdminOnly: true})
+
+	res := be.Run(command.AppIdDiscord, "user-id", []string{"ok"})
+	require.True(t, res.Successful)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	assert.Equal(t, "engine.middleware", spans[0].Name())
+	assert.Equal(t, "rpc", spans[1].Name())
+	assert.Equal(t, "engine.handler", spans[2].Name())
+	assert.Equal(t, "engine.dispatch", spans[3].Name())
+	assert.Equal(t, spans[3].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, spans[2].SpanContext().SpanID(), spans[1].Parent().SpanID(), "the RPCs are under the handler")
+	assert.Equal(t, spans[3].SpanContext().SpanID(), spans[2].Parent().SpanID(), "the handler is not under the checks")
+	assert.Contains(t, spans[3].Attributes(), attribute.String("pagu.command", "ok"))
+	assert.Contains(t, spans[3].Attributes(), attribute.Bool("pagu.successful", true))
+	assert.Contains(t, spans[3].Attributes(), attribute.String("pagu.request_id", res.RequestID))
+
+	res = be.Run(command.AppIdDiscord, "user-id", []string{"admin-only"})
+	require.False(t, res.Successful)
+
+	spans = recorder.Ended()[4:]
+	require.Len(t, spans, 2, "a failed check doesn't run the handler")
+	assert.Equal(t, "engine.middleware", spans[0].Name())
+	assert.Contains(t, spans[1].Attributes(), attribute.String("pagu.code", string(command.ErrCodeUnauthorized)))
+}
+
+func TestRequestID(t *testing.T) {
+	be := &BotEngine{
+		ctx:     context.Background(),
+		metrics: metrics.NewMetrics(),
+		rootCmd: command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	handlerID := ""
+	be.rootCmd.AddSubCommand(command.Command{
+		Name:   "broken",
+		AppIDs: command.AllAppIDs(),
+		Handler: func(
+			ctx context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+		) command.CommandResult {
+			handlerID = log.RequestID(ctx)
+
+			return cmd.ErrorResult(fmt.Errorf("database is locked"))
+		},
+	})
+	be.rootCmd.AddSubCommand(command.Command{Name: "admin-only", AppIDs: command.AllAppIDs(), AdminOnly: true})
+
+	res := be.Run(command.AppIdDiscord, "user-id", []string{"broken"})
+	assert.Equal(t, command.ErrCodeInternal, res.Code)
+	assert.Len(t, res.RequestID, 8)
+	assert.True(t, strings.HasSuffix(res.Message, "\n\nerror id: "+res.RequestID), res.Message)
+	assert.Equal(t, res.RequestID, handlerID, "the log lines of the handler have the request ID")
+
+	other := be.Run(command.AppIdDiscord, "user-id", []string{"broken"})
+	assert.NotEqual(t, res.RequestID, other.RequestID, "each command has its own ID")
+
+	res = be.Run(command.AppIdDiscord, "user-id", []string{"admin-only"})
+	assert.Equal(t, command.ErrCodeUnauthorized, res.Code)
+	assert.NotEmpty(t, res.RequestID)
+	assert.NotContains(t, res.Message, "error id", "only the internal errors show the ID")
+}
+
+func TestFollowUp(t *testing.T) {
+	be := &BotEngine{
+		metrics:  metrics.NewMetrics(),
+		contexts: newContextStore(time.Minute),
+		rootCmd:  command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	cmdNetwork := command.Command{Name: "network", AppIDs: command.AllAppIDs(), SubCommands: make([]command.Command, 0)}
+	cmdNetwork.AddSubCommand(command.Command{
+		Name:   "node-info",
+		Args:   []command.Args{{Name: "validator_address"}},
+		AppIDs: command.AllAppIDs(),
+		Handler: func(
+			_ context.Context, cmd command.Command, _ command.AppID, _ string, args ...string,
+		) command.CommandResult {
+			return cmd.SuccessfulResult("info of %s", args[0]).WithReference(command.ReferenceValidator, args[0])
+		},
+	})
+	be.rootCmd.AddSubCommand(cmdNetwork)
+	be.rootCmd.AddHelpSubCommand()
+
+	res := be.Run(command.AppIdTelegram, "user-1", []string{"and", "its", "stake?"})
+	assert.NotEqual(t, "info of "+testAddress, res.Message, "no reference yet")
+
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"network", "node-info", testAddress})
+	assert.True(t, res.Successful)
+
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"and", "its", "stake?"})
+	assert.True(t, res.Successful)
+	assert.Equal(t, "info of "+testAddress, res.Message)
+
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"hello"})
+	assert.NotEqual(t, "info of "+testAddress, res.Message, "not a follow-up")
+
+	res = be.Run(command.AppIdTelegram, "user-2", []string{"and", "its", "stake?"})
+	assert.NotEqual(t, "info of "+testAddress, res.Message, "the context is per caller")
+
+	be.contexts.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"and", "its", "stake?"})
+	assert.NotEqual(t, "info of "+testAddress, res.Message, "the context is expired")
+}
+
+func TestPrompt(t *testing.T) {
+	be := &BotEngine{
+		metrics: metrics.NewMetrics(),
+		prompts: newPromptStore(time.Minute),
+		rootCmd: command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	cmdNetwork := command.Command{Name: "network", AppIDs: command.AllAppIDs(), SubCommands: make([]command.Command, 0)}
+	cmdNetwork.AddSubCommand(command.Command{
+		Name:   "node-info",
+		Args:   []command.Args{{Name: "validator_address", Desc: "The validator address"}},
+		AppIDs: command.AllAppIDs(),
+		Handler: func(
+			_ context.Context, cmd command.Command, _ command.AppID, _ string, args ...string,
+		) command.CommandResult {
+			return cmd.SuccessfulResult("info of %s", args[0])
+		},
+	})
+	be.rootCmd.AddSubCommand(cmdNetwork)
+	be.rootCmd.AddHelpSubCommand()
+
+	res := be.Run(command.AppIdTelegram, "user-1", []string{"network", "node-info"})
+	assert.True(t, res.Successful)
+	assert.Contains(t, res.Message, "Please send the validator_address")
+	assert.True(t, be.Prompted(command.AppIdTelegram, "user-1"))
+	assert.False(t, be.Prompted(command.AppIdTelegram, "user-2"))
+
+	res = be.Run(command.AppIdTelegram, "user-2", []string{testAddress})
+	assert.NotEqual(t, "info of "+testAddress, res.Message, "the prompt is per caller")
+
+	res = be.Run(command.AppIdTelegram, "user-1", []string{testAddress})
+	assert.True(t, res.Successful)
+	assert.Equal(t, "info of "+testAddress, res.Message)
+
+	res = be.Run(command.AppIdTelegram, "user-1", []string{testAddress})
+	assert.NotEqual(t, "info of "+testAddress, res.Message, "the prompt is answered")
+
+	be.Run(command.AppIdTelegram, "user-1", []string{"network", "node-info"})
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"cancel"})
+	assert.Equal(t, "The command is canceled", res.Message)
+
+	be.Run(command.AppIdTelegram, "user-1", []string{"network", "node-info"})
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"network", "node-info", otherAddress})
+	assert.Equal(t, "info of "+otherAddress, res.Message, "another command drops the prompt")
+
+	be.Run(command.AppIdTelegram, "user-1", []string{"network", "node-info"})
+	be.prompts.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	res = be.Run(command.AppIdTelegram, "user-1", []string{testAddress})
+	assert.NotEqual(t, "info of "+testAddress, res.Message, "the prompt is expired")
+	be.prompts.now = time.Now
+
+	res = be.Run(command.AppIdHTTP, "user-1", []string{"network", "node-info"})
+	assert.False(t, res.Successful, "the usage error on HTTP")
+	assert.NotContains(t, res.Message, "Please send")
+}
+
+func TestIntents(t *testing.T) {
+	be := setupHelpEngine()
+	be.ctx = context.Background()
+
+	res := be.Run(command.AppIdTelegram, "user-id", []string{"node", "of", testAddress + "?"})
+	assert.NotContains(t, res.Message, "didn't understand", "the NLP layer is disabled")
+
+	be.intents = newIntentMatcher(config.NLP{Enable: true})
+
+	res = be.Run(command.AppIdTelegram, "user-id", []string{"node", "of", testAddress + "?"})
+	assert.True(t, res.Successful)
+	assert.Equal(t, "ok", res.Message)
+
+	res = be.Run(command.AppIdTelegram, "user-id", []string{"tell", "me", "about", "a", "node"})
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "didn't understand \"tell me about a node\"")
+	assert.Contains(t, res.Message, "`network node-info <validator_address>`: View the information of a node")
+	assert.NotContains(t, res.Message, "admin", "admin commands are not suggested")
+
+	res = be.Run(command.AppIdTelegram, "user-id", []string{"help"})
+	assert.NotContains(t, res.Message, "didn't understand", "commands are not matched")
+}
+
+type testProvider struct {
+	cmd command.Command
+}
+
+func (p testProvider) GetCommand() command.Command {
+	return p.cmd
+}
+
+func TestPlugins(t *testing.T) {
+	handler := func(
+		_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+	) command.CommandResult {
+		return cmd.SuccessfulResult("plugin")
+	}
+
+	be := &BotEngine{
+		metrics: metrics.NewMetrics(),
+		rootCmd: command.Command{
+			Name:        "pagu",
+			AppIDs:      command.AllAppIDs(),
+			SubCommands: make([]command.Command, 0),
+		},
+		plugins: []plugin.CommandProvider{
+			testProvider{cmd: command.Command{
+				Name:    "exchange",
+				Desc:    "Exchange tools",
+				AppIDs:  command.AllAppIDs(),
+				Handler: handler,
+			}},
+			testProvider{cmd: command.Command{
+				Name:    "network",
+				Desc:    "Overrides the network command",
+				AppIDs:  command.AllAppIDs(),
+				Handler: handler,
+			}},
+		},
+	}
+	be.rootCmd.AddSubCommand(command.Command{
+		Name:        "network",
+		Desc:        "Network related commands",
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+	})
+	be.registerPlugins()
+
+	res := be.Run(command.AppIdCLI, "0", []string{"exchange"})
+	assert.True(t, res.Successful)
+	assert.Equal(t, "plugin", res.Message)
+
+	// the plugin can't replace a registered command.
+	names := make([]string, 0)
+	for _, cmd := range be.Commands() {
+		names = append(names, cmd.Name)
+	}
+	assert.Equal(t, []string{"network", "exchange"}, names)
+}
+
+func TestFeatureFlags(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	be := setupHelpEngine()
+	be.features = feature.NewFlags(db)
+
+	res := be.Run(command.AppIdCLI, "0", []string{"network", "node-info", testAddress})
+	assert.True(t, res.Successful)
+
+	require.NoError(t, be.features.Disable("network.node-info", "admin-id"))
+	res = be.Run(command.AppIdCLI, "0", []string{"network", "node-info", testAddress})
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "`network node-info` command is disabled")
+
+	res = be.Run(command.AppIdDiscord, "0", []string{"network", "qr"})
+	assert.True(t, res.Successful, "the other commands are enabled")
+
+	require.NoError(t, be.features.Disable("network", "admin-id"))
+	res = be.Run(command.AppIdDiscord, "0", []string{"network", "qr"})
+	assert.False(t, res.Successful, "the group is disabled")
+}
+
+func TestReadOnly(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	setts := settings.NewSettings(db)
+	be := &BotEngine{
+		metrics:  metrics.NewMetrics(),
+		settings: setts,
+		rootCmd:  command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	handler := func(
+		_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+	) command.CommandResult {
+		return cmd.SuccessfulResult("ok")
+	}
+	be.rootCmd.AddSubCommand(command.Command{Name: "faucet", AppIDs: command.AllAppIDs(), Mutating: true, Handler: handler})
+	be.rootCmd.AddSubCommand(command.Command{Name: "status", AppIDs: command.AllAppIDs(), Handler: handler})
+
+	res := be.Run(command.AppIdCLI, "0", []string{"faucet"})
+	assert.True(t, res.Successful)
+
+	require.NoError(t, setts.SetReadOnly(true, "admin-id"))
+
+	res = be.Run(command.AppIdCLI, "0", []string{"faucet"})
+	assert.False(t, res.Successful, "the admins can't run it either")
+	assert.Contains(t, res.Message, "read-only mode")
+
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"status"})
+	assert.True(t, res.Successful, "the read commands keep working")
+}
+
+func TestRunInTopic(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	setts := settings.NewSettings(db)
+	be := &BotEngine{
+		metrics:  metrics.NewMetrics(),
+		settings: setts,
+		rootCmd:  command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	handler := func(
+		_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+	) command.CommandResult {
+		return cmd.SuccessfulResult("ok")
+	}
+	be.rootCmd.AddSubCommand(command.Command{Name: "phoenix", AppIDs: command.AllAppIDs(), Handler: handler})
+	be.rootCmd.AddSubCommand(command.Command{Name: "network", AppIDs: command.AllAppIDs(), Handler: handler})
+	be.rootCmd.AddSubCommand(command.Command{Name: "blockchain", AppIDs: command.AllAppIDs(), Handler: handler})
+	be.rootCmd.AddHelpSubCommand()
+
+	res := be.RunInTopic(command.AppIdTelegram, "-100123", "42", "user-1", []string{"network"})
+	assert.True(t, res.Successful, "no topic is bound")
+
+	require.NoError(t, setts.Set(settings.TopicName("-100123", "42"), "phoenix", "admin-id"))
+
+	res = be.RunInTopic(command.AppIdTelegram, "-100123", "42", "user-1", []string{"phoenix"})
+	assert.True(t, res.Successful)
+
+	res = be.RunInTopic(command.AppIdTelegram, "-100123", "42", "user-1", []string{"network"})
+	assert.False(t, res.Successful)
+	assert.Equal(t, "This topic is for the `phoenix` commands, please ask in the other topics!", res.Message)
+
+	res = be.RunInTopic(command.AppIdTelegram, "-100123", "7", "user-1", []string{"phoenix"})
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "in their own topic")
+
+	res = be.RunInTopic(command.AppIdTelegram, "-100123", "7", "user-1", []string{"blockchain"})
+	assert.True(t, res.Successful)
+
+	res = be.RunInTopic(command.AppIdTelegram, "-100123", "42", "user-1", []string{"help"})
+	assert.True(t, res.Successful, "the help runs in all the topics")
+}
+
+func TestServerSetup(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	setts := settings.NewSettings(db)
+	be := &BotEngine{
+		ctx:        context.Background(),
+		metrics:    metrics.NewMetrics(),
+		settings:   setts,
+		quota:      cache.NewLimiter(cache.NewMemoryStore(), 2, quotaWindow),
+		quotaLimit: 2,
+		rootCmd:    command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	handler := func(
+		_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+	) command.CommandResult {
+		return cmd.SuccessfulResult("ok")
+	}
+	be.rootCmd.AddSubCommand(command.Command{Name: "phoenix", AppIDs: command.AllAppIDs(), Handler: handler})
+	be.rootCmd.AddSubCommand(command.Command{
+		Name: "network", AppIDs: command.AllAppIDs(), Expensive: true, Handler: handler,
+	})
+	be.rootCmd.AddHelpSubCommand()
+	assert.Equal(t, []string{"phoenix", "network"}, be.commandGroups())
+
+	require.NoError(t, setts.Set(settings.ServerName(command.AppIdDiscord, "guild-1", settings.ServerGroups),
+		"network", "admin-id"))
+	require.NoError(t, setts.Set(settings.ServerName(command.AppIdDiscord, "guild-1", settings.ServerLimits),
+		settings.LimitsStrict, "admin-id"))
+
+	res := be.RunInGuild(command.AppIdDiscord, "guild-1", "user-1", []string{"phoenix"})
+	assert.False(t, res.Successful)
+	assert.Equal(t, "The `phoenix` commands are not enabled in this server.", res.Message)
+	assert.Equal(t, command.ErrCodeDisabled, res.Code)
+
+	res = be.RunInGuild(command.AppIdDiscord, "guild-2", "user-1", []string{"phoenix"})
+	assert.True(t, res.Successful, "the server is not set up")
+
+	res = be.RunInGuild(command.AppIdDiscord, "guild-1", "user-1", []string{"help"})
+	assert.True(t, res.Successful, "the help runs in all the servers")
+
+	res = be.RunInGuild(command.AppIdDiscord, "guild-1", "user-1", []string{"network"})
+	assert.True(t, res.Successful)
+	res = be.RunInGuild(command.AppIdDiscord, "guild-1", "user-2", []string{"network"})
+	assert.False(t, res.Successful, "the strict preset halves the quota")
+	assert.Contains(t, res.Message, "daily quota of 1 heavy commands")
+}
+
+type fakeServerAdmins map[string]string // The admin of each server.
+
+func (f fakeServerAdmins) IsServerAdmin(guildID, userID string) bool {
+	return f[guildID] == userID
+}
+
+func (fakeServerAdmins) HasChannel(guildID, channelID string) bool {
+	return guildID == channelID
+}
+
+func TestServerAdmins(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	be := &BotEngine{
+		metrics:      metrics.NewMetrics(),
+		settings:     settings.NewSettings(db),
+		serverAdmins: make(map[command.AppID]ServerAdmins),
+		rootCmd:      command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	handler := func(
+		_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+	) command.CommandResult {
+		return cmd.SuccessfulResult("ok")
+	}
+	cmdAdmin := command.Command{Name: "admin", AppIDs: command.AllAppIDs(), AdminOnly: true}
+	cmdAdmin.AddSubCommand(command.Command{
+		Name: "setup", AppIDs: command.AllAppIDs(), Args: []command.Args{{Name: "server"}},
+		ServerAdmin: true, Handler: handler,
+	})
+	cmdAdmin.AddSubCommand(command.Command{Name: "slo", AppIDs: command.AllAppIDs(), Handler: handler})
+	be.rootCmd.AddSubCommand(cmdAdmin)
+	assert.False(t, be.isServerChannel(command.AppIdTelegram, "-100", "-100"), "no server admins of the platform")
+	be.SetServerAdmins(command.AppIdTelegram, fakeServerAdmins{"-100": "owner-1"})
+	assert.True(t, be.isServerChannel(command.AppIdTelegram, "-100", "-100"))
+	assert.False(t, be.isServerChannel(command.AppIdTelegram, "-100", "-200"))
+
+	res := be.RunInGuild(command.AppIdTelegram, "-100", "owner-1", []string{"admin", "setup", "-100"})
+	assert.True(t, res.Successful, "the admin of the group sets it up")
+
+	res = be.RunInGuild(command.AppIdTelegram, "-100", "owner-1", []string{"admin", "setup", "-200"})
+	assert.False(t, res.Successful, "the admin of the group can't set up another group")
+	assert.Equal(t, "You can only run it for this server, its ID is: -100", res.Message)
+
+	res = be.RunInGuild(command.AppIdTelegram, "-100", "owner-1", []string{"admin", "slo"})
+	assert.Equal(t, command.ErrCodeUnauthorized, res.Code, "only the server admin commands")
+
+	res = be.RunInGuild(command.AppIdTelegram, "-100", "user-1", []string{"admin", "setup", "-100"})
+	assert.Equal(t, command.ErrCodeUnauthorized, res.Code)
+
+	res = be.Run(command.AppIdTelegram, "owner-1", []string{"admin", "setup", "-100"})
+	assert.Equal(t, command.ErrCodeUnauthorized, res.Code, "the private chats have no server")
+}
+
+type deprecationStore map[string]int64
+
+func (s deprecationStore) AddDeprecatedCall(name string, _ time.Time) error {
+	s[name]++
+
+	return nil
+}
+
+func (s deprecationStore) GetDeprecatedCalls() (map[string]int64, error) {
+	return s, nil
+}
+
+func TestDeprecation(t *testing.T) {
+	store := deprecationStore{}
+	be := &BotEngine{
+		metrics:      metrics.NewMetrics(),
+		deprecations: store,
+		rootCmd:      command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	handler := func(
+		_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+	) command.CommandResult {
+		return cmd.SuccessfulResult("ok")
+	}
+	be.rootCmd.AddSubCommand(command.Command{
+		Name: "old-status", AppIDs: command.AllAppIDs(), Deprecated: true, ReplacedBy: "network status",
+		SunsetAt: time.Now().Add(time.Hour), Handler: handler,
+	})
+	be.rootCmd.AddSubCommand(command.Command{
+		Name: "old-info", AppIDs: command.AllAppIDs(), Deprecated: true, ReplacedBy: "blockchain info",
+		SunsetAt: time.Now().Add(-time.Hour), Handler: handler,
+	})
+
+	res := be.Run(command.AppIdCLI, "user-1", []string{"old-status"})
+	assert.True(t, res.Successful)
+	assert.Contains(t, res.Message, "use `network status` instead")
+
+	res = be.Run(command.AppIdCLI, "user-1", []string{"old-info"})
+	assert.False(t, res.Successful, "the command doesn't run after its sunset")
+	assert.Equal(t, "The `old-info` command is removed, please use `blockchain info` instead.", res.Message)
+	assert.Equal(t, command.ErrCodeDisabled, res.Code)
+
+	calls, err := be.deprecatedCalls()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"old-status": 1, "old-info": 1}, calls)
+}
+
+func TestBlockCache(t *testing.T) {
+	height := uint32(0)
+	calls := 0
+	be := &BotEngine{
+		ctx:        context.Background(),
+		metrics:    metrics.NewMetrics(),
+		blockCache: newBlockCache(cache.NewMemoryStore(), func(context.Context) (uint32, error) { return height, nil }),
+		rootCmd:    command.Command{Name: "pagu", AppIDs: command.AllAppIDs()},
+	}
+	be.rootCmd.AddSubCommand(command.Command{
+		Name:       "status",
+		Args:       []command.Args{{Name: "options", Optional: true}},
+		AppIDs:     command.AllAppIDs(),
+		PerBlock:   true,
+		CallerArgs: []string{"--diff"},
+		Handler: func(
+			_ context.Context, cmd command.Command, _ command.AppID, _ string, _ ...string,
+		) command.CommandResult {
+			calls++
+
+			return cmd.SuccessfulResult("status %d", calls)
+		},
+	})
+
+	be.Run(command.AppIdCLI, "0", []string{"status"})
+	be.Run(command.AppIdCLI, "0", []string{"status"})
+	assert.Equal(t, 2, calls, "no height is observed yet")
+
+	height = 10
+	require.NoError(t, be.blockCache.Observe(context.Background()))
+	res := be.Run(command.AppIdCLI, "0", []string{"status"})
+	assert.Equal(t, "status 3", res.Message)
+	res = be.Run(command.AppIdCLI, "0", []string{"status"})
+	assert.Equal(t, "status 3", res.Message, "the same block has the same result")
+	res = be.Run(command.AppIdTelegram, "user-1", []string{"status"})
+	assert.Equal(t, "status 4", res.Message, "each platform has its own result")
+	res = be.Run(command.AppIdCLI, "0", []string{"status", "--full"})
+	assert.Equal(t, "status 5", res.Message, "the arguments have their own result")
+	res = be.Run(command.AppIdCLI, "1", []string{"status", "--full"})
+	assert.Equal(t, "status 5", res.Message)
+	res = be.Run(command.AppIdCLI, "0", []string{"status", "--diff"})
+	assert.Equal(t, "status 6", res.Message)
+	res = be.Run(command.AppIdCLI, "0", []string{"status", "--diff"})
+	assert.Equal(t, "status 6", res.Message, "the caller has the same result in the block")
+	res = be.Run(command.AppIdCLI, "1", []string{"status", "--diff"})
+	assert.Equal(t, "status 7", res.Message, "the arguments of the caller are cached per caller")
+
+	height = 9
+	require.NoError(t, be.blockCache.Observe(context.Background()))
+	res = be.Run(command.AppIdCLI, "0", []string{"status"})
+	assert.Equal(t, "status 3", res.Message, "a lagging node doesn't go back")
+
+	height = 11
+	require.NoError(t, be.blockCache.Observe(context.Background()))
+	res = be.Run(command.AppIdCLI, "0", []string{"status"})
+	assert.Equal(t, "status 8", res.Message, "a new block has a new result")
+}
+
+func TestMaintenance(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	be := setupHelpEngine()
+	be.maintenance = maintenance.NewMaintenance(settings.NewSettings(db), db, notify.NewHub(), nil, time.Hour)
+
+	window, err := maintenance.ParseWindow("now", "30m", time.Now())
+	require.NoError(t, err)
+	require.NoError(t, be.maintenance.Schedule(window, "admin-id"))
+
+	res := be.Run(command.AppIdDiscord, "user-id", []string{"network", "qr"})
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "Pagu is under maintenance until "+window.End.Format("2006-01-02 15:04")+" UTC")
+	assert.Equal(t, command.ErrCodeMaintenance, res.Code)
+	assert.InDelta(t, 30*time.Minute, res.RetryAfter, float64(time.Minute))
+
+	res = be.Run(command.AppIdCLI, "0", []string{"network", "node-info", testAddress})
+	assert.True(t, res.Successful, "the admins run the commands")
+
+	_, err = be.maintenance.Cancel()
+	require.NoError(t, err)
+	res = be.Run(command.AppIdDiscord, "user-id", []string{"network", "qr"})
+	assert.True(t, res.Successful, "resumed after the cancel")
+}
+
+func TestTrafficRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	recorder, err := newTrafficRecorder(path)
+	require.NoError(t, err)
+
+	be := setupHelpEngine()
+	be.traffic = recorder
+	be.Run(command.AppIdDiscord, "user-id", []string{"network", "help node-info"})
+	be.Run(command.AppIdTelegram, "user-id", []string{"help"})
+	require.NoError(t, recorder.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	records, err := ReadTraffic(file)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, command.AppIdDiscord, records[0].AppID)
+	assert.Equal(t, []string{"network", "help node-info"}, records[0].Tokens)
+	assert.Len(t, records[0].CallerID, 16)
+	assert.NotContains(t, records[0].CallerID, "user-id", "the caller ID is hashed")
+	assert.Equal(t, records[0].CallerID, records[1].CallerID)
+}
+
+func TestListArgs(t *testing.T) {
+	be := &BotEngine{
+		metrics: metrics.NewMetrics(),
+		rootCmd: command.Command{Name: "pagu", AppIDs: command.AllAppIDs(), SubCommands: make([]command.Command, 0)},
+	}
+	cmdValidator := command.Command{
+		Name:        "validator",
+		AppIDs:      command.AllAppIDs(),
+		SubCommands: make([]command.Command, 0),
+	}
+	cmdValidator.AddSubCommand(command.Command{
+		Name: "bulk",
+		Args: []command.Args{
+			{Name: "validators", Optional: true, Variadic: true, Kind: command.ArgList, Upload: true},
+		},
+		AppIDs: command.AllAppIDs(),
+		Handler: func(
+			_ context.Context, cmd command.Command, _ command.AppID, _ string, args ...string,
+		) command.CommandResult {
+			return cmd.SuccessfulResult("%d validators", len(command.SplitList(args)))
+		},
+	})
+	be.rootCmd.AddSubCommand(cmdValidator)
+	be.rootCmd.AddHelpSubCommand()
+
+	assert.True(t, be.AcceptsUpload([]string{"validator", "bulk"}))
+	assert.False(t, be.AcceptsUpload([]string{"validator"}))
+	assert.False(t, be.AcceptsUpload([]string{"help"}))
+
+	// the slash command of Discord sends the list in one option, longer than a text argument.
+	validators := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		addr := crypto.NewAddress(crypto.AddressTypeValidator, append(make([]byte, 19), byte(i)))
+		validators = append(validators, addr.String())
+	}
+	list := strings.Join(validators, ",")
+	require.Greater(t, len(list), command.MaxArgLength)
+
+	res := be.RunInGuild(command.AppIdDiscord, "guild-1", "user-1", []string{"validator", "bulk", list})
+	assert.True(t, res.Successful, res.Message)
+	assert.Equal(t, "20 validators", res.Message)
+
+	testnet := "tpc1" + strings.TrimPrefix(testAddress, "pc1")
+	res = be.RunInGuild(command.AppIdDiscord, "guild-1", "user-1", []string{"validator", "bulk", list + "," + testnet})
+	assert.False(t, res.Successful)
+	assert.Contains(t, res.Message, "Testnet")
+}
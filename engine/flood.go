@@ -0,0 +1,15 @@
+package engine
+
+import "github.com/pagu-project/Pagu/engine/command"
+
+// AllowMessage counts the message of the user in the flood protection, the chat adapters call it
+// for each message to Pagu, like a prefixed command or an interaction, before it runs.
+// The other messages of the chats are not counted. It returns false if the user is muted,
+// then the message is ignored without a response. The admins are not muted.
+func (be *BotEngine) AllowMessage(appID command.AppID, userID string) bool {
+	if be.isAdmin(appID, userID) {
+		return true
+	}
+
+	return be.flood.Allow(be.ctx, appID, userID)
+}
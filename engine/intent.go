@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/intent"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// maxSuggestions is the number of commands suggested when the question matches no command.
+const maxSuggestions = 3
+
+// stopWords are the common words that don't help to find the command of a question.
+var stopWords = []string{"the", "and", "for", "you", "your", "what", "how", "are", "can", "with", "this", "that"}
+
+// intentResult runs the command of a free-text question, like: "is the network ok?".
+// If no command matches the question, it suggests the commands that share words with it.
+func (be *BotEngine) intentResult(ctx context.Context, appID command.AppID, guildID, callerID string, isAdmin bool,
+	tokens []string,
+) command.CommandResult {
+	text := strings.Join(tokens, " ")
+	commands := be.visibleCommands(appID, isAdmin)
+
+	matched, err := be.intents.Match(ctx, text, commands)
+	if err != nil {
+		log.Ctx(ctx).Warn("can't match the intent", "err", err, "text", text)
+	}
+
+	// The matched command should be known, the matcher may answer anything.
+	if _, _, path := be.getCommand(matched); len(path) != 0 {
+		log.Ctx(ctx).Debug("matched intent", "callerID", callerID, "text", text, "tokens", matched)
+
+		return be.run(ctx, appID, guildID, callerID, matched)
+	}
+
+	res := be.rootCmd.RenderResult(appID, "intent_suggestions", map[string]any{
+		"Text":        text,
+		"Suggestions": suggestCommands(text, commands),
+	})
+	res.Successful = false
+
+	return res
+}
+
+// visibleCommands returns the runnable commands that are visible to the caller.
+func (be *BotEngine) visibleCommands(appID command.AppID, isAdmin bool) []intent.Command {
+	commands := make([]intent.Command, 0)
+
+	var walk func(cmd command.Command, path []string)
+	walk = func(cmd command.Command, path []string) {
+		for _, sc := range cmd.SubCommands {
+			if sc.Name == command.HelpCommandName || !sc.IsVisible(appID, isAdmin) {
+				continue
+			}
+
+			scPath := append(slices.Clone(path), sc.Name)
+			if sc.Handler != nil {
+				commands = append(commands, intent.Command{
+					Usage: sc.Usage(strings.Join(scPath, " ")),
+					Desc:  sc.Desc,
+				})
+			}
+			walk(sc, scPath)
+		}
+	}
+	walk(be.rootCmd, nil)
+
+	return commands
+}
+
+// suggestCommands returns the commands that share the most words with the text.
+func suggestCommands(text string, commands []intent.Command) []intent.Command {
+	words := questionWords(text)
+
+	type suggestion struct {
+		cmd   intent.Command
+		score int
+	}
+	suggestions := make([]suggestion, 0)
+	for _, cmd := range commands {
+		score := 0
+		for _, word := range questionWords(cmd.Usage + " " + cmd.Desc) {
+			if slices.Contains(words, word) {
+				score++
+			}
+		}
+
+		if score > 0 {
+			suggestions = append(suggestions, suggestion{cmd: cmd, score: score})
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int {
+		return b.score - a.score
+	})
+
+	result := make([]intent.Command, 0, maxSuggestions)
+	for i := 0; i < len(suggestions) && i < maxSuggestions; i++ {
+		result = append(result, suggestions[i].cmd)
+	}
+
+	return result
+}
+
+// questionWords returns the lower case words of the text, short and common words are ignored.
+func questionWords(text string) []string {
+	words := make([]string, 0)
+	for _, field := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !('a' <= r && r <= 'z') && !('0' <= r && r <= '9')
+	}) {
+		if len(field) > 2 && !slices.Contains(stopWords, field) && !slices.Contains(words, field) {
+			words = append(words, field)
+		}
+	}
+
+	return words
+}
+
+// newIntentMatcher creates the matcher of the free-text questions, it's nil if the NLP layer is disabled.
+// The keyword rules are tried first, then the LLM if it's configured.
+func newIntentMatcher(cfg config.NLP) intent.Matcher {
+	if !cfg.Enable {
+		return nil
+	}
+
+	matchers := intent.Chain{intent.NewKeywords(intent.DefaultRules())}
+	if cfg.LLMURL != "" {
+		matchers = append(matchers, intent.NewLLM(cfg.LLMURL, cfg.LLMAPIKey, cfg.LLMModel))
+	}
+
+	return matchers
+}
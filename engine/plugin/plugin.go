@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+// CommandProvider provides a command group, like the command packages of the engine.
+type CommandProvider interface {
+	GetCommand() command.Command
+}
+
+// Dependencies are the services of the engine that the plugins can use.
+// The plugins can add their jobs to the scheduler, it's started after the plugins are loaded.
+type Dependencies struct {
+	Ctx       context.Context
+	ClientMgr *client.Mgr
+	DB        *database.DB
+	Notifier  *notify.Hub
+	Scheduler *scheduler.Scheduler
+}
+
+// Factory creates the command provider of a plugin.
+type Factory func(deps Dependencies) (CommandProvider, error)
+
+var (
+	lock      sync.Mutex
+	factories = make(map[string]Factory)
+)
+
+// Register registers the factory of a plugin, it's called by the init function of the plugin package.
+// The plugins are compiled in by importing their package in the main package, like:
+//
+//	import _ "github.com/example/pagu-exchange"
+//
+// It panics if the factory is nil or the name is registered already.
+func Register(name string, factory Factory) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if factory == nil {
+		panic("plugin: the factory of " + name + " is nil")
+	}
+
+	if _, ok := factories[name]; ok {
+		panic("plugin: " + name + " is registered already")
+	}
+
+	factories[name] = factory
+}
+
+// Names returns the names of the registered plugins, sorted.
+func Names() []string {
+	lock.Lock()
+	defer lock.Unlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Load creates the command providers of the registered plugins, in the order of their names.
+func Load(deps Dependencies) ([]CommandProvider, error) {
+	names := Names()
+	providers := make([]CommandProvider, 0, len(names))
+	for _, name := range names {
+		lock.Lock()
+		factory := factories[name]
+		lock.Unlock()
+
+		provider, err := factory(deps)
+		if err != nil {
+			return nil, fmt.Errorf("can't load the plugin %s: %w", name, err)
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type provider struct {
+	name string
+}
+
+func (p provider) GetCommand() command.Command {
+	return command.Command{
+		Name:   p.name,
+		AppIDs: command.AllAppIDs(),
+	}
+}
+
+func resetFactories() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	factories = make(map[string]Factory)
+}
+
+func TestRegister(t *testing.T) {
+	resetFactories()
+	t.Cleanup(resetFactories)
+
+	Register("exchange", func(_ Dependencies) (CommandProvider, error) {
+		return provider{name: "exchange"}, nil
+	})
+	Register("bridge", func(_ Dependencies) (CommandProvider, error) {
+		return provider{name: "bridge"}, nil
+	})
+
+	assert.Equal(t, []string{"bridge", "exchange"}, Names())
+
+	assert.Panics(t, func() {
+		Register("bridge", func(_ Dependencies) (CommandProvider, error) {
+			return provider{name: "bridge"}, nil
+		})
+	})
+	assert.Panics(t, func() {
+		Register("nil", nil)
+	})
+
+	providers, err := Load(Dependencies{})
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	assert.Equal(t, "bridge", providers[0].GetCommand().Name)
+	assert.Equal(t, "exchange", providers[1].GetCommand().Name)
+}
+
+func TestLoadError(t *testing.T) {
+	resetFactories()
+	t.Cleanup(resetFactories)
+
+	Register("broken", func(_ Dependencies) (CommandProvider, error) {
+		return nil, errors.New("no API key")
+	})
+
+	_, err := Load(Dependencies{})
+	assert.ErrorContains(t, err, "broken")
+	assert.ErrorContains(t, err, "no API key")
+}
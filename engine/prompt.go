@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// promptCancel is the answer that stops the prompt.
+const promptCancel = "cancel"
+
+// prompt is a command that waits for a missing argument of the caller.
+type prompt struct {
+	cmd       command.Command
+	tokens    []string // The command path and the given arguments.
+	arg       command.Args
+	expiresAt time.Time
+}
+
+// promptStore keeps the prompt of each caller for a short time,
+// so the next message of the caller is the missing argument.
+type promptStore struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	prompts map[string]prompt
+}
+
+// newPromptStore creates the prompt store, a zero TTL disables the prompts.
+func newPromptStore(ttl time.Duration) *promptStore {
+	return &promptStore{
+		ttl:     ttl,
+		now:     time.Now,
+		prompts: make(map[string]prompt),
+	}
+}
+
+// Ask keeps the prompt of the caller, it replaces the previous one. It returns false if the prompts are disabled.
+func (s *promptStore) Ask(appID command.AppID, callerID string, cmd command.Command,
+	tokens []string, arg command.Args,
+) bool {
+	if s == nil || s.ttl <= 0 || callerID == "" || !appID.Supports(command.CapabilityPrompt) {
+		return false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	for key, p := range s.prompts {
+		if now.After(p.expiresAt) {
+			delete(s.prompts, key)
+		}
+	}
+
+	s.prompts[contextKey(appID, callerID)] = prompt{
+		cmd:       cmd,
+		tokens:    slices.Clone(tokens),
+		arg:       arg,
+		expiresAt: now.Add(s.ttl),
+	}
+
+	return true
+}
+
+// Take returns the prompt of the caller if it's not expired, and removes it.
+func (s *promptStore) Take(appID command.AppID, callerID string) (prompt, bool) {
+	if s == nil {
+		return prompt{}, false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := contextKey(appID, callerID)
+	p, ok := s.prompts[key]
+	delete(s.prompts, key)
+	if !ok || s.now().After(p.expiresAt) {
+		return prompt{}, false
+	}
+
+	return p, true
+}
+
+// Pending checks if the caller has a prompt that is not expired.
+func (s *promptStore) Pending(appID command.AppID, callerID string) bool {
+	if s == nil {
+		return false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p, ok := s.prompts[contextKey(appID, callerID)]
+
+	return ok && !s.now().After(p.expiresAt)
+}
+
+// Prompted checks if the caller is asked for a missing argument, the next message of the caller is the answer,
+// even without the command prefix.
+func (be *BotEngine) Prompted(appID command.AppID, callerID string) bool {
+	return be.prompts.Pending(appID, callerID)
+}
+
+// answerPrompt runs the command of the prompt with the message of the caller as the missing argument,
+// like the validator address. It returns false if the caller has no prompt or the message is another command,
+// the prompt is dropped then.
+func (be *BotEngine) answerPrompt(ctx context.Context, appID command.AppID, guildID, callerID string,
+	tokens []string,
+) (command.CommandResult, bool) {
+	p, ok := be.prompts.Take(appID, callerID)
+	if !ok {
+		return command.CommandResult{}, false
+	}
+
+	if _, _, path := be.getCommand(tokens); len(path) != 0 {
+		return command.CommandResult{}, false
+	}
+
+	answer := strings.TrimSpace(strings.Join(tokens, " "))
+	if answer == "" {
+		return command.CommandResult{}, false
+	}
+
+	if strings.EqualFold(answer, promptCancel) {
+		return p.cmd.SuccessfulResult("The command is canceled"), true
+	}
+
+	// a free text is the rest of the words, the other arguments are one word.
+	answered := append(p.tokens, answer)
+	if p.arg.Variadic {
+		answered = append(p.tokens, strings.Fields(answer)...)
+	}
+
+	log.Ctx(ctx).Debug("answered prompt", "callerID", callerID, "tokens", answered)
+
+	return be.run(ctx, appID, guildID, callerID, answered), true
+}
+
+// promptResult asks the caller for the missing argument of the command, it returns false if the platform
+// doesn't support the prompts, then the usage error is returned.
+func (be *BotEngine) promptResult(cmd command.Command, appID command.AppID, callerID string,
+	tokens, args []string,
+) (command.CommandResult, bool) {
+	if len(args) >= len(cmd.Args) || cmd.Args[len(args)].Optional {
+		return command.CommandResult{}, false
+	}
+
+	arg := cmd.Args[len(args)]
+	if !be.prompts.Ask(appID, callerID, cmd, tokens, arg) {
+		return command.CommandResult{}, false
+	}
+
+	return cmd.RenderResult(appID, "argument_prompt", map[string]any{
+		"Arg":     arg,
+		"Cancel":  promptCancel,
+		"Minutes": int(be.prompts.ttl.Minutes()),
+	}), true
+}
@@ -0,0 +1,46 @@
+package engine
+
+import "github.com/pagu-project/Pagu/engine/command"
+
+// ServerAdmins checks the admins of the servers of a platform, like the admins of a Telegram group.
+type ServerAdmins interface {
+	IsServerAdmin(guildID, userID string) bool
+	// HasChannel returns true if the channel is in the server, like a channel of the Discord server.
+	HasChannel(guildID, channelID string) bool
+}
+
+// SetServerAdmins sets the admins of the servers of the platform, they run the server admin commands,
+// like the setup, for their own server.
+func (be *BotEngine) SetServerAdmins(appID command.AppID, admins ServerAdmins) {
+	be.serverAdminsLock.Lock()
+	defer be.serverAdminsLock.Unlock()
+
+	be.serverAdmins[appID] = admins
+}
+
+// isServerAdmin returns true if the caller is an admin of the server, the private chats have no admin.
+func (be *BotEngine) isServerAdmin(appID command.AppID, guildID, callerID string) bool {
+	if guildID == "" || callerID == "" {
+		return false
+	}
+
+	be.serverAdminsLock.RLock()
+	admins, ok := be.serverAdmins[appID]
+	be.serverAdminsLock.RUnlock()
+
+	return ok && admins.IsServerAdmin(guildID, callerID)
+}
+
+// isServerChannel returns true if the channel is in the server, so the setup of a server doesn't point
+// the announcements to a channel of another server.
+func (be *BotEngine) isServerChannel(appID command.AppID, guildID, channelID string) bool {
+	if guildID == "" || channelID == "" {
+		return false
+	}
+
+	be.serverAdminsLock.RLock()
+	admins, ok := be.serverAdmins[appID]
+	be.serverAdminsLock.RUnlock()
+
+	return ok && admins.HasChannel(guildID, channelID)
+}
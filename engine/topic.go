@@ -0,0 +1,27 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+// RunInTopic runs the command of a topic in a forum group, like a Telegram forum.
+// The admins bind the command groups to the topics, like the faucet to the "Testnet" topic,
+// the other commands are rejected there. The group is the server of the quota.
+func (be *BotEngine) RunInTopic(appID command.AppID, chatID, topicID, callerID string,
+	tokens []string,
+) command.CommandResult {
+	cmd, _, path := be.getCommand(tokens)
+	if len(path) != 0 && path[0] != command.HelpCommandName &&
+		!be.settings.TopicAllows(chatID, topicID, path[0]) {
+		if groups, ok := be.settings.TopicGroups(chatID, topicID); ok {
+			return cmd.FailedResult("This topic is for the `%s` commands, please ask in the other topics!",
+				strings.Join(groups, "`, `"))
+		}
+
+		return cmd.FailedResult("The `%s` commands are in their own topic of this group, please ask there!", path[0])
+	}
+
+	return be.RunInGuild(appID, chatID, callerID, tokens)
+}
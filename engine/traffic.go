@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// TrafficRecord is a command that is run on the engine, the recorded traffic is replayed by the benchmarks.
+type TrafficRecord struct {
+	AppID    command.AppID `json:"app_id"`
+	CallerID string        `json:"caller_id"` // A hash of the caller ID, so the recorded traffic has no user IDs.
+	Tokens   []string      `json:"tokens"`
+}
+
+// trafficRecorder appends the commands to a file, one JSON record per line.
+type trafficRecorder struct {
+	lock sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+func newTrafficRecorder(path string) (*trafficRecorder, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trafficRecorder{
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+func (r *trafficRecorder) Record(appID command.AppID, callerID string, tokens []string) {
+	if r == nil {
+		return
+	}
+
+	hash := sha256.Sum256([]byte(callerID))
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.enc.Encode(TrafficRecord{
+		AppID:    appID,
+		CallerID: hex.EncodeToString(hash[:8]),
+		Tokens:   tokens,
+	}); err != nil {
+		log.Warn("can't record the command", "err", err)
+	}
+}
+
+func (r *trafficRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	return r.file.Close()
+}
+
+// ReadTraffic reads the recorded traffic, the empty lines are skipped.
+func ReadTraffic(reader io.Reader) ([]TrafficRecord, error) {
+	records := make([]TrafficRecord, 0)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record TrafficRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
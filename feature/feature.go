@@ -0,0 +1,159 @@
+package feature
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// refreshInterval is how often the flags are loaded from the store,
+// so the flags that are changed on another instance are applied.
+const refreshInterval = 30 * time.Second
+
+// namePattern is the name of a flag, the command flags are the command path joined by dots, like: "network.map".
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*(\.[a-z0-9][a-z0-9-]*)*$`)
+
+// Store keeps the flags where all the Pagu instances can see them, like the database.
+type Store interface {
+	SetFeatureFlag(f *database.FeatureFlag) error
+	GetFeatureFlags() ([]*database.FeatureFlag, error)
+	DeleteFeatureFlag(name string) (bool, error)
+}
+
+// Flags are the feature flags that enable or disable the commands and the experimental behaviors at runtime.
+// The features without a flag have their default state, the commands are enabled by default.
+type Flags struct {
+	lock     sync.Mutex
+	store    Store
+	now      func() time.Time
+	flags    map[string]bool
+	loadedAt time.Time
+}
+
+func NewFlags(store Store) *Flags {
+	return &Flags{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// ValidName checks the name of a flag, like: "network.map".
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// CommandName returns the flag name of the command path.
+func CommandName(path []string) string {
+	return strings.Join(path, ".")
+}
+
+// Enabled checks the flag of the feature, the default is returned if it's not set.
+func (f *Flags) Enabled(name string, def bool) bool {
+	if f == nil {
+		return def
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	enabled, ok := f.load()[name]
+	if !ok {
+		return def
+	}
+
+	return enabled
+}
+
+// CommandEnabled checks the flags of the command and its parents, a disabled group disables its sub commands.
+func (f *Flags) CommandEnabled(path []string) bool {
+	if f == nil {
+		return true
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	flags := f.load()
+	for i := range path {
+		if enabled, ok := flags[CommandName(path[:i+1])]; ok && !enabled {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *Flags) Enable(name, updatedBy string) error {
+	return f.set(name, true, updatedBy)
+}
+
+func (f *Flags) Disable(name, updatedBy string) error {
+	return f.set(name, false, updatedBy)
+}
+
+// Reset removes the flag so the feature has its default state, it returns false if the flag is not set.
+func (f *Flags) Reset(name string) (bool, error) {
+	removed, err := f.store.DeleteFeatureFlag(name)
+	if err != nil {
+		return false, err
+	}
+
+	f.lock.Lock()
+	delete(f.flags, name)
+	f.lock.Unlock()
+
+	return removed, nil
+}
+
+// List returns the flags that are set, sorted by name.
+func (f *Flags) List() ([]*database.FeatureFlag, error) {
+	return f.store.GetFeatureFlags()
+}
+
+func (f *Flags) set(name string, enabled bool, updatedBy string) error {
+	if err := f.store.SetFeatureFlag(&database.FeatureFlag{
+		Name:      name,
+		Enabled:   enabled,
+		UpdatedBy: updatedBy,
+	}); err != nil {
+		return err
+	}
+
+	f.lock.Lock()
+	if f.flags == nil {
+		f.flags = make(map[string]bool)
+	}
+	f.flags[name] = enabled
+	f.lock.Unlock()
+
+	return nil
+}
+
+// load returns the flags, they are loaded from the store if the last load is older than the refresh interval.
+// The last loaded flags are kept if the store fails, so a database error doesn't change the features.
+// The lock must be held while the map is read, because the flags are changed in place.
+func (f *Flags) load() map[string]bool {
+	if f.flags != nil && f.now().Sub(f.loadedAt) < refreshInterval {
+		return f.flags
+	}
+
+	list, err := f.store.GetFeatureFlags()
+	if err != nil {
+		log.Warn("can't load the feature flags", "err", err)
+
+		return f.flags
+	}
+
+	flags := make(map[string]bool, len(list))
+	for _, flag := range list {
+		flags[flag.Name] = flag.Enabled
+	}
+	f.flags = flags
+	f.loadedAt = f.now()
+
+	return f.flags
+}
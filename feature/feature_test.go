@@ -0,0 +1,149 @@
+package feature
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	lock  sync.Mutex
+	flags map[string]*database.FeatureFlag
+	err   error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		flags: make(map[string]*database.FeatureFlag),
+	}
+}
+
+func (s *memoryStore) SetFeatureFlag(f *database.FeatureFlag) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.flags[f.Name] = f
+
+	return nil
+}
+
+func (s *memoryStore) GetFeatureFlags() ([]*database.FeatureFlag, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	flags := make([]*database.FeatureFlag, 0, len(s.flags))
+	for _, f := range s.flags {
+		flags = append(flags, f)
+	}
+	slices.SortFunc(flags, func(a, b *database.FeatureFlag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return flags, nil
+}
+
+func (s *memoryStore) DeleteFeatureFlag(name string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, ok := s.flags[name]
+	delete(s.flags, name)
+
+	return ok, nil
+}
+
+func TestValidName(t *testing.T) {
+	assert.True(t, ValidName("network"))
+	assert.True(t, ValidName("network.map"))
+	assert.True(t, ValidName("network.node-info"))
+	assert.False(t, ValidName(""))
+	assert.False(t, ValidName("network."))
+	assert.False(t, ValidName(".map"))
+	assert.False(t, ValidName("Network Map"))
+}
+
+func TestCommandEnabled(t *testing.T) {
+	flags := NewFlags(newMemoryStore())
+
+	assert.True(t, flags.CommandEnabled([]string{"network", "map"}), "enabled by default")
+
+	require.NoError(t, flags.Disable("network.map", "admin"))
+	assert.False(t, flags.CommandEnabled([]string{"network", "map"}))
+	assert.True(t, flags.CommandEnabled([]string{"network", "health"}))
+
+	require.NoError(t, flags.Disable("network", "admin"))
+	require.NoError(t, flags.Enable("network.map", "admin"))
+	assert.False(t, flags.CommandEnabled([]string{"network", "map"}), "the group is disabled")
+
+	removed, err := flags.Reset("network")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	assert.True(t, flags.CommandEnabled([]string{"network", "map"}))
+
+	var nilFlags *Flags
+	assert.True(t, nilFlags.CommandEnabled([]string{"network"}))
+	assert.True(t, nilFlags.Enabled("experimental", true))
+}
+
+func TestEnabled(t *testing.T) {
+	flags := NewFlags(newMemoryStore())
+
+	assert.False(t, flags.Enabled("experimental.llm", false))
+	require.NoError(t, flags.Enable("experimental.llm", "admin"))
+	assert.True(t, flags.Enabled("experimental.llm", false))
+}
+
+func TestRefresh(t *testing.T) {
+	store := newMemoryStore()
+	now := time.Now()
+	flags := NewFlags(store)
+	flags.now = func() time.Time { return now }
+
+	assert.True(t, flags.CommandEnabled([]string{"network"}))
+
+	// another instance disables the command.
+	store.flags["network"] = &database.FeatureFlag{Name: "network", Enabled: false}
+	assert.True(t, flags.CommandEnabled([]string{"network"}), "not refreshed yet")
+
+	now = now.Add(refreshInterval)
+	assert.False(t, flags.CommandEnabled([]string{"network"}))
+
+	// the last loaded flags are kept if the store fails.
+	store.err = errors.New("database is locked")
+	now = now.Add(refreshInterval)
+	assert.False(t, flags.CommandEnabled([]string{"network"}))
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	flags := NewFlags(newMemoryStore())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, flags.Disable("network.map", "admin"))
+			_, err := flags.Reset("network.map")
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+
+			flags.Enabled("network.map", true)
+			flags.CommandEnabled([]string{"network", "map"})
+		}()
+	}
+	wg.Wait()
+}
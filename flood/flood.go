@@ -0,0 +1,105 @@
+package flood
+
+import (
+	"context"
+	"time"
+
+	"github.com/pagu-project/Pagu/cache"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+)
+
+// Guard detects the users that flood the chat platforms with messages, like the spam bots.
+// It's separate from the rate limit of the commands: it counts all the messages that the adapters receive.
+// A flooding user is muted for a while, the messages are ignored without a response and the moderators
+// are notified. The counters are in the cache store, so the instances share them with Redis.
+type Guard struct {
+	store    cache.Store
+	counter  *cache.Limiter
+	messages int64
+	window   time.Duration
+	mute     time.Duration
+	hub      *notify.Hub
+	channels []notify.Channel
+}
+
+// NewGuard returns the guard that allows the messages per user in the window, zero messages disables it.
+func NewGuard(store cache.Store, messages int64, window, mute time.Duration,
+	hub *notify.Hub, channels []notify.Channel,
+) *Guard {
+	return &Guard{
+		store:    store,
+		counter:  cache.NewLimiter(store, messages, window),
+		messages: messages,
+		window:   window,
+		mute:     mute,
+		hub:      hub,
+		channels: channels,
+	}
+}
+
+// Allow counts the message of the user, it returns false if the user is muted.
+// The errors of the store allow the message, the flood protection doesn't stop the bot.
+func (g *Guard) Allow(ctx context.Context, appID command.AppID, userID string) bool {
+	if g == nil || g.messages <= 0 || userID == "" {
+		return true
+	}
+
+	key := appID.String() + ":" + userID
+	if _, muted, err := g.store.Get(ctx, "mute:"+key); err != nil {
+		log.Warn("can't check the mute of the user", "err", err, "user", key)
+	} else if muted {
+		return false
+	}
+
+	ok, err := g.counter.Allow(ctx, "flood:"+key)
+	if err != nil {
+		log.Warn("can't count the messages of the user", "err", err, "user", key)
+
+		return true
+	}
+
+	if ok {
+		return true
+	}
+
+	// without the mute only the messages over the limit are ignored, until the next window.
+	if g.mute <= 0 {
+		return false
+	}
+
+	if err := g.store.Set(ctx, "mute:"+key, []byte("1"), g.mute); err != nil {
+		log.Error("can't mute the user", "err", err, "user", key)
+	}
+
+	log.Warn("the user is muted for flooding", "user", key, "mute", g.mute)
+	g.alert(appID, userID)
+
+	return false
+}
+
+func (g *Guard) alert(appID command.AppID, userID string) {
+	for _, channel := range g.channels {
+		if !g.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, "flood_alert", map[string]any{
+			"Platform": appID.String(),
+			"UserID":   userID,
+			"Messages": g.messages,
+			"Window":   g.window,
+			"Mute":     g.mute,
+		})
+		if err != nil {
+			log.Error("can't render the flood alert", "err", err)
+
+			continue
+		}
+
+		if err := g.hub.Announce(channel, msg); err != nil {
+			log.Warn("can't post the flood alert", "err", err, "channel", channel)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package flood
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/cache"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func TestGuard(t *testing.T) {
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	ctx := context.Background()
+	g := NewGuard(cache.NewMemoryStore(), 3, 200*time.Millisecond, 400*time.Millisecond, hub, []notify.Channel{
+		{AppID: command.AppIdDiscord, ID: "mods"},
+		{AppID: command.AppIdTelegram, ID: "-1001"},
+	})
+
+	for i := 0; i < 3; i++ {
+		require.True(t, g.Allow(ctx, command.AppIdTelegram, "alice"))
+	}
+	assert.False(t, g.Allow(ctx, command.AppIdTelegram, "alice"), "over the limit")
+	assert.False(t, g.Allow(ctx, command.AppIdTelegram, "alice"), "muted")
+	assert.True(t, g.Allow(ctx, command.AppIdTelegram, "bob"), "the others are not muted")
+	assert.True(t, g.Allow(ctx, command.AppIdDiscord, "alice"), "the users are per platform")
+
+	require.Len(t, discord["mods"], 1, "the moderators are notified once")
+	assert.Equal(t, "Flood protection⚠️: the Telegram user alice sent over 3 messages in 200ms, "+
+		"their messages are ignored for 400ms.", discord["mods"][0])
+
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, g.Allow(ctx, command.AppIdTelegram, "alice"), "the mute is over")
+
+	t.Run("disabled", func(t *testing.T) {
+		g := NewGuard(cache.NewMemoryStore(), 0, time.Minute, time.Minute, hub, nil)
+		for i := 0; i < 10; i++ {
+			assert.True(t, g.Allow(ctx, command.AppIdTelegram, "alice"))
+		}
+
+		var nilGuard *Guard
+		assert.True(t, nilGuard.Allow(ctx, command.AppIdTelegram, "alice"))
+	})
+}
@@ -0,0 +1,186 @@
+package fork
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+// checkInterval is the block time of Pactus, the height is checked on each block.
+const checkInterval = 10 * time.Second
+
+// confirmations is how many blocks the compared block is behind the last block,
+// so the nodes that are a few blocks behind have it too.
+const confirmations = 2
+
+// Status is the result of the last comparison of the block hashes.
+type Status struct {
+	Checked  bool
+	Height   uint32
+	Diverged bool // The nodes have different hashes for the block.
+	Hashes   []client.NodeHash
+}
+
+// Store keeps the posted alerts.
+type Store interface {
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Checker compares the block hashes of all the nodes every N blocks, different hashes mean a fork.
+// The operators are alerted when the fork is detected and when it's resolved.
+type Checker struct {
+	lock      sync.Mutex
+	clientMgr *client.Mgr
+	store     Store
+	hub       *notify.Hub
+	channels  []notify.Channel
+	every     uint32
+	status    Status
+}
+
+// NewChecker creates the checker that compares the hashes every N blocks, zero disables it.
+func NewChecker(clientMgr *client.Mgr, store Store, hub *notify.Hub,
+	channels []notify.Channel, every uint32,
+) *Checker {
+	return &Checker{
+		clientMgr: clientMgr,
+		store:     store,
+		hub:       hub,
+		channels:  channels,
+		every:     every,
+	}
+}
+
+// Status returns the result of the last comparison.
+func (c *Checker) Status() Status {
+	if c == nil {
+		return Status{}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.status
+}
+
+// Job returns the scheduler job of the checks. It's not exclusive, each instance keeps its own status.
+// The compared heights are the multiples of N, so an alert of the instances is posted once.
+func (c *Checker) Job() scheduler.Job {
+	interval := checkInterval
+	if c.every == 0 {
+		interval = 0
+	}
+
+	return scheduler.Job{
+		Name:      "fork",
+		Interval:  interval,
+		Exclusive: false,
+		Run:       c.Run,
+	}
+}
+
+// Run compares the hashes of the last multiple of N blocks, if it's not compared yet.
+func (c *Checker) Run(ctx context.Context) error {
+	if c.every == 0 {
+		return nil
+	}
+
+	height, err := c.clientMgr.GetBlockchainHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	if height <= confirmations {
+		return nil
+	}
+
+	target := (height - confirmations) / c.every * c.every
+	last := c.Status()
+	if target == 0 || (last.Checked && target <= last.Height) {
+		return nil
+	}
+
+	hashes := c.clientMgr.GetBlockHashes(ctx, target)
+	status := Status{
+		Checked:  true,
+		Height:   target,
+		Diverged: diverged(hashes),
+		Hashes:   hashes,
+	}
+
+	c.lock.Lock()
+	c.status = status
+	c.lock.Unlock()
+
+	switch {
+	case status.Diverged && !last.Diverged:
+		log.Error("fork detected, the nodes have different block hashes", "height", target)
+		c.alert("detected", status)
+
+	case !status.Diverged && last.Diverged:
+		log.Info("fork resolved, the nodes have the same block hash", "height", target)
+		c.alert("resolved", status)
+	}
+
+	return nil
+}
+
+// diverged checks if the nodes that returned the hash have different hashes.
+func diverged(hashes []client.NodeHash) bool {
+	first := ""
+	for _, nh := range hashes {
+		if nh.Err != nil {
+			continue
+		}
+
+		if first == "" {
+			first = nh.Hash
+		} else if nh.Hash != first {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Checker) alert(kind string, status Status) {
+	for _, channel := range c.channels {
+		if !c.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		key := "fork:" + kind + ":" + strconv.FormatUint(uint64(status.Height), 10) + ":" + channel.String()
+		claimed, err := c.store.ClaimAnnouncement(key)
+		if err != nil {
+			log.Error("can't claim the fork alert", "err", err, "channel", channel)
+
+			continue
+		}
+
+		if !claimed {
+			// another instance posted it.
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, "fork_alert", map[string]any{
+			"Resolved": kind == "resolved",
+			"Status":   status,
+		})
+		if err != nil {
+			log.Error("can't render the fork alert", "err", err)
+
+			continue
+		}
+
+		if err := c.hub.Announce(channel, msg); err != nil {
+			log.Warn("can't post the fork alert", "err", err, "channel", channel)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package fork
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type memoryStore map[string]bool
+
+func (s memoryStore) ClaimAnnouncement(key string) (bool, error) {
+	if s[key] {
+		return false, nil
+	}
+	s[key] = true
+
+	return true, nil
+}
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func TestChecker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	height := uint32(21)
+	hashes := map[string][]byte{"node-a": {0xaa}, "node-b": {0xaa}}
+
+	cm := client.NewClientMgr(context.Background())
+	for _, node := range []string{"node-a", "node-b"} {
+		c := client.NewMockIClient(ctrl)
+		c.EXPECT().GetBlockchainHeight(gomock.Any()).DoAndReturn(func(_ context.Context) (uint32, error) {
+			return height, nil
+		}).AnyTimes()
+		c.EXPECT().GetBlockHash(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ uint32) ([]byte, error) {
+			return hashes[node], nil
+		}).AnyTimes()
+		c.EXPECT().Target().Return(node).AnyTimes()
+		cm.AddClient(c)
+	}
+
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	store := memoryStore{}
+	checker := NewChecker(cm, store, hub, []notify.Channel{{AppID: command.AppIdDiscord, ID: "ops"}}, 10)
+
+	assert.False(t, checker.Status().Checked)
+	require.NoError(t, checker.Run(context.Background()))
+	assert.Equal(t, Status{
+		Checked: true,
+		Height:  10,
+		Hashes:  checker.Status().Hashes,
+	}, checker.Status(), "the block is two blocks behind the last one")
+	assert.Empty(t, discord["ops"])
+
+	height = 32
+	hashes["node-b"] = []byte{0xbb}
+	require.NoError(t, checker.Run(context.Background()))
+	assert.True(t, checker.Status().Diverged)
+	assert.Equal(t, uint32(30), checker.Status().Height)
+	require.Len(t, discord["ops"], 1)
+	assert.Contains(t, discord["ops"][0], "Fork detected")
+	assert.Contains(t, discord["ops"][0], "node-b: bb")
+
+	height = 42
+	require.NoError(t, checker.Run(context.Background()))
+	assert.Len(t, discord["ops"], 1, "the fork is alerted once")
+
+	height = 52
+	hashes["node-b"] = []byte{0xaa}
+	require.NoError(t, checker.Run(context.Background()))
+	assert.False(t, checker.Status().Diverged)
+	require.Len(t, discord["ops"], 2)
+	assert.Contains(t, discord["ops"][1], "Fork resolved")
+
+	var nilChecker *Checker
+	assert.Equal(t, Status{}, nilChecker.Status())
+}
@@ -0,0 +1,131 @@
+// Package golden compares the outputs of the renderers with the files in the testdata directory,
+// so a change of the formatting, like an escape or a truncation, fails the tests until it's reviewed.
+package golden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// UpdateEnv is the environment variable that writes the outputs to the golden files instead of comparing them,
+// like: PAGU_GOLDEN_UPDATE=1 go test ./discord.
+const UpdateEnv = "PAGU_GOLDEN_UPDATE"
+
+// Limit is the message limit of the fixtures, the long fixture is over it.
+const Limit = 1_000
+
+// bech32Chars are the characters of the addresses, the addresses of the long fixture are made of them.
+const bech32Chars = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Fixture is a result that the renderers of the platforms are tested with.
+type Fixture struct {
+	Name   string
+	Result command.CommandResult
+}
+
+// Fixtures returns the results that cover the formatting: the links to the explorer, the markup characters,
+// the code spans, a failed result, an attachment and a message over the limit.
+func Fixtures() []Fixture {
+	long := make([]string, 0, 20)
+	for i := 1; i <= 20; i++ {
+		long = append(long, fmt.Sprintf("Validator %d: pc1p%s is online", i, strings.Repeat(bech32Chars[i:i+1], 38)))
+	}
+
+	return []Fixture{
+		{
+			Name: "status",
+			Result: command.CommandResult{
+				Title:      "Network status",
+				Successful: true,
+				Message: "Current Block Height: 1,234,567\n" +
+					"Validator: pc1pqpu5tkuctj6ecxjs85f9apm802hf8xgdxqrhz8\n" +
+					"Transaction: 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b\n" +
+					"Copy: `pc1pqpu5tkuctj6ecxjs85f9apm802hf8xgdxqrhz8`\n" +
+					"Docs: https://docs.pactus.org/?a=1&b=2",
+			},
+		},
+		{
+			Name: "markup",
+			Result: command.CommandResult{
+				Title:      "Moniker",
+				Successful: true,
+				Message:    "Moniker: <b>*bold*</b> _under_ & ~strike~ [link](x) @everyone",
+			},
+		},
+		{
+			Name: "failed",
+			Result: command.CommandResult{
+				Title:   "Validator",
+				Message: "pc1pzzz is not a valid address",
+				Code:    command.ErrCodeInvalidArgs,
+			},
+		},
+		{
+			Name: "attachment",
+			Result: command.CommandResult{
+				Title:      "Stake distribution",
+				Successful: true,
+				Message:    "Stake distribution of 3 active validators",
+			}.WithAttachment(command.Attachment{
+				Name:        "stake-distribution.png",
+				ContentType: "image/png",
+				Data:        []byte("png"),
+				AltText:     "Bar chart of the validators by their stake in PAC: <10: 1, 10-100: 2.",
+			}),
+		},
+		{
+			Name: "long",
+			Result: command.CommandResult{
+				Title:      "Validators",
+				Successful: true,
+				Message:    strings.Join(long, "\n"),
+			},
+		},
+	}
+}
+
+// Assert compares the output with the golden file of the name in the testdata directory of the package,
+// or writes the output to it if UpdateEnv is set.
+func Assert(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if os.Getenv(UpdateEnv) != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o600))
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "no golden file, run the test with %s=1 to write it", UpdateEnv)
+	assert.Equal(t, string(want), got,
+		"the output differs from %s, run the test with %s=1 to update it", path, UpdateEnv)
+}
+
+// Result serializes the result for a golden file, the data of the attachments is shown by its size.
+func Result(res command.CommandResult) string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "title: %s\n", res.Title)
+	fmt.Fprintf(&sb, "successful: %t\n", res.Successful)
+	if res.Code != "" {
+		fmt.Fprintf(&sb, "code: %s\n", res.Code)
+	}
+
+	for _, att := range res.Attachments {
+		fmt.Fprintf(&sb, "attachment: %s (%s, %d bytes)\n", att.Name, att.ContentType, len(att.Data))
+		if att.AltText != "" {
+			fmt.Fprintf(&sb, "alt text: %s\n", att.AltText)
+		}
+	}
+	fmt.Fprintf(&sb, "message:\n%s\n", res.Message)
+
+	return sb.String()
+}
@@ -1,8 +1,11 @@
 package http
 
 import (
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pagu-project/Pagu/config"
@@ -22,15 +25,37 @@ type HTTPHandler struct {
 }
 
 func NewHTTPServer(be *engine.BotEngine, cfg config.HTTP) HTTPServer {
+	eServer := echo.New()
+	eServer.IPExtractor = ipExtractor(cfg.TrustedProxies)
+
 	return HTTPServer{
 		handler: HTTPHandler{
 			engine: be,
 		},
-		eServer: echo.New(),
+		eServer: eServer,
 		cfg:     cfg,
 	}
 }
 
+// ipExtractor returns the extractor of the IP of the caller, it's the ID of the caller for the rate limits
+// and the faucet checks. The headers of the caller are not trusted, only the X-Forwarded-For of the trusted proxies.
+func ipExtractor(trustedProxies []*net.IPNet) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
 func (hs *HTTPServer) Start() error {
 	log.Info("Starting HTTP Server", "listen", hs.cfg.Listen)
 	hs.eServer.POST("/run", hs.handler.Run)
@@ -41,8 +66,14 @@ type RunRequest struct {
 	Command string `json:"command"`
 }
 
+// RunResponse is the JSON result of a command, the code is set if the command failed, like: ERR_INVALID_ADDRESS.
+// The retry after is the seconds to retry a blocked command in, like a rate limited one.
+// The request ID is the ID of the command in the logs of Pagu, for the reports of the errors.
 type RunResponse struct {
-	Result string `json:"result"`
+	Result     string            `json:"result"`
+	Code       command.ErrorCode `json:"code,omitempty"`
+	RetryAfter int64             `json:"retry_after,omitempty"`
+	RequestID  string            `json:"request_id,omitempty"`
 }
 
 func (hh *HTTPHandler) Run(c echo.Context) error {
@@ -56,11 +87,126 @@ func (hh *HTTPHandler) Run(c echo.Context) error {
 	tokens := strings.Split(r.Command, " ")
 	beInput = append(beInput, tokens...)
 
+	format, ok := negotiate(c.Request().Header.Get(echo.HeaderAccept))
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotAcceptable, "supported types: "+strings.Join(mediaTypes, ", "))
+	}
+
 	cmdResult := hh.engine.Run(command.AppIdHTTP, c.RealIP(), beInput)
+	if cmdResult.RequestID != "" {
+		c.Response().Header().Set(echo.HeaderXRequestID, cmdResult.RequestID)
+	}
+	retryAfter := retryAfterSeconds(cmdResult.RetryAfter)
+	if retryAfter > 0 {
+		c.Response().Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+	}
+
+	status := statusCode(cmdResult.Code)
+	switch format {
+	case command.FormatMarkdown:
+		return c.Blob(status, mimeMarkdown, []byte(renderMessage(format, cmdResult.Message)))
+
+	case command.FormatHTML:
+		return c.HTML(status, renderMessage(format, cmdResult.Message))
+
+	default:
+		return c.JSON(status, RunResponse{
+			Result:     cmdResult.Message,
+			Code:       cmdResult.Code,
+			RetryAfter: retryAfter,
+			RequestID:  cmdResult.RequestID,
+		})
+	}
+}
+
+// statusCode returns the HTTP status of the result, the blocked commands that can be retried have their own status,
+// so the clients can honor the Retry-After header. The other results are OK, their code is in the response.
+func statusCode(code command.ErrorCode) int {
+	switch code {
+	case command.ErrCodeRateLimited, command.ErrCodeQuotaExceeded:
+		return http.StatusTooManyRequests
+	case command.ErrCodeMaintenance:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusOK
+	}
+}
+
+// retryAfterSeconds rounds the duration up to the seconds, so the clients don't retry before the end.
+func retryAfterSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+const mimeMarkdown = "text/markdown; charset=UTF-8"
+
+// mediaTypes are the supported types of the responses, the first one is the default.
+var mediaTypes = []string{echo.MIMEApplicationJSON, "text/markdown", "text/html"}
+
+var mediaFormats = map[string]command.Format{
+	echo.MIMEApplicationJSON: command.FormatText,
+	"text/markdown":          command.FormatMarkdown,
+	"text/html":              command.FormatHTML,
+}
+
+// negotiate returns the format of the most preferred type in the Accept header, the JSON is the default.
+// It returns false if none of the accepted types are supported.
+func negotiate(accept string) (command.Format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return command.FormatText, true
+	}
+
+	best, bestQ := command.Format(""), 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if key == "q" {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		format, ok := mediaFormats[mediaType]
+		if !ok && (mediaType == "*/*" || mediaType == "application/*") {
+			format, ok = command.FormatText, true
+		}
+
+		// the first type wins the same quality.
+		if ok && q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+
+	return best, bestQ > 0
+}
+
+// renderMessage returns the message in the markup of the format, the text is the result of the JSON as it is.
+func renderMessage(format command.Format, msg string) string {
+	switch format {
+	case command.FormatMarkdown:
+		return command.LinkifyFormat(command.FormatMarkdown, msg)
+
+	case command.FormatHTML:
+		return htmlFragment(msg)
+
+	default:
+		return msg
+	}
+}
+
+// htmlFragment is the message in HTML to embed in a page, the lines are kept.
+func htmlFragment(msg string) string {
+	linked := command.LinkifyFormat(command.FormatHTML, msg)
 
-	return c.JSON(http.StatusOK, RunResponse{
-		Result: cmdResult.Message,
-	})
+	return `<div class="pagu-result">` + strings.ReplaceAll(linked, "\n", "<br>\n") + "</div>"
 }
 
 func (hs *HTTPServer) Stop() error {
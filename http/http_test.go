@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/golden"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		format command.Format
+		ok     bool
+	}{
+		{"", command.FormatText, true},
+		{"*/*", command.FormatText, true},
+		{"application/json", command.FormatText, true},
+		{"text/markdown", command.FormatMarkdown, true},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", command.FormatHTML, true},
+		{"application/json;q=0.5, text/markdown", command.FormatMarkdown, true},
+		{"text/html, text/markdown", command.FormatHTML, true},
+		{"image/png", "", false},
+		{"text/html;q=0", "", false},
+	}
+
+	for _, tt := range tests {
+		format, ok := negotiate(tt.accept)
+		assert.Equal(t, tt.ok, ok, tt.accept)
+		assert.Equal(t, tt.format, format, tt.accept)
+	}
+}
+
+func TestHTMLFragment(t *testing.T) {
+	addr := "pc1zgp0x33hehvczq6dtfjyh9ca8nd0cyw8m8yppaa"
+
+	assert.Equal(t, `<div class="pagu-result">Sent &lt;1 PAC&gt;<br>`+"\n"+
+		`To: <a href="https://pacviewer.com/address/`+addr+`">`+addr+`</a></div>`,
+		htmlFragment("Sent <1 PAC>\nTo: "+addr))
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	assert.Zero(t, retryAfterSeconds(0))
+	assert.Equal(t, int64(1), retryAfterSeconds(time.Millisecond))
+	assert.Equal(t, int64(45), retryAfterSeconds(45*time.Second))
+	assert.Equal(t, int64(46), retryAfterSeconds(45*time.Second+time.Millisecond))
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusTooManyRequests, statusCode(command.ErrCodeRateLimited))
+	assert.Equal(t, http.StatusTooManyRequests, statusCode(command.ErrCodeQuotaExceeded))
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode(command.ErrCodeMaintenance))
+	assert.Equal(t, http.StatusOK, statusCode(command.ErrCodeInvalidArgs))
+	assert.Equal(t, http.StatusOK, statusCode(""))
+}
+
+func TestIPExtractor(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/run", nil)
+	req.RemoteAddr = "10.0.0.2:4321"
+	req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.7")
+	req.Header.Set(echo.HeaderXRealIP, "203.0.113.8")
+
+	assert.Equal(t, "10.0.0.2", ipExtractor(nil)(req), "the headers of the caller are not trusted")
+
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/24")
+	assert.Equal(t, "203.0.113.7", ipExtractor([]*net.IPNet{proxies})(req), "the header of a trusted proxy")
+
+	req.RemoteAddr = "192.168.1.5:4321"
+	assert.Equal(t, "192.168.1.5", ipExtractor([]*net.IPNet{proxies})(req), "the private networks are not trusted")
+}
+
+func TestGoldenResults(t *testing.T) {
+	for _, f := range golden.Fixtures() {
+		for _, format := range []command.Format{command.FormatText, command.FormatMarkdown, command.FormatHTML} {
+			t.Run(f.Name+"/"+string(format), func(t *testing.T) {
+				golden.Assert(t, f.Name+"."+string(format), renderMessage(format, f.Result.Message)+"\n")
+			})
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package i18n
+
+import "fmt"
+
+type CatalogError struct {
+	Name   string
+	Reason string
+}
+
+func (e CatalogError) Error() string {
+	return fmt.Sprintf("invalid catalog %s: %s", e.Name, e.Reason)
+}
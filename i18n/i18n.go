@@ -0,0 +1,46 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"path"
+)
+
+//go:embed catalogs/*.json
+var catalogsFS embed.FS
+
+// Catalog is the localization of a language.
+type Catalog struct {
+	// Locale is the language code, like: "tr".
+	Locale string `json:"locale"`
+	// Aliases are the localized names of the commands by command path, like: "network status": ["durum"].
+	Aliases map[string][]string `json:"aliases"`
+}
+
+// Catalogs returns the embedded catalogs, sorted by locale.
+func Catalogs() ([]Catalog, error) {
+	entries, err := catalogsFS.ReadDir("catalogs")
+	if err != nil {
+		return nil, err
+	}
+
+	catalogs := make([]Catalog, 0, len(entries))
+	for _, entry := range entries {
+		data, err := catalogsFS.ReadFile(path.Join("catalogs", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		catalog := Catalog{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, CatalogError{
+				Name:   entry.Name(),
+				Reason: err.Error(),
+			}
+		}
+
+		catalogs = append(catalogs, catalog)
+	}
+
+	return catalogs, nil
+}
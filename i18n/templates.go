@@ -0,0 +1,34 @@
+package i18n
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"slices"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// Template returns the localized template of the language by its name without the extension,
+// like "release_announcement", false if the language has no such template.
+func Template(locale, name string) (string, bool) {
+	data, err := templatesFS.ReadFile(path.Join("templates", locale, name+".tmpl"))
+	if err != nil {
+		return "", false
+	}
+
+	return string(data), true
+}
+
+// HasLocale checks if the language has the localized templates, like: "tr".
+func HasLocale(locale string) bool {
+	entries, err := templatesFS.ReadDir("templates")
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(entries, func(entry fs.DirEntry) bool {
+		return entry.IsDir() && entry.Name() == locale
+	})
+}
@@ -0,0 +1,51 @@
+package indexer
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Gini returns the Gini coefficient of the stakes, 0 is an equal stake of all the validators
+// and it gets closer to 1 when fewer validators hold the stake.
+func Gini(stakes []int64) float64 {
+	sorted := slices.Clone(stakes)
+	slices.Sort(sorted)
+
+	var total, weighted float64
+	for i, stake := range sorted {
+		total += float64(stake)
+		weighted += float64(i+1) * float64(stake)
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	n := float64(len(sorted))
+
+	return 2*weighted/(n*total) - (n+1)/n
+}
+
+// Nakamoto returns the Nakamoto coefficient of the stakes, the fewest validators that hold over a third of the stake.
+// A third is enough to stop the consensus of Pactus.
+func Nakamoto(stakes []int64) int32 {
+	sorted := slices.Clone(stakes)
+	slices.SortFunc(sorted, func(a, b int64) int {
+		return cmp.Compare(b, a)
+	})
+
+	var total int64
+	for _, stake := range sorted {
+		total += stake
+	}
+
+	var held int64
+	for i, stake := range sorted {
+		held += stake
+		if held*3 > total {
+			return int32(i + 1)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,77 @@
+package indexer
+
+import (
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/utils"
+)
+
+// StakeBounds are the bounds of the stake buckets in PAC, the stake of a validator is 1,000 PAC at most.
+var StakeBounds = []int64{10, 100, 500, 1_000}
+
+// StakeBucket is the validators whose stake is in the bucket, the minimum is inclusive and the maximum is exclusive.
+// The last bucket has no maximum.
+type StakeBucket struct {
+	Label      string // Like: "<10", "10-100" or "1k+".
+	Min        amount.Amount
+	Max        amount.Amount
+	Validators int
+	Stake      amount.Amount // The total stake of the validators in the bucket.
+	Share      float64       // The share of the validators in the bucket, in percent.
+}
+
+// StakeDistribution buckets the stakes by the bounds in PAC, the bounds are in ascending order.
+func StakeDistribution(stakes []int64, bounds []int64) []StakeBucket {
+	buckets := make([]StakeBucket, 0, len(bounds)+1)
+	lower := int64(0)
+	for _, bound := range bounds {
+		label := utils.CompactNumber(lower) + "-" + utils.CompactNumber(bound)
+		if lower == 0 {
+			label = "<" + utils.CompactNumber(bound)
+		}
+
+		buckets = append(buckets, StakeBucket{
+			Label: label,
+			Min:   amount.Amount(lower * 1e9),
+			Max:   amount.Amount(bound * 1e9),
+		})
+		lower = bound
+	}
+	buckets = append(buckets, StakeBucket{
+		Label: utils.CompactNumber(lower) + "+",
+		Min:   amount.Amount(lower * 1e9),
+	})
+
+	for _, stake := range stakes {
+		i := len(buckets) - 1
+		for i > 0 && stake < int64(buckets[i].Min) {
+			i--
+		}
+
+		buckets[i].Validators++
+		buckets[i].Stake += amount.Amount(stake)
+	}
+
+	if len(stakes) > 0 {
+		for i := range buckets {
+			buckets[i].Share = float64(buckets[i].Validators) * 100 / float64(len(stakes))
+		}
+	}
+
+	return buckets
+}
+
+// StakeDistribution returns the buckets of the stakes of the active validators by the stake bounds.
+// It returns false if the validators are not loaded yet.
+func (i *Indexer) StakeDistribution() ([]StakeBucket, int, bool) {
+	validators, updatedAt := i.clientMgr.GetValidators()
+	if updatedAt.IsZero() {
+		return nil, 0, false
+	}
+
+	stakes := make([]int64, 0, len(validators))
+	for _, val := range validators {
+		stakes = append(stakes, val.Stake)
+	}
+
+	return StakeDistribution(stakes, StakeBounds), len(stakes), true
+}
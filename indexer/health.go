@@ -0,0 +1,111 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const (
+	// healthInterval is the time between the health samples, a few blocks.
+	healthInterval = time.Minute
+
+	// HealthyLag is the most seconds since the last block of a healthy network, like in the network health.
+	HealthyLag = 15
+
+	// DownLag is the seconds since the last block of a network that is down, a few blocks are missed.
+	DownLag = 60
+
+	// healthKeep is how long the samples are kept, the timeline is of the last day.
+	healthKeep = 48 * time.Hour
+)
+
+// HealthState is the health of the network in an hour.
+type HealthState string
+
+const (
+	HealthUp       HealthState = "up"
+	HealthDegraded HealthState = "degraded" // A block was late, but the network made the blocks.
+	HealthDown     HealthState = "down"
+	HealthUnknown  HealthState = "unknown" // The hour has no samples, like before the bot started.
+)
+
+// HealthHour is the health of the network in an hour of the timeline.
+type HealthHour struct {
+	Hour   time.Time
+	State  HealthState
+	MaxLag int64 // The most seconds since the last block in the samples of the hour.
+}
+
+// HealthJob returns the scheduler job of the health samples. It's exclusive,
+// one of the instances samples the health to the shared store.
+func (i *Indexer) HealthJob() scheduler.Job {
+	return scheduler.Job{
+		Name:      "health-samples",
+		Interval:  healthInterval,
+		Exclusive: true,
+		Run:       i.SampleHealth,
+	}
+}
+
+// SampleHealth keeps the time since the last block of the nodes, and removes the old samples.
+func (i *Indexer) SampleHealth(ctx context.Context) error {
+	blockTime, height := i.clientMgr.GetLastBlockTime(ctx)
+	if height == 0 {
+		return errors.New("the last block is not known")
+	}
+
+	now := i.now().UTC()
+	if err := i.store.AddHealthSample(&database.HealthSample{
+		Minute: now.Truncate(time.Minute),
+		Height: height,
+		Lag:    max(now.Unix()-int64(blockTime), 0),
+	}); err != nil {
+		return err
+	}
+
+	return i.store.DeleteHealthSamples(now.Add(-healthKeep))
+}
+
+// HealthTimeline returns the health of the network in the last hours, the oldest first and the current hour last.
+// An hour is down if the blocks stopped in a sample, and degraded if a block was late.
+func (i *Indexer) HealthTimeline(hours int) ([]HealthHour, error) {
+	current := i.now().UTC().Truncate(time.Hour)
+	from := current.Add(-time.Duration(hours-1) * time.Hour)
+
+	samples, err := i.store.GetHealthSamples(from)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := make([]HealthHour, 0, hours)
+	for h := 0; h < hours; h++ {
+		timeline = append(timeline, HealthHour{
+			Hour:  from.Add(time.Duration(h) * time.Hour),
+			State: HealthUnknown,
+		})
+	}
+
+	for _, s := range samples {
+		h := int(s.Minute.Sub(from) / time.Hour)
+		if h < 0 || h >= hours {
+			continue
+		}
+
+		hour := &timeline[h]
+		hour.MaxLag = max(hour.MaxLag, s.Lag)
+		switch {
+		case hour.MaxLag > DownLag:
+			hour.State = HealthDown
+		case hour.MaxLag > HealthyLag:
+			hour.State = HealthDegraded
+		default:
+			hour.State = HealthUp
+		}
+	}
+
+	return timeline, nil
+}
@@ -0,0 +1,237 @@
+package indexer
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pactus-project/pactus/crypto"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+// interval is the time between the snapshots, the first snapshot of each day is kept.
+const interval = time.Hour
+
+// txInterval is the time between the transaction indexing runs.
+const txInterval = 30 * time.Second
+
+// maxBlocksPerRun keeps a run short when the indexer is behind, the next runs catch up.
+const maxBlocksPerRun = 100
+
+// Store keeps the daily network snapshots, the health samples and the transactions of the accounts.
+type Store interface {
+	AddNetworkSnapshot(s *database.NetworkSnapshot) error
+	GetNetworkSnapshot(at time.Time) (*database.NetworkSnapshot, error)
+	AddAccountTransactions(txs []*database.AccountTransaction) error
+	GetLastIndexedHeight() (uint32, error)
+	GetAccountTransactions(address string, offset, limit int) ([]*database.AccountTransaction, error)
+	GetBondTransactions(address string, limit int) ([]*database.AccountTransaction, error)
+	GetOutgoingTransactions(addresses []string, afterHeight uint32, limit int) ([]*database.AccountTransaction, error)
+	AddHealthSample(s *database.HealthSample) error
+	GetHealthSamples(since time.Time) ([]*database.HealthSample, error)
+	DeleteHealthSamples(before time.Time) error
+}
+
+// Indexer keeps the history of the network, to compare the network with the past.
+// The instances can share the store, a day keeps one snapshot.
+type Indexer struct {
+	clientMgr *client.Mgr
+	store     Store
+	now       func() time.Time
+}
+
+func NewIndexer(clientMgr *client.Mgr, store Store) *Indexer {
+	return &Indexer{
+		clientMgr: clientMgr,
+		store:     store,
+		now:       time.Now,
+	}
+}
+
+// Current returns the snapshot of the network now.
+func (i *Indexer) Current(ctx context.Context) (*database.NetworkSnapshot, error) {
+	chainInfo, err := i.clientMgr.GetBlockchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	netInfo, err := i.clientMgr.GetNetworkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &database.NetworkSnapshot{
+		Day:        i.now().UTC().Truncate(24 * time.Hour),
+		Validators: chainInfo.TotalValidators,
+		Accounts:   chainInfo.TotalAccounts,
+		TotalPower: chainInfo.TotalPower,
+		Nodes:      netInfo.ConnectedPeersCount,
+	}
+
+	// the validators are loaded in the background, the coefficients are zero until then.
+	validators, updatedAt := i.clientMgr.GetValidators()
+	if !updatedAt.IsZero() {
+		stakes := make([]int64, 0, len(validators))
+		for _, val := range validators {
+			stakes = append(stakes, val.Stake)
+		}
+		s.Gini = Gini(stakes)
+		s.Nakamoto = Nakamoto(stakes)
+	}
+
+	return s, nil
+}
+
+// DaysAgo returns the snapshot of the days ago, or the last one before it. It's nil if there is none.
+func (i *Indexer) DaysAgo(days int) (*database.NetworkSnapshot, error) {
+	return i.store.GetNetworkSnapshot(i.now().UTC().AddDate(0, 0, -days))
+}
+
+// Start takes the snapshots until the context is done.
+func (i *Indexer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		i.snapshot(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				i.snapshot(ctx)
+			}
+		}
+	}()
+}
+
+func (i *Indexer) snapshot(ctx context.Context) {
+	s, err := i.Current(ctx)
+	if err != nil {
+		log.Warn("can't take the network snapshot", "err", err)
+
+		return
+	}
+
+	if err := i.store.AddNetworkSnapshot(s); err != nil {
+		log.Error("can't add the network snapshot", "err", err)
+	}
+}
+
+// Activity returns the transactions of the address, the newest first.
+func (i *Indexer) Activity(address string, offset, limit int) ([]*database.AccountTransaction, error) {
+	return i.store.GetAccountTransactions(address, offset, limit)
+}
+
+// Bonds returns the bonds, the unbonds and the withdraws of the validator, the newest first.
+func (i *Indexer) Bonds(address string, limit int) ([]*database.AccountTransaction, error) {
+	return i.store.GetBondTransactions(address, limit)
+}
+
+// Outgoing returns the outgoing transactions of the addresses, the newest first.
+func (i *Indexer) Outgoing(addresses []string, limit int) ([]*database.AccountTransaction, error) {
+	return i.store.GetOutgoingTransactions(addresses, 0, limit)
+}
+
+// Job returns the scheduler job of the transaction indexing. It's exclusive,
+// one of the instances indexes the blocks to the shared store.
+func (i *Indexer) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:      "indexer",
+		Interval:  txInterval,
+		Exclusive: true,
+		Run:       i.IndexTransactions,
+	}
+}
+
+// IndexTransactions indexes the transactions of the blocks after the last indexed one.
+// An empty store starts from the last block, the history before it is not indexed.
+func (i *Indexer) IndexTransactions(ctx context.Context) error {
+	last, err := i.store.GetLastIndexedHeight()
+	if err != nil {
+		return err
+	}
+
+	height, err := i.clientMgr.GetBlockchainHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	from := last + 1
+	if last == 0 {
+		from = height
+	}
+
+	for h := from; h <= height && h < from+maxBlocksPerRun; h++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		block, err := i.clientMgr.GetBlockTransactions(ctx, h)
+		if err != nil {
+			return err
+		}
+
+		if err := i.store.AddAccountTransactions(accountTransactions(block)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// accountTransactions returns a row for each address that a transaction of the block touches.
+// Every block has the reward transaction of its proposer, so the block has a row and its height is indexed.
+func accountTransactions(block *pactus.GetBlockResponse) []*database.AccountTransaction {
+	blockTime := time.Unix(int64(block.BlockTime), 0)
+	treasury := crypto.TreasuryAddress.String()
+
+	txs := make([]*database.AccountTransaction, 0, len(block.Txs))
+	add := func(tx *pactus.TransactionInfo, address, counterparty string, direction database.TxDirection) {
+		if address == "" || address == treasury {
+			return
+		}
+
+		txs = append(txs, &database.AccountTransaction{
+			TxID:         hex.EncodeToString(tx.Id),
+			Address:      address,
+			Height:       block.Height,
+			BlockTime:    blockTime,
+			Type:         strings.ToLower(strings.TrimSuffix(tx.PayloadType.String(), "_PAYLOAD")),
+			Direction:    direction,
+			Counterparty: counterparty,
+			Amount:       tx.Value,
+		})
+	}
+
+	for _, tx := range block.Txs {
+		var sender, receiver string
+		switch payload := tx.Payload.(type) {
+		case *pactus.TransactionInfo_Transfer:
+			sender, receiver = payload.Transfer.Sender, payload.Transfer.Receiver
+		case *pactus.TransactionInfo_Bond:
+			sender, receiver = payload.Bond.Sender, payload.Bond.Receiver
+		case *pactus.TransactionInfo_Withdraw:
+			sender, receiver = payload.Withdraw.From, payload.Withdraw.To
+		case *pactus.TransactionInfo_Unbond:
+			sender = payload.Unbond.Validator
+		default:
+			// the sortition transactions are not an activity of the users.
+			continue
+		}
+
+		if sender == treasury {
+			sender = ""
+		}
+		add(tx, sender, receiver, database.TxOutgoing)
+		add(tx, receiver, sender, database.TxIncoming)
+	}
+
+	return txs
+}
@@ -0,0 +1,332 @@
+package indexer
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/types/amount"
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type memoryStore struct {
+	snapshots []*database.NetworkSnapshot
+	txs       []*database.AccountTransaction
+	samples   []*database.HealthSample
+}
+
+func (s *memoryStore) AddNetworkSnapshot(snapshot *database.NetworkSnapshot) error {
+	s.snapshots = append(s.snapshots, snapshot)
+
+	return nil
+}
+
+func (s *memoryStore) GetNetworkSnapshot(at time.Time) (*database.NetworkSnapshot, error) {
+	var last *database.NetworkSnapshot
+	for _, snapshot := range s.snapshots {
+		if !snapshot.Day.After(at) {
+			last = snapshot
+		}
+	}
+
+	return last, nil
+}
+
+func (s *memoryStore) AddAccountTransactions(txs []*database.AccountTransaction) error {
+	s.txs = append(s.txs, txs...)
+
+	return nil
+}
+
+func (s *memoryStore) GetLastIndexedHeight() (uint32, error) {
+	if len(s.txs) == 0 {
+		return 0, nil
+	}
+
+	return s.txs[len(s.txs)-1].Height, nil
+}
+
+func (s *memoryStore) GetAccountTransactions(address string, offset, limit int) ([]*database.AccountTransaction, error) {
+	txs := make([]*database.AccountTransaction, 0)
+	for i := len(s.txs) - 1; i >= 0; i-- {
+		if s.txs[i].Address == address {
+			txs = append(txs, s.txs[i])
+		}
+	}
+
+	if offset >= len(txs) {
+		return nil, nil
+	}
+
+	return txs[offset:min(offset+limit, len(txs))], nil
+}
+
+func (s *memoryStore) GetBondTransactions(address string, limit int) ([]*database.AccountTransaction, error) {
+	txs := make([]*database.AccountTransaction, 0)
+	for i := len(s.txs) - 1; i >= 0 && len(txs) < limit; i-- {
+		if s.txs[i].Address == address && s.txs[i].Type != "transfer" {
+			txs = append(txs, s.txs[i])
+		}
+	}
+
+	return txs, nil
+}
+
+func (s *memoryStore) GetOutgoingTransactions(addresses []string, afterHeight uint32, limit int,
+) ([]*database.AccountTransaction, error) {
+	txs := make([]*database.AccountTransaction, 0)
+	for i := len(s.txs) - 1; i >= 0 && len(txs) < limit; i-- {
+		tx := s.txs[i]
+		if slices.Contains(addresses, tx.Address) && tx.Direction == database.TxOutgoing && tx.Height > afterHeight {
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs, nil
+}
+
+func (s *memoryStore) AddHealthSample(sample *database.HealthSample) error {
+	s.samples = append(s.samples, sample)
+
+	return nil
+}
+
+func (s *memoryStore) GetHealthSamples(since time.Time) ([]*database.HealthSample, error) {
+	samples := make([]*database.HealthSample, 0)
+	for _, sample := range s.samples {
+		if !sample.Minute.Before(since) {
+			samples = append(samples, sample)
+		}
+	}
+
+	return samples, nil
+}
+
+func (s *memoryStore) DeleteHealthSamples(before time.Time) error {
+	s.samples = slices.DeleteFunc(s.samples, func(sample *database.HealthSample) bool {
+		return sample.Minute.Before(before)
+	})
+
+	return nil
+}
+
+func TestIndexer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(&pactus.GetBlockchainInfoResponse{
+		TotalValidators: 100,
+		TotalAccounts:   2000,
+		TotalPower:      5_000_000_000_000,
+	}, nil).AnyTimes()
+	c.EXPECT().GetNetworkInfo(gomock.Any()).Return(&pactus.GetNetworkInfoResponse{
+		ConnectedPeersCount: 50,
+	}, nil).AnyTimes()
+
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	store := &memoryStore{}
+	idx := NewIndexer(cm, store)
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	idx.now = func() time.Time { return now }
+
+	idx.snapshot(context.Background())
+	require.Len(t, store.snapshots, 1)
+	assert.Equal(t, time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), store.snapshots[0].Day)
+	assert.Equal(t, int32(100), store.snapshots[0].Validators)
+	assert.Equal(t, uint32(50), store.snapshots[0].Nodes)
+
+	s, err := idx.DaysAgo(7)
+	require.NoError(t, err)
+	assert.Nil(t, s, "no snapshot of 7 days ago")
+
+	now = now.AddDate(0, 0, 8)
+	s, err = idx.DaysAgo(7)
+	require.NoError(t, err)
+	assert.Equal(t, store.snapshots[0], s)
+}
+
+func block(height uint32, txs ...*pactus.TransactionInfo) *pactus.GetBlockResponse {
+	reward := &pactus.TransactionInfo{
+		Id:          []byte{byte(height)},
+		Value:       1_000_000_000,
+		PayloadType: pactus.PayloadType_TRANSFER_PAYLOAD,
+		Payload: &pactus.TransactionInfo_Transfer{Transfer: &pactus.PayloadTransfer{
+			Sender:   crypto.TreasuryAddress.String(),
+			Receiver: "pc1z-proposer",
+			Amount:   1_000_000_000,
+		}},
+	}
+
+	return &pactus.GetBlockResponse{
+		Height:    height,
+		BlockTime: 1_700_000_000 + height*10,
+		Txs:       append([]*pactus.TransactionInfo{reward}, txs...),
+	}
+}
+
+func TestIndexTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	height := uint32(100)
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainHeight(gomock.Any()).DoAndReturn(func(_ context.Context) (uint32, error) {
+		return height, nil
+	}).AnyTimes()
+	c.EXPECT().GetBlockTransactions(gomock.Any(), uint32(100)).Return(block(100), nil)
+	c.EXPECT().GetBlockTransactions(gomock.Any(), uint32(101)).Return(block(101), nil)
+	c.EXPECT().GetBlockTransactions(gomock.Any(), uint32(102)).Return(block(102,
+		&pactus.TransactionInfo{
+			Id:          []byte{0xaa},
+			Value:       5_000_000_000,
+			PayloadType: pactus.PayloadType_TRANSFER_PAYLOAD,
+			Payload: &pactus.TransactionInfo_Transfer{Transfer: &pactus.PayloadTransfer{
+				Sender: "pc1z-alice", Receiver: "pc1z-bob", Amount: 5_000_000_000,
+			}},
+		},
+		&pactus.TransactionInfo{
+			Id:          []byte{0xbb},
+			PayloadType: pactus.PayloadType_SORTITION_PAYLOAD,
+			Payload: &pactus.TransactionInfo_Sortition{Sortition: &pactus.PayloadSortition{
+				Address: "pc1p-validator",
+			}},
+		},
+		&pactus.TransactionInfo{
+			Id:          []byte{0xcc},
+			PayloadType: pactus.PayloadType_UNBOND_PAYLOAD,
+			Payload: &pactus.TransactionInfo_Unbond{Unbond: &pactus.PayloadUnbond{
+				Validator: "pc1p-validator",
+			}},
+		},
+	), nil)
+
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	store := &memoryStore{}
+	idx := NewIndexer(cm, store)
+
+	require.NoError(t, idx.IndexTransactions(context.Background()))
+	require.Len(t, store.txs, 1, "an empty store starts from the last block")
+	assert.Equal(t, uint32(100), store.txs[0].Height)
+
+	height = 102
+	require.NoError(t, idx.IndexTransactions(context.Background()))
+
+	proposer, err := idx.Activity("pc1z-proposer", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, proposer, 3)
+	assert.Equal(t, database.TxIncoming, proposer[0].Direction)
+	assert.Empty(t, proposer[0].Counterparty, "the treasury is not a counterparty")
+	assert.Equal(t, "transfer", proposer[0].Type)
+
+	alice, err := idx.Activity("pc1z-alice", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, alice, 1)
+	assert.Equal(t, database.AccountTransaction{
+		TxID:         "aa",
+		Address:      "pc1z-alice",
+		Height:       102,
+		BlockTime:    time.Unix(1_700_001_020, 0),
+		Type:         "transfer",
+		Direction:    database.TxOutgoing,
+		Counterparty: "pc1z-bob",
+		Amount:       5_000_000_000,
+	}, *alice[0])
+
+	validator, err := idx.Activity("pc1p-validator", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, validator, 1, "the sortitions are not indexed")
+	assert.Equal(t, "unbond", validator[0].Type)
+
+	treasury, err := idx.Activity(crypto.TreasuryAddress.String(), 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, treasury)
+}
+
+func TestDecentralization(t *testing.T) {
+	assert.InDelta(t, 0, Gini([]int64{100, 100, 100, 100}), 0.0001, "equal stakes")
+	assert.InDelta(t, 0.75, Gini([]int64{0, 0, 0, 400}), 0.0001, "one of four holds the stake")
+	assert.InDelta(t, 0.25, Gini([]int64{40, 10, 30, 20}), 0.0001)
+	assert.Zero(t, Gini(nil))
+
+	assert.Equal(t, int32(1), Nakamoto([]int64{10, 50, 20, 20}))
+	assert.Equal(t, int32(2), Nakamoto([]int64{20, 30, 30, 20}), "30 of 100 is not over a third")
+	assert.Equal(t, int32(4), Nakamoto([]int64{10, 10, 10, 10, 10, 10, 10, 10, 10, 10}))
+	assert.Zero(t, Nakamoto(nil))
+}
+
+func TestStakeDistribution(t *testing.T) {
+	pac := func(v int64) int64 { return v * 1e9 }
+	buckets := StakeDistribution([]int64{pac(1), pac(9), pac(10), pac(250), pac(999), pac(1_000), pac(1_000)}, StakeBounds)
+
+	labels := make([]string, 0, len(buckets))
+	counts := make([]int, 0, len(buckets))
+	for _, b := range buckets {
+		labels = append(labels, b.Label)
+		counts = append(counts, b.Validators)
+	}
+	assert.Equal(t, []string{"<10", "10-100", "100-500", "500-1k", "1k+"}, labels)
+	assert.Equal(t, []int{2, 1, 1, 1, 2}, counts)
+	assert.Equal(t, amount.Amount(pac(2_000)), buckets[4].Stake)
+	assert.InDelta(t, 28.57, buckets[0].Share, 0.01)
+
+	empty := StakeDistribution(nil, StakeBounds)
+	assert.Len(t, empty, 5)
+	assert.Zero(t, empty[0].Share)
+
+	i := NewIndexer(client.NewClientMgr(context.Background()), nil)
+	_, _, ok := i.StakeDistribution()
+	assert.False(t, ok, "the validators are not loaded")
+}
+
+func TestHealthTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	now := time.Date(2024, 5, 20, 10, 30, 0, 0, time.UTC)
+	blockTime := now.Add(-5 * time.Second)
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().LastBlockTime(gomock.Any()).DoAndReturn(func(_ context.Context) (uint32, uint32, error) {
+		return uint32(blockTime.Unix()), 1_000, nil
+	}).AnyTimes()
+
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	store := &memoryStore{}
+	idx := NewIndexer(cm, store)
+	idx.now = func() time.Time { return now }
+
+	old := &database.HealthSample{Minute: now.Add(-3 * 24 * time.Hour), Height: 1}
+	store.samples = append(store.samples, old)
+
+	// the blocks stopped for two minutes at 08:10, and a block was late at 09:20.
+	sample := func(at time.Time, lag time.Duration) {
+		now, blockTime = at, at.Add(-lag)
+		require.NoError(t, idx.SampleHealth(context.Background()))
+	}
+	sample(time.Date(2024, 5, 20, 8, 0, 0, 0, time.UTC), 5*time.Second)
+	sample(time.Date(2024, 5, 20, 8, 10, 0, 0, time.UTC), 2*time.Minute)
+	sample(time.Date(2024, 5, 20, 9, 20, 0, 0, time.UTC), 20*time.Second)
+	sample(time.Date(2024, 5, 20, 9, 21, 0, 0, time.UTC), 3*time.Second)
+	sample(time.Date(2024, 5, 20, 10, 30, 0, 0, time.UTC), 3*time.Second)
+	assert.NotContains(t, store.samples, old, "the old samples are removed")
+
+	timeline, err := idx.HealthTimeline(24)
+	require.NoError(t, err)
+	require.Len(t, timeline, 24)
+	assert.Equal(t, time.Date(2024, 5, 19, 11, 0, 0, 0, time.UTC), timeline[0].Hour)
+	assert.Equal(t, HealthUnknown, timeline[0].State, "no samples")
+	assert.Equal(t, HealthDown, timeline[21].State)
+	assert.Equal(t, int64(120), timeline[21].MaxLag)
+	assert.Equal(t, HealthDegraded, timeline[22].State)
+	assert.Equal(t, HealthUp, timeline[23].State, "the current hour")
+}
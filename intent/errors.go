@@ -0,0 +1,11 @@
+package intent
+
+import "fmt"
+
+type LLMError struct {
+	Reason string
+}
+
+func (e LLMError) Error() string {
+	return fmt.Sprintf("can't match the intent with the LLM: %s", e.Reason)
+}
@@ -0,0 +1,91 @@
+package intent
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// Command is a command that the free text can be mapped to.
+type Command struct {
+	Usage string // Like: "network validator <number>".
+	Desc  string
+}
+
+// Matcher maps a free-text question to the tokens of a command.
+// It returns nil tokens if the text doesn't match any command.
+type Matcher interface {
+	Match(ctx context.Context, text string, commands []Command) ([]string, error)
+}
+
+// Rule maps the texts that match the pattern to the command,
+// the command can refer to the pattern groups, like: "network validator $1".
+type Rule struct {
+	Pattern *regexp.Regexp
+	Command string
+}
+
+// DefaultRules returns the keyword rules of the common questions.
+func DefaultRules() []Rule {
+	return []Rule{
+		{regexp.MustCompile(`(?i)\bvalidator\s+(?:number\s+|#)?(\d+)\b`), "network validator $1"},
+		{regexp.MustCompile(`(?i)\b(pc1p[0-9a-z]+)\b`), "network node-info $1"},
+		{regexp.MustCompile(`(?i)\bat[\s-]risk\b|\blow\s+scores?\b`), "network validators at-risk"},
+		{regexp.MustCompile(`(?i)\b(network|chain)\b.*\b(ok|okay|healthy|up|down|alive|working)\b`), "network health"},
+		{regexp.MustCompile(`(?i)\bhealth\b`), "network health"},
+		{regexp.MustCompile(`(?i)\bsupply\b|\bcirculating\b`), "network supply"},
+		{regexp.MustCompile(`(?i)\bgrow(th|ing)?\b`), "network growth"},
+		{regexp.MustCompile(`(?i)\bprice\b|\bhow\s+much\s+is\s+pac\b`), "market price"},
+		{regexp.MustCompile(`(?i)\bfees?\b`), "blockchain fee-calc"},
+		{regexp.MustCompile(`(?i)\brewards?\b`), "blockchain reward-calc"},
+		{regexp.MustCompile(`(?i)\bstatus\b|\bhow\s+many\s+(validators|peers|nodes|accounts)\b`), "network status"},
+	}
+}
+
+// Keywords matches the text with the rules in order, the first matching rule wins.
+type Keywords struct {
+	rules []Rule
+}
+
+func NewKeywords(rules []Rule) *Keywords {
+	return &Keywords{
+		rules: rules,
+	}
+}
+
+func (k *Keywords) Match(_ context.Context, text string, _ []Command) ([]string, error) {
+	for _, rule := range k.rules {
+		match := rule.Pattern.FindStringSubmatchIndex(text)
+		if match == nil {
+			continue
+		}
+
+		cmd := rule.Pattern.ExpandString(nil, rule.Command, text, match)
+
+		return strings.Fields(string(cmd)), nil
+	}
+
+	return nil, nil
+}
+
+// Chain tries the matchers in order until one of them matches the text.
+// A failing matcher doesn't stop the chain, the error is returned if no matcher matches.
+type Chain []Matcher
+
+func (c Chain) Match(ctx context.Context, text string, commands []Command) ([]string, error) {
+	var lastErr error
+	for _, m := range c {
+		tokens, err := m.Match(ctx, text, commands)
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		if len(tokens) != 0 {
+			return tokens, nil
+		}
+	}
+
+	return nil, lastErr
+}
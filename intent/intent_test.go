@@ -0,0 +1,80 @@
+package intent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywords(t *testing.T) {
+	k := NewKeywords(DefaultRules())
+
+	tests := []struct {
+		text   string
+		tokens []string
+	}{
+		{"is the network ok?", []string{"network", "health"}},
+		{"Is the chain down right now", []string{"network", "health"}},
+		{"what's the PAC price", []string{"market", "price"}},
+		{"tell me about validator 42", []string{"network", "validator", "42"}},
+		{"info of validator #7 please", []string{"network", "validator", "7"}},
+		{"which validators are at risk?", []string{"network", "validators", "at-risk"}},
+		{"how many validators are there", []string{"network", "status"}},
+		{"what's the weather", nil},
+	}
+
+	for _, tt := range tests {
+		tokens, err := k.Match(context.Background(), tt.text, nil)
+		require.NoError(t, err)
+		assert.Equal(t, tt.tokens, tokens, tt.text)
+	}
+}
+
+func TestLLM(t *testing.T) {
+	answer := "network supply"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var req chatRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "test-model", req.Model)
+		assert.Contains(t, req.Messages[0].Content, "- network supply: Circulating supply")
+		assert.Equal(t, "how many coins exist?", req.Messages[1].Content)
+
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"` + answer + `"}}]}`))
+	}))
+	defer server.Close()
+
+	llm := NewLLM(server.URL, "secret", "test-model")
+	commands := []Command{{Usage: "network supply", Desc: "Circulating supply"}}
+
+	tokens, err := llm.Match(context.Background(), "how many coins exist?", commands)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"network", "supply"}, tokens)
+
+	answer = "none"
+	tokens, err = llm.Match(context.Background(), "how many coins exist?", commands)
+	require.NoError(t, err)
+	assert.Nil(t, tokens)
+}
+
+func TestChain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	chain := Chain{NewLLM(server.URL, "", "test-model"), NewKeywords(DefaultRules())}
+
+	tokens, err := chain.Match(context.Background(), "is the network ok?", nil)
+	require.NoError(t, err, "the failing matcher is skipped")
+	assert.Equal(t, []string{"network", "health"}, tokens)
+
+	_, err = chain.Match(context.Background(), "what's the weather", nil)
+	assert.ErrorAs(t, err, &LLMError{})
+}
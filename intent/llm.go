@@ -0,0 +1,125 @@
+package intent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// noneAnswer is the answer of the model if no command matches the question.
+const noneAnswer = "none"
+
+// LLM asks a language model with an OpenAI compatible chat completions API to pick the command.
+type LLM struct {
+	url    string
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+// NewLLM creates the LLM matcher, the url is the chat completions endpoint,
+// like: "https://api.openai.com/v1/chat/completions".
+func NewLLM(url, apiKey, model string) *LLM {
+	return &LLM{
+		url:    url,
+		apiKey: apiKey,
+		model:  model,
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (l *LLM) Match(ctx context.Context, text string, commands []Command) ([]string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: l.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt(commands)},
+			{Role: "user", Content: text},
+		},
+	})
+	if err != nil {
+		return nil, LLMError{
+			Reason: err.Error(),
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, LLMError{
+			Reason: err.Error(),
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+l.apiKey)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, LLMError{
+			Reason: err.Error(),
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, LLMError{
+			Reason: fmt.Sprintf("unexpected status: %s", resp.Status),
+		}
+	}
+
+	var chat chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
+		return nil, LLMError{
+			Reason: err.Error(),
+		}
+	}
+
+	if len(chat.Choices) == 0 {
+		return nil, LLMError{
+			Reason: "no answer",
+		}
+	}
+
+	answer := strings.Trim(strings.TrimSpace(chat.Choices[0].Message.Content), "`/")
+	if strings.EqualFold(answer, noneAnswer) {
+		return nil, nil
+	}
+
+	return strings.Fields(answer), nil
+}
+
+// systemPrompt describes the commands to the model, the engine checks the answer is a known command.
+func systemPrompt(commands []Command) string {
+	var sb strings.Builder
+	sb.WriteString("You map the questions of the Pactus blockchain users to one of these bot commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&sb, "- %s: %s\n", cmd.Usage, cmd.Desc)
+	}
+	sb.WriteString("Answer only with the command and its arguments taken from the question, without any explanation. ")
+	fmt.Fprintf(&sb, "If no command answers the question, or an argument is missing, answer %q.", noneAnswer)
+
+	return sb.String()
+}
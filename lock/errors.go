@@ -0,0 +1,11 @@
+package lock
+
+import "fmt"
+
+type LockedError struct {
+	Name string
+}
+
+func (e LockedError) Error() string {
+	return fmt.Sprintf("%s is in progress, please try again later", e.Name)
+}
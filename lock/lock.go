@@ -0,0 +1,88 @@
+package lock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Store keeps the locks where all the Pagu instances can see them, like the database.
+type Store interface {
+	AcquireLock(name, owner string, ttl time.Duration) (bool, error)
+	RenewLock(name, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(name, owner string) error
+}
+
+// Locker coordinates the Pagu instances, so the scheduled jobs and the payouts run once at a time.
+type Locker struct {
+	store Store
+	owner string
+}
+
+func NewLocker(store Store, owner string) *Locker {
+	return &Locker{
+		store: store,
+		owner: owner,
+	}
+}
+
+// DefaultOwner returns a name for this instance based on the host name and the process ID.
+func DefaultOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "pagu"
+	}
+
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+func (l *Locker) Owner() string {
+	return l.owner
+}
+
+// TryLock takes or renews the lock of the instance until the TTL is passed, it doesn't wait if another instance holds it.
+// The TTL should be longer than the work, an instance that stopped without unlocking holds the lock until then.
+func (l *Locker) TryLock(name string, ttl time.Duration) (bool, error) {
+	renewed, err := l.store.RenewLock(name, l.owner, ttl)
+	if err != nil || renewed {
+		return renewed, err
+	}
+
+	return l.store.AcquireLock(name, l.owner, ttl)
+}
+
+func (l *Locker) Unlock(name string) error {
+	return l.store.ReleaseLock(name, l.owner)
+}
+
+// WithLock runs the function while holding the lock, or returns LockedError if another call holds it.
+// Each call holds the lock by its own token, so the calls of the same instance exclude each other too.
+func (l *Locker) WithLock(name string, ttl time.Duration, fn func() error) error {
+	token := l.token()
+	ok, err := l.store.AcquireLock(name, token, ttl)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return LockedError{
+			Name: name,
+		}
+	}
+
+	defer func() {
+		_ = l.store.ReleaseLock(name, token)
+	}()
+
+	return fn()
+}
+
+// token returns the owner of a single call, like: "pagu-1234/3f9a1c07".
+func (l *Locker) token() string {
+	id := make([]byte, 4)
+	_, _ = rand.Read(id)
+
+	return fmt.Sprintf("%s/%s", l.owner, hex.EncodeToString(id))
+}
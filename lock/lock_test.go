@@ -0,0 +1,95 @@
+package lock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	owners map[string]string
+}
+
+func (s *memoryStore) AcquireLock(name, owner string, _ time.Duration) (bool, error) {
+	if _, ok := s.owners[name]; ok {
+		return false, nil
+	}
+	s.owners[name] = owner
+
+	return true, nil
+}
+
+func (s *memoryStore) RenewLock(name, owner string, _ time.Duration) (bool, error) {
+	return s.owners[name] == owner, nil
+}
+
+func (s *memoryStore) ReleaseLock(name, owner string) error {
+	if s.owners[name] == owner {
+		delete(s.owners, name)
+	}
+
+	return nil
+}
+
+func TestWithLock(t *testing.T) {
+	store := &memoryStore{owners: make(map[string]string)}
+	first := NewLocker(store, "pagu-1")
+	second := NewLocker(store, "pagu-2")
+
+	err := first.WithLock("payout", time.Minute, func() error {
+		err := second.WithLock("payout", time.Minute, func() error {
+			t.Fatal("the lock is held by the first instance")
+
+			return nil
+		})
+		assert.ErrorIs(t, err, LockedError{Name: "payout"})
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	errFailed := errors.New("failed")
+	err = second.WithLock("payout", time.Minute, func() error {
+		return errFailed
+	})
+	assert.ErrorIs(t, err, errFailed)
+	assert.Empty(t, store.owners, "unlocked after the function")
+
+	err = first.WithLock("payout", time.Minute, func() error {
+		err := first.WithLock("payout", time.Minute, func() error {
+			t.Fatal("the lock is held by another call of the same instance")
+
+			return nil
+		})
+		assert.ErrorIs(t, err, LockedError{Name: "payout"})
+
+		// the other call can't release the lock of this call.
+		require.NoError(t, first.Unlock("payout"))
+		assert.NotEmpty(t, store.owners)
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, store.owners)
+}
+
+func TestTryLock(t *testing.T) {
+	store := &memoryStore{owners: make(map[string]string)}
+	leader := NewLocker(store, "pagu-1")
+	follower := NewLocker(store, "pagu-2")
+
+	ok, err := leader.TryLock("backup", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = follower.TryLock("backup", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = leader.TryLock("backup", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "the instance renews its lock")
+}
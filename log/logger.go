@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -199,6 +200,31 @@ func Panic(msg string, keyvals ...interface{}) {
 	addFields(log.Panic(), keyvals...).Msg(msg)
 }
 
+type requestIDKey struct{}
+
+// WithRequestID returns the context of a command with its request ID, the log lines of Ctx have it.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID of the command of the context, empty if it has none.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+
+	return requestID
+}
+
+// Ctx returns the logger of the command of the context, its log lines have the request ID of the command,
+// so a user report with the request ID is matched to the logs.
+func Ctx(ctx context.Context) *SubLogger {
+	requestID := RequestID(ctx)
+	if requestID == "" {
+		return &SubLogger{logger: log.Logger}
+	}
+
+	return &SubLogger{logger: log.Logger.With().Str("request_id", requestID).Logger()}
+}
+
 func isNil(i interface{}) bool {
 	if i == nil {
 		return true
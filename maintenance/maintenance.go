@@ -0,0 +1,276 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+	"github.com/pagu-project/Pagu/settings"
+)
+
+const (
+	// SettingName is the setting of the scheduled window,
+	// it's kept in the settings table so all the instances pause in it.
+	SettingName = "maintenance"
+
+	// StartFormat is the start of a window in UTC, like: "2024-07-01T10:00".
+	StartFormat = "2006-01-02T15:04"
+
+	// MaxDuration is the longest window, a longer maintenance is scheduled again.
+	MaxDuration = 24 * time.Hour
+)
+
+const (
+	// checkInterval is how often the window is checked for the reminders.
+	checkInterval = time.Minute
+
+	// endGrace is how long after the end of a window the end is announced, an instance that starts later doesn't.
+	endGrace = time.Hour
+)
+
+var (
+	ErrInvalidStart    = errors.New("the start should be like: 2024-07-01T10:00 in UTC, or now")
+	ErrInvalidDuration = errors.New("the duration should be like: 30m, up to 24h")
+	ErrPast            = errors.New("the window is in the past")
+)
+
+// Window is a maintenance window, the commands respond with a notice and the jobs are paused in it.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseWindow parses the start in UTC, like: "2024-07-01T10:00" or "now", and the duration, like: "30m".
+func ParseWindow(start, duration string, now time.Time) (Window, error) {
+	from := now.UTC().Truncate(time.Minute)
+	if !strings.EqualFold(start, "now") {
+		t, err := time.Parse(StartFormat, start)
+		if err != nil {
+			return Window{}, ErrInvalidStart
+		}
+		from = t
+	}
+
+	length, err := time.ParseDuration(duration)
+	if err != nil || length <= 0 || length > MaxDuration {
+		return Window{}, ErrInvalidDuration
+	}
+
+	w := Window{
+		Start: from,
+		End:   from.Add(length),
+	}
+	if !w.End.After(now) {
+		return Window{}, ErrPast
+	}
+
+	return w, nil
+}
+
+// Active checks if the time is in the window.
+func (w Window) Active(now time.Time) bool {
+	return !now.Before(w.Start) && now.Before(w.End)
+}
+
+// Length returns the duration of the window, like: "1h30m".
+func (w Window) Length() string {
+	length := w.End.Sub(w.Start).String()
+	if strings.HasSuffix(length, "m0s") {
+		length = strings.TrimSuffix(length, "0s")
+	}
+
+	if strings.HasSuffix(length, "h0m") {
+		length = strings.TrimSuffix(length, "0m")
+	}
+
+	return length
+}
+
+func (w Window) String() string {
+	return w.Start.Format(time.RFC3339) + "/" + w.End.Format(time.RFC3339)
+}
+
+func parseStored(value string) (Window, bool) {
+	start, end, ok := strings.Cut(value, "/")
+	if !ok {
+		return Window{}, false
+	}
+
+	from, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return Window{}, false
+	}
+
+	to, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return Window{}, false
+	}
+
+	return Window{Start: from.UTC(), End: to.UTC()}, true
+}
+
+// Store keeps the posted alerts.
+type Store interface {
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Maintenance keeps the maintenance window that the admins schedule, and reminds the operators before it.
+// The operators are notified of the start and the end of the window too.
+type Maintenance struct {
+	settings *settings.Settings
+	store    Store
+	hub      *notify.Hub
+	channels []notify.Channel
+	remind   time.Duration
+	now      func() time.Time
+}
+
+// NewMaintenance creates the maintenance of the settings, the channels are reminded before the window starts.
+func NewMaintenance(setts *settings.Settings, store Store, hub *notify.Hub,
+	channels []notify.Channel, remind time.Duration,
+) *Maintenance {
+	return &Maintenance{
+		settings: setts,
+		store:    store,
+		hub:      hub,
+		channels: channels,
+		remind:   remind,
+		now:      time.Now,
+	}
+}
+
+// Scheduled returns the window that is not ended yet, false if no window is scheduled.
+func (m *Maintenance) Scheduled() (Window, bool) {
+	if m == nil {
+		return Window{}, false
+	}
+
+	w, ok := m.stored()
+	if !ok || !m.now().Before(w.End) {
+		return Window{}, false
+	}
+
+	return w, true
+}
+
+// Active returns the window if it's started and not ended yet.
+func (m *Maintenance) Active() (Window, bool) {
+	w, ok := m.Scheduled()
+	if !ok || !w.Active(m.now()) {
+		return Window{}, false
+	}
+
+	return w, true
+}
+
+// Paused checks if the jobs are paused, the scheduler skips the jobs in the window.
+func (m *Maintenance) Paused() bool {
+	_, active := m.Active()
+
+	return active
+}
+
+// Schedule schedules the window on all the instances, it replaces the scheduled one.
+func (m *Maintenance) Schedule(w Window, scheduledBy string) error {
+	return m.settings.Set(SettingName, w.String(), scheduledBy)
+}
+
+// Cancel removes the scheduled window and resumes the jobs, it returns false if no window is scheduled.
+func (m *Maintenance) Cancel() (bool, error) {
+	if _, ok := m.Scheduled(); !ok {
+		return false, nil
+	}
+
+	return m.settings.Reset(SettingName)
+}
+
+// Job returns the scheduler job of the reminders. It's not exclusive, each platform posts its own reminders.
+// It runs in the window too, to notify the end.
+func (m *Maintenance) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:       "maintenance",
+		Interval:   checkInterval,
+		Exclusive:  false,
+		RunsPaused: true,
+		Run:        m.Run,
+	}
+}
+
+// Run notifies the operators before the window, at the start and at the end of it, once for each of them.
+func (m *Maintenance) Run(_ context.Context) error {
+	w, ok := m.stored()
+	if !ok {
+		return nil
+	}
+
+	now := m.now()
+	switch {
+	case now.Before(w.Start):
+		if m.remind > 0 && !now.Before(w.Start.Add(-m.remind)) {
+			m.alert("reminder", w)
+		}
+
+	case w.Active(now):
+		m.alert("started", w)
+
+	case now.Before(w.End.Add(endGrace)):
+		m.alert("ended", w)
+	}
+
+	return nil
+}
+
+func (m *Maintenance) stored() (Window, bool) {
+	value, ok := m.settings.Get(SettingName)
+	if !ok {
+		return Window{}, false
+	}
+
+	w, ok := parseStored(value)
+	if !ok {
+		log.Warn("invalid maintenance window", "value", value)
+	}
+
+	return w, ok
+}
+
+func (m *Maintenance) alert(kind string, w Window) {
+	for _, channel := range m.channels {
+		if !m.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		key := "maintenance:" + kind + ":" + strconv.FormatInt(w.Start.Unix(), 10) + ":" + channel.String()
+		claimed, err := m.store.ClaimAnnouncement(key)
+		if err != nil {
+			log.Error("can't claim the maintenance alert", "err", err, "channel", channel)
+
+			continue
+		}
+
+		if !claimed {
+			// another instance posted it.
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, "maintenance_alert", map[string]any{
+			"Kind":   kind,
+			"Window": w,
+		})
+		if err != nil {
+			log.Error("can't render the maintenance alert", "err", err)
+
+			continue
+		}
+
+		if err := m.hub.Announce(channel, msg); err != nil {
+			log.Warn("can't post the maintenance alert", "err", err, "channel", channel)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package maintenance
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func TestParseWindow(t *testing.T) {
+	now := time.Date(2024, 7, 1, 9, 0, 30, 0, time.UTC)
+
+	w, err := ParseWindow("2024-07-01T10:00", "90m", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC), w.Start)
+	assert.Equal(t, time.Date(2024, 7, 1, 11, 30, 0, 0, time.UTC), w.End)
+	assert.Equal(t, "1h30m", w.Length())
+
+	w, err = ParseWindow("now", "1h", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC), w.Start)
+	assert.Equal(t, "1h", w.Length())
+
+	_, err = ParseWindow("01/07/2024", "30m", now)
+	assert.ErrorIs(t, err, ErrInvalidStart)
+
+	_, err = ParseWindow("2024-07-01T10:00", "25h", now)
+	assert.ErrorIs(t, err, ErrInvalidDuration)
+
+	_, err = ParseWindow("2024-06-30T10:00", "30m", now)
+	assert.ErrorIs(t, err, ErrPast)
+}
+
+func TestMaintenance(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "pagu.db"))
+	require.NoError(t, err)
+
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	now := time.Date(2024, 7, 1, 8, 0, 0, 0, time.UTC)
+	m := NewMaintenance(settings.NewSettings(db), db, hub,
+		[]notify.Channel{{AppID: command.AppIdDiscord, ID: "ops"}}, time.Hour)
+	m.now = func() time.Time { return now }
+
+	_, ok := m.Scheduled()
+	assert.False(t, ok)
+	require.NoError(t, m.Run(context.Background()))
+
+	w, err := ParseWindow("2024-07-01T10:00", "30m", now)
+	require.NoError(t, err)
+	require.NoError(t, m.Schedule(w, "admin-id"))
+
+	scheduled, ok := m.Scheduled()
+	assert.True(t, ok)
+	assert.Equal(t, w, scheduled)
+	assert.False(t, m.Paused())
+	require.NoError(t, m.Run(context.Background()))
+	assert.Empty(t, discord["ops"], "too early for the reminder")
+
+	now = time.Date(2024, 7, 1, 9, 15, 0, 0, time.UTC)
+	require.NoError(t, m.Run(context.Background()))
+	require.NoError(t, m.Run(context.Background()))
+	require.Len(t, discord["ops"], 1, "reminded once")
+	assert.Contains(t, discord["ops"][0], "Pagu goes into maintenance at 2024-07-01 10:00 UTC for 30m")
+
+	now = time.Date(2024, 7, 1, 10, 10, 0, 0, time.UTC)
+	assert.True(t, m.Paused())
+	require.NoError(t, m.Run(context.Background()))
+	require.Len(t, discord["ops"], 2)
+	assert.Contains(t, discord["ops"][1], "Maintenance started")
+
+	now = time.Date(2024, 7, 1, 10, 31, 0, 0, time.UTC)
+	assert.False(t, m.Paused(), "resumed at the end")
+	require.NoError(t, m.Run(context.Background()))
+	require.Len(t, discord["ops"], 3)
+	assert.Contains(t, discord["ops"][2], "Maintenance ended")
+
+	canceled, err := m.Cancel()
+	require.NoError(t, err)
+	assert.False(t, canceled, "the window is ended")
+
+	var nilMaintenance *Maintenance
+	assert.False(t, nilMaintenance.Paused())
+}
@@ -0,0 +1,110 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pagu-project/Pagu/utils"
+)
+
+const (
+	CurrencyUSD = "USD"
+	CurrencyEUR = "EUR"
+	CurrencyIRR = "IRR"
+)
+
+// currencyDecimals are the decimals that the values of the supported currencies are shown with.
+var currencyDecimals = map[string]int{
+	CurrencyUSD: 2,
+	CurrencyEUR: 2,
+	CurrencyIRR: 0, // The rial has no practical fraction.
+}
+
+// Fiat converts the PAC amounts to the fiat currencies with the last price, the price is in USDT as a USD.
+// The other currencies need their rates to the USD, like 0.92 EUR for a USD.
+type Fiat struct {
+	tracker *Tracker
+	rates   map[string]float64
+}
+
+// NewFiat creates the converter with the USD rates of the currencies, the USD has no rate.
+func NewFiat(tracker *Tracker, rates map[string]float64) (*Fiat, error) {
+	all := map[string]float64{CurrencyUSD: 1}
+	for code, rate := range rates {
+		code = strings.ToUpper(code)
+		if _, ok := currencyDecimals[code]; !ok {
+			return nil, fmt.Errorf("unknown currency: %s", code)
+		}
+
+		if rate <= 0 {
+			return nil, fmt.Errorf("the rate of %s should be positive", code)
+		}
+
+		all[code] = rate
+	}
+
+	return &Fiat{
+		tracker: tracker,
+		rates:   all,
+	}, nil
+}
+
+// Currencies returns the currencies that have a rate, sorted by their code.
+func (f *Fiat) Currencies() []string {
+	codes := make([]string, 0, len(f.rates))
+	for code := range f.rates {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	return codes
+}
+
+// Currency returns the code of the currency in the upper case, false if it has no rate.
+func (f *Fiat) Currency(code string) (string, bool) {
+	code = strings.ToUpper(code)
+	_, ok := f.rates[code]
+
+	return code, ok
+}
+
+// Converter returns the function that formats the fiat value of a PAC amount in the currency,
+// like: "≈1,234.56 EUR". It fails if the price is not available.
+func (f *Fiat) Converter(ctx context.Context, code string) (func(pac float64) string, error) {
+	rate, ok := f.rates[code]
+	if !ok {
+		return nil, fmt.Errorf("unknown currency: %s", code)
+	}
+
+	price, err := f.tracker.Price(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(pac float64) string {
+		return "≈" + FormatFiat(pac*price*rate, code)
+	}, nil
+}
+
+// FormatFiat returns the value with the grouped digits and the decimals of the currency, like: "1,234.56 EUR".
+func FormatFiat(value float64, code string) string {
+	decimals := currencyDecimals[code]
+	formatted := fmt.Sprintf("%.*f", decimals, value)
+
+	whole, fraction, _ := strings.Cut(formatted, ".")
+	sign := ""
+	if strings.HasPrefix(whole, "-") {
+		sign, whole = "-", whole[1:]
+	}
+
+	num, _ := strconv.ParseInt(whole, 10, 64)
+	formatted = sign + utils.FormatNumber(num)
+	if fraction != "" {
+		formatted += "." + fraction
+	}
+
+	return formatted + " " + code
+}
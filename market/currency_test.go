@@ -0,0 +1,41 @@
+package market
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiat(t *testing.T) {
+	tracker := NewTracker(&fixedProvider{price: 0.5}, &memoryStore{}, notify.NewHub(), time.Minute)
+
+	_, err := NewFiat(tracker, map[string]float64{"GBP": 0.8})
+	assert.Error(t, err, "unknown currency")
+	_, err = NewFiat(tracker, map[string]float64{"EUR": 0})
+	assert.Error(t, err)
+
+	fiat, err := NewFiat(tracker, map[string]float64{"eur": 0.9, "IRR": 600_000})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "IRR", "USD"}, fiat.Currencies())
+
+	code, ok := fiat.Currency("eur")
+	assert.True(t, ok)
+	assert.Equal(t, "EUR", code)
+
+	convert, err := fiat.Converter(context.Background(), CurrencyEUR)
+	require.NoError(t, err)
+	assert.Equal(t, "≈1,125.00 EUR", convert(2_500))
+
+	convert, err = fiat.Converter(context.Background(), CurrencyIRR)
+	require.NoError(t, err)
+	assert.Equal(t, "≈3,000,000 IRR", convert(10), "the rial has no decimals")
+
+	_, err = fiat.Converter(context.Background(), "GBP")
+	assert.Error(t, err)
+
+	assert.Equal(t, "-0.05 USD", FormatFiat(-0.051, CurrencyUSD))
+}
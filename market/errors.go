@@ -0,0 +1,11 @@
+package market
+
+import "fmt"
+
+type PriceError struct {
+	Reason string
+}
+
+func (e PriceError) Error() string {
+	return fmt.Sprintf("can't get the price: %s", e.Reason)
+}
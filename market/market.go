@@ -0,0 +1,159 @@
+package market
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+)
+
+const DefaultPollInterval = 5 * time.Minute
+
+// Provider returns the last price of PAC in USDT.
+type Provider interface {
+	Price(ctx context.Context) (float64, error)
+}
+
+// Store keeps the hourly price samples and the price alerts of the users.
+type Store interface {
+	AddPriceSample(s *database.PriceSample) error
+	GetActivePriceAlerts() ([]*database.PriceAlert, error)
+	TriggerPriceAlert(id uint) (bool, error)
+}
+
+// Tracker polls the price, keeps a sample of each hour and notifies the users whose alerts are crossed.
+// The instances can share the store, an hour keeps one sample and an alert is triggered once.
+type Tracker struct {
+	lock     sync.Mutex
+	provider Provider
+	store    Store
+	hub      *notify.Hub
+	interval time.Duration
+	now      func() time.Time
+	price    float64
+	priceAt  time.Time
+}
+
+func NewTracker(provider Provider, store Store, hub *notify.Hub, interval time.Duration) *Tracker {
+	return &Tracker{
+		provider: provider,
+		store:    store,
+		hub:      hub,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Price returns the last polled price, the provider is called if the price is older than the interval.
+func (t *Tracker) Price(ctx context.Context) (float64, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.priceAt.IsZero() && t.now().Sub(t.priceAt) < t.interval {
+		return t.price, nil
+	}
+
+	price, err := t.provider.Price(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	t.price = price
+	t.priceAt = t.now()
+
+	return price, nil
+}
+
+// Start polls the price on the interval until the context is done.
+func (t *Tracker) Start(ctx context.Context) {
+	if t.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		t.poll(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				t.poll(ctx)
+			}
+		}
+	}()
+}
+
+func (t *Tracker) poll(ctx context.Context) {
+	price, err := t.provider.Price(ctx)
+	if err != nil {
+		log.Warn("can't poll the price", "err", err)
+
+		return
+	}
+
+	t.lock.Lock()
+	t.price = price
+	t.priceAt = t.now()
+	t.lock.Unlock()
+
+	if err := t.store.AddPriceSample(&database.PriceSample{
+		Hour:  t.now().UTC().Truncate(time.Hour),
+		Price: price,
+	}); err != nil {
+		log.Error("can't add the price sample", "err", err)
+	}
+
+	t.checkAlerts(price)
+}
+
+// checkAlerts notifies the users whose alerts are crossed by the price.
+// Only the alerts of the platforms that this instance can notify on are checked.
+func (t *Tracker) checkAlerts(price float64) {
+	alerts, err := t.store.GetActivePriceAlerts()
+	if err != nil {
+		log.Error("can't get the price alerts", "err", err)
+
+		return
+	}
+
+	for _, alert := range alerts {
+		appID := command.AppID(alert.AppID)
+		if !alert.Crossed(price) || !t.hub.Supports(appID) {
+			continue
+		}
+
+		triggered, err := t.store.TriggerPriceAlert(alert.ID)
+		if err != nil {
+			log.Error("can't trigger the price alert", "err", err, "id", alert.ID)
+
+			continue
+		}
+
+		if !triggered {
+			// another instance triggered it.
+			continue
+		}
+
+		msg, err := command.RenderTemplate(appID, "market_alert", map[string]any{
+			"Alert": alert,
+			"Price": price,
+		})
+		if err != nil {
+			log.Error("can't render the price alert", "err", err, "id", alert.ID)
+
+			continue
+		}
+
+		if err := t.hub.Notify(appID, alert.UserID, msg); err != nil {
+			log.Warn("can't notify the price alert", "err", err, "id", alert.ID, "user", alert.UserID)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package market
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedProvider struct {
+	price float64
+	calls int
+}
+
+func (p *fixedProvider) Price(_ context.Context) (float64, error) {
+	p.calls++
+
+	return p.price, nil
+}
+
+type memoryStore struct {
+	samples   []*database.PriceSample
+	alerts    []*database.PriceAlert
+	triggered map[uint]bool
+}
+
+func (s *memoryStore) AddPriceSample(sample *database.PriceSample) error {
+	s.samples = append(s.samples, sample)
+
+	return nil
+}
+
+func (s *memoryStore) GetActivePriceAlerts() ([]*database.PriceAlert, error) {
+	active := make([]*database.PriceAlert, 0)
+	for _, alert := range s.alerts {
+		if !s.triggered[alert.ID] {
+			active = append(active, alert)
+		}
+	}
+
+	return active, nil
+}
+
+func (s *memoryStore) TriggerPriceAlert(id uint) (bool, error) {
+	if s.triggered[id] {
+		return false, nil
+	}
+	s.triggered[id] = true
+
+	return true, nil
+}
+
+type messages map[string][]string
+
+func (m messages) Notify(userID, message string) error {
+	m[userID] = append(m[userID], message)
+
+	return nil
+}
+
+func TestTracker(t *testing.T) {
+	provider := &fixedProvider{price: 0.12}
+	store := &memoryStore{triggered: make(map[uint]bool)}
+	alert := func(id uint, appID command.AppID, userID string, dir database.PriceAlertDirection, price float64) {
+		a := &database.PriceAlert{AppID: int(appID), UserID: userID, Direction: dir, Price: price}
+		a.ID = id
+		store.alerts = append(store.alerts, a)
+	}
+	alert(1, command.AppIdDiscord, "alice", database.PriceAlertAbove, 0.1)
+	alert(2, command.AppIdDiscord, "bob", database.PriceAlertBelow, 0.1)
+	alert(3, command.AppIdTelegram, "carol", database.PriceAlertAbove, 0.1)
+
+	discord := messages{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	tracker := NewTracker(provider, store, hub, time.Minute)
+	tracker.poll(context.Background())
+
+	require.Len(t, store.samples, 1)
+	assert.Equal(t, 0.12, store.samples[0].Price)
+
+	require.Len(t, discord["alice"], 1)
+	assert.Contains(t, discord["alice"][0], "above 0.1000 USDT")
+	assert.Empty(t, discord["bob"], "not crossed")
+	assert.False(t, store.triggered[3], "no notifier for telegram in this instance")
+
+	tracker.poll(context.Background())
+	assert.Len(t, discord["alice"], 1, "an alert is triggered once")
+}
+
+func TestTrackerPrice(t *testing.T) {
+	provider := &fixedProvider{price: 0.12}
+	tracker := NewTracker(provider, &memoryStore{}, notify.NewHub(), time.Minute)
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		price, err := tracker.Price(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0.12, price)
+	}
+	assert.Equal(t, 1, provider.calls)
+
+	now = now.Add(time.Minute)
+	_, err := tracker.Price(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.calls, "the price is older than the interval")
+}
+
+func TestXeggex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"symbol":"PAC/USDT","lastPrice":"0.1234"}`))
+	}))
+	defer server.Close()
+
+	price, err := NewXeggex(server.URL).Price(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0.1234, price)
+
+	_, err = NewXeggex(server.URL + "/not-found\x00").Price(context.Background())
+	assert.Error(t, err)
+}
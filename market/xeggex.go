@@ -0,0 +1,68 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const DefaultPriceURL = "https://api.xeggex.com/api/v2/market/getbysymbol/PAC%2FUSDT"
+
+// Xeggex reads the last traded price of PAC on the Xeggex exchange.
+type Xeggex struct {
+	url    string
+	client *http.Client
+}
+
+func NewXeggex(url string) *Xeggex {
+	return &Xeggex{
+		url: url,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (x *Xeggex) Price(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, x.url, http.NoBody)
+	if err != nil {
+		return 0, PriceError{
+			Reason: err.Error(),
+		}
+	}
+
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return 0, PriceError{
+			Reason: err.Error(),
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, PriceError{
+			Reason: fmt.Sprintf("unexpected status: %s", resp.Status),
+		}
+	}
+
+	var market struct {
+		LastPrice string `json:"lastPrice"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&market); err != nil {
+		return 0, PriceError{
+			Reason: err.Error(),
+		}
+	}
+
+	price, err := strconv.ParseFloat(market.LastPrice, 64)
+	if err != nil {
+		return 0, PriceError{
+			Reason: err.Error(),
+		}
+	}
+
+	return price, nil
+}
@@ -0,0 +1,317 @@
+package metrics
+
+import (
+	"context"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+)
+
+const (
+	bucketDuration = time.Hour
+	retention      = 7 * 24 // number of hourly buckets kept in memory.
+)
+
+// latencyBounds are the upper bounds of the latency histogram buckets.
+var latencyBounds = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// Stats is the aggregated result of the observations in a time window.
+type Stats struct {
+	Total  int64
+	Failed int64
+	P50    time.Duration
+	P95    time.Duration
+}
+
+// SuccessRate returns the percentage of successful observations, 100 if there is no observation.
+func (s Stats) SuccessRate() float64 {
+	if s.Total == 0 {
+		return 100
+	}
+
+	return float64(s.Total-s.Failed) * 100 / float64(s.Total)
+}
+
+type bucket struct {
+	start      time.Time
+	total      int64
+	failed     int64
+	maxLatency time.Duration
+	latencies  [12]int64 // len(latencyBounds) + 1 for the overflow.
+}
+
+// series keeps the observations in a ring of hourly buckets.
+type series struct {
+	buckets [retention]bucket
+}
+
+func (s *series) observe(now time.Time, failed bool, latency time.Duration) {
+	start := now.Truncate(bucketDuration)
+	b := &s.buckets[start.Unix()/int64(bucketDuration.Seconds())%retention]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+
+	b.total++
+	if failed {
+		b.failed++
+	}
+
+	if latency > b.maxLatency {
+		b.maxLatency = latency
+	}
+
+	i := 0
+	for i < len(latencyBounds) && latency > latencyBounds[i] {
+		i++
+	}
+	b.latencies[i]++
+}
+
+func (s *series) stats(now time.Time, window time.Duration) Stats {
+	from := now.Add(-window).Truncate(bucketDuration)
+	stats := Stats{}
+	latencies := [12]int64{}
+	maxLatency := time.Duration(0)
+
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		if b.start.IsZero() || b.start.Before(from) || b.start.After(now) {
+			continue
+		}
+
+		stats.Total += b.total
+		stats.Failed += b.failed
+		for j, c := range b.latencies {
+			latencies[j] += c
+		}
+
+		if b.maxLatency > maxLatency {
+			maxLatency = b.maxLatency
+		}
+	}
+
+	stats.P50 = quantile(latencies, stats.Total, 0.50, maxLatency)
+	stats.P95 = quantile(latencies, stats.Total, 0.95, maxLatency)
+
+	return stats
+}
+
+// quantile returns the upper bound of the histogram bucket that contains the quantile q.
+func quantile(latencies [12]int64, total int64, q float64, maxLatency time.Duration) time.Duration {
+	if total == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(float64(total) * q))
+
+	cumulative := int64(0)
+	for i, c := range latencies {
+		cumulative += c
+		if cumulative >= rank {
+			if i < len(latencyBounds) && latencyBounds[i] < maxLatency {
+				return latencyBounds[i]
+			}
+
+			return maxLatency
+		}
+	}
+
+	return maxLatency
+}
+
+// Metrics collects the command and RPC observations of the bot.
+type Metrics struct {
+	lock     sync.Mutex
+	now      func() time.Time
+	commands series
+	rpc      series
+	rpcNodes map[string]*series
+
+	deprecated map[string]int64
+	errors     map[string]int64
+	shards     map[int]*ShardHealth
+	otel       instruments
+}
+
+// ShardHealth is the health of a shard of the Discord gateway, the big bots have some shards of the guilds.
+type ShardHealth struct {
+	Shard       int
+	Connected   bool
+	Guilds      int
+	Latency     time.Duration // The latency of the heartbeat of the gateway.
+	Disconnects int64         // The times the shard was disconnected since the start.
+	UpdatedAt   time.Time
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		now:        time.Now,
+		rpcNodes:   make(map[string]*series),
+		deprecated: make(map[string]int64),
+		errors:     make(map[string]int64),
+		shards:     make(map[int]*ShardHealth),
+	}
+	m.otel = newInstruments(m.ShardHealth)
+
+	return m
+}
+
+// ObserveCommand records the result and the handling latency of a command.
+func (m *Metrics) ObserveCommand(successful bool, latency time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.commands.observe(m.now(), !successful, latency)
+	m.otel.observeCommand(successful, latency)
+}
+
+// ObserveRPC records the result and the latency of a call to an RPC node.
+func (m *Metrics) ObserveRPC(node string, err error, latency time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+	m.rpc.observe(now, err != nil, latency)
+
+	nodeSeries, ok := m.rpcNodes[node]
+	if !ok {
+		nodeSeries = &series{}
+		m.rpcNodes[node] = nodeSeries
+	}
+	nodeSeries.observe(now, err != nil, latency)
+	m.otel.observeRPC(node, err != nil, latency)
+}
+
+// ObserveDeprecated counts a call to a deprecated command, to know when it's safe to remove it.
+func (m *Metrics) ObserveDeprecated(name string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.deprecated[name]++
+	m.otel.deprecated.Add(context.Background(), 1, metric.WithAttributes(attribute.String("command", name)))
+}
+
+// DeprecatedUsage returns the number of calls to each deprecated command since the start.
+func (m *Metrics) DeprecatedUsage() map[string]int64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return maps.Clone(m.deprecated)
+}
+
+// ObserveError counts a failed command by its error code, like: ERR_RPC_UNAVAILABLE.
+func (m *Metrics) ObserveError(code string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.errors[code]++
+	m.otel.errors.Add(context.Background(), 1, metric.WithAttributes(attribute.String("code", code)))
+}
+
+// ErrorCounts returns the number of the failed commands of each error code since the start.
+func (m *Metrics) ErrorCounts() map[string]int64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return maps.Clone(m.errors)
+}
+
+// ObserveShard records the health of a shard of the Discord gateway, a shard that goes down is counted
+// in its disconnects.
+func (m *Metrics) ObserveShard(shard int, connected bool, guilds int, latency time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	health, ok := m.shards[shard]
+	if !ok {
+		health = &ShardHealth{Shard: shard}
+		m.shards[shard] = health
+	}
+
+	if ok && health.Connected && !connected {
+		health.Disconnects++
+	}
+
+	health.Connected = connected
+	health.Guilds = guilds
+	health.Latency = latency
+	health.UpdatedAt = m.now()
+}
+
+// ShardHealth returns the health of the shards of the Discord gateway, sorted by the shard.
+func (m *Metrics) ShardHealth() []ShardHealth {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	shards := make([]ShardHealth, 0, len(m.shards))
+	for _, health := range m.shards {
+		shards = append(shards, *health)
+	}
+	slices.SortFunc(shards, func(a, b ShardHealth) int {
+		return a.Shard - b.Shard
+	})
+
+	return shards
+}
+
+// CommandStats returns the command statistics of the last window, up to 7 days.
+func (m *Metrics) CommandStats(window time.Duration) Stats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.commands.stats(m.now(), window)
+}
+
+// RPCStats returns the RPC statistics of the last window, up to 7 days.
+func (m *Metrics) RPCStats(window time.Duration) Stats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.rpc.stats(m.now(), window)
+}
+
+// RPCNodeStats returns the RPC statistics of each node in the last window, up to 7 days.
+func (m *Metrics) RPCNodeStats(window time.Duration) map[string]Stats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+	stats := make(map[string]Stats, len(m.rpcNodes))
+	for node, nodeSeries := range m.rpcNodes {
+		stats[node] = nodeSeries.stats(now, window)
+	}
+
+	return stats
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that records all calls to the RPC nodes.
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.ObserveRPC(cc.Target(), err, time.Since(start))
+
+		return err
+	}
+}
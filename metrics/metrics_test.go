@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 30, 0, 0, time.UTC)
+	m := NewMetrics()
+	m.now = func() time.Time { return now }
+
+	t.Run("no observation", func(t *testing.T) {
+		stats := m.CommandStats(24 * time.Hour)
+		assert.Zero(t, stats.Total)
+		assert.Equal(t, 100.0, stats.SuccessRate())
+		assert.Zero(t, stats.P95)
+	})
+
+	t.Run("windows and quantiles", func(t *testing.T) {
+		// two days ago, only in the 7 days window.
+		now = now.Add(-48 * time.Hour)
+		m.ObserveCommand(false, 3*time.Second)
+		now = now.Add(48 * time.Hour)
+
+		for i := 0; i < 99; i++ {
+			m.ObserveCommand(true, 20*time.Millisecond)
+		}
+		m.ObserveCommand(false, 700*time.Millisecond)
+
+		day := m.CommandStats(24 * time.Hour)
+		assert.Equal(t, int64(100), day.Total)
+		assert.Equal(t, int64(1), day.Failed)
+		assert.Equal(t, 99.0, day.SuccessRate())
+		assert.Equal(t, 25*time.Millisecond, day.P95)
+
+		week := m.CommandStats(7 * 24 * time.Hour)
+		assert.Equal(t, int64(101), week.Total)
+		assert.Equal(t, int64(2), week.Failed)
+	})
+
+	t.Run("old buckets are overwritten", func(t *testing.T) {
+		now = now.Add(8 * 24 * time.Hour)
+		m.ObserveCommand(true, 5*time.Millisecond)
+
+		week := m.CommandStats(7 * 24 * time.Hour)
+		assert.Equal(t, int64(1), week.Total)
+		assert.Equal(t, 5*time.Millisecond, week.P95)
+	})
+
+	t.Run("rpc errors", func(t *testing.T) {
+		m.ObserveRPC("node-1:50051", nil, time.Millisecond)
+		m.ObserveRPC("node-2:50051", errors.New("unavailable"), 40*time.Second)
+
+		stats := m.RPCStats(24 * time.Hour)
+		assert.Equal(t, int64(2), stats.Total)
+		assert.Equal(t, int64(1), stats.Failed)
+		assert.Equal(t, 40*time.Second, stats.P95)
+
+		nodes := m.RPCNodeStats(24 * time.Hour)
+		assert.Len(t, nodes, 2)
+		assert.Equal(t, 100.0, nodes["node-1:50051"].SuccessRate())
+		assert.Equal(t, 0.0, nodes["node-2:50051"].SuccessRate())
+	})
+
+	t.Run("deprecated usage", func(t *testing.T) {
+		m.ObserveDeprecated("network old-status")
+		m.ObserveDeprecated("network old-status")
+
+		usage := m.DeprecatedUsage()
+		assert.Equal(t, int64(2), usage["network old-status"])
+
+		usage["network old-status"] = 0
+		assert.Equal(t, int64(2), m.DeprecatedUsage()["network old-status"], "usage is a copy")
+	})
+
+	t.Run("error counts", func(t *testing.T) {
+		m.ObserveError("ERR_RPC_UNAVAILABLE")
+		m.ObserveError("ERR_RPC_UNAVAILABLE")
+		m.ObserveError("ERR_INVALID_ADDRESS")
+
+		assert.Equal(t, map[string]int64{
+			"ERR_RPC_UNAVAILABLE": 2,
+			"ERR_INVALID_ADDRESS": 1,
+		}, m.ErrorCounts())
+	})
+}
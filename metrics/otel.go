@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// meterName is the name of the meter of the instruments, they are exported if the telemetry is set up.
+const meterName = "github.com/pagu-project/Pagu/metrics"
+
+// instruments are the OpenTelemetry copies of the observations, for the operators with an observability stack.
+// The in-memory series are kept for the SLO reports of the admins.
+type instruments struct {
+	commands        metric.Int64Counter
+	commandDuration metric.Float64Histogram
+	rpcCalls        metric.Int64Counter
+	rpcDuration     metric.Float64Histogram
+	errors          metric.Int64Counter
+	deprecated      metric.Int64Counter
+}
+
+// newInstruments creates the instruments on the global meter, an instrument that can't be created is a no-op.
+// The gauges of the shards are read from their health on each export.
+func newInstruments(shards func() []ShardHealth) instruments {
+	meter := otel.Meter(meterName)
+	fallback := noop.Meter{}
+
+	counter := func(name, desc string) metric.Int64Counter {
+		c, err := meter.Int64Counter(name, metric.WithDescription(desc))
+		if err != nil {
+			c, _ = fallback.Int64Counter(name)
+		}
+
+		return c
+	}
+
+	histogram := func(name, desc string) metric.Float64Histogram {
+		h, err := meter.Float64Histogram(name, metric.WithDescription(desc), metric.WithUnit("s"))
+		if err != nil {
+			h, _ = fallback.Float64Histogram(name)
+		}
+
+		return h
+	}
+
+	shardGauges(meter, shards)
+
+	return instruments{
+		commands:        counter("pagu.commands", "The handled commands"),
+		commandDuration: histogram("pagu.command.duration", "The handling latency of the commands"),
+		rpcCalls:        counter("pagu.rpc.calls", "The calls to the RPC nodes"),
+		rpcDuration:     histogram("pagu.rpc.duration", "The latency of the calls to the RPC nodes"),
+		errors:          counter("pagu.command.errors", "The failed commands by their error code"),
+		deprecated:      counter("pagu.command.deprecated", "The calls to the deprecated commands"),
+	}
+}
+
+func (i instruments) observeCommand(successful bool, latency time.Duration) {
+	attrs := metric.WithAttributes(attribute.Bool("successful", successful))
+	i.commands.Add(context.Background(), 1, attrs)
+	i.commandDuration.Record(context.Background(), latency.Seconds(), attrs)
+}
+
+func (i instruments) observeRPC(node string, failed bool, latency time.Duration) {
+	attrs := metric.WithAttributes(attribute.String("node", node), attribute.Bool("failed", failed))
+	i.rpcCalls.Add(context.Background(), 1, attrs)
+	i.rpcDuration.Record(context.Background(), latency.Seconds(), attrs)
+}
+
+// shardGauges registers the gauges of the shards of the Discord gateway, they have the shard as an attribute.
+func shardGauges(meter metric.Meter, shards func() []ShardHealth) {
+	observe := func(record func(ShardHealth, metric.ObserveOption)) {
+		for _, health := range shards() {
+			record(health, metric.WithAttributes(attribute.Int("shard", health.Shard)))
+		}
+	}
+
+	_, _ = meter.Int64ObservableGauge("pagu.discord.shard.connected",
+		metric.WithDescription("One if the shard of the Discord gateway is connected"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			observe(func(health ShardHealth, attrs metric.ObserveOption) {
+				connected := int64(0)
+				if health.Connected {
+					connected = 1
+				}
+				o.Observe(connected, attrs)
+			})
+
+			return nil
+		}))
+
+	_, _ = meter.Int64ObservableGauge("pagu.discord.shard.guilds",
+		metric.WithDescription("The guilds of the shard of the Discord gateway"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			observe(func(health ShardHealth, attrs metric.ObserveOption) {
+				o.Observe(int64(health.Guilds), attrs)
+			})
+
+			return nil
+		}))
+
+	_, _ = meter.Float64ObservableGauge("pagu.discord.shard.latency",
+		metric.WithDescription("The heartbeat latency of the shard of the Discord gateway"), metric.WithUnit("s"),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			observe(func(health ShardHealth, attrs metric.ObserveOption) {
+				o.Observe(health.Latency.Seconds(), attrs)
+			})
+
+			return nil
+		}))
+}
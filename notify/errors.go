@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+type NoNotifierError struct {
+	AppID command.AppID
+}
+
+func (e NoNotifierError) Error() string {
+	return fmt.Sprintf("can't send direct messages on %s", e.AppID)
+}
+
+type NoAnnouncerError struct {
+	AppID command.AppID
+}
+
+func (e NoAnnouncerError) Error() string {
+	return fmt.Sprintf("can't post to the channels on %s", e.AppID)
+}
+
+type InvalidChannelError struct {
+	Channel string
+}
+
+func (e InvalidChannelError) Error() string {
+	return fmt.Sprintf("invalid channel: %s, it should be like: Discord:1234 or Telegram:-1001234:tr", e.Channel)
+}
@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/i18n"
+)
+
+// Notifier sends a direct message to a user of a platform, like a Discord DM.
+type Notifier interface {
+	Notify(userID, message string) error
+}
+
+// Announcer posts a message to a channel of a platform, like a Telegram group.
+// The notifiers that can post to the channels implement it.
+type Announcer interface {
+	Announce(channelID, message string) error
+}
+
+// RichAnnouncer posts a message with its attachments to a channel, like a report with a chart.
+// The announcers that can't post the attachments only post the message.
+type RichAnnouncer interface {
+	AnnounceAttachments(channelID, message string, attachments []command.Attachment) error
+}
+
+// Channel is a channel of a platform to post the announcements to.
+// The announcements to a channel with a language are posted in that language, like to a Turkish community.
+type Channel struct {
+	AppID  command.AppID
+	ID     string
+	Locale string // Empty for the default language.
+}
+
+func (c Channel) String() string {
+	if c.Locale != "" {
+		return c.AppID.String() + ":" + c.ID + ":" + c.Locale
+	}
+
+	return c.AppID.String() + ":" + c.ID
+}
+
+// ParseChannels parses the channels in "Platform:ID" or "Platform:ID:Language" format,
+// like: "Discord:1234" or "Telegram:-1001234:tr".
+func ParseChannels(values []string) ([]Channel, error) {
+	channels := make([]Channel, 0, len(values))
+	for _, value := range values {
+		platform, id, found := strings.Cut(value, ":")
+		if !found || id == "" {
+			return nil, InvalidChannelError{
+				Channel: value,
+			}
+		}
+
+		id, locale, _ := strings.Cut(id, ":")
+		if id == "" || (locale != "" && !i18n.HasLocale(locale)) {
+			return nil, InvalidChannelError{
+				Channel: value,
+			}
+		}
+
+		appID, ok := command.ParseAppID(platform)
+		if !ok {
+			return nil, InvalidChannelError{
+				Channel: value,
+			}
+		}
+
+		channels = append(channels, Channel{AppID: appID, ID: id, Locale: locale})
+	}
+
+	return channels, nil
+}
+
+// Hub routes the notifications to the notifier of the platform.
+// The platform adapters register their notifiers when they start.
+type Hub struct {
+	lock      sync.RWMutex
+	notifiers map[command.AppID]Notifier
+	source    func() []Channel
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		notifiers: make(map[command.AppID]Notifier),
+	}
+}
+
+func (h *Hub) Register(appID command.AppID, notifier Notifier) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.notifiers[appID] = notifier
+}
+
+// SetChannelSource sets the channels that the admins add at runtime, like the announcement channels of the servers.
+func (h *Hub) SetChannelSource(source func() []Channel) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.source = source
+}
+
+// Channels returns the configured channels and the channels of the source, without the repeated ones.
+func (h *Hub) Channels(configured []Channel) []Channel {
+	h.lock.RLock()
+	source := h.source
+	h.lock.RUnlock()
+
+	if source == nil {
+		return configured
+	}
+
+	channels := slices.Clone(configured)
+	for _, channel := range source() {
+		if !slices.ContainsFunc(channels, func(c Channel) bool {
+			return c.AppID == channel.AppID && c.ID == channel.ID
+		}) {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels
+}
+
+// Supports checks if the users of the platform can be notified.
+func (h *Hub) Supports(appID command.AppID) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	_, ok := h.notifiers[appID]
+
+	return ok
+}
+
+func (h *Hub) Notify(appID command.AppID, userID, message string) error {
+	h.lock.RLock()
+	notifier, ok := h.notifiers[appID]
+	h.lock.RUnlock()
+
+	if !ok {
+		return NoNotifierError{
+			AppID: appID,
+		}
+	}
+
+	return notifier.Notify(userID, message)
+}
+
+// SupportsAnnounce checks if this instance can post to the channels of the platform.
+func (h *Hub) SupportsAnnounce(appID command.AppID) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	_, ok := h.notifiers[appID].(Announcer)
+
+	return ok
+}
+
+// Announce posts the message to the channel.
+func (h *Hub) Announce(channel Channel, message string) error {
+	h.lock.RLock()
+	announcer, ok := h.notifiers[channel.AppID].(Announcer)
+	h.lock.RUnlock()
+
+	if !ok {
+		return NoAnnouncerError{
+			AppID: channel.AppID,
+		}
+	}
+
+	return announcer.Announce(channel.ID, message)
+}
+
+// AnnounceAttachments posts the message with the attachments to the channel.
+// The attachments are dropped if the announcer of the platform can't post them.
+func (h *Hub) AnnounceAttachments(channel Channel, message string, attachments []command.Attachment) error {
+	h.lock.RLock()
+	notifier := h.notifiers[channel.AppID]
+	h.lock.RUnlock()
+
+	if rich, ok := notifier.(RichAnnouncer); ok && len(attachments) > 0 {
+		return rich.AnnounceAttachments(channel.ID, message, attachments)
+	}
+
+	return h.Announce(channel, message)
+}
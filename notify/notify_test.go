@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChannels(t *testing.T) {
+	channels, err := ParseChannels([]string{"Discord:1234", "Telegram:-1001234"})
+	require.NoError(t, err)
+	assert.Equal(t, []Channel{
+		{AppID: command.AppIdDiscord, ID: "1234"},
+		{AppID: command.AppIdTelegram, ID: "-1001234"},
+	}, channels)
+	assert.Equal(t, "Telegram:-1001234", channels[1].String())
+
+	channels, err = ParseChannels([]string{"Telegram:-1001234:tr"})
+	require.NoError(t, err)
+	assert.Equal(t, []Channel{{AppID: command.AppIdTelegram, ID: "-1001234", Locale: "tr"}}, channels)
+	assert.Equal(t, "Telegram:-1001234:tr", channels[0].String())
+
+	_, err = ParseChannels([]string{"Telegram:-1001234:xx"})
+	assert.ErrorAs(t, err, &InvalidChannelError{}, "no templates of the language")
+
+	_, err = ParseChannels([]string{"IRC:1234"})
+	assert.ErrorAs(t, err, &InvalidChannelError{})
+
+	_, err = ParseChannels([]string{"Discord"})
+	assert.ErrorAs(t, err, &InvalidChannelError{})
+}
@@ -0,0 +1,169 @@
+package outbox
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/log"
+)
+
+// maxRetries is the number of times a rate-limited message is sent again, after the time the platform asks for.
+const maxRetries = 3
+
+// ErrStopped is returned for the messages that are not sent before the outbox stops.
+var ErrStopped = errors.New("the outbox is stopped")
+
+// Priority is the order of the outgoing messages, the replies to the commands are sent before the announcements.
+type Priority int
+
+const (
+	PriorityReply        Priority = iota // The replies to the commands, the users wait for them.
+	PriorityAnnouncement                 // The scheduled announcements and the notifications.
+)
+
+// RetryAfterFunc returns the time to send a message again in, if the platform rate-limited it.
+type RetryAfterFunc func(err error) (time.Duration, bool)
+
+type message struct {
+	send   func() error
+	result chan error
+}
+
+// Outbox sends the outgoing messages of a platform at the rate of the platform, like 30 messages per second
+// on Telegram. A rate-limited message is sent again after the time the platform asks for, the other messages
+// wait meanwhile. The senders wait for their message, so a full queue slows down the announcers.
+type Outbox struct {
+	interval      time.Duration
+	retryAfter    RetryAfterFunc
+	replies       chan message
+	announcements chan message
+	done          chan struct{}
+	stopOnce      sync.Once
+	sleep         func(d time.Duration, done <-chan struct{}) bool
+}
+
+// NewOutbox returns the outbox that sends the messages per second, zero or less doesn't pace them.
+// The queue is the number of the messages of each priority that wait to be sent.
+func NewOutbox(perSecond float64, queue int, retryAfter RetryAfterFunc) *Outbox {
+	interval := time.Duration(0)
+	if perSecond > 0 {
+		interval = time.Duration(float64(time.Second) / perSecond)
+	}
+
+	if retryAfter == nil {
+		retryAfter = func(error) (time.Duration, bool) { return 0, false }
+	}
+
+	return &Outbox{
+		interval:      interval,
+		retryAfter:    retryAfter,
+		replies:       make(chan message, queue),
+		announcements: make(chan message, queue),
+		done:          make(chan struct{}),
+		sleep:         sleep,
+	}
+}
+
+// Start starts sending the messages, until the outbox stops.
+func (o *Outbox) Start() {
+	go o.run()
+}
+
+// Stop stops the outbox, the messages in the queue are not sent.
+func (o *Outbox) Stop() {
+	o.stopOnce.Do(func() { close(o.done) })
+}
+
+// Send queues the message and waits for it to be sent, it returns the error of the platform.
+func (o *Outbox) Send(priority Priority, send func() error) error {
+	queue := o.announcements
+	if priority == PriorityReply {
+		queue = o.replies
+	}
+
+	msg := message{send: send, result: make(chan error, 1)}
+	select {
+	case queue <- msg:
+	case <-o.done:
+		return ErrStopped
+	}
+
+	select {
+	case err := <-msg.result:
+		return err
+	case <-o.done:
+		return ErrStopped
+	}
+}
+
+func (o *Outbox) run() {
+	for {
+		msg, ok := o.next()
+		if !ok {
+			return
+		}
+
+		msg.result <- o.deliver(msg)
+
+		if !o.sleep(o.interval, o.done) {
+			return
+		}
+	}
+}
+
+// next returns the next message to send, a waiting reply is sent before the announcements.
+func (o *Outbox) next() (message, bool) {
+	select {
+	case msg := <-o.replies:
+		return msg, true
+	default:
+	}
+
+	select {
+	case msg := <-o.replies:
+		return msg, true
+	case msg := <-o.announcements:
+		return msg, true
+	case <-o.done:
+		return message{}, false
+	}
+}
+
+// deliver sends the message, a rate-limited message is sent again after the time that the platform asks for.
+func (o *Outbox) deliver(msg message) error {
+	for attempt := 0; ; attempt++ {
+		err := msg.send()
+		retryAfter, limited := o.retryAfter(err)
+		if err == nil || !limited || attempt == maxRetries {
+			return err
+		}
+
+		log.Warn("the platform rate-limited a message", "retryAfter", retryAfter, "attempt", attempt+1)
+		if !o.sleep(retryAfter, o.done) {
+			return ErrStopped
+		}
+	}
+}
+
+// sleep waits for the duration, it returns false if the outbox stops meanwhile.
+func sleep(d time.Duration, done <-chan struct{}) bool {
+	if d <= 0 {
+		select {
+		case <-done:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-done:
+		return false
+	}
+}
@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errRateLimited = errors.New("too many requests")
+
+func TestPriority(t *testing.T) {
+	o := NewOutbox(0, 10, nil)
+	defer o.Stop()
+
+	var lock sync.Mutex
+	sent := make([]string, 0)
+	send := func(name string) func() error {
+		return func() error {
+			lock.Lock()
+			defer lock.Unlock()
+			sent = append(sent, name)
+
+			return nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	queue := func(priority Priority, name string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, o.Send(priority, send(name)))
+		}()
+	}
+
+	queue(PriorityAnnouncement, "announcement")
+	assert.Eventually(t, func() bool { return len(o.announcements) == 1 }, time.Second, time.Millisecond)
+	queue(PriorityReply, "reply")
+	assert.Eventually(t, func() bool { return len(o.replies) == 1 }, time.Second, time.Millisecond)
+
+	o.Start()
+	wg.Wait()
+
+	assert.Equal(t, []string{"reply", "announcement"}, sent)
+}
+
+func TestRetryAfter(t *testing.T) {
+	o := NewOutbox(0, 1, func(err error) (time.Duration, bool) {
+		return 2 * time.Second, errors.Is(err, errRateLimited)
+	})
+	slept := make([]time.Duration, 0)
+	o.sleep = func(d time.Duration, _ <-chan struct{}) bool {
+		if d > 0 {
+			slept = append(slept, d)
+		}
+
+		return true
+	}
+	o.Start()
+	defer o.Stop()
+
+	attempts := 0
+	err := o.Send(PriorityReply, func() error {
+		attempts++
+		if attempts < 3 {
+			return errRateLimited
+		}
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []time.Duration{2 * time.Second, 2 * time.Second}, slept)
+
+	attempts = 0
+	err = o.Send(PriorityReply, func() error {
+		attempts++
+
+		return errRateLimited
+	})
+	assert.ErrorIs(t, err, errRateLimited, "the retries are limited")
+	assert.Equal(t, maxRetries+1, attempts)
+
+	err = o.Send(PriorityReply, func() error { return errors.New("forbidden") })
+	assert.EqualError(t, err, "forbidden", "the other errors are not retried")
+}
+
+func TestPacing(t *testing.T) {
+	o := NewOutbox(20, 5, nil)
+	o.Start()
+	defer o.Stop()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, o.Send(PriorityAnnouncement, func() error { return nil }))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond, "20 messages per second")
+}
+
+func TestStop(t *testing.T) {
+	o := NewOutbox(0, 1, nil)
+	o.Stop()
+
+	assert.ErrorIs(t, o.Send(PriorityReply, func() error { return nil }), ErrStopped)
+}
@@ -0,0 +1,239 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/queue"
+)
+
+// checkTimeout is the time that a check waits for a node or a platform.
+const checkTimeout = 10 * time.Second
+
+// ErrSkipped is returned by the checks of the optional parts that are not configured.
+var ErrSkipped = errors.New("not configured")
+
+// Check is a check of the config, the detail is shown in the report.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (string, error)
+}
+
+type Status string
+
+const (
+	StatusOK      Status = "OK"
+	StatusFailed  Status = "FAIL"
+	StatusSkipped Status = "SKIP"
+)
+
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+}
+
+// Report is the results of the checks, in the order of the checks.
+type Report struct {
+	Results []Result
+}
+
+// Failed returns the number of the failed checks.
+func (r Report) Failed() int {
+	failed := 0
+	for _, res := range r.Results {
+		if res.Status == StatusFailed {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+func (r Report) String() string {
+	var sb strings.Builder
+	for _, res := range r.Results {
+		fmt.Fprintf(&sb, "[%s]%s %s", res.Status, strings.Repeat(" ", 4-len(res.Status)), res.Name)
+		if res.Detail != "" {
+			fmt.Fprintf(&sb, ": %s", res.Detail)
+		}
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "%d checks, %d failed", len(r.Results), r.Failed())
+
+	return sb.String()
+}
+
+// Run runs the checks one by one, each one with a timeout.
+func Run(ctx context.Context, checks []Check) Report {
+	report := Report{
+		Results: make([]Result, 0, len(checks)),
+	}
+
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		detail, err := check.Run(checkCtx)
+		cancel()
+
+		res := Result{
+			Name:   check.Name,
+			Status: StatusOK,
+			Detail: detail,
+		}
+
+		switch {
+		case errors.Is(err, ErrSkipped):
+			res.Status = StatusSkipped
+			res.Detail = err.Error()
+
+		case err != nil:
+			res.Status = StatusFailed
+			res.Detail = err.Error()
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	return report
+}
+
+// Checks returns the checks of the config: the RPC nodes, the database and the tokens of the platforms.
+// The tokens of the required platforms should be set, the others are checked if they are set.
+func Checks(cfg *config.Config, required ...command.AppID) []Check {
+	checks := make([]Check, 0)
+
+	checks = append(checks, Check{
+		Name: "Local node " + client.RedactEndpoint(cfg.LocalNode),
+		Run:  nodeCheck(cfg.RPCClient, cfg.LocalNode),
+	})
+
+	for _, node := range cfg.NetworkNodes {
+		if node == "" {
+			continue
+		}
+
+		checks = append(checks, Check{
+			Name: "Network node " + client.RedactEndpoint(node),
+			Run:  nodeCheck(cfg.RPCClient, node),
+		})
+	}
+
+	checks = append(checks,
+		Check{
+			Name: "Database " + cfg.DataBasePath,
+			Run: func(_ context.Context) (string, error) {
+				// the check doesn't create the database, the bot creates and migrates it on the start.
+				if _, err := os.Stat(cfg.DataBasePath); errors.Is(err, os.ErrNotExist) {
+					return "not created yet, it's created on the start", nil
+				}
+
+				return "connected", database.Ping(cfg.DataBasePath)
+			},
+		},
+		Check{
+			Name: "Discord token",
+			Run: tokenCheck(cfg.DiscordBot.Token, slices.Contains(required, command.AppIdDiscord),
+				func(token string) (string, error) {
+					s, err := discordgo.New("Bot " + token)
+					if err != nil {
+						return "", err
+					}
+
+					user, err := s.User("@me")
+					if err != nil {
+						return "", err
+					}
+
+					return "bot " + user.Username, nil
+				}),
+		},
+		Check{
+			Name: "Telegram token",
+			Run: tokenCheck(cfg.Telegram.BotToken, slices.Contains(required, command.AppIdTelegram),
+				func(token string) (string, error) {
+					// creating the bot calls getMe, it fails if the token is invalid.
+					bot, err := gotgbot.NewBot(token, nil)
+					if err != nil {
+						return "", err
+					}
+
+					return "bot @" + bot.Username, nil
+				}),
+		},
+		Check{
+			Name: "Message queue",
+			Run: func(_ context.Context) (string, error) {
+				if cfg.Queue.URL == "" {
+					if slices.Contains(required, command.AppIdQueue) {
+						return "", errors.New("the URL is empty")
+					}
+
+					return "", ErrSkipped
+				}
+
+				broker, err := queue.NewNATS(cfg.Queue.URL)
+				if err != nil {
+					return "", err
+				}
+
+				return "connected", broker.Close()
+			},
+		},
+	)
+
+	return checks
+}
+
+func nodeCheck(rpcCfg config.RPCClient, endpoint string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		address, _, err := client.ParseEndpoint(endpoint)
+		if err != nil {
+			return "", err
+		}
+
+		if address == "" {
+			return "", errors.New("the address is empty")
+		}
+
+		// the TLS endpoints are checked with their certificates and auth.
+		rpcCfg.Size = 1
+		c, err := client.NewPool(rpcCfg).NewClient(address)
+		if err != nil {
+			return "", err
+		}
+		defer c.Close()
+
+		height, err := c.GetBlockchainHeight(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("reachable at height %d", height), nil
+	}
+}
+
+func tokenCheck(token string, required bool, verify func(token string) (string, error),
+) func(ctx context.Context) (string, error) {
+	return func(_ context.Context) (string, error) {
+		if token == "" {
+			if required {
+				return "", errors.New("the token is empty")
+			}
+
+			return "", ErrSkipped
+		}
+
+		return verify(token)
+	}
+}
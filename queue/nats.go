@@ -0,0 +1,63 @@
+package queue
+
+import (
+	"github.com/nats-io/nats.go"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// NATS is the broker of a NATS server, the requests are shared by the queue group.
+type NATS struct {
+	conn   *nats.Conn
+	closed chan struct{}
+}
+
+func NewNATS(url string) (*NATS, error) {
+	closed := make(chan struct{})
+	conn, err := nats.Connect(url,
+		nats.Name("pagu"),
+		// keep reconnecting, the requests are received again when the server is back.
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			log.Warn("disconnected from NATS", "err", err)
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			log.Info("reconnected to NATS", "url", c.ConnectedUrl())
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			close(closed)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATS{
+		conn:   conn,
+		closed: closed,
+	}, nil
+}
+
+func (n *NATS) Subscribe(subject, group string, handler func(msg Message)) error {
+	_, err := n.conn.QueueSubscribe(subject, group, func(m *nats.Msg) {
+		handler(Message{
+			Data:  m.Data,
+			Reply: m.Reply,
+		})
+	})
+
+	return err
+}
+
+func (n *NATS) Publish(subject string, data []byte) error {
+	return n.conn.Publish(subject, data)
+}
+
+// Close drains the connection, so the received requests are handled and their responses are sent.
+func (n *NATS) Close() error {
+	if err := n.conn.Drain(); err != nil {
+		return err
+	}
+	<-n.closed
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package queue
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+)
+
+const (
+	DefaultSubject       = "pagu.commands"
+	DefaultGroup         = "pagu"
+	DefaultResultSubject = "pagu.results"
+)
+
+// Request is a command request of a service, the ID is sent back in the response to match them.
+// The caller ID is chosen by the publisher, so it names the caller but doesn't authorize the admin commands.
+type Request struct {
+	ID       string `json:"id"`
+	CallerID string `json:"caller_id"`
+	Command  string `json:"command"`
+}
+
+type Response struct {
+	ID         string `json:"id"`
+	Successful bool   `json:"successful"`
+	Message    string `json:"message"`
+}
+
+// Message is a message of the broker, the reply is the subject that the publisher waits for the response on.
+type Message struct {
+	Data  []byte
+	Reply string
+}
+
+// Broker is the message queue, like NATS.
+type Broker interface {
+	// Subscribe calls the handler for the messages of the subject, a message is handled by one member of the group.
+	// The handler of a subscription is called once at a time.
+	Subscribe(subject, group string, handler func(msg Message)) error
+	Publish(subject string, data []byte) error
+	// Close stops the subscriptions, waits for the handlers and closes the connection.
+	Close() error
+}
+
+// Engine runs the commands, like the bot engine.
+type Engine interface {
+	Run(appID command.AppID, callerID string, tokens []string) command.CommandResult
+}
+
+// Server consumes the command requests from the queue and publishes the results,
+// so the other services can run the commands asynchronously.
+type Server struct {
+	engine Engine
+	broker Broker
+	cfg    config.Queue
+}
+
+func NewServer(be Engine, broker Broker, cfg config.Queue) *Server {
+	if cfg.Subject == "" {
+		cfg.Subject = DefaultSubject
+	}
+
+	if cfg.Group == "" {
+		cfg.Group = DefaultGroup
+	}
+
+	if cfg.ResultSubject == "" {
+		cfg.ResultSubject = DefaultResultSubject
+	}
+
+	if cfg.Workers <= 0 {
+		cfg.Workers = config.DefaultQueueWorkers
+	}
+
+	return &Server{
+		engine: be,
+		broker: broker,
+		cfg:    cfg,
+	}
+}
+
+// Start subscribes the workers to the requests, each worker runs one request at a time.
+func (s *Server) Start() error {
+	log.Info("Starting Queue Server", "subject", s.cfg.Subject, "group", s.cfg.Group, "workers", s.cfg.Workers)
+
+	for i := int64(0); i < s.cfg.Workers; i++ {
+		if err := s.broker.Subscribe(s.cfg.Subject, s.cfg.Group, s.handle); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) Stop() error {
+	log.Info("Stopping Queue Server")
+
+	return s.broker.Close()
+}
+
+func (s *Server) handle(msg Message) {
+	res := s.run(msg.Data)
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		log.Error("can't marshal the queue response", "err", err, "id", res.ID)
+
+		return
+	}
+
+	subject := msg.Reply
+	if subject == "" {
+		subject = s.cfg.ResultSubject
+	}
+
+	if err := s.broker.Publish(subject, data); err != nil {
+		log.Error("can't publish the queue response", "err", err, "id", res.ID, "subject", subject)
+	}
+}
+
+func (s *Server) run(data []byte) Response {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return Response{
+			Successful: false,
+			Message:    "invalid request: " + err.Error(),
+		}
+	}
+
+	tokens := strings.Fields(req.Command)
+	if len(tokens) == 0 {
+		return Response{
+			ID:         req.ID,
+			Successful: false,
+			Message:    "invalid request: the command is empty",
+		}
+	}
+
+	res := s.engine.Run(command.AppIdQueue, req.CallerID, tokens)
+
+	return Response{
+		ID:         req.ID,
+		Successful: res.Successful,
+		Message:    res.Message,
+	}
+}
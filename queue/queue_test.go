@@ -0,0 +1,97 @@
+package queue
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pagu-project/Pagu/config"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBroker struct {
+	handlers  []func(msg Message)
+	published map[string][][]byte
+}
+
+func (b *testBroker) Subscribe(_, _ string, handler func(msg Message)) error {
+	b.handlers = append(b.handlers, handler)
+
+	return nil
+}
+
+func (b *testBroker) Publish(subject string, data []byte) error {
+	b.published[subject] = append(b.published[subject], data)
+
+	return nil
+}
+
+func (*testBroker) Close() error {
+	return nil
+}
+
+type testEngine struct{}
+
+func (testEngine) Run(appID command.AppID, callerID string, tokens []string) command.CommandResult {
+	return command.CommandResult{
+		Message:    appID.String() + " " + callerID + " " + strings.Join(tokens, " "),
+		Successful: tokens[0] != "unknown",
+	}
+}
+
+func response(t *testing.T, data []byte) Response {
+	t.Helper()
+
+	var res Response
+	require.NoError(t, json.Unmarshal(data, &res))
+
+	return res
+}
+
+func TestServer(t *testing.T) {
+	broker := &testBroker{
+		published: make(map[string][][]byte),
+	}
+	server := NewServer(testEngine{}, broker, config.Queue{Workers: 2})
+	require.NoError(t, server.Start())
+	require.Len(t, broker.handlers, 2)
+
+	t.Run("reply subject", func(t *testing.T) {
+		broker.handlers[0](Message{
+			Data:  []byte(`{"id":"1","caller_id":"explorer","command":"network  health"}`),
+			Reply: "_INBOX.1",
+		})
+
+		require.Len(t, broker.published["_INBOX.1"], 1)
+		res := response(t, broker.published["_INBOX.1"][0])
+		assert.Equal(t, "1", res.ID)
+		assert.True(t, res.Successful)
+		assert.Equal(t, "Queue explorer network health", res.Message)
+	})
+
+	t.Run("result subject", func(t *testing.T) {
+		broker.handlers[1](Message{
+			Data: []byte(`{"id":"2","caller_id":"explorer","command":"unknown"}`),
+		})
+
+		require.Len(t, broker.published[DefaultResultSubject], 1)
+		res := response(t, broker.published[DefaultResultSubject][0])
+		assert.Equal(t, "2", res.ID)
+		assert.False(t, res.Successful)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		broker.handlers[0](Message{Data: []byte(`not json`), Reply: "_INBOX.3"})
+		res := response(t, broker.published["_INBOX.3"][0])
+		assert.False(t, res.Successful)
+		assert.Contains(t, res.Message, "invalid request")
+
+		broker.handlers[0](Message{Data: []byte(`{"id":"4","command":" "}`), Reply: "_INBOX.4"})
+		res = response(t, broker.published["_INBOX.4"][0])
+		assert.Equal(t, "4", res.ID)
+		assert.False(t, res.Successful)
+		assert.Contains(t, res.Message, "the command is empty")
+	})
+}
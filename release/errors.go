@@ -0,0 +1,11 @@
+package release
+
+import "fmt"
+
+type ReleaseError struct {
+	Reason string
+}
+
+func (e ReleaseError) Error() string {
+	return fmt.Sprintf("can't get the latest release: %s", e.Reason)
+}
@@ -0,0 +1,109 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const DefaultReleaseURL = "https://api.github.com/repos/pactus-project/pactus/releases/latest"
+
+// maxHighlights is the number of the changelog items kept in the highlights.
+const maxHighlights = 5
+
+// urgentPattern finds the releases that the node operators should upgrade to soon.
+var urgentPattern = regexp.MustCompile(`(?i)\b(urgent|critical|security|mandatory|hard[\s-]?fork|must\s+upgrade)\b`)
+
+// GitHub reads the latest release of the Pactus node from the GitHub API.
+type GitHub struct {
+	url    string
+	client *http.Client
+}
+
+func NewGitHub(url string) *GitHub {
+	return &GitHub{
+		url: url,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (g *GitHub) Latest(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.url, http.NoBody)
+	if err != nil {
+		return nil, ReleaseError{
+			Reason: err.Error(),
+		}
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, ReleaseError{
+			Reason: err.Error(),
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ReleaseError{
+			Reason: fmt.Sprintf("unexpected status: %s", resp.Status),
+		}
+	}
+
+	var gh struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		Body        string    `json:"body"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gh); err != nil {
+		return nil, ReleaseError{
+			Reason: err.Error(),
+		}
+	}
+
+	if gh.TagName == "" {
+		return nil, ReleaseError{
+			Reason: "no release tag",
+		}
+	}
+
+	return &Release{
+		Version:     gh.TagName,
+		Name:        gh.Name,
+		URL:         gh.HTMLURL,
+		PublishedAt: gh.PublishedAt,
+		Highlights:  highlights(gh.Body),
+		Urgent:      urgentPattern.MatchString(gh.Name + "\n" + gh.Body),
+	}, nil
+}
+
+// highlights returns the first items of the changelog, they are the list items in the release notes.
+func highlights(body string) []string {
+	items := make([]string, 0, maxHighlights)
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		item, ok := strings.CutPrefix(line, "- ")
+		if !ok {
+			item, ok = strings.CutPrefix(line, "* ")
+		}
+
+		if !ok || strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		items = append(items, strings.TrimSpace(item))
+		if len(items) == maxHighlights {
+			break
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,151 @@
+package release
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const DefaultPollInterval = time.Hour
+
+// maxAnnounceAge is the age of the releases that are announced,
+// so the old releases are not announced when the bot starts in a new deployment.
+const maxAnnounceAge = 7 * 24 * time.Hour
+
+// Release is a release of the Pactus node.
+type Release struct {
+	Version     string
+	Name        string
+	URL         string
+	PublishedAt time.Time
+	Highlights  []string
+	Urgent      bool // The release notes ask for a soon upgrade, like a security fix.
+}
+
+// Provider returns the latest release of the Pactus node.
+type Provider interface {
+	Latest(ctx context.Context) (*Release, error)
+}
+
+// Store keeps the posted announcements.
+type Store interface {
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Watcher polls the latest release and announces the new ones to the channels.
+// The instances can share the store, a release is announced once to a channel.
+type Watcher struct {
+	lock      sync.Mutex
+	provider  Provider
+	store     Store
+	hub       *notify.Hub
+	channels  []notify.Channel
+	interval  time.Duration
+	now       func() time.Time
+	latest    *Release
+	fetchedAt time.Time
+}
+
+func NewWatcher(provider Provider, store Store, hub *notify.Hub,
+	channels []notify.Channel, interval time.Duration,
+) *Watcher {
+	return &Watcher{
+		provider: provider,
+		store:    store,
+		hub:      hub,
+		channels: channels,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Latest returns the latest release, the provider is called if the last one is older than the interval.
+func (w *Watcher) Latest(ctx context.Context) (*Release, error) {
+	w.lock.Lock()
+	latest, fetchedAt := w.latest, w.fetchedAt
+	w.lock.Unlock()
+
+	if latest != nil && w.now().Sub(fetchedAt) < w.interval {
+		return latest, nil
+	}
+
+	return w.refresh(ctx)
+}
+
+func (w *Watcher) refresh(ctx context.Context) (*Release, error) {
+	latest, err := w.provider.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w.lock.Lock()
+	w.latest = latest
+	w.fetchedAt = w.now()
+	w.lock.Unlock()
+
+	return latest, nil
+}
+
+// Job returns the scheduler job of the announcements. It's not exclusive,
+// each instance posts to the channels of the platforms that it can post on.
+func (w *Watcher) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:      "release",
+		Interval:  w.interval,
+		Exclusive: false,
+		Run:       w.Run,
+	}
+}
+
+// Run announces the latest release if it's new.
+func (w *Watcher) Run(ctx context.Context) error {
+	channels := w.hub.Channels(w.channels)
+	if len(channels) == 0 {
+		return nil
+	}
+
+	latest, err := w.refresh(ctx)
+	if err != nil {
+		return err
+	}
+
+	if w.now().Sub(latest.PublishedAt) > maxAnnounceAge {
+		return nil
+	}
+
+	for _, channel := range channels {
+		if !w.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		claimed, err := w.store.ClaimAnnouncement("release:" + latest.Version + ":" + channel.String())
+		if err != nil {
+			log.Error("can't claim the release announcement", "err", err, "channel", channel)
+
+			continue
+		}
+
+		if !claimed {
+			// announced already.
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, "release_announcement", latest)
+		if err != nil {
+			log.Error("can't render the release announcement", "err", err, "version", latest.Version)
+
+			continue
+		}
+
+		if err := w.hub.Announce(channel, msg); err != nil {
+			log.Warn("can't announce the release", "err", err, "channel", channel, "version", latest.Version)
+		}
+	}
+
+	return nil
+}
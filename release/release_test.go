@@ -0,0 +1,111 @@
+package release
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedProvider struct {
+	release *Release
+	calls   int
+}
+
+func (p *fixedProvider) Latest(_ context.Context) (*Release, error) {
+	p.calls++
+
+	return p.release, nil
+}
+
+type memoryStore map[string]bool
+
+func (s memoryStore) ClaimAnnouncement(key string) (bool, error) {
+	if s[key] {
+		return false, nil
+	}
+	s[key] = true
+
+	return true, nil
+}
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func TestGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"tag_name": "v1.2.0",
+			"name": "v1.2.0 Security release",
+			"html_url": "https://github.com/pactus-project/pactus/releases/tag/v1.2.0",
+			"published_at": "2024-05-10T08:00:00Z",
+			"body": "## What's Changed\n- Fix the sync issue\n* Add the new API\n-\nSome text\n- a\n- b\n- c\n- d"
+		}`))
+	}))
+	defer server.Close()
+
+	r, err := NewGitHub(server.URL).Latest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", r.Version)
+	assert.True(t, r.Urgent)
+	assert.Equal(t, time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC), r.PublishedAt)
+	assert.Equal(t, []string{"Fix the sync issue", "Add the new API", "a", "b", "c"}, r.Highlights)
+}
+
+func TestWatcher(t *testing.T) {
+	now := time.Date(2024, 5, 11, 8, 0, 0, 0, time.UTC)
+	provider := &fixedProvider{release: &Release{
+		Version:     "v1.2.0",
+		URL:         "https://github.com/pactus-project/pactus/releases/tag/v1.2.0",
+		PublishedAt: now.Add(-24 * time.Hour),
+		Highlights:  []string{"Fix the sync issue"},
+	}}
+
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	store := memoryStore{}
+	w := NewWatcher(provider, store, hub, []notify.Channel{
+		{AppID: command.AppIdDiscord, ID: "news"},
+		{AppID: command.AppIdDiscord, ID: "news-tr", Locale: "tr"},
+		{AppID: command.AppIdTelegram, ID: "-1001"},
+	}, time.Hour)
+	w.now = func() time.Time { return now }
+
+	require.NoError(t, w.Run(context.Background()))
+	require.Len(t, discord["news"], 1)
+	assert.Equal(t, "New Pactus node release v1.2.0🔔\n\nHighlights:\n  - Fix the sync issue\n\n"+
+		"https://github.com/pactus-project/pactus/releases/tag/v1.2.0", discord["news"][0])
+	require.Len(t, discord["news-tr"], 1)
+	assert.Equal(t, "Yeni Pactus düğüm sürümü v1.2.0🔔\n\nÖne çıkanlar:\n  - Fix the sync issue\n\n"+
+		"https://github.com/pactus-project/pactus/releases/tag/v1.2.0", discord["news-tr"][0])
+	assert.True(t, store["release:v1.2.0:Discord:news-tr:tr"])
+	assert.False(t, store["release:v1.2.0:Telegram:-1001"], "no announcer for telegram in this instance")
+
+	require.NoError(t, w.Run(context.Background()))
+	assert.Len(t, discord["news"], 1, "a release is announced once")
+
+	provider.release = &Release{Version: "v1.1.0", PublishedAt: now.Add(-30 * 24 * time.Hour)}
+	require.NoError(t, w.Run(context.Background()))
+	assert.Len(t, discord["news"], 1, "old releases are not announced")
+
+	_, err := w.Latest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, provider.calls, "the latest release is cached in the interval")
+}
@@ -0,0 +1,300 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+	"github.com/pagu-project/Pagu/utils"
+)
+
+const (
+	// checkInterval is the time between the checks of the report time, the report is posted in its hour.
+	checkInterval = 10 * time.Minute
+
+	// reportDays is the days that a report covers.
+	reportDays = 7
+
+	// templateName is the template of the report, a deployment can override it by the custom templates.
+	templateName = "weekly_report"
+)
+
+// Store keeps the daily network snapshots, the indexed blocks, the price samples and the posted announcements.
+type Store interface {
+	GetNetworkSnapshot(at time.Time) (*database.NetworkSnapshot, error)
+	GetBlockStats(since time.Time) (*database.BlockStats, error)
+	GetBiggestTransfer(since time.Time) (*database.AccountTransaction, error)
+	GetPriceSamples(since time.Time) ([]*database.PriceSample, error)
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Transfer is the biggest transfer of the week.
+type Transfer struct {
+	TxID     string
+	Sender   string
+	Receiver string
+	Amount   amount.Amount
+}
+
+// Day is the new accounts of a day of the week, the bars of the chart.
+type Day struct {
+	Day         time.Time
+	NewAccounts int32
+}
+
+// Report is the activity of the network in the week to the time.
+// The fields that the history doesn't have are zero, like for a young index.
+type Report struct {
+	From          time.Time
+	To            time.Time
+	Blocks        uint32        // The indexed blocks in the week.
+	BlockTime     time.Duration // The average time between the blocks, zero if there are not enough blocks.
+	Accounts      int32
+	NewAccounts   int32
+	Validators    int32
+	NewValidators int32
+	History       bool      // The week has a snapshot to compare the accounts and the validators with.
+	Transfer      *Transfer // Nil if the week has no indexed transfer.
+	Price         float64   // The last price in USDT, zero if there is no sample.
+	PriceChange   float64   // The change of the price in the week, in percent.
+	Days          []Day
+}
+
+// Reporter posts the weekly report of the network to the channels, on the day and the hour of the week in UTC.
+// The template of the report is weekly_report.tmpl, so a deployment can customize it with its custom templates.
+type Reporter struct {
+	clientMgr *client.Mgr
+	store     Store
+	hub       *notify.Hub
+	channels  []notify.Channel
+	weekday   time.Weekday
+	hour      int
+	now       func() time.Time
+}
+
+// NewReporter creates the reporter of the week, the announcement channels of the servers get the report too.
+func NewReporter(clientMgr *client.Mgr, store Store, hub *notify.Hub, channels []notify.Channel,
+	weekday time.Weekday, hour int,
+) *Reporter {
+	return &Reporter{
+		clientMgr: clientMgr,
+		store:     store,
+		hub:       hub,
+		channels:  channels,
+		weekday:   weekday,
+		hour:      hour,
+		now:       time.Now,
+	}
+}
+
+// Job returns the scheduler job of the report. It's not exclusive, the reports are claimed by the week,
+// so a report of the instances is posted once.
+func (r *Reporter) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:      "report",
+		Interval:  checkInterval,
+		Exclusive: false,
+		Run:       r.Run,
+	}
+}
+
+// Run posts the report of the week if it's the day and the hour of the report, or later in that day.
+func (r *Reporter) Run(ctx context.Context) error {
+	now := r.now().UTC()
+	channels := r.hub.Channels(r.channels)
+	if len(channels) == 0 || now.Weekday() != r.weekday || now.Hour() < r.hour {
+		return nil
+	}
+
+	report, err := r.Report(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	year, week := now.ISOWeek()
+	r.announce(channels, fmt.Sprintf("%d-W%02d", year, week), report)
+
+	return nil
+}
+
+// Report returns the report of the week to the time.
+func (r *Reporter) Report(ctx context.Context, to time.Time) (*Report, error) {
+	info, err := r.clientMgr.GetBlockchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from := to.AddDate(0, 0, -reportDays)
+	report := &Report{
+		From:       from,
+		To:         to,
+		Accounts:   info.TotalAccounts,
+		Validators: info.TotalValidators,
+	}
+
+	weekAgo, err := r.store.GetNetworkSnapshot(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if weekAgo != nil {
+		report.History = true
+		report.NewAccounts = info.TotalAccounts - weekAgo.Accounts
+		report.NewValidators = info.TotalValidators - weekAgo.Validators
+	}
+
+	if report.Days, err = r.days(to, info.TotalAccounts); err != nil {
+		return nil, err
+	}
+
+	stats, err := r.store.GetBlockStats(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats != nil {
+		report.Blocks = stats.LastHeight - stats.FirstHeight + 1
+		if stats.LastHeight > stats.FirstHeight {
+			report.BlockTime = stats.LastTime.Sub(stats.FirstTime) / time.Duration(stats.LastHeight-stats.FirstHeight)
+		}
+	}
+
+	biggest, err := r.store.GetBiggestTransfer(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if biggest != nil {
+		report.Transfer = &Transfer{
+			TxID:     biggest.TxID,
+			Sender:   biggest.Address,
+			Receiver: biggest.Counterparty,
+			Amount:   amount.Amount(biggest.Amount),
+		}
+	}
+
+	samples, err := r.store.GetPriceSamples(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(samples) > 0 {
+		first, last := samples[0].Price, samples[len(samples)-1].Price
+		report.Price = last
+		if first > 0 {
+			report.PriceChange = (last - first) * 100 / first
+		}
+	}
+
+	return report, nil
+}
+
+// days returns the new accounts of the last full days by the daily snapshots, the snapshot of a day is taken
+// at its start. A day without the snapshots of its start and its end has no new accounts.
+func (r *Reporter) days(to time.Time, accounts int32) ([]Day, error) {
+	today := to.Truncate(24 * time.Hour)
+	start := today.AddDate(0, 0, -reportDays)
+	counts := make([]int32, 0, reportDays+1)
+	for i := 0; i <= reportDays; i++ {
+		day := start.AddDate(0, 0, i)
+		s, err := r.store.GetNetworkSnapshot(day)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case s != nil && s.Day.Equal(day):
+			counts = append(counts, s.Accounts)
+		case day.Equal(today):
+			// today's snapshot may not be taken yet.
+			counts = append(counts, accounts)
+		default:
+			counts = append(counts, 0)
+		}
+	}
+
+	days := make([]Day, 0, reportDays)
+	for i := 0; i < reportDays; i++ {
+		day := Day{Day: start.AddDate(0, 0, i)}
+		if counts[i] > 0 && counts[i+1] >= counts[i] {
+			day.NewAccounts = counts[i+1] - counts[i]
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// chart draws the new accounts of the days, labeled by the day of the month.
+// Failing to draw the chart is not fatal, the report is posted without it.
+func chart(report *Report) []command.Attachment {
+	labels := make([]string, 0, len(report.Days))
+	dates := make([]string, 0, len(report.Days))
+	counts := make([]int64, 0, len(report.Days))
+	for _, day := range report.Days {
+		labels = append(labels, day.Day.Format("2"))
+		dates = append(dates, day.Day.Format("Mon Jan 2"))
+		counts = append(counts, int64(day.NewAccounts))
+	}
+
+	png, err := utils.BarChartPNG(labels, counts)
+	if err != nil {
+		log.Warn("can't draw the chart of the weekly report", "err", err)
+
+		return nil
+	}
+
+	return []command.Attachment{
+		{
+			Name:        "weekly-report.png",
+			ContentType: "image/png",
+			Data:        png,
+			AltText:     utils.BarChartAltText("the new accounts of the days", dates, counts),
+		},
+	}
+}
+
+func (r *Reporter) announce(channels []notify.Channel, week string, report *Report) {
+	for _, channel := range channels {
+		if !r.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		key := "report:" + week + ":" + channel.String()
+		claimed, err := r.store.ClaimAnnouncement(key)
+		if err != nil {
+			log.Error("can't claim the weekly report", "err", err, "channel", channel)
+
+			continue
+		}
+
+		if !claimed {
+			// another instance posted it.
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, templateName, report)
+		if err != nil {
+			log.Error("can't render the weekly report", "err", err, "template", templateName)
+
+			continue
+		}
+
+		var attachments []command.Attachment
+		if channel.AppID.Supports(command.CapabilityImage) {
+			attachments = chart(report)
+			msg = command.WithAltText(msg, attachments)
+		}
+
+		if err := r.hub.AnnounceAttachments(channel, msg, attachments); err != nil {
+			log.Warn("can't post the weekly report", "err", err, "channel", channel)
+		}
+	}
+}
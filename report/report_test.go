@@ -0,0 +1,162 @@
+package report
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type memoryStore struct {
+	snapshots []*database.NetworkSnapshot // In ascending order of the days.
+	stats     *database.BlockStats
+	transfer  *database.AccountTransaction
+	samples   []*database.PriceSample
+	claimed   map[string]bool
+}
+
+func (s *memoryStore) GetNetworkSnapshot(at time.Time) (*database.NetworkSnapshot, error) {
+	var last *database.NetworkSnapshot
+	for _, snapshot := range s.snapshots {
+		if snapshot.Day.After(at) {
+			break
+		}
+		last = snapshot
+	}
+
+	return last, nil
+}
+
+func (s *memoryStore) GetBlockStats(_ time.Time) (*database.BlockStats, error) {
+	return s.stats, nil
+}
+
+func (s *memoryStore) GetBiggestTransfer(_ time.Time) (*database.AccountTransaction, error) {
+	return s.transfer, nil
+}
+
+func (s *memoryStore) GetPriceSamples(_ time.Time) ([]*database.PriceSample, error) {
+	return s.samples, nil
+}
+
+func (s *memoryStore) ClaimAnnouncement(key string) (bool, error) {
+	if s.claimed[key] {
+		return false, nil
+	}
+	s.claimed[key] = true
+
+	return true, nil
+}
+
+// channels keeps the posted messages and the names of their attachments.
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func (c channels) AnnounceAttachments(channelID, message string, attachments []command.Attachment) error {
+	c[channelID] = append(c[channelID], message)
+	for _, att := range attachments {
+		c[channelID] = append(c[channelID], att.Name)
+	}
+
+	return nil
+}
+
+func TestReporter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).Return(
+		&pactus.GetBlockchainInfoResponse{TotalAccounts: 2_000, TotalValidators: 110}, nil).AnyTimes()
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	// a Monday.
+	today := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{
+		stats: &database.BlockStats{
+			FirstHeight: 1_001,
+			LastHeight:  61_000,
+			FirstTime:   today.AddDate(0, 0, -7),
+			LastTime:    today.AddDate(0, 0, -7).Add(59_999 * 10 * time.Second),
+		},
+		transfer: &database.AccountTransaction{TxID: "abcd", Address: "pc1z1", Counterparty: "pc1z2", Amount: 5e12},
+		samples:  []*database.PriceSample{{Price: 0.1}, {Price: 0.12}},
+		claimed:  make(map[string]bool),
+	}
+	for d := 7; d >= 1; d-- {
+		// 100 new accounts and a validator in a day.
+		store.snapshots = append(store.snapshots, &database.NetworkSnapshot{
+			Day:        today.AddDate(0, 0, -d),
+			Accounts:   2_000 - int32(d)*100,
+			Validators: 110 - int32(d),
+		})
+	}
+
+	reporter := NewReporter(cm, store, hub, []notify.Channel{{AppID: command.AppIdDiscord, ID: "community"}},
+		time.Monday, 12)
+	ctx := context.Background()
+
+	reporter.now = func() time.Time { return today.Add(11 * time.Hour) }
+	require.NoError(t, reporter.Run(ctx))
+	assert.Empty(t, discord["community"], "before the hour")
+
+	reporter.now = func() time.Time { return today.Add(13 * time.Hour) }
+	require.NoError(t, reporter.Run(ctx))
+	require.NoError(t, reporter.Run(ctx), "the report is posted once in the week")
+	require.Len(t, discord["community"], 2)
+	assert.Equal(t, "Weekly Pactus report📈\n"+
+		"May 13 - May 20, 2024\n"+
+		"\n"+
+		"New blocks: 60,000, 10.0s on average\n"+
+		"Accounts: 2,000 (+700)\n"+
+		"Validators: 110 (+7)\n"+
+		"Biggest transfer: 5,000 PAC\n"+
+		"Transaction: https://pacviewer.com/transaction/abcd\n"+
+		"PAC price: 0.1200 USDT (+20.00% in the week)\n"+
+		"\n"+
+		"Image: Bar chart of the new accounts of the days: Mon May 13: 100, Tue May 14: 100, Wed May 15: 100, "+
+		"Thu May 16: 100, Fri May 17: 100, Sat May 18: 100, Sun May 19: 100. "+
+		"The total is 700, the highest is Mon May 13 with 100.", discord["community"][0])
+	assert.Equal(t, "weekly-report.png", discord["community"][1], "the chart")
+
+	t.Run("days", func(t *testing.T) {
+		rep, err := reporter.Report(context.Background(), today.Add(13*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, rep.Days, 7)
+		assert.Equal(t, today.AddDate(0, 0, -7), rep.Days[0].Day)
+		assert.Equal(t, int32(100), rep.Days[0].NewAccounts)
+		assert.Equal(t, int32(100), rep.Days[6].NewAccounts, "to the current accounts")
+	})
+
+	t.Run("young index", func(t *testing.T) {
+		young := NewReporter(cm, &memoryStore{}, hub, nil, time.Monday, 12)
+		rep, err := young.Report(context.Background(), today)
+		require.NoError(t, err)
+		assert.False(t, rep.History)
+		assert.Zero(t, rep.Blocks)
+		assert.Nil(t, rep.Transfer)
+		assert.Zero(t, rep.Days[6].NewAccounts, "no snapshot of its start")
+		require.NoError(t, young.Run(ctx), "no channel")
+	})
+}
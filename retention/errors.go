@@ -0,0 +1,18 @@
+package retention
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pagu-project/Pagu/database"
+)
+
+// TableError is a retention of a table that is not pruned, like a typo in the config.
+type TableError struct {
+	Table string
+}
+
+func (e TableError) Error() string {
+	return fmt.Sprintf("%s has no retention, the tables are: %s", e.Table,
+		strings.Join(database.RetentionTables(), ", "))
+}
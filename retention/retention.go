@@ -0,0 +1,89 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+// Store removes the old rows of the tables.
+type Store interface {
+	PruneTable(table string, before time.Time) (int64, error)
+}
+
+// Pruner removes the rows of the tables that are older than their retention, so a long-running deployment
+// doesn't grow unbounded, like the hourly samples of the charts.
+type Pruner struct {
+	store    Store
+	interval time.Duration
+	keep     map[string]time.Duration // The age of the kept rows of each table, the other tables are kept forever.
+	now      func() time.Time
+}
+
+// NewPruner creates the pruner of the tables, the retention is in days and zero keeps the rows of the table forever.
+// It fails if a table is not one of the database.RetentionTables.
+func NewPruner(store Store, interval time.Duration, days map[string]int64) (*Pruner, error) {
+	keep := make(map[string]time.Duration, len(days))
+	for table, d := range days {
+		if !slices.Contains(database.RetentionTables(), table) {
+			return nil, TableError{Table: table}
+		}
+
+		if d > 0 {
+			keep[table] = time.Duration(d) * 24 * time.Hour
+		}
+	}
+
+	return &Pruner{
+		store:    store,
+		interval: interval,
+		keep:     keep,
+		now:      time.Now,
+	}, nil
+}
+
+// Job returns the scheduler job of the pruning, it's exclusive since the instances share the database.
+func (p *Pruner) Job() scheduler.Job {
+	interval := p.interval
+	if len(p.keep) == 0 {
+		interval = 0
+	}
+
+	return scheduler.Job{
+		Name:      "retention",
+		Interval:  interval,
+		Exclusive: true,
+		Run:       p.Run,
+	}
+}
+
+// Run removes the old rows of the tables, a failed table doesn't stop the others.
+func (p *Pruner) Run(_ context.Context) error {
+	tables := make([]string, 0, len(p.keep))
+	for table := range p.keep {
+		tables = append(tables, table)
+	}
+	slices.Sort(tables)
+
+	errs := make([]error, 0)
+	for _, table := range tables {
+		pruned, err := p.store.PruneTable(table, p.now().Add(-p.keep[table]))
+		if err != nil {
+			log.Error("can't prune the table", "err", err, "table", table)
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if pruned > 0 {
+			log.Info("the old rows are pruned", "table", table, "rows", pruned, "retention", p.keep[table])
+		}
+	}
+
+	return errors.Join(errs...)
+}
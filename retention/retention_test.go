@@ -0,0 +1,62 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	pruned map[string]time.Time
+	failed string
+}
+
+func (s *memoryStore) PruneTable(table string, before time.Time) (int64, error) {
+	if table == s.failed {
+		return 0, database.WriteError{Reason: "disk is full"}
+	}
+	s.pruned[table] = before
+
+	return 1, nil
+}
+
+func TestPruner(t *testing.T) {
+	store := &memoryStore{pruned: map[string]time.Time{}}
+
+	_, err := NewPruner(store, time.Hour, map[string]int64{"users": 30})
+	require.ErrorIs(t, err, TableError{Table: "users"})
+	assert.Contains(t, err.Error(), database.TablePriceSamples)
+
+	idle, err := NewPruner(store, time.Hour, map[string]int64{database.TableAnnouncements: 0})
+	require.NoError(t, err)
+	assert.Zero(t, idle.Job().Interval, "no table is pruned")
+
+	pruner, err := NewPruner(store, time.Hour, map[string]int64{
+		database.TableAnnouncements:       90,
+		database.TableAvailabilitySamples: 0,
+		database.TablePriceSamples:        365,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, pruner.Job().Interval)
+	assert.True(t, pruner.Job().Exclusive)
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	pruner.now = func() time.Time { return now }
+	require.NoError(t, pruner.Run(context.Background()))
+	assert.Equal(t, map[string]time.Time{
+		database.TableAnnouncements: now.AddDate(0, 0, -90),
+		database.TablePriceSamples:  now.AddDate(0, 0, -365),
+	}, store.pruned, "the availability samples are kept forever")
+
+	store.pruned = map[string]time.Time{}
+	store.failed = database.TableAnnouncements
+	err = pruner.Run(context.Background())
+	var writeErr database.WriteError
+	assert.True(t, errors.As(err, &writeErr))
+	assert.Contains(t, store.pruned, database.TablePriceSamples, "a failed table doesn't stop the others")
+}
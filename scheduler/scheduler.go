@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/lock"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// Job is a task that runs on an interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// Exclusive jobs run on one instance, the leader of the job, the others skip the runs.
+	// Jobs that notify users on a platform shouldn't be exclusive, each platform may run in its own process.
+	Exclusive bool
+	// RunsPaused jobs run while the scheduler is paused, like the reminders of the maintenance.
+	RunsPaused bool
+	Run        func(ctx context.Context) error
+}
+
+// Scheduler runs the jobs of the bot, like the daily digests.
+type Scheduler struct {
+	lock   sync.Mutex
+	locker *lock.Locker
+	jobs   []Job
+	paused func() bool
+}
+
+func NewScheduler(locker *lock.Locker) *Scheduler {
+	return &Scheduler{
+		locker: locker,
+		jobs:   make([]Job, 0),
+	}
+}
+
+// Add adds the job, the jobs added after the start don't run.
+func (s *Scheduler) Add(job Job) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.jobs = append(s.jobs, job)
+}
+
+// SetPause sets the check of the pause, the jobs skip their runs while it's true, like in a maintenance window.
+func (s *Scheduler) SetPause(paused func() bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.paused = paused
+}
+
+// Start runs each job on its interval until the context is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			continue
+		}
+
+		go func(job Job) {
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-ticker.C:
+					s.run(ctx, job)
+				}
+			}
+		}(job)
+	}
+}
+
+// run runs the job once, the exclusive jobs run only on the leader of the job.
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	if !job.RunsPaused && s.isPaused() {
+		log.Debug("job is paused", "job", job.Name)
+
+		return
+	}
+
+	if job.Exclusive && !s.isLeader(job) {
+		log.Debug("job is running on another instance", "job", job.Name)
+
+		return
+	}
+
+	if err := job.Run(ctx); err != nil {
+		log.Error("scheduled job failed", "job", job.Name, "err", err)
+	}
+}
+
+// isLeader checks if this instance runs the exclusive job.
+// The leader renews the lock on each run and never releases it, the lock lasts longer than the interval,
+// so another instance takes over only if the leader stops.
+func (s *Scheduler) isLeader(job Job) bool {
+	if s.locker == nil {
+		return true
+	}
+
+	ok, err := s.locker.TryLock("job:"+job.Name, job.Interval+job.Interval/2)
+	if err != nil {
+		log.Error("unable to lock the scheduled job", "job", job.Name, "err", err)
+
+		return false
+	}
+
+	return ok
+}
+
+func (s *Scheduler) isPaused() bool {
+	s.lock.Lock()
+	paused := s.paused
+	s.lock.Unlock()
+
+	return paused != nil && paused()
+}
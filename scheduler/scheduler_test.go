@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/lock"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore struct {
+	owners map[string]string
+}
+
+func (s *memoryStore) AcquireLock(name, owner string, _ time.Duration) (bool, error) {
+	if _, ok := s.owners[name]; ok {
+		return false, nil
+	}
+	s.owners[name] = owner
+
+	return true, nil
+}
+
+func (s *memoryStore) RenewLock(name, owner string, _ time.Duration) (bool, error) {
+	return s.owners[name] == owner, nil
+}
+
+func (s *memoryStore) ReleaseLock(name, owner string) error {
+	if s.owners[name] == owner {
+		delete(s.owners, name)
+	}
+
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	store := &memoryStore{owners: make(map[string]string)}
+	first := NewScheduler(lock.NewLocker(store, "pagu-1"))
+	second := NewScheduler(lock.NewLocker(store, "pagu-2"))
+
+	runs := map[string]int{}
+	job := func(exclusive bool, run func()) Job {
+		return Job{
+			Name:      "report",
+			Interval:  time.Minute,
+			Exclusive: exclusive,
+			Run: func(_ context.Context) error {
+				run()
+
+				return nil
+			},
+		}
+	}
+
+	// the second instance tries to run the job while the first one is running it.
+	first.run(context.Background(), job(true, func() {
+		runs["pagu-1"]++
+		second.run(context.Background(), job(true, func() { runs["pagu-2"]++ }))
+	}))
+	assert.Equal(t, 1, runs["pagu-1"])
+	assert.Zero(t, runs["pagu-2"], "the exclusive job runs on one instance at a time")
+
+	// each instance runs the job on its own ticker, one run per interval.
+	for range 3 {
+		second.run(context.Background(), job(true, func() { runs["pagu-2"]++ }))
+		first.run(context.Background(), job(true, func() { runs["pagu-1"]++ }))
+	}
+	assert.Equal(t, 4, runs["pagu-1"], "the leader keeps the lock after the run")
+	assert.Zero(t, runs["pagu-2"])
+
+	// the lease of the leader expired, like when it stopped.
+	delete(store.owners, "job:report")
+	second.run(context.Background(), job(true, func() { runs["pagu-2"]++ }))
+	first.run(context.Background(), job(true, func() { runs["pagu-1"]++ }))
+	assert.Equal(t, 1, runs["pagu-2"], "another instance takes over")
+	assert.Equal(t, 4, runs["pagu-1"])
+
+	first.run(context.Background(), job(false, func() {
+		runs["pagu-1"]++
+		second.run(context.Background(), job(false, func() { runs["pagu-2"]++ }))
+	}))
+	assert.Equal(t, 5, runs["pagu-1"])
+	assert.Equal(t, 2, runs["pagu-2"], "the non-exclusive job runs on both instances")
+}
+
+func TestPause(t *testing.T) {
+	s := NewScheduler(nil)
+	paused := true
+	s.SetPause(func() bool { return paused })
+
+	runs := map[string]int{}
+	job := func(name string, runsPaused bool) Job {
+		return Job{
+			Name:       name,
+			Interval:   time.Minute,
+			RunsPaused: runsPaused,
+			Run: func(_ context.Context) error {
+				runs[name]++
+
+				return nil
+			},
+		}
+	}
+
+	s.run(context.Background(), job("digest", false))
+	s.run(context.Background(), job("maintenance", true))
+	assert.Zero(t, runs["digest"], "the job is paused")
+	assert.Equal(t, 1, runs["maintenance"])
+
+	paused = false
+	s.run(context.Background(), job("digest", false))
+	assert.Equal(t, 1, runs["digest"], "the job is resumed")
+}
+
+func TestStart(t *testing.T) {
+	s := NewScheduler(nil)
+	ran := make(chan struct{}, 1)
+	s.Add(Job{
+		Name:     "tick",
+		Interval: 10 * time.Millisecond,
+		Run: func(_ context.Context) error {
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("the job didn't run")
+	}
+}
@@ -0,0 +1,83 @@
+package settings
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pagu-project/Pagu/engine/command"
+)
+
+// MaxPrefixLength is the maximum length of a command prefix in characters, like: "!pagu".
+const MaxPrefixLength = 10
+
+// PrefixPlatforms are the platforms that their adapters parse the messages with a command prefix.
+var PrefixPlatforms = []command.AppID{command.AppIdDiscord, command.AppIdTelegram}
+
+// PrefixName returns the setting name of the command prefix of the platform, like: "prefix.discord".
+// The scope is a server of the platform, like a Discord guild; empty is the prefix of the whole platform.
+func PrefixName(appID command.AppID, scope string) string {
+	name := "prefix." + strings.ToLower(appID.String())
+	if scope != "" {
+		name += "." + scope
+	}
+
+	return name
+}
+
+// ValidPrefix checks the command prefix, it's a short word without spaces, like: "!pagu" or ".p".
+func ValidPrefix(prefix string) bool {
+	if prefix == "" || utf8.RuneCountInString(prefix) > MaxPrefixLength {
+		return false
+	}
+
+	for _, r := range prefix {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Prefix returns the command prefix of the scope, or of the platform if the scope has no prefix.
+// It's empty if neither of them is set.
+func (s *Settings) Prefix(appID command.AppID, scope string) string {
+	if scope != "" {
+		if prefix, ok := s.Get(PrefixName(appID, scope)); ok {
+			return prefix
+		}
+	}
+
+	prefix, _ := s.Get(PrefixName(appID, ""))
+
+	return prefix
+}
+
+// PrefixedTokens returns the command tokens of a message that starts with the command prefix of the scope,
+// like: "!pagu network status". It returns false if no prefix is set or the message doesn't start with it.
+func (s *Settings) PrefixedTokens(appID command.AppID, scope, msg string) ([]string, bool) {
+	prefix := s.Prefix(appID, scope)
+	if prefix == "" {
+		return nil, false
+	}
+
+	rest, ok := strings.CutPrefix(strings.TrimSpace(msg), prefix)
+	if !ok {
+		return nil, false
+	}
+
+	// a prefix that ends with a word is separated by a space, "!paguxyz" doesn't start with "!pagu".
+	// The symbols are not, like: "!network status".
+	last, _ := utf8.DecodeLastRuneInString(prefix)
+	next, _ := utf8.DecodeRuneInString(rest)
+	if rest != "" && isWordRune(last) && !unicode.IsSpace(next) {
+		return nil, false
+	}
+
+	return strings.Fields(rest), true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
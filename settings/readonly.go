@@ -0,0 +1,45 @@
+package settings
+
+// ReadOnlyName is the setting of the read-only mode, it's kept in the settings table so all the instances are read-only.
+const ReadOnlyName = "readonly"
+
+// ForceReadOnly makes the instance read-only whatever the setting is, like the READ_ONLY config of an instance.
+func (s *Settings) ForceReadOnly() {
+	s.lock.Lock()
+	s.forcedReadOnly = true
+	s.lock.Unlock()
+}
+
+// ReadOnlyForced checks if the instance is read-only by its config, the admins can't turn it off.
+func (s *Settings) ReadOnlyForced() bool {
+	if s == nil {
+		return false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.forcedReadOnly
+}
+
+// ReadOnly checks if the state-changing commands are disabled, like the faucet and the payouts.
+func (s *Settings) ReadOnly() bool {
+	if s.ReadOnlyForced() {
+		return true
+	}
+
+	_, on := s.Get(ReadOnlyName)
+
+	return on
+}
+
+// SetReadOnly turns the read-only mode of all the instances on or off.
+func (s *Settings) SetReadOnly(on bool, updatedBy string) error {
+	if on {
+		return s.Set(ReadOnlyName, "on", updatedBy)
+	}
+
+	_, err := s.Reset(ReadOnlyName)
+
+	return err
+}
@@ -0,0 +1,97 @@
+package settings
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+)
+
+// serverPrefix is the prefix of the server settings, like: "server.discord.1234.groups".
+const serverPrefix = "server."
+
+// The settings of a server that the admins choose in the setup, the prefix of a server is its prefix setting.
+const (
+	ServerGroups  = "groups"  // The comma separated command groups that run in the server, all if it's not set.
+	ServerChannel = "channel" // The channel of the announcements in the server, like the releases.
+	ServerLocale  = "locale"  // The language of the announcements in the server.
+	ServerLimits  = "limits"  // The rate-limit preset of the server.
+)
+
+// The rate-limit presets of the servers, they scale the daily quota of the heavy commands of the server.
+const (
+	LimitsStrict   = "strict"
+	LimitsStandard = "standard"
+	LimitsRelaxed  = "relaxed"
+)
+
+// LimitPresets are the rate-limit presets and their factor of the daily quota.
+var LimitPresets = map[string]float64{
+	LimitsStrict:   0.5,
+	LimitsStandard: 1,
+	LimitsRelaxed:  2,
+}
+
+// ServerName returns the setting name of a server of the platform, like: "server.telegram.-100123.locale".
+func ServerName(appID command.AppID, scope, key string) string {
+	return serverPrefix + strings.ToLower(appID.String()) + "." + scope + "." + key
+}
+
+// ServerGroupsOf returns the command groups that are enabled in the server, false if all of them are.
+func (s *Settings) ServerGroupsOf(appID command.AppID, scope string) ([]string, bool) {
+	value, ok := s.Get(ServerName(appID, scope, ServerGroups))
+	if !ok {
+		return nil, false
+	}
+
+	return ParseTopicGroups(value), true
+}
+
+// ServerAllows checks if the command group runs in the server, the servers without the setup run all the groups.
+func (s *Settings) ServerAllows(appID command.AppID, scope, group string) bool {
+	groups, ok := s.ServerGroupsOf(appID, scope)
+
+	return !ok || slices.Contains(groups, group)
+}
+
+// ServerQuotaFactor returns the factor of the daily quota of the server by its rate-limit preset, one if it has none.
+func (s *Settings) ServerQuotaFactor(appID command.AppID, scope string) float64 {
+	preset, _ := s.Get(ServerName(appID, scope, ServerLimits))
+	if factor, ok := LimitPresets[preset]; ok {
+		return factor
+	}
+
+	return 1
+}
+
+// ServerChannels returns the announcement channels of the servers with their language, sorted by the settings.
+func (s *Settings) ServerChannels() []notify.Channel {
+	values := s.list(serverPrefix)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if strings.HasSuffix(name, "."+ServerChannel) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	channels := make([]notify.Channel, 0, len(names))
+	for _, name := range names {
+		// the scope is between the platform and the key, like: "server.discord.1234.channel".
+		platform, rest, _ := strings.Cut(strings.TrimPrefix(name, serverPrefix), ".")
+		scope := strings.TrimSuffix(rest, "."+ServerChannel)
+		appID, ok := command.ParseAppID(platform)
+		if !ok || scope == "" {
+			continue
+		}
+
+		channels = append(channels, notify.Channel{
+			AppID:  appID,
+			ID:     values[name],
+			Locale: values[ServerName(appID, scope, ServerLocale)],
+		})
+	}
+
+	return channels
+}
@@ -0,0 +1,109 @@
+package settings
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/log"
+)
+
+// refreshInterval is how often the settings are loaded from the store,
+// so the settings that are changed on another instance are applied.
+const refreshInterval = 30 * time.Second
+
+// Store keeps the settings where all the Pagu instances can see them, like the database.
+type Store interface {
+	SetSetting(s *database.Setting) error
+	GetSettings() ([]*database.Setting, error)
+	DeleteSetting(name string) (bool, error)
+}
+
+// Settings are the runtime settings of Pagu that the admins change with the commands, like the command prefixes.
+type Settings struct {
+	lock           sync.Mutex
+	store          Store
+	now            func() time.Time
+	values         map[string]string
+	loadedAt       time.Time
+	forcedReadOnly bool
+}
+
+func NewSettings(store Store) *Settings {
+	return &Settings{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Get returns the value of the setting, false if it's not set.
+func (s *Settings) Get(name string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	value, ok := s.load()[name]
+
+	return value, ok
+}
+
+func (s *Settings) Set(name, value, updatedBy string) error {
+	if err := s.store.SetSetting(&database.Setting{
+		Name:      name,
+		Value:     value,
+		UpdatedBy: updatedBy,
+	}); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	if s.values == nil {
+		s.values = make(map[string]string)
+	}
+	s.values[name] = value
+	s.lock.Unlock()
+
+	return nil
+}
+
+// Reset removes the setting so it has its default value, it returns false if the setting is not set.
+func (s *Settings) Reset(name string) (bool, error) {
+	removed, err := s.store.DeleteSetting(name)
+	if err != nil {
+		return false, err
+	}
+
+	s.lock.Lock()
+	delete(s.values, name)
+	s.lock.Unlock()
+
+	return removed, nil
+}
+
+// load returns the settings, they are loaded from the store if the last load is older than the refresh interval.
+// The last loaded settings are kept if the store fails, so a database error doesn't change them.
+// The lock must be held while the map is read, because the settings are changed in place.
+func (s *Settings) load() map[string]string {
+	if s.values != nil && s.now().Sub(s.loadedAt) < refreshInterval {
+		return s.values
+	}
+
+	list, err := s.store.GetSettings()
+	if err != nil {
+		log.Warn("can't load the settings", "err", err)
+
+		return s.values
+	}
+
+	values := make(map[string]string, len(list))
+	for _, setting := range list {
+		values[setting.Name] = setting.Value
+	}
+	s.values = values
+	s.loadedAt = s.now()
+
+	return s.values
+}
@@ -0,0 +1,208 @@
+package settings
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	lock     sync.Mutex
+	settings map[string]*database.Setting
+	err      error
+}
+
+func (s *memoryStore) SetSetting(setting *database.Setting) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.settings[setting.Name] = setting
+
+	return nil
+}
+
+func (s *memoryStore) GetSettings() ([]*database.Setting, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	settings := make([]*database.Setting, 0, len(s.settings))
+	for _, setting := range s.settings {
+		settings = append(settings, setting)
+	}
+
+	return settings, nil
+}
+
+func (s *memoryStore) DeleteSetting(name string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, ok := s.settings[name]
+	delete(s.settings, name)
+
+	return ok, nil
+}
+
+func TestSettings(t *testing.T) {
+	store := &memoryStore{settings: map[string]*database.Setting{}}
+	s := NewSettings(store)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	_, ok := s.Get("prefix.discord")
+	assert.False(t, ok)
+
+	require.NoError(t, s.Set("prefix.discord", "!pagu", "admin-id"))
+	value, ok := s.Get("prefix.discord")
+	assert.True(t, ok)
+	assert.Equal(t, "!pagu", value)
+
+	// another instance changes the setting, it's applied after the refresh.
+	store.settings["prefix.discord"] = &database.Setting{Name: "prefix.discord", Value: ".p"}
+	value, _ = s.Get("prefix.discord")
+	assert.Equal(t, "!pagu", value)
+
+	now = now.Add(refreshInterval)
+	value, _ = s.Get("prefix.discord")
+	assert.Equal(t, ".p", value)
+
+	store.err = errors.New("database is locked")
+	now = now.Add(refreshInterval)
+	value, _ = s.Get("prefix.discord")
+	assert.Equal(t, ".p", value, "the last loaded settings are kept")
+	store.err = nil
+
+	removed, err := s.Reset("prefix.discord")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	_, ok = s.Get("prefix.discord")
+	assert.False(t, ok)
+
+	var nilSettings *Settings
+	_, ok = nilSettings.Get("prefix.discord")
+	assert.False(t, ok)
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	settings := NewSettings(&memoryStore{settings: map[string]*database.Setting{}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, settings.Set(PrefixName(command.AppIdDiscord, ""), "!", "admin"))
+			_, err := settings.Reset(PrefixName(command.AppIdDiscord, ""))
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+
+			settings.Get(PrefixName(command.AppIdDiscord, ""))
+			settings.GroupTopics("-100", "network")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPrefixedTokens(t *testing.T) {
+	s := NewSettings(&memoryStore{settings: map[string]*database.Setting{}})
+
+	_, ok := s.PrefixedTokens(command.AppIdDiscord, "1234", "!pagu network status")
+	assert.False(t, ok, "no prefix is set")
+
+	require.NoError(t, s.Set(PrefixName(command.AppIdDiscord, ""), "!pagu", "admin-id"))
+	require.NoError(t, s.Set(PrefixName(command.AppIdDiscord, "1234"), ".p", "admin-id"))
+	require.NoError(t, s.Set(PrefixName(command.AppIdTelegram, ""), "!", "admin-id"))
+	assert.Equal(t, "prefix.discord.1234", PrefixName(command.AppIdDiscord, "1234"))
+
+	tokens, ok := s.PrefixedTokens(command.AppIdDiscord, "5678", " !pagu network  status")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"network", "status"}, tokens)
+
+	_, ok = s.PrefixedTokens(command.AppIdDiscord, "5678", "!paguxyz network status")
+	assert.False(t, ok)
+
+	_, ok = s.PrefixedTokens(command.AppIdDiscord, "1234", "!pagu network status")
+	assert.False(t, ok, "the guild has its own prefix")
+
+	tokens, ok = s.PrefixedTokens(command.AppIdDiscord, "1234", ".p")
+	assert.True(t, ok)
+	assert.Empty(t, tokens)
+
+	tokens, ok = s.PrefixedTokens(command.AppIdTelegram, "", "!network status")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"network", "status"}, tokens)
+
+	assert.True(t, ValidPrefix("!pagu"))
+	assert.False(t, ValidPrefix(""))
+	assert.False(t, ValidPrefix("! pagu"))
+	assert.False(t, ValidPrefix("!averylongprefix"))
+}
+
+func FuzzPrefixedTokens(f *testing.F) {
+	f.Add("!pagu", " !pagu network  status")
+	f.Add("!", "!network status")
+	f.Add("!pagu", "!paguxyz network status")
+	f.Add("ä", "ä\u00a0network")
+
+	f.Fuzz(func(t *testing.T, prefix, msg string) {
+		if !ValidPrefix(prefix) {
+			return
+		}
+
+		s := NewSettings(&memoryStore{settings: map[string]*database.Setting{}})
+		require.NoError(t, s.Set(PrefixName(command.AppIdDiscord, ""), prefix, "admin-id"))
+
+		tokens, ok := s.PrefixedTokens(command.AppIdDiscord, "", msg)
+		if !ok {
+			return
+		}
+
+		if !strings.HasPrefix(strings.TrimSpace(msg), prefix) {
+			t.Fatalf("%q is parsed without the prefix %q", msg, prefix)
+		}
+
+		for _, token := range tokens {
+			if token == "" || strings.ContainsFunc(token, unicode.IsSpace) {
+				t.Fatalf("the token %q of %q is empty or has a space", token, msg)
+			}
+		}
+	})
+}
+
+func TestTopics(t *testing.T) {
+	s := NewSettings(&memoryStore{settings: map[string]*database.Setting{}})
+
+	assert.True(t, s.TopicAllows("-100123", "42", "phoenix"), "no topic is bound")
+
+	require.NoError(t, s.Set(TopicName("-100123", "42"), "Phoenix, zealy", "admin-id"))
+	require.NoError(t, s.Set(TopicName("-100123", GeneralTopic), "network", "admin-id"))
+	assert.Equal(t, "topic.-100123.42", TopicName("-100123", "42"))
+
+	groups, ok := s.TopicGroups("-100123", "42")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"phoenix", "zealy"}, groups)
+
+	assert.True(t, s.TopicAllows("-100123", "42", "phoenix"))
+	assert.False(t, s.TopicAllows("-100123", "42", "network"), "the topic runs only its groups")
+	assert.False(t, s.TopicAllows("-100123", "7", "phoenix"), "the group runs only in its topics")
+	assert.True(t, s.TopicAllows("-100123", "7", "blockchain"))
+	assert.True(t, s.TopicAllows("-1001234", "7", "phoenix"), "another group is not bound")
+	assert.Equal(t, []string{"42"}, s.GroupTopics("-100123", "phoenix"))
+
+	assert.Equal(t, []string{"a", "b"}, ParseTopicGroups(" a,,B, a "))
+}
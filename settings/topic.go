@@ -0,0 +1,86 @@
+package settings
+
+import (
+	"slices"
+	"strings"
+)
+
+// topicPrefix is the prefix of the topic settings, like: "topic.-100123.42" is the topic 42 of the group -100123.
+const topicPrefix = "topic."
+
+// GeneralTopic is the ID of the General topic of the forum groups, its messages have no topic ID.
+const GeneralTopic = "general"
+
+// TopicName returns the setting name of the command groups of a topic in a Telegram forum group,
+// like: "topic.-100123.42".
+func TopicName(chatID, topicID string) string {
+	return topicPrefix + chatID + "." + topicID
+}
+
+// ParseTopicGroups parses the comma separated command groups of a topic, like: "phoenix,network".
+func ParseTopicGroups(value string) []string {
+	groups := make([]string, 0)
+	for _, group := range strings.Split(value, ",") {
+		if group = strings.ToLower(strings.TrimSpace(group)); group != "" && !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// TopicGroups returns the command groups that are bound to the topic, false if the topic is not bound.
+func (s *Settings) TopicGroups(chatID, topicID string) ([]string, bool) {
+	value, ok := s.Get(TopicName(chatID, topicID))
+	if !ok {
+		return nil, false
+	}
+
+	return ParseTopicGroups(value), true
+}
+
+// GroupTopics returns the topics of the group chat that the command group is bound to, sorted.
+func (s *Settings) GroupTopics(chatID, group string) []string {
+	prefix := TopicName(chatID, "")
+	topics := make([]string, 0)
+	for name, value := range s.list(prefix) {
+		if slices.Contains(ParseTopicGroups(value), group) {
+			topics = append(topics, strings.TrimPrefix(name, prefix))
+		}
+	}
+	slices.Sort(topics)
+
+	return topics
+}
+
+// TopicAllows checks if the command group can run in the topic. A bound topic runs only its groups,
+// and a bound group runs only in its topics, like the faucet in the "Testnet" topic.
+// The groups that are not bound run in the topics that are not bound.
+func (s *Settings) TopicAllows(chatID, topicID, group string) bool {
+	if groups, ok := s.TopicGroups(chatID, topicID); ok {
+		return slices.Contains(groups, group)
+	}
+
+	return len(s.GroupTopics(chatID, group)) == 0
+}
+
+// list returns the settings that their names start with the prefix.
+func (s *Settings) list(prefix string) map[string]string {
+	if s == nil {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	values := s.load()
+
+	listed := make(map[string]string)
+	for name, value := range values {
+		if strings.HasPrefix(name, prefix) {
+			listed[name] = value
+		}
+	}
+
+	return listed
+}
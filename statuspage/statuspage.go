@@ -0,0 +1,151 @@
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const (
+	PageName = "status.html"
+	DataName = "status.json"
+
+	// callerID is the caller of the commands in the engine, like the users of the platforms.
+	callerID = "status-page"
+)
+
+// DefaultCommands are the outputs on the page: the network status, its health and the trend of the validators.
+var DefaultCommands = []string{"network status", "network health", "network growth"}
+
+//go:embed status.html.tmpl
+var pageTemplate string
+
+var page = template.Must(template.New(PageName).Funcs(template.FuncMap{
+	// the message is escaped and linked to the explorer, the lines are kept.
+	"message": func(msg string) template.HTML {
+		linked := command.LinkifyFormat(command.FormatHTML, msg)
+
+		return template.HTML(strings.ReplaceAll(linked, "\n", "<br>\n")) //nolint:gosec // escaped by the link markup.
+	},
+}).Parse(pageTemplate))
+
+// Engine runs the commands, like the bot engine.
+type Engine interface {
+	Run(appID command.AppID, callerID string, tokens []string) command.CommandResult
+}
+
+// Writer stores the generated files, like a directory or an S3 bucket.
+type Writer interface {
+	Write(ctx context.Context, name, contentType string, data []byte) error
+}
+
+// Section is the output of a command on the page.
+type Section struct {
+	Command    string `json:"command"`
+	Title      string `json:"title"`
+	Successful bool   `json:"successful"`
+	Message    string `json:"message"`
+}
+
+// Status is the content of the page, the JSON file has the same content.
+type Status struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Sections    []Section `json:"sections"`
+}
+
+// Generator renders the outputs of the commands to a static HTML page and a JSON file,
+// so a community can host a public status page.
+type Generator struct {
+	engine   Engine
+	writers  []Writer
+	commands []string
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewGenerator creates the generator of the commands, zero interval or no writers disables it.
+func NewGenerator(be Engine, writers []Writer, commands []string, interval time.Duration) *Generator {
+	if len(commands) == 0 {
+		commands = DefaultCommands
+	}
+
+	return &Generator{
+		engine:   be,
+		writers:  writers,
+		commands: commands,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Job returns the scheduler job of the page. It's exclusive, one of the instances writes the page.
+func (g *Generator) Job() scheduler.Job {
+	interval := g.interval
+	if len(g.writers) == 0 {
+		interval = 0
+	}
+
+	return scheduler.Job{
+		Name:      "status-page",
+		Interval:  interval,
+		Exclusive: true,
+		Run:       g.Run,
+	}
+}
+
+// Run runs the commands and writes the page and the JSON file with all the writers.
+func (g *Generator) Run(ctx context.Context) error {
+	status := g.Status()
+
+	var html bytes.Buffer
+	if err := page.Execute(&html, status); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	for _, w := range g.writers {
+		if err := w.Write(ctx, PageName, "text/html; charset=utf-8", html.Bytes()); err != nil {
+			return err
+		}
+
+		if err := w.Write(ctx, DataName, "application/json", data); err != nil {
+			return err
+		}
+	}
+
+	log.Debug("status page generated", "sections", len(status.Sections))
+
+	return nil
+}
+
+// Status runs the commands, a failed command is on the page with its message.
+func (g *Generator) Status() Status {
+	status := Status{
+		GeneratedAt: g.now().UTC(),
+		Sections:    make([]Section, 0, len(g.commands)),
+	}
+
+	for _, cmd := range g.commands {
+		res := g.engine.Run(command.AppIdHTTP, callerID, strings.Fields(cmd))
+		status.Sections = append(status.Sections, Section{
+			Command:    cmd,
+			Title:      res.Title,
+			Successful: res.Successful,
+			Message:    res.Message,
+		})
+	}
+
+	return status
+}
@@ -0,0 +1,57 @@
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEngine struct{}
+
+func (testEngine) Run(appID command.AppID, callerID string, tokens []string) command.CommandResult {
+	return command.CommandResult{
+		Title:      strings.Join(tokens, " "),
+		Message:    appID.String() + " " + callerID + "\nLast Block Height: 1,234 <ok>",
+		Successful: tokens[0] != "unknown",
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "www")
+	gen := NewGenerator(testEngine{}, []Writer{NewDir(dir)}, []string{"network  health", "unknown"}, time.Minute)
+	gen.now = func() time.Time { return time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC) }
+
+	require.NoError(t, gen.Run(context.Background()))
+
+	html, err := os.ReadFile(filepath.Join(dir, PageName))
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "<h2>network health</h2>")
+	assert.Contains(t, string(html), `HTTP status-page<br>`+"\n"+
+		`Last Block Height: <a href="https://pacviewer.com/block/1234">1,234</a> &lt;ok&gt;`)
+	assert.Contains(t, string(html), `<section class="failed">`)
+	assert.Contains(t, string(html), "Generated by Pagu at 01/05/2024, 12:30:00 UTC")
+
+	data, err := os.ReadFile(filepath.Join(dir, DataName))
+	require.NoError(t, err)
+
+	var status Status
+	require.NoError(t, json.Unmarshal(data, &status))
+	require.Len(t, status.Sections, 2)
+	assert.Equal(t, "network  health", status.Sections[0].Command)
+	assert.True(t, status.Sections[0].Successful)
+	assert.False(t, status.Sections[1].Successful)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary files are left")
+
+	assert.Zero(t, NewGenerator(testEngine{}, nil, nil, time.Minute).Job().Interval, "no writers")
+}
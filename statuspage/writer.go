@@ -0,0 +1,105 @@
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pagu-project/Pagu/config"
+)
+
+// Dir writes the files to a directory, like the root of a web server.
+type Dir struct {
+	path string
+}
+
+func NewDir(path string) Dir {
+	return Dir{
+		path: path,
+	}
+}
+
+// Write replaces the file at once, so the web server doesn't serve a half written file.
+func (d Dir) Write(_ context.Context, name, _ string, data []byte) error {
+	if err := os.MkdirAll(d.path, 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(d.path, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// the temporary files are private, the page is public.
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil { //nolint:gosec // the page is public.
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(d.path, name))
+}
+
+// S3 writes the files to a bucket of S3, or of an S3 compatible storage.
+type S3 struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func NewS3(cfg config.StatusPage) (*S3, error) {
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3{
+		client: client,
+		bucket: cfg.S3Bucket,
+		prefix: cfg.S3Prefix,
+	}, nil
+}
+
+// NewWriters returns the writers of the config, the directory and the bucket are both written if they are set.
+func NewWriters(cfg config.StatusPage) ([]Writer, error) {
+	writers := make([]Writer, 0, 2)
+	if cfg.Path != "" {
+		writers = append(writers, NewDir(cfg.Path))
+	}
+
+	if cfg.S3Bucket != "" {
+		s3, err := NewS3(cfg)
+		if err != nil {
+			return nil, err
+		}
+		writers = append(writers, s3)
+	}
+
+	return writers, nil
+}
+
+func (s *S3) Write(ctx context.Context, name, contentType string, data []byte) error {
+	_, err := s.client.PutObject(ctx, s.bucket, path.Join(s.prefix, name), bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{
+			ContentType:  contentType,
+			CacheControl: "max-age=60",
+		})
+
+	return err
+}
@@ -17,7 +17,11 @@ type CommandHandler struct {
 }
 
 func (bot *TelegramBot) CheckUpdate(b *gotgbot.Bot, ctx *ext.Context) bool {
-	return ctx.Update.Message.Chat.Type == "private"
+	if ctx.Update.Message == nil {
+		return false
+	}
+
+	return handledChat(ctx.Update.Message.Chat)
 }
 
 func (bot *TelegramBot) Name() string {
@@ -0,0 +1,26 @@
+package telegram
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// outboxQueue is the number of the messages of each priority that wait to be sent.
+const outboxQueue = 100
+
+// retryAfter returns the time that Telegram asks to send a rate-limited message again in, the retry_after of a 429.
+func retryAfter(err error) (time.Duration, bool) {
+	var telegramErr *gotgbot.TelegramError
+	if !errors.As(err, &telegramErr) || telegramErr.Code != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if telegramErr.ResponseParams == nil || telegramErr.ResponseParams.RetryAfter <= 0 {
+		return time.Second, true
+	}
+
+	return time.Duration(telegramErr.ResponseParams.RetryAfter) * time.Second, true
+}
@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
 	"strconv"
 	"strings"
@@ -11,6 +12,8 @@ import (
 	"github.com/pagu-project/Pagu/engine"
 	"github.com/pagu-project/Pagu/engine/command"
 	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/outbox"
+	"github.com/pagu-project/Pagu/settings"
 )
 
 type TelegramBot struct {
@@ -22,6 +25,7 @@ type TelegramBot struct {
 	ctx             context.Context
 	cancel          context.CancelFunc
 	updater         *ext.Updater
+	outbox          *outbox.Outbox // Paces the messages, the replies to the commands are sent before the announcements.
 }
 
 func NewTelegramBot(botEngine *engine.BotEngine, token string, chatID int64, config *config.Config) (*TelegramBot, error) {
@@ -43,6 +47,7 @@ func NewTelegramBot(botEngine *engine.BotEngine, token string, chatID int64, con
 		commandHandlers: commandHandlers,
 		ctx:             ctx,
 		cancel:          cancel,
+		outbox:          outbox.NewOutbox(config.Telegram.SendRate, outboxQueue, retryAfter),
 	}, nil
 }
 
@@ -65,6 +70,8 @@ func (bot *TelegramBot) Start() error {
 
 	updater := ext.NewUpdater(dispatcher, nil)
 
+	bot.outbox.Start()
+
 	bot.updater = updater
 
 	go func() {
@@ -75,54 +82,272 @@ func (bot *TelegramBot) Start() error {
 		}
 	}()
 
+	bot.botEngine.SetNotifier(command.AppIdTelegram, bot)
+	bot.botEngine.SetServerAdmins(command.AppIdTelegram, bot)
+
 	log.Info("Telegram Bot started successfully")
 
 	return nil
 }
 
-func (bot *TelegramBot) HandleUpdate(b *gotgbot.Bot, ctx *ext.Context) error {
-	// Check if the message is from a private chat (DM)
-	if ctx.Update.Message.Chat.Type != "private" {
-		return nil // Ignore non-private messages.
+// Notify sends a private message to the user, the user should have started a chat with the bot.
+func (bot *TelegramBot) Notify(userID, message string) error {
+	chatID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return err
 	}
 
-	// Handle the update.
-	if ctx.Update.Message != nil {
-		// Extract the entire message, including commands.
-		fullMessage := ctx.Update.Message.Text
+	return bot.outbox.Send(outbox.PriorityAnnouncement, func() error {
+		_, err := bot.botInstance.SendMessage(chatID, message, nil)
 
-		// Remove the slash prefix.
-		fullMessage = strings.TrimPrefix(fullMessage, "/")
+		return err
+	})
+}
 
-		// Split the message into an array.
-		messageParts := strings.Split(fullMessage, " ")
+// Announce posts the message to the chat, like a group or a channel that the bot is a member of.
+func (bot *TelegramBot) Announce(chatID, message string) error {
+	return bot.Notify(chatID, message)
+}
 
-		// Pass the array to the bot engine.
-		res := bot.botEngine.Run(command.AppIdTelegram, strconv.FormatInt(ctx.EffectiveSender.User.Id, 10), messageParts)
+// AnnounceAttachments posts the message to the chat, then its attachments, like a report and its chart.
+func (bot *TelegramBot) AnnounceAttachments(chatID, message string, attachments []command.Attachment) error {
+	if err := bot.Notify(chatID, message); err != nil {
+		return err
+	}
 
-		// Check if the command execution resulted in an error.
-		if res.Error != "" {
-			log.Error("Failed to execute command:", res.Error)
+	id, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil {
+		return err
+	}
+	bot.sendAttachments(bot.botInstance, outbox.PriorityAnnouncement, id, 0, attachments)
 
-			_, err := b.SendMessage(ctx.EffectiveChat.Id, "An error occurred while processing your request.", nil)
-			if err != nil {
-				log.Error("Failed to send error response:", err)
-			}
+	return nil
+}
+
+// IsServerAdmin returns true if the user is the creator or an admin of the group.
+func (bot *TelegramBot) IsServerAdmin(guildID, userID string) bool {
+	chatID, err := strconv.ParseInt(guildID, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	memberID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	member, err := bot.botInstance.GetChatMember(chatID, memberID, nil)
+	if err != nil {
+		log.Warn("can't get the member of the group", "err", err, "chat", guildID)
+
+		return false
+	}
+
+	switch member.(type) {
+	case gotgbot.ChatMemberOwner, gotgbot.ChatMemberAdministrator:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasChannel returns true if the chat is the group, the announcements are posted to the chat of the group.
+func (*TelegramBot) HasChannel(guildID, channelID string) bool {
+	groupID, err := strconv.ParseInt(guildID, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	chatID, err := strconv.ParseInt(channelID, 10, 64)
+
+	return err == nil && chatID == groupID
+}
+
+// uploadArgs returns the arguments of an uploaded text file, the other files are ignored.
+func uploadArgs(ctx context.Context, b *gotgbot.Bot, doc *gotgbot.Document) []string {
+	if !command.IsTextUpload(doc.MimeType, doc.FileSize) {
+		return nil
+	}
+
+	file, err := b.GetFile(doc.FileId, nil)
+	if err != nil {
+		log.Warn("can't get the uploaded file", "err", err)
+
+		return nil
+	}
+
+	args, err := command.DownloadUploadArgs(ctx, file.URL(b, nil))
+	if err != nil {
+		log.Warn("can't download the uploaded file", "err", err, "name", doc.FileName)
+	}
+
+	return args
+}
+
+func (bot *TelegramBot) HandleUpdate(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.Update.Message
+	if msg == nil {
+		return nil
+	}
+
+	// The private chats and the groups are handled, the other chats are ignored.
+	if !handledChat(msg.Chat) {
+		return nil
+	}
+	topicID, inTopic := forumTopic(msg)
+
+	// Extract the entire message, including commands. The command of an uploaded file is its caption.
+	fullMessage := msg.Text
+	if msg.Document != nil {
+		fullMessage = msg.Caption
+	}
+
+	// The command prefix of the chat is removed, like: "!pagu network status",
+	// the other messages are commands with or without a slash, or free-text questions.
+	messageParts, ok := bot.botEngine.PrefixedTokens(command.AppIdTelegram,
+		strconv.FormatInt(ctx.EffectiveChat.Id, 10), fullMessage)
+	if !ok {
+		// the members talk in the groups, only the commands are answered there.
+		if msg.Chat.Type != gotgbot.ChatTypePrivate && !strings.HasPrefix(fullMessage, "/") {
 			return nil
 		}
 
-		// Send the response back to the user.
-		_, err := b.SendMessage(ctx.EffectiveChat.Id, res.Message, nil)
+		messageParts = strings.Split(strings.TrimPrefix(fullMessage, "/"), " ")
+		// the commands of the groups can mention the bot, like: "/network@PaguBot status".
+		messageParts[0] = strings.TrimSuffix(messageParts[0], "@"+b.Username)
+	}
+
+	// the anonymous admins and the channels have no user to check the limits of.
+	if ctx.EffectiveSender == nil || ctx.EffectiveSender.User == nil {
+		return nil
+	}
+	callerID := strconv.FormatInt(ctx.EffectiveSender.User.Id, 10)
+
+	// the messages to Pagu are counted in the flood protection, the muted users are ignored.
+	if !bot.botEngine.AllowMessage(command.AppIdTelegram, callerID) {
+		return nil
+	}
+
+	// the arguments can be uploaded in a text file, like the validators of "validator bulk".
+	if msg.Document != nil && bot.botEngine.AcceptsUpload(messageParts) {
+		messageParts = append(messageParts, uploadArgs(bot.ctx, b, msg.Document)...)
+	}
+
+	// Pass the array to the bot engine, the commands of a group are routed by the group and its topic.
+	chatID := strconv.FormatInt(ctx.EffectiveChat.Id, 10)
+	var res command.CommandResult
+	switch {
+	case inTopic:
+		res = bot.botEngine.RunInTopic(command.AppIdTelegram, chatID, topicID, callerID, messageParts)
+	case msg.Chat.Type == gotgbot.ChatTypePrivate:
+		res = bot.botEngine.Run(command.AppIdTelegram, callerID, messageParts)
+	default:
+		res = bot.botEngine.RunInGuild(command.AppIdTelegram, chatID, callerID, messageParts)
+	}
+
+	// Check if the command execution resulted in an error.
+	if res.Error != "" {
+		log.Error("Failed to execute command:", res.Error)
+
+		err := bot.outbox.Send(outbox.PriorityReply, func() error {
+			_, err := b.SendMessage(ctx.EffectiveChat.Id, "An error occurred while processing your request.",
+				&gotgbot.SendMessageOpts{MessageThreadId: msg.MessageThreadId})
+
+			return err
+		})
 		if err != nil {
-			log.Error("Failed to send response:", err)
+			log.Error("Failed to send error response:", err)
 		}
-
 		return nil
 	}
 
+	// Send the response back to the user, as a reply to keep the follow-ups in the same thread.
+	// The response is in HTML, to link the addresses and the transactions to the explorer.
+	// A long response is summarized, and the full response is sent as a file.
+	res, reply := renderReply(res, bot.config.Telegram.MessageLimit)
+	err := bot.outbox.Send(outbox.PriorityReply, func() error {
+		_, err := b.SendMessage(ctx.EffectiveChat.Id, reply, &gotgbot.SendMessageOpts{
+			ParseMode:       gotgbot.ParseModeHTML,
+			MessageThreadId: msg.MessageThreadId,
+			ReplyParameters: &gotgbot.ReplyParameters{
+				MessageId:                msg.MessageId,
+				AllowSendingWithoutReply: true,
+			},
+		})
+
+		return err
+	})
+	if err != nil {
+		log.Error("Failed to send response:", err)
+	}
+
+	bot.sendAttachments(b, outbox.PriorityReply, ctx.EffectiveChat.Id, msg.MessageThreadId, res.Attachments)
+
 	return nil
 }
 
+// renderReply fits the result in the limit and returns it with its reply in HTML, linked to the explorer.
+func renderReply(res command.CommandResult, limit int) (command.CommandResult, string) {
+	res = res.Fit(command.AppIdTelegram, limit)
+
+	return res, command.Linkify(command.AppIdTelegram, res.Message)
+}
+
+// handledChat returns true for the private chats and the groups, like the forum groups.
+func handledChat(chat gotgbot.Chat) bool {
+	switch chat.Type {
+	case gotgbot.ChatTypePrivate, gotgbot.ChatTypeGroup, gotgbot.ChatTypeSupergroup:
+		return true
+	default:
+		return false
+	}
+}
+
+// forumTopic returns the topic of a message in a forum group, the messages of the General topic
+// have no topic ID.
+func forumTopic(msg *gotgbot.Message) (string, bool) {
+	if !msg.Chat.IsForum {
+		return "", false
+	}
+
+	if !msg.IsTopicMessage {
+		return settings.GeneralTopic, true
+	}
+
+	return strconv.FormatInt(msg.MessageThreadId, 10), true
+}
+
+// sendAttachments sends the attachments by the outbox, a file that is sent again is read from the start.
+// The alt text of an image is its caption.
+func (bot *TelegramBot) sendAttachments(b *gotgbot.Bot, priority outbox.Priority, chatID, threadID int64,
+	attachments []command.Attachment,
+) {
+	for _, att := range attachments {
+		err := bot.outbox.Send(priority, func() error {
+			file := gotgbot.NamedFile{
+				File:     bytes.NewReader(att.Data),
+				FileName: att.Name,
+			}
+
+			var err error
+			if strings.HasPrefix(att.ContentType, "image/") {
+				// the caption is the nearest to an alt text, the screen readers read it with the photo.
+				_, err = b.SendPhoto(chatID, file, &gotgbot.SendPhotoOpts{
+					MessageThreadId: threadID,
+					Caption:         att.AltText,
+				})
+			} else {
+				_, err = b.SendDocument(chatID, file, &gotgbot.SendDocumentOpts{MessageThreadId: threadID})
+			}
+
+			return err
+		})
+		if err != nil {
+			log.Error("Failed to send attachment:", err)
+		}
+	}
+}
+
 func (bot *TelegramBot) RegisterCommandHandler(command string, handler CommandFunc) {
 	bot.commandHandlers[command] = NewCommandHandler(handler)
 }
@@ -134,6 +359,7 @@ func (bot *TelegramBot) GetName() string {
 func (bot *TelegramBot) Stop() {
 	log.Info("Shutting down Telegram Bot")
 	bot.cancel()
+	bot.outbox.Stop()
 
 	if bot.updater != nil {
 		log.Info("Stopping polling for updates...")
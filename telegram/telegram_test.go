@@ -0,0 +1,27 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/pagu-project/Pagu/golden"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoldenResults(t *testing.T) {
+	for _, f := range golden.Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			res, reply := renderReply(f.Result, golden.Limit)
+			res.Message = reply
+
+			golden.Assert(t, f.Name, golden.Result(res))
+		})
+	}
+}
+
+func TestHasChannel(t *testing.T) {
+	bot := &TelegramBot{}
+
+	assert.True(t, bot.HasChannel("-100123", "-100123"))
+	assert.False(t, bot.HasChannel("-100123", "-100456"), "another group")
+	assert.False(t, bot.HasChannel("-100123", "general"))
+}
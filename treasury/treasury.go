@@ -0,0 +1,193 @@
+package treasury
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const (
+	// checkInterval is the time between the checks of the indexed transactions, a few blocks.
+	checkInterval = time.Minute
+
+	// maxTransactions is the number of the new transactions in a page of a check, the treasury sends a few in a day.
+	maxTransactions = 100
+)
+
+// Store keeps the indexed transactions of the accounts and the posted alerts.
+type Store interface {
+	GetOutgoingTransactions(addresses []string, afterHeight uint32, limit int) ([]*database.AccountTransaction, error)
+	GetNextOutgoingTransactions(addresses []string, afterHeight uint32, limit int,
+	) ([]*database.AccountTransaction, error)
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Movement is an outgoing transaction of the treasury.
+type Movement struct {
+	TxID     string
+	Height   uint32
+	From     string
+	To       string
+	Internal bool // The receiver is a treasury account too, like a refill of a warm wallet.
+	Amount   amount.Amount
+}
+
+// NewMovement returns the movement of an indexed transaction of a treasury account.
+func NewMovement(tx *database.AccountTransaction, addresses []string) Movement {
+	return Movement{
+		TxID:     tx.TxID,
+		Height:   tx.Height,
+		From:     tx.Address,
+		To:       tx.Counterparty,
+		Internal: slices.Contains(addresses, tx.Counterparty),
+		Amount:   amount.Amount(tx.Amount),
+	}
+}
+
+// Monitor alerts the large outgoing transactions of the treasury accounts, from the transactions of the indexer.
+type Monitor struct {
+	lock      sync.Mutex
+	store     Store
+	hub       *notify.Hub
+	channels  []notify.Channel
+	addresses []string
+	minAmount int64  // The transactions of this amount or more are alerted, zero disables the alerts.
+	started   bool   // The first check starts from the indexed transactions, they are not alerted.
+	last      uint32 // The height of the last checked transaction.
+}
+
+// NewMonitor creates the monitor of the treasury accounts, the alerts are disabled without a channel.
+func NewMonitor(store Store, hub *notify.Hub, channels []notify.Channel, addresses []string, minAmount int64) *Monitor {
+	return &Monitor{
+		store:     store,
+		hub:       hub,
+		channels:  channels,
+		addresses: addresses,
+		minAmount: minAmount,
+	}
+}
+
+func (m *Monitor) enabled() bool {
+	return m.minAmount > 0 && len(m.channels) > 0
+}
+
+// Job returns the scheduler job of the checks. It's not exclusive, each instance keeps its own last height.
+// The alerts are claimed by the transaction, so an alert of the instances is posted once.
+func (m *Monitor) Job() scheduler.Job {
+	interval := checkInterval
+	if !m.enabled() {
+		interval = 0
+	}
+
+	return scheduler.Job{
+		Name:      "treasury",
+		Interval:  interval,
+		Exclusive: false,
+		Run:       m.Run,
+	}
+}
+
+// Run alerts the large outgoing transactions of the treasury that are indexed since the last check,
+// they are read in pages from the last height until the check is caught up.
+func (m *Monitor) Run(_ context.Context) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.started {
+		// the first check starts from the newest indexed transaction, the older ones are not alerted.
+		newest, err := m.store.GetOutgoingTransactions(m.addresses, 0, 1)
+		if err != nil {
+			return err
+		}
+
+		if len(newest) > 0 {
+			m.last = newest[0].Height
+		}
+		m.started = true
+
+		return nil
+	}
+
+	for {
+		txs, err := m.store.GetNextOutgoingTransactions(m.addresses, m.last, maxTransactions)
+		if err != nil {
+			return err
+		}
+
+		full := len(txs) == maxTransactions
+		if full {
+			// the last height of a full page may go on in the next page, it's read there from its start.
+			// A page of one height is checked whole, the treasury doesn't send so many in a block.
+			if cut := heightStart(txs); cut > 0 {
+				txs = txs[:cut]
+			}
+		}
+
+		for _, tx := range txs {
+			m.last = max(m.last, tx.Height)
+			if tx.Amount < m.minAmount {
+				continue
+			}
+
+			movement := NewMovement(tx, m.addresses)
+			log.Warn("a large treasury movement", "tx", tx.TxID, "from", movement.From, "to", movement.To,
+				"amount", movement.Amount)
+			m.alert(movement)
+		}
+
+		if !full {
+			return nil
+		}
+	}
+}
+
+// heightStart returns the index of the first transaction at the height of the last one, the oldest first.
+func heightStart(txs []*database.AccountTransaction) int {
+	last := txs[len(txs)-1].Height
+	start := len(txs) - 1
+	for start > 0 && txs[start-1].Height == last {
+		start--
+	}
+
+	return start
+}
+
+func (m *Monitor) alert(movement Movement) {
+	for _, channel := range m.channels {
+		if !m.hub.SupportsAnnounce(channel.AppID) {
+			continue
+		}
+
+		key := "treasury:" + movement.TxID + ":" + channel.String()
+		claimed, err := m.store.ClaimAnnouncement(key)
+		if err != nil {
+			log.Error("can't claim the treasury alert", "err", err, "channel", channel)
+
+			continue
+		}
+
+		if !claimed {
+			// another instance posted it.
+			continue
+		}
+
+		msg, err := command.RenderLocalizedTemplate(channel.AppID, channel.Locale, "treasury_alert", movement)
+		if err != nil {
+			log.Error("can't render the treasury alert", "err", err)
+
+			continue
+		}
+
+		if err := m.hub.Announce(channel, msg); err != nil {
+			log.Warn("can't post the treasury alert", "err", err, "channel", channel)
+		}
+	}
+}
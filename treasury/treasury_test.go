@@ -0,0 +1,119 @@
+package treasury
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	txs    []*database.AccountTransaction
+	claims map[string]bool
+}
+
+func (s *memoryStore) GetOutgoingTransactions(addresses []string, afterHeight uint32, limit int,
+) ([]*database.AccountTransaction, error) {
+	txs := make([]*database.AccountTransaction, 0)
+	for i := len(s.txs) - 1; i >= 0 && len(txs) < limit; i-- {
+		tx := s.txs[i]
+		if slices.Contains(addresses, tx.Address) && tx.Direction == database.TxOutgoing && tx.Height > afterHeight {
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs, nil
+}
+
+func (s *memoryStore) GetNextOutgoingTransactions(addresses []string, afterHeight uint32, limit int,
+) ([]*database.AccountTransaction, error) {
+	txs := make([]*database.AccountTransaction, 0)
+	for _, tx := range s.txs {
+		if slices.Contains(addresses, tx.Address) && tx.Direction == database.TxOutgoing && tx.Height > afterHeight {
+			txs = append(txs, tx)
+		}
+	}
+	slices.SortStableFunc(txs, func(a, b *database.AccountTransaction) int { return cmp.Compare(a.Height, b.Height) })
+
+	return txs[:min(limit, len(txs))], nil
+}
+
+func (s *memoryStore) ClaimAnnouncement(key string) (bool, error) {
+	if s.claims[key] {
+		return false, nil
+	}
+	s.claims[key] = true
+
+	return true, nil
+}
+
+type channels map[string][]string
+
+func (c channels) Notify(userID, message string) error {
+	return c.Announce(userID, message)
+}
+
+func (c channels) Announce(channelID, message string) error {
+	c[channelID] = append(c[channelID], message)
+
+	return nil
+}
+
+func TestMonitor(t *testing.T) {
+	store := &memoryStore{claims: map[string]bool{}}
+	discord := channels{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	addresses := []string{"pc1z-reserve", "pc1z-warm"}
+	ops := []notify.Channel{{AppID: command.AppIdDiscord, ID: "ops"}}
+	monitor := NewMonitor(store, hub, ops, addresses, 1_000e9)
+	another := NewMonitor(store, hub, ops, addresses, 1_000e9)
+	assert.Positive(t, monitor.Job().Interval)
+	assert.Zero(t, NewMonitor(store, hub, nil, addresses, 1_000e9).Job().Interval, "no channel")
+
+	outgoing := func(id, from, to string, height uint32, amt int64) *database.AccountTransaction {
+		return &database.AccountTransaction{
+			TxID: id, Address: from, Height: height, Direction: database.TxOutgoing, Counterparty: to, Amount: amt,
+		}
+	}
+
+	store.txs = append(store.txs, outgoing("aa", "pc1z-reserve", "pc1z-grant", 10, 5_000e9))
+	require.NoError(t, monitor.Run(context.Background()))
+	require.NoError(t, another.Run(context.Background()))
+	assert.Empty(t, discord["ops"], "the indexed transactions before the start are not alerted")
+
+	store.txs = append(store.txs,
+		outgoing("bb", "pc1z-warm", "pc1z-small", 20, 10e9),
+		outgoing("cc", "pc1z-reserve", "pc1z-warm", 30, 2_000e9),
+		outgoing("dd", "pc1z-other", "pc1z-grant", 30, 9_000e9),
+	)
+	require.NoError(t, monitor.Run(context.Background()))
+	require.NoError(t, another.Run(context.Background()))
+	require.Len(t, discord["ops"], 1, "the instances post the large treasury transaction once")
+	assert.Contains(t, discord["ops"][0], "2,000 PAC from pc1z-reserve to pc1z-warm (a treasury account)")
+	assert.Contains(t, discord["ops"][0], "/transaction/cc")
+
+	require.NoError(t, monitor.Run(context.Background()))
+	assert.Len(t, discord["ops"], 1, "a transaction is alerted once")
+
+	// a burst of the small transactions fills the pages, the large ones after them are alerted too.
+	for i := 0; i < 2*maxTransactions; i++ {
+		store.txs = append(store.txs, outgoing(fmt.Sprintf("s%03d", i), "pc1z-warm", "pc1z-small", 40+uint32(i/3), 1e9))
+	}
+	store.txs = append(store.txs,
+		outgoing("ee", "pc1z-reserve", "pc1z-grant", 40+maxTransactions/3, 3_000e9),
+		outgoing("ff", "pc1z-reserve", "pc1z-grant", 500, 4_000e9),
+	)
+	require.NoError(t, monitor.Run(context.Background()))
+	require.Len(t, discord["ops"], 3, "the check pages forward until it's caught up")
+	assert.Contains(t, discord["ops"][1], "/transaction/ee")
+	assert.Contains(t, discord["ops"][2], "/transaction/ff")
+}
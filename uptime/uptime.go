@@ -0,0 +1,285 @@
+package uptime
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/log"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/pagu-project/Pagu/scheduler"
+)
+
+const (
+	// sampleInterval is the time between the samples of the watched validators.
+	sampleInterval = time.Hour
+
+	// reportInterval is how often the reports of the last month are checked, they are sent once.
+	reportInterval = time.Hour
+
+	// syncTolerance is how many blocks the node can be behind the network and still be online.
+	syncTolerance = 10
+)
+
+// MonthLayout is the format of the months of the reports, like: "2024-07".
+const MonthLayout = "2006-01"
+
+// Store keeps the samples of the validators and the sent reports.
+type Store interface {
+	GetAllWatchedValidators() ([]*database.WatchedValidator, error)
+	AddAvailabilitySample(s *database.AvailabilitySample) error
+	GetAvailabilitySamples(address string, from, to time.Time) ([]*database.AvailabilitySample, error)
+	ClaimAnnouncement(key string) (bool, error)
+}
+
+// Report is the uptime of a validator in a month.
+type Report struct {
+	Address    string
+	Month      time.Time
+	Samples    int
+	Heights    uint32  // The blocks between the first and the last sample.
+	Online     float64 // The heights that the node was online in, in percent.
+	Sortitions int     // The sample intervals that the validator joined the committee in, once or more.
+	Expected   float64 // The intervals with a sortition expected by the share of the stake.
+	Missed     int     // The expected intervals that the validator didn't join the committee in.
+	MinScore   float64
+	AvgScore   float64
+}
+
+// Uptime samples the watched validators every hour, and sends the monthly reports of them to their watchers.
+// The instances can share the store, an hour keeps one sample and a report is claimed before it's sent.
+type Uptime struct {
+	clientMgr *client.Mgr
+	store     Store
+	hub       *notify.Hub
+	now       func() time.Time
+}
+
+func NewUptime(clientMgr *client.Mgr, store Store, hub *notify.Hub) *Uptime {
+	return &Uptime{
+		clientMgr: clientMgr,
+		store:     store,
+		hub:       hub,
+		now:       time.Now,
+	}
+}
+
+// ParseMonth parses the month of a report, like: "2024-07".
+func ParseMonth(value string) (time.Time, error) {
+	return time.Parse(MonthLayout, value)
+}
+
+// Jobs returns the scheduler jobs of the samples and the reports. The samples are exclusive,
+// one of the instances takes them. The reports are not, each instance sends the reports of its platforms.
+func (u *Uptime) Jobs() []scheduler.Job {
+	return []scheduler.Job{
+		{
+			Name:      "uptime-samples",
+			Interval:  sampleInterval,
+			Exclusive: true,
+			Run:       u.Sample,
+		},
+		{
+			Name:      "uptime-reports",
+			Interval:  reportInterval,
+			Exclusive: false,
+			Run:       u.SendReports,
+		},
+	}
+}
+
+// Sample adds the samples of the watched validators in the current hour.
+func (u *Uptime) Sample(ctx context.Context) error {
+	watched, err := u.store.GetAllWatchedValidators()
+	if err != nil {
+		return err
+	}
+
+	if len(watched) == 0 {
+		return nil
+	}
+
+	info, err := u.clientMgr.GetBlockchainInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	hour := u.now().UTC().Truncate(time.Hour)
+	sampled := make(map[string]bool)
+	for _, w := range watched {
+		if sampled[w.Address] {
+			continue
+		}
+		sampled[w.Address] = true
+
+		val, err := u.clientMgr.GetValidatorInfo(ctx, w.Address)
+		if err != nil || val.Validator == nil {
+			log.Warn("can't sample the validator", "err", err, "address", w.Address)
+
+			continue
+		}
+
+		sample := &database.AvailabilitySample{
+			Address:             w.Address,
+			Hour:                hour,
+			Height:              info.LastBlockHeight,
+			Score:               val.Validator.AvailabilityScore,
+			LastSortitionHeight: val.Validator.LastSortitionHeight,
+			Stake:               val.Validator.Stake,
+			TotalPower:          info.TotalPower,
+		}
+		if peer, err := u.clientMgr.GetPeerInfo(w.Address); err == nil {
+			sample.Online = peer.Height+syncTolerance >= info.LastBlockHeight
+		}
+
+		if err := u.store.AddAvailabilitySample(sample); err != nil {
+			log.Error("can't add the availability sample", "err", err, "address", w.Address)
+		}
+	}
+
+	return nil
+}
+
+// Report returns the uptime report of the validator in the month, nil if the month has no samples of it.
+func (u *Uptime) Report(address string, month time.Time) (*Report, error) {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	samples, err := u.store.GetAvailabilitySamples(address, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(samples) == 0 {
+		return nil, nil
+	}
+
+	report := BuildReport(address, from, samples)
+
+	return &report, nil
+}
+
+// BuildReport computes the report of the samples, the oldest first. The heights between two samples are online
+// if the node is online in the second sample. The samples only have the last sortition, so the sortitions are
+// counted per interval: the validator joins the committee in a height by the share of its stake in the total power,
+// and an interval is expected to have a sortition if one of its heights has.
+func BuildReport(address string, month time.Time, samples []*database.AvailabilitySample) Report {
+	report := Report{
+		Address:  address,
+		Month:    month,
+		Samples:  len(samples),
+		MinScore: math.Inf(1),
+	}
+
+	var onlineHeights uint32
+	var totalScore float64
+	onlineSamples := 0
+	for i, s := range samples {
+		totalScore += s.Score
+		report.MinScore = min(report.MinScore, s.Score)
+		if s.Online {
+			onlineSamples++
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := samples[i-1]
+		if s.LastSortitionHeight > prev.LastSortitionHeight {
+			report.Sortitions++
+		}
+
+		if s.Height <= prev.Height {
+			continue
+		}
+
+		span := s.Height - prev.Height
+		report.Heights += span
+		if s.Online {
+			onlineHeights += span
+		}
+		if s.TotalPower > 0 {
+			share := min(float64(s.Stake)/float64(s.TotalPower), 1)
+			report.Expected += 1 - math.Pow(1-share, float64(span))
+		}
+	}
+
+	report.AvgScore = totalScore / float64(len(samples))
+	if report.Heights > 0 {
+		report.Online = float64(onlineHeights) * 100 / float64(report.Heights)
+	} else {
+		report.Online = float64(onlineSamples) * 100 / float64(len(samples))
+	}
+	report.Missed = max(int(math.Round(report.Expected))-report.Sortitions, 0)
+
+	return report
+}
+
+// SendReports sends the reports of the last month to the watchers of the validators, once in the month.
+func (u *Uptime) SendReports(_ context.Context) error {
+	watched, err := u.store.GetAllWatchedValidators()
+	if err != nil {
+		return err
+	}
+
+	now := u.now().UTC()
+	lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	reports := make(map[string]*Report)
+	for _, w := range watched {
+		appID := command.AppID(w.AppID)
+		if !u.hub.Supports(appID) {
+			continue
+		}
+
+		report, ok := reports[w.Address]
+		if !ok {
+			report, err = u.Report(w.Address, lastMonth)
+			if err != nil {
+				log.Error("can't build the uptime report", "err", err, "address", w.Address)
+
+				continue
+			}
+			reports[w.Address] = report
+		}
+
+		if report == nil {
+			// the validator was not watched in the last month.
+			continue
+		}
+
+		u.send(appID, w.UserID, report)
+	}
+
+	return nil
+}
+
+// send claims the report of the user and sends it, a failed message is not retried.
+func (u *Uptime) send(appID command.AppID, userID string, report *Report) {
+	key := "uptime:" + report.Month.Format(MonthLayout) + ":" + strconv.Itoa(int(appID)) + ":" +
+		userID + ":" + report.Address
+	claimed, err := u.store.ClaimAnnouncement(key)
+	if err != nil {
+		log.Error("can't claim the uptime report", "err", err, "key", key)
+
+		return
+	}
+
+	if !claimed {
+		// another instance sent it, or it's sent in the last run.
+		return
+	}
+
+	msg, err := command.RenderTemplate(appID, "uptime_report", report)
+	if err != nil {
+		log.Error("can't render the uptime report", "err", err, "address", report.Address)
+
+		return
+	}
+
+	if err := u.hub.Notify(appID, userID, msg); err != nil {
+		log.Warn("can't send the uptime report", "err", err, "user", userID, "address", report.Address)
+	}
+}
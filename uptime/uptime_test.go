@@ -0,0 +1,127 @@
+package uptime
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/pagu-project/Pagu/client"
+	"github.com/pagu-project/Pagu/database"
+	"github.com/pagu-project/Pagu/engine/command"
+	"github.com/pagu-project/Pagu/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type messages map[string][]string
+
+func (m messages) Notify(userID, message string) error {
+	m[userID] = append(m[userID], message)
+
+	return nil
+}
+
+func TestBuildReport(t *testing.T) {
+	month := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	samples := []*database.AvailabilitySample{
+		{Height: 1_000, Online: true, Score: 1, LastSortitionHeight: 900, Stake: 10, TotalPower: 100},
+		{Height: 1_100, Online: true, Score: 0.9, LastSortitionHeight: 1_050, Stake: 10, TotalPower: 100},
+		{Height: 1_200, Online: false, Score: 0.5, LastSortitionHeight: 1_050, Stake: 10, TotalPower: 100},
+		{Height: 1_400, Online: true, Score: 0.8, LastSortitionHeight: 1_350, Stake: 10, TotalPower: 100},
+	}
+
+	report := BuildReport("pc1p1", month, samples)
+	assert.Equal(t, 4, report.Samples)
+	assert.Equal(t, uint32(400), report.Heights)
+	assert.InDelta(t, 75, report.Online, 0.001, "the 100 blocks before the offline sample are offline")
+	assert.Equal(t, 2, report.Sortitions)
+	assert.InDelta(t, 3, report.Expected, 0.001, "a tenth of the power joins in about 10 blocks")
+	assert.Equal(t, 1, report.Missed, "the interval before the offline sample has no sortition")
+	assert.InDelta(t, 0.5, report.MinScore, 0.001)
+	assert.InDelta(t, 0.8, report.AvgScore, 0.001)
+
+	samples[1].Stake, samples[2].Stake, samples[3].Stake = 1, 1, 1
+	report = BuildReport("pc1p1", month, samples)
+	assert.InDelta(t, 2.13, report.Expected, 0.01, "a hundredth of the power may miss an interval")
+
+	report = BuildReport("pc1p1", month, samples[2:3])
+	assert.Zero(t, report.Heights)
+	assert.Zero(t, report.Online, "the only sample is offline")
+	assert.Zero(t, report.Missed)
+}
+
+func TestUptime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	dbFile, err := os.CreateTemp("", "temp-db")
+	require.NoError(t, err)
+	db, err := database.NewDB(dbFile.Name())
+	require.NoError(t, err)
+
+	height := uint32(1_000)
+	c := client.NewMockIClient(ctrl)
+	c.EXPECT().GetBlockchainInfo(gomock.Any()).DoAndReturn(
+		func(_ context.Context) (*pactus.GetBlockchainInfoResponse, error) {
+			return &pactus.GetBlockchainInfoResponse{LastBlockHeight: height, TotalPower: 100}, nil
+		}).AnyTimes()
+	c.EXPECT().GetValidatorInfo(gomock.Any(), "pc1p1").Return(&pactus.GetValidatorResponse{
+		Validator: &pactus.ValidatorInfo{AvailabilityScore: 0.95, Stake: 10, LastSortitionHeight: 900},
+	}, nil).AnyTimes()
+	cm := client.NewClientMgr(context.Background())
+	cm.AddClient(c)
+
+	discord := messages{}
+	hub := notify.NewHub()
+	hub.Register(command.AppIdDiscord, discord)
+
+	u := NewUptime(cm, db, hub)
+	ctx := context.Background()
+
+	require.NoError(t, u.Sample(ctx), "nothing to sample")
+
+	require.NoError(t, db.AddWatchedValidator(&database.WatchedValidator{
+		AppID: int(command.AppIdDiscord), UserID: "alice", Address: "pc1p1",
+	}))
+	require.NoError(t, db.AddWatchedValidator(&database.WatchedValidator{
+		AppID: int(command.AppIdDiscord), UserID: "bob", Address: "pc1p1",
+	}))
+	require.NoError(t, db.AddWatchedValidator(&database.WatchedValidator{
+		AppID: int(command.AppIdTelegram), UserID: "carol", Address: "pc1p1",
+	}))
+
+	now := time.Date(2024, 7, 31, 22, 10, 0, 0, time.UTC)
+	u.now = func() time.Time { return now }
+	require.NoError(t, u.Sample(ctx))
+	require.NoError(t, u.Sample(ctx), "the hour has a sample")
+
+	now = now.Add(time.Hour)
+	height = 1_360
+	require.NoError(t, u.Sample(ctx))
+
+	report, err := u.Report("pc1p1", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, 2, report.Samples)
+	assert.Equal(t, uint32(360), report.Heights)
+	assert.Zero(t, report.Online, "the node is not connected")
+
+	report, err = u.Report("pc1p1", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Nil(t, report)
+
+	require.NoError(t, u.SendReports(ctx))
+	assert.Empty(t, discord, "the last month has no samples")
+
+	now = time.Date(2024, 8, 1, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, u.SendReports(ctx))
+	require.NoError(t, u.SendReports(ctx), "the reports are sent once")
+	require.Len(t, discord["alice"], 1)
+	require.Len(t, discord["bob"], 1)
+	assert.Contains(t, discord["alice"][0], "Uptime report of July 2024")
+	assert.Contains(t, discord["alice"][0], "Online: 0.00% of 360 blocks")
+	assert.Contains(t, discord["alice"][0], "Sortitions: in 0 of 1.0 expected hours, 1 missed")
+	assert.Empty(t, discord["carol"], "Telegram has no notifier")
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pactus-project/pactus/types/amount"
+)
+
+const (
+	UnitPAC     = "PAC"
+	UnitNanoPAC = "NanoPAC"
+)
+
+// nanoDigits is the number of the decimal digits of a PAC, one NanoPAC is 1e-9 PAC.
+const nanoDigits = 9
+
+// AmountFormat is how the amounts are shown, like: "1,234.56 PAC".
+// The amounts are rounded to the precision digits and the trailing zeros are removed.
+type AmountFormat struct {
+	Precision int
+	Unit      string
+}
+
+// DefaultAmountFormat shows the amounts in PAC with the NanoPAC precision.
+var DefaultAmountFormat = AmountFormat{
+	Precision: nanoDigits,
+	Unit:      UnitPAC,
+}
+
+// Validate checks the precision and the unit of the format.
+func (f AmountFormat) Validate() error {
+	if f.Precision < 0 || f.Precision > nanoDigits {
+		return fmt.Errorf("amount precision should be between 0 and %d", nanoDigits)
+	}
+
+	if f.Unit != UnitPAC && f.Unit != UnitNanoPAC {
+		return fmt.Errorf("unknown amount unit: %s", f.Unit)
+	}
+
+	return nil
+}
+
+// Format returns the amount with the grouped digits and the unit suffix, the NanoPAC unit has no decimals.
+func (f AmountFormat) Format(amt amount.Amount) string {
+	nano := int64(amt)
+	if f.Unit == UnitNanoPAC {
+		return FormatNumber(nano) + " " + UnitNanoPAC
+	}
+
+	sign := ""
+	if nano < 0 {
+		sign = "-"
+		nano = -nano
+	}
+
+	// rounding half away from zero, on the NanoPAC to keep it exact.
+	scale := int64(1)
+	for i := f.Precision; i < nanoDigits; i++ {
+		scale *= 10
+	}
+	nano = (nano + scale/2) / scale * scale
+
+	whole := FormatNumber(nano / 1e9)
+	fraction := strings.TrimRight(fmt.Sprintf("%09d", nano%1e9)[:f.Precision], "0")
+	if fraction == "" {
+		return sign + whole + " " + f.Unit
+	}
+
+	return sign + whole + "." + fraction + " " + f.Unit
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/pactus-project/pactus/types/amount"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAmount(t *testing.T) {
+	f := DefaultAmountFormat
+	assert.Equal(t, "0 PAC", f.Format(0))
+	assert.Equal(t, "1,234.56 PAC", f.Format(1_234_560_000_000))
+	assert.Equal(t, "0.000000001 PAC", f.Format(1))
+	assert.Equal(t, "-12.5 PAC", f.Format(-12_500_000_000))
+	assert.Equal(t, "1,000,000 PAC", f.Format(amount.Amount(1e15)))
+
+	f.Precision = 2
+	assert.Equal(t, "1,234.57 PAC", f.Format(1_234_567_000_000))
+	assert.Equal(t, "1 PAC", f.Format(999_999_999))
+	assert.Equal(t, "0 PAC", f.Format(4_000_000))
+
+	f.Precision = 0
+	assert.Equal(t, "1,235 PAC", f.Format(1_234_500_000_000))
+
+	f.Unit = UnitNanoPAC
+	assert.Equal(t, "1,234,560,000,000 NanoPAC", f.Format(1_234_560_000_000))
+
+	assert.NoError(t, DefaultAmountFormat.Validate())
+	assert.Error(t, AmountFormat{Precision: 10, Unit: UnitPAC}.Validate())
+	assert.Error(t, AmountFormat{Precision: 2, Unit: "USD"}.Validate())
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+const (
+	ChartWidth  = 480
+	ChartHeight = 280
+
+	chartMargin = 24
+	glyphScale  = 3 // The glyphs are 3x5 pixels, scaled to 9x15.
+)
+
+var (
+	chartBackground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	chartBar        = color.RGBA{R: 0x2f, G: 0x80, B: 0xed, A: 0xff}
+	chartText       = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+)
+
+// glyphs are the 3x5 bitmaps of the characters of the chart labels, a row in 3 bits.
+// The other characters are drawn as spaces.
+var glyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'k': {4, 5, 6, 5, 5},
+	'M': {5, 7, 7, 5, 5},
+	'<': {1, 2, 4, 2, 1},
+	'+': {0, 2, 7, 2, 0},
+	'-': {0, 0, 7, 0, 0},
+	',': {0, 0, 0, 2, 4},
+	'.': {0, 0, 0, 0, 2},
+}
+
+// BarChartPNG draws the values as a bar chart, with the value above each bar and the label under it.
+// The labels can have the digits and: k M < + - , . like: "<10", "100-500" or "1k+".
+func BarChartPNG(labels []string, values []int64) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, ChartWidth, ChartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: chartBackground}, image.Point{}, draw.Src)
+
+	maxValue := int64(0)
+	for _, v := range values {
+		maxValue = max(maxValue, v)
+	}
+
+	glyphHeight := 5 * glyphScale
+	top := chartMargin + glyphHeight + 4
+	bottom := ChartHeight - chartMargin - glyphHeight - 4
+	if len(values) > 0 {
+		slot := (ChartWidth - 2*chartMargin) / len(values)
+		barWidth := slot * 2 / 3
+		for i, v := range values {
+			left := chartMargin + i*slot + (slot-barWidth)/2
+			barHeight := 0
+			if maxValue > 0 {
+				barHeight = int(int64(bottom-top) * v / maxValue)
+			}
+
+			draw.Draw(img, image.Rect(left, bottom-barHeight, left+barWidth, bottom),
+				&image.Uniform{C: chartBar}, image.Point{}, draw.Src)
+
+			center := left + barWidth/2
+			drawText(img, FormatNumber(v), center, bottom-barHeight-glyphHeight-4)
+			if i < len(labels) {
+				drawText(img, labels[i], center, bottom+4)
+			}
+		}
+	}
+
+	buf := bytes.Buffer{}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// BarChartAltText describes the bar chart for the screen readers, the values of the bars, their total and the highest,
+// like: "Bar chart of the validators: <10: 3, 10-100: 12. The total is 15, the highest is 10-100 with 12.".
+func BarChartAltText(title string, labels []string, values []int64) string {
+	bars := make([]string, 0, len(values))
+	highest := -1
+	total := int64(0)
+	for i, v := range values {
+		total += v
+		label := ""
+		if i < len(labels) {
+			label = labels[i]
+		}
+		bars = append(bars, label+": "+FormatNumber(v))
+
+		if highest < 0 || v > values[highest] {
+			highest = i
+		}
+	}
+
+	if highest < 0 || values[highest] == 0 {
+		return "Bar chart of " + title + ", it has no values."
+	}
+
+	text := "Bar chart of " + title + ": " + strings.Join(bars, ", ") + ". The total is " + FormatNumber(total)
+	if highest < len(labels) {
+		text += ", the highest is " + labels[highest] + " with " + FormatNumber(values[highest])
+	}
+	text += "."
+
+	return text
+}
+
+// drawText draws the text centered on the x, from the top y.
+func drawText(img *image.RGBA, text string, x, y int) {
+	advance := 4 * glyphScale
+	x -= (len([]rune(text))*advance - glyphScale) / 2
+	for _, r := range text {
+		rows := glyphs[r]
+		for row, bits := range rows {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) == 0 {
+					continue
+				}
+
+				px, py := x+col*glyphScale, y+row*glyphScale
+				draw.Draw(img, image.Rect(px, py, px+glyphScale, py+glyphScale),
+					&image.Uniform{C: chartText}, image.Point{}, draw.Src)
+			}
+		}
+		x += advance
+	}
+}
+
+// CompactNumber returns the number with the k and M suffixes of the thousands and the millions, like: "10k".
+// The number is not rounded, it's for the round bounds of the chart labels.
+func CompactNumber(num int64) string {
+	switch {
+	case num >= 1_000_000 && num%1_000_000 == 0:
+		return strconv.FormatInt(num/1_000_000, 10) + "M"
+	case num >= 1_000 && num%1_000 == 0:
+		return strconv.FormatInt(num/1_000, 10) + "k"
+	default:
+		return strconv.FormatInt(num, 10)
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarChartPNG(t *testing.T) {
+	data, err := BarChartPNG([]string{"<10", "10-100", "1k+"}, []int64{3, 12, 0})
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, ChartWidth, img.Bounds().Dx())
+	assert.Equal(t, ChartHeight, img.Bounds().Dy())
+
+	// the highest bar reaches the top of the plot, in the middle slot.
+	r, g, b, _ := img.At(ChartWidth/2, chartMargin+5*glyphScale+8).RGBA()
+	assert.Equal(t, [3]uint32{0x2f2f, 0x8080, 0xeded}, [3]uint32{r, g, b})
+
+	_, err = BarChartPNG(nil, nil)
+	require.NoError(t, err, "an empty chart")
+}
+
+func TestBarChartAltText(t *testing.T) {
+	assert.Equal(t, "Bar chart of the validators by stake: <10: 3, 10-100: 1,200, 1k+: 0. "+
+		"The total is 1,203, the highest is 10-100 with 1,200.",
+		BarChartAltText("the validators by stake", []string{"<10", "10-100", "1k+"}, []int64{3, 1_200, 0}))
+	assert.Equal(t, "Bar chart of the new accounts, it has no values.",
+		BarChartAltText("the new accounts", []string{"Mon"}, []int64{0}))
+	assert.Equal(t, "Bar chart of the new accounts, it has no values.", BarChartAltText("the new accounts", nil, nil))
+}
+
+func TestCompactNumber(t *testing.T) {
+	assert.Equal(t, "0", CompactNumber(0))
+	assert.Equal(t, "500", CompactNumber(500))
+	assert.Equal(t, "1k", CompactNumber(1_000))
+	assert.Equal(t, "1500", CompactNumber(1_500))
+	assert.Equal(t, "10M", CompactNumber(10_000_000))
+}
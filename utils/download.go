@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DownloadFile returns the file of the URL, like an uploaded file of a chat platform.
+// It fails if the file is larger than the maximum size, so a large file isn't read to the memory.
+func DownloadFile(ctx context.Context, fileURL string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, http.NoBody)
+	if err != nil {
+		return nil, errors.New("invalid file URL")
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// the URL is not in the error, the file URLs of Telegram have the token of the bot.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, urlErr.Err
+		}
+
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("can't download the file: %s", res.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("the file is larger than %d bytes", maxSize)
+	}
+
+	return data, nil
+}
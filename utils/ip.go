@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -13,29 +14,58 @@ type GeoIP struct {
 	City        string `json:"city"`
 	TimeZone    string `json:"timezone"`
 	ISP         string `json:"isp"`
+	AS          string `json:"as"`      // Like: "AS15169 Google LLC".
+	Proxy       bool   `json:"proxy"`   // Proxy, VPN or Tor exit address.
+	Hosting     bool   `json:"hosting"` // Hosting, colocated or data center address.
 }
 
-func ExtractIPFromMultiAddr(multiAddr string) string {
-	parts := strings.Split(strings.Split(multiAddr, "/")[2], "/")
-	return parts[0]
+// geoIPFields are the fields requested from ip-api, proxy and hosting are not in the default response.
+const geoIPFields = "country,regionName,city,timezone,isp,as,proxy,hosting"
+
+// ASN returns the autonomous system number of the AS field, like: "AS15169".
+func (g *GeoIP) ASN() string {
+	asn, _, _ := strings.Cut(g.AS, " ")
+
+	return asn
 }
 
 func GetGeoIP(ip string) *GeoIP {
+	geo, _ := GetGeoIPContext(context.Background(), ip)
+
+	return geo
+}
+
+// GetGeoIPContext returns the GeoIP of the IP, it stops when the context is done.
+// The GeoIP is empty if the lookup fails.
+func GetGeoIPContext(ctx context.Context, ip string) (*GeoIP, error) {
 	geo := &GeoIP{}
-	res, err := http.Get("http://ip-api.com/json/" + ip)
+	if ip == "" {
+		// ip-api looks up the address of the bot itself for an empty IP.
+		return geo, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"http://ip-api.com/json/"+ip+"?fields="+geoIPFields, http.NoBody)
+	if err != nil {
+		return geo, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return geo
+		return geo, err
 	}
 
+	defer res.Body.Close()
+
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return geo
+		return geo, err
 	}
 
 	err = json.Unmarshal(body, &geo)
 	if err != nil {
-		return geo
+		return geo, err
 	}
 
-	return geo
+	return geo, nil
 }
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// resolveTimeout is the time to resolve the host name of a multiaddr, a slow name server doesn't hold the command.
+const resolveTimeout = 3 * time.Second
+
+// MultiAddr is the host of the multiaddr of a peer, like "/dns4/node.example.com/tcp/21888/p2p/12D3...".
+type MultiAddr struct {
+	Protocol string // The protocol of the host: "ip4", "ip6", "dns", "dns4" or "dns6".
+	Host     string // The IP or the host name.
+	Port     int    // The TCP or the UDP port, zero if there is none.
+	Relayed  bool   // The peer is reached through a relay, the host is the relay's, like: ".../p2p-circuit".
+}
+
+// ParseMultiAddr returns the host of the multiaddr, it fails if the multiaddr is malformed or has no host,
+// like a relayed address without the address of the relay: "/p2p/12D3.../p2p-circuit".
+func ParseMultiAddr(multiAddr string) (MultiAddr, error) {
+	addr, err := ma.NewMultiaddr(multiAddr)
+	if err != nil {
+		return MultiAddr{}, err
+	}
+
+	parsed := MultiAddr{}
+	ma.ForEach(addr, func(c ma.Component) bool {
+		switch c.Protocol().Code {
+		case ma.P_IP4, ma.P_IP6, ma.P_DNS, ma.P_DNS4, ma.P_DNS6:
+			if parsed.Host == "" {
+				parsed.Protocol = c.Protocol().Name
+				parsed.Host = c.Value()
+			}
+
+		case ma.P_TCP, ma.P_UDP:
+			if parsed.Port == 0 {
+				parsed.Port, _ = strconv.Atoi(c.Value())
+			}
+
+		case ma.P_CIRCUIT:
+			parsed.Relayed = true
+		}
+
+		return true
+	})
+
+	if parsed.Host == "" {
+		return MultiAddr{}, fmt.Errorf("%s has no IP or host name", multiAddr)
+	}
+
+	return parsed, nil
+}
+
+// IsDNS returns true if the host is a host name, like "node.example.com".
+func (m MultiAddr) IsDNS() bool {
+	return strings.HasPrefix(m.Protocol, "dns")
+}
+
+// ResolveIP returns the IP of the host, a host name is resolved to its first public IP of the protocol.
+// The names out of the public DNS, like "localhost" or "node.local", are not resolved.
+func (m MultiAddr) ResolveIP(ctx context.Context) (string, error) {
+	if !m.IsDNS() {
+		return m.Host, nil
+	}
+
+	if !isPublicName(m.Host) {
+		return "", fmt.Errorf("%s is not a public host name", m.Host)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	network := map[string]string{"dns": "ip", "dns4": "ip4", "dns6": "ip6"}[m.Protocol]
+	ips, err := net.DefaultResolver.LookupIP(ctx, network, m.Host)
+	if err != nil {
+		return "", err
+	}
+
+	for _, ip := range ips {
+		if IsPublicIP(ip) {
+			return ip.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("%s has no public IP", m.Host)
+}
+
+// GetMultiAddrGeoIP returns the GeoIP of the host of the multiaddr, a host name is resolved first.
+// The GeoIP is empty for a relayed peer, the host is the relay's.
+func GetMultiAddrGeoIP(ctx context.Context, multiAddr string) *GeoIP {
+	addr, err := ParseMultiAddr(multiAddr)
+	if err != nil || addr.Relayed {
+		return &GeoIP{}
+	}
+
+	ip, err := addr.ResolveIP(ctx)
+	if err != nil {
+		return &GeoIP{}
+	}
+
+	geo, _ := GetGeoIPContext(ctx, ip)
+
+	return geo
+}
+
+// isPublicName returns true for the names that the public DNS resolves, the local names are not.
+func isPublicName(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if !strings.Contains(host, ".") {
+		return false
+	}
+
+	for _, local := range []string{".local", ".localhost", ".internal", ".home.arpa"} {
+		if strings.HasSuffix(host, local) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsPublicIP returns true if the IP is on the internet, not a private, a loopback or a link-local one.
+func IsPublicIP(ip net.IP) bool {
+	return ip != nil && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsMulticast()
+}
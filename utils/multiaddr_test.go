@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMultiAddr(t *testing.T) {
+	peerID := "12D3KooWJUrrPmeXuD5d2eE2BupE3fC3ZMGnKqt8SFeH8zWEWgmg"
+	tests := []struct {
+		addr string
+		want MultiAddr
+	}{
+		{"/ip4/1.2.3.4/tcp/21888/p2p/" + peerID, MultiAddr{Protocol: "ip4", Host: "1.2.3.4", Port: 21888}},
+		{"/ip6/2001:db8::1/tcp/21777", MultiAddr{Protocol: "ip6", Host: "2001:db8::1", Port: 21777}},
+		{
+			"/dns4/node.example.com/udp/21888/quic-v1",
+			MultiAddr{Protocol: "dns4", Host: "node.example.com", Port: 21888},
+		},
+		{"/dns6/node.example.com", MultiAddr{Protocol: "dns6", Host: "node.example.com"}},
+		{
+			"/ip4/5.6.7.8/tcp/4001/p2p/" + peerID + "/p2p-circuit/p2p/" + peerID,
+			MultiAddr{Protocol: "ip4", Host: "5.6.7.8", Port: 4001, Relayed: true},
+		},
+	}
+
+	for _, tt := range tests {
+		addr, err := ParseMultiAddr(tt.addr)
+		require.NoError(t, err, tt.addr)
+		assert.Equal(t, tt.want, addr, tt.addr)
+	}
+
+	for _, addr := range []string{"", "/ip4", "1.2.3.4", "/p2p/" + peerID + "/p2p-circuit"} {
+		_, err := ParseMultiAddr(addr)
+		assert.Error(t, err, addr)
+	}
+}
+
+func TestResolveIP(t *testing.T) {
+	ip, err := MultiAddr{Protocol: "ip6", Host: "2001:db8::1"}.ResolveIP(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::1", ip)
+
+	for _, host := range []string{"localhost", "node.local", "db.internal"} {
+		_, err := MultiAddr{Protocol: "dns4", Host: host}.ResolveIP(context.Background())
+		assert.Error(t, err, host)
+	}
+
+	assert.Empty(t, *GetMultiAddrGeoIP(context.Background(), "/p2p-circuit"))
+}
+
+func FuzzParseMultiAddr(f *testing.F) {
+	seeds := []string{"/ip4/1.2.3.4/tcp/21888", "/ip6/::1", "/dns4/node.example.com", "/ip4", "", "/", "//"}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, multiAddr string) {
+		addr, err := ParseMultiAddr(multiAddr)
+		if err != nil {
+			return
+		}
+
+		if addr.Host == "" || addr.Protocol == "" {
+			t.Fatalf("%q is parsed without a host: %+v", multiAddr, addr)
+		}
+
+		if addr.Port < 0 || addr.Port > 65535 {
+			t.Fatalf("%q is parsed with the port %d", multiAddr, addr.Port)
+		}
+
+		if !addr.IsDNS() && strings.ContainsAny(addr.Host, "/") {
+			t.Fatalf("the IP %q of %q has a slash", addr.Host, multiAddr)
+		}
+	})
+}
@@ -6,6 +6,11 @@ func FormatNumber(num int64) string {
 	numStr := strconv.FormatInt(num, 10)
 
 	var formattedNum string
+	if num < 0 {
+		formattedNum = "-"
+		numStr = numStr[1:]
+	}
+
 	for i, c := range numStr {
 		if (i > 0) && (len(numStr)-i)%3 == 0 {
 			formattedNum += ","
@@ -15,3 +20,19 @@ func FormatNumber(num int64) string {
 
 	return formattedNum
 }
+
+// FormatBytes returns the size in the largest unit that it has one of, like: "1.50 GB".
+func FormatBytes(size uint64) string {
+	const unit = 1000
+	if size < unit {
+		return strconv.FormatUint(size, 10) + " B"
+	}
+
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return strconv.FormatFloat(float64(size)/float64(div), 'f', 2, 64) + " " + string("KMGTP"[exp]) + "B"
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "0", FormatNumber(0))
+	assert.Equal(t, "123", FormatNumber(123))
+	assert.Equal(t, "1,234", FormatNumber(1234))
+	assert.Equal(t, "1,234,567", FormatNumber(1234567))
+	assert.Equal(t, "-123", FormatNumber(-123))
+	assert.Equal(t, "-1,234", FormatNumber(-1234))
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "0 B", FormatBytes(0))
+	assert.Equal(t, "999 B", FormatBytes(999))
+	assert.Equal(t, "1.00 KB", FormatBytes(1000))
+	assert.Equal(t, "1.50 MB", FormatBytes(1_500_000))
+	assert.Equal(t, "4.29 GB", FormatBytes(4_294_967_296), "over uint32")
+	assert.Equal(t, "12.35 TB", FormatBytes(12_345_678_901_234))
+	assert.Equal(t, "18446.74 PB", FormatBytes(18_446_744_073_709_551_615))
+}
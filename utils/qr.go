@@ -0,0 +1,10 @@
+package utils
+
+import "github.com/skip2/go-qrcode"
+
+const QRCodeSize = 384
+
+// QRCodePNG encodes the content as a QR code and returns it as a PNG image.
+func QRCodePNG(content string) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, QRCodeSize)
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQRCodePNG(t *testing.T) {
+	data, err := QRCodePNG("pc1zgp0x33hehvczq6dtfjyh9ca8nd0cyw8m8yppaa")
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, QRCodeSize, img.Bounds().Dx())
+	assert.Equal(t, QRCodeSize, img.Bounds().Dy())
+
+	_, err = QRCodePNG("")
+	assert.Error(t, err, "no content")
+}
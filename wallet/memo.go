@@ -0,0 +1,47 @@
+package wallet
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxMemoLength is the longest memo that the nodes accept in a transaction, in bytes.
+const MaxMemoLength = 64
+
+// MemoValues are the values of the placeholders in the memo templates.
+type MemoValues struct {
+	ClaimID uint
+	Address string
+	Amount  int64 // In PAC.
+	Date    time.Time
+}
+
+// RenderMemo replaces the placeholders of the template, like: "Pagu faucet {claim_id}",
+// so the transactions can be reconciled with the claims.
+// The placeholders are {claim_id}, {address}, {amount} and {date}, and the memo is cut to the max length.
+func RenderMemo(template string, values MemoValues) string {
+	if values.Date.IsZero() {
+		values.Date = time.Now()
+	}
+
+	memo := strings.NewReplacer(
+		"{claim_id}", strconv.FormatUint(uint64(values.ClaimID), 10),
+		"{address}", values.Address,
+		"{amount}", strconv.FormatInt(values.Amount, 10),
+		"{date}", values.Date.UTC().Format(time.DateOnly),
+	).Replace(template)
+
+	if len(memo) <= MaxMemoLength {
+		return memo
+	}
+
+	// the memo is not cut in the middle of a character.
+	memo = memo[:MaxMemoLength]
+	for !utf8.ValidString(memo) {
+		memo = memo[:len(memo)-1]
+	}
+
+	return memo
+}
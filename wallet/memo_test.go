@@ -0,0 +1,29 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMemo(t *testing.T) {
+	values := MemoValues{
+		ClaimID: 42,
+		Address: "tpc1zlymfcuxlgvvuud2q4zw0scllqn74d2f90hld6w",
+		Amount:  5,
+		Date:    time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, "Pagu faucet 42", RenderMemo("Pagu faucet {claim_id}", values))
+	assert.Equal(t, "5 PAC on 2024-05-01", RenderMemo("{amount} PAC on {date}", values))
+	assert.Equal(t, "no placeholder", RenderMemo("no placeholder", values))
+
+	t.Run("long memo is cut", func(t *testing.T) {
+		memo := RenderMemo("Pagu faucet {claim_id} to {address} ✓✓✓✓", values)
+		assert.LessOrEqual(t, len(memo), MaxMemoLength)
+		assert.True(t, strings.HasPrefix(memo, "Pagu faucet 42 to tpc1z"))
+		assert.NotContains(t, memo, "�")
+	})
+}